@@ -23,6 +23,15 @@ const (
 	PropertyKindMethod PropertyKind = "method"
 )
 
+// ImportPhase is the phase of an import declaration or of a dynamic import.
+type ImportPhase uint8
+
+const (
+	ImportPhaseEvaluation ImportPhase = iota // import x from "y"
+	ImportPhaseSource                        // import source x from "y"
+	ImportPhaseDefer                         // import defer * as ns from "y"
+)
+
 // All nodes implement the Node interface.
 type Node interface {
 	Idx0() file.Idx // The index of the first character belonging to the node
@@ -288,8 +297,10 @@ type (
 	SuperExpression struct {
 		Idx file.Idx
 	}
+	// DynamicImportExpression is the `import`, `import.source` or `import.defer` callee of a dynamic import.
 	DynamicImportExpression struct {
-		Idx file.Idx
+		Idx   file.Idx
+		Phase ImportPhase
 	}
 
 	UnaryExpression struct {
@@ -415,6 +426,7 @@ type (
 		Into   ForInto
 		Source Expression
 		Body   Statement
+		Await  bool
 	}
 
 	ForStatement struct {
@@ -471,6 +483,7 @@ type (
 	LexicalDeclaration struct {
 		Idx   file.Idx
 		Token token.Token
+		Await bool // set for 'await using' declarations
 		List  []*Binding
 	}
 
@@ -495,6 +508,8 @@ type (
 		ImportClause    *ImportClause
 		FromClause      *FromClause
 		ModuleSpecifier unistring.String
+		Attributes      []*ImportAttribute
+		Phase           ImportPhase
 	}
 
 	ImportClause struct {
@@ -527,11 +542,19 @@ type (
 		FromClause           *FromClause
 		HoistableDeclaration *HoistableDeclaration
 		IsDefault            bool
+		Attributes           []*ImportAttribute
 	}
 
 	FromClause struct {
 		ModuleSpecifier unistring.String
 	}
+
+	// ImportAttribute is a single key/value pair of an import's "with" clause.
+	ImportAttribute struct {
+		Idx   file.Idx
+		Key   unistring.String
+		Value unistring.String
+	}
 	ExportFromClause struct {
 		IsWildcard   bool
 		Alias        unistring.String
@@ -659,6 +682,8 @@ type (
 	ForDeclaration struct {
 		Idx     file.Idx
 		IsConst bool
+		IsUsing bool // set for 'using' and 'await using' declarations, IsConst is also set
+		IsAwait bool // set for 'await using' declarations
 		Target  BindingTarget
 	}
 
@@ -250,12 +250,15 @@ func (r *Runtime) arrayproto_toString(call FunctionCall) Value {
 	})
 }
 
-func (r *Runtime) writeItemLocaleString(item Value, buf *StringBuilder) {
+// writeItemLocaleString appends the result of item.toLocaleString(locales, options), args holds the locales
+// and the options.
+func (r *Runtime) writeItemLocaleString(item Value, args []Value, buf *StringBuilder) {
 	if item != nil && item != _undefined && item != _null {
 		if f, ok := r.getVStr(item, "toLocaleString").(*Object); ok {
 			if c, ok := f.self.assertCallable(); ok {
 				strVal := c(FunctionCall{
-					This: item,
+					This:      item,
+					Arguments: args,
 				})
 				buf.WriteString(strVal.toString())
 				return
@@ -274,13 +277,14 @@ func (r *Runtime) arrayproto_toLocaleString(call FunctionCall) Value {
 	}
 	defer r.popFromStringStack()
 
+	args := []Value{call.Argument(0), call.Argument(1)}
 	var buf StringBuilder
 	if a := r.checkStdArrayObj(array); a != nil {
 		for i, item := range a.values {
 			if i > 0 {
 				buf.WriteRune(',')
 			}
-			r.writeItemLocaleString(item, &buf)
+			r.writeItemLocaleString(item, args, &buf)
 		}
 	} else {
 		length := toLength(array.self.getStr("length", nil))
@@ -289,7 +293,7 @@ func (r *Runtime) arrayproto_toLocaleString(call FunctionCall) Value {
 				buf.WriteRune(',')
 			}
 			item := array.self.getIdx(valueInt(i), nil)
-			r.writeItemLocaleString(item, &buf)
+			r.writeItemLocaleString(item, args, &buf)
 		}
 	}
 
@@ -1555,6 +1559,184 @@ func (r *Runtime) array_from(call FunctionCall) Value {
 	return arr
 }
 
+// asyncIteratorCloseThrow implements AsyncIteratorClose for a throw completion: the 'return' method of the
+// iterator (if any) is called and awaited, then reject is called with the original reason.
+func (r *Runtime) asyncIteratorCloseThrow(iter *iteratorRecord, reason Value, reject func(Value)) {
+	var res Value
+	ex := r.vm.try(func() {
+		if method := toMethod(iter.iterator.self.getStr("return", nil)); method != nil {
+			res = method(FunctionCall{This: iter.iterator})
+		}
+	})
+	if ex != nil || res == nil {
+		reject(reason)
+		return
+	}
+	done := func(Value) {
+		reject(reason)
+	}
+	r.await(res, done, done)
+}
+
+func (r *Runtime) array_fromAsync(call FunctionCall) Value {
+	pcap := r.newPromiseCapability(r.getPromise())
+	items, mapFnArg, t := call.Argument(0), call.Argument(1), call.Argument(2)
+	var mapFn func(FunctionCall) Value
+	var arr *Object
+	var iter *iteratorRecord
+	var l int64
+	if !pcap.try(func() {
+		if mapFnArg != _undefined {
+			fn, ok := assertCallable(mapFnArg)
+			if !ok {
+				panic(r.NewTypeError("%s is not a function", mapFnArg))
+			}
+			mapFn = fn
+		}
+		var ctor func(args []Value, newTarget *Object) *Object
+		if call.This != r.global.Array {
+			if o, ok := call.This.(*Object); ok {
+				ctor = o.self.assertConstructor()
+			}
+		}
+		if usingAsyncIterator := toMethod(r.getV(items, SymAsyncIterator)); usingAsyncIterator != nil {
+			iter = r.getIterator(items, usingAsyncIterator)
+		} else if usingSyncIterator := toMethod(r.getV(items, SymIterator)); usingSyncIterator != nil {
+			iter = r.createAsyncFromSyncIterator(r.getIterator(items, usingSyncIterator))
+		}
+		if iter != nil {
+			if ctor != nil {
+				arr = ctor([]Value{}, nil)
+			} else {
+				arr = r.newArrayValues(nil)
+			}
+		} else {
+			items = items.ToObject(r)
+			l = toLength(items.(*Object).self.getStr("length", nil))
+			if ctor != nil {
+				arr = ctor([]Value{intToValue(l)}, nil)
+			} else {
+				arr = r.newArrayValues(nil)
+			}
+		}
+	}) {
+		return pcap.promise
+	}
+
+	k := int64(0)
+	if iter != nil {
+		closeIter := func(reason Value) {
+			r.asyncIteratorCloseThrow(iter, reason, pcap.reject)
+		}
+		define := func(val Value) bool {
+			if ex := r.vm.try(func() {
+				createDataPropertyOrThrow(arr, intToValue(k), val)
+			}); ex != nil {
+				closeIter(ex.val)
+				return false
+			}
+			k++
+			return true
+		}
+		var step func()
+		onValue := func(val Value) {
+			if define(val) {
+				step()
+			}
+		}
+		onResult := func(res Value) {
+			var done bool
+			var value Value
+			if !pcap.try(func() {
+				obj, ok := res.(*Object)
+				if !ok {
+					panic(r.NewTypeError("Iterator result %s is not an object", res))
+				}
+				if done = iteratorComplete(obj); done {
+					arr.self.setOwnStr("length", intToValue(k), true)
+				} else {
+					value = iteratorValue(obj)
+				}
+			}) {
+				return
+			}
+			if done {
+				pcap.resolve(arr)
+				return
+			}
+			if mapFn == nil {
+				onValue(value)
+				return
+			}
+			var mapped Value
+			if ex := r.vm.try(func() {
+				mapped = mapFn(FunctionCall{This: t, Arguments: []Value{value, intToValue(k)}})
+			}); ex != nil {
+				closeIter(ex.val)
+				return
+			}
+			r.await(mapped, onValue, closeIter)
+		}
+		step = func() {
+			var res Value
+			if !pcap.try(func() {
+				if iter.next == nil {
+					panic(r.NewTypeError("iterator.next is missing or not a function"))
+				}
+				res = iter.next(FunctionCall{This: iter.iterator})
+			}) {
+				return
+			}
+			r.await(res, onResult, pcap.reject)
+		}
+		step()
+	} else {
+		arrayLike := items.(*Object)
+		var step func()
+		onValue := func(val Value) {
+			if !pcap.try(func() {
+				createDataPropertyOrThrow(arr, intToValue(k), val)
+			}) {
+				return
+			}
+			k++
+			step()
+		}
+		onItem := func(item Value) {
+			if mapFn == nil {
+				onValue(item)
+				return
+			}
+			var mapped Value
+			if !pcap.try(func() {
+				mapped = mapFn(FunctionCall{This: t, Arguments: []Value{item, intToValue(k)}})
+			}) {
+				return
+			}
+			r.await(mapped, onValue, pcap.reject)
+		}
+		step = func() {
+			if k >= l {
+				if pcap.try(func() {
+					arr.self.setOwnStr("length", intToValue(l), true)
+				}) {
+					pcap.resolve(arr)
+				}
+				return
+			}
+			var item Value
+			if !pcap.try(func() {
+				item = nilSafe(arrayLike.self.getIdx(valueInt(k), nil))
+			}) {
+				return
+			}
+			r.await(item, onItem, pcap.reject)
+		}
+		step()
+	}
+	return pcap.promise
+}
+
 func (r *Runtime) array_isArray(call FunctionCall) Value {
 	if o, ok := call.Argument(0).(*Object); ok {
 		if isArray(o) {
@@ -1704,6 +1886,7 @@ func (r *Runtime) getArray() *Object {
 func (r *Runtime) createArray(val *Object) objectImpl {
 	o := r.newNativeFuncConstructObj(val, r.builtin_newArray, "Array", r.getArrayPrototype(), 1)
 	o._putProp("from", r.newNativeFunc(r.array_from, "from", 1), true, false, true)
+	o._putProp("fromAsync", r.newNativeFunc(r.array_fromAsync, "fromAsync", 1), true, false, true)
 	o._putProp("isArray", r.newNativeFunc(r.array_isArray, "isArray", 1), true, false, true)
 	o._putProp("of", r.newNativeFunc(r.array_of, "of", 0), true, false, true)
 	r.putSpeciesReturnThis(o)
@@ -365,3 +365,49 @@ func TestArrayToSpliced(t *testing.T) {
 	`
 	testScriptWithTestLib(SCRIPT, _undefined, t)
 }
+
+func TestArrayFromAsync(t *testing.T) {
+	const SCRIPT = `
+	assert(compareArray(await Array.fromAsync([1, Promise.resolve(2), 3]), [1, 2, 3]));
+	assert(compareArray(await Array.fromAsync({length: 2, 0: Promise.resolve("a"), 1: "b"}), ["a", "b"]));
+
+	async function* gen() {
+		yield 1;
+		yield 2;
+	}
+	assert(compareArray(await Array.fromAsync(gen(), async (v, i) => v * 10 + i), [10, 21]));
+
+	let closed = false;
+	const iterable = {
+		[Symbol.asyncIterator]() {
+			return {
+				next() { return Promise.resolve({value: 1, done: false}); },
+				return() { closed = true; return Promise.resolve({done: true}); }
+			};
+		}
+	};
+	try {
+		await Array.fromAsync(iterable, () => { throw new Error("boom"); });
+		throw new Error("should not reach");
+	} catch (e) {
+		assert.sameValue(e.message, "boom");
+	}
+	assert(closed, "iterator is closed");
+
+	const p = Array.fromAsync([1], 1);
+	assert(p instanceof Promise);
+	try {
+		await p;
+		throw new Error("should not reach");
+	} catch (e) {
+		assert(e instanceof TypeError, e);
+	}
+
+	function C() {}
+	const c = await Array.fromAsync.call(C, [1, 2]);
+	assert(c instanceof C);
+	assert.sameValue(c.length, 2);
+	assert.sameValue(Array.fromAsync.length, 1);
+	`
+	testAsyncFuncWithTestLib(SCRIPT, _undefined, t)
+}
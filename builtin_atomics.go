@@ -0,0 +1,589 @@
+package sobek
+
+import (
+	"math"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// atomicsWaiter is an entry in the waiter list of a shared data block, created by Atomics.wait() (in which case
+// wake is set) or by Atomics.waitAsync() (in which case owner, queue and promise are set).
+type atomicsWaiter struct {
+	// notified is guarded by sharedDataBlock.mu.
+	notified bool
+	wake     chan struct{}
+
+	owner   *Runtime
+	queue   *waitAsyncQueue
+	promise *Promise
+	timer   *time.Timer
+}
+
+// waitAsyncQueue holds the Atomics.waitAsync() waiters that have been notified by another Runtime or have timed
+// out, and whose promises are waiting to be resolved. It is populated from arbitrary goroutines, therefore it
+// must not access the Runtime.
+type waitAsyncQueue struct {
+	mu      sync.Mutex
+	waiters []*atomicsWaiter
+	notify  func()
+}
+
+func (q *waitAsyncQueue) push(w *atomicsWaiter) {
+	q.mu.Lock()
+	q.waiters = append(q.waiters, w)
+	notify := q.notify
+	q.mu.Unlock()
+	if notify != nil {
+		notify()
+	}
+}
+
+func (q *waitAsyncQueue) takeAll() []*atomicsWaiter {
+	q.mu.Lock()
+	waiters := q.waiters
+	q.waiters = nil
+	q.mu.Unlock()
+	return waiters
+}
+
+func (r *Runtime) getWaitAsyncQueue() *waitAsyncQueue {
+	q := r.waitAsyncQueue
+	if q == nil {
+		q = &waitAsyncQueue{}
+		r.waitAsyncQueue = q
+	}
+	return q
+}
+
+// SetAtomicsWaitAsyncNotifier sets a function that is called when a promise returned by Atomics.waitAsync()
+// is ready to be resolved because the waiter has been notified from another Runtime or has timed out. The function
+// is called from an arbitrary goroutine, so it must not use the Runtime. Instead, it should schedule a call to
+// ResolveAtomicsWaitAsync() on the goroutine that runs the Runtime (e.g. using the host event loop).
+// Waiters notified by Atomics.notify() called in the same Runtime are resolved immediately and do not
+// require this.
+// Passing nil removes the notifier.
+func (r *Runtime) SetAtomicsWaitAsyncNotifier(notify func()) {
+	q := r.getWaitAsyncQueue()
+	q.mu.Lock()
+	q.notify = notify
+	q.mu.Unlock()
+}
+
+// ResolveAtomicsWaitAsync resolves the promises returned by Atomics.waitAsync() whose waiters have been notified
+// from another Runtime or have timed out since the last call. Any promise jobs are run before this method returns.
+// This method must not be called concurrently with other Runtime methods and must not be called
+// from inside JavaScript code.
+func (r *Runtime) ResolveAtomicsWaitAsync() error {
+	q := r.waitAsyncQueue
+	if q == nil {
+		return nil
+	}
+	waiters := q.takeAll()
+	if len(waiters) == 0 {
+		return nil
+	}
+	return r.runWrapped(func() {
+		for _, w := range waiters {
+			w.resolve()
+		}
+	})
+}
+
+func (w *atomicsWaiter) resolve() {
+	if w.notified {
+		w.promise.fulfill(asciiString("ok"))
+	} else {
+		w.promise.fulfill(asciiString("timed-out"))
+	}
+}
+
+func (b *sharedDataBlock) ptr(idx int) unsafe.Pointer {
+	return unsafe.Add(unsafe.Pointer(unsafe.SliceData(b.data)), idx)
+}
+
+// word returns the aligned 32-bit word containing the size-byte value at byte index idx and the bit position
+// of the value within the word.
+func (b *sharedDataBlock) word(idx, size int) (*uint32, int) {
+	var shift int
+	if nativeEndian == littleEndian {
+		shift = (idx & 3) * 8
+	} else {
+		shift = (4 - idx&3 - size) * 8
+	}
+	return (*uint32)(b.ptr(idx &^ 3)), shift
+}
+
+// load atomically reads the size-byte value at byte index idx.
+func (b *sharedDataBlock) load(idx, size int) uint64 {
+	switch size {
+	case 8:
+		return atomic.LoadUint64((*uint64)(b.ptr(idx)))
+	case 4:
+		return uint64(atomic.LoadUint32((*uint32)(b.ptr(idx))))
+	}
+	w, shift := b.word(idx, size)
+	return uint64(atomic.LoadUint32(w)>>shift) & (uint64(1)<<(size*8) - 1)
+}
+
+// update atomically replaces the size-byte value at byte index idx with f(old) and returns old.
+func (b *sharedDataBlock) update(idx, size int, f func(uint64) uint64) uint64 {
+	switch size {
+	case 8:
+		p := (*uint64)(b.ptr(idx))
+		for {
+			old := atomic.LoadUint64(p)
+			if atomic.CompareAndSwapUint64(p, old, f(old)) {
+				return old
+			}
+		}
+	case 4:
+		p := (*uint32)(b.ptr(idx))
+		for {
+			old := atomic.LoadUint32(p)
+			if atomic.CompareAndSwapUint32(p, old, uint32(f(uint64(old)))) {
+				return uint64(old)
+			}
+		}
+	}
+	w, shift := b.word(idx, size)
+	mask := (uint32(1)<<(size*8) - 1) << shift
+	for {
+		oldW := atomic.LoadUint32(w)
+		old := uint64((oldW & mask) >> shift)
+		if atomic.CompareAndSwapUint32(w, oldW, oldW&^mask|uint32(f(old))<<shift&mask) {
+			return old
+		}
+	}
+}
+
+func (b *sharedDataBlock) addWaiter(idx int, w *atomicsWaiter) {
+	if b.waiters == nil {
+		b.waiters = make(map[int][]*atomicsWaiter)
+	}
+	b.waiters[idx] = append(b.waiters[idx], w)
+}
+
+func (b *sharedDataBlock) removeWaiter(idx int, w *atomicsWaiter) bool {
+	list := b.waiters[idx]
+	for i, item := range list {
+		if item == w {
+			if len(list) == 1 {
+				delete(b.waiters, idx)
+			} else {
+				b.waiters[idx] = append(list[:i:i], list[i+1:]...)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// notify wakes up to count waiters waiting at byte index idx in FIFO order and returns the number of woken
+// waiters. r is the Runtime calling Atomics.notify().
+func (b *sharedDataBlock) notify(idx, count int, r *Runtime) int {
+	b.mu.Lock()
+	list := b.waiters[idx]
+	n := min(count, len(list))
+	woken := list[:n]
+	if n == len(list) {
+		delete(b.waiters, idx)
+	} else {
+		b.waiters[idx] = list[n:]
+	}
+	for _, w := range woken {
+		w.notified = true
+	}
+	b.mu.Unlock()
+
+	for _, w := range woken {
+		if w.wake != nil {
+			select {
+			case w.wake <- struct{}{}:
+			default:
+			}
+			continue
+		}
+		if w.timer != nil {
+			w.timer.Stop()
+		}
+		if w.owner == r {
+			r.jobQueue = append(r.jobQueue, w.resolve)
+		} else {
+			w.queue.push(w)
+		}
+	}
+	return n
+}
+
+func (a *typedArrayObject) atomicLoad(idx int) uint64 {
+	if s := a.viewedArrayBuf.shared; s != nil {
+		return s.load((a.offset+idx)*a.elemSize, a.elemSize)
+	}
+	return a.typedArray.getRaw(a.offset + idx)
+}
+
+func (a *typedArrayObject) atomicUpdate(idx int, f func(uint64) uint64) uint64 {
+	if s := a.viewedArrayBuf.shared; s != nil {
+		return s.update((a.offset+idx)*a.elemSize, a.elemSize, f)
+	}
+	old := a.typedArray.getRaw(a.offset + idx)
+	a.typedArray.setRaw(a.offset+idx, f(old))
+	return old
+}
+
+func (a *typedArrayObject) isBigInt() bool {
+	switch a.typedArray.(type) {
+	case *bigInt64Array, *bigUint64Array:
+		return true
+	}
+	return false
+}
+
+// rawToValue converts a raw element value (which may or may not be sign-extended) to a Value.
+func (a *typedArrayObject) rawToValue(raw uint64) Value {
+	switch a.typedArray.(type) {
+	case *int8Array:
+		return intToValue(int64(int8(raw)))
+	case *uint8Array:
+		return intToValue(int64(uint8(raw)))
+	case *int16Array:
+		return intToValue(int64(int16(raw)))
+	case *uint16Array:
+		return intToValue(int64(uint16(raw)))
+	case *int32Array:
+		return intToValue(int64(int32(raw)))
+	case *uint32Array:
+		return intToValue(int64(uint32(raw)))
+	case *bigInt64Array:
+		return (*valueBigInt)(big.NewInt(int64(raw)))
+	case *bigUint64Array:
+		return (*valueBigInt)(new(big.Int).SetUint64(raw))
+	}
+	panic("unsupported typed array type")
+}
+
+// toIntegral converts v to a BigInt for BigInt arrays or using ToIntegerOrInfinity otherwise.
+func (a *typedArrayObject) toIntegral(v Value) Value {
+	if a.isBigInt() {
+		return toBigInt(v)
+	}
+	n := v.ToNumber()
+	if f, ok := n.(valueFloat); ok {
+		t := math.Trunc(float64(f))
+		if t == 0 || math.IsNaN(t) {
+			return intToValue(0)
+		}
+		return floatToValue(t)
+	}
+	return n
+}
+
+func (r *Runtime) validateIntegerTypedArray(v Value, waitable bool) (*typedArrayObject, int) {
+	if o, ok := v.(*Object); ok {
+		if ta, ok := o.self.(*typedArrayObject); ok {
+			length := ta.validate()
+			if waitable {
+				switch ta.typedArray.(type) {
+				case *int32Array, *bigInt64Array:
+					return ta, length
+				}
+			} else {
+				switch ta.typedArray.(type) {
+				case *uint8ClampedArray, *float16Array, *float32Array, *float64Array:
+				default:
+					return ta, length
+				}
+			}
+			panic(r.NewTypeError("Atomics operation is not allowed on %s", ta.defaultCtor.self.getStr("name", nil)))
+		}
+	}
+	panic(r.NewTypeError("Argument is not a typed array: %s", v))
+}
+
+// validateAtomicAccess returns the element index for the index argument. length is the length of the array
+// at the time of validation.
+func (r *Runtime) validateAtomicAccess(ta *typedArrayObject, length int, index Value) int {
+	idx := r.toIndex(index)
+	if idx >= length {
+		panic(r.newErrorf(r.getRangeError(), "Index %d is out of range", idx))
+	}
+	return idx
+}
+
+// revalidateAtomicAccess checks that the element at idx is still accessible after argument conversion.
+func (r *Runtime) revalidateAtomicAccess(ta *typedArrayObject, idx int) {
+	if idx >= ta.validate() {
+		panic(r.newErrorf(r.getRangeError(), "Index %d is out of range", idx))
+	}
+}
+
+func (r *Runtime) atomicReadModifyWrite(call FunctionCall, op func(old, v uint64) uint64) Value {
+	ta, length := r.validateIntegerTypedArray(call.Argument(0), false)
+	idx := r.validateAtomicAccess(ta, length, call.Argument(1))
+	v := ta.typedArray.toRaw(ta.toIntegral(call.Argument(2)))
+	r.revalidateAtomicAccess(ta, idx)
+	return ta.rawToValue(ta.atomicUpdate(idx, func(old uint64) uint64 {
+		return op(old, v)
+	}))
+}
+
+func (r *Runtime) atomics_add(call FunctionCall) Value {
+	return r.atomicReadModifyWrite(call, func(old, v uint64) uint64 {
+		return old + v
+	})
+}
+
+func (r *Runtime) atomics_and(call FunctionCall) Value {
+	return r.atomicReadModifyWrite(call, func(old, v uint64) uint64 {
+		return old & v
+	})
+}
+
+func (r *Runtime) atomics_compareExchange(call FunctionCall) Value {
+	ta, length := r.validateIntegerTypedArray(call.Argument(0), false)
+	idx := r.validateAtomicAccess(ta, length, call.Argument(1))
+	expected := ta.typedArray.toRaw(ta.toIntegral(call.Argument(2)))
+	replacement := ta.typedArray.toRaw(ta.toIntegral(call.Argument(3)))
+	r.revalidateAtomicAccess(ta, idx)
+	mask := uint64(1)<<(ta.elemSize*8) - 1
+	return ta.rawToValue(ta.atomicUpdate(idx, func(old uint64) uint64 {
+		if old&mask == expected&mask {
+			return replacement
+		}
+		return old
+	}))
+}
+
+func (r *Runtime) atomics_exchange(call FunctionCall) Value {
+	return r.atomicReadModifyWrite(call, func(_, v uint64) uint64 {
+		return v
+	})
+}
+
+func (r *Runtime) atomics_isLockFree(call FunctionCall) Value {
+	switch call.Argument(0).ToInteger() {
+	case 1, 2, 4, 8:
+		return valueTrue
+	}
+	return valueFalse
+}
+
+func (r *Runtime) atomics_load(call FunctionCall) Value {
+	ta, length := r.validateIntegerTypedArray(call.Argument(0), false)
+	idx := r.validateAtomicAccess(ta, length, call.Argument(1))
+	r.revalidateAtomicAccess(ta, idx)
+	return ta.rawToValue(ta.atomicLoad(idx))
+}
+
+func (r *Runtime) atomics_notify(call FunctionCall) Value {
+	ta, length := r.validateIntegerTypedArray(call.Argument(0), true)
+	idx := r.validateAtomicAccess(ta, length, call.Argument(1))
+	count := math.MaxInt
+	if c := call.Argument(2); c != _undefined {
+		count = int(max(min(c.ToInteger(), math.MaxInt), 0))
+	}
+	block := ta.viewedArrayBuf.shared
+	if block == nil {
+		return intToValue(0)
+	}
+	return intToValue(int64(block.notify((ta.offset+idx)*ta.elemSize, count, r)))
+}
+
+func (r *Runtime) atomics_or(call FunctionCall) Value {
+	return r.atomicReadModifyWrite(call, func(old, v uint64) uint64 {
+		return old | v
+	})
+}
+
+func (r *Runtime) atomics_pause(call FunctionCall) Value {
+	if n := call.Argument(0); n != _undefined {
+		switch n := n.(type) {
+		case valueInt:
+		case valueFloat:
+			if f := float64(n); math.IsInf(f, 0) || f != math.Trunc(f) {
+				panic(r.NewTypeError("Atomics.pause argument must be an integral number"))
+			}
+		default:
+			panic(r.NewTypeError("Atomics.pause argument must be an integral number"))
+		}
+	}
+	return _undefined
+}
+
+func (r *Runtime) atomics_store(call FunctionCall) Value {
+	ta, length := r.validateIntegerTypedArray(call.Argument(0), false)
+	idx := r.validateAtomicAccess(ta, length, call.Argument(1))
+	v := ta.toIntegral(call.Argument(2))
+	raw := ta.typedArray.toRaw(v)
+	r.revalidateAtomicAccess(ta, idx)
+	ta.atomicUpdate(idx, func(uint64) uint64 {
+		return raw
+	})
+	return v
+}
+
+func (r *Runtime) atomics_sub(call FunctionCall) Value {
+	return r.atomicReadModifyWrite(call, func(old, v uint64) uint64 {
+		return old - v
+	})
+}
+
+// doWait implements DoWait. For a synchronous wait it blocks the calling goroutine until the waiter is notified,
+// the timeout expires or the Runtime is interrupted.
+func (r *Runtime) doWait(call FunctionCall, async bool) Value {
+	ta, length := r.validateIntegerTypedArray(call.Argument(0), true)
+	block := ta.viewedArrayBuf.shared
+	if block == nil {
+		panic(r.NewTypeError("Atomics.wait cannot be called on a non-shared TypedArray"))
+	}
+	idx := r.validateAtomicAccess(ta, length, call.Argument(1))
+	var v uint64
+	if ta.isBigInt() {
+		v = ta.typedArray.toRaw(toBigInt(call.Argument(2)))
+	} else {
+		v = uint64(uint32(toInt32(call.Argument(2))))
+	}
+	timeout := time.Duration(-1)
+	if q := call.Argument(3).ToFloat(); !math.IsNaN(q) && q < float64(math.MaxInt64/time.Millisecond) {
+		timeout = time.Duration(max(q, 0) * float64(time.Millisecond))
+	}
+	byteIdx := (ta.offset + idx) * ta.elemSize
+
+	block.mu.Lock()
+	if block.load(byteIdx, ta.elemSize) != v {
+		block.mu.Unlock()
+		return r.waitResult(async, asciiString("not-equal"))
+	}
+	if timeout == 0 {
+		block.mu.Unlock()
+		return r.waitResult(async, asciiString("timed-out"))
+	}
+	w := &atomicsWaiter{}
+	if async {
+		w.owner = r
+		w.queue = r.getWaitAsyncQueue()
+		w.promise = r.newPromise(r.getPromisePrototype())
+		if timeout > 0 {
+			w.timer = time.AfterFunc(timeout, func() {
+				block.mu.Lock()
+				removed := block.removeWaiter(byteIdx, w)
+				block.mu.Unlock()
+				if removed {
+					w.queue.push(w)
+				}
+			})
+		}
+		block.addWaiter(byteIdx, w)
+		block.mu.Unlock()
+		return r.newWaitAsyncResult(true, w.promise.val)
+	}
+	w.wake = make(chan struct{}, 1)
+	block.addWaiter(byteIdx, w)
+	block.mu.Unlock()
+
+	if !r.vm.setInterruptWake(w.wake) {
+		var timeoutC <-chan time.Time
+		if timeout > 0 {
+			t := time.NewTimer(timeout)
+			defer t.Stop()
+			timeoutC = t.C
+		}
+		select {
+		case <-w.wake:
+		case <-timeoutC:
+		}
+	}
+	r.vm.setInterruptWake(nil)
+
+	block.mu.Lock()
+	notified := w.notified
+	if !notified {
+		block.removeWaiter(byteIdx, w)
+	}
+	block.mu.Unlock()
+	if notified {
+		return asciiString("ok")
+	}
+	if atomic.LoadUint32(&r.vm.interrupted) != 0 {
+		panic(r.vm.newInterruptedError())
+	}
+	return asciiString("timed-out")
+}
+
+// waitResult returns the result of a wait that has completed synchronously.
+func (r *Runtime) waitResult(async bool, value Value) Value {
+	if async {
+		return r.newWaitAsyncResult(false, value)
+	}
+	return value
+}
+
+func (r *Runtime) newWaitAsyncResult(isAsync bool, value Value) Value {
+	o := r.NewObject()
+	o.self.setOwnStr("async", r.toBoolean(isAsync), false)
+	o.self.setOwnStr("value", value, false)
+	return o
+}
+
+func (r *Runtime) atomics_wait(call FunctionCall) Value {
+	return r.doWait(call, false)
+}
+
+func (r *Runtime) atomics_waitAsync(call FunctionCall) Value {
+	return r.doWait(call, true)
+}
+
+func (r *Runtime) atomics_xor(call FunctionCall) Value {
+	return r.atomicReadModifyWrite(call, func(old, v uint64) uint64 {
+		return old ^ v
+	})
+}
+
+func createAtomicsTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.global.ObjectPrototype
+	}
+
+	t.putSym(SymToStringTag, func(r *Runtime) Value { return valueProp(asciiString("Atomics"), false, false, true) })
+
+	t.putStr("add", func(r *Runtime) Value { return r.methodProp(r.atomics_add, "add", 3) })
+	t.putStr("and", func(r *Runtime) Value { return r.methodProp(r.atomics_and, "and", 3) })
+	t.putStr("compareExchange", func(r *Runtime) Value { return r.methodProp(r.atomics_compareExchange, "compareExchange", 4) })
+	t.putStr("exchange", func(r *Runtime) Value { return r.methodProp(r.atomics_exchange, "exchange", 3) })
+	t.putStr("isLockFree", func(r *Runtime) Value { return r.methodProp(r.atomics_isLockFree, "isLockFree", 1) })
+	t.putStr("load", func(r *Runtime) Value { return r.methodProp(r.atomics_load, "load", 2) })
+	t.putStr("notify", func(r *Runtime) Value { return r.methodProp(r.atomics_notify, "notify", 3) })
+	t.putStr("or", func(r *Runtime) Value { return r.methodProp(r.atomics_or, "or", 3) })
+	t.putStr("pause", func(r *Runtime) Value { return r.methodProp(r.atomics_pause, "pause", 0) })
+	t.putStr("store", func(r *Runtime) Value { return r.methodProp(r.atomics_store, "store", 3) })
+	t.putStr("sub", func(r *Runtime) Value { return r.methodProp(r.atomics_sub, "sub", 3) })
+	t.putStr("wait", func(r *Runtime) Value { return r.methodProp(r.atomics_wait, "wait", 4) })
+	t.putStr("waitAsync", func(r *Runtime) Value { return r.methodProp(r.atomics_waitAsync, "waitAsync", 4) })
+	t.putStr("xor", func(r *Runtime) Value { return r.methodProp(r.atomics_xor, "xor", 3) })
+
+	return t
+}
+
+var atomicsTemplate *objectTemplate
+var atomicsTemplateOnce sync.Once
+
+func getAtomicsTemplate() *objectTemplate {
+	atomicsTemplateOnce.Do(func() {
+		atomicsTemplate = createAtomicsTemplate()
+	})
+	return atomicsTemplate
+}
+
+func (r *Runtime) getAtomics() *Object {
+	ret := r.global.Atomics
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.Atomics = ret
+		r.newTemplatedObject(getAtomicsTemplate(), ret)
+	}
+	return ret
+}
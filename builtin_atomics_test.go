@@ -0,0 +1,225 @@
+package sobek
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAtomics(t *testing.T) {
+	const SCRIPT = `
+	for (const buf of [new SharedArrayBuffer(16), new ArrayBuffer(16)]) {
+		const i8 = new Int8Array(buf);
+		assert.sameValue(Atomics.store(i8, 1, 127), 127);
+		assert.sameValue(Atomics.add(i8, 1, 1), 127);
+		assert.sameValue(Atomics.load(i8, 1), -128);
+		assert.sameValue(Atomics.sub(i8, 1, 1), -128);
+		assert.sameValue(Atomics.load(i8, 1), 127);
+		assert.sameValue(Atomics.load(i8, 0), 0);
+		assert.sameValue(Atomics.load(i8, 2), 0);
+
+		const u16 = new Uint16Array(buf, 4, 2);
+		assert.sameValue(Atomics.store(u16, 1, 0xff0f), 0xff0f);
+		assert.sameValue(Atomics.and(u16, 1, 0xf0ff), 0xff0f);
+		assert.sameValue(Atomics.or(u16, 1, 0x0100), 0xf00f);
+		assert.sameValue(Atomics.xor(u16, 1, 0xffff), 0xf10f);
+		assert.sameValue(Atomics.exchange(u16, 1, -1), 0x0ef0);
+		assert.sameValue(Atomics.compareExchange(u16, 1, 0xffff, 5), 0xffff);
+		assert.sameValue(Atomics.compareExchange(u16, 1, 4, 6), 5);
+		assert.sameValue(Atomics.load(u16, 1), 5);
+		assert.sameValue(Atomics.load(u16, 0), 0);
+
+		const i32 = new Int32Array(buf);
+		assert.sameValue(Atomics.compareExchange(i32, 3, 0, -1), 0);
+		assert.sameValue(Atomics.load(i32, 3), -1);
+		assert.sameValue(Atomics.store(i32, 3, 3.9), 3);
+		assert.sameValue(Atomics.store(i32, 3, -0), 0);
+		assert.sameValue(1 / Atomics.store(i32, 3, -0), Infinity);
+
+		const b64 = new BigInt64Array(buf);
+		assert.sameValue(Atomics.store(b64, 1, -1n), -1n);
+		assert.sameValue(Atomics.add(b64, 1, 2n), -1n);
+		assert.sameValue(Atomics.load(b64, 1), 1n);
+		assert.sameValue(Atomics.compareExchange(new BigUint64Array(buf), 1, 1n, 2n ** 64n - 1n), 1n);
+		assert.sameValue(Atomics.load(b64, 1), -1n);
+		assert.sameValue(Atomics.compareExchange(b64, 1, -1n, -2n), -1n, "negative expected value");
+		assert.sameValue(Atomics.load(b64, 1), -2n);
+		assert.sameValue(b64.indexOf(-2n), 1);
+		assert.sameValue(b64.includes(-2n), true);
+
+		assert.throws(RangeError, () => Atomics.load(i32, 4));
+		assert.throws(TypeError, () => Atomics.load(new Float64Array(buf), 0));
+		assert.throws(TypeError, () => Atomics.load(new Uint8ClampedArray(buf), 0));
+		assert.throws(TypeError, () => Atomics.notify(new Uint32Array(buf), 0));
+		assert.throws(TypeError, () => Atomics.load([1], 0));
+	}
+
+	assert.sameValue(Atomics.isLockFree(4), true);
+	assert.sameValue(Atomics.isLockFree(3), false);
+	assert.sameValue(Atomics.pause(), undefined);
+	assert.throws(TypeError, () => Atomics.pause(1.5));
+	assert.sameValue(Object.prototype.toString.call(Atomics), "[object Atomics]");
+
+	const ia = new Int32Array(new SharedArrayBuffer(8));
+	assert.sameValue(Atomics.wait(ia, 0, 1), "not-equal");
+	assert.sameValue(Atomics.wait(ia, 0, 0, 0), "timed-out");
+	assert.sameValue(Atomics.wait(ia, 0, 0, 10), "timed-out");
+	assert.sameValue(Atomics.notify(ia, 0), 0);
+	assert.sameValue(Atomics.notify(new Int32Array(4), 0), 0);
+	assert.throws(TypeError, () => Atomics.wait(new Int32Array(4), 0, 0, 0));
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestAtomicsWaitAsync(t *testing.T) {
+	const SCRIPT = `
+	const ia = new Int32Array(new SharedArrayBuffer(8));
+	let res = Atomics.waitAsync(ia, 0, 1);
+	assert.sameValue(res.async, false);
+	assert.sameValue(res.value, "not-equal");
+	res = Atomics.waitAsync(ia, 0, 0, 0);
+	assert.sameValue(res.async, false);
+	assert.sameValue(res.value, "timed-out");
+
+	res = Atomics.waitAsync(ia, 0, 0);
+	assert.sameValue(res.async, true);
+	assert(res.value instanceof Promise, "value is a promise");
+	let result;
+	res.value.then(v => { result = v; });
+	assert.sameValue(Atomics.notify(ia, 0, 1), 1);
+	assert.sameValue(Atomics.notify(ia, 0, 1), 0);
+	`
+	vm := New()
+	if _, err := vm.RunProgram(testLib()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vm.RunString(SCRIPT); err != nil {
+		t.Fatal(err)
+	}
+	// the promise is resolved by a job in the same Runtime
+	if res := vm.Get("result").String(); res != "ok" {
+		t.Fatal(res)
+	}
+}
+
+func TestAtomicsWaitNotifyAcrossRuntimes(t *testing.T) {
+	sab := NewSharedArrayBuffer(8)
+	const waiters = 4
+	var wg sync.WaitGroup
+	results := make([]string, waiters)
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := New()
+			r.Set("sab", sab)
+			v, err := r.RunString(`
+			const ia = new Int32Array(sab);
+			Atomics.add(ia, 1, 1);
+			Atomics.wait(ia, 0, 0, 10000);
+			`)
+			if err != nil {
+				results[i] = err.Error()
+				return
+			}
+			results[i] = v.String()
+		}()
+	}
+
+	r := New()
+	r.Set("sab", sab)
+	_, err := r.RunString(`
+	var ia = new Int32Array(sab);
+	while (Atomics.load(ia, 1) !== 4) {
+		Atomics.pause();
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// All the waiters have incremented the counter, but they may not have started waiting yet.
+	woken := int64(0)
+	deadline := time.Now().Add(10 * time.Second)
+	for woken < waiters && time.Now().Before(deadline) {
+		v, err := r.RunString(`Atomics.notify(ia, 0)`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		woken += v.ToInteger()
+	}
+	wg.Wait()
+	for i, res := range results {
+		if res != "ok" {
+			t.Fatalf("%d: %s", i, res)
+		}
+	}
+}
+
+func TestAtomicsWaitInterrupt(t *testing.T) {
+	r := New()
+	r.Set("sab", NewSharedArrayBuffer(4))
+	time.AfterFunc(50*time.Millisecond, func() {
+		r.Interrupt("halt")
+	})
+	_, err := r.RunString(`Atomics.wait(new Int32Array(sab), 0, 0)`)
+	var ie *InterruptedError
+	if !errors.As(err, &ie) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ie.Value() != "halt" {
+		t.Fatal(ie.Value())
+	}
+}
+
+func TestAtomicsWaitAsyncAcrossRuntimes(t *testing.T) {
+	sab := NewSharedArrayBuffer(4)
+	r := New()
+	ready := make(chan struct{}, 1)
+	r.SetAtomicsWaitAsyncNotifier(func() {
+		ready <- struct{}{}
+	})
+	r.Set("sab", sab)
+	_, err := r.RunString(`
+	var results = [];
+	var ia = new Int32Array(sab);
+	Atomics.waitAsync(ia, 0, 0).value.then(v => { results.push("first: " + v); });
+	Atomics.waitAsync(ia, 0, 0, 10).value.then(v => { results.push("second: " + v); });
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout is not notified")
+	}
+	if err := r.ResolveAtomicsWaitAsync(); err != nil {
+		t.Fatal(err)
+	}
+	if res := r.Get("results").String(); res != "second: timed-out" {
+		t.Fatal(res)
+	}
+
+	r2 := New()
+	r2.Set("sab", sab)
+	v, err := r2.RunString(`Atomics.notify(new Int32Array(sab), 0)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := v.ToInteger(); n != 1 {
+		t.Fatal(n)
+	}
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("notification is not delivered")
+	}
+	if err := r.ResolveAtomicsWaitAsync(); err != nil {
+		t.Fatal(err)
+	}
+	if res := r.Get("results").String(); res != "second: timed-out,first: ok" {
+		t.Fatal(res)
+	}
+}
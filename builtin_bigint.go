@@ -218,6 +218,11 @@ func (r *Runtime) bigintproto_valueOf(call FunctionCall) Value {
 	return r.thisBigIntValue(call.This)
 }
 
+func (r *Runtime) bigintproto_toLocaleString(call FunctionCall) Value {
+	x := newIntlDecimalFromBigInt((*big.Int)(r.thisBigIntValue(call.This).(*valueBigInt)))
+	return r.numberToLocaleString(x, call.Argument(0), call.Argument(1))
+}
+
 func (r *Runtime) bigintproto_toString(call FunctionCall) Value {
 	x := (*big.Int)(r.thisBigIntValue(call.This).(*valueBigInt))
 	radix := call.Argument(0)
@@ -341,7 +346,7 @@ func createBigIntProtoTemplate() *objectTemplate {
 	t.putStr("name", func(r *Runtime) Value { return valueProp(asciiString("BigInt"), false, false, true) })
 	t.putStr("constructor", func(r *Runtime) Value { return valueProp(r.getBigInt(), true, false, true) })
 
-	t.putStr("toLocaleString", func(r *Runtime) Value { return r.methodProp(r.bigintproto_toString, "toLocaleString", 0) })
+	t.putStr("toLocaleString", func(r *Runtime) Value { return r.methodProp(r.bigintproto_toLocaleString, "toLocaleString", 0) })
 	t.putStr("toString", func(r *Runtime) Value { return r.methodProp(r.bigintproto_toString, "toString", 0) })
 	t.putStr("valueOf", func(r *Runtime) Value { return r.methodProp(r.bigintproto_valueOf, "valueOf", 0) })
 	t.putSym(SymToStringTag, func(r *Runtime) Value { return valueProp(asciiString("BigInt"), false, false, true) })
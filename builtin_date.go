@@ -3,6 +3,7 @@ package sobek
 import (
 	"fmt"
 	"math"
+	"math/big"
 	"sync"
 	"time"
 )
@@ -10,7 +11,7 @@ import (
 func (r *Runtime) makeDate(args []Value, utc bool) (t time.Time, valid bool) {
 	switch {
 	case len(args) >= 2:
-		t = time.Date(1970, time.January, 1, 0, 0, 0, 0, time.Local)
+		t = time.Date(1970, time.January, 1, 0, 0, 0, 0, r.location())
 		t, valid = _dateSetYear(t, FunctionCall{Arguments: args}, 0, utc)
 	case len(args) == 0:
 		t = r.now()
@@ -25,7 +26,7 @@ func (r *Runtime) makeDate(args []Value, utc bool) (t time.Time, valid bool) {
 		if !valid {
 			pv := toPrimitive(args[0])
 			if val, ok := pv.(String); ok {
-				return dateParse(val.String())
+				return dateParse(val.String(), r.location())
 			}
 			pv = pv.ToNumber()
 			var n int64
@@ -69,11 +70,11 @@ func (r *Runtime) builtin_newDate(args []Value, proto *Object) *Object {
 }
 
 func (r *Runtime) builtin_date(FunctionCall) Value {
-	return asciiString(dateFormat(r.now()))
+	return asciiString(dateFormat(r.now(), r.location()))
 }
 
 func (r *Runtime) date_parse(call FunctionCall) Value {
-	t, set := dateParse(call.Argument(0).toString().String())
+	t, set := dateParse(call.Argument(0).toString().String(), r.location())
 	if set {
 		return intToValue(timeToMsec(t))
 	}
@@ -161,6 +162,18 @@ func (r *Runtime) dateproto_toJSON(call FunctionCall) Value {
 	panic(r.NewTypeError("toISOString is not a function"))
 }
 
+func (r *Runtime) dateproto_toTemporalInstant(call FunctionCall) Value {
+	obj := r.toObject(call.This)
+	if d, ok := obj.self.(*dateObject); ok {
+		if d.isSet() {
+			ns := new(big.Int).Mul(big.NewInt(d.msec), big.NewInt(nsPerMillisecond))
+			return r.newTemporalInstant(ns, nil)
+		}
+		panic(r.newError(r.getRangeError(), "Invalid time value"))
+	}
+	panic(r.NewTypeError("Method Date.prototype.toTemporalInstant is called on incompatible receiver"))
+}
+
 func (r *Runtime) dateproto_toPrimitive(call FunctionCall) Value {
 	o := r.toObject(call.This)
 	arg := call.Argument(0)
@@ -202,7 +215,7 @@ func (r *Runtime) dateproto_toLocaleString(call FunctionCall) Value {
 	obj := r.toObject(call.This)
 	if d, ok := obj.self.(*dateObject); ok {
 		if d.isSet() {
-			return asciiString(d.time().Format(datetimeLayout_en_GB))
+			return r.dateToLocaleString(d.time(), 0, call.Argument(0), call.Argument(1))
 		} else {
 			return stringInvalidDate
 		}
@@ -214,7 +227,7 @@ func (r *Runtime) dateproto_toLocaleDateString(call FunctionCall) Value {
 	obj := r.toObject(call.This)
 	if d, ok := obj.self.(*dateObject); ok {
 		if d.isSet() {
-			return asciiString(d.time().Format(dateLayout_en_GB))
+			return r.dateToLocaleString(d.time(), 1, call.Argument(0), call.Argument(1))
 		} else {
 			return stringInvalidDate
 		}
@@ -226,7 +239,7 @@ func (r *Runtime) dateproto_toLocaleTimeString(call FunctionCall) Value {
 	obj := r.toObject(call.This)
 	if d, ok := obj.self.(*dateObject); ok {
 		if d.isSet() {
-			return asciiString(d.time().Format(timeLayout_en_GB))
+			return r.dateToLocaleString(d.time(), 2, call.Argument(0), call.Argument(1))
 		} else {
 			return stringInvalidDate
 		}
@@ -669,15 +682,12 @@ func _dateSetMilliseconds(year, mon, day, hours, min, sec int64, t time.Time, ca
 	if utc {
 		loc = time.UTC
 	} else {
-		loc = time.Local
+		loc = t.Location()
 	}
 	r, ok := mkTime(year, mon, day, hours, min, sec, msec*1e6, loc)
 	if !ok {
 		return time.Time{}, false
 	}
-	if utc {
-		return r.In(time.Local), true
-	}
 	return r, true
 }
 
@@ -908,7 +918,7 @@ func (r *Runtime) dateproto_setFullYear(call FunctionCall) Value {
 		if d.isSet() {
 			t = d.time()
 		} else {
-			t = time.Date(1970, time.January, 1, 0, 0, 0, 0, time.Local)
+			t = time.Date(1970, time.January, 1, 0, 0, 0, 0, r.location())
 		}
 		t, ok := _dateSetFullYear(t, limitCallArgs(call, 3), 0, false)
 		if !ok {
@@ -1029,6 +1039,7 @@ func createDateProtoTemplate() *objectTemplate {
 	t.putStr("toUTCString", func(r *Runtime) Value { return r.methodProp(r.dateproto_toUTCString, "toUTCString", 0) })
 	t.putStr("toISOString", func(r *Runtime) Value { return r.methodProp(r.dateproto_toISOString, "toISOString", 0) })
 	t.putStr("toJSON", func(r *Runtime) Value { return r.methodProp(r.dateproto_toJSON, "toJSON", 1) })
+	t.putStr("toTemporalInstant", func(r *Runtime) Value { return r.methodProp(r.dateproto_toTemporalInstant, "toTemporalInstant", 0) })
 
 	t.putSym(SymToPrimitive, func(r *Runtime) Value {
 		return valueProp(r.newNativeFunc(r.dateproto_toPrimitive, "[Symbol.toPrimitive]", 1), false, false, true)
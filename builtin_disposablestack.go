@@ -0,0 +1,438 @@
+package sobek
+
+type disposableResource struct {
+	value  Value
+	method func(FunctionCall) Value
+	async  bool
+}
+
+// disposeCapability holds the resources added by 'using' declarations (or by DisposableStack methods) which
+// are disposed in reverse order.
+type disposeCapability struct {
+	resources []disposableResource
+}
+
+// needsAwait returns true if the capability contains resources added with the async-dispose hint.
+func (dc *disposeCapability) needsAwait() bool {
+	for _, res := range dc.resources {
+		if res.async {
+			return true
+		}
+	}
+	return false
+}
+
+// getDisposeMethod implements GetDisposeMethod.
+func (r *Runtime) getDisposeMethod(v Value, async bool) func(FunctionCall) Value {
+	if !async {
+		return toMethod(r.getV(v, SymDispose))
+	}
+	if method := toMethod(r.getV(v, SymAsyncDispose)); method != nil {
+		return method
+	}
+	method := toMethod(r.getV(v, SymDispose))
+	if method == nil {
+		return nil
+	}
+	return func(call FunctionCall) Value {
+		pcap := r.newPromiseCapability(r.getPromise())
+		pcap.try(func() {
+			method(FunctionCall{This: call.This})
+			pcap.resolve(_undefined)
+		})
+		return pcap.promise
+	}
+}
+
+// addDisposableResource implements AddDisposableResource. If method is nil, it is obtained from v.
+func (r *Runtime) addDisposableResource(dc *disposeCapability, v Value, async bool, method func(FunctionCall) Value) {
+	if method == nil {
+		if v == _null || v == _undefined {
+			if async {
+				dc.resources = append(dc.resources, disposableResource{value: _undefined, async: true})
+			}
+			return
+		}
+		if _, ok := v.(*Object); !ok {
+			panic(r.NewTypeError("%s is not an object", v.String()))
+		}
+		method = r.getDisposeMethod(v, async)
+		if method == nil {
+			if async {
+				panic(r.NewTypeError("Object is not async disposable"))
+			}
+			panic(r.NewTypeError("Object is not disposable"))
+		}
+	} else {
+		v = _undefined
+	}
+	dc.resources = append(dc.resources, disposableResource{value: v, method: method, async: async})
+}
+
+func (r *Runtime) suppressError(ex, suppressed *Exception) *Exception {
+	if suppressed == nil {
+		return ex
+	}
+	return &Exception{
+		val:   r.newSuppressedError(ex.val, suppressed.val),
+		stack: ex.stack,
+	}
+}
+
+// disposeResources implements DisposeResources for a capability that does not contain async-dispose resources.
+// ex is the pending exception (if any), the returned value is the resulting one.
+func (r *Runtime) disposeResources(dc *disposeCapability, ex *Exception) *Exception {
+	resources := dc.resources
+	dc.resources = nil
+	for i := len(resources) - 1; i >= 0; i-- {
+		res := resources[i]
+		if res.method == nil {
+			continue
+		}
+		if ex1 := r.vm.try(func() {
+			res.method(FunctionCall{This: res.value})
+		}); ex1 != nil {
+			ex = r.suppressError(ex1, ex)
+		}
+	}
+	return ex
+}
+
+// disposeResourcesAsync implements DisposeResources for a capability that may contain async-dispose resources.
+// It returns a promise which is resolved when all resources have been disposed. If any of the disposals
+// has thrown, the promise is rejected with the resulting error (which includes ex if it's not nil),
+// otherwise it is fulfilled with undefined.
+func (r *Runtime) disposeResourcesAsync(dc *disposeCapability, ex *Exception) *Object {
+	resources := dc.resources
+	dc.resources = nil
+	pcap := r.newPromiseCapability(r.getPromise())
+	var err *Exception
+	setError := func(e *Exception) {
+		if err == nil {
+			err = r.suppressError(e, ex)
+		} else {
+			err = r.suppressError(e, err)
+		}
+	}
+	needsAwait, hasAwaited := false, false
+	i := len(resources)
+	var step func()
+	cont := func(Value) {
+		step()
+	}
+	step = func() {
+		for i > 0 {
+			res := resources[i-1]
+			if !res.async && needsAwait && !hasAwaited {
+				needsAwait = false
+				r.await(_undefined, cont, cont)
+				return
+			}
+			i--
+			if res.method == nil {
+				needsAwait = true
+				continue
+			}
+			var result Value
+			if ex1 := r.vm.try(func() {
+				result = res.method(FunctionCall{This: res.value})
+			}); ex1 != nil {
+				setError(ex1)
+				continue
+			}
+			if res.async {
+				hasAwaited = true
+				r.await(result, cont, func(reason Value) {
+					setError(&Exception{val: reason})
+					step()
+				})
+				return
+			}
+		}
+		if needsAwait && !hasAwaited {
+			hasAwaited = true
+			r.await(_undefined, cont, cont)
+			return
+		}
+		if err != nil {
+			pcap.reject(err.val)
+		} else {
+			pcap.resolve(_undefined)
+		}
+	}
+	step()
+	return pcap.promise
+}
+
+type disposableStackObject struct {
+	baseObject
+	capability disposeCapability
+	disposed   bool
+	async      bool
+}
+
+func (r *Runtime) newDisposableStack(proto *Object, async bool) *disposableStackObject {
+	o := &Object{runtime: r}
+
+	ds := &disposableStackObject{async: async}
+	ds.class = classObject
+	ds.val = o
+	ds.extensible = true
+	o.self = ds
+	ds.prototype = proto
+	ds.init()
+	return ds
+}
+
+func (ds *disposableStackObject) name() string {
+	if ds.async {
+		return classAsyncDisposableStack
+	}
+	return classDisposableStack
+}
+
+func (r *Runtime) toDisposableStackObject(v Value, async bool, method string) *disposableStackObject {
+	thisObj := r.toObject(v)
+	ds, ok := thisObj.self.(*disposableStackObject)
+	if !ok || ds.async != async {
+		name := classDisposableStack
+		if async {
+			name = classAsyncDisposableStack
+		}
+		panic(r.NewTypeError("Method %s.prototype.%s called on incompatible receiver %s", name, method, r.objectproto_toString(FunctionCall{This: thisObj})))
+	}
+	return ds
+}
+
+func (ds *disposableStackObject) checkNotDisposed() {
+	if ds.disposed {
+		r := ds.val.runtime
+		panic(r.newError(r.getReferenceError(), ds.name()+" already disposed"))
+	}
+}
+
+func (ds *disposableStackObject) use(call FunctionCall) Value {
+	ds.checkNotDisposed()
+	v := call.Argument(0)
+	ds.val.runtime.addDisposableResource(&ds.capability, v, ds.async, nil)
+	return v
+}
+
+func (ds *disposableStackObject) adopt(call FunctionCall) Value {
+	ds.checkNotDisposed()
+	r := ds.val.runtime
+	v := call.Argument(0)
+	onDispose, ok := assertCallable(call.Argument(1))
+	if !ok {
+		panic(r.NewTypeError("%s is not a function", call.Argument(1).String()))
+	}
+	r.addDisposableResource(&ds.capability, _undefined, ds.async, func(FunctionCall) Value {
+		return onDispose(FunctionCall{This: _undefined, Arguments: []Value{v}})
+	})
+	return v
+}
+
+func (ds *disposableStackObject) deferFunc(call FunctionCall) Value {
+	ds.checkNotDisposed()
+	r := ds.val.runtime
+	onDispose, ok := assertCallable(call.Argument(0))
+	if !ok {
+		panic(r.NewTypeError("%s is not a function", call.Argument(0).String()))
+	}
+	r.addDisposableResource(&ds.capability, _undefined, ds.async, onDispose)
+	return _undefined
+}
+
+func (ds *disposableStackObject) move() Value {
+	ds.checkNotDisposed()
+	r := ds.val.runtime
+	var proto *Object
+	if ds.async {
+		proto = r.getAsyncDisposableStackPrototype()
+	} else {
+		proto = r.getDisposableStackPrototype()
+	}
+	newStack := r.newDisposableStack(proto, ds.async)
+	newStack.capability = ds.capability
+	ds.capability = disposeCapability{}
+	ds.disposed = true
+	return newStack.val
+}
+
+func (r *Runtime) disposableStackProto_adopt(call FunctionCall) Value {
+	return r.toDisposableStackObject(call.This, false, "adopt").adopt(call)
+}
+
+func (r *Runtime) disposableStackProto_defer(call FunctionCall) Value {
+	return r.toDisposableStackObject(call.This, false, "defer").deferFunc(call)
+}
+
+func (r *Runtime) disposableStackProto_dispose(call FunctionCall) Value {
+	ds := r.toDisposableStackObject(call.This, false, "dispose")
+	if ds.disposed {
+		return _undefined
+	}
+	ds.disposed = true
+	if ex := r.disposeResources(&ds.capability, nil); ex != nil {
+		panic(ex)
+	}
+	return _undefined
+}
+
+func (r *Runtime) disposableStackProto_getDisposed(call FunctionCall) Value {
+	return r.toBoolean(r.toDisposableStackObject(call.This, false, "disposed").disposed)
+}
+
+func (r *Runtime) disposableStackProto_move(call FunctionCall) Value {
+	return r.toDisposableStackObject(call.This, false, "move").move()
+}
+
+func (r *Runtime) disposableStackProto_use(call FunctionCall) Value {
+	return r.toDisposableStackObject(call.This, false, "use").use(call)
+}
+
+func (r *Runtime) asyncDisposableStackProto_adopt(call FunctionCall) Value {
+	return r.toDisposableStackObject(call.This, true, "adopt").adopt(call)
+}
+
+func (r *Runtime) asyncDisposableStackProto_defer(call FunctionCall) Value {
+	return r.toDisposableStackObject(call.This, true, "defer").deferFunc(call)
+}
+
+func (r *Runtime) asyncDisposableStackProto_disposeAsync(call FunctionCall) Value {
+	pcap := r.newPromiseCapability(r.getPromise())
+	pcap.try(func() {
+		ds := r.toDisposableStackObject(call.This, true, "disposeAsync")
+		if ds.disposed {
+			pcap.resolve(_undefined)
+			return
+		}
+		ds.disposed = true
+		pcap.resolve(r.disposeResourcesAsync(&ds.capability, nil))
+	})
+	return pcap.promise
+}
+
+func (r *Runtime) asyncDisposableStackProto_getDisposed(call FunctionCall) Value {
+	return r.toBoolean(r.toDisposableStackObject(call.This, true, "disposed").disposed)
+}
+
+func (r *Runtime) asyncDisposableStackProto_move(call FunctionCall) Value {
+	return r.toDisposableStackObject(call.This, true, "move").move()
+}
+
+func (r *Runtime) asyncDisposableStackProto_use(call FunctionCall) Value {
+	return r.toDisposableStackObject(call.This, true, "use").use(call)
+}
+
+func (r *Runtime) builtin_newDisposableStack(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		panic(r.needNew("DisposableStack"))
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.global.DisposableStack, r.getDisposableStackPrototype())
+	return r.newDisposableStack(proto, false).val
+}
+
+func (r *Runtime) builtin_newAsyncDisposableStack(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		panic(r.needNew("AsyncDisposableStack"))
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.global.AsyncDisposableStack, r.getAsyncDisposableStackPrototype())
+	return r.newDisposableStack(proto, true).val
+}
+
+func (r *Runtime) createDisposableStackProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
+
+	o._putProp("constructor", r.getDisposableStack(), true, false, true)
+	o._putProp("adopt", r.newNativeFunc(r.disposableStackProto_adopt, "adopt", 2), true, false, true)
+	o._putProp("defer", r.newNativeFunc(r.disposableStackProto_defer, "defer", 1), true, false, true)
+	disposeFunc := r.newNativeFunc(r.disposableStackProto_dispose, "dispose", 0)
+	o._putProp("dispose", disposeFunc, true, false, true)
+	o.setOwnStr("disposed", &valueProperty{
+		getterFunc:   r.newNativeFunc(r.disposableStackProto_getDisposed, "get disposed", 0),
+		accessor:     true,
+		configurable: true,
+	}, true)
+	o._putProp("move", r.newNativeFunc(r.disposableStackProto_move, "move", 0), true, false, true)
+	o._putProp("use", r.newNativeFunc(r.disposableStackProto_use, "use", 1), true, false, true)
+
+	o._putSym(SymDispose, valueProp(disposeFunc, true, false, true))
+	o._putSym(SymToStringTag, valueProp(asciiString(classDisposableStack), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) createDisposableStack(val *Object) objectImpl {
+	o := r.newNativeConstructOnly(val, r.builtin_newDisposableStack, r.getDisposableStackPrototype(), "DisposableStack", 0)
+
+	return o
+}
+
+func (r *Runtime) createAsyncDisposableStackProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
+
+	o._putProp("constructor", r.getAsyncDisposableStack(), true, false, true)
+	o._putProp("adopt", r.newNativeFunc(r.asyncDisposableStackProto_adopt, "adopt", 2), true, false, true)
+	o._putProp("defer", r.newNativeFunc(r.asyncDisposableStackProto_defer, "defer", 1), true, false, true)
+	disposeAsyncFunc := r.newNativeFunc(r.asyncDisposableStackProto_disposeAsync, "disposeAsync", 0)
+	o._putProp("disposeAsync", disposeAsyncFunc, true, false, true)
+	o.setOwnStr("disposed", &valueProperty{
+		getterFunc:   r.newNativeFunc(r.asyncDisposableStackProto_getDisposed, "get disposed", 0),
+		accessor:     true,
+		configurable: true,
+	}, true)
+	o._putProp("move", r.newNativeFunc(r.asyncDisposableStackProto_move, "move", 0), true, false, true)
+	o._putProp("use", r.newNativeFunc(r.asyncDisposableStackProto_use, "use", 1), true, false, true)
+
+	o._putSym(SymAsyncDispose, valueProp(disposeAsyncFunc, true, false, true))
+	o._putSym(SymToStringTag, valueProp(asciiString(classAsyncDisposableStack), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) createAsyncDisposableStack(val *Object) objectImpl {
+	o := r.newNativeConstructOnly(val, r.builtin_newAsyncDisposableStack, r.getAsyncDisposableStackPrototype(), "AsyncDisposableStack", 0)
+
+	return o
+}
+
+func (r *Runtime) getDisposableStackPrototype() *Object {
+	ret := r.global.DisposableStackPrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.DisposableStackPrototype = ret
+		ret.self = r.createDisposableStackProto(ret)
+	}
+	return ret
+}
+
+func (r *Runtime) getDisposableStack() *Object {
+	ret := r.global.DisposableStack
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.DisposableStack = ret
+		ret.self = r.createDisposableStack(ret)
+	}
+	return ret
+}
+
+func (r *Runtime) getAsyncDisposableStackPrototype() *Object {
+	ret := r.global.AsyncDisposableStackPrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.AsyncDisposableStackPrototype = ret
+		ret.self = r.createAsyncDisposableStackProto(ret)
+	}
+	return ret
+}
+
+func (r *Runtime) getAsyncDisposableStack() *Object {
+	ret := r.global.AsyncDisposableStack
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.AsyncDisposableStack = ret
+		ret.self = r.createAsyncDisposableStack(ret)
+	}
+	return ret
+}
@@ -0,0 +1,144 @@
+package sobek
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDisposableStack(t *testing.T) {
+	const SCRIPT = `
+	const trace = [];
+	const stack = new DisposableStack();
+	const res = stack.use({[Symbol.dispose]() { trace.push("use"); }});
+	assert.sameValue(typeof res, "object");
+	assert.sameValue(stack.adopt(42, v => trace.push("adopt " + v)), 42);
+	stack.defer(() => trace.push("defer"));
+	stack.use(null);
+	const moved = stack.move();
+	assert(stack.disposed, "stack.disposed");
+	assert(!moved.disposed, "moved.disposed");
+	assert.throws(ReferenceError, () => stack.use({}));
+	moved[Symbol.dispose]();
+	assert(compareArray(trace, ["defer", "adopt 42", "use"]), trace);
+	moved.dispose();
+	assert.sameValue(trace.length, 3);
+
+	const s2 = new DisposableStack();
+	s2.defer(() => { throw new Error("first"); });
+	s2.defer(() => { throw new Error("second"); });
+	try {
+		s2.dispose();
+		throw new Error("should not reach");
+	} catch (e) {
+		assert(e instanceof SuppressedError, "SuppressedError");
+		assert.sameValue(e.error.message, "first");
+		assert.sameValue(e.suppressed.message, "second");
+	}
+
+	assert.throws(TypeError, () => new DisposableStack().use(1));
+	assert.throws(TypeError, () => new DisposableStack().defer(1));
+	assert.throws(TypeError, () => DisposableStack());
+	assert.sameValue(Object.prototype.toString.call(stack), "[object DisposableStack]");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestAsyncDisposableStack(t *testing.T) {
+	const SCRIPT = `
+	const trace = [];
+	const stack = new AsyncDisposableStack();
+	stack.use({[Symbol.dispose]() { trace.push("sync"); }});
+	stack.use({async [Symbol.asyncDispose]() { await null; trace.push("async"); }});
+	stack.defer(() => trace.push("defer"));
+	const p = stack.disposeAsync();
+	assert.sameValue(trace.length, 1);
+	await p;
+	assert(compareArray(trace, ["defer", "async", "sync"]), trace);
+	assert(stack.disposed, "disposed");
+	assert.sameValue(await stack.disposeAsync(), undefined);
+
+	try {
+		await AsyncDisposableStack.prototype.disposeAsync.call({});
+		throw new Error("should not reach");
+	} catch (e) {
+		assert(e instanceof TypeError, e);
+	}
+	`
+	testAsyncFuncWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestIteratorDispose(t *testing.T) {
+	const SCRIPT = `
+	const trace = [];
+	function* g() {
+		try {
+			yield 1;
+			yield 2;
+		} finally {
+			trace.push("finally");
+		}
+	}
+	{
+		using it = g();
+		trace.push(it.next().value);
+	}
+	async function* ag() {
+		try {
+			yield 1;
+		} finally {
+			trace.push("async finally");
+		}
+	}
+	{
+		await using it = ag();
+		await it.next();
+	}
+	assert(compareArray(trace, [1, "finally", "async finally"]), trace);
+	`
+	testAsyncFuncWithTestLib(SCRIPT, _undefined, t)
+}
+
+type testDisposable struct {
+	disposed bool
+	err      error
+}
+
+func (d *testDisposable) Dispose() error {
+	d.disposed = true
+	return d.err
+}
+
+func TestGoDisposable(t *testing.T) {
+	r := New()
+	d := &testDisposable{}
+	r.Set("d", d)
+	_, err := r.RunString(`
+	{
+		using x = d;
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.disposed {
+		t.Fatal("not disposed")
+	}
+
+	d1 := &testDisposable{err: errors.New("dispose failed")}
+	r.Set("d1", d1)
+	res, err := r.RunString(`
+	let msg;
+	try {
+		using x = d1;
+	} catch (e) {
+		msg = e.value.Error();
+	}
+	msg;
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := res.String(); s != "dispose failed" {
+		t.Fatal(s)
+	}
+}
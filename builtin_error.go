@@ -166,6 +166,29 @@ func (r *Runtime) builtin_AggregateError(args []Value, proto *Object) *Object {
 	return obj.val
 }
 
+func (r *Runtime) builtin_SuppressedError(args []Value, proto *Object) *Object {
+	obj := r.newErrorObject(proto, classError)
+	if len(args) > 2 && args[2] != _undefined {
+		obj._putProp("message", args[2].toString(), true, false, true)
+	}
+	var err, suppressed Value = _undefined, _undefined
+	if len(args) > 0 {
+		err = args[0]
+	}
+	if len(args) > 1 {
+		suppressed = args[1]
+	}
+	obj._putProp("error", err, true, false, true)
+	obj._putProp("suppressed", suppressed, true, false, true)
+	return obj.val
+}
+
+// newSuppressedError creates a SuppressedError as it's done by DisposeResources when the disposal
+// of a resource throws while there is already a pending exception.
+func (r *Runtime) newSuppressedError(err, suppressed Value) *Object {
+	return r.builtin_SuppressedError([]Value{err, suppressed}, r.getSuppressedErrorPrototype())
+}
+
 func writeErrorString(sb *StringBuilder, obj *Object) String {
 	var nameStr, msgStr String
 	name := obj.self.getStr("name", nil)
@@ -243,6 +266,20 @@ func (r *Runtime) getAggregateError() *Object {
 	return ret
 }
 
+func (r *Runtime) getSuppressedErrorPrototype() *Object {
+	return r.getSuppressedError().self.getStr("prototype", nil).(*Object)
+}
+
+func (r *Runtime) getSuppressedError() *Object {
+	ret := r.global.SuppressedError
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.SuppressedError = ret
+		r.newNativeFuncConstructProto(ret, r.builtin_SuppressedError, "SuppressedError", r.createErrorPrototype(stringSuppressedError, ret), r.getError(), 3)
+	}
+	return ret
+}
+
 func (r *Runtime) getTypeError() *Object {
 	ret := r.global.TypeError
 	if ret == nil {
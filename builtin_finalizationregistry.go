@@ -0,0 +1,261 @@
+package sobek
+
+import (
+	"runtime"
+	"sync"
+)
+
+type finalizationCell struct {
+	registry        *finalizationRegistryObject
+	heldValue       Value
+	unregisterToken weakValue
+	cleanup         runtime.Cleanup
+}
+
+type finalizationRegistryObject struct {
+	baseObject
+	callback func(FunctionCall) Value
+	cells    map[*finalizationCell]struct{}
+}
+
+// finalizationQueue holds the cells whose targets have been collected. It is populated by the Go runtime
+// from an arbitrary goroutine, therefore it must not access the Runtime.
+type finalizationQueue struct {
+	mu     sync.Mutex
+	cells  []*finalizationCell
+	notify func()
+}
+
+func (q *finalizationQueue) push(cell *finalizationCell) {
+	q.mu.Lock()
+	q.cells = append(q.cells, cell)
+	notify := q.notify
+	q.mu.Unlock()
+	if notify != nil {
+		notify()
+	}
+}
+
+func (q *finalizationQueue) takeAll() []*finalizationCell {
+	q.mu.Lock()
+	cells := q.cells
+	q.cells = nil
+	q.mu.Unlock()
+	return cells
+}
+
+func (r *Runtime) getFinalizationQueue() *finalizationQueue {
+	q := r.finalizationQueue
+	if q == nil {
+		q = &finalizationQueue{}
+		r.finalizationQueue = q
+	}
+	return q
+}
+
+// SetFinalizationCleanupNotifier sets a function that is called when the target of a FinalizationRegistry
+// registration has been garbage collected and the cleanup callback is waiting to be run. The function
+// is called from an arbitrary goroutine, so it must not use the Runtime. Instead, it should schedule
+// a call to RunFinalizationCleanup() on the goroutine that runs the Runtime (e.g. using the host event loop).
+// Passing nil removes the notifier.
+func (r *Runtime) SetFinalizationCleanupNotifier(notify func()) {
+	q := r.getFinalizationQueue()
+	q.mu.Lock()
+	q.notify = notify
+	q.mu.Unlock()
+}
+
+// RunFinalizationCleanup calls the cleanup callbacks of FinalizationRegistry instances for the targets
+// that have been garbage collected since the last call. The callbacks are run as jobs in the Runtime job
+// queue, so any promise jobs they enqueue are also run before this method returns.
+// If a callback throws, the remaining callbacks of the same registry are deferred until the next call
+// and the first exception is returned.
+// This method must not be called concurrently with other Runtime methods and must not be called
+// from inside JavaScript code.
+func (r *Runtime) RunFinalizationCleanup() error {
+	q := r.finalizationQueue
+	if q == nil {
+		return nil
+	}
+	cells := q.takeAll()
+	if len(cells) == 0 {
+		return nil
+	}
+	var order []*finalizationRegistryObject
+	byRegistry := make(map[*finalizationRegistryObject][]*finalizationCell)
+	for _, cell := range cells {
+		if _, exists := byRegistry[cell.registry]; !exists {
+			order = append(order, cell.registry)
+		}
+		byRegistry[cell.registry] = append(byRegistry[cell.registry], cell)
+	}
+	var firstErr error
+	err := r.runWrapped(func() {
+		for _, registry := range order {
+			r.jobQueue = append(r.jobQueue, func() {
+				if ex := registry.cleanup(byRegistry[registry]); ex != nil && firstErr == nil {
+					firstErr = ex
+				}
+			})
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return firstErr
+}
+
+// cleanup implements CleanupFinalizationRegistry for the given empty cells.
+func (fr *finalizationRegistryObject) cleanup(cells []*finalizationCell) *Exception {
+	r := fr.val.runtime
+	for i, cell := range cells {
+		if _, exists := fr.cells[cell]; !exists {
+			// unregistered after the target had been collected
+			continue
+		}
+		delete(fr.cells, cell)
+		ex := r.vm.try(func() {
+			fr.callback(FunctionCall{This: _undefined, Arguments: []Value{cell.heldValue}})
+		})
+		if ex != nil {
+			q := r.getFinalizationQueue()
+			q.mu.Lock()
+			q.cells = append(q.cells, cells[i+1:]...)
+			q.mu.Unlock()
+			return ex
+		}
+	}
+	return nil
+}
+
+func (fr *finalizationRegistryObject) register(target, heldValue, unregisterToken Value) {
+	cell := &finalizationCell{
+		registry:        fr,
+		heldValue:       heldValue,
+		unregisterToken: newWeakValue(unregisterToken),
+	}
+	q := fr.val.runtime.getFinalizationQueue()
+	switch target := target.(type) {
+	case *Object:
+		cell.cleanup = runtime.AddCleanup(target, q.push, cell)
+	case *Symbol:
+		cell.cleanup = runtime.AddCleanup(target, q.push, cell)
+	}
+	if fr.cells == nil {
+		fr.cells = make(map[*finalizationCell]struct{})
+	}
+	fr.cells[cell] = struct{}{}
+}
+
+func (fr *finalizationRegistryObject) unregister(token Value) bool {
+	removed := false
+	for cell := range fr.cells {
+		if t := cell.unregisterToken.get(); t != nil && t == token {
+			cell.cleanup.Stop()
+			delete(fr.cells, cell)
+			removed = true
+		}
+	}
+	return removed
+}
+
+func (r *Runtime) toFinalizationRegistryObject(v Value, method string) *finalizationRegistryObject {
+	thisObj := r.toObject(v)
+	fr, ok := thisObj.self.(*finalizationRegistryObject)
+	if !ok {
+		panic(r.NewTypeError("Method FinalizationRegistry.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: thisObj})))
+	}
+	return fr
+}
+
+func (r *Runtime) finalizationRegistryProto_register(call FunctionCall) Value {
+	fr := r.toFinalizationRegistryObject(call.This, "register")
+	target := call.Argument(0)
+	if !r.canBeHeldWeakly(target) {
+		panic(r.NewTypeError("FinalizationRegistry.prototype.register: invalid target"))
+	}
+	heldValue := call.Argument(1)
+	if target.SameAs(heldValue) {
+		panic(r.NewTypeError("FinalizationRegistry.prototype.register: target and holdings must not be same"))
+	}
+	unregisterToken := call.Argument(2)
+	if !r.canBeHeldWeakly(unregisterToken) {
+		if unregisterToken != _undefined {
+			panic(r.NewTypeError("FinalizationRegistry.prototype.register: invalid unregister token"))
+		}
+	}
+	fr.register(target, heldValue, unregisterToken)
+	return _undefined
+}
+
+func (r *Runtime) finalizationRegistryProto_unregister(call FunctionCall) Value {
+	fr := r.toFinalizationRegistryObject(call.This, "unregister")
+	token := call.Argument(0)
+	if !r.canBeHeldWeakly(token) {
+		panic(r.NewTypeError("FinalizationRegistry.prototype.unregister: invalid unregister token"))
+	}
+	return r.toBoolean(fr.unregister(token))
+}
+
+func (r *Runtime) builtin_newFinalizationRegistry(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		panic(r.needNew("FinalizationRegistry"))
+	}
+	var callback func(FunctionCall) Value
+	if len(args) > 0 {
+		callback, _ = assertCallable(args[0])
+	}
+	if callback == nil {
+		panic(r.NewTypeError("FinalizationRegistry: cleanup must be callable"))
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.global.FinalizationRegistry, r.getFinalizationRegistryPrototype())
+	o := &Object{runtime: r}
+
+	fr := &finalizationRegistryObject{}
+	fr.class = classObject
+	fr.val = o
+	fr.extensible = true
+	o.self = fr
+	fr.prototype = proto
+	fr.init()
+	fr.callback = callback
+	return o
+}
+
+func (r *Runtime) createFinalizationRegistryProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
+
+	o._putProp("constructor", r.getFinalizationRegistry(), true, false, true)
+	o._putProp("register", r.newNativeFunc(r.finalizationRegistryProto_register, "register", 2), true, false, true)
+	o._putProp("unregister", r.newNativeFunc(r.finalizationRegistryProto_unregister, "unregister", 1), true, false, true)
+
+	o._putSym(SymToStringTag, valueProp(asciiString(classFinalizationRegistry), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) createFinalizationRegistry(val *Object) objectImpl {
+	o := r.newNativeConstructOnly(val, r.builtin_newFinalizationRegistry, r.getFinalizationRegistryPrototype(), "FinalizationRegistry", 1)
+
+	return o
+}
+
+func (r *Runtime) getFinalizationRegistryPrototype() *Object {
+	ret := r.global.FinalizationRegistryPrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.FinalizationRegistryPrototype = ret
+		ret.self = r.createFinalizationRegistryProto(ret)
+	}
+	return ret
+}
+
+func (r *Runtime) getFinalizationRegistry() *Object {
+	ret := r.global.FinalizationRegistry
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.FinalizationRegistry = ret
+		ret.self = r.createFinalizationRegistry(ret)
+	}
+	return ret
+}
@@ -0,0 +1,94 @@
+package sobek
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFinalizationRegistry(t *testing.T) {
+	const SCRIPT = `
+	const fr = new FinalizationRegistry(() => {});
+	const target = {};
+	const token = {};
+	assert.sameValue(fr.register(target, "held", token), undefined);
+	assert.sameValue(fr.unregister(token), true);
+	assert.sameValue(fr.unregister(token), false);
+	assert.throws(TypeError, () => fr.register(target, target));
+	assert.throws(TypeError, () => fr.register(1, "held"));
+	assert.throws(TypeError, () => fr.register(target, "held", 1));
+	assert.throws(TypeError, () => fr.unregister(undefined));
+	assert.throws(TypeError, () => new FinalizationRegistry());
+	assert.throws(TypeError, () => FinalizationRegistry(() => {}));
+	assert.sameValue(Object.prototype.toString.call(fr), "[object FinalizationRegistry]");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestFinalizationRegistryCleanup(t *testing.T) {
+	r := New()
+	var notified atomic.Bool
+	r.SetFinalizationCleanupNotifier(func() {
+		notified.Store(true)
+	})
+	_, err := r.RunString(`
+	var cleanedUp = [];
+	var fr = new FinalizationRegistry(held => {
+		cleanedUp.push(held);
+		Promise.resolve().then(() => cleanedUp.push("job"));
+	});
+	var token = {};
+	(function() {
+		fr.register({}, "collected");
+		fr.register({}, "unregistered", token);
+	})();
+	fr.unregister(token);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100 && !notified.Load(); i++ {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	if !notified.Load() {
+		t.Fatal("the notifier has not been called")
+	}
+	if err := r.RunFinalizationCleanup(); err != nil {
+		t.Fatal(err)
+	}
+	res, err := r.RunString(`cleanedUp.join()`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := res.String(); s != "collected,job" {
+		t.Fatalf("unexpected result: %q", s)
+	}
+}
+
+func TestFinalizationRegistryCleanupThrows(t *testing.T) {
+	r := New()
+	_, err := r.RunString(`
+	var fr = new FinalizationRegistry(held => {
+		throw new Error(held);
+	});
+	(function() {
+		fr.register({}, "boom");
+	})();
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+		err = r.RunFinalizationCleanup()
+		if err != nil {
+			break
+		}
+	}
+	if ex, ok := err.(*Exception); !ok || ex.Value().String() != "Error: boom" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -52,6 +52,10 @@ func (r *Runtime) builtin_generatorFunction(args []Value, proto *Object) *Object
 	return r.functionCtor(args, proto, false, true)
 }
 
+func (r *Runtime) builtin_asyncGeneratorFunction(args []Value, proto *Object) *Object {
+	return r.functionCtor(args, proto, true, true)
+}
+
 func (r *Runtime) functionproto_toString(call FunctionCall) Value {
 	obj := r.toObject(call.This)
 	switch f := obj.self.(type) {
@@ -406,6 +410,89 @@ func (r *Runtime) getGeneratorPrototype() *Object {
 	return o
 }
 
+func (r *Runtime) asyncGeneratorProtoEnqueue(call FunctionCall, typ completionType, method string) Value {
+	pcap := r.newPromiseCapability(r.getPromise())
+	if o, ok := call.This.(*Object); ok {
+		if gen, ok := o.self.(*asyncGeneratorObject); ok {
+			gen.enqueue(typ, call.Argument(0), pcap)
+			return pcap.promise
+		}
+	}
+	pcap.reject(r.NewTypeError("Method [AsyncGenerator].prototype.%s called on incompatible receiver", method))
+	return pcap.promise
+}
+
+func (r *Runtime) builtin_asyncgenproto_next(call FunctionCall) Value {
+	return r.asyncGeneratorProtoEnqueue(call, completionNormal, "next")
+}
+
+func (r *Runtime) builtin_asyncgenproto_return(call FunctionCall) Value {
+	return r.asyncGeneratorProtoEnqueue(call, completionReturn, "return")
+}
+
+func (r *Runtime) builtin_asyncgenproto_throw(call FunctionCall) Value {
+	return r.asyncGeneratorProtoEnqueue(call, completionThrow, "throw")
+}
+
+func (r *Runtime) createAsyncGeneratorFunctionProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.getFunctionPrototype(), classObject)
+
+	o._putProp("constructor", r.getAsyncGeneratorFunction(), false, false, true)
+	o._putProp("prototype", r.getAsyncGeneratorPrototype(), false, false, true)
+	o._putSym(SymToStringTag, valueProp(asciiString(classAsyncGeneratorFunction), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) getAsyncGeneratorFunctionPrototype() *Object {
+	var o *Object
+	if o = r.global.AsyncGeneratorFunctionPrototype; o == nil {
+		o = &Object{runtime: r}
+		r.global.AsyncGeneratorFunctionPrototype = o
+		o.self = r.createAsyncGeneratorFunctionProto(o)
+	}
+	return o
+}
+
+func (r *Runtime) createAsyncGeneratorFunction(val *Object) objectImpl {
+	o := r.newNativeFuncConstructObj(val, r.builtin_asyncGeneratorFunction, "AsyncGeneratorFunction", r.getAsyncGeneratorFunctionPrototype(), 1)
+	o.prototype = r.getFunction()
+	return o
+}
+
+func (r *Runtime) getAsyncGeneratorFunction() *Object {
+	var o *Object
+	if o = r.global.AsyncGeneratorFunction; o == nil {
+		o = &Object{runtime: r}
+		r.global.AsyncGeneratorFunction = o
+		o.self = r.createAsyncGeneratorFunction(o)
+	}
+	return o
+}
+
+func (r *Runtime) createAsyncGeneratorProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.getAsyncIteratorPrototype(), classObject)
+
+	o._putProp("constructor", r.getAsyncGeneratorFunctionPrototype(), false, false, true)
+	o._putProp("next", r.newNativeFunc(r.builtin_asyncgenproto_next, "next", 1), true, false, true)
+	o._putProp("return", r.newNativeFunc(r.builtin_asyncgenproto_return, "return", 1), true, false, true)
+	o._putProp("throw", r.newNativeFunc(r.builtin_asyncgenproto_throw, "throw", 1), true, false, true)
+
+	o._putSym(SymToStringTag, valueProp(asciiString(classAsyncGenerator), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) getAsyncGeneratorPrototype() *Object {
+	var o *Object
+	if o = r.global.AsyncGeneratorPrototype; o == nil {
+		o = &Object{runtime: r}
+		r.global.AsyncGeneratorPrototype = o
+		o.self = r.createAsyncGeneratorProto(o)
+	}
+	return o
+}
+
 func (r *Runtime) getFunction() *Object {
 	ret := r.global.Function
 	if ret == nil {
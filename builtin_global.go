@@ -346,6 +346,7 @@ func createGlobalObjectTemplate() *objectTemplate {
 	t.putStr("Reflect", func(r *Runtime) Value { return valueProp(r.getReflect(), true, false, true) })
 	t.putStr("Error", func(r *Runtime) Value { return valueProp(r.getError(), true, false, true) })
 	t.putStr("AggregateError", func(r *Runtime) Value { return valueProp(r.getAggregateError(), true, false, true) })
+	t.putStr("SuppressedError", func(r *Runtime) Value { return valueProp(r.getSuppressedError(), true, false, true) })
 	t.putStr("TypeError", func(r *Runtime) Value { return valueProp(r.getTypeError(), true, false, true) })
 	t.putStr("ReferenceError", func(r *Runtime) Value { return valueProp(r.getReferenceError(), true, false, true) })
 	t.putStr("SyntaxError", func(r *Runtime) Value { return valueProp(r.getSyntaxError(), true, false, true) })
@@ -358,13 +359,21 @@ func createGlobalObjectTemplate() *objectTemplate {
 
 	t.putStr("Math", func(r *Runtime) Value { return valueProp(r.getMath(), true, false, true) })
 	t.putStr("JSON", func(r *Runtime) Value { return valueProp(r.getJSON(), true, false, true) })
+	t.putStr("Atomics", func(r *Runtime) Value { return valueProp(r.getAtomics(), true, false, true) })
+	t.putStr("Intl", func(r *Runtime) Value { return valueProp(r.getIntl(), true, false, true) })
+	t.putStr("Temporal", func(r *Runtime) Value { return valueProp(r.getTemporal(), true, false, true) })
 	addTypedArrays(t)
 	t.putStr("Symbol", func(r *Runtime) Value { return valueProp(r.getSymbol(), true, false, true) })
 	t.putStr("WeakSet", func(r *Runtime) Value { return valueProp(r.getWeakSet(), true, false, true) })
 	t.putStr("WeakMap", func(r *Runtime) Value { return valueProp(r.getWeakMap(), true, false, true) })
+	t.putStr("WeakRef", func(r *Runtime) Value { return valueProp(r.getWeakRef(), true, false, true) })
+	t.putStr("FinalizationRegistry", func(r *Runtime) Value { return valueProp(r.getFinalizationRegistry(), true, false, true) })
+	t.putStr("DisposableStack", func(r *Runtime) Value { return valueProp(r.getDisposableStack(), true, false, true) })
+	t.putStr("AsyncDisposableStack", func(r *Runtime) Value { return valueProp(r.getAsyncDisposableStack(), true, false, true) })
 	t.putStr("Map", func(r *Runtime) Value { return valueProp(r.getMap(), true, false, true) })
 	t.putStr("Set", func(r *Runtime) Value { return valueProp(r.getSet(), true, false, true) })
 	t.putStr("Promise", func(r *Runtime) Value { return valueProp(r.getPromise(), true, false, true) })
+	t.putStr("Iterator", func(r *Runtime) Value { return valueProp(r.getIteratorConstructor(), true, false, true) })
 
 	t.putStr("globalThis", func(r *Runtime) Value { return valueProp(r.globalObject, true, false, true) })
 	t.putStr("NaN", func(r *Runtime) Value { return valueProp(_NaN, false, false, false) })
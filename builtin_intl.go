@@ -0,0 +1,451 @@
+package sobek
+
+import (
+	"strings"
+	"sync"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+
+	"github.com/grafana/sobek/unistring"
+)
+
+const classIntl = "Intl"
+
+// intlState holds the per-Runtime locale settings and the formatters used by the toLocaleString()
+// family of methods when they are called without arguments.
+type intlState struct {
+	defaultLocale    language.Tag
+	availableLocales map[string]struct{}
+
+	collator       *intlCollator
+	numberFormat   *intlNumberFormat
+	dateTimeFormat [3]*intlDateTimeFormat
+}
+
+func (s *intlState) resetCache() {
+	s.collator = nil
+	s.numberFormat = nil
+	s.dateTimeFormat = [3]*intlDateTimeFormat{}
+}
+
+// intlLocale is the result of matching the requested locales against the available ones.
+type intlLocale struct {
+	// the matched locale without any extensions
+	base string
+	// the requested tag the locale has been matched against, it's only used to look up the
+	// Unicode extension keywords
+	requested    language.Tag
+	hasRequested bool
+}
+
+// keyword returns the value of the Unicode extension keyword (e.g. "nu" or "kn") of the requested locale.
+func (l *intlLocale) keyword(key string) string {
+	if !l.hasRequested {
+		return ""
+	}
+	if v := l.requested.TypeForKey(key); v != "" {
+		return v
+	}
+	// a key without a value means "true"
+	if ext, ok := l.requested.Extension('u'); ok {
+		for _, subtag := range strings.Split(ext.String(), "-") {
+			if subtag == key {
+				return "true"
+			}
+		}
+	}
+	return ""
+}
+
+func (l *intlLocale) tag() language.Tag {
+	tag, err := language.Parse(l.base)
+	if err != nil {
+		return language.Und
+	}
+	return tag
+}
+
+// String returns the resolved locale with the given keywords appended as a Unicode extension, the
+// keywords are key-value pairs, the pairs with an empty value are skipped.
+func (l *intlLocale) String(keywords ...string) string {
+	var sb strings.Builder
+	sb.WriteString(l.base)
+	ext := false
+	for i := 0; i+1 < len(keywords); i += 2 {
+		if keywords[i+1] == "" {
+			continue
+		}
+		if !ext {
+			sb.WriteString("-u")
+			ext = true
+		}
+		sb.WriteByte('-')
+		sb.WriteString(keywords[i])
+		if v := keywords[i+1]; v != "true" {
+			sb.WriteByte('-')
+			sb.WriteString(v)
+		}
+	}
+	return sb.String()
+}
+
+var (
+	intlLanguages     map[string]struct{}
+	intlLanguagesOnce sync.Once
+)
+
+// getIntlLanguages returns the languages that are available when the host has not restricted
+// the set of locales: all the languages that have collation, number or date data.
+func getIntlLanguages() map[string]struct{} {
+	intlLanguagesOnce.Do(func() {
+		intlLanguages = make(map[string]struct{})
+		for _, tag := range collate.Supported() {
+			if base, conf := tag.Base(); conf == language.Exact {
+				intlLanguages[base.String()] = struct{}{}
+			}
+		}
+		for lang := range intlCurrencyPatterns {
+			intlLanguages[lang] = struct{}{}
+		}
+		for locale := range intlDateLocales {
+			intlLanguages[strings.SplitN(locale, "-", 2)[0]] = struct{}{}
+		}
+		delete(intlLanguages, "und")
+	})
+	return intlLanguages
+}
+
+func (r *Runtime) intlDefaultLocale() string {
+	if r.intl.defaultLocale == language.Und {
+		return "en-US"
+	}
+	return intlStripExtensions(r.intl.defaultLocale.String())
+}
+
+func (r *Runtime) intlIsAvailableLocale(locale string) bool {
+	if r.intl.availableLocales != nil {
+		_, exists := r.intl.availableLocales[locale]
+		return exists
+	}
+	lang := locale
+	if idx := strings.IndexByte(lang, '-'); idx >= 0 {
+		lang = lang[:idx]
+	}
+	_, exists := getIntlLanguages()[lang]
+	return exists
+}
+
+// intlStripExtensions removes the extension and private use sequences from a canonical language tag.
+func intlStripExtensions(locale string) string {
+	for i := 0; i+2 < len(locale); i++ {
+		if locale[i] == '-' && locale[i+2] == '-' {
+			return locale[:i]
+		}
+	}
+	return locale
+}
+
+// intlBestAvailableLocale implements BestAvailableLocale.
+func (r *Runtime) intlBestAvailableLocale(locale string) string {
+	for {
+		if r.intlIsAvailableLocale(locale) {
+			return locale
+		}
+		pos := strings.LastIndexByte(locale, '-')
+		if pos < 0 {
+			return ""
+		}
+		if pos >= 2 && locale[pos-2] == '-' {
+			pos -= 2
+		}
+		locale = locale[:pos]
+	}
+}
+
+// intlResolveLocale implements the lookup matcher. The "best fit" matcher is the same.
+func (r *Runtime) intlResolveLocale(requested []language.Tag) intlLocale {
+	for _, tag := range requested {
+		if locale := r.intlBestAvailableLocale(intlStripExtensions(tag.String())); locale != "" {
+			return intlLocale{
+				base:         locale,
+				requested:    tag,
+				hasRequested: true,
+			}
+		}
+	}
+	return intlLocale{base: r.intlDefaultLocale()}
+}
+
+func isIntlAlpha(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i] | 0x20; c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isIntlAlnum(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < '0' || c > '9') && ((c|0x20) < 'a' || (c|0x20) > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// isIntlTypeSequence checks if the string matches the "type" production of UTS #35 which
+// is required for the values of options like calendar, collation and numberingSystem.
+func isIntlTypeSequence(s string) bool {
+	for _, part := range strings.Split(s, "-") {
+		if len(part) < 3 || len(part) > 8 || !isIntlAlnum(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// intlCanonicalizeTag validates and canonicalizes a language tag. Well-formed tags with subtags unknown to
+// golang.org/x/text only get their case normalised.
+func intlCanonicalizeTag(s string) (language.Tag, string, bool) {
+	parts := strings.Split(s, "-")
+	if l := len(parts[0]); l < 2 || l == 4 || l > 8 || !isIntlAlpha(parts[0]) || strings.IndexByte(s, '_') >= 0 {
+		return language.Und, "", false
+	}
+	tag, err := language.Parse(s)
+	if err != nil {
+		if _, ok := err.(language.ValueError); !ok {
+			return language.Und, "", false
+		}
+		for i, part := range parts {
+			switch {
+			case i == 0:
+				parts[i] = strings.ToLower(part)
+			case len(part) == 2 && isIntlAlpha(part) && len(parts[i-1]) > 1:
+				parts[i] = strings.ToUpper(part)
+			case len(part) == 4 && isIntlAlpha(part) && len(parts[i-1]) > 1:
+				parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+			default:
+				parts[i] = strings.ToLower(part)
+			}
+		}
+		return tag, strings.Join(parts, "-"), true
+	}
+	canonical := tag.String()
+	if idx := strings.Index(canonical, "-u-"); idx >= 0 {
+		// "true" is the default value of a keyword and is omitted in the canonical form
+		canonical = canonical[:idx] + strings.ReplaceAll(canonical[idx:]+"-", "-true-", "-")
+		canonical = canonical[:len(canonical)-1]
+	}
+	return tag, canonical, true
+}
+
+// intlCanonicalizeLocaleList implements CanonicalizeLocaleList.
+func (r *Runtime) intlCanonicalizeLocaleList(locales Value) ([]language.Tag, []string) {
+	if locales == _undefined {
+		return nil, nil
+	}
+	var list []Value
+	if s, ok := locales.(String); ok {
+		list = []Value{s}
+	} else {
+		o := r.toObject(locales)
+		l := toLength(o.self.getStr("length", nil))
+		for i := int64(0); i < l; i++ {
+			if v := o.self.getIdx(valueInt(i), nil); v != nil {
+				switch v.(type) {
+				case String, *Object:
+				default:
+					panic(r.NewTypeError("Language ID should be string or object."))
+				}
+				list = append(list, v)
+			}
+		}
+	}
+	tags := make([]language.Tag, 0, len(list))
+	names := make([]string, 0, len(list))
+	seen := make(map[string]struct{}, len(list))
+	for _, v := range list {
+		tag, name, ok := intlCanonicalizeTag(v.toString().String())
+		if !ok {
+			panic(r.newErrorf(r.getRangeError(), "Incorrect locale information provided"))
+		}
+		if _, exists := seen[name]; exists {
+			continue
+		}
+		seen[name] = struct{}{}
+		tags = append(tags, tag)
+		names = append(names, name)
+	}
+	return tags, names
+}
+
+func (r *Runtime) intlLocaleList(locales Value) []language.Tag {
+	tags, _ := r.intlCanonicalizeLocaleList(locales)
+	return tags
+}
+
+// intlCoerceOptions implements CoerceOptionsToObject. It returns nil if options is undefined.
+func (r *Runtime) intlCoerceOptions(options Value) *Object {
+	if options == _undefined {
+		return nil
+	}
+	return r.toObject(options)
+}
+
+func intlGet(options *Object, name unistring.String) Value {
+	if options == nil {
+		return _undefined
+	}
+	return nilSafe(options.self.getStr(name, nil))
+}
+
+// intlGetStringOption implements GetOption for string options. If values is not empty, the value must be
+// one of them. It returns fallback if the option is undefined.
+func (r *Runtime) intlGetStringOption(options *Object, name unistring.String, values []string, fallback string) string {
+	v := intlGet(options, name)
+	if v == _undefined {
+		return fallback
+	}
+	s := v.toString().String()
+	if len(values) > 0 {
+		for _, value := range values {
+			if s == value {
+				return s
+			}
+		}
+		panic(r.newErrorf(r.getRangeError(), "Value %s out of range for Intl options property %s", s, name))
+	}
+	return s
+}
+
+// intlUnsupportedOption throws a RangeError for a valid option value which is not implemented.
+func (r *Runtime) intlUnsupportedOption(name, value string) {
+	panic(r.newErrorf(r.getRangeError(), "Intl options property %s with value %s is not supported", name, value))
+}
+
+// intlGetBoolOption implements GetOption for boolean options. The second result is false if the option is undefined.
+func (r *Runtime) intlGetBoolOption(options *Object, name unistring.String) (bool, bool) {
+	v := intlGet(options, name)
+	if v == _undefined {
+		return false, false
+	}
+	return v.ToBoolean(), true
+}
+
+// intlDefaultNumberOption implements DefaultNumberOption. The second result is false if the value is undefined.
+func (r *Runtime) intlDefaultNumberOption(v Value, name unistring.String, minimum, maximum, fallback int) (int, bool) {
+	if v == _undefined {
+		return fallback, false
+	}
+	f := v.ToFloat()
+	if f != f || f < float64(minimum) || f > float64(maximum) {
+		panic(r.newErrorf(r.getRangeError(), "%s value is out of range.", name))
+	}
+	return int(f), true
+}
+
+// intlGetNumberOption implements GetNumberOption.
+func (r *Runtime) intlGetNumberOption(options *Object, name unistring.String, minimum, maximum, fallback int) (int, bool) {
+	return r.intlDefaultNumberOption(intlGet(options, name), name, minimum, maximum, fallback)
+}
+
+// intlGetLocaleMatcher reads and validates the localeMatcher option. Both matchers are implemented
+// by the lookup algorithm.
+func (r *Runtime) intlGetLocaleMatcher(options *Object) {
+	r.intlGetStringOption(options, "localeMatcher", []string{"lookup", "best fit"}, "best fit")
+}
+
+func (r *Runtime) intl_getCanonicalLocales(call FunctionCall) Value {
+	_, names := r.intlCanonicalizeLocaleList(call.Argument(0))
+	values := make([]Value, len(names))
+	for i, name := range names {
+		values[i] = newStringValue(name)
+	}
+	return r.newArrayValues(values)
+}
+
+// intlSupportedLocalesOf implements the supportedLocalesOf() method which is the same for all the constructors.
+func (r *Runtime) intlSupportedLocalesOf(call FunctionCall) Value {
+	tags, names := r.intlCanonicalizeLocaleList(call.Argument(0))
+	r.intlGetLocaleMatcher(r.intlCoerceOptions(call.Argument(1)))
+	var values []Value
+	for i, tag := range tags {
+		if r.intlBestAvailableLocale(intlStripExtensions(tag.String())) != "" {
+			values = append(values, newStringValue(names[i]))
+		}
+	}
+	return r.newArrayValues(values)
+}
+
+func createIntlTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.global.ObjectPrototype
+	}
+
+	t.putSym(SymToStringTag, func(r *Runtime) Value { return valueProp(asciiString(classIntl), false, false, true) })
+
+	t.putStr("Collator", func(r *Runtime) Value { return valueProp(r.getIntlCollator(), true, false, true) })
+	t.putStr("DateTimeFormat", func(r *Runtime) Value { return valueProp(r.getIntlDateTimeFormat(), true, false, true) })
+	t.putStr("NumberFormat", func(r *Runtime) Value { return valueProp(r.getIntlNumberFormat(), true, false, true) })
+	t.putStr("PluralRules", func(r *Runtime) Value { return valueProp(r.getIntlPluralRules(), true, false, true) })
+
+	t.putStr("getCanonicalLocales", func(r *Runtime) Value { return r.methodProp(r.intl_getCanonicalLocales, "getCanonicalLocales", 1) })
+
+	return t
+}
+
+var intlTemplate *objectTemplate
+var intlTemplateOnce sync.Once
+
+func getIntlTemplate() *objectTemplate {
+	intlTemplateOnce.Do(func() {
+		intlTemplate = createIntlTemplate()
+	})
+	return intlTemplate
+}
+
+func (r *Runtime) getIntl() *Object {
+	ret := r.global.Intl
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.Intl = ret
+		r.newTemplatedObject(getIntlTemplate(), ret)
+	}
+	return ret
+}
+
+// intlBoundFunc returns the function stored in *slot creating it on the first access. It's used for
+// the format and compare getters which return a function bound to the instance.
+func (r *Runtime) intlBoundFunc(slot **Object, f func(FunctionCall) Value, length int) *Object {
+	if *slot == nil {
+		*slot = r.newNativeFunc(f, "", length)
+	}
+	return *slot
+}
+
+func (r *Runtime) intlResolvedOptions(props ...interface{}) *Object {
+	o := r.NewObject()
+	for i := 0; i+1 < len(props); i += 2 {
+		var v Value
+		switch p := props[i+1].(type) {
+		case nil:
+			continue
+		case string:
+			if p == "" {
+				continue
+			}
+			v = newStringValue(p)
+		case int:
+			v = intToValue(int64(p))
+		case bool:
+			v = r.toBoolean(p)
+		case Value:
+			v = p
+		}
+		o.self._putProp(unistring.NewFromString(props[i].(string)), v, true, true, true)
+	}
+	return o
+}
@@ -0,0 +1,183 @@
+package sobek
+
+import (
+	"golang.org/x/text/collate"
+	"golang.org/x/text/unicode/norm"
+)
+
+type intlCollator struct {
+	locale            string
+	usage             string
+	sensitivity       string
+	ignorePunctuation bool
+	collation         string
+	numeric           bool
+	caseFirst         string
+
+	collator *collate.Collator
+}
+
+func (c *intlCollator) compare(x, y String) int {
+	return c.collator.CompareString(norm.NFD.String(x.String()), norm.NFD.String(y.String()))
+}
+
+func isIntlBoolKeyword(s string) bool {
+	return s == "true" || s == "false"
+}
+
+// newIntlCollator implements InitializeCollator. The caseFirst option is validated but
+// not supported by golang.org/x/text/collate, so it always resolves to "false".
+func (r *Runtime) newIntlCollator(locales, opts Value) *intlCollator {
+	requested := r.intlLocaleList(locales)
+	options := r.intlCoerceOptions(opts)
+	c := &intlCollator{}
+	c.usage = r.intlGetStringOption(options, "usage", []string{"sort", "search"}, "sort")
+	r.intlGetLocaleMatcher(options)
+	collation := r.intlGetStringOption(options, "collation", nil, "")
+	if collation != "" && !isIntlTypeSequence(collation) {
+		panic(r.newErrorf(r.getRangeError(), "Invalid collation : %s", collation))
+	}
+	var kn string
+	if numeric, ok := r.intlGetBoolOption(options, "numeric"); ok {
+		kn = "false"
+		if numeric {
+			kn = "true"
+		}
+	}
+	r.intlGetStringOption(options, "caseFirst", []string{"upper", "lower", "false"}, "")
+
+	locale := r.intlResolveLocale(requested)
+	kn, knFromExt := intlResolveKeyword(locale.keyword("kn"), kn, isIntlBoolKeyword, "false")
+	c.numeric = kn == "true"
+	c.caseFirst = "false"
+	c.collation = "default"
+	if !knFromExt {
+		kn = ""
+	}
+	c.locale = locale.String("kn", kn)
+
+	c.sensitivity = r.intlGetStringOption(options, "sensitivity", []string{"base", "accent", "case", "variant"}, "variant")
+	ignorePunctuation, _ := r.intlGetBoolOption(options, "ignorePunctuation")
+	c.ignorePunctuation = ignorePunctuation
+
+	tag := locale.tag()
+	var collateOptions []collate.Option
+	switch c.sensitivity {
+	case "base":
+		collateOptions = append(collateOptions, collate.IgnoreCase, collate.IgnoreDiacritics)
+	case "accent":
+		collateOptions = append(collateOptions, collate.IgnoreCase)
+	case "case":
+		collateOptions = append(collateOptions, collate.IgnoreDiacritics)
+	}
+	if c.numeric {
+		collateOptions = append(collateOptions, collate.Numeric)
+	}
+	if c.ignorePunctuation {
+		if t, err := tag.SetTypeForKey("ka", "shifted"); err == nil {
+			tag = t
+		}
+		collateOptions = append(collateOptions, collate.OptionsFromTag(tag))
+	}
+	c.collator = collate.New(tag, collateOptions...)
+	return c
+}
+
+func (c *intlCollator) resolvedOptions(r *Runtime) *Object {
+	return r.intlResolvedOptions(
+		"locale", c.locale,
+		"usage", c.usage,
+		"sensitivity", c.sensitivity,
+		"ignorePunctuation", c.ignorePunctuation,
+		"collation", c.collation,
+		"numeric", c.numeric,
+		"caseFirst", c.caseFirst,
+	)
+}
+
+type collatorObject struct {
+	baseObject
+	c            *intlCollator
+	boundCompare *Object
+}
+
+func (r *Runtime) toCollator(v Value, method string) *collatorObject {
+	if o, ok := v.(*Object); ok {
+		if c, ok := o.self.(*collatorObject); ok {
+			return c
+		}
+	}
+	panic(r.NewTypeError("Method Intl.Collator.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+func (r *Runtime) builtin_newCollator(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		newTarget = r.getIntlCollator()
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.global.IntlCollator, r.getIntlCollatorPrototype())
+	c := r.newIntlCollator(argAt(args, 0), argAt(args, 1))
+	o := &Object{runtime: r}
+	co := &collatorObject{c: c}
+	co.class = classObject
+	co.val = o
+	co.extensible = true
+	o.self = co
+	co.prototype = proto
+	co.init()
+	return o
+}
+
+func (r *Runtime) collatorProto_getCompare(call FunctionCall) Value {
+	co := r.toCollator(call.This, "compare")
+	return r.intlBoundFunc(&co.boundCompare, func(call FunctionCall) Value {
+		return intToValue(int64(co.c.compare(call.Argument(0).toString(), call.Argument(1).toString())))
+	}, 2)
+}
+
+func (r *Runtime) collatorProto_resolvedOptions(call FunctionCall) Value {
+	co := r.toCollator(call.This, "resolvedOptions")
+	return co.c.resolvedOptions(r)
+}
+
+func (r *Runtime) createIntlCollatorProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
+
+	o._putProp("constructor", r.getIntlCollator(), true, false, true)
+	o.setOwnStr("compare", &valueProperty{
+		getterFunc:   r.newNativeFunc(r.collatorProto_getCompare, "get compare", 0),
+		accessor:     true,
+		configurable: true,
+	}, true)
+	o._putProp("resolvedOptions", r.newNativeFunc(r.collatorProto_resolvedOptions, "resolvedOptions", 0), true, false, true)
+
+	o._putSym(SymToStringTag, valueProp(asciiString("Intl.Collator"), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) createIntlCollator(val *Object) objectImpl {
+	o := r.newNativeConstructOnly(val, r.builtin_newCollator, r.getIntlCollatorPrototype(), "Collator", 0)
+	o._putProp("supportedLocalesOf", r.newNativeFunc(r.intlSupportedLocalesOf, "supportedLocalesOf", 1), true, false, true)
+
+	return o
+}
+
+func (r *Runtime) getIntlCollatorPrototype() *Object {
+	ret := r.global.IntlCollatorPrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.IntlCollatorPrototype = ret
+		ret.self = r.createIntlCollatorProto(ret)
+	}
+	return ret
+}
+
+func (r *Runtime) getIntlCollator() *Object {
+	ret := r.global.IntlCollator
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.IntlCollator = ret
+		ret.self = r.createIntlCollator(ret)
+	}
+	return ret
+}
@@ -0,0 +1,1004 @@
+package sobek
+
+// The locale data below has been extracted from CLDR 47 (via ICU 77). The patterns use the CLDR
+// date field symbols, see https://unicode.org/reports/tr35/tr35-dates.html#Date_Field_Symbol_Table
+// Like the major engines do for web compatibility, U+202F (narrow no-break space) in the time patterns
+// has been replaced with a regular space.
+
+var intlDateLocales = map[string]*intlDateLocale{
+	"en": {
+		months:            [3][12]string{{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}, {"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}, {"J", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"}},
+		weekdays:          [3][7]string{{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}, {"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}, {"S", "M", "T", "W", "T", "F", "S"}},
+		dayPeriods:        [2]string{"AM", "PM"},
+		hour12:            true,
+		gmt:               "GMT",
+		gmtMinus:          "-",
+		utcLong:           "Coordinated Universal Time",
+		fractionSeparator: ".",
+		dateStyles:        [4]string{"EEEE, MMMM d, y", "MMMM d, y", "MMM d, y", "M/d/yy"},
+		timeStyles:        [2][4]string{{"h:mm:ss a zzzz", "h:mm:ss a z", "h:mm:ss a", "h:mm a"}, {"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"}},
+		dateTimeStyles:    [4]string{"{1}' at '{0}", "{1}' at '{0}", "{1}, {0}", "{1}, {0}"},
+		skeletons: map[string]string{
+			"d":       "d",
+			"M":       "M",
+			"Md":      "M/d",
+			"MMM":     "LLL",
+			"MMMd":    "MMM d",
+			"MMMM":    "LLLL",
+			"MMMMd":   "MMMM d",
+			"y":       "y",
+			"yd":      "y' (day: 'd)",
+			"yM":      "M/y",
+			"yMd":     "M/d/y",
+			"yMMM":    "MMM y",
+			"yMMMd":   "MMM d, y",
+			"yMMMM":   "MMMM y",
+			"yMMMMd":  "MMMM d, y",
+			"E":       "EEE",
+			"Ed":      "d EEE",
+			"EM":      "M EEE",
+			"EMd":     "EEE, M/d",
+			"EMMM":    "LLL EEE",
+			"EMMMd":   "EEE, MMM d",
+			"EMMMM":   "EEE' (month: 'LLLL)",
+			"EMMMMd":  "EEE, MMMM d",
+			"Ey":      "y EEE",
+			"Eyd":     "d EEE y",
+			"EyM":     "M/y EEE",
+			"EyMd":    "EEE, M/d/y",
+			"EyMMM":   "MMM y EEE",
+			"EyMMMd":  "EEE, MMM d, y",
+			"EyMMMM":  "MMMM y EEE",
+			"EyMMMMd": "EEE, MMMM d, y",
+			"h":       "h a",
+			"hz":      "h a z",
+			"hm":      "h:mm a",
+			"hmz":     "h:mm a z",
+			"hms":     "h:mm:ss a",
+			"hmsz":    "h:mm:ss a z",
+			"m":       "m",
+			"ms":      "mm:ss",
+			"s":       "s",
+			"H":       "HH",
+			"Hz":      "HH z",
+			"Hm":      "HH:mm",
+			"Hmz":     "HH:mm z",
+			"Hms":     "HH:mm:ss",
+			"Hmsz":    "HH:mm:ss z",
+		},
+	},
+	"en-GB": {
+		months:            [3][12]string{{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}, {"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sept", "Oct", "Nov", "Dec"}, {"J", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"}},
+		weekdays:          [3][7]string{{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}, {"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}, {"S", "M", "T", "W", "T", "F", "S"}},
+		dayPeriods:        [2]string{"am", "pm"},
+		hour12:            false,
+		gmt:               "GMT",
+		gmtMinus:          "-",
+		utcLong:           "Coordinated Universal Time",
+		fractionSeparator: ".",
+		dateStyles:        [4]string{"EEEE, d MMMM y", "d MMMM y", "d MMM y", "dd/MM/y"},
+		timeStyles:        [2][4]string{{"hh:mm:ss a zzzz", "hh:mm:ss a z", "hh:mm:ss a", "hh:mm a"}, {"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"}},
+		dateTimeStyles:    [4]string{"{1}' at '{0}", "{1}' at '{0}", "{1}, {0}", "{1}, {0}"},
+		skeletons: map[string]string{
+			"d":       "d",
+			"M":       "M",
+			"Md":      "dd/MM",
+			"MMM":     "LLL",
+			"MMMd":    "d MMM",
+			"MMMM":    "LLLL",
+			"MMMMd":   "d MMMM",
+			"y":       "y",
+			"yd":      "y' (day: 'd)",
+			"yM":      "MM/y",
+			"yMd":     "dd/MM/y",
+			"yMMM":    "MMM y",
+			"yMMMd":   "d MMM y",
+			"yMMMM":   "MMMM y",
+			"yMMMMd":  "d MMMM y",
+			"E":       "EEE",
+			"Ed":      "EEE d",
+			"EM":      "M EEE",
+			"EMd":     "EEE dd/MM",
+			"EMMM":    "LLL EEE",
+			"EMMMd":   "EEE d MMM",
+			"EMMMM":   "EEE' (month: 'LLLL)",
+			"EMMMMd":  "EEE d MMMM",
+			"Ey":      "y EEE",
+			"Eyd":     "EEE d y",
+			"EyM":     "MM/y EEE",
+			"EyMd":    "EEE, dd/MM/y",
+			"EyMMM":   "MMM y EEE",
+			"EyMMMd":  "EEE, d MMM y",
+			"EyMMMM":  "MMMM y EEE",
+			"EyMMMMd": "EEE, d MMMM y",
+			"h":       "h a",
+			"hz":      "h a z",
+			"hm":      "h:mm a",
+			"hmz":     "h:mm a z",
+			"hms":     "h:mm:ss a",
+			"hmsz":    "h:mm:ss a z",
+			"m":       "m",
+			"ms":      "mm:ss",
+			"s":       "s",
+			"H":       "HH",
+			"Hz":      "HH z",
+			"Hm":      "HH:mm",
+			"Hmz":     "HH:mm z",
+			"Hms":     "HH:mm:ss",
+			"Hmsz":    "H:mm:ss z",
+		},
+	},
+	"de": {
+		months:            [3][12]string{{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"}, {"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."}, {"J", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"}},
+		standaloneMonths:  &[3][12]string{{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"}, {"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"}, {"J", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"}},
+		weekdays:          [3][7]string{{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"}, {"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"}, {"S", "M", "D", "M", "D", "F", "S"}},
+		dayPeriods:        [2]string{"AM", "PM"},
+		hour12:            false,
+		gmt:               "GMT",
+		gmtMinus:          "-",
+		utcLong:           "Koordinierte Weltzeit",
+		fractionSeparator: ",",
+		dateStyles:        [4]string{"EEEE, d. MMMM y", "d. MMMM y", "dd.MM.y", "dd.MM.yy"},
+		timeStyles:        [2][4]string{{"hh:mm:ss a zzzz", "hh:mm:ss a z", "hh:mm:ss a", "hh:mm a"}, {"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"}},
+		dateTimeStyles:    [4]string{"{1}' um '{0}", "{1}' um '{0}", "{1}, {0}", "{1}, {0}"},
+		skeletons: map[string]string{
+			"d":       "d",
+			"M":       "M",
+			"Md":      "d.M.",
+			"MMM":     "LLL",
+			"MMMd":    "d. MMM",
+			"MMMM":    "LLLL",
+			"MMMMd":   "d. MMMM",
+			"y":       "y",
+			"yd":      "y' (Tag: 'd)",
+			"yM":      "M/y",
+			"yMd":     "d.M.y",
+			"yMMM":    "MMM y",
+			"yMMMd":   "d. MMM y",
+			"yMMMM":   "MMMM y",
+			"yMMMMd":  "d. MMMM y",
+			"E":       "EEE",
+			"Ed":      "EEE, d.",
+			"EM":      "M EEE",
+			"EMd":     "EEE, d.M.",
+			"EMMM":    "LLL EEE",
+			"EMMMd":   "EEE, d. MMM",
+			"EMMMM":   "EEE' (Monat: 'LLLL)",
+			"EMMMMd":  "EEE, d. MMMM",
+			"Ey":      "y EEE",
+			"Eyd":     "y EEE, d.",
+			"EyM":     "M/y EEE",
+			"EyMd":    "EEE, d.M.y",
+			"EyMMM":   "MMM y EEE",
+			"EyMMMd":  "EEE, d. MMM y",
+			"EyMMMM":  "MMMM y EEE",
+			"EyMMMMd": "EEE, d. MMMM y",
+			"h":       "h' Uhr 'a",
+			"hz":      "h' Uhr 'a z",
+			"hm":      "h:mm a",
+			"hmz":     "h:mm a z",
+			"hms":     "h:mm:ss a",
+			"hmsz":    "h:mm:ss a z",
+			"m":       "m",
+			"ms":      "mm:ss",
+			"s":       "s",
+			"H":       "HH' Uhr'",
+			"Hz":      "HH' Uhr 'z",
+			"Hm":      "HH:mm",
+			"Hmz":     "HH:mm z",
+			"Hms":     "HH:mm:ss",
+			"Hmsz":    "H:mm:ss z",
+		},
+	},
+	"es": {
+		months:            [3][12]string{{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"}, {"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"}, {"E", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"}},
+		weekdays:          [3][7]string{{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"}, {"dom", "lun", "mar", "mié", "jue", "vie", "sáb"}, {"D", "L", "M", "X", "J", "V", "S"}},
+		dayPeriods:        [2]string{"a.\u00a0m.", "p.\u00a0m."},
+		hour12:            false,
+		gmt:               "GMT",
+		gmtMinus:          "-",
+		utcLong:           "tiempo universal coordinado",
+		fractionSeparator: ",",
+		dateStyles:        [4]string{"EEEE, d' de 'MMMM' de 'y", "d' de 'MMMM' de 'y", "d MMM y", "d/M/yy"},
+		timeStyles:        [2][4]string{{"h:mm:ss a zzzz", "h:mm:ss a z", "h:mm:ss a", "h:mm a"}, {"H:mm:ss (zzzz)", "H:mm:ss z", "H:mm:ss", "H:mm"}},
+		dateTimeStyles:    [4]string{"{1}, {0}", "{1}, {0}", "{1}, {0}", "{1}, {0}"},
+		skeletons: map[string]string{
+			"d":       "d",
+			"M":       "M",
+			"Md":      "d/M",
+			"MMM":     "LLL",
+			"MMMd":    "d MMM",
+			"MMMM":    "LLLL",
+			"MMMMd":   "d' de 'MMMM",
+			"y":       "y",
+			"yd":      "y' (día: 'd)",
+			"yM":      "M/y",
+			"yMd":     "d/M/y",
+			"yMMM":    "MMM y",
+			"yMMMd":   "d MMM y",
+			"yMMMM":   "MMMM' de 'y",
+			"yMMMMd":  "d' de 'MMMM' de 'y",
+			"E":       "EEE",
+			"Ed":      "EEE d",
+			"EM":      "M EEE",
+			"EMd":     "EEE, d/M",
+			"EMMM":    "LLL EEE",
+			"EMMMd":   "EEE, d MMM",
+			"EMMMM":   "EEE' (mes: 'LLLL)",
+			"EMMMMd":  "EEE, d' de 'MMMM",
+			"Ey":      "y EEE",
+			"Eyd":     "y EEE d",
+			"EyM":     "M/y EEE",
+			"EyMd":    "EEE, d/M/y",
+			"EyMMM":   "MMM y EEE",
+			"EyMMMd":  "EEE, d MMM y",
+			"EyMMMM":  "MMMM' de 'y EEE",
+			"EyMMMMd": "EEE, d' de 'MMMM' de 'y",
+			"h":       "h a",
+			"hz":      "h a z",
+			"hm":      "h:mm a",
+			"hmz":     "h:mm a z",
+			"hms":     "h:mm:ss a",
+			"hmsz":    "h:mm:ss a z",
+			"m":       "m",
+			"ms":      "mm:ss",
+			"s":       "s",
+			"H":       "H",
+			"Hz":      "H z",
+			"Hm":      "H:mm",
+			"Hmz":     "H:mm z",
+			"Hms":     "H:mm:ss",
+			"Hmsz":    "H:mm:ss z",
+		},
+	},
+	"fr": {
+		months:            [3][12]string{{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"}, {"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."}, {"J", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"}},
+		weekdays:          [3][7]string{{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"}, {"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."}, {"D", "L", "M", "M", "J", "V", "S"}},
+		dayPeriods:        [2]string{"AM", "PM"},
+		hour12:            false,
+		gmt:               "UTC",
+		gmtMinus:          "−",
+		utcLong:           "temps universel coordonné",
+		fractionSeparator: ",",
+		dateStyles:        [4]string{"EEEE d MMMM y", "d MMMM y", "d MMM y", "dd/MM/y"},
+		timeStyles:        [2][4]string{{"hh:mm:ss a zzzz", "hh:mm:ss a z", "hh:mm:ss a", "hh:mm a"}, {"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"}},
+		dateTimeStyles:    [4]string{"{1} à {0}", "{1} à {0}", "{1}, {0}", "{1} {0}"},
+		skeletons: map[string]string{
+			"d":       "d",
+			"M":       "M",
+			"Md":      "dd/MM",
+			"MMM":     "LLL",
+			"MMMd":    "d MMM",
+			"MMMM":    "LLLL",
+			"MMMMd":   "d MMMM",
+			"y":       "y",
+			"yd":      "y' (jour: 'd)",
+			"yM":      "MM/y",
+			"yMd":     "dd/MM/y",
+			"yMMM":    "MMM y",
+			"yMMMd":   "d MMM y",
+			"yMMMM":   "MMMM y",
+			"yMMMMd":  "d MMMM y",
+			"E":       "EEE",
+			"Ed":      "EEE d",
+			"EM":      "M EEE",
+			"EMd":     "EEE dd/MM",
+			"EMMM":    "LLL EEE",
+			"EMMMd":   "EEE d MMM",
+			"EMMMM":   "EEE' (mois: 'LLLL)",
+			"EMMMMd":  "EEE d MMMM",
+			"Ey":      "y EEE",
+			"Eyd":     "y EEE d",
+			"EyM":     "MM/y EEE",
+			"EyMd":    "EEE dd/MM/y",
+			"EyMMM":   "MMM y EEE",
+			"EyMMMd":  "EEE d MMM y",
+			"EyMMMM":  "MMMM y EEE",
+			"EyMMMMd": "EEE d MMMM y",
+			"h":       "h a",
+			"hz":      "h a z",
+			"hm":      "h:mm a",
+			"hmz":     "h:mm a z",
+			"hms":     "h:mm:ss a",
+			"hmsz":    "h:mm:ss a z",
+			"m":       "m",
+			"ms":      "mm:ss",
+			"s":       "s",
+			"H":       "HH' h'",
+			"Hz":      "HH' h 'z",
+			"Hm":      "HH:mm",
+			"Hmz":     "HH:mm z",
+			"Hms":     "HH:mm:ss",
+			"Hmsz":    "H:mm:ss z",
+		},
+	},
+	"it": {
+		months:            [3][12]string{{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"}, {"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"}, {"G", "F", "M", "A", "M", "G", "L", "A", "S", "O", "N", "D"}},
+		weekdays:          [3][7]string{{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"}, {"dom", "lun", "mar", "mer", "gio", "ven", "sab"}, {"D", "L", "M", "M", "G", "V", "S"}},
+		dayPeriods:        [2]string{"AM", "PM"},
+		hour12:            false,
+		gmt:               "GMT",
+		gmtMinus:          "-",
+		utcLong:           "Tempo coordinato universale",
+		fractionSeparator: ",",
+		dateStyles:        [4]string{"EEEE d MMMM y", "d MMMM y", "d MMM y", "dd/MM/yy"},
+		timeStyles:        [2][4]string{{"hh:mm:ss a zzzz", "hh:mm:ss a z", "hh:mm:ss a", "hh:mm a"}, {"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"}},
+		dateTimeStyles:    [4]string{"{1}' alle ore '{0}", "{1}' alle ore '{0}", "{1}, {0}", "{1}, {0}"},
+		skeletons: map[string]string{
+			"d":       "d",
+			"M":       "M",
+			"Md":      "dd/MM",
+			"MMM":     "LLL",
+			"MMMd":    "d MMM",
+			"MMMM":    "LLLL",
+			"MMMMd":   "d MMMM",
+			"y":       "y",
+			"yd":      "y' (giorno: 'd)",
+			"yM":      "MM/y",
+			"yMd":     "dd/MM/y",
+			"yMMM":    "MMM y",
+			"yMMMd":   "d MMM y",
+			"yMMMM":   "MMMM y",
+			"yMMMMd":  "d MMMM y",
+			"E":       "EEE",
+			"Ed":      "EEE d",
+			"EM":      "M EEE",
+			"EMd":     "EEE dd/MM",
+			"EMMM":    "LLL EEE",
+			"EMMMd":   "EEE d MMM",
+			"EMMMM":   "EEE' (mese: 'LLLL)",
+			"EMMMMd":  "EEE d MMMM",
+			"Ey":      "y EEE",
+			"Eyd":     "y EEE d",
+			"EyM":     "MM/y EEE",
+			"EyMd":    "EEE dd/MM/y",
+			"EyMMM":   "MMM y EEE",
+			"EyMMMd":  "EEE d MMM y",
+			"EyMMMM":  "MMMM y EEE",
+			"EyMMMMd": "EEE d MMMM y",
+			"h":       "h a",
+			"hz":      "h a z",
+			"hm":      "h:mm a",
+			"hmz":     "h:mm a z",
+			"hms":     "h:mm:ss a",
+			"hmsz":    "h:mm:ss a z",
+			"m":       "m",
+			"ms":      "mm:ss",
+			"s":       "s",
+			"H":       "HH",
+			"Hz":      "HH z",
+			"Hm":      "HH:mm",
+			"Hmz":     "HH:mm z",
+			"Hms":     "HH:mm:ss",
+			"Hmsz":    "H:mm:ss z",
+		},
+	},
+	"ja": {
+		months:            [3][12]string{{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}, {"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}, {"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}},
+		weekdays:          [3][7]string{{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"}, {"日", "月", "火", "水", "木", "金", "土"}, {"日", "月", "火", "水", "木", "金", "土"}},
+		dayPeriods:        [2]string{"午前", "午後"},
+		hour12:            false,
+		gmt:               "GMT",
+		gmtMinus:          "-",
+		utcLong:           "協定世界時",
+		fractionSeparator: ".",
+		dateStyles:        [4]string{"y年M月d日EEEE", "y年M月d日", "y/MM/dd", "y/MM/dd"},
+		timeStyles:        [2][4]string{{"ah:mm:ss zzzz", "ah:mm:ss z", "ah:mm:ss", "ah:mm"}, {"H時mm分ss秒 zzzz", "H:mm:ss z", "H:mm:ss", "H:mm"}},
+		dateTimeStyles:    [4]string{"{1} {0}", "{1} {0}", "{1} {0}", "{1} {0}"},
+		skeletons: map[string]string{
+			"d":       "d日",
+			"M":       "M月",
+			"Md":      "M/d",
+			"MMM":     "M月",
+			"MMMd":    "M月d日",
+			"MMMM":    "M月",
+			"MMMMd":   "M月d日",
+			"y":       "y年",
+			"yd":      "y年 (日: d日)",
+			"yM":      "y/M",
+			"yMd":     "y/M/d",
+			"yMMM":    "y年M月",
+			"yMMMd":   "y年M月d日",
+			"yMMMM":   "y年M月",
+			"yMMMMd":  "y年M月d日",
+			"E":       "EEE",
+			"Ed":      "d日(EEE)",
+			"EM":      "M月 EEE",
+			"EMd":     "M/d(EEE)",
+			"EMMM":    "M月 EEE",
+			"EMMMd":   "M月d日(EEE)",
+			"EMMMM":   "EEE (月: M月)",
+			"EMMMMd":  "M月d日(EEE)",
+			"Ey":      "y年 EEE",
+			"Eyd":     "y年 d日(EEE)",
+			"EyM":     "y/M EEE",
+			"EyMd":    "y/M/d(EEE)",
+			"EyMMM":   "y年M月 EEE",
+			"EyMMMd":  "y年M月d日(EEE)",
+			"EyMMMM":  "y年M月 EEE",
+			"EyMMMMd": "y年M月d日(EEE)",
+			"h":       "ah時",
+			"hz":      "ah時 z",
+			"hm":      "ah:mm",
+			"hmz":     "ah:mm z",
+			"hms":     "ah:mm:ss",
+			"hmsz":    "ah:mm:ss z",
+			"m":       "m",
+			"ms":      "mm:ss",
+			"s":       "s",
+			"H":       "H時",
+			"Hz":      "H時 z",
+			"Hm":      "H:mm",
+			"Hmz":     "H:mm z",
+			"Hms":     "H:mm:ss",
+			"Hmsz":    "H:mm:ss z",
+		},
+	},
+	"ko": {
+		months:            [3][12]string{{"1월", "2월", "3월", "4월", "5월", "6월", "7월", "8월", "9월", "10월", "11월", "12월"}, {"1월", "2월", "3월", "4월", "5월", "6월", "7월", "8월", "9월", "10월", "11월", "12월"}, {"1월", "2월", "3월", "4월", "5월", "6월", "7월", "8월", "9월", "10월", "11월", "12월"}},
+		weekdays:          [3][7]string{{"일요일", "월요일", "화요일", "수요일", "목요일", "금요일", "토요일"}, {"일", "월", "화", "수", "목", "금", "토"}, {"일", "월", "화", "수", "목", "금", "토"}},
+		dayPeriods:        [2]string{"오전", "오후"},
+		hour12:            true,
+		gmt:               "GMT",
+		gmtMinus:          "-",
+		utcLong:           "협정 세계시",
+		fractionSeparator: ".",
+		dateStyles:        [4]string{"y년 MMMM d일 EEEE", "y년 MMMM d일", "y. M. d.", "yy. M. d."},
+		timeStyles:        [2][4]string{{"a h시 m분 s초 zzzz", "a h시 m분 s초 z", "a h:mm:ss", "a h:mm"}, {"H시 m분 s초 zzzz", "H시 m분 s초 z", "H:mm:ss", "HH:mm"}},
+		dateTimeStyles:    [4]string{"{1} {0}", "{1} {0}", "{1} {0}", "{1} {0}"},
+		skeletons: map[string]string{
+			"d":       "d일",
+			"M":       "M월",
+			"Md":      "M. d.",
+			"MMM":     "LLL",
+			"MMMd":    "MMM d일",
+			"MMMM":    "LLLL",
+			"MMMMd":   "MMMM d일",
+			"y":       "y년",
+			"yd":      "y년 (일: d일)",
+			"yM":      "y. M.",
+			"yMd":     "y. M. d.",
+			"yMMM":    "y년 MMM",
+			"yMMMd":   "y년 MMM d일",
+			"yMMMM":   "y년 MMMM",
+			"yMMMMd":  "y년 MMMM d일",
+			"E":       "EEE",
+			"Ed":      "d일 (EEE)",
+			"EM":      "M월 EEE",
+			"EMd":     "M. d. (EEE)",
+			"EMMM":    "LLL EEE",
+			"EMMMd":   "MMM d일 (EEE)",
+			"EMMMM":   "EEE (월: LLLL)",
+			"EMMMMd":  "MMMM d일 (EEE)",
+			"Ey":      "y년 EEE",
+			"Eyd":     "y년 d일 (EEE)",
+			"EyM":     "y. M. EEE",
+			"EyMd":    "y. M. d. (EEE)",
+			"EyMMM":   "y년 MMM EEE",
+			"EyMMMd":  "y년 MMM d일 (EEE)",
+			"EyMMMM":  "y년 MMMM EEE",
+			"EyMMMMd": "y년 MMMM d일 EEE",
+			"h":       "a h시",
+			"hz":      "a h시 z",
+			"hm":      "a h:mm",
+			"hmz":     "a h:mm z",
+			"hms":     "a h:mm:ss",
+			"hmsz":    "a h시 m분 s초 z",
+			"m":       "m",
+			"ms":      "mm:ss",
+			"s":       "s",
+			"H":       "H시",
+			"Hz":      "H시 z",
+			"Hm":      "HH:mm",
+			"Hmz":     "HH:mm z",
+			"Hms":     "H시 m분 s초",
+			"Hmsz":    "H시 m분 s초 z",
+		},
+	},
+	"nl": {
+		months:            [3][12]string{{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"}, {"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"}, {"J", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"}},
+		weekdays:          [3][7]string{{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"}, {"zo", "ma", "di", "wo", "do", "vr", "za"}, {"Z", "M", "D", "W", "D", "V", "Z"}},
+		dayPeriods:        [2]string{"a.m.", "p.m."},
+		hour12:            false,
+		gmt:               "GMT",
+		gmtMinus:          "-",
+		utcLong:           "gecoördineerde wereldtijd",
+		fractionSeparator: ",",
+		dateStyles:        [4]string{"EEEE d MMMM y", "d MMMM y", "d MMM y", "dd-MM-y"},
+		timeStyles:        [2][4]string{{"hh:mm:ss a zzzz", "hh:mm:ss a z", "hh:mm:ss a", "hh:mm a"}, {"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"}},
+		dateTimeStyles:    [4]string{"{1}' om '{0}", "{1}' om '{0}", "{1}, {0}", "{1}, {0}"},
+		skeletons: map[string]string{
+			"d":       "d",
+			"M":       "M",
+			"Md":      "d-M",
+			"MMM":     "LLL",
+			"MMMd":    "d MMM",
+			"MMMM":    "LLLL",
+			"MMMMd":   "d MMMM",
+			"y":       "y",
+			"yd":      "y' (dag: 'd)",
+			"yM":      "M-y",
+			"yMd":     "d-M-y",
+			"yMMM":    "MMM y",
+			"yMMMd":   "d MMM y",
+			"yMMMM":   "MMMM y",
+			"yMMMMd":  "d MMMM y",
+			"E":       "EEE",
+			"Ed":      "EEE d",
+			"EM":      "M EEE",
+			"EMd":     "EEE d-M",
+			"EMMM":    "LLL EEE",
+			"EMMMd":   "EEE d MMM",
+			"EMMMM":   "EEE' (maand: 'LLLL)",
+			"EMMMMd":  "EEE d MMMM",
+			"Ey":      "y EEE",
+			"Eyd":     "y EEE d",
+			"EyM":     "M-y EEE",
+			"EyMd":    "EEE d-M-y",
+			"EyMMM":   "MMM y EEE",
+			"EyMMMd":  "EEE d MMM y",
+			"EyMMMM":  "MMMM y EEE",
+			"EyMMMMd": "EEE d MMMM y",
+			"h":       "h a",
+			"hz":      "h a z",
+			"hm":      "h:mm a",
+			"hmz":     "h:mm a z",
+			"hms":     "h:mm:ss a",
+			"hmsz":    "h:mm:ss a z",
+			"m":       "m",
+			"ms":      "mm:ss",
+			"s":       "s",
+			"H":       "HH",
+			"Hz":      "HH z",
+			"Hm":      "HH:mm",
+			"Hmz":     "HH:mm z",
+			"Hms":     "HH:mm:ss",
+			"Hmsz":    "H:mm:ss z",
+		},
+	},
+	"pl": {
+		months:            [3][12]string{{"stycznia", "lutego", "marca", "kwietnia", "maja", "czerwca", "lipca", "sierpnia", "września", "października", "listopada", "grudnia"}, {"sty", "lut", "mar", "kwi", "maj", "cze", "lip", "sie", "wrz", "paź", "lis", "gru"}, {"s", "l", "m", "k", "m", "c", "l", "s", "w", "p", "l", "g"}},
+		standaloneMonths:  &[3][12]string{{"styczeń", "luty", "marzec", "kwiecień", "maj", "czerwiec", "lipiec", "sierpień", "wrzesień", "październik", "listopad", "grudzień"}, {"sty", "lut", "mar", "kwi", "maj", "cze", "lip", "sie", "wrz", "paź", "lis", "gru"}, {"S", "L", "M", "K", "M", "C", "L", "S", "W", "P", "L", "G"}},
+		weekdays:          [3][7]string{{"niedziela", "poniedziałek", "wtorek", "środa", "czwartek", "piątek", "sobota"}, {"niedz.", "pon.", "wt.", "śr.", "czw.", "pt.", "sob."}, {"N", "P", "W", "Ś", "C", "P", "S"}},
+		dayPeriods:        [2]string{"AM", "PM"},
+		hour12:            false,
+		gmt:               "GMT",
+		gmtMinus:          "-",
+		utcLong:           "uniwersalny czas koordynowany",
+		fractionSeparator: ",",
+		dateStyles:        [4]string{"EEEE, d MMMM y", "d MMMM y", "d MMM y", "d.MM.y"},
+		timeStyles:        [2][4]string{{"hh:mm:ss a zzzz", "hh:mm:ss a z", "hh:mm:ss a", "hh:mm a"}, {"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"}},
+		dateTimeStyles:    [4]string{"{1} {0}", "{1} {0}", "{1}, {0}", "{1}, {0}"},
+		skeletons: map[string]string{
+			"d":       "d",
+			"M":       "M",
+			"Md":      "d.MM",
+			"MMM":     "LLL",
+			"MMMd":    "d MMM",
+			"MMMM":    "LLLL",
+			"MMMMd":   "d MMMM",
+			"y":       "y",
+			"yd":      "y' (dzień: 'd)",
+			"yM":      "MM.y",
+			"yMd":     "d.MM.y",
+			"yMMM":    "MMM y",
+			"yMMMd":   "d MMM y",
+			"yMMMM":   "LLLL y",
+			"yMMMMd":  "d MMMM y",
+			"E":       "EEE",
+			"Ed":      "EEE, d",
+			"EM":      "M EEE",
+			"EMd":     "EEE, d.MM",
+			"EMMM":    "LLL EEE",
+			"EMMMd":   "EEE, d MMM",
+			"EMMMM":   "EEE' (miesiąc: 'LLLL)",
+			"EMMMMd":  "EEE, d MMMM",
+			"Ey":      "y EEE",
+			"Eyd":     "y EEE, d",
+			"EyM":     "MM.y EEE",
+			"EyMd":    "EEE, d.MM.y",
+			"EyMMM":   "MMM y EEE",
+			"EyMMMd":  "EEE, d MMM y",
+			"EyMMMM":  "LLLL y EEE",
+			"EyMMMMd": "EEE, d MMMM y",
+			"h":       "h a",
+			"hz":      "h a z",
+			"hm":      "h:mm a",
+			"hmz":     "h:mm a z",
+			"hms":     "h:mm:ss a",
+			"hmsz":    "h:mm:ss a z",
+			"m":       "m",
+			"ms":      "mm:ss",
+			"s":       "s",
+			"H":       "HH",
+			"Hz":      "HH z",
+			"Hm":      "HH:mm",
+			"Hmz":     "HH:mm z",
+			"Hms":     "HH:mm:ss",
+			"Hmsz":    "H:mm:ss z",
+		},
+	},
+	"pt": {
+		months:            [3][12]string{{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"}, {"jan.", "fev.", "mar.", "abr.", "mai.", "jun.", "jul.", "ago.", "set.", "out.", "nov.", "dez."}, {"J", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"}},
+		weekdays:          [3][7]string{{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"}, {"dom.", "seg.", "ter.", "qua.", "qui.", "sex.", "sáb."}, {"D", "S", "T", "Q", "Q", "S", "S"}},
+		dayPeriods:        [2]string{"AM", "PM"},
+		hour12:            false,
+		gmt:               "GMT",
+		gmtMinus:          "-",
+		utcLong:           "Horário Universal Coordenado",
+		fractionSeparator: ",",
+		dateStyles:        [4]string{"EEEE, d' de 'MMMM' de 'y", "d' de 'MMMM' de 'y", "d' de 'MMM' de 'y", "dd/MM/y"},
+		timeStyles:        [2][4]string{{"hh:mm:ss a zzzz", "hh:mm:ss a z", "hh:mm:ss a", "hh:mm a"}, {"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"}},
+		dateTimeStyles:    [4]string{"{1}' às '{0}", "{1}' às '{0}", "{1}, {0}", "{1}, {0}"},
+		skeletons: map[string]string{
+			"d":       "d",
+			"M":       "M",
+			"Md":      "dd/MM",
+			"MMM":     "LLL",
+			"MMMd":    "d' de 'MMM",
+			"MMMM":    "LLLL",
+			"MMMMd":   "d' de 'MMMM",
+			"y":       "y",
+			"yd":      "y' (dia: 'd)",
+			"yM":      "MM/y",
+			"yMd":     "dd/MM/y",
+			"yMMM":    "MMM' de 'y",
+			"yMMMd":   "d' de 'MMM' de 'y",
+			"yMMMM":   "MMMM' de 'y",
+			"yMMMMd":  "d' de 'MMMM' de 'y",
+			"E":       "EEE",
+			"Ed":      "EEE, d",
+			"EM":      "M EEE",
+			"EMd":     "EEE, dd/MM",
+			"EMMM":    "LLL EEE",
+			"EMMMd":   "EEE, d' de 'MMM",
+			"EMMMM":   "EEE' (mês: 'LLLL)",
+			"EMMMMd":  "EEE, d' de 'MMMM",
+			"Ey":      "y EEE",
+			"Eyd":     "y EEE, d",
+			"EyM":     "MM/y EEE",
+			"EyMd":    "EEE, dd/MM/y",
+			"EyMMM":   "MMM' de 'y EEE",
+			"EyMMMd":  "EEE, d' de 'MMM' de 'y",
+			"EyMMMM":  "MMMM' de 'y EEE",
+			"EyMMMMd": "EEE, d' de 'MMMM' de 'y",
+			"h":       "h a",
+			"hz":      "h a z",
+			"hm":      "h:mm a",
+			"hmz":     "h:mm a z",
+			"hms":     "h:mm:ss a",
+			"hmsz":    "h:mm:ss a z",
+			"m":       "m",
+			"ms":      "mm:ss",
+			"s":       "s",
+			"H":       "HH",
+			"Hz":      "HH z",
+			"Hm":      "HH:mm",
+			"Hmz":     "HH:mm z",
+			"Hms":     "HH:mm:ss",
+			"Hmsz":    "H:mm:ss z",
+		},
+	},
+	"ru": {
+		months:            [3][12]string{{"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"}, {"янв.", "февр.", "мар.", "апр.", "мая", "июн.", "июл.", "авг.", "сент.", "окт.", "нояб.", "дек."}, {"Я", "Ф", "М", "А", "М", "И", "И", "А", "С", "О", "Н", "Д"}},
+		standaloneMonths:  &[3][12]string{{"январь", "февраль", "март", "апрель", "май", "июнь", "июль", "август", "сентябрь", "октябрь", "ноябрь", "декабрь"}, {"янв.", "февр.", "март", "апр.", "май", "июнь", "июль", "авг.", "сент.", "окт.", "нояб.", "дек."}, {"Я", "Ф", "М", "А", "М", "И", "И", "А", "С", "О", "Н", "Д"}},
+		weekdays:          [3][7]string{{"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"}, {"вс", "пн", "вт", "ср", "чт", "пт", "сб"}, {"В", "П", "В", "С", "Ч", "П", "С"}},
+		dayPeriods:        [2]string{"AM", "PM"},
+		hour12:            false,
+		gmt:               "GMT",
+		gmtMinus:          "-",
+		utcLong:           "Всемирное координированное время",
+		fractionSeparator: ",",
+		dateStyles:        [4]string{"EEEE, d MMMM y г.", "d MMMM y г.", "d MMM y г.", "dd.MM.y"},
+		timeStyles:        [2][4]string{{"hh:mm:ss a zzzz", "hh:mm:ss a z", "hh:mm:ss a", "hh:mm a"}, {"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"}},
+		dateTimeStyles:    [4]string{"{1} в {0}", "{1} в {0}", "{1}, {0}", "{1}, {0}"},
+		skeletons: map[string]string{
+			"d":       "d",
+			"M":       "M",
+			"Md":      "dd.MM",
+			"MMM":     "LLL",
+			"MMMd":    "d MMM",
+			"MMMM":    "LLLL",
+			"MMMMd":   "d MMMM",
+			"y":       "y",
+			"yd":      "y (день: d)",
+			"yM":      "MM.y",
+			"yMd":     "dd.MM.y",
+			"yMMM":    "MMM y г.",
+			"yMMMd":   "d MMM y г.",
+			"yMMMM":   "LLLL y г.",
+			"yMMMMd":  "d MMMM y г.",
+			"E":       "EEE",
+			"Ed":      "EEE, d",
+			"EM":      "M EEE",
+			"EMd":     "EEE, dd.MM",
+			"EMMM":    "LLL EEE",
+			"EMMMd":   "EEE, d MMM",
+			"EMMMM":   "EEE (месяц: LLLL)",
+			"EMMMMd":  "EEE, d MMMM",
+			"Ey":      "y EEE",
+			"Eyd":     "y EEE, d",
+			"EyM":     "MM.y EEE",
+			"EyMd":    "EEE, dd.MM.y г.",
+			"EyMMM":   "MMM y г. EEE",
+			"EyMMMd":  "EEE, d MMM y г.",
+			"EyMMMM":  "LLLL y г. EEE",
+			"EyMMMMd": "EEE, d MMMM y г.",
+			"h":       "h a",
+			"hz":      "h a z",
+			"hm":      "h:mm a",
+			"hmz":     "h:mm a z",
+			"hms":     "h:mm:ss a",
+			"hmsz":    "h:mm:ss a z",
+			"m":       "m",
+			"ms":      "mm:ss",
+			"s":       "s",
+			"H":       "HH",
+			"Hz":      "HH z",
+			"Hm":      "HH:mm",
+			"Hmz":     "HH:mm z",
+			"Hms":     "HH:mm:ss",
+			"Hmsz":    "H:mm:ss z",
+		},
+	},
+	"sv": {
+		months:            [3][12]string{{"januari", "februari", "mars", "april", "maj", "juni", "juli", "augusti", "september", "oktober", "november", "december"}, {"jan.", "feb.", "mars", "apr.", "maj", "juni", "juli", "aug.", "sep.", "okt.", "nov.", "dec."}, {"J", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"}},
+		weekdays:          [3][7]string{{"söndag", "måndag", "tisdag", "onsdag", "torsdag", "fredag", "lördag"}, {"sön", "mån", "tis", "ons", "tors", "fre", "lör"}, {"S", "M", "T", "O", "T", "F", "L"}},
+		dayPeriods:        [2]string{"fm", "em"},
+		hour12:            false,
+		gmt:               "GMT",
+		gmtMinus:          "−",
+		utcLong:           "koordinerad universell tid",
+		fractionSeparator: ",",
+		dateStyles:        [4]string{"EEEE d MMMM y", "d MMMM y", "d MMM y", "y-MM-dd"},
+		timeStyles:        [2][4]string{{"hh:mm:ss a zzzz", "hh:mm:ss a z", "hh:mm:ss a", "hh:mm a"}, {"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"}},
+		dateTimeStyles:    [4]string{"{1}' kl. '{0}", "{1}' kl. '{0}", "{1} {0}", "{1} {0}"},
+		skeletons: map[string]string{
+			"d":       "d",
+			"M":       "M",
+			"Md":      "d/M",
+			"MMM":     "LLL",
+			"MMMd":    "d MMM",
+			"MMMM":    "LLLL",
+			"MMMMd":   "d MMMM",
+			"y":       "y",
+			"yd":      "y' (dag: 'd)",
+			"yM":      "y-MM",
+			"yMd":     "y-MM-dd",
+			"yMMM":    "MMM y",
+			"yMMMd":   "d MMM y",
+			"yMMMM":   "MMMM y",
+			"yMMMMd":  "d MMMM y",
+			"E":       "EEE",
+			"Ed":      "EEE d",
+			"EM":      "M EEE",
+			"EMd":     "EEE d/M",
+			"EMMM":    "LLL EEE",
+			"EMMMd":   "EEE d MMM",
+			"EMMMM":   "EEE' (månad: 'LLLL)",
+			"EMMMMd":  "EEE d MMMM",
+			"Ey":      "y EEE",
+			"Eyd":     "y EEE d",
+			"EyM":     "y-MM EEE",
+			"EyMd":    "EEE, y-MM-dd",
+			"EyMMM":   "MMM y EEE",
+			"EyMMMd":  "EEE d MMM y",
+			"EyMMMM":  "MMMM y EEE",
+			"EyMMMMd": "EEE d MMMM y",
+			"h":       "h a",
+			"hz":      "h a z",
+			"hm":      "h:mm a",
+			"hmz":     "h:mm a z",
+			"hms":     "h:mm:ss a",
+			"hmsz":    "h:mm:ss a z",
+			"m":       "m",
+			"ms":      "mm:ss",
+			"s":       "s",
+			"H":       "HH",
+			"Hz":      "HH z",
+			"Hm":      "HH:mm",
+			"Hmz":     "HH:mm z",
+			"Hms":     "HH:mm:ss",
+			"Hmsz":    "H:mm:ss z",
+		},
+	},
+	"tr": {
+		months:            [3][12]string{{"Ocak", "Şubat", "Mart", "Nisan", "Mayıs", "Haziran", "Temmuz", "Ağustos", "Eylül", "Ekim", "Kasım", "Aralık"}, {"Oca", "Şub", "Mar", "Nis", "May", "Haz", "Tem", "Ağu", "Eyl", "Eki", "Kas", "Ara"}, {"O", "Ş", "M", "N", "M", "H", "T", "A", "E", "E", "K", "A"}},
+		weekdays:          [3][7]string{{"Pazar", "Pazartesi", "Salı", "Çarşamba", "Perşembe", "Cuma", "Cumartesi"}, {"Paz", "Pzt", "Sal", "Çar", "Per", "Cum", "Cmt"}, {"P", "P", "S", "Ç", "P", "C", "C"}},
+		dayPeriods:        [2]string{"ÖÖ", "ÖS"},
+		hour12:            false,
+		gmt:               "GMT",
+		gmtMinus:          "-",
+		utcLong:           "Eş Güdümlü Evrensel Zaman",
+		fractionSeparator: ",",
+		dateStyles:        [4]string{"d MMMM y EEEE", "d MMMM y", "d MMM y", "d.MM.y"},
+		timeStyles:        [2][4]string{{"a hh:mm:ss zzzz", "a hh:mm:ss z", "a hh:mm:ss", "a hh:mm"}, {"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"}},
+		dateTimeStyles:    [4]string{"{1} {0}", "{1} {0}", "{1} {0}", "{1} {0}"},
+		skeletons: map[string]string{
+			"d":       "d",
+			"M":       "M",
+			"Md":      "d/M",
+			"MMM":     "LLL",
+			"MMMd":    "d MMM",
+			"MMMM":    "LLLL",
+			"MMMMd":   "d MMMM",
+			"y":       "y",
+			"yd":      "y' (gün: 'd)",
+			"yM":      "MM/y",
+			"yMd":     "dd.MM.y",
+			"yMMM":    "MMM y",
+			"yMMMd":   "d MMM y",
+			"yMMMM":   "MMMM y",
+			"yMMMMd":  "d MMMM y",
+			"E":       "EEE",
+			"Ed":      "d EEE",
+			"EM":      "M EEE",
+			"EMd":     "d/MM EEE",
+			"EMMM":    "LLL EEE",
+			"EMMMd":   "d MMM EEE",
+			"EMMMM":   "EEE' (ay: 'LLLL)",
+			"EMMMMd":  "d MMMM EEE",
+			"Ey":      "y EEE",
+			"Eyd":     "y d EEE",
+			"EyM":     "MM/y EEE",
+			"EyMd":    "d.M.y EEE",
+			"EyMMM":   "MMM y EEE",
+			"EyMMMd":  "d MMM y EEE",
+			"EyMMMM":  "MMMM y EEE",
+			"EyMMMMd": "d MMMM y EEE",
+			"h":       "a h",
+			"hz":      "a h z",
+			"hm":      "a h:mm",
+			"hmz":     "a h:mm z",
+			"hms":     "a h:mm:ss",
+			"hmsz":    "a h:mm:ss z",
+			"m":       "m",
+			"ms":      "mm:ss",
+			"s":       "s",
+			"H":       "HH",
+			"Hz":      "HH z",
+			"Hm":      "HH:mm",
+			"Hmz":     "HH:mm z",
+			"Hms":     "HH:mm:ss",
+			"Hmsz":    "H:mm:ss z",
+		},
+	},
+	"zh": {
+		months:            [3][12]string{{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}, {"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}, {"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}},
+		standaloneMonths:  &[3][12]string{{"一月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"}, {"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"}, {"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}},
+		weekdays:          [3][7]string{{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"}, {"周日", "周一", "周二", "周三", "周四", "周五", "周六"}, {"日", "一", "二", "三", "四", "五", "六"}},
+		dayPeriods:        [2]string{"上午", "下午"},
+		hour12:            false,
+		gmt:               "GMT",
+		gmtMinus:          "-",
+		utcLong:           "协调世界时",
+		fractionSeparator: ".",
+		dateStyles:        [4]string{"y年M月d日EEEE", "y年M月d日", "y年M月d日", "y/M/d"},
+		timeStyles:        [2][4]string{{"zzzz ahh:mm:ss", "z ahh:mm:ss", "ahh:mm:ss", "ahh:mm"}, {"zzzz HH:mm:ss", "z HH:mm:ss", "HH:mm:ss", "HH:mm"}},
+		dateTimeStyles:    [4]string{"{1} {0}", "{1} {0}", "{1} {0}", "{1} {0}"},
+		skeletons: map[string]string{
+			"d":       "d日",
+			"M":       "M月",
+			"Md":      "M/d",
+			"MMM":     "LLL",
+			"MMMd":    "M月d日",
+			"MMMM":    "LLLL",
+			"MMMMd":   "M月d日",
+			"y":       "y年",
+			"yd":      "y年 (日: d日)",
+			"yM":      "y/M",
+			"yMd":     "y/M/d",
+			"yMMM":    "y年M月",
+			"yMMMd":   "y年M月d日",
+			"yMMMM":   "y年M月",
+			"yMMMMd":  "y年M月d日",
+			"E":       "EEE",
+			"Ed":      "d日EEE",
+			"EM":      "M月 EEE",
+			"EMd":     "M/dEEE",
+			"EMMM":    "LLL EEE",
+			"EMMMd":   "M月d日EEE",
+			"EMMMM":   "EEE (月: LLLL)",
+			"EMMMMd":  "M月d日EEE",
+			"Ey":      "y年 EEE",
+			"Eyd":     "y年 d日EEE",
+			"EyM":     "y/M EEE",
+			"EyMd":    "y/M/dEEE",
+			"EyMMM":   "y年M月 EEE",
+			"EyMMMd":  "y年M月d日EEE",
+			"EyMMMM":  "y年M月 EEE",
+			"EyMMMMd": "y年M月d日EEE",
+			"h":       "ah时",
+			"hz":      "zah时",
+			"hm":      "ah:mm",
+			"hmz":     "z ah:mm",
+			"hms":     "ah:mm:ss",
+			"hmsz":    "z ah:mm:ss",
+			"m":       "m",
+			"ms":      "mm:ss",
+			"s":       "s",
+			"H":       "H时",
+			"Hz":      "zH时",
+			"Hm":      "HH:mm",
+			"Hmz":     "z HH:mm",
+			"Hms":     "HH:mm:ss",
+			"Hmsz":    "z H:mm:ss",
+		},
+	},
+}
+
+// intlCurrencyPatterns holds the standard, the negative and the accounting negative currency patterns,
+// '#' is the number, '¤' is the currency symbol and '-' is the sign.
+var intlCurrencyPatterns = map[string][3]string{
+	"af":  {"¤#", "-¤#", "(¤#)"},
+	"ar":  {"\u200f#\u00a0¤", "\u200f\u200e-#\u00a0¤", "(\u061c#¤)"},
+	"az":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"be":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"bg":  {"#\u00a0¤", "-#\u00a0¤", "(#\u00a0¤)"},
+	"bn":  {"#¤", "-#¤", "(#¤)"},
+	"bs":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"ca":  {"#\u00a0¤", "-#\u00a0¤", "(#\u00a0¤)"},
+	"cs":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"cy":  {"¤#", "-¤#", "(¤#)"},
+	"da":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"de":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"el":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"en":  {"¤#", "-¤#", "(¤#)"},
+	"es":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"et":  {"#\u00a0¤", "-#\u00a0¤", "(#\u00a0¤)"},
+	"eu":  {"#\u00a0¤", "-#\u00a0¤", "(#\u00a0¤)"},
+	"fa":  {"\u200e¤\u00a0#", "\u200e-\u200e¤\u00a0#", "\u200e(¤\u00a0#)"},
+	"fi":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"fil": {"¤#", "-¤#", "(¤#)"},
+	"fr":  {"#\u00a0¤", "-#\u00a0¤", "(#\u00a0¤)"},
+	"ga":  {"¤#", "-¤#", "(¤#)"},
+	"gl":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"gu":  {"¤#", "-¤#", "(¤#)"},
+	"he":  {"\u200f#\u00a0\u200f¤", "\u200f\u200e-#\u00a0\u200f¤", "\u200f\u200e-#\u00a0\u200f¤"},
+	"hi":  {"¤#", "-¤#", "-¤#"},
+	"hr":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"hu":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"hy":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"id":  {"¤#", "-¤#", "-¤#"},
+	"is":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"it":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"ja":  {"¤#", "-¤#", "(¤#)"},
+	"ka":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"kk":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"km":  {"#¤", "-#¤", "(#¤)"},
+	"kn":  {"¤#", "-¤#", "(¤#)"},
+	"ko":  {"¤#", "-¤#", "(¤#)"},
+	"lt":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"lv":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"mk":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"ml":  {"¤#", "-¤#", "(¤#)"},
+	"mn":  {"¤\u00a0#", "-¤\u00a0#", "-¤\u00a0#"},
+	"mr":  {"¤#", "-¤#", "(¤#)"},
+	"ms":  {"¤#", "-¤#", "(¤#)"},
+	"my":  {"#\u00a0¤", "-#\u00a0¤", "-¤\u00a0#"},
+	"nb":  {"#\u00a0¤", "-#\u00a0¤", "(¤\u00a0#)"},
+	"ne":  {"¤\u00a0#", "-¤\u00a0#", "-¤\u00a0#"},
+	"nl":  {"¤\u00a0#", "¤\u00a0-#", "(¤\u00a0#)"},
+	"pa":  {"¤#", "-¤#", "-¤\u00a0#"},
+	"pl":  {"#\u00a0¤", "-#\u00a0¤", "(#\u00a0¤)"},
+	"pt":  {"¤\u00a0#", "-¤\u00a0#", "-¤\u00a0#"},
+	"ro":  {"#\u00a0¤", "-#\u00a0¤", "(#\u00a0¤)"},
+	"ru":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"si":  {"¤#", "-¤#", "(¤#)"},
+	"sk":  {"#\u00a0¤", "-#\u00a0¤", "(#\u00a0¤)"},
+	"sl":  {"#\u00a0¤", "-#\u00a0¤", "(#\u00a0¤)"},
+	"sq":  {"#\u00a0¤", "-#\u00a0¤", "(#\u00a0¤)"},
+	"sr":  {"#\u00a0¤", "-#\u00a0¤", "(#\u00a0¤)"},
+	"sv":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"sw":  {"¤\u00a0#", "-¤\u00a0#", "-¤\u00a0#"},
+	"ta":  {"¤#", "-¤#", "(¤#)"},
+	"te":  {"¤#", "-¤#", "(¤#)"},
+	"th":  {"¤#", "-¤#", "(¤#)"},
+	"tr":  {"¤#", "-¤#", "(¤#)"},
+	"uk":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"ur":  {"¤#", "\u200e-¤#", "(¤#)"},
+	"uz":  {"#\u00a0¤", "-#\u00a0¤", "(¤#)"},
+	"vi":  {"#\u00a0¤", "-#\u00a0¤", "-#\u00a0¤"},
+	"zh":  {"¤#", "-¤#", "(¤#)"},
+	"zu":  {"¤#", "-¤#", "(¤#)"},
+}
@@ -0,0 +1,986 @@
+package sobek
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/sobek/unistring"
+)
+
+type intlDateLocale struct {
+	// long, short and narrow names
+	months           [3][12]string
+	standaloneMonths *[3][12]string
+	weekdays         [3][7]string
+	dayPeriods       [2]string
+
+	// whether the locale uses the 12-hour clock by default
+	hour12 bool
+
+	gmt, gmtMinus     string
+	utcLong           string
+	fractionSeparator string
+
+	// the patterns for the full, long, medium and short styles
+	dateStyles [4]string
+	// the time style patterns for the 12-hour and the 24-hour clock
+	timeStyles [2][4]string
+	// the patterns used to join the date and the time, {1} is replaced by the date and {0} by the time
+	dateTimeStyles [4]string
+
+	// the patterns for the combinations of the components, the keys use the CLDR skeleton format
+	skeletons map[string]string
+}
+
+func getIntlDateLocale(locale string) *intlDateLocale {
+	for {
+		if data := intlDateLocales[locale]; data != nil {
+			return data
+		}
+		pos := strings.LastIndexByte(locale, '-')
+		if pos < 0 {
+			return intlDateLocales["en"]
+		}
+		locale = locale[:pos]
+	}
+}
+
+// hourCycle12 returns the hour cycle used by the locale for the 12-hour clock.
+func (d *intlDateLocale) hourCycle12() string {
+	if strings.IndexByte(d.timeStyles[0][3], 'K') >= 0 {
+		return "h11"
+	}
+	return "h12"
+}
+
+// intlDateToken is either a date field (e.g. "yyyy") or a literal text if field is 0.
+type intlDateToken struct {
+	field   byte
+	count   int
+	literal string
+}
+
+func isIntlPatternLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// parseIntlDatePattern splits a CLDR date pattern into tokens. The text in quotes is a literal
+// and two single quotes represent a single quote.
+func parseIntlDatePattern(pattern string) []intlDateToken {
+	var tokens []intlDateToken
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			tokens = append(tokens, intlDateToken{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+	quoted := false
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		switch {
+		case c == '\'':
+			if i+1 < len(pattern) && pattern[i+1] == '\'' {
+				literal.WriteByte('\'')
+				i += 2
+				continue
+			}
+			quoted = !quoted
+			i++
+		case !quoted && isIntlPatternLetter(c):
+			flush()
+			j := i + 1
+			for j < len(pattern) && pattern[j] == c {
+				j++
+			}
+			tokens = append(tokens, intlDateToken{field: c, count: j - i})
+			i = j
+		default:
+			literal.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+	return tokens
+}
+
+var intlDateComponents = [...]string{"weekday", "era", "year", "month", "day", "dayPeriod", "hour", "minute", "second", "fractionalSecondDigits", "timeZoneName"}
+
+var intlDateComponentValues = map[string][]string{
+	"weekday":      {"narrow", "short", "long"},
+	"era":          {"narrow", "short", "long"},
+	"year":         {"2-digit", "numeric"},
+	"month":        {"2-digit", "numeric", "narrow", "short", "long"},
+	"day":          {"2-digit", "numeric"},
+	"dayPeriod":    {"narrow", "short", "long"},
+	"hour":         {"2-digit", "numeric"},
+	"minute":       {"2-digit", "numeric"},
+	"second":       {"2-digit", "numeric"},
+	"timeZoneName": {"short", "long", "shortOffset", "longOffset", "shortGeneric", "longGeneric"},
+}
+
+var intlDateStyles = []string{"full", "long", "medium", "short"}
+
+func intlDateStyleIndex(style string) int {
+	for i, s := range intlDateStyles {
+		if s == style {
+			return i
+		}
+	}
+	return -1
+}
+
+type intlDateTimeFormat struct {
+	locale          string
+	calendar        string
+	numberingSystem string
+	zero            rune
+	timeZone        string
+	loc             *time.Location
+	hourCycle       string
+	dateStyle       string
+	timeStyle       string
+
+	data   *intlDateLocale
+	tokens []intlDateToken
+}
+
+func isIntlHourCycle(s string) bool {
+	return s == "h11" || s == "h12" || s == "h23" || s == "h24"
+}
+
+func isIntlCalendar(s string) bool {
+	return s == "gregory"
+}
+
+// intlDefaultTimeZone returns the name and the location of the Runtime's local time zone.
+func (r *Runtime) intlDefaultTimeZone() (string, *time.Location) {
+	loc := r.location()
+	if name := loc.String(); name != "Local" {
+		if id, l, ok := intlTimeZone(name); ok {
+			return id, l
+		}
+		return name, loc
+	}
+	if tz := os.Getenv("TZ"); tz != "" {
+		return strings.TrimPrefix(tz, ":"), loc
+	}
+	if target, err := os.Readlink("/etc/localtime"); err == nil {
+		if idx := strings.Index(target, "zoneinfo/"); idx >= 0 {
+			return target[idx+len("zoneinfo/"):], loc
+		}
+		return filepath.Base(target), loc
+	}
+	return "UTC", loc
+}
+
+// intlTimeZone validates and canonicalizes a time zone name. Besides the IANA names, the UTC offsets
+// in the "+hh:mm" format are supported.
+func intlTimeZone(name string) (string, *time.Location, bool) {
+	switch strings.ToUpper(name) {
+	case "UTC", "ETC/UTC", "GMT", "ETC/GMT", "ETC/UCT", "UCT", "ETC/ZULU", "ZULU", "ETC/UNIVERSAL", "UNIVERSAL", "ETC/GREENWICH", "GREENWICH":
+		return "UTC", time.UTC, true
+	}
+	if len(name) > 0 && (name[0] == '+' || name[0] == '-') {
+		offset, ok := parseIntlOffset(name[1:])
+		if !ok {
+			return "", nil, false
+		}
+		if name[0] == '-' {
+			offset = -offset
+		}
+		if offset == 0 {
+			return "+00:00", time.FixedZone("+00:00", 0), true
+		}
+		canonical := name[:1] + formatIntlOffset(offset)
+		return canonical, time.FixedZone(canonical, offset), true
+	}
+	if strings.Contains(name, "..") || strings.HasPrefix(name, "/") {
+		return "", nil, false
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" || name == "" {
+		return "", nil, false
+	}
+	return name, loc, true
+}
+
+// parseIntlOffset parses the "hh:mm" or "hhmm" offset, the result is in seconds.
+func parseIntlOffset(s string) (int, bool) {
+	s = strings.Replace(s, ":", "", 1)
+	if len(s) != 2 && len(s) != 4 {
+		return 0, false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+	}
+	h, _ := strconv.Atoi(s[:2])
+	m := 0
+	if len(s) == 4 {
+		m, _ = strconv.Atoi(s[2:])
+	}
+	if h > 23 || m > 59 {
+		return 0, false
+	}
+	return (h*60 + m) * 60, true
+}
+
+// formatIntlOffset formats the absolute value of the offset as "hh:mm".
+func formatIntlOffset(offset int) string {
+	if offset < 0 {
+		offset = -offset
+	}
+	offset /= 60
+	return twoDigits(offset/60) + ":" + twoDigits(offset%60)
+}
+
+func twoDigits(n int) string {
+	if n < 10 {
+		return "0" + strconv.Itoa(n)
+	}
+	return strconv.Itoa(n)
+}
+
+// newIntlDateTimeFormat implements CreateDateTimeFormat. The required argument is one of "any", "date"
+// and "time", defaults is one of "date", "time" and "all".
+func (r *Runtime) newIntlDateTimeFormat(locales, opts Value, required, defaults string) *intlDateTimeFormat {
+	requested := r.intlLocaleList(locales)
+	options := r.intlCoerceOptions(opts)
+	r.intlGetLocaleMatcher(options)
+	ca := r.intlGetStringOption(options, "calendar", nil, "")
+	if ca != "" && !isIntlTypeSequence(ca) {
+		panic(r.newErrorf(r.getRangeError(), "Invalid calendar : %s", ca))
+	}
+	nu := r.intlGetStringOption(options, "numberingSystem", nil, "")
+	if nu != "" && !isIntlTypeSequence(nu) {
+		panic(r.newErrorf(r.getRangeError(), "Invalid numberingSystem : %s", nu))
+	}
+	hour12, hasHour12 := r.intlGetBoolOption(options, "hour12")
+	hc := r.intlGetStringOption(options, "hourCycle", []string{"h11", "h12", "h23", "h24"}, "")
+
+	locale := r.intlResolveLocale(requested)
+	dtf := &intlDateTimeFormat{
+		data: getIntlDateLocale(locale.base),
+	}
+	var caFromExt, nuFromExt, hcFromExt bool
+	dtf.calendar, caFromExt = intlResolveKeyword(locale.keyword("ca"), ca, isIntlCalendar, "gregory")
+	dtf.numberingSystem, nuFromExt = intlResolveKeyword(locale.keyword("nu"), nu, isIntlNumberingSystem, intlNumberingSystemName(getIntlNumberSymbols(&locale).zero))
+	dtf.zero = intlNumberingSystems[dtf.numberingSystem]
+	if hasHour12 {
+		if hour12 {
+			hc = dtf.data.hourCycle12()
+		} else {
+			hc = "h23"
+		}
+	} else {
+		hc, hcFromExt = intlResolveKeyword(locale.keyword("hc"), hc, isIntlHourCycle, "")
+	}
+	if hc == "" {
+		if dtf.data.hour12 {
+			hc = dtf.data.hourCycle12()
+		} else {
+			hc = "h23"
+		}
+	}
+	keywords := make([]string, 0, 6)
+	if caFromExt {
+		keywords = append(keywords, "ca", dtf.calendar)
+	}
+	if hcFromExt {
+		keywords = append(keywords, "hc", hc)
+	}
+	if nuFromExt {
+		keywords = append(keywords, "nu", dtf.numberingSystem)
+	}
+	dtf.locale = locale.String(keywords...)
+
+	if tz := intlGet(options, "timeZone"); tz != _undefined {
+		name := tz.toString().String()
+		var ok bool
+		dtf.timeZone, dtf.loc, ok = intlTimeZone(name)
+		if !ok {
+			panic(r.newErrorf(r.getRangeError(), "Invalid time zone specified: %s", name))
+		}
+	} else {
+		dtf.timeZone, dtf.loc = r.intlDefaultTimeZone()
+	}
+
+	components := make(map[string]string, len(intlDateComponents))
+	fractionalSecondDigits := 0
+	for _, name := range intlDateComponents {
+		if name == "fractionalSecondDigits" {
+			fractionalSecondDigits, _ = r.intlGetNumberOption(options, "fractionalSecondDigits", 1, 3, 0)
+			continue
+		}
+		if v := r.intlGetStringOption(options, unistring.NewFromString(name), intlDateComponentValues[name], ""); v != "" {
+			if name == "era" || name == "dayPeriod" {
+				// the locale data has no era names and no flexible day periods
+				r.intlUnsupportedOption(name, v)
+			}
+			components[name] = v
+		}
+	}
+	r.intlGetStringOption(options, "formatMatcher", []string{"basic", "best fit"}, "best fit")
+	dtf.dateStyle = r.intlGetStringOption(options, "dateStyle", intlDateStyles, "")
+	dtf.timeStyle = r.intlGetStringOption(options, "timeStyle", intlDateStyles, "")
+
+	var pattern string
+	if dtf.dateStyle != "" || dtf.timeStyle != "" {
+		if len(components) > 0 || fractionalSecondDigits > 0 {
+			panic(r.NewTypeError("Can't set option %s when dateStyle or timeStyle is used", intlFirstComponent(components, fractionalSecondDigits)))
+		}
+		if required == "date" && dtf.timeStyle != "" {
+			panic(r.NewTypeError("Invalid option : timeStyle"))
+		}
+		if required == "time" && dtf.dateStyle != "" {
+			panic(r.NewTypeError("Invalid option : dateStyle"))
+		}
+		pattern = dtf.stylePattern(hc)
+	} else {
+		needDefaults := true
+		if required == "date" || required == "any" {
+			for _, name := range []string{"weekday", "year", "month", "day"} {
+				if components[name] != "" {
+					needDefaults = false
+				}
+			}
+		}
+		if required == "time" || required == "any" {
+			for _, name := range []string{"dayPeriod", "hour", "minute", "second"} {
+				if components[name] != "" {
+					needDefaults = false
+				}
+			}
+			if fractionalSecondDigits > 0 {
+				needDefaults = false
+			}
+		}
+		if needDefaults && (defaults == "date" || defaults == "all") {
+			components["year"], components["month"], components["day"] = "numeric", "numeric", "numeric"
+		}
+		if needDefaults && (defaults == "time" || defaults == "all") {
+			components["hour"], components["minute"], components["second"] = "numeric", "numeric", "numeric"
+		}
+		pattern = dtf.componentsPattern(components, fractionalSecondDigits, hc)
+	}
+	dtf.tokens = parseIntlDatePattern(pattern)
+	dtf.hourCycle = hc
+	return dtf
+}
+
+func intlFirstComponent(components map[string]string, fractionalSecondDigits int) string {
+	for _, name := range intlDateComponents {
+		if components[name] != "" || name == "fractionalSecondDigits" && fractionalSecondDigits > 0 {
+			return name
+		}
+	}
+	return ""
+}
+
+// intlHourLetter returns the pattern letter of the hour field for the hour cycle.
+func intlHourLetter(hc string) byte {
+	switch hc {
+	case "h11":
+		return 'K'
+	case "h12":
+		return 'h'
+	case "h24":
+		return 'k'
+	}
+	return 'H'
+}
+
+// setIntlHourCycle replaces the hour fields of the pattern with the ones matching the hour cycle.
+func setIntlHourCycle(pattern string, hc string) string {
+	letter := intlHourLetter(hc)
+	b := []byte(pattern)
+	quoted := false
+	for i, c := range b {
+		switch {
+		case c == '\'':
+			quoted = !quoted
+		case !quoted && (c == 'h' || c == 'H' || c == 'K' || c == 'k'):
+			b[i] = letter
+		}
+	}
+	return string(b)
+}
+
+func (dtf *intlDateTimeFormat) timeGroup(hc string) int {
+	if hc == "h11" || hc == "h12" {
+		return 0
+	}
+	return 1
+}
+
+func (dtf *intlDateTimeFormat) stylePattern(hc string) string {
+	var datePattern, timePattern string
+	dateIdx := intlDateStyleIndex(dtf.dateStyle)
+	if dateIdx >= 0 {
+		datePattern = dtf.data.dateStyles[dateIdx]
+	}
+	if timeIdx := intlDateStyleIndex(dtf.timeStyle); timeIdx >= 0 {
+		timePattern = setIntlHourCycle(dtf.data.timeStyles[dtf.timeGroup(hc)][timeIdx], hc)
+	}
+	if datePattern == "" {
+		return timePattern
+	}
+	if timePattern == "" {
+		return datePattern
+	}
+	return dtf.joinPatterns(dateIdx, datePattern, timePattern)
+}
+
+func (dtf *intlDateTimeFormat) joinPatterns(idx int, datePattern, timePattern string) string {
+	glue := dtf.data.dateTimeStyles[idx]
+	return strings.Replace(strings.Replace(glue, "{1}", datePattern, 1), "{0}", timePattern, 1)
+}
+
+// componentsPattern finds the pattern for the requested components and adjusts the widths of its fields.
+func (dtf *intlDateTimeFormat) componentsPattern(components map[string]string, fractionalSecondDigits int, hc string) string {
+	var dateKey string
+	if components["weekday"] != "" {
+		dateKey += "E"
+	}
+	if components["year"] != "" {
+		dateKey += "y"
+	}
+	month := components["month"]
+	switch month {
+	case "numeric", "2-digit":
+		dateKey += "M"
+	case "short", "narrow":
+		dateKey += "MMM"
+	case "long":
+		dateKey += "MMMM"
+	}
+	if components["day"] != "" {
+		dateKey += "d"
+	}
+
+	var timeKey string
+	hour, minute, second := components["hour"], components["minute"], components["second"]
+	if hour != "" {
+		if hc == "h11" || hc == "h12" {
+			timeKey += "h"
+		} else {
+			timeKey += "H"
+		}
+	}
+	if minute != "" || hour != "" && second != "" {
+		timeKey += "m"
+	}
+	if second != "" {
+		timeKey += "s"
+	}
+
+	var datePattern, timePattern string
+	if dateKey != "" {
+		datePattern = dtf.data.skeletons[dateKey]
+	}
+	tzName := components["timeZoneName"]
+	if timeKey != "" {
+		if p, exists := dtf.data.skeletons[timeKey+"z"]; tzName != "" && exists {
+			timePattern = p
+			tzName = ""
+		} else {
+			timePattern = dtf.data.skeletons[timeKey]
+		}
+		timePattern = setIntlHourCycle(timePattern, hc)
+	}
+	if fractionalSecondDigits > 0 {
+		fraction := strings.Repeat("S", fractionalSecondDigits)
+		if idx := strings.LastIndex(timePattern, "ss"); idx >= 0 {
+			idx += 2
+			timePattern = timePattern[:idx] + dtf.data.fractionSeparator + fraction + timePattern[idx:]
+		} else if timePattern == "" {
+			timePattern = fraction
+		} else {
+			timePattern += " " + fraction
+		}
+	}
+	if tzName != "" {
+		if timePattern == "" {
+			timePattern = "z"
+		} else {
+			timePattern += " z"
+		}
+	}
+	if timePattern == "" && components["dayPeriod"] != "" && datePattern == "" {
+		timePattern = "a"
+	}
+
+	pattern := datePattern
+	if timePattern != "" {
+		if datePattern == "" {
+			pattern = timePattern
+		} else {
+			idx := 3
+			switch {
+			case month == "long" && components["weekday"] == "long":
+				idx = 0
+			case month == "long":
+				idx = 1
+			case month == "short":
+				idx = 2
+			}
+			pattern = dtf.joinPatterns(idx, datePattern, timePattern)
+		}
+	}
+	return adjustIntlDatePattern(pattern, components)
+}
+
+// adjustIntlDatePattern sets the widths of the fields to match the requested options.
+func adjustIntlDatePattern(pattern string, components map[string]string) string {
+	tokens := parseIntlDatePattern(pattern)
+	var sb strings.Builder
+	for _, t := range tokens {
+		if t.field == 0 {
+			sb.WriteByte('\'')
+			sb.WriteString(strings.ReplaceAll(t.literal, "'", "''"))
+			sb.WriteByte('\'')
+			continue
+		}
+		count := t.count
+		switch t.field {
+		case 'E':
+			switch components["weekday"] {
+			case "short":
+				count = 3
+			case "long":
+				count = 4
+			case "narrow":
+				count = 5
+			}
+		case 'y':
+			switch components["year"] {
+			case "2-digit":
+				count = 2
+			case "numeric":
+				count = 1
+			}
+		case 'M', 'L':
+			switch components["month"] {
+			case "numeric":
+				if count > 2 {
+					count = 1
+				}
+			case "2-digit":
+				count = 2
+			case "short":
+				count = 3
+			case "long":
+				count = 4
+			case "narrow":
+				count = 5
+			}
+		case 'd':
+			if components["day"] == "2-digit" {
+				count = 2
+			}
+		case 'h', 'H', 'K', 'k':
+			if components["hour"] == "2-digit" {
+				count = 2
+			}
+		case 'm':
+			if components["minute"] == "2-digit" {
+				count = 2
+			}
+		case 's':
+			if components["second"] == "2-digit" {
+				count = 2
+			}
+		case 'z':
+			switch components["timeZoneName"] {
+			case "long":
+				count = 4
+			case "shortOffset":
+				t.field, count = 'O', 1
+			case "longOffset":
+				t.field, count = 'O', 4
+			case "shortGeneric":
+				t.field, count = 'v', 1
+			case "longGeneric":
+				t.field, count = 'v', 4
+			}
+		}
+		sb.WriteString(strings.Repeat(string(t.field), count))
+	}
+	return sb.String()
+}
+
+var intlDateFieldTypes = map[byte]string{
+	'y': "year",
+	'M': "month",
+	'L': "month",
+	'd': "day",
+	'E': "weekday",
+	'a': "dayPeriod",
+	'h': "hour",
+	'H': "hour",
+	'K': "hour",
+	'k': "hour",
+	'm': "minute",
+	's': "second",
+	'S': "fractionalSecond",
+	'z': "timeZoneName",
+	'O': "timeZoneName",
+	'v': "timeZoneName",
+}
+
+func (dtf *intlDateTimeFormat) number(n, width int) string {
+	s := strconv.Itoa(n)
+	for len(s) < width {
+		s = "0" + s
+	}
+	if dtf.zero == '0' {
+		return s
+	}
+	var sb strings.Builder
+	for _, c := range s {
+		sb.WriteRune(dtf.zero + c - '0')
+	}
+	return sb.String()
+}
+
+var intlTextWidths = [...]string{"long", "short", "narrow"}
+
+// intlWidthIndex returns the index of the names (long, short or narrow) for the field length.
+func intlWidthIndex(count int) int {
+	switch count {
+	case 4:
+		return 0
+	case 5:
+		return 2
+	}
+	return 1
+}
+
+// zoneName formats the time zone name, count is 4 for the long forms.
+func (dtf *intlDateTimeFormat) zoneName(t time.Time, field byte, count int) string {
+	abbr, offset := t.Zone()
+	if field != 'O' && dtf.timeZone == "UTC" {
+		if count == 4 {
+			return dtf.data.utcLong
+		}
+		return "UTC"
+	}
+	if count < 4 && field != 'O' && (dtf.data == intlDateLocales["en"]) &&
+		(strings.HasPrefix(dtf.timeZone, "America/") || strings.HasPrefix(dtf.timeZone, "US/")) && isIntlAlpha(abbr) {
+		return abbr
+	}
+	if offset == 0 {
+		return dtf.data.gmt
+	}
+	var sb strings.Builder
+	sb.WriteString(dtf.data.gmt)
+	if offset < 0 {
+		sb.WriteString(dtf.data.gmtMinus)
+	} else {
+		sb.WriteByte('+')
+	}
+	hm := formatIntlOffset(offset)
+	if count == 4 {
+		sb.WriteString(hm)
+	} else {
+		h, _ := strconv.Atoi(hm[:2])
+		sb.WriteString(strconv.Itoa(h))
+		if hm[3:] != "00" {
+			sb.WriteByte(':')
+			sb.WriteString(hm[3:])
+		}
+	}
+	return sb.String()
+}
+
+func (dtf *intlDateTimeFormat) field(t time.Time, token intlDateToken) string {
+	count := token.count
+	switch token.field {
+	case 'y':
+		year := t.Year()
+		if year <= 0 {
+			year = 1 - year
+		}
+		if count == 2 {
+			return dtf.number(year%100, 2)
+		}
+		return dtf.number(year, count)
+	case 'M', 'L':
+		month := int(t.Month()) - 1
+		if count <= 2 {
+			return dtf.number(month+1, count)
+		}
+		names := &dtf.data.months
+		if token.field == 'L' && dtf.data.standaloneMonths != nil {
+			names = dtf.data.standaloneMonths
+		}
+		return names[intlWidthIndex(count)][month]
+	case 'd':
+		return dtf.number(t.Day(), count)
+	case 'E':
+		return dtf.data.weekdays[intlWidthIndex(count)][t.Weekday()]
+	case 'a':
+		if t.Hour() < 12 {
+			return dtf.data.dayPeriods[0]
+		}
+		return dtf.data.dayPeriods[1]
+	case 'h':
+		h := t.Hour() % 12
+		if h == 0 {
+			h = 12
+		}
+		return dtf.number(h, count)
+	case 'K':
+		return dtf.number(t.Hour()%12, count)
+	case 'H':
+		return dtf.number(t.Hour(), count)
+	case 'k':
+		h := t.Hour()
+		if h == 0 {
+			h = 24
+		}
+		return dtf.number(h, count)
+	case 'm':
+		return dtf.number(t.Minute(), count)
+	case 's':
+		return dtf.number(t.Second(), count)
+	case 'S':
+		return dtf.number(t.Nanosecond()/1e6, 3)[:count]
+	case 'z', 'O', 'v':
+		return dtf.zoneName(t, token.field, count)
+	}
+	return ""
+}
+
+func (dtf *intlDateTimeFormat) formatToParts(t time.Time) []intlPart {
+	t = t.In(dtf.loc)
+	parts := make([]intlPart, 0, len(dtf.tokens))
+	for _, token := range dtf.tokens {
+		if token.field == 0 {
+			parts = appendIntlLiteral(parts, token.literal)
+			continue
+		}
+		if typ, exists := intlDateFieldTypes[token.field]; exists {
+			parts = append(parts, intlPart{typ: typ, value: dtf.field(t, token)})
+		}
+	}
+	return parts
+}
+
+func (dtf *intlDateTimeFormat) format(t time.Time) string {
+	return intlPartsToString(dtf.formatToParts(t))
+}
+
+func (dtf *intlDateTimeFormat) resolvedOptions(r *Runtime) *Object {
+	props := []interface{}{
+		"locale", dtf.locale,
+		"calendar", dtf.calendar,
+		"numberingSystem", dtf.numberingSystem,
+		"timeZone", dtf.timeZone,
+	}
+	hasHour := false
+	for _, t := range dtf.tokens {
+		switch t.field {
+		case 'h', 'H', 'K', 'k':
+			hasHour = true
+		}
+	}
+	if hasHour {
+		props = append(props, "hourCycle", dtf.hourCycle, "hour12", dtf.hourCycle == "h11" || dtf.hourCycle == "h12")
+	}
+	if dtf.dateStyle == "" && dtf.timeStyle == "" {
+		components := make(map[string]interface{})
+		for _, t := range dtf.tokens {
+			var name string
+			var value interface{}
+			switch t.field {
+			case 'E':
+				name, value = "weekday", intlTextWidths[intlWidthIndex(t.count)]
+			case 'y':
+				name, value = "year", "numeric"
+				if t.count == 2 {
+					value = "2-digit"
+				}
+			case 'M', 'L':
+				name = "month"
+				switch t.count {
+				case 1:
+					value = "numeric"
+				case 2:
+					value = "2-digit"
+				default:
+					value = intlTextWidths[intlWidthIndex(t.count)]
+				}
+			case 'd', 'h', 'H', 'K', 'k', 'm', 's':
+				name = intlDateFieldTypes[t.field]
+				value = "numeric"
+				if t.count == 2 {
+					value = "2-digit"
+				}
+			case 'S':
+				name, value = "fractionalSecondDigits", t.count
+			case 'z':
+				name, value = "timeZoneName", "short"
+				if t.count == 4 {
+					value = "long"
+				}
+			case 'O':
+				name, value = "timeZoneName", "shortOffset"
+				if t.count == 4 {
+					value = "longOffset"
+				}
+			case 'v':
+				name, value = "timeZoneName", "shortGeneric"
+				if t.count == 4 {
+					value = "longGeneric"
+				}
+			}
+			if name != "" {
+				components[name] = value
+			}
+		}
+		for _, name := range intlDateComponents {
+			if v, exists := components[name]; exists {
+				props = append(props, name, v)
+			}
+		}
+	}
+	props = append(props, "dateStyle", dtf.dateStyle, "timeStyle", dtf.timeStyle)
+	return r.intlResolvedOptions(props...)
+}
+
+// toIntlTime converts the argument of format() to time, undefined means the current time.
+func (r *Runtime) toIntlTime(v Value) time.Time {
+	if v == _undefined {
+		return r.now()
+	}
+	f := v.ToFloat()
+	if f != f || math.Abs(f) > maxTime {
+		panic(r.newErrorf(r.getRangeError(), "Invalid time value"))
+	}
+	return timeFromMsec(int64(f))
+}
+
+type dateTimeFormatObject struct {
+	baseObject
+	dtf         *intlDateTimeFormat
+	boundFormat *Object
+}
+
+func (r *Runtime) toDateTimeFormat(v Value, method string) *dateTimeFormatObject {
+	if o, ok := v.(*Object); ok {
+		if dtf, ok := o.self.(*dateTimeFormatObject); ok {
+			return dtf
+		}
+	}
+	panic(r.NewTypeError("Method Intl.DateTimeFormat.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+func (r *Runtime) builtin_newDateTimeFormat(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		newTarget = r.getIntlDateTimeFormat()
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.global.IntlDateTimeFormat, r.getIntlDateTimeFormatPrototype())
+	dtf := r.newIntlDateTimeFormat(argAt(args, 0), argAt(args, 1), "any", "date")
+	o := &Object{runtime: r}
+	dtfo := &dateTimeFormatObject{dtf: dtf}
+	dtfo.class = classObject
+	dtfo.val = o
+	dtfo.extensible = true
+	o.self = dtfo
+	dtfo.prototype = proto
+	dtfo.init()
+	return o
+}
+
+func (r *Runtime) dateTimeFormatProto_getFormat(call FunctionCall) Value {
+	dtfo := r.toDateTimeFormat(call.This, "format")
+	return r.intlBoundFunc(&dtfo.boundFormat, func(call FunctionCall) Value {
+		return newStringValue(dtfo.dtf.format(r.toIntlTime(call.Argument(0))))
+	}, 1)
+}
+
+func (r *Runtime) dateTimeFormatProto_formatToParts(call FunctionCall) Value {
+	dtfo := r.toDateTimeFormat(call.This, "formatToParts")
+	return r.intlPartsToArray(dtfo.dtf.formatToParts(r.toIntlTime(call.Argument(0))))
+}
+
+func (r *Runtime) dateTimeFormatProto_resolvedOptions(call FunctionCall) Value {
+	dtfo := r.toDateTimeFormat(call.This, "resolvedOptions")
+	return dtfo.dtf.resolvedOptions(r)
+}
+
+func (r *Runtime) createIntlDateTimeFormatProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
+
+	o._putProp("constructor", r.getIntlDateTimeFormat(), true, false, true)
+	o.setOwnStr("format", &valueProperty{
+		getterFunc:   r.newNativeFunc(r.dateTimeFormatProto_getFormat, "get format", 0),
+		accessor:     true,
+		configurable: true,
+	}, true)
+	o._putProp("formatToParts", r.newNativeFunc(r.dateTimeFormatProto_formatToParts, "formatToParts", 1), true, false, true)
+	o._putProp("resolvedOptions", r.newNativeFunc(r.dateTimeFormatProto_resolvedOptions, "resolvedOptions", 0), true, false, true)
+
+	o._putSym(SymToStringTag, valueProp(asciiString("Intl.DateTimeFormat"), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) createIntlDateTimeFormat(val *Object) objectImpl {
+	o := r.newNativeConstructOnly(val, r.builtin_newDateTimeFormat, r.getIntlDateTimeFormatPrototype(), "DateTimeFormat", 0)
+	o._putProp("supportedLocalesOf", r.newNativeFunc(r.intlSupportedLocalesOf, "supportedLocalesOf", 1), true, false, true)
+
+	return o
+}
+
+func (r *Runtime) getIntlDateTimeFormatPrototype() *Object {
+	ret := r.global.IntlDateTimeFormatPrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.IntlDateTimeFormatPrototype = ret
+		ret.self = r.createIntlDateTimeFormatProto(ret)
+	}
+	return ret
+}
+
+func (r *Runtime) getIntlDateTimeFormat() *Object {
+	ret := r.global.IntlDateTimeFormat
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.IntlDateTimeFormat = ret
+		ret.self = r.createIntlDateTimeFormat(ret)
+	}
+	return ret
+}
+
+var intlDateToLocaleKinds = [...]struct {
+	required, defaults string
+}{
+	{"any", "all"},
+	{"date", "date"},
+	{"time", "time"},
+}
+
+// dateToLocaleString is used by Date.prototype.toLocaleString(), toLocaleDateString() and toLocaleTimeString(),
+// kind is the index in intlDateToLocaleKinds.
+func (r *Runtime) dateToLocaleString(t time.Time, kind int, locales, options Value) Value {
+	var dtf *intlDateTimeFormat
+	k := intlDateToLocaleKinds[kind]
+	if locales == _undefined && options == _undefined {
+		dtf = r.intl.dateTimeFormat[kind]
+		if dtf == nil {
+			dtf = r.newIntlDateTimeFormat(_undefined, _undefined, k.required, k.defaults)
+			r.intl.dateTimeFormat[kind] = dtf
+		}
+	} else {
+		dtf = r.newIntlDateTimeFormat(locales, options, k.required, k.defaults)
+	}
+	return newStringValue(dtf.format(t))
+}
@@ -0,0 +1,1082 @@
+package sobek
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+
+	"github.com/grafana/sobek/unistring"
+)
+
+// intlNumberSymbols holds the locale specific symbols and grouping sizes. They are obtained once per
+// locale by formatting a few numbers with golang.org/x/text/message.
+type intlNumberSymbols struct {
+	zero           rune
+	decimal        string
+	group          string
+	primaryGroup   int
+	secondaryGroup int
+	minusSign      string
+	plusSign       string
+	signAfter      bool
+	percentPrefix  string
+	percentSuffix  string
+	nan            string
+	infinity       string
+}
+
+var intlNumberSymbolsCache sync.Map
+
+var intlNumberingSystems = map[string]rune{
+	"arab":     '٠',
+	"arabext":  '۰',
+	"beng":     '০',
+	"deva":     '०',
+	"fullwide": '０',
+	"gujr":     '૦',
+	"guru":     '੦',
+	"khmr":     '០',
+	"knda":     '೦',
+	"laoo":     '໐',
+	"latn":     '0',
+	"mlym":     '൦',
+	"mymr":     '၀',
+	"orya":     '୦',
+	"tamldec":  '௦',
+	"telu":     '౦',
+	"thai":     '๐',
+	"tibt":     '༠',
+}
+
+func intlNumberingSystemName(zero rune) string {
+	for name, z := range intlNumberingSystems {
+		if z == zero {
+			return name
+		}
+	}
+	return "latn"
+}
+
+func isIntlNumberingSystem(name string) bool {
+	_, exists := intlNumberingSystems[name]
+	return exists
+}
+
+// findDigits returns the byte offsets of the first run of decimal digits in s.
+func findDigits(s string) (start, end int) {
+	start = -1
+	for i, c := range s {
+		if unicode.IsDigit(c) {
+			if start < 0 {
+				start = i
+			}
+			end = i + utf8.RuneLen(c)
+		} else if start >= 0 {
+			break
+		}
+	}
+	if start < 0 {
+		return 0, 0
+	}
+	return
+}
+
+func probeIntlNumberSymbols(tag language.Tag) *intlNumberSymbols {
+	sym := &intlNumberSymbols{
+		zero:           '0',
+		decimal:        ".",
+		group:          ",",
+		primaryGroup:   3,
+		secondaryGroup: 3,
+		minusSign:      "-",
+		plusSign:       "+",
+		percentSuffix:  "%",
+		nan:            "NaN",
+		infinity:       "∞",
+	}
+	p := message.NewPrinter(tag)
+
+	// The separators and the grouping sizes are found in the formatted 1234567890.125
+	var digits []rune
+	var separators []string
+	var positions []int
+	var sep strings.Builder
+	for _, c := range p.Sprint(number.Decimal(1234567890.125, number.MinFractionDigits(3))) {
+		if unicode.IsDigit(c) {
+			if sep.Len() > 0 {
+				separators = append(separators, sep.String())
+				positions = append(positions, len(digits))
+				sep.Reset()
+			}
+			digits = append(digits, c)
+		} else if len(digits) > 0 {
+			sep.WriteRune(c)
+		}
+	}
+	if len(digits) == 13 && len(separators) > 0 && positions[len(positions)-1] == 10 {
+		sym.zero = digits[9]
+		sym.decimal = separators[len(separators)-1]
+		groups := positions[:len(positions)-1]
+		if len(groups) > 0 {
+			sym.group = separators[0]
+			sym.primaryGroup = 10 - groups[len(groups)-1]
+			sym.secondaryGroup = sym.primaryGroup
+			if len(groups) > 1 {
+				sym.secondaryGroup = groups[len(groups)-1] - groups[len(groups)-2]
+			}
+		} else {
+			sym.primaryGroup = 0
+		}
+	}
+
+	s := p.Sprint(number.Decimal(-1))
+	if start, end := findDigits(s); end > 0 {
+		if start > 0 {
+			sym.minusSign = s[:start]
+		} else if end < len(s) {
+			sym.minusSign = s[end:]
+			sym.signAfter = true
+		}
+		sym.plusSign = strings.NewReplacer("-", "+", "−", "+").Replace(sym.minusSign)
+	}
+
+	s = p.Sprint(number.Percent(0.5))
+	if start, end := findDigits(s); end > 0 {
+		sym.percentPrefix = s[:start]
+		sym.percentSuffix = s[end:]
+	}
+
+	sym.nan = p.Sprint(number.Decimal(math.NaN()))
+	sym.infinity = p.Sprint(number.Decimal(math.Inf(1)))
+	return sym
+}
+
+func getIntlNumberSymbols(locale *intlLocale) *intlNumberSymbols {
+	if sym, ok := intlNumberSymbolsCache.Load(locale.base); ok {
+		return sym.(*intlNumberSymbols)
+	}
+	sym, _ := intlNumberSymbolsCache.LoadOrStore(locale.base, probeIntlNumberSymbols(locale.tag()))
+	return sym.(*intlNumberSymbols)
+}
+
+// intlDecimal is an exact decimal number, its absolute value is digits * 10^exp.
+type intlDecimal struct {
+	neg, nan, inf bool
+	digits        *big.Int
+	exp           int
+}
+
+func newIntlDecimalFromFloat(f float64) *intlDecimal {
+	d := &intlDecimal{digits: new(big.Int)}
+	switch {
+	case f != f:
+		d.nan = true
+		return d
+	case math.Signbit(f):
+		d.neg = true
+		f = -f
+	}
+	if math.IsInf(f, 1) {
+		d.inf = true
+		return d
+	}
+	if f == 0 {
+		return d
+	}
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+	pos := strings.IndexByte(s, 'e')
+	exp, _ := strconv.Atoi(s[pos+1:])
+	mant := strings.Replace(s[:pos], ".", "", 1)
+	d.digits.SetString(mant, 10)
+	d.exp = exp - (len(mant) - 1)
+	return d
+}
+
+func newIntlDecimalFromBigInt(i *big.Int) *intlDecimal {
+	return &intlDecimal{neg: i.Sign() < 0, digits: new(big.Int).Abs(i)}
+}
+
+// parseIntlDecimal parses a decimal literal without losing precision. It returns nil if the string
+// is not a decimal literal (e.g. a hex literal or "Infinity") or the exponent is too large.
+func parseIntlDecimal(s string) *intlDecimal {
+	d := &intlDecimal{digits: new(big.Int)}
+	if s == "" {
+		return d
+	}
+	switch s[0] {
+	case '-':
+		d.neg = true
+		fallthrough
+	case '+':
+		s = s[1:]
+	}
+	var mant strings.Builder
+	exp := 0
+	seenDot, seenDigit := false, false
+	i := 0
+	for ; i < len(s); i++ {
+		c := s[i]
+		if c >= '0' && c <= '9' {
+			mant.WriteByte(c)
+			seenDigit = true
+			if seenDot {
+				exp--
+			}
+		} else if c == '.' && !seenDot {
+			seenDot = true
+		} else {
+			break
+		}
+	}
+	if !seenDigit {
+		return nil
+	}
+	if i < len(s) {
+		if s[i] != 'e' && s[i] != 'E' {
+			return nil
+		}
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil || e > 10000 || e < -10000 || s[i+1] == ' ' {
+			return nil
+		}
+		exp += e
+	}
+	d.digits.SetString(mant.String(), 10)
+	d.exp = exp
+	return d
+}
+
+// toIntlDecimal implements ToIntlMathematicalValue.
+func (r *Runtime) toIntlDecimal(v Value) *intlDecimal {
+	if o, ok := v.(*Object); ok {
+		v = o.toPrimitiveNumber()
+	}
+	switch v := v.(type) {
+	case *valueBigInt:
+		return newIntlDecimalFromBigInt((*big.Int)(v))
+	case String:
+		if d := parseIntlDecimal(v.toTrimmedUTF8()); d != nil {
+			return d
+		}
+	}
+	return newIntlDecimalFromFloat(v.ToFloat())
+}
+
+func (d *intlDecimal) isZero() bool {
+	return d.digits.Sign() == 0
+}
+
+// magnitude returns the exponent of the most significant digit, i.e. floor(log10(|x|)).
+func (d *intlDecimal) magnitude() int {
+	if d.isZero() {
+		return 0
+	}
+	return len(d.digits.String()) - 1 + d.exp
+}
+
+var bigTen = big.NewInt(10)
+
+func pow10Big(n int) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// round rounds the number to a multiple of increment*10^-frac and returns the digits of the result
+// with frac implied fraction digits.
+func (d *intlDecimal) round(frac int, increment int64, mode string) string {
+	x := new(big.Int).Set(d.digits)
+	den := big.NewInt(1)
+	if shift := d.exp + frac; shift >= 0 {
+		x.Mul(x, pow10Big(shift))
+	} else {
+		den = pow10Big(-shift)
+	}
+	unit := new(big.Int).Mul(den, big.NewInt(increment))
+	q, rem := new(big.Int).QuoRem(x, unit, new(big.Int))
+	if rem.Sign() != 0 {
+		half := rem.Mul(rem, big.NewInt(2)).Cmp(unit)
+		var up bool
+		switch mode {
+		case "ceil":
+			up = !d.neg
+		case "floor":
+			up = d.neg
+		case "expand":
+			up = true
+		case "trunc":
+			up = false
+		case "halfCeil":
+			up = half > 0 || half == 0 && !d.neg
+		case "halfFloor":
+			up = half > 0 || half == 0 && d.neg
+		case "halfTrunc":
+			up = half > 0
+		case "halfEven":
+			up = half > 0 || half == 0 && q.Bit(0) == 1
+		default:
+			up = half >= 0
+		}
+		if up {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q.Mul(q, big.NewInt(increment)).String()
+}
+
+// intlDigitOptions holds the options set by SetNumberFormatDigitOptions.
+type intlDigitOptions struct {
+	minimumIntegerDigits     int
+	minimumFractionDigits    int
+	maximumFractionDigits    int
+	minimumSignificantDigits int
+	maximumSignificantDigits int
+	useFractionDigits        bool
+	useSignificantDigits     bool
+	roundingIncrement        int
+	roundingMode             string
+	roundingPriority         string
+	roundingType             string
+	trailingZeroDisplay      string
+}
+
+var intlRoundingIncrements = []int{1, 2, 5, 10, 20, 25, 50, 100, 200, 250, 500, 1000, 2000, 2500, 5000}
+
+var intlRoundingModes = []string{"ceil", "floor", "expand", "trunc", "halfCeil", "halfFloor", "halfExpand", "halfTrunc", "halfEven"}
+
+// intlSetDigitOptions implements SetNumberFormatDigitOptions.
+func (r *Runtime) intlSetDigitOptions(o *intlDigitOptions, options *Object, mnfdDefault, mxfdDefault int, notation string) {
+	o.minimumIntegerDigits, _ = r.intlGetNumberOption(options, "minimumIntegerDigits", 1, 21, 1)
+	mnfd := intlGet(options, "minimumFractionDigits")
+	mxfd := intlGet(options, "maximumFractionDigits")
+	mnsd := intlGet(options, "minimumSignificantDigits")
+	mxsd := intlGet(options, "maximumSignificantDigits")
+	o.roundingIncrement, _ = r.intlGetNumberOption(options, "roundingIncrement", 1, 5000, 1)
+	validIncrement := false
+	for _, inc := range intlRoundingIncrements {
+		if inc == o.roundingIncrement {
+			validIncrement = true
+			break
+		}
+	}
+	if !validIncrement {
+		panic(r.newErrorf(r.getRangeError(), "roundingIncrement value is out of range."))
+	}
+	o.roundingMode = r.intlGetStringOption(options, "roundingMode", intlRoundingModes, "halfExpand")
+	o.roundingPriority = r.intlGetStringOption(options, "roundingPriority", []string{"auto", "morePrecision", "lessPrecision"}, "auto")
+	o.trailingZeroDisplay = r.intlGetStringOption(options, "trailingZeroDisplay", []string{"auto", "stripIfInteger"}, "auto")
+	if o.roundingIncrement != 1 {
+		mxfdDefault = mnfdDefault
+	}
+	hasSd := mnsd != _undefined || mxsd != _undefined
+	hasFd := mnfd != _undefined || mxfd != _undefined
+	needSd, needFd := true, true
+	if o.roundingPriority == "auto" {
+		needSd = hasSd
+		if needSd || !hasFd && notation == "compact" {
+			needFd = false
+		}
+	}
+	if needSd {
+		if hasSd {
+			o.minimumSignificantDigits, _ = r.intlDefaultNumberOption(mnsd, "minimumSignificantDigits", 1, 21, 1)
+			o.maximumSignificantDigits, _ = r.intlDefaultNumberOption(mxsd, "maximumSignificantDigits", o.minimumSignificantDigits, 21, 21)
+		} else {
+			o.minimumSignificantDigits, o.maximumSignificantDigits = 1, 21
+		}
+	}
+	if needFd {
+		if hasFd {
+			min, hasMin := r.intlDefaultNumberOption(mnfd, "minimumFractionDigits", 0, 100, 0)
+			max, hasMax := r.intlDefaultNumberOption(mxfd, "maximumFractionDigits", 0, 100, 0)
+			switch {
+			case !hasMin:
+				if mnfdDefault < max {
+					min = mnfdDefault
+				} else {
+					min = max
+				}
+			case !hasMax:
+				if mxfdDefault > min {
+					max = mxfdDefault
+				} else {
+					max = min
+				}
+			case min > max:
+				panic(r.newErrorf(r.getRangeError(), "maximumFractionDigits value is out of range."))
+			}
+			o.minimumFractionDigits, o.maximumFractionDigits = min, max
+		} else {
+			o.minimumFractionDigits, o.maximumFractionDigits = mnfdDefault, mxfdDefault
+		}
+	}
+	o.useSignificantDigits, o.useFractionDigits = needSd, needFd
+	switch {
+	case !needSd && !needFd:
+		o.minimumFractionDigits, o.maximumFractionDigits = 0, 0
+		o.minimumSignificantDigits, o.maximumSignificantDigits = 1, 2
+		o.useSignificantDigits, o.useFractionDigits = true, true
+		o.roundingType = "morePrecision"
+		o.roundingPriority = "morePrecision"
+	case o.roundingPriority == "auto":
+		if needSd {
+			o.roundingType = "significantDigits"
+		} else {
+			o.roundingType = "fractionDigits"
+		}
+	default:
+		o.roundingType = o.roundingPriority
+	}
+	if o.roundingIncrement != 1 {
+		if o.roundingType != "fractionDigits" {
+			panic(r.NewTypeError("roundingIncrement can only be used with fraction digits rounding"))
+		}
+		if o.maximumFractionDigits != o.minimumFractionDigits {
+			panic(r.newErrorf(r.getRangeError(), "maximumFractionDigits must be equal to minimumFractionDigits when roundingIncrement is used"))
+		}
+	}
+}
+
+// resolvedOptions returns the digit options as the key-value pairs expected by intlResolvedOptions.
+func (o *intlDigitOptions) resolvedOptions() []interface{} {
+	props := []interface{}{"minimumIntegerDigits", o.minimumIntegerDigits}
+	if o.useFractionDigits {
+		props = append(props, "minimumFractionDigits", o.minimumFractionDigits, "maximumFractionDigits", o.maximumFractionDigits)
+	}
+	if o.useSignificantDigits {
+		props = append(props, "minimumSignificantDigits", o.minimumSignificantDigits, "maximumSignificantDigits", o.maximumSignificantDigits)
+	}
+	return props
+}
+
+func (o *intlDigitOptions) roundingOptions() []interface{} {
+	return []interface{}{
+		"roundingIncrement", o.roundingIncrement,
+		"roundingMode", o.roundingMode,
+		"roundingPriority", o.roundingPriority,
+		"trailingZeroDisplay", o.trailingZeroDisplay,
+	}
+}
+
+// roundSignificant rounds to the significant digits and returns the digits, the number of the implied fraction
+// digits and the minimum number of the fraction digits.
+func (o *intlDigitOptions) roundSignificant(d *intlDecimal) (string, int, int) {
+	frac := o.maximumSignificantDigits - 1 - d.magnitude()
+	digits := d.round(frac, 1, o.roundingMode)
+	magnitude := len(digits) - 1 - frac
+	if digits == "0" {
+		magnitude = 0
+	}
+	return digits, frac, o.minimumSignificantDigits - 1 - magnitude
+}
+
+func (o *intlDigitOptions) roundFraction(d *intlDecimal) (string, int, int) {
+	return d.round(o.maximumFractionDigits, int64(o.roundingIncrement), o.roundingMode), o.maximumFractionDigits, o.minimumFractionDigits
+}
+
+// format implements FormatNumericToString, it returns the integer and the fraction digits of the rounded number.
+func (o *intlDigitOptions) format(d *intlDecimal) (string, string) {
+	var digits string
+	var frac, minFrac int
+	switch o.roundingType {
+	case "significantDigits":
+		digits, frac, minFrac = o.roundSignificant(d)
+	case "fractionDigits":
+		digits, frac, minFrac = o.roundFraction(d)
+	default:
+		sDigits, sFrac, sMinFrac := o.roundSignificant(d)
+		fDigits, fFrac, fMinFrac := o.roundFraction(d)
+		pickSignificant := sFrac >= fFrac
+		if o.roundingType == "lessPrecision" {
+			pickSignificant = sFrac <= fFrac
+		}
+		if pickSignificant {
+			digits, frac, minFrac = sDigits, sFrac, sMinFrac
+		} else {
+			digits, frac, minFrac = fDigits, fFrac, fMinFrac
+		}
+	}
+
+	var intPart, fracPart string
+	if frac > 0 {
+		if len(digits) <= frac {
+			digits = strings.Repeat("0", frac-len(digits)+1) + digits
+		}
+		intPart, fracPart = digits[:len(digits)-frac], digits[len(digits)-frac:]
+	} else {
+		intPart = digits
+		if digits != "0" {
+			intPart += strings.Repeat("0", -frac)
+		}
+	}
+	if minFrac < 0 {
+		minFrac = 0
+	}
+	end := len(fracPart)
+	for end > minFrac && fracPart[end-1] == '0' {
+		end--
+	}
+	fracPart = fracPart[:end]
+	if o.trailingZeroDisplay == "stripIfInteger" && strings.Trim(fracPart, "0") == "" {
+		fracPart = ""
+	}
+	for len(fracPart) < minFrac {
+		fracPart += "0"
+	}
+	if l := len(intPart); l < o.minimumIntegerDigits {
+		intPart = strings.Repeat("0", o.minimumIntegerDigits-l) + intPart
+	}
+	return intPart, fracPart
+}
+
+type intlPart struct {
+	typ   string
+	value string
+}
+
+func appendIntlLiteral(parts []intlPart, s string) []intlPart {
+	if s == "" {
+		return parts
+	}
+	if l := len(parts); l > 0 && parts[l-1].typ == "literal" {
+		parts[l-1].value += s
+		return parts
+	}
+	return append(parts, intlPart{typ: "literal", value: s})
+}
+
+func intlPartsToString(parts []intlPart) string {
+	var sb strings.Builder
+	for _, p := range parts {
+		sb.WriteString(p.value)
+	}
+	return sb.String()
+}
+
+type intlNumberFormat struct {
+	locale          string
+	numberingSystem string
+	sym             *intlNumberSymbols
+	zero            rune
+	lang            string
+
+	style           string
+	currency        string
+	currencyDisplay string
+	currencySign    string
+	currencySymbol  string
+
+	digits intlDigitOptions
+
+	notation    string
+	useGrouping string
+	signDisplay string
+}
+
+func (nf *intlNumberFormat) localizeDigits(s string) string {
+	if nf.zero == '0' {
+		return s
+	}
+	var sb strings.Builder
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			sb.WriteRune(nf.zero + c - '0')
+		} else {
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
+
+func (nf *intlNumberFormat) appendInteger(parts []intlPart, s string) []intlPart {
+	primary, secondary := nf.sym.primaryGroup, nf.sym.secondaryGroup
+	grouping := primary > 0
+	switch nf.useGrouping {
+	case "false":
+		grouping = false
+	case "min2":
+		grouping = grouping && len(s) >= primary+2
+	}
+	if !grouping || len(s) <= primary {
+		return append(parts, intlPart{typ: "integer", value: nf.localizeDigits(s)})
+	}
+	var groups []string
+	end := len(s) - primary
+	groups = append(groups, s[end:])
+	for end > secondary {
+		groups = append(groups, s[end-secondary:end])
+		end -= secondary
+	}
+	groups = append(groups, s[:end])
+	for i := len(groups) - 1; i >= 0; i-- {
+		parts = append(parts, intlPart{typ: "integer", value: nf.localizeDigits(groups[i])})
+		if i > 0 {
+			parts = append(parts, intlPart{typ: "group", value: nf.sym.group})
+		}
+	}
+	return parts
+}
+
+// numberParts returns the parts of the formatted absolute value and whether it's zero after rounding.
+func (nf *intlNumberFormat) numberParts(x *intlDecimal) ([]intlPart, bool) {
+	if x.nan {
+		return []intlPart{{typ: "nan", value: nf.sym.nan}}, false
+	}
+	if x.inf {
+		return []intlPart{{typ: "infinity", value: nf.sym.infinity}}, false
+	}
+	if nf.style == "percent" {
+		x = &intlDecimal{neg: x.neg, digits: x.digits, exp: x.exp + 2}
+	}
+	var intPart, fracPart string
+	exponent := 0
+	if nf.notation == "scientific" || nf.notation == "engineering" {
+		step := 1
+		if nf.notation == "engineering" {
+			step = 3
+		}
+		exponent = int(math.Floor(float64(x.magnitude())/float64(step))) * step
+		for {
+			intPart, fracPart = nf.digits.format(&intlDecimal{neg: x.neg, digits: x.digits, exp: x.exp - exponent})
+			// rounding may produce a mantissa that needs a greater exponent, e.g. 9.99 -> 10
+			if x.isZero() || len(strings.TrimLeft(intPart, "0")) <= step {
+				break
+			}
+			exponent += step
+		}
+	} else {
+		intPart, fracPart = nf.digits.format(x)
+	}
+	parts := nf.appendInteger(nil, intPart)
+	if fracPart != "" {
+		parts = append(parts, intlPart{typ: "decimal", value: nf.sym.decimal}, intlPart{typ: "fraction", value: nf.localizeDigits(fracPart)})
+	}
+	if nf.notation == "scientific" || nf.notation == "engineering" {
+		parts = append(parts, intlPart{typ: "exponentSeparator", value: "E"})
+		if exponent < 0 {
+			parts = append(parts, intlPart{typ: "exponentMinusSign", value: strings.TrimFunc(nf.sym.minusSign, unicode.IsControl)})
+			exponent = -exponent
+		}
+		parts = append(parts, intlPart{typ: "exponentInteger", value: nf.localizeDigits(strconv.Itoa(exponent))})
+	}
+	isZero := strings.Trim(intPart, "0") == "" && strings.Trim(fracPart, "0") == ""
+	return parts, isZero
+}
+
+// sign returns the type of the sign part for the formatted number ("" if no sign is displayed).
+func (nf *intlNumberFormat) sign(x *intlDecimal, isZero bool) string {
+	switch nf.signDisplay {
+	case "never":
+		return ""
+	case "always":
+		if x.neg && !x.nan {
+			return "minusSign"
+		}
+		return "plusSign"
+	case "exceptZero":
+		switch {
+		case isZero || x.nan:
+			return ""
+		case x.neg:
+			return "minusSign"
+		}
+		return "plusSign"
+	case "negative":
+		if x.neg && !isZero && !x.nan {
+			return "minusSign"
+		}
+		return ""
+	}
+	if x.neg && !x.nan {
+		return "minusSign"
+	}
+	return ""
+}
+
+func (nf *intlNumberFormat) signPart(sign string) intlPart {
+	if sign == "plusSign" {
+		return intlPart{typ: sign, value: nf.sym.plusSign}
+	}
+	return intlPart{typ: sign, value: nf.sym.minusSign}
+}
+
+func appendIntlPercentParts(parts []intlPart, s string) []intlPart {
+	for s != "" {
+		c, size := utf8.DecodeRuneInString(s)
+		if c == '%' || c == '٪' || c == '‰' {
+			parts = append(parts, intlPart{typ: "percentSign", value: s[:size]})
+		} else {
+			parts = appendIntlLiteral(parts, s[:size])
+		}
+		s = s[size:]
+	}
+	return parts
+}
+
+func (nf *intlNumberFormat) formatToParts(x *intlDecimal) []intlPart {
+	num, isZero := nf.numberParts(x)
+	sign := nf.sign(x, isZero)
+	if nf.style == "currency" {
+		return nf.currencyParts(num, sign, x.neg)
+	}
+	var parts []intlPart
+	if sign != "" && !nf.sym.signAfter {
+		parts = append(parts, nf.signPart(sign))
+	}
+	if nf.style == "percent" {
+		parts = appendIntlPercentParts(parts, nf.sym.percentPrefix)
+	}
+	parts = append(parts, num...)
+	if nf.style == "percent" {
+		parts = appendIntlPercentParts(parts, nf.sym.percentSuffix)
+	}
+	if sign != "" && nf.sym.signAfter {
+		parts = append(parts, nf.signPart(sign))
+	}
+	return parts
+}
+
+// intlDefaultCurrencyPatterns are used for the languages missing in intlCurrencyPatterns.
+var intlDefaultCurrencyPatterns = [3]string{"¤\u00a0#", "-¤\u00a0#", "(¤\u00a0#)"}
+
+func (nf *intlNumberFormat) currencyParts(num []intlPart, sign string, neg bool) []intlPart {
+	patterns, exists := intlCurrencyPatterns[nf.lang]
+	if !exists {
+		patterns = intlDefaultCurrencyPatterns
+	}
+	pattern := patterns[0]
+	if sign != "" {
+		pattern = patterns[1]
+		if sign == "minusSign" && nf.currencySign == "accounting" {
+			pattern = patterns[2]
+		}
+	}
+	signValue := strings.TrimFunc(nf.signPart(sign).value, unicode.IsControl)
+	var parts []intlPart
+	var prev rune
+	for _, c := range pattern {
+		switch c {
+		case '#':
+			// a letter of the currency symbol is separated from the digits
+			if prev == '¤' {
+				if c, _ := utf8.DecodeLastRuneInString(nf.currencySymbol); unicode.IsLetter(c) {
+					parts = appendIntlLiteral(parts, "\u00a0")
+				}
+			}
+			parts = append(parts, num...)
+		case '¤':
+			if prev == '#' {
+				if c, _ := utf8.DecodeRuneInString(nf.currencySymbol); unicode.IsLetter(c) {
+					parts = appendIntlLiteral(parts, "\u00a0")
+				}
+			}
+			parts = append(parts, intlPart{typ: "currency", value: nf.currencySymbol})
+		case '-':
+			if sign != "" {
+				parts = append(parts, intlPart{typ: sign, value: signValue})
+			}
+		default:
+			parts = appendIntlLiteral(parts, string(c))
+		}
+		prev = c
+	}
+	return parts
+}
+
+func (nf *intlNumberFormat) format(x *intlDecimal) string {
+	return intlPartsToString(nf.formatToParts(x))
+}
+
+func (r *Runtime) intlPartsToArray(parts []intlPart, extra ...string) *Object {
+	values := make([]Value, len(parts))
+	for i, p := range parts {
+		o := r.NewObject()
+		o.self._putProp("type", asciiString(p.typ), true, true, true)
+		o.self._putProp("value", newStringValue(p.value), true, true, true)
+		for j := 0; j+1 < len(extra); j += 2 {
+			o.self._putProp(unistring.NewFromString(extra[j]), newStringValue(extra[j+1]), true, true, true)
+		}
+		values[i] = o
+	}
+	return r.newArrayValues(values)
+}
+
+func isIntlCurrencyCode(s string) bool {
+	return len(s) == 3 && isIntlAlpha(s)
+}
+
+// intlCurrencyDigits returns the number of the minor unit digits of the currency.
+func intlCurrencyDigits(code string) int {
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return 2
+	}
+	scale, _ := currency.Standard.Rounding(unit)
+	return scale
+}
+
+func intlCurrencySymbol(tag language.Tag, code, display string) string {
+	unit, err := currency.ParseISO(code)
+	if err != nil || display == "code" || display == "name" {
+		return code
+	}
+	p := message.NewPrinter(tag)
+	if display == "narrowSymbol" {
+		return p.Sprint(currency.NarrowSymbol(unit))
+	}
+	return p.Sprint(currency.Symbol(unit))
+}
+
+// intlResolveKeyword resolves the value of a Unicode extension keyword: the option takes precedence over the value
+// in the locale's extension. The second result is true if the extension value is used and must be included in
+// the resolved locale.
+func intlResolveKeyword(ext, option string, supported func(string) bool, fallback string) (string, bool) {
+	value, fromExt := fallback, false
+	if ext != "" && supported(ext) {
+		value, fromExt = ext, true
+	}
+	if option != "" && supported(option) {
+		if option != value {
+			fromExt = false
+		}
+		value = option
+	}
+	return value, fromExt
+}
+
+// newIntlNumberFormat implements InitializeNumberFormat.
+func (r *Runtime) newIntlNumberFormat(locales, opts Value) *intlNumberFormat {
+	requested := r.intlLocaleList(locales)
+	options := r.intlCoerceOptions(opts)
+	r.intlGetLocaleMatcher(options)
+	nu := r.intlGetStringOption(options, "numberingSystem", nil, "")
+	if nu != "" && !isIntlTypeSequence(nu) {
+		panic(r.newErrorf(r.getRangeError(), "Invalid numberingSystem : %s", nu))
+	}
+	locale := r.intlResolveLocale(requested)
+	nf := &intlNumberFormat{
+		sym: getIntlNumberSymbols(&locale),
+	}
+	nf.lang = strings.SplitN(locale.base, "-", 2)[0]
+	var nuFromExt bool
+	nf.numberingSystem, nuFromExt = intlResolveKeyword(locale.keyword("nu"), nu, isIntlNumberingSystem, intlNumberingSystemName(nf.sym.zero))
+	nf.zero = intlNumberingSystems[nf.numberingSystem]
+	if nuFromExt {
+		nf.locale = locale.String("nu", nf.numberingSystem)
+	} else {
+		nf.locale = locale.String()
+	}
+
+	nf.style = r.intlGetStringOption(options, "style", []string{"decimal", "percent", "currency", "unit"}, "decimal")
+	var currencyCode string
+	cur := intlGet(options, "currency")
+	if cur != _undefined {
+		currencyCode = cur.toString().String()
+		if !isIntlCurrencyCode(currencyCode) {
+			panic(r.newErrorf(r.getRangeError(), "Invalid currency code : %s", currencyCode))
+		}
+		currencyCode = strings.ToUpper(currencyCode)
+	}
+	currencyDisplay := r.intlGetStringOption(options, "currencyDisplay", []string{"code", "symbol", "narrowSymbol", "name"}, "symbol")
+	currencySign := r.intlGetStringOption(options, "currencySign", []string{"standard", "accounting"}, "standard")
+	unit := r.intlGetStringOption(options, "unit", nil, "")
+	r.intlGetStringOption(options, "unitDisplay", []string{"short", "narrow", "long"}, "short")
+	switch nf.style {
+	case "currency":
+		if cur == _undefined {
+			panic(r.NewTypeError("Currency code is required with currency style."))
+		}
+		if currencyDisplay == "name" {
+			r.intlUnsupportedOption("currencyDisplay", currencyDisplay)
+		}
+		nf.currency, nf.currencyDisplay, nf.currencySign = currencyCode, currencyDisplay, currencySign
+		nf.currencySymbol = intlCurrencySymbol(locale.tag(), nf.currency, currencyDisplay)
+	case "unit":
+		if unit == "" {
+			panic(r.NewTypeError("Unit is required with unit style."))
+		}
+		r.intlUnsupportedOption("style", nf.style)
+	}
+
+	nf.notation = r.intlGetStringOption(options, "notation", []string{"standard", "scientific", "engineering", "compact"}, "standard")
+	if nf.notation == "compact" {
+		r.intlUnsupportedOption("notation", nf.notation)
+	}
+	mnfdDefault, mxfdDefault := 0, 3
+	switch nf.style {
+	case "currency":
+		mnfdDefault = intlCurrencyDigits(nf.currency)
+		mxfdDefault = mnfdDefault
+	case "percent":
+		mxfdDefault = 0
+	}
+	r.intlSetDigitOptions(&nf.digits, options, mnfdDefault, mxfdDefault, nf.notation)
+	r.intlGetStringOption(options, "compactDisplay", []string{"short", "long"}, "short")
+	nf.useGrouping = r.intlGetUseGrouping(options)
+	nf.signDisplay = r.intlGetStringOption(options, "signDisplay", []string{"auto", "never", "always", "exceptZero", "negative"}, "auto")
+	return nf
+}
+
+// intlGetUseGrouping implements GetBooleanOrStringNumberFormatOption for the useGrouping option. The
+// result is "false" if grouping is disabled.
+func (r *Runtime) intlGetUseGrouping(options *Object) string {
+	v := intlGet(options, "useGrouping")
+	if v == _undefined {
+		return "auto"
+	}
+	if v == valueTrue {
+		return "always"
+	}
+	if !v.ToBoolean() {
+		return "false"
+	}
+	switch s := v.toString().String(); s {
+	case "true", "false":
+		return "auto"
+	case "min2", "auto", "always":
+		return s
+	default:
+		panic(r.newErrorf(r.getRangeError(), "Value %s out of range for Intl.NumberFormat options property useGrouping", s))
+	}
+}
+
+func (nf *intlNumberFormat) resolvedOptions(r *Runtime) *Object {
+	props := []interface{}{
+		"locale", nf.locale,
+		"numberingSystem", nf.numberingSystem,
+		"style", nf.style,
+		"currency", nf.currency,
+		"currencyDisplay", nf.currencyDisplay,
+		"currencySign", nf.currencySign,
+	}
+	props = append(props, nf.digits.resolvedOptions()...)
+	var useGrouping interface{} = nf.useGrouping
+	if nf.useGrouping == "false" {
+		useGrouping = false
+	}
+	props = append(props, "useGrouping", useGrouping, "notation", nf.notation, "signDisplay", nf.signDisplay)
+	props = append(props, nf.digits.roundingOptions()...)
+	return r.intlResolvedOptions(props...)
+}
+
+type numberFormatObject struct {
+	baseObject
+	nf          *intlNumberFormat
+	boundFormat *Object
+}
+
+func (r *Runtime) toNumberFormat(v Value, method string) *numberFormatObject {
+	if o, ok := v.(*Object); ok {
+		if nf, ok := o.self.(*numberFormatObject); ok {
+			return nf
+		}
+	}
+	panic(r.NewTypeError("Method Intl.NumberFormat.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+func (r *Runtime) builtin_newNumberFormat(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		newTarget = r.getIntlNumberFormat()
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.global.IntlNumberFormat, r.getIntlNumberFormatPrototype())
+	nf := r.newIntlNumberFormat(argAt(args, 0), argAt(args, 1))
+	o := &Object{runtime: r}
+	nfo := &numberFormatObject{nf: nf}
+	nfo.class = classObject
+	nfo.val = o
+	nfo.extensible = true
+	o.self = nfo
+	nfo.prototype = proto
+	nfo.init()
+	return o
+}
+
+func argAt(args []Value, idx int) Value {
+	if idx < len(args) {
+		return args[idx]
+	}
+	return _undefined
+}
+
+func (r *Runtime) numberFormatProto_getFormat(call FunctionCall) Value {
+	nfo := r.toNumberFormat(call.This, "format")
+	return r.intlBoundFunc(&nfo.boundFormat, func(call FunctionCall) Value {
+		return newStringValue(nfo.nf.format(r.toIntlDecimal(call.Argument(0))))
+	}, 1)
+}
+
+func (r *Runtime) numberFormatProto_formatToParts(call FunctionCall) Value {
+	nfo := r.toNumberFormat(call.This, "formatToParts")
+	return r.intlPartsToArray(nfo.nf.formatToParts(r.toIntlDecimal(call.Argument(0))))
+}
+
+func (r *Runtime) numberFormatProto_resolvedOptions(call FunctionCall) Value {
+	nfo := r.toNumberFormat(call.This, "resolvedOptions")
+	return nfo.nf.resolvedOptions(r)
+}
+
+func (r *Runtime) createIntlNumberFormatProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
+
+	o._putProp("constructor", r.getIntlNumberFormat(), true, false, true)
+	o.setOwnStr("format", &valueProperty{
+		getterFunc:   r.newNativeFunc(r.numberFormatProto_getFormat, "get format", 0),
+		accessor:     true,
+		configurable: true,
+	}, true)
+	o._putProp("formatToParts", r.newNativeFunc(r.numberFormatProto_formatToParts, "formatToParts", 1), true, false, true)
+	o._putProp("resolvedOptions", r.newNativeFunc(r.numberFormatProto_resolvedOptions, "resolvedOptions", 0), true, false, true)
+
+	o._putSym(SymToStringTag, valueProp(asciiString("Intl.NumberFormat"), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) createIntlNumberFormat(val *Object) objectImpl {
+	o := r.newNativeConstructOnly(val, r.builtin_newNumberFormat, r.getIntlNumberFormatPrototype(), "NumberFormat", 0)
+	o._putProp("supportedLocalesOf", r.newNativeFunc(r.intlSupportedLocalesOf, "supportedLocalesOf", 1), true, false, true)
+
+	return o
+}
+
+func (r *Runtime) getIntlNumberFormatPrototype() *Object {
+	ret := r.global.IntlNumberFormatPrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.IntlNumberFormatPrototype = ret
+		ret.self = r.createIntlNumberFormatProto(ret)
+	}
+	return ret
+}
+
+func (r *Runtime) getIntlNumberFormat() *Object {
+	ret := r.global.IntlNumberFormat
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.IntlNumberFormat = ret
+		ret.self = r.createIntlNumberFormat(ret)
+	}
+	return ret
+}
+
+// numberToLocaleString is used by Number.prototype.toLocaleString() and BigInt.prototype.toLocaleString().
+func (r *Runtime) numberToLocaleString(x *intlDecimal, locales, options Value) Value {
+	var nf *intlNumberFormat
+	if locales == _undefined && options == _undefined {
+		nf = r.intl.numberFormat
+		if nf == nil {
+			nf = r.newIntlNumberFormat(_undefined, _undefined)
+			r.intl.numberFormat = nf
+		}
+	} else {
+		nf = r.newIntlNumberFormat(locales, options)
+	}
+	return newStringValue(nf.format(x))
+}
@@ -0,0 +1,213 @@
+package sobek
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+type intlPluralRules struct {
+	locale string
+	tag    language.Tag
+	typ    string
+	digits intlDigitOptions
+}
+
+var intlPluralForms = [...]struct {
+	form plural.Form
+	name string
+}{
+	{plural.Zero, "zero"},
+	{plural.One, "one"},
+	{plural.Two, "two"},
+	{plural.Few, "few"},
+	{plural.Many, "many"},
+	{plural.Other, "other"},
+}
+
+func intlPluralFormName(form plural.Form) string {
+	for _, f := range intlPluralForms {
+		if f.form == form {
+			return f.name
+		}
+	}
+	return "other"
+}
+
+// newIntlPluralRules implements InitializePluralRules.
+func (r *Runtime) newIntlPluralRules(locales, opts Value) *intlPluralRules {
+	requested := r.intlLocaleList(locales)
+	options := r.intlCoerceOptions(opts)
+	r.intlGetLocaleMatcher(options)
+	pr := &intlPluralRules{}
+	pr.typ = r.intlGetStringOption(options, "type", []string{"cardinal", "ordinal"}, "cardinal")
+	r.intlSetDigitOptions(&pr.digits, options, 0, 3, "standard")
+	locale := r.intlResolveLocale(requested)
+	pr.locale = locale.String()
+	pr.tag = locale.tag()
+	return pr
+}
+
+func (pr *intlPluralRules) rules() *plural.Rules {
+	if pr.typ == "ordinal" {
+		return plural.Ordinal
+	}
+	return plural.Cardinal
+}
+
+// match returns the plural form for the operands (see UTS #35) of the formatted number.
+func (pr *intlPluralRules) match(intPart, fracPart string) plural.Form {
+	if len(intPart) > 9 {
+		// only the last digits are significant for the rules
+		intPart = "1" + intPart[len(intPart)-6:]
+	}
+	i, _ := strconv.Atoi(intPart)
+	trimmed := strings.TrimRight(fracPart, "0")
+	if len(fracPart) > 9 {
+		fracPart, trimmed = fracPart[:9], trimmed[:min(len(trimmed), 9)]
+	}
+	f, _ := strconv.Atoi("0" + fracPart)
+	t, _ := strconv.Atoi("0" + trimmed)
+	return pr.rules().MatchPlural(pr.tag, i, len(fracPart), len(trimmed), f, t)
+}
+
+// selectForm implements ResolvePlural.
+func (pr *intlPluralRules) selectForm(n float64) string {
+	if n != n || math.IsInf(n, 0) {
+		return "other"
+	}
+	intPart, fracPart := pr.digits.format(newIntlDecimalFromFloat(n))
+	return intlPluralFormName(pr.match(intPart, fracPart))
+}
+
+func (pr *intlPluralRules) pluralCategories() []string {
+	var forms [len(intlPluralForms)]bool
+	for i := 0; i < 1000; i++ {
+		forms[pr.match(strconv.Itoa(i), "")] = true
+		for _, frac := range []string{"0", "1", "5", "00", "01", "50"} {
+			forms[pr.match(strconv.Itoa(i), frac)] = true
+		}
+	}
+	forms[pr.match("1000000", "")] = true
+	var categories []string
+	for _, f := range intlPluralForms {
+		if forms[f.form] {
+			categories = append(categories, f.name)
+		}
+	}
+	return categories
+}
+
+func (pr *intlPluralRules) resolvedOptions(r *Runtime) *Object {
+	props := []interface{}{
+		"locale", pr.locale,
+		"type", pr.typ,
+	}
+	props = append(props, pr.digits.resolvedOptions()...)
+	categories := pr.pluralCategories()
+	values := make([]Value, len(categories))
+	for i, c := range categories {
+		values[i] = asciiString(c)
+	}
+	props = append(props, "pluralCategories", r.newArrayValues(values))
+	props = append(props, pr.digits.roundingOptions()...)
+	return r.intlResolvedOptions(props...)
+}
+
+type pluralRulesObject struct {
+	baseObject
+	pr *intlPluralRules
+}
+
+func (r *Runtime) toPluralRules(v Value, method string) *pluralRulesObject {
+	if o, ok := v.(*Object); ok {
+		if pr, ok := o.self.(*pluralRulesObject); ok {
+			return pr
+		}
+	}
+	panic(r.NewTypeError("Method Intl.PluralRules.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+func (r *Runtime) builtin_newPluralRules(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		panic(r.needNew("Intl.PluralRules"))
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.global.IntlPluralRules, r.getIntlPluralRulesPrototype())
+	pr := r.newIntlPluralRules(argAt(args, 0), argAt(args, 1))
+	o := &Object{runtime: r}
+	pro := &pluralRulesObject{pr: pr}
+	pro.class = classObject
+	pro.val = o
+	pro.extensible = true
+	o.self = pro
+	pro.prototype = proto
+	pro.init()
+	return o
+}
+
+func (r *Runtime) pluralRulesProto_select(call FunctionCall) Value {
+	pro := r.toPluralRules(call.This, "select")
+	return asciiString(pro.pr.selectForm(call.Argument(0).ToFloat()))
+}
+
+func (r *Runtime) pluralRulesProto_selectRange(call FunctionCall) Value {
+	pro := r.toPluralRules(call.This, "selectRange")
+	start, end := call.Argument(0), call.Argument(1)
+	if start == _undefined || end == _undefined {
+		panic(r.NewTypeError("start and end are required"))
+	}
+	x, y := start.ToFloat(), end.ToFloat()
+	if x != x || y != y {
+		panic(r.newErrorf(r.getRangeError(), "Invalid number range"))
+	}
+	// The plural range rules of most locales resolve to the category of the end of the range.
+	return asciiString(pro.pr.selectForm(y))
+}
+
+func (r *Runtime) pluralRulesProto_resolvedOptions(call FunctionCall) Value {
+	pro := r.toPluralRules(call.This, "resolvedOptions")
+	return pro.pr.resolvedOptions(r)
+}
+
+func (r *Runtime) createIntlPluralRulesProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
+
+	o._putProp("constructor", r.getIntlPluralRules(), true, false, true)
+	o._putProp("resolvedOptions", r.newNativeFunc(r.pluralRulesProto_resolvedOptions, "resolvedOptions", 0), true, false, true)
+	o._putProp("select", r.newNativeFunc(r.pluralRulesProto_select, "select", 1), true, false, true)
+	o._putProp("selectRange", r.newNativeFunc(r.pluralRulesProto_selectRange, "selectRange", 2), true, false, true)
+
+	o._putSym(SymToStringTag, valueProp(asciiString("Intl.PluralRules"), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) createIntlPluralRules(val *Object) objectImpl {
+	o := r.newNativeConstructOnly(val, r.builtin_newPluralRules, r.getIntlPluralRulesPrototype(), "PluralRules", 0)
+	o._putProp("supportedLocalesOf", r.newNativeFunc(r.intlSupportedLocalesOf, "supportedLocalesOf", 1), true, false, true)
+
+	return o
+}
+
+func (r *Runtime) getIntlPluralRulesPrototype() *Object {
+	ret := r.global.IntlPluralRulesPrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.IntlPluralRulesPrototype = ret
+		ret.self = r.createIntlPluralRulesProto(ret)
+	}
+	return ret
+}
+
+func (r *Runtime) getIntlPluralRules() *Object {
+	ret := r.global.IntlPluralRules
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.IntlPluralRules = ret
+		ret.self = r.createIntlPluralRules(ret)
+	}
+	return ret
+}
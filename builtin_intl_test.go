@@ -0,0 +1,239 @@
+package sobek
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestIntlNumberFormat(t *testing.T) {
+	const SCRIPT = `
+	assert.sameValue(new Intl.NumberFormat("en-US").format(1234567.891), "1,234,567.891");
+	assert.sameValue(new Intl.NumberFormat("de-DE").format(1234567.891), "1.234.567,891");
+	assert.sameValue(new Intl.NumberFormat("en-IN").format(123456789), "12,34,56,789");
+	assert.sameValue(new Intl.NumberFormat("en", {minimumFractionDigits: 2}).format(5), "5.00");
+	assert.sameValue(new Intl.NumberFormat("en", {maximumSignificantDigits: 3}).format(123456), "123,000");
+	assert.sameValue(new Intl.NumberFormat("en", {style: "percent"}).format(0.256), "26%");
+	assert.sameValue(new Intl.NumberFormat("en", {style: "currency", currency: "USD"}).format(-1234.5), "-$1,234.50");
+	assert.sameValue(new Intl.NumberFormat("en", {style: "currency", currency: "USD", currencySign: "accounting"}).format(-1234.5), "($1,234.50)");
+	assert.sameValue(new Intl.NumberFormat("de", {style: "currency", currency: "EUR"}).format(1234.5), "1.234,50\u00a0€");
+	assert.sameValue(new Intl.NumberFormat("ja", {style: "currency", currency: "JPY"}).format(1234.5), "￥1,235");
+	assert.sameValue(new Intl.NumberFormat("en", {style: "currency", currency: "EUR", currencyDisplay: "code"}).format(1), "EUR\u00a01.00");
+	assert.sameValue(new Intl.NumberFormat("en", {notation: "scientific"}).format(123456), "1.235E5");
+	assert.sameValue(new Intl.NumberFormat("en", {notation: "engineering"}).format(123456), "123.456E3");
+	assert.sameValue(new Intl.NumberFormat("en", {signDisplay: "always"}).format(5), "+5");
+	assert.sameValue(new Intl.NumberFormat("en", {signDisplay: "exceptZero"}).format(0), "0");
+	assert.sameValue(new Intl.NumberFormat("en", {useGrouping: false}).format(12345), "12345");
+	assert.sameValue(new Intl.NumberFormat("en", {roundingMode: "floor", maximumFractionDigits: 0}).format(2.7), "2");
+	assert.sameValue(new Intl.NumberFormat("en", {minimumFractionDigits: 2, maximumFractionDigits: 2, roundingIncrement: 5}).format(1.234), "1.25");
+	assert.sameValue(new Intl.NumberFormat("en").format(12345678901234567890n), "12,345,678,901,234,567,890");
+	assert.sameValue(new Intl.NumberFormat("en").format("1.0000000000000000001"), "1");
+	assert.sameValue(new Intl.NumberFormat("en-u-nu-arab").format(123), "١٢٣");
+	assert.sameValue(new Intl.NumberFormat("en").format(NaN), "NaN");
+	assert.sameValue(new Intl.NumberFormat("en").format(-Infinity), "-∞");
+
+	const parts = new Intl.NumberFormat("en").formatToParts(-1234.5);
+	assert.sameValue(parts.map(p => p.type).join(), "minusSign,integer,group,integer,decimal,fraction");
+	assert.sameValue(parts.map(p => p.value).join(""), "-1,234.5");
+
+	const opts = new Intl.NumberFormat("en", {style: "currency", currency: "usd"}).resolvedOptions();
+	assert.sameValue(opts.locale, "en");
+	assert.sameValue(opts.currency, "USD");
+	assert.sameValue(opts.minimumFractionDigits, 2);
+	assert.sameValue(opts.maximumFractionDigits, 2);
+	assert.sameValue(opts.useGrouping, "auto");
+
+	assert.throws(TypeError, () => new Intl.NumberFormat("en", {style: "currency"}));
+	assert.throws(RangeError, () => new Intl.NumberFormat("en", {style: "currency", currency: "EURO"}));
+	assert.throws(RangeError, () => new Intl.NumberFormat("en", {maximumFractionDigits: 101}));
+	assert.throws(TypeError, () => Intl.NumberFormat.prototype.format);
+	assert.throws(RangeError, () => new Intl.NumberFormat("en", {notation: "compact"}), "notation: compact");
+	assert.throws(RangeError, () => new Intl.NumberFormat("en", {style: "unit", unit: "meter"}), "style: unit");
+	assert.throws(RangeError, () => new Intl.NumberFormat("en", {style: "currency", currency: "USD", currencyDisplay: "name"}), "currencyDisplay: name");
+
+	const nf = Intl.NumberFormat("en");
+	assert(nf instanceof Intl.NumberFormat);
+	assert.sameValue(nf.format, nf.format);
+	assert.sameValue([1, 2].map(nf.format).join(), "1,2");
+	assert.sameValue(Object.prototype.toString.call(nf), "[object Intl.NumberFormat]");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestIntlDateTimeFormat(t *testing.T) {
+	const SCRIPT = `
+	const d = new Date(Date.UTC(2006, 0, 2, 15, 4, 5, 123));
+	function fmt(locale, options) {
+		return new Intl.DateTimeFormat(locale, Object.assign({timeZone: "UTC"}, options)).format(d);
+	}
+	assert.sameValue(fmt("en-US"), "1/2/2006");
+	assert.sameValue(fmt("en-US", {dateStyle: "full", timeStyle: "long"}), "Monday, January 2, 2006 at 3:04:05 PM UTC");
+	assert.sameValue(fmt("de", {dateStyle: "medium", timeStyle: "short"}), "02.01.2006, 15:04");
+	assert.sameValue(fmt("es", {dateStyle: "full"}), "lunes, 2 de enero de 2006");
+	assert.sameValue(fmt("en", {year: "numeric", timeZoneName: "short"}), "2006, UTC");
+	assert.sameValue(fmt("de", {month: "long", day: "numeric", timeZoneName: "short"}), "2. Januar um UTC");
+	assert.sameValue(fmt("pl", {month: "long", day: "numeric"}), "2 stycznia");
+	assert.sameValue(fmt("ru", {month: "long", year: "numeric"}), "январь 2006 г.");
+	assert.sameValue(fmt("nl", {weekday: "short", day: "numeric", month: "short"}), "ma 2 jan");
+	assert.sameValue(fmt("en", {weekday: "long", year: "numeric", month: "long", day: "numeric", hour: "2-digit", minute: "2-digit"}),
+		"Monday, January 2, 2006 at 03:04 PM");
+	assert.sameValue(fmt("en", {year: "2-digit", month: "2-digit", day: "2-digit"}), "01/02/06");
+	assert.sameValue(fmt("en", {minute: "2-digit", second: "2-digit", fractionalSecondDigits: 3}), "04:05.123");
+	assert.sameValue(fmt("en", {hourCycle: "h11", hour: "numeric"}), "3 PM");
+	assert.sameValue(fmt("en", {timeZone: "America/New_York", hour: "numeric", minute: "numeric", timeZoneName: "short"}), "10:04 AM EST");
+	assert.sameValue(fmt("en", {timeZone: "Europe/Berlin", hour: "numeric", minute: "numeric", timeZoneName: "short"}), "4:04 PM GMT+1");
+	assert.sameValue(fmt("en", {timeZone: "Asia/Kolkata", timeZoneName: "longOffset"}), "1/2/2006, GMT+05:30");
+	assert.sameValue(fmt("en", {timeZone: "+05:30", hour: "numeric", minute: "numeric"}), "8:34 PM");
+	assert.sameValue(new Intl.DateTimeFormat("en", {timeZone: "UTC"}).format(new Date(Date.UTC(-50, 0, 2))), "1/2/51");
+
+	const parts = new Intl.DateTimeFormat("en", {timeZone: "UTC", hour: "numeric", minute: "numeric"}).formatToParts(d);
+	assert.sameValue(parts.map(p => p.type).join(), "hour,literal,minute,literal,dayPeriod");
+
+	let opts = new Intl.DateTimeFormat("en-u-hc-h23", {timeZone: "Etc/GMT", hour: "numeric"}).resolvedOptions();
+	assert.sameValue(opts.locale, "en-u-hc-h23");
+	assert.sameValue(opts.timeZone, "UTC");
+	assert.sameValue(opts.hourCycle, "h23");
+	assert.sameValue(opts.hour12, false);
+	assert.sameValue(opts.hour, "2-digit");
+
+	opts = new Intl.DateTimeFormat("de-u-nu-arab", {timeZone: "UTC"}).resolvedOptions();
+	assert.sameValue(opts.locale, "de-u-nu-arab");
+	assert.sameValue(opts.numberingSystem, "arab");
+	assert.sameValue(opts.calendar, "gregory");
+	assert.sameValue(opts.year, "numeric");
+	assert.sameValue(opts.hourCycle, undefined);
+
+	// only the Gregorian calendar is supported, the other ones are ignored
+	assert.sameValue(new Intl.DateTimeFormat("en", {calendar: "hebrew"}).resolvedOptions().calendar, "gregory");
+	assert.sameValue(new Intl.DateTimeFormat("en-u-ca-hebrew").resolvedOptions().locale, "en");
+
+	opts = new Intl.DateTimeFormat("en", {dateStyle: "short"}).resolvedOptions();
+	assert.sameValue(opts.dateStyle, "short");
+	assert.sameValue(opts.year, undefined);
+
+	assert.throws(RangeError, () => new Intl.DateTimeFormat("en", {timeZone: "Mars/Base"}));
+	assert.throws(TypeError, () => new Intl.DateTimeFormat("en", {dateStyle: "short", year: "numeric"}));
+	assert.throws(RangeError, () => new Intl.DateTimeFormat("en", {hour: "3-digit"}));
+	assert.throws(RangeError, () => new Intl.DateTimeFormat("en").format(NaN));
+	assert.throws(TypeError, () => Intl.DateTimeFormat.prototype.formatToParts.call({}, 0));
+	assert.sameValue(typeof Intl.DateTimeFormat().format(), "string");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestIntlCollator(t *testing.T) {
+	const SCRIPT = `
+	assert.sameValue(["b", "a", "ä", "A"].sort(new Intl.Collator("de").compare).join(), "a,A,ä,b");
+	assert.sameValue(["b", "a", "ä", "A"].sort(new Intl.Collator("sv").compare).join(), "a,A,b,ä");
+	assert.sameValue(new Intl.Collator("en", {sensitivity: "base"}).compare("a", "Á"), 0);
+	assert.sameValue(new Intl.Collator("en", {sensitivity: "accent"}).compare("a", "á"), -1);
+	assert.sameValue(["10", "9", "2"].sort(new Intl.Collator("en", {numeric: true}).compare).join(), "2,9,10");
+	assert.sameValue(["10", "9", "2"].sort(new Intl.Collator("en-u-kn").compare).join(), "2,9,10");
+
+	const opts = new Intl.Collator("en-u-kn").resolvedOptions();
+	assert.sameValue(opts.locale, "en-u-kn");
+	assert.sameValue(opts.numeric, true);
+	assert.sameValue(opts.usage, "sort");
+	assert.sameValue(opts.sensitivity, "variant");
+
+	assert.sameValue("a".localeCompare("B"), -1);
+	assert.sameValue("ä".localeCompare("z", "sv"), 1);
+	assert.sameValue("ä".localeCompare("z", "de"), -1);
+	assert.sameValue("a".localeCompare("A", "en", {sensitivity: "base"}), 0);
+	assert.sameValue("A\u030a".localeCompare("\u00c5"), 0);
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestIntlPluralRules(t *testing.T) {
+	const SCRIPT = `
+	assert.sameValue(new Intl.PluralRules("en").select(0), "other");
+	assert.sameValue(new Intl.PluralRules("en").select(1), "one");
+	assert.sameValue(new Intl.PluralRules("en", {minimumFractionDigits: 1}).select(1), "other");
+	assert.sameValue(new Intl.PluralRules("en", {type: "ordinal"}).select(2), "two");
+	assert.sameValue(new Intl.PluralRules("en", {type: "ordinal"}).select(13), "other");
+	assert.sameValue(new Intl.PluralRules("ru").select(3), "few");
+	assert.sameValue(new Intl.PluralRules("ru").select(5), "many");
+	assert.sameValue(new Intl.PluralRules("ar").resolvedOptions().pluralCategories.join(), "zero,one,two,few,many,other");
+	assert.sameValue(new Intl.PluralRules("en").resolvedOptions().pluralCategories.join(), "one,other");
+	assert.throws(TypeError, () => Intl.PluralRules());
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestIntlLocales(t *testing.T) {
+	const SCRIPT = `
+	assert.sameValue(Intl.getCanonicalLocales(["EN-us", "de-DE-u-co-phonebk", "en-US"]).join(), "en-US,de-DE-u-co-phonebk");
+	assert.sameValue(Intl.NumberFormat.supportedLocalesOf(["de-AT", "xx", "en"]).join(), "de-AT,en");
+	assert.throws(RangeError, () => Intl.getCanonicalLocales("en_US"));
+	assert.throws(TypeError, () => Intl.getCanonicalLocales([1]));
+	assert.sameValue(Object.prototype.toString.call(Intl), "[object Intl]");
+	assert.sameValue(new Intl.NumberFormat().resolvedOptions().locale, "en-US");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestIntlDefaultLocale(t *testing.T) {
+	vm := New()
+	vm.SetDefaultLocale(language.German)
+	res, err := vm.RunString(`
+	const d = new Date(Date.UTC(2006, 0, 2, 15, 4, 5));
+	[
+		(1234.5).toLocaleString(),
+		d.toLocaleString(undefined, {timeZone: "UTC"}),
+		new Intl.Collator().resolvedOptions().locale,
+		new Intl.NumberFormat("xx").resolvedOptions().locale,
+	].join("|");
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := res.String(); s != "1.234,5|2.1.2006, 15:04:05|de|de" {
+		t.Fatal(s)
+	}
+
+	vm.SetAvailableLocales(language.English, language.French)
+	res, err = vm.RunString(`
+	[
+		new Intl.NumberFormat("fr-CA").resolvedOptions().locale,
+		new Intl.NumberFormat("de").resolvedOptions().locale,
+		Intl.NumberFormat.supportedLocalesOf(["de", "en-GB"]).join(),
+	].join("|");
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := res.String(); s != "fr|de|en-GB" {
+		t.Fatal(s)
+	}
+}
+
+func TestToLocaleString(t *testing.T) {
+	const SCRIPT = `
+	const d = new Date(Date.UTC(2006, 0, 2, 15, 4, 5));
+	assert.sameValue(d.toLocaleString("en-US", {timeZone: "UTC"}), "1/2/2006, 3:04:05 PM");
+	assert.sameValue(d.toLocaleString("en-GB", {timeZone: "UTC"}), "02/01/2006, 15:04:05");
+	assert.sameValue(d.toLocaleDateString("en-US", {timeZone: "UTC"}), "1/2/2006");
+	assert.sameValue(d.toLocaleTimeString("en-US", {timeZone: "UTC"}), "3:04:05 PM");
+	assert.sameValue(d.toLocaleTimeString("ko", {timeZone: "UTC"}), "오후 3:04:05");
+	assert.sameValue(d.toLocaleString("ja", {timeZone: "UTC"}), "2006/1/2 15:04:05");
+	assert.throws(TypeError, () => d.toLocaleDateString("en", {timeStyle: "short"}));
+	assert.sameValue(new Date(NaN).toLocaleString(), "Invalid Date");
+	// the default locale is en-US
+	assert.sameValue(d.toLocaleString(undefined, {timeZone: "UTC"}), "1/2/2006, 3:04:05 PM");
+	assert.sameValue(d.toLocaleDateString([], {timeZone: "UTC"}), "1/2/2006");
+	assert.sameValue(d.toLocaleString("en-US", {timeZone: "UTC", year: "numeric", month: "2-digit", day: "2-digit",
+		hour: "2-digit", minute: "2-digit", second: "2-digit", hourCycle: "h23"}), "01/02/2006, 15:04:05", "the format used before Intl");
+	assert.sameValue(d.toLocaleString("en", {timeZone: "UTC", calendar: "gregory"}), "1/2/2006, 3:04:05 PM");
+	assert.sameValue(d.toLocaleString("en-u-ca-gregory", {timeZone: "UTC"}), "1/2/2006, 3:04:05 PM");
+	assert.throws(RangeError, () => d.toLocaleString("en", {era: "short"}));
+	assert.throws(RangeError, () => d.toLocaleTimeString("en", {hour: "numeric", dayPeriod: "long"}));
+
+	assert.sameValue((1234.5).toLocaleString(), "1,234.5");
+	assert.sameValue((1234.5).toLocaleString("de"), "1.234,5");
+	assert.sameValue((12345n).toLocaleString("en-IN"), "12,345");
+	assert.sameValue([1234.5, "x", 6789].toLocaleString("de"), "1.234,5,x,6.789");
+	assert.sameValue(new Float64Array([0.5]).toLocaleString("en", {style: "percent"}), "50%");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
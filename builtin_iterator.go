@@ -0,0 +1,642 @@
+package sobek
+
+import (
+	"iter"
+	"math"
+	"runtime"
+
+	"github.com/grafana/sobek/unistring"
+)
+
+// iteratorHelperObject implements the objects returned by the Iterator.prototype methods (map, filter, etc.).
+// They behave like generators with a closure instead of the body.
+type iteratorHelperObject struct {
+	baseObject
+	state generatorState
+
+	// next produces the next value, false means the iteration is complete.
+	next func() (Value, bool)
+	// close is called when return() is called before the iteration is complete. It should close the
+	// underlying iterator(s).
+	close func()
+}
+
+func (r *Runtime) newIteratorHelper(next func() (Value, bool), close func()) *Object {
+	o := &Object{runtime: r}
+
+	ih := &iteratorHelperObject{
+		state: genStateSuspendedStart,
+		next:  next,
+		close: close,
+	}
+	ih.class = classObject
+	ih.val = o
+	ih.extensible = true
+	o.self = ih
+	ih.prototype = r.getIteratorHelperPrototype()
+	ih.init()
+
+	return o
+}
+
+func (ih *iteratorHelperObject) step() Value {
+	r := ih.val.runtime
+	switch ih.state {
+	case genStateExecuting:
+		panic(r.NewTypeError("Illegal iterator helper state"))
+	case genStateCompleted:
+		return r.createIterResultObject(_undefined, true)
+	}
+	ih.state = genStateExecuting
+	var value Value
+	var ok bool
+	if ex := r.vm.try(func() {
+		value, ok = ih.next()
+	}); ex != nil {
+		ih.state = genStateCompleted
+		panic(ex)
+	}
+	if !ok {
+		ih.state = genStateCompleted
+		return r.createIterResultObject(_undefined, true)
+	}
+	ih.state = genStateSuspendedYield
+	return r.createIterResultObject(value, false)
+}
+
+func (ih *iteratorHelperObject) doReturn() Value {
+	r := ih.val.runtime
+	switch ih.state {
+	case genStateExecuting:
+		panic(r.NewTypeError("Illegal iterator helper state"))
+	case genStateCompleted:
+		return r.createIterResultObject(_undefined, true)
+	case genStateSuspendedStart:
+		ih.state = genStateCompleted
+	default:
+		ih.state = genStateExecuting
+	}
+	ex := r.vm.try(ih.close)
+	ih.state = genStateCompleted
+	if ex != nil {
+		panic(ex)
+	}
+	return r.createIterResultObject(_undefined, true)
+}
+
+// getIteratorDirect implements GetIteratorDirect.
+func (r *Runtime) getIteratorDirect(obj *Object) *iteratorRecord {
+	next, _ := assertCallable(nilSafe(obj.self.getStr("next", nil)))
+	return &iteratorRecord{
+		iterator: obj,
+		next:     next,
+	}
+}
+
+// getIteratorFlattenable implements GetIteratorFlattenable. If iterateStrings is false, primitives are rejected.
+func (r *Runtime) getIteratorFlattenable(obj Value, iterateStrings bool) *iteratorRecord {
+	if _, ok := obj.(*Object); !ok {
+		if _, ok := obj.(String); !ok || !iterateStrings {
+			panic(r.NewTypeError("%s is not an object", obj.String()))
+		}
+	}
+	var iterator Value
+	if method := toMethod(r.getV(obj, SymIterator)); method != nil {
+		iterator = method(FunctionCall{This: obj})
+	} else {
+		iterator = obj
+	}
+	iterObj, ok := iterator.(*Object)
+	if !ok {
+		panic(r.NewTypeError("%s is not an object", iterator.String()))
+	}
+	return r.getIteratorDirect(iterObj)
+}
+
+// stepValue implements IteratorStepValue. It returns false if the iteration is complete. Exceptions are
+// propagated without closing the iterator.
+func (ir *iteratorRecord) stepValue() (Value, bool) {
+	r := ir.iterator.runtime
+	if ir.next == nil {
+		panic(r.NewTypeError("iterator.next is missing or not a function"))
+	}
+	res := r.toObject(ir.next(FunctionCall{This: ir.iterator}))
+	if iteratorComplete(res) {
+		return nil, false
+	}
+	return iteratorValue(res), true
+}
+
+// closeAndThrow implements IteratorClose() with a throw completion: the iterator is closed ignoring any
+// errors and err is re-thrown.
+func (ir *iteratorRecord) closeAndThrow(err interface{}) {
+	_ = tryFunc(ir.returnIter)
+	panic(err)
+}
+
+// closeOnAbrupt calls f closing the iterator if it throws (i.e. IfAbruptCloseIterator).
+func (ir *iteratorRecord) closeOnAbrupt(f func()) {
+	if ex := tryFunc(f); ex != nil {
+		ir.closeAndThrow(ex)
+	}
+}
+
+func (r *Runtime) toIteratorObject(v Value, method string) *Object {
+	if obj, ok := v.(*Object); ok {
+		return obj
+	}
+	panic(r.NewTypeError("Iterator.prototype.%s called on non-object", method))
+}
+
+// toIteratorCallback returns the callback argument of an Iterator.prototype method. If it's not callable,
+// the iterator is closed and a TypeError is thrown.
+func (r *Runtime) toIteratorCallback(obj *Object, arg Value) func(FunctionCall) Value {
+	if f, ok := assertCallable(arg); ok {
+		return f
+	}
+	iterated := &iteratorRecord{iterator: obj}
+	iterated.closeAndThrow(r.NewTypeError("%s is not a function", arg.String()))
+	panic("unreachable")
+}
+
+// toIteratorLimit converts the limit argument of take() and drop(). If the conversion fails, the iterator
+// is closed.
+func (r *Runtime) toIteratorLimit(obj *Object, arg Value) (limit float64) {
+	iterated := &iteratorRecord{iterator: obj}
+	iterated.closeOnAbrupt(func() {
+		limit = arg.ToFloat()
+	})
+	if math.IsNaN(limit) {
+		iterated.closeAndThrow(r.newError(r.getRangeError(), "limit must be a number"))
+	}
+	limit = math.Trunc(limit)
+	if limit < 0 {
+		iterated.closeAndThrow(r.newError(r.getRangeError(), "limit must be positive"))
+	}
+	return
+}
+
+func (r *Runtime) iteratorProto_map(call FunctionCall) Value {
+	o := r.toIteratorObject(call.This, "map")
+	mapper := r.toIteratorCallback(o, call.Argument(0))
+	iterated := r.getIteratorDirect(o)
+	var counter int64
+	return r.newIteratorHelper(func() (Value, bool) {
+		value, ok := iterated.stepValue()
+		if !ok {
+			return nil, false
+		}
+		var mapped Value
+		iterated.closeOnAbrupt(func() {
+			mapped = mapper(FunctionCall{This: _undefined, Arguments: []Value{value, valueInt(counter)}})
+		})
+		counter++
+		return mapped, true
+	}, iterated.returnIter)
+}
+
+func (r *Runtime) iteratorProto_filter(call FunctionCall) Value {
+	o := r.toIteratorObject(call.This, "filter")
+	predicate := r.toIteratorCallback(o, call.Argument(0))
+	iterated := r.getIteratorDirect(o)
+	var counter int64
+	return r.newIteratorHelper(func() (Value, bool) {
+		for {
+			value, ok := iterated.stepValue()
+			if !ok {
+				return nil, false
+			}
+			var selected bool
+			iterated.closeOnAbrupt(func() {
+				selected = predicate(FunctionCall{This: _undefined, Arguments: []Value{value, valueInt(counter)}}).ToBoolean()
+			})
+			counter++
+			if selected {
+				return value, true
+			}
+		}
+	}, iterated.returnIter)
+}
+
+func (r *Runtime) iteratorProto_take(call FunctionCall) Value {
+	o := r.toIteratorObject(call.This, "take")
+	remaining := r.toIteratorLimit(o, call.Argument(0))
+	iterated := r.getIteratorDirect(o)
+	return r.newIteratorHelper(func() (Value, bool) {
+		if remaining == 0 {
+			iterated.returnIter()
+			return nil, false
+		}
+		if !math.IsInf(remaining, 1) {
+			remaining--
+		}
+		return iterated.stepValue()
+	}, iterated.returnIter)
+}
+
+func (r *Runtime) iteratorProto_drop(call FunctionCall) Value {
+	o := r.toIteratorObject(call.This, "drop")
+	remaining := r.toIteratorLimit(o, call.Argument(0))
+	iterated := r.getIteratorDirect(o)
+	return r.newIteratorHelper(func() (Value, bool) {
+		for remaining > 0 {
+			if !math.IsInf(remaining, 1) {
+				remaining--
+			}
+			if _, ok := iterated.stepValue(); !ok {
+				return nil, false
+			}
+		}
+		return iterated.stepValue()
+	}, iterated.returnIter)
+}
+
+func (r *Runtime) iteratorProto_flatMap(call FunctionCall) Value {
+	o := r.toIteratorObject(call.This, "flatMap")
+	mapper := r.toIteratorCallback(o, call.Argument(0))
+	iterated := r.getIteratorDirect(o)
+	var counter int64
+	var inner *iteratorRecord
+	return r.newIteratorHelper(func() (Value, bool) {
+		for {
+			if inner == nil {
+				value, ok := iterated.stepValue()
+				if !ok {
+					return nil, false
+				}
+				iterated.closeOnAbrupt(func() {
+					mapped := mapper(FunctionCall{This: _undefined, Arguments: []Value{value, valueInt(counter)}})
+					inner = r.getIteratorFlattenable(mapped, false)
+				})
+				counter++
+			}
+			var value Value
+			var ok bool
+			iterated.closeOnAbrupt(func() {
+				value, ok = inner.stepValue()
+			})
+			if ok {
+				return value, true
+			}
+			inner = nil
+		}
+	}, func() {
+		if inner != nil {
+			iterated.closeOnAbrupt(inner.returnIter)
+		}
+		iterated.returnIter()
+	})
+}
+
+func (r *Runtime) iteratorProto_reduce(call FunctionCall) Value {
+	o := r.toIteratorObject(call.This, "reduce")
+	reducer := r.toIteratorCallback(o, call.Argument(0))
+	iterated := r.getIteratorDirect(o)
+	var accumulator Value
+	var counter int64
+	if len(call.Arguments) < 2 {
+		value, ok := iterated.stepValue()
+		if !ok {
+			panic(r.NewTypeError("Reduce of empty iterator with no initial value"))
+		}
+		accumulator = value
+		counter = 1
+	} else {
+		accumulator = call.Arguments[1]
+	}
+	for {
+		value, ok := iterated.stepValue()
+		if !ok {
+			return accumulator
+		}
+		iterated.closeOnAbrupt(func() {
+			accumulator = reducer(FunctionCall{This: _undefined, Arguments: []Value{accumulator, value, valueInt(counter)}})
+		})
+		counter++
+	}
+}
+
+func (r *Runtime) iteratorProto_toArray(call FunctionCall) Value {
+	o := r.toIteratorObject(call.This, "toArray")
+	iterated := r.getIteratorDirect(o)
+	var items []Value
+	for {
+		value, ok := iterated.stepValue()
+		if !ok {
+			return r.newArrayValues(items)
+		}
+		items = append(items, value)
+	}
+}
+
+// iterateWithCallback calls f for each value of the iterator until it returns false. If f throws,
+// the iterator is closed.
+func (r *Runtime) iterateWithCallback(iterated *iteratorRecord, f func(value Value, counter int64) bool) {
+	var counter int64
+	for {
+		value, ok := iterated.stepValue()
+		if !ok {
+			return
+		}
+		cont := true
+		iterated.closeOnAbrupt(func() {
+			cont = f(value, counter)
+		})
+		if !cont {
+			iterated.returnIter()
+			return
+		}
+		counter++
+	}
+}
+
+func (r *Runtime) iteratorProto_forEach(call FunctionCall) Value {
+	o := r.toIteratorObject(call.This, "forEach")
+	fn := r.toIteratorCallback(o, call.Argument(0))
+	r.iterateWithCallback(r.getIteratorDirect(o), func(value Value, counter int64) bool {
+		fn(FunctionCall{This: _undefined, Arguments: []Value{value, valueInt(counter)}})
+		return true
+	})
+	return _undefined
+}
+
+func (r *Runtime) iteratorProto_some(call FunctionCall) Value {
+	o := r.toIteratorObject(call.This, "some")
+	predicate := r.toIteratorCallback(o, call.Argument(0))
+	result := false
+	r.iterateWithCallback(r.getIteratorDirect(o), func(value Value, counter int64) bool {
+		result = predicate(FunctionCall{This: _undefined, Arguments: []Value{value, valueInt(counter)}}).ToBoolean()
+		return !result
+	})
+	return r.toBoolean(result)
+}
+
+func (r *Runtime) iteratorProto_every(call FunctionCall) Value {
+	o := r.toIteratorObject(call.This, "every")
+	predicate := r.toIteratorCallback(o, call.Argument(0))
+	result := true
+	r.iterateWithCallback(r.getIteratorDirect(o), func(value Value, counter int64) bool {
+		result = predicate(FunctionCall{This: _undefined, Arguments: []Value{value, valueInt(counter)}}).ToBoolean()
+		return result
+	})
+	return r.toBoolean(result)
+}
+
+func (r *Runtime) iteratorProto_find(call FunctionCall) Value {
+	o := r.toIteratorObject(call.This, "find")
+	predicate := r.toIteratorCallback(o, call.Argument(0))
+	var result Value = _undefined
+	r.iterateWithCallback(r.getIteratorDirect(o), func(value Value, counter int64) bool {
+		if predicate(FunctionCall{This: _undefined, Arguments: []Value{value, valueInt(counter)}}).ToBoolean() {
+			result = value
+			return false
+		}
+		return true
+	})
+	return result
+}
+
+func (r *Runtime) iterProto_dispose(call FunctionCall) Value {
+	if ret := toMethod(r.getV(call.This, asciiString("return"))); ret != nil {
+		ret(FunctionCall{This: call.This})
+	}
+	return _undefined
+}
+
+// setterThatIgnoresPrototypeProperties implements SetterThatIgnoresPrototypeProperties.
+func (r *Runtime) setterThatIgnoresPrototypeProperties(this Value, home *Object, p unistring.String, v Value) {
+	obj, ok := this.(*Object)
+	if !ok {
+		panic(r.NewTypeError("Cannot set property %s on a non-object", p))
+	}
+	if obj == home {
+		panic(r.NewTypeError("Cannot assign to read only property '%s' of object", p))
+	}
+	if obj.self.getOwnPropStr(p) == nil {
+		createDataPropertyOrThrow(obj, stringValueFromRaw(p), v)
+	} else {
+		obj.self.setOwnStr(p, v, true)
+	}
+}
+
+func (r *Runtime) iteratorProto_getConstructor(FunctionCall) Value {
+	return r.getIteratorConstructor()
+}
+
+func (r *Runtime) iteratorProto_setConstructor(call FunctionCall) Value {
+	r.setterThatIgnoresPrototypeProperties(call.This, r.getIteratorPrototype(), "constructor", call.Argument(0))
+	return _undefined
+}
+
+func (r *Runtime) iteratorProto_getToStringTag(FunctionCall) Value {
+	return asciiString(classIterator)
+}
+
+func (r *Runtime) iteratorProto_setToStringTag(call FunctionCall) Value {
+	obj, ok := call.This.(*Object)
+	if !ok {
+		panic(r.NewTypeError("Cannot set property Symbol.toStringTag on a non-object"))
+	}
+	if obj == r.getIteratorPrototype() {
+		panic(r.NewTypeError("Cannot assign to read only property 'Symbol(Symbol.toStringTag)' of object"))
+	}
+	if obj.self.getOwnPropSym(SymToStringTag) == nil {
+		obj.self.defineOwnPropertySym(SymToStringTag, PropertyDescriptor{
+			Value:        call.Argument(0),
+			Writable:     FLAG_TRUE,
+			Enumerable:   FLAG_TRUE,
+			Configurable: FLAG_TRUE,
+		}, true)
+	} else {
+		obj.self.setOwnSym(SymToStringTag, call.Argument(0), true)
+	}
+	return _undefined
+}
+
+func (r *Runtime) iterator_from(call FunctionCall) Value {
+	iterated := r.getIteratorFlattenable(call.Argument(0), true)
+	if r.getIteratorConstructor().self.hasInstance(iterated.iterator) {
+		return iterated.iterator
+	}
+	o := &Object{runtime: r}
+	w := &wrapForValidIteratorObject{
+		iterated: iterated,
+	}
+	w.class = classObject
+	w.val = o
+	w.extensible = true
+	o.self = w
+	w.prototype = r.getWrapForValidIteratorPrototype()
+	w.init()
+	return o
+}
+
+type wrapForValidIteratorObject struct {
+	baseObject
+	iterated *iteratorRecord
+}
+
+func (r *Runtime) toWrapForValidIteratorObject(v Value, method string) *wrapForValidIteratorObject {
+	if obj, ok := v.(*Object); ok {
+		if w, ok := obj.self.(*wrapForValidIteratorObject); ok {
+			return w
+		}
+	}
+	panic(r.NewTypeError("Method %%WrapForValidIteratorPrototype%%.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+func (r *Runtime) wrapForValidIteratorProto_next(call FunctionCall) Value {
+	iterated := r.toWrapForValidIteratorObject(call.This, "next").iterated
+	if iterated.next == nil {
+		panic(r.NewTypeError("iterator.next is missing or not a function"))
+	}
+	return iterated.next(FunctionCall{This: iterated.iterator})
+}
+
+func (r *Runtime) wrapForValidIteratorProto_return(call FunctionCall) Value {
+	iterator := r.toWrapForValidIteratorObject(call.This, "return").iterated.iterator
+	retMethod := toMethod(iterator.self.getStr("return", nil))
+	if retMethod == nil {
+		return r.createIterResultObject(_undefined, true)
+	}
+	return retMethod(FunctionCall{This: iterator})
+}
+
+func (r *Runtime) toIteratorHelperObject(v Value, method string) *iteratorHelperObject {
+	if obj, ok := v.(*Object); ok {
+		if ih, ok := obj.self.(*iteratorHelperObject); ok {
+			return ih
+		}
+	}
+	panic(r.NewTypeError("Method Iterator Helper.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+func (r *Runtime) iteratorHelperProto_next(call FunctionCall) Value {
+	return r.toIteratorHelperObject(call.This, "next").step()
+}
+
+func (r *Runtime) iteratorHelperProto_return(call FunctionCall) Value {
+	return r.toIteratorHelperObject(call.This, "return").doReturn()
+}
+
+func (r *Runtime) builtin_Iterator(args []Value, newTarget *Object) *Object {
+	if newTarget == nil || newTarget == r.getIteratorConstructor() {
+		panic(r.NewTypeError("Abstract class Iterator not directly constructable"))
+	}
+	return r.newBaseObject(r.getPrototypeFromCtor(newTarget, r.getIteratorConstructor(), r.getIteratorPrototype()), classObject).val
+}
+
+func (r *Runtime) createIterProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
+
+	o.setOwnStr("constructor", &valueProperty{
+		getterFunc:   r.newNativeFunc(r.iteratorProto_getConstructor, "get constructor", 0),
+		setterFunc:   r.newNativeFunc(r.iteratorProto_setConstructor, "set constructor", 1),
+		accessor:     true,
+		configurable: true,
+	}, true)
+	o._putProp("drop", r.newNativeFunc(r.iteratorProto_drop, "drop", 1), true, false, true)
+	o._putProp("every", r.newNativeFunc(r.iteratorProto_every, "every", 1), true, false, true)
+	o._putProp("filter", r.newNativeFunc(r.iteratorProto_filter, "filter", 1), true, false, true)
+	o._putProp("find", r.newNativeFunc(r.iteratorProto_find, "find", 1), true, false, true)
+	o._putProp("flatMap", r.newNativeFunc(r.iteratorProto_flatMap, "flatMap", 1), true, false, true)
+	o._putProp("forEach", r.newNativeFunc(r.iteratorProto_forEach, "forEach", 1), true, false, true)
+	o._putProp("map", r.newNativeFunc(r.iteratorProto_map, "map", 1), true, false, true)
+	o._putProp("reduce", r.newNativeFunc(r.iteratorProto_reduce, "reduce", 1), true, false, true)
+	o._putProp("some", r.newNativeFunc(r.iteratorProto_some, "some", 1), true, false, true)
+	o._putProp("take", r.newNativeFunc(r.iteratorProto_take, "take", 1), true, false, true)
+	o._putProp("toArray", r.newNativeFunc(r.iteratorProto_toArray, "toArray", 0), true, false, true)
+
+	o._putSym(SymIterator, valueProp(r.newNativeFunc(r.returnThis, "[Symbol.iterator]", 0), true, false, true))
+	o._putSym(SymDispose, valueProp(r.newNativeFunc(r.iterProto_dispose, "[Symbol.dispose]", 0), true, false, true))
+	o._putSym(SymToStringTag, &valueProperty{
+		getterFunc:   r.newNativeFunc(r.iteratorProto_getToStringTag, "get [Symbol.toStringTag]", 0),
+		setterFunc:   r.newNativeFunc(r.iteratorProto_setToStringTag, "set [Symbol.toStringTag]", 1),
+		accessor:     true,
+		configurable: true,
+	})
+	return o
+}
+
+func (r *Runtime) getIteratorPrototype() *Object {
+	var o *Object
+	if o = r.global.IteratorPrototype; o == nil {
+		o = &Object{runtime: r}
+		r.global.IteratorPrototype = o
+		o.self = r.createIterProto(o)
+	}
+	return o
+}
+
+func (r *Runtime) createIteratorHelperProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.getIteratorPrototype(), classObject)
+
+	o._putProp("next", r.newNativeFunc(r.iteratorHelperProto_next, "next", 0), true, false, true)
+	o._putProp("return", r.newNativeFunc(r.iteratorHelperProto_return, "return", 0), true, false, true)
+	o._putSym(SymToStringTag, valueProp(asciiString(classIteratorHelper), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) getIteratorHelperPrototype() *Object {
+	var o *Object
+	if o = r.global.IteratorHelperPrototype; o == nil {
+		o = &Object{runtime: r}
+		r.global.IteratorHelperPrototype = o
+		o.self = r.createIteratorHelperProto(o)
+	}
+	return o
+}
+
+func (r *Runtime) createWrapForValidIteratorProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.getIteratorPrototype(), classObject)
+
+	o._putProp("next", r.newNativeFunc(r.wrapForValidIteratorProto_next, "next", 0), true, false, true)
+	o._putProp("return", r.newNativeFunc(r.wrapForValidIteratorProto_return, "return", 0), true, false, true)
+
+	return o
+}
+
+func (r *Runtime) getWrapForValidIteratorPrototype() *Object {
+	var o *Object
+	if o = r.global.WrapForValidIteratorPrototype; o == nil {
+		o = &Object{runtime: r}
+		r.global.WrapForValidIteratorPrototype = o
+		o.self = r.createWrapForValidIteratorProto(o)
+	}
+	return o
+}
+
+func (r *Runtime) createIterator(val *Object) objectImpl {
+	o := r.newNativeConstructOnly(val, r.builtin_Iterator, r.getIteratorPrototype(), "Iterator", 0)
+	o._putProp("from", r.newNativeFunc(r.iterator_from, "from", 1), true, false, true)
+
+	return o
+}
+
+func (r *Runtime) getIteratorConstructor() *Object {
+	ret := r.global.Iterator
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.Iterator = ret
+		ret.self = r.createIterator(ret)
+	}
+	return ret
+}
+
+// NewIterator creates a JavaScript Iterator which produces the values of the given sequence. The resulting
+// object inherits from Iterator.prototype, so all iterator helpers (map, filter, take, etc.) are available,
+// and it can be used in for-of loops and with the spread syntax.
+//
+// The sequence is consumed lazily using iter.Pull(). If the iterator is not exhausted, the sequence is stopped
+// when return() is called on the iterator (e.g. when a for-of loop exits early), or, failing that, after the
+// iterator object has been garbage collected. In the latter case the sequence is stopped from a separate
+// goroutine, therefore it should not access the Runtime after yield has returned false.
+func (r *Runtime) NewIterator(seq iter.Seq[Value]) *Object {
+	next, stop := iter.Pull(seq)
+	o := r.newIteratorHelper(next, stop)
+	runtime.AddCleanup(o, func(stop func()) {
+		stop()
+	}, stop)
+	return o
+}
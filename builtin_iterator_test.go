@@ -0,0 +1,119 @@
+package sobek
+
+import (
+	"testing"
+)
+
+func TestIteratorHelpers(t *testing.T) {
+	const SCRIPT = `
+	const trace = [];
+	function* naturals() {
+		try {
+			for (let i = 0; ; i++) {
+				yield i;
+			}
+		} finally {
+			trace.push("closed");
+		}
+	}
+	assert(compareArray(naturals().map(x => x * 2).filter(x => x % 3 === 0).take(3).toArray(), [0, 6, 12]));
+	assert.sameValue(trace.length, 1);
+	assert.sameValue(naturals().drop(2).take(2).reduce((a, b) => a + b), 5);
+	assert(compareArray(naturals().flatMap(x => [x, x]).take(5).toArray(), [0, 0, 1, 1, 2]));
+	assert.sameValue(naturals().some(x => x > 3), true);
+	assert.sameValue(naturals().every(x => x < 3), false);
+	assert.sameValue(naturals().find(x => x === 5), 5);
+	assert.sameValue(trace.length, 6);
+
+	const helper = naturals().map(x => x);
+	helper.next();
+	assert.sameValue(helper.return().done, true);
+	assert.sameValue(trace.length, 7);
+	assert.sameValue(helper.next().done, true);
+
+	let sum = 0;
+	[1, 2, 3].values().forEach(x => sum += x);
+	assert.sameValue(sum, 6);
+	assert.throws(TypeError, () => [].values().reduce((a, b) => a + b));
+	const started = naturals();
+	started.next();
+	assert.throws(RangeError, () => started.take(-1));
+	assert.sameValue(trace.length, 8, "the iterator is closed on argument validation failure");
+	assert.throws(TypeError, () => naturals().map(1));
+	assert.sameValue(Object.prototype.toString.call(helper), "[object Iterator Helper]");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestIteratorConstructor(t *testing.T) {
+	const SCRIPT = `
+	assert.sameValue(Object.getPrototypeOf([].values()).__proto__, Iterator.prototype);
+	assert.sameValue([].values().constructor, Iterator);
+	assert.sameValue(Iterator.prototype[Symbol.toStringTag], "Iterator");
+	assert.throws(TypeError, () => new Iterator());
+	assert.throws(TypeError, () => Iterator());
+
+	class Counter extends Iterator {
+		#i = 0;
+		next() {
+			return this.#i < 3 ? {value: this.#i++, done: false} : {value: undefined, done: true};
+		}
+	}
+	const c = new Counter();
+	assert.sameValue(Iterator.from(c), c);
+	assert(compareArray(c.map(x => x + 1).toArray(), [1, 2, 3]));
+
+	const plain = {next() { return {value: 1, done: false}; }};
+	const wrapped = Iterator.from(plain);
+	assert(wrapped !== plain, "wrapped");
+	assert.sameValue(wrapped.next().value, 1);
+	assert.sameValue(wrapped.return().done, true);
+	assert(compareArray(Iterator.from("ab").toArray(), ["a", "b"]));
+	assert.throws(TypeError, () => Iterator.from(1));
+
+	const it = [].values();
+	it.constructor = 1;
+	assert.sameValue(it.constructor, 1);
+	assert.sameValue(Iterator.prototype.constructor, Iterator);
+	assert.throws(TypeError, () => { Iterator.prototype.constructor = 1; });
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestNewIterator(t *testing.T) {
+	r := New()
+	stopped := false
+	seq := func(yield func(Value) bool) {
+		defer func() {
+			stopped = true
+		}()
+		for i := 0; ; i++ {
+			if !yield(r.ToValue(i)) {
+				return
+			}
+		}
+	}
+	r.Set("newIter", func() *Object {
+		return r.NewIterator(seq)
+	})
+	res, err := r.RunString(`
+	const res = [];
+	for (const v of newIter()) {
+		if (v > 2) {
+			break;
+		}
+		res.push(v);
+	}
+	res.push(...newIter().filter(x => x % 2).take(2));
+	res.join(",");
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := res.String(); s != "0,1,2,1,3" {
+		t.Fatal(s)
+	}
+	if !stopped {
+		t.Fatal("sequence was not stopped")
+	}
+}
@@ -140,6 +140,42 @@ func TestMapGetAdderGetIteratorOrder(t *testing.T) {
 	testScript(SCRIPT, valueTrue, t)
 }
 
+func TestGroupBy(t *testing.T) {
+	const SCRIPT = `
+	const items = [1, 2, 3, 4, 5];
+	const byParity = Object.groupBy(items, (v, i) => v % 2 ? "odd" : "even");
+	assert.sameValue(Object.getPrototypeOf(byParity), null);
+	assert(compareArray(Object.keys(byParity), ["odd", "even"]));
+	assert(compareArray(byParity.odd, [1, 3, 5]));
+	assert(compareArray(byParity.even, [2, 4]));
+	assert(compareArray(Object.keys(Object.groupBy("abc", (c, i) => i)), ["0", "1", "2"]));
+
+	const k1 = {}, k2 = {};
+	const m = Map.groupBy(items, v => v > 2 ? k1 : k2);
+	assert(m instanceof Map);
+	assert(compareArray([...m.keys()], [k2, k1]));
+	assert(compareArray(m.get(k1), [3, 4, 5]));
+	assert.sameValue(Map.groupBy([1, 2], () => -0).get(0).length, 2);
+
+	let closed = false;
+	const iterable = {
+		[Symbol.iterator]() {
+			return {
+				next() { return {value: 1, done: false}; },
+				return() { closed = true; return {}; }
+			};
+		}
+	};
+	assert.throws(Test262Error, () => Object.groupBy(iterable, () => { throw new Test262Error(); }));
+	assert(closed, "iterator is closed");
+	assert.throws(TypeError, () => Map.groupBy(null, () => {}));
+	assert.throws(TypeError, () => Object.groupBy([], null));
+	assert.sameValue(Object.groupBy.length, 2);
+	assert.sameValue(Map.groupBy.length, 2);
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
 func ExampleObject_Export_map() {
 	vm := New()
 	m, err := vm.RunString(`
@@ -73,6 +73,10 @@ func (r *Runtime) math_floor(call FunctionCall) Value {
 	return floatToValue(math.Floor(call.Argument(0).ToFloat()))
 }
 
+func (r *Runtime) math_f16round(call FunctionCall) Value {
+	return floatToValue(float16BitsToFloat64(toFloat16(call.Argument(0))))
+}
+
 func (r *Runtime) math_fround(call FunctionCall) Value {
 	return floatToValue(float64(float32(call.Argument(0).ToFloat())))
 }
@@ -265,6 +269,164 @@ func (r *Runtime) math_sqrt(call FunctionCall) Value {
 	return floatToValue(math.Sqrt(call.Argument(0).ToFloat()))
 }
 
+// preciseSum is an exact accumulator of finite float64 values based on Shewchuk's algorithm: the sum is
+// kept as a list of non-overlapping partials sorted by increasing magnitude. Intermediate overflows are
+// tracked separately in multiples of 2**1024.
+type preciseSum struct {
+	partials []float64
+	overflow int
+}
+
+const (
+	twoPow1023 = 0x1p1023
+	maxULP     = math.MaxFloat64 - 0x1.ffffffffffffep1023
+)
+
+// twoSum returns the rounded sum of x and y and its rounding error. It requires |x| >= |y|.
+func twoSum(x, y float64) (hi, lo float64) {
+	hi = x + y
+	lo = y - (hi - x)
+	return
+}
+
+func (s *preciseSum) add(x float64) {
+	used := 0
+	for _, y := range s.partials {
+		if math.Abs(x) < math.Abs(y) {
+			x, y = y, x
+		}
+		hi, lo := twoSum(x, y)
+		if math.IsInf(hi, 0) {
+			sign := 1.0
+			if hi < 0 {
+				sign = -1
+			}
+			s.overflow += int(sign)
+			x = (x - sign*twoPow1023) - sign*twoPow1023
+			if math.Abs(x) < math.Abs(y) {
+				x, y = y, x
+			}
+			hi, lo = twoSum(x, y)
+		}
+		if lo != 0 {
+			s.partials[used] = lo
+			used++
+		}
+		x = hi
+	}
+	s.partials = s.partials[:used]
+	if x != 0 {
+		s.partials = append(s.partials, x)
+	}
+}
+
+// value returns the exact sum rounded to the nearest float64 (ties to even).
+func (s *preciseSum) value() float64 {
+	partials := s.partials
+	n := len(partials) - 1
+	var hi, lo float64
+
+	if s.overflow != 0 {
+		var next float64
+		if n >= 0 {
+			next = partials[n]
+		}
+		n--
+		if s.overflow > 1 || s.overflow < -1 || (s.overflow > 0 && next > 0) || (s.overflow < 0 && next < 0) {
+			return math.Inf(s.overflow)
+		}
+		// The overflow is halved so that it can be added without overflowing.
+		hi, lo = twoSum(float64(s.overflow)*twoPow1023, next/2)
+		lo *= 2
+		if math.IsInf(2*hi, 0) {
+			// The result is either infinity or math.MaxFloat64 if the next partial makes it round down.
+			if hi > 0 {
+				if hi == twoPow1023 && lo == -(maxULP/2) && n >= 0 && partials[n] < 0 {
+					return math.MaxFloat64
+				}
+				return math.Inf(1)
+			}
+			if hi == -twoPow1023 && lo == maxULP/2 && n >= 0 && partials[n] > 0 {
+				return -math.MaxFloat64
+			}
+			return math.Inf(-1)
+		}
+		if lo != 0 {
+			partials[n+1] = lo
+			n++
+			lo = 0
+		}
+		hi *= 2
+	}
+
+	for n >= 0 {
+		x, y := hi, partials[n]
+		n--
+		hi, lo = twoSum(x, y)
+		if lo != 0 {
+			break
+		}
+	}
+
+	// If the rounding error is exactly half an ULP, the next partial decides the direction of rounding.
+	if n >= 0 && ((lo < 0 && partials[n] < 0) || (lo > 0 && partials[n] > 0)) {
+		y := lo * 2
+		x := hi + y
+		if x-hi == y {
+			hi = x
+		}
+	}
+	return hi
+}
+
+func (r *Runtime) math_sumPrecise(call FunctionCall) Value {
+	items := call.Argument(0)
+	r.checkObjectCoercible(items)
+	var sum preciseSum
+	var nonFinite float64
+	hasNaN, allNegativeZero := false, true
+	iter := r.getIterator(items, nil)
+	iter.iterate(func(v Value) {
+		var x float64
+		switch v := v.(type) {
+		case valueInt:
+			x = float64(v)
+		case valueFloat:
+			x = float64(v)
+		default:
+			panic(r.NewTypeError("Math.sumPrecise: all values must be numbers"))
+		}
+		if hasNaN {
+			return
+		}
+		switch {
+		case math.IsNaN(x):
+			hasNaN = true
+		case math.IsInf(x, 0):
+			if nonFinite != 0 && nonFinite != x {
+				hasNaN = true
+			}
+			nonFinite = x
+		case nonFinite == 0:
+			if x != 0 || !math.Signbit(x) {
+				allNegativeZero = false
+			}
+			if x != 0 {
+				sum.add(x)
+			}
+		}
+	})
+	switch {
+	case hasNaN:
+		return _NaN
+	case nonFinite != 0:
+		return floatToValue(nonFinite)
+	case allNegativeZero:
+		return _negativeZero
+	}
+	return floatToValue(sum.value())
+}
+
 func (r *Runtime) math_tan(call FunctionCall) Value {
 	return floatToValue(math.Tan(call.Argument(0).ToFloat()))
 }
@@ -314,6 +476,7 @@ func createMathTemplate() *objectTemplate {
 	t.putStr("exp", func(r *Runtime) Value { return r.methodProp(r.math_exp, "exp", 1) })
 	t.putStr("expm1", func(r *Runtime) Value { return r.methodProp(r.math_expm1, "expm1", 1) })
 	t.putStr("floor", func(r *Runtime) Value { return r.methodProp(r.math_floor, "floor", 1) })
+	t.putStr("f16round", func(r *Runtime) Value { return r.methodProp(r.math_f16round, "f16round", 1) })
 	t.putStr("fround", func(r *Runtime) Value { return r.methodProp(r.math_fround, "fround", 1) })
 	t.putStr("hypot", func(r *Runtime) Value { return r.methodProp(r.math_hypot, "hypot", 2) })
 	t.putStr("imul", func(r *Runtime) Value { return r.methodProp(r.math_imul, "imul", 2) })
@@ -330,6 +493,7 @@ func createMathTemplate() *objectTemplate {
 	t.putStr("sin", func(r *Runtime) Value { return r.methodProp(r.math_sin, "sin", 1) })
 	t.putStr("sinh", func(r *Runtime) Value { return r.methodProp(r.math_sinh, "sinh", 1) })
 	t.putStr("sqrt", func(r *Runtime) Value { return r.methodProp(r.math_sqrt, "sqrt", 1) })
+	t.putStr("sumPrecise", func(r *Runtime) Value { return r.methodProp(r.math_sumPrecise, "sumPrecise", 1) })
 	t.putStr("tan", func(r *Runtime) Value { return r.methodProp(r.math_tan, "tan", 1) })
 	t.putStr("tanh", func(r *Runtime) Value { return r.methodProp(r.math_tanh, "tanh", 1) })
 	t.putStr("trunc", func(r *Runtime) Value { return r.methodProp(r.math_trunc, "trunc", 1) })
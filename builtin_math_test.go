@@ -0,0 +1,85 @@
+package sobek
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestMathSumPrecise(t *testing.T) {
+	const SCRIPT = `
+	assert.sameValue(Math.sumPrecise([1e20, 0.1, -1e20]), 0.1);
+	assert.sameValue(Math.sumPrecise([0.1, 0.2, 0.3]), 0.6);
+	assert.sameValue(Math.sumPrecise([1, 2, 3]), 6);
+	assert.sameValue(Math.sumPrecise([]), -0);
+	assert.sameValue(Math.sumPrecise([-0, -0]), -0);
+	assert.sameValue(Math.sumPrecise([-0, 0]), 0);
+	assert.sameValue(Math.sumPrecise([1, -1]), 0);
+	assert.sameValue(Math.sumPrecise([Infinity, 1]), Infinity);
+	assert.sameValue(Math.sumPrecise([Infinity, -Infinity]), NaN);
+	assert.sameValue(Math.sumPrecise([NaN, 1]), NaN);
+	assert.sameValue(Math.sumPrecise([1.7976931348623157e308, 1.7976931348623157e308]), Infinity);
+	assert.sameValue(Math.sumPrecise([1.7976931348623157e308, 1.7976931348623157e308, -1.7976931348623157e308]), 1.7976931348623157e308);
+	assert.sameValue(Math.sumPrecise([1.7976931348623157e308, 9.979201547673598e291]), 1.7976931348623157e308);
+	assert.sameValue(Math.sumPrecise([1.7976931348623157e308, 9.979201547673599e291]), Infinity);
+	assert.sameValue(Math.sumPrecise(new Set([1, 2])), 3);
+
+	assert.throws(TypeError, () => Math.sumPrecise([1, "2"]));
+	assert.throws(TypeError, () => Math.sumPrecise([NaN, 1n]));
+	assert.throws(TypeError, () => Math.sumPrecise(1));
+	assert.throws(TypeError, () => Math.sumPrecise());
+
+	let closed = false;
+	const iterable = {
+		[Symbol.iterator]() {
+			return {
+				next() { return {value: "x", done: false}; },
+				return() { closed = true; return {}; }
+			};
+		}
+	};
+	assert.throws(TypeError, () => Math.sumPrecise(iterable));
+	assert(closed, "iterator is closed");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestPreciseSum(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	randFloat := func() float64 {
+		switch rnd.Intn(4) {
+		case 0:
+			return rnd.NormFloat64()
+		case 1:
+			return math.Ldexp(rnd.Float64()-0.5, rnd.Intn(2098)-1074)
+		case 2:
+			return math.Ldexp(float64(rnd.Int63n(1<<53)), rnd.Intn(40)-20)
+		default:
+			return math.Float64frombits(rnd.Uint64()&^(0x7ff<<52) | uint64(rnd.Intn(0x7ff))<<52)
+		}
+	}
+	for i := 0; i < 20000; i++ {
+		var s preciseSum
+		exact := new(big.Float).SetPrec(4096)
+		n := rnd.Intn(10) + 1
+		for j := 0; j < n; j++ {
+			x := randFloat()
+			if rnd.Intn(3) == 0 && j > 0 {
+				x = -x
+			}
+			if x == 0 {
+				continue
+			}
+			s.add(x)
+			exact.Add(exact, new(big.Float).SetFloat64(x))
+		}
+		expected, _ := exact.Float64()
+		if expected == 0 {
+			expected = 0
+		}
+		if res := s.value(); res != expected {
+			t.Fatalf("%d: expected %v, got %v", i, expected, res)
+		}
+	}
+}
@@ -577,6 +577,46 @@ func (r *Runtime) object_fromEntries(call FunctionCall) Value {
 	return result
 }
 
+type valueGroup struct {
+	key    Value
+	values []Value
+}
+
+// groupBy implements GroupBy. If propertyKeys is true the keys are converted to property keys,
+// otherwise they are compared using SameValueZero. The groups are returned in the order of insertion.
+func (r *Runtime) groupBy(items, callback Value, propertyKeys bool) []*valueGroup {
+	r.checkObjectCoercible(items)
+	callbackFn := r.toCallable(callback)
+	var groups []*valueGroup
+	index := newOrderedMap(r.getHash())
+	k := int64(0)
+	iter := r.getIterator(items, nil)
+	iter.iterate(func(value Value) {
+		key := callbackFn(FunctionCall{This: _undefined, Arguments: []Value{value, intToValue(k)}})
+		if propertyKeys {
+			key = toPropertyKey(key)
+		}
+		if idx := index.get(key); idx != nil {
+			g := groups[idx.ToInteger()]
+			g.values = append(g.values, value)
+		} else {
+			index.set(key, intToValue(int64(len(groups))))
+			groups = append(groups, &valueGroup{key: key, values: []Value{value}})
+		}
+		k++
+	})
+	return groups
+}
+
+func (r *Runtime) object_groupBy(call FunctionCall) Value {
+	groups := r.groupBy(call.Argument(0), call.Argument(1), true)
+	result := r.newBaseObject(nil, classObject).val
+	for _, g := range groups {
+		createDataPropertyOrThrow(result, g.key, r.newArrayValues(g.values))
+	}
+	return result
+}
+
 func (r *Runtime) object_hasOwn(call FunctionCall) Value {
 	o := call.Argument(0)
 	obj := o.ToObject(r)
@@ -627,6 +667,7 @@ func createObjectTemplate() *objectTemplate {
 	t.putStr("setPrototypeOf", func(r *Runtime) Value { return r.methodProp(r.object_setPrototypeOf, "setPrototypeOf", 2) })
 	t.putStr("values", func(r *Runtime) Value { return r.methodProp(r.object_values, "values", 1) })
 	t.putStr("fromEntries", func(r *Runtime) Value { return r.methodProp(r.object_fromEntries, "fromEntries", 1) })
+	t.putStr("groupBy", func(r *Runtime) Value { return r.methodProp(r.object_groupBy, "groupBy", 2) })
 	t.putStr("hasOwn", func(r *Runtime) Value { return r.methodProp(r.object_hasOwn, "hasOwn", 2) })
 
 	return t
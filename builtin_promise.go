@@ -342,9 +342,11 @@ func (r *Runtime) promiseProto_catch(call FunctionCall) Value {
 
 func (r *Runtime) promiseResolve(c *Object, x Value) *Object {
 	if obj, ok := x.(*Object); ok {
-		xConstructor := nilSafe(obj.self.getStr("constructor", nil))
-		if xConstructor.SameAs(c) {
-			return obj
+		if _, ok := obj.self.(*Promise); ok {
+			xConstructor := nilSafe(obj.self.getStr("constructor", nil))
+			if xConstructor.SameAs(c) {
+				return obj
+			}
 		}
 	}
 	pcap := r.newPromiseCapability(c)
@@ -352,6 +354,32 @@ func (r *Runtime) promiseResolve(c *Object, x Value) *Object {
 	return pcap.promise
 }
 
+// await implements the Await() abstract operation for code driven from Go: onFulfilled or onRejected
+// is called from a promise job once v is settled.
+func (r *Runtime) await(v Value, onFulfilled, onRejected func(Value)) {
+	var promise *Object
+	ex := r.vm.try(func() {
+		promise = r.promiseResolve(r.getPromise(), v)
+	})
+	if ex != nil {
+		onRejected(ex.val)
+		return
+	}
+	promise.self.(*Promise).addReactions(&promiseReaction{
+		typ: promiseReactionFulfill,
+		handler: &jobCallback{callback: func(call FunctionCall) Value {
+			onFulfilled(call.Argument(0))
+			return _undefined
+		}},
+	}, &promiseReaction{
+		typ: promiseReactionReject,
+		handler: &jobCallback{callback: func(call FunctionCall) Value {
+			onRejected(call.Argument(0))
+			return _undefined
+		}},
+	})
+}
+
 func (r *Runtime) promiseProto_finally(call FunctionCall) Value {
 	promise := r.toObject(call.This)
 	c := r.speciesConstructorObj(promise, r.getPromise())
@@ -548,6 +576,33 @@ func (r *Runtime) promise_resolve(call FunctionCall) Value {
 	return r.promiseResolve(r.toObject(call.This), call.Argument(0))
 }
 
+func (r *Runtime) promise_try(call FunctionCall) Value {
+	pcap := r.newPromiseCapability(r.toObject(call.This))
+	var result Value
+	ex := r.vm.try(func() {
+		var args []Value
+		if len(call.Arguments) > 1 {
+			args = call.Arguments[1:]
+		}
+		result = r.toCallable(call.Argument(0))(FunctionCall{This: _undefined, Arguments: args})
+	})
+	if ex != nil {
+		pcap.reject(ex.val)
+	} else {
+		pcap.resolve(result)
+	}
+	return pcap.promise
+}
+
+func (r *Runtime) promise_withResolvers(call FunctionCall) Value {
+	pcap := r.newPromiseCapability(r.toObject(call.This))
+	obj := r.NewObject()
+	obj.self._putProp("promise", pcap.promise, true, true, true)
+	obj.self._putProp("resolve", pcap.resolveObj, true, true, true)
+	obj.self._putProp("reject", pcap.rejectObj, true, true, true)
+	return obj
+}
+
 func (r *Runtime) createPromiseProto(val *Object) objectImpl {
 	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
 	o._putProp("constructor", r.getPromise(), true, false, true)
@@ -570,6 +625,8 @@ func (r *Runtime) createPromise(val *Object) objectImpl {
 	o._putProp("race", r.newNativeFunc(r.promise_race, "race", 1), true, false, true)
 	o._putProp("reject", r.newNativeFunc(r.promise_reject, "reject", 1), true, false, true)
 	o._putProp("resolve", r.newNativeFunc(r.promise_resolve, "resolve", 1), true, false, true)
+	o._putProp("try", r.newNativeFunc(r.promise_try, "try", 1), true, false, true)
+	o._putProp("withResolvers", r.newNativeFunc(r.promise_withResolvers, "withResolvers", 0), true, false, true)
 
 	r.putSpeciesReturnThis(o)
 
@@ -186,7 +186,7 @@ func compileRegexpFromValueString(patternStr String, flags string) (*regexpPatte
 }
 
 func compileRegexp(patternStr, flags string) (p *regexpPattern, err error) {
-	var global, ignoreCase, multiline, dotAll, sticky, unicode bool
+	var global, ignoreCase, multiline, dotAll, sticky, unicode, unicodeSets, hasIndices bool
 	var wrapper *regexpWrapper
 	var wrapper2 *regexp2Wrapper
 
@@ -196,6 +196,12 @@ func compileRegexp(patternStr, flags string) (p *regexpPattern, err error) {
 		}
 		for _, chr := range flags {
 			switch chr {
+			case 'd':
+				if hasIndices {
+					invalidFlags()
+					return
+				}
+				hasIndices = true
 			case 'g':
 				if global {
 					invalidFlags()
@@ -227,10 +233,17 @@ func compileRegexp(patternStr, flags string) (p *regexpPattern, err error) {
 				}
 				sticky = true
 			case 'u':
-				if unicode {
+				if unicode || unicodeSets {
 					invalidFlags()
+					return
 				}
 				unicode = true
+			case 'v':
+				if unicode || unicodeSets {
+					invalidFlags()
+					return
+				}
+				unicodeSets = true
 			default:
 				invalidFlags()
 				return
@@ -238,12 +251,24 @@ func compileRegexp(patternStr, flags string) (p *regexpPattern, err error) {
 		}
 	}
 
-	if unicode {
+	if unicode || unicodeSets {
 		patternStr = convertRegexpToUnicode(patternStr)
+		patternStr, err = parser.ExpandUnicodeClasses(patternStr, unicodeSets, ignoreCase)
+		if err != nil {
+			return
+		}
+		// the expanded pattern only uses the u flag syntax
+		unicode = true
 	} else {
 		patternStr = convertRegexpToUtf16(patternStr)
 	}
 
+	// regexp2 does not allow duplicate group names, they need to be renamed
+	regexp2Str, groupNames, err := parser.RenameDuplicateGroups(patternStr)
+	if err != nil {
+		return
+	}
+
 	re2Str, err := parser.TransformRegExp(patternStr, dotAll, unicode)
 	if err == nil {
 		re2flags := ""
@@ -278,15 +303,17 @@ func compileRegexp(patternStr, flags string) (p *regexpPattern, err error) {
 	}
 
 	if wrapper == nil {
-		wrapper2, err = compileRegexp2(patternStr, multiline, dotAll, ignoreCase, unicode)
+		wrapper2, err = compileRegexp2(regexp2Str, multiline, dotAll, ignoreCase, unicode)
 		if err != nil {
 			err = fmt.Errorf("Invalid regular expression (regexp2): %s (%v)", patternStr, err)
 			return
 		}
+		wrapper2.groupNames = groupNames
 	}
 
 	p = &regexpPattern{
-		src:            patternStr,
+		src:            regexp2Str,
+		groupNames:     groupNames,
 		regexpWrapper:  wrapper,
 		regexp2Wrapper: wrapper2,
 		global:         global,
@@ -295,6 +322,8 @@ func compileRegexp(patternStr, flags string) (p *regexpPattern, err error) {
 		dotAll:         dotAll,
 		sticky:         sticky,
 		unicode:        unicode,
+		unicodeSets:    unicodeSets,
+		hasIndices:     hasIndices,
 	}
 	return
 }
@@ -370,6 +399,57 @@ func (r *Runtime) builtin_RegExp(call FunctionCall) Value {
 	return r.newRegExp(pattern, flags, r.getRegExpPrototype()).val
 }
 
+func (r *Runtime) regexp_escape(call FunctionCall) Value {
+	str, ok := call.Argument(0).(String)
+	if !ok {
+		panic(r.NewTypeError("RegExp.escape requires a string argument"))
+	}
+	var sb StringBuilder
+	rd := &lenientUtf16Decoder{utf16Reader: str.utf16Reader()}
+	for first := true; ; first = false {
+		c, _, err := rd.ReadRune()
+		if err != nil {
+			break
+		}
+		if first && (c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			// the result may be appended to \0 or \c
+			writeRegexpHexEscape(&sb, c)
+			continue
+		}
+		switch c {
+		case '^', '$', '\\', '.', '*', '+', '?', '(', ')', '[', ']', '{', '}', '|', '/':
+			sb.WriteRune('\\')
+			sb.WriteRune(c)
+		case '\t':
+			sb.writeASCII(`\t`)
+		case '\n':
+			sb.writeASCII(`\n`)
+		case '\v':
+			sb.writeASCII(`\v`)
+		case '\f':
+			sb.writeASCII(`\f`)
+		case '\r':
+			sb.writeASCII(`\r`)
+		default:
+			if strings.ContainsRune(",-=<>#&!%:;@~'`\"", c) || strings.ContainsRune(parser.WhitespaceChars, c) || utf16.IsSurrogate(c) {
+				writeRegexpHexEscape(&sb, c)
+			} else {
+				sb.WriteRune(c)
+			}
+		}
+	}
+	return sb.String()
+}
+
+func writeRegexpHexEscape(sb *StringBuilder, c rune) {
+	if c <= 0xFF {
+		sb.writeASCII(string([]byte{'\\', 'x', hex[c>>4], hex[c&0xF]}))
+	} else {
+		// all the characters that need to be escaped are in the BMP
+		sb.writeASCII(string([]byte{'\\', 'u', hex[c>>12], hex[(c>>8)&0xF], hex[(c>>4)&0xF], hex[c&0xF]}))
+	}
+}
+
 func (r *Runtime) regexpproto_compile(call FunctionCall) Value {
 	if this, ok := r.toObject(call.This).self.(*regexpObject); ok {
 		var (
@@ -442,6 +522,9 @@ func (r *Runtime) regexpproto_toString(call FunctionCall) Value {
 			sb.WriteString(this.source)
 		}
 		sb.WriteRune('/')
+		if this.pattern.hasIndices {
+			sb.WriteRune('d')
+		}
 		if this.pattern.global {
 			sb.WriteRune('g')
 		}
@@ -454,7 +537,9 @@ func (r *Runtime) regexpproto_toString(call FunctionCall) Value {
 		if this.pattern.dotAll {
 			sb.WriteRune('s')
 		}
-		if this.pattern.unicode {
+		if this.pattern.unicodeSets {
+			sb.WriteRune('v')
+		} else if this.pattern.unicode {
 			sb.WriteRune('u')
 		}
 		if this.pattern.sticky {
@@ -547,6 +632,20 @@ func (r *Runtime) regexpproto_getGlobal(call FunctionCall) Value {
 	}
 }
 
+func (r *Runtime) regexpproto_getHasIndices(call FunctionCall) Value {
+	if this, ok := r.toObject(call.This).self.(*regexpObject); ok {
+		if this.pattern.hasIndices {
+			return valueTrue
+		} else {
+			return valueFalse
+		}
+	} else if call.This == r.global.RegExpPrototype {
+		return _undefined
+	} else {
+		panic(r.NewTypeError("Method RegExp.prototype.hasIndices getter called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: call.This})))
+	}
+}
+
 func (r *Runtime) regexpproto_getMultiline(call FunctionCall) Value {
 	if this, ok := r.toObject(call.This).self.(*regexpObject); ok {
 		if this.pattern.multiline {
@@ -591,7 +690,7 @@ func (r *Runtime) regexpproto_getIgnoreCase(call FunctionCall) Value {
 
 func (r *Runtime) regexpproto_getUnicode(call FunctionCall) Value {
 	if this, ok := r.toObject(call.This).self.(*regexpObject); ok {
-		if this.pattern.unicode {
+		if this.pattern.unicode && !this.pattern.unicodeSets {
 			return valueTrue
 		} else {
 			return valueFalse
@@ -603,6 +702,20 @@ func (r *Runtime) regexpproto_getUnicode(call FunctionCall) Value {
 	}
 }
 
+func (r *Runtime) regexpproto_getUnicodeSets(call FunctionCall) Value {
+	if this, ok := r.toObject(call.This).self.(*regexpObject); ok {
+		if this.pattern.unicodeSets {
+			return valueTrue
+		} else {
+			return valueFalse
+		}
+	} else if call.This == r.global.RegExpPrototype {
+		return _undefined
+	} else {
+		panic(r.NewTypeError("Method RegExp.prototype.unicodeSets getter called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: call.This})))
+	}
+}
+
 func (r *Runtime) regexpproto_getSticky(call FunctionCall) Value {
 	if this, ok := r.toObject(call.This).self.(*regexpObject); ok {
 		if this.pattern.sticky {
@@ -618,10 +731,16 @@ func (r *Runtime) regexpproto_getSticky(call FunctionCall) Value {
 }
 
 func (r *Runtime) regexpproto_getFlags(call FunctionCall) Value {
-	var global, ignoreCase, multiline, dotAll, sticky, unicode bool
+	var hasIndices, global, ignoreCase, multiline, dotAll, sticky, unicode, unicodeSets bool
 
 	thisObj := r.toObject(call.This)
 	size := 0
+	if v := thisObj.self.getStr("hasIndices", nil); v != nil {
+		hasIndices = v.ToBoolean()
+		if hasIndices {
+			size++
+		}
+	}
 	if v := thisObj.self.getStr("global", nil); v != nil {
 		global = v.ToBoolean()
 		if global {
@@ -658,9 +777,18 @@ func (r *Runtime) regexpproto_getFlags(call FunctionCall) Value {
 			size++
 		}
 	}
+	if v := thisObj.self.getStr("unicodeSets", nil); v != nil {
+		unicodeSets = v.ToBoolean()
+		if unicodeSets {
+			size++
+		}
+	}
 
 	var sb strings.Builder
 	sb.Grow(size)
+	if hasIndices {
+		sb.WriteByte('d')
+	}
 	if global {
 		sb.WriteByte('g')
 	}
@@ -676,6 +804,9 @@ func (r *Runtime) regexpproto_getFlags(call FunctionCall) Value {
 	if unicode {
 		sb.WriteByte('u')
 	}
+	if unicodeSets {
+		sb.WriteByte('v')
+	}
 	if sticky {
 		sb.WriteByte('y')
 	}
@@ -726,7 +857,7 @@ func (r *Runtime) regexpproto_stdMatcherGeneric(rxObj *Object, s String) Value {
 	flags := nilSafe(rx.getStr("flags", nil)).String()
 	global := strings.ContainsRune(flags, 'g')
 	if global {
-		a := r.getGlobalRegexpMatches(rxObj, s, strings.ContainsRune(flags, 'u'))
+		a := r.getGlobalRegexpMatches(rxObj, s, strings.ContainsAny(flags, "uv"))
 		if len(a) == 0 {
 			return _null
 		}
@@ -773,7 +904,7 @@ func (r *Runtime) regexpproto_stdMatcher(call FunctionCall) Value {
 	}
 	if rx.pattern.global {
 		rx.setOwnStr("lastIndex", intToValue(0), true)
-		results := rx.pattern.findAllSubmatchIndex(s, 0, -1, rx.pattern.sticky)
+		results := rx.pattern.findAllSubmatchIndex(r, s, 0, -1, rx.pattern.sticky)
 		if len(results) == 0 {
 			return _null
 		}
@@ -821,7 +952,7 @@ func (r *Runtime) regexpproto_stdMatcherAll(call FunctionCall) Value {
 	matcher.self.setOwnStr("lastIndex", valueInt(toLength(thisObj.self.getStr("lastIndex", nil))), true)
 	flagsStr := flags.String()
 	global := strings.Contains(flagsStr, "g")
-	fullUnicode := strings.Contains(flagsStr, "u")
+	fullUnicode := strings.ContainsAny(flagsStr, "uv")
 	return r.createRegExpStringIterator(matcher, s, global, fullUnicode)
 }
 
@@ -1020,7 +1151,7 @@ func (r *Runtime) regexpproto_stdSplitter(call FunctionCall) Value {
 		splitter = r.toConstructor(c)([]Value{rxObj, flags}, nil)
 		search = r.checkStdRegexp(splitter)
 		if search == nil {
-			return r.regexpproto_stdSplitterGeneric(splitter, s, limitValue, strings.Contains(flagsStr, "u"))
+			return r.regexpproto_stdSplitterGeneric(splitter, s, limitValue, strings.ContainsAny(flagsStr, "uv"))
 		}
 	}
 
@@ -1039,7 +1170,7 @@ func (r *Runtime) regexpproto_stdSplitter(call FunctionCall) Value {
 	lastIndex := 0
 	found := 0
 
-	results := pattern.findAllSubmatchIndex(s, 0, -1, false)
+	results := pattern.findAllSubmatchIndex(r, s, 0, -1, false)
 	if targetLength == 0 {
 		if len(results) == 0 {
 			valueArray = append(valueArray, s)
@@ -1103,7 +1234,7 @@ func (r *Runtime) regexpproto_stdReplacerGeneric(rxObj *Object, s, replaceStr St
 	var results []Value
 	flags := nilSafe(rxObj.self.getStr("flags", nil)).String()
 	isGlobal := strings.ContainsRune(flags, 'g')
-	isUnicode := strings.ContainsRune(flags, 'u')
+	isUnicode := strings.ContainsAny(flags, "uv")
 	if isGlobal {
 		results = r.getGlobalRegexpMatches(rxObj, s, isUnicode)
 	} else {
@@ -1275,7 +1406,7 @@ func (r *Runtime) regexpproto_stdReplacer(call FunctionCall) Value {
 	} else {
 		index = rx.getLastIndex()
 	}
-	found := rx.pattern.findAllSubmatchIndex(s, toIntStrict(index), find, rx.pattern.sticky)
+	found := rx.pattern.findAllSubmatchIndex(r, s, toIntStrict(index), find, rx.pattern.sticky)
 	if rx.pattern.global || rx.pattern.sticky {
 		var newLastIndex int64
 		if !rx.pattern.global && len(found) > 0 {
@@ -1322,6 +1453,7 @@ func (r *Runtime) getRegExp() *Object {
 		r.newNativeFuncAndConstruct(ret, r.builtin_RegExp,
 			r.wrapNativeConstruct(r.builtin_newRegExp, ret, proto), proto, "RegExp", intToValue(2))
 		rx := ret.self
+		rx._putProp("escape", r.newNativeFunc(r.regexp_escape, "escape", 1), true, false, true)
 		r.putSpeciesReturnThis(rx)
 	}
 	return ret
@@ -1350,6 +1482,11 @@ func (r *Runtime) getRegExpPrototype() *Object {
 			getterFunc:   r.newNativeFunc(r.regexpproto_getGlobal, "get global", 0),
 			accessor:     true,
 		}, false)
+		o.setOwnStr("hasIndices", &valueProperty{
+			configurable: true,
+			getterFunc:   r.newNativeFunc(r.regexpproto_getHasIndices, "get hasIndices", 0),
+			accessor:     true,
+		}, false)
 		o.setOwnStr("multiline", &valueProperty{
 			configurable: true,
 			getterFunc:   r.newNativeFunc(r.regexpproto_getMultiline, "get multiline", 0),
@@ -1370,6 +1507,11 @@ func (r *Runtime) getRegExpPrototype() *Object {
 			getterFunc:   r.newNativeFunc(r.regexpproto_getUnicode, "get unicode", 0),
 			accessor:     true,
 		}, false)
+		o.setOwnStr("unicodeSets", &valueProperty{
+			configurable: true,
+			getterFunc:   r.newNativeFunc(r.regexpproto_getUnicodeSets, "get unicodeSets", 0),
+			accessor:     true,
+		}, false)
 		o.setOwnStr("sticky", &valueProperty{
 			configurable: true,
 			getterFunc:   r.newNativeFunc(r.regexpproto_getSticky, "get sticky", 0),
@@ -1386,7 +1528,7 @@ func (r *Runtime) getRegExpPrototype() *Object {
 		o._putSym(SymSearch, valueProp(r.newNativeFunc(r.regexpproto_stdSearch, "[Symbol.search]", 1), true, false, true))
 		o._putSym(SymSplit, valueProp(r.newNativeFunc(r.regexpproto_stdSplitter, "[Symbol.split]", 2), true, false, true))
 		o._putSym(SymReplace, valueProp(r.newNativeFunc(r.regexpproto_stdReplacer, "[Symbol.replace]", 2), true, false, true))
-		o.guard("exec", "global", "multiline", "ignoreCase", "unicode", "sticky")
+		o.guard("exec", "global", "hasIndices", "multiline", "ignoreCase", "unicode", "unicodeSets", "sticky")
 	}
 	return ret
 }
@@ -2,6 +2,7 @@ package sobek
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 )
 
@@ -192,6 +193,244 @@ func (r *Runtime) setProto_values(call FunctionCall) Value {
 	return r.createSetIterator(call.This, iterationKindValue)
 }
 
+// setRecord is a Set Record as returned by GetSetRecord. It describes a "set-like" object, i.e. any object
+// with a numeric size property and callable has and keys properties.
+type setRecord struct {
+	set  *Object
+	size float64
+	has  func(FunctionCall) Value
+	keys func(FunctionCall) Value
+}
+
+// getSetRecord implements GetSetRecord.
+func (r *Runtime) getSetRecord(v Value) *setRecord {
+	obj, ok := v.(*Object)
+	if !ok {
+		panic(r.NewTypeError("%s is not an object", v.String()))
+	}
+	size := nilSafe(obj.self.getStr("size", nil)).ToNumber().ToFloat()
+	if math.IsNaN(size) {
+		panic(r.NewTypeError("The 'size' property must be a number"))
+	}
+	size = math.Trunc(size)
+	if size < 0 {
+		panic(r.newError(r.getRangeError(), "The 'size' property must not be negative"))
+	}
+	has, ok := assertCallable(nilSafe(obj.self.getStr("has", nil)))
+	if !ok {
+		panic(r.NewTypeError("The 'has' property must be a function"))
+	}
+	keys, ok := assertCallable(nilSafe(obj.self.getStr("keys", nil)))
+	if !ok {
+		panic(r.NewTypeError("The 'keys' property must be a function"))
+	}
+	return &setRecord{
+		set:  obj,
+		size: size,
+		has:  has,
+		keys: keys,
+	}
+}
+
+func (sr *setRecord) callHas(v Value) bool {
+	return sr.has(FunctionCall{This: sr.set, Arguments: []Value{v}}).ToBoolean()
+}
+
+// getKeysIterator implements GetKeysIterator.
+func (sr *setRecord) getKeysIterator() *iteratorRecord {
+	r := sr.set.runtime
+	iter, ok := sr.keys(FunctionCall{This: sr.set}).(*Object)
+	if !ok {
+		panic(r.NewTypeError("keys() did not return an object"))
+	}
+	next, ok := assertCallable(nilSafe(iter.self.getStr("next", nil)))
+	if !ok {
+		panic(r.NewTypeError("iterator.next is missing or not a function"))
+	}
+	return &iteratorRecord{
+		iterator: iter,
+		next:     next,
+	}
+}
+
+// iterateKeys calls f with each value produced by the keys iterator until f returns false, in which case
+// the iterator is closed.
+func (sr *setRecord) iterateKeys(f func(Value) bool) {
+	iter := sr.getKeysIterator()
+	for {
+		value, ok := iter.stepValue()
+		if !ok {
+			return
+		}
+		if value == _negativeZero {
+			value = intToValue(0)
+		}
+		if !f(value) {
+			iter.returnIter()
+			return
+		}
+	}
+}
+
+func (r *Runtime) toSetObject(v Value, method string) *setObject {
+	thisObj := r.toObject(v)
+	so, ok := thisObj.self.(*setObject)
+	if !ok {
+		panic(r.NewTypeError("Method Set.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: thisObj})))
+	}
+	return so
+}
+
+func (so *setObject) copyData() *orderedMap {
+	m := newOrderedMap(so.val.runtime.getHash())
+	iter := so.m.newIter()
+	for entry := iter.next(); entry != nil; entry = iter.next() {
+		m.set(entry.key, nil)
+	}
+	return m
+}
+
+// forEachKey calls f for each element of the set until f returns false. Elements added during the iteration
+// are visited, removed ones are skipped.
+func (so *setObject) forEachKey(f func(Value) bool) {
+	iter := so.m.newIter()
+	for entry := iter.next(); entry != nil; entry = iter.next() {
+		if !f(entry.key) {
+			return
+		}
+	}
+}
+
+func (r *Runtime) newSetFromData(m *orderedMap) *Object {
+	o := &Object{runtime: r}
+
+	so := &setObject{}
+	so.class = classObject
+	so.val = o
+	so.extensible = true
+	o.self = so
+	so.prototype = r.getSetPrototype()
+	so.init()
+	so.m = m
+
+	return o
+}
+
+func (r *Runtime) setProto_union(call FunctionCall) Value {
+	so := r.toSetObject(call.This, "union")
+	other := r.getSetRecord(call.Argument(0))
+	result := so.copyData()
+	other.iterateKeys(func(v Value) bool {
+		result.set(v, nil)
+		return true
+	})
+	return r.newSetFromData(result)
+}
+
+func (r *Runtime) setProto_intersection(call FunctionCall) Value {
+	so := r.toSetObject(call.This, "intersection")
+	other := r.getSetRecord(call.Argument(0))
+	result := newOrderedMap(r.getHash())
+	if float64(so.m.size) <= other.size {
+		so.forEachKey(func(v Value) bool {
+			if other.callHas(v) {
+				result.set(v, nil)
+			}
+			return true
+		})
+	} else {
+		other.iterateKeys(func(v Value) bool {
+			if so.m.has(v) {
+				result.set(v, nil)
+			}
+			return true
+		})
+	}
+	return r.newSetFromData(result)
+}
+
+func (r *Runtime) setProto_difference(call FunctionCall) Value {
+	so := r.toSetObject(call.This, "difference")
+	other := r.getSetRecord(call.Argument(0))
+	result := so.copyData()
+	if float64(so.m.size) <= other.size {
+		// the copy is iterated rather than the set itself, as other.has() may modify the set
+		iter := result.newIter()
+		for entry := iter.next(); entry != nil; entry = iter.next() {
+			if other.callHas(entry.key) {
+				result.remove(entry.key)
+			}
+		}
+	} else {
+		other.iterateKeys(func(v Value) bool {
+			result.remove(v)
+			return true
+		})
+	}
+	return r.newSetFromData(result)
+}
+
+func (r *Runtime) setProto_symmetricDifference(call FunctionCall) Value {
+	so := r.toSetObject(call.This, "symmetricDifference")
+	other := r.getSetRecord(call.Argument(0))
+	result := so.copyData()
+	other.iterateKeys(func(v Value) bool {
+		if so.m.has(v) {
+			result.remove(v)
+		} else {
+			result.set(v, nil)
+		}
+		return true
+	})
+	return r.newSetFromData(result)
+}
+
+func (r *Runtime) setProto_isSubsetOf(call FunctionCall) Value {
+	so := r.toSetObject(call.This, "isSubsetOf")
+	other := r.getSetRecord(call.Argument(0))
+	if float64(so.m.size) > other.size {
+		return valueFalse
+	}
+	result := true
+	so.forEachKey(func(v Value) bool {
+		result = other.callHas(v)
+		return result
+	})
+	return r.toBoolean(result)
+}
+
+func (r *Runtime) setProto_isSupersetOf(call FunctionCall) Value {
+	so := r.toSetObject(call.This, "isSupersetOf")
+	other := r.getSetRecord(call.Argument(0))
+	if float64(so.m.size) < other.size {
+		return valueFalse
+	}
+	result := true
+	other.iterateKeys(func(v Value) bool {
+		result = so.m.has(v)
+		return result
+	})
+	return r.toBoolean(result)
+}
+
+func (r *Runtime) setProto_isDisjointFrom(call FunctionCall) Value {
+	so := r.toSetObject(call.This, "isDisjointFrom")
+	other := r.getSetRecord(call.Argument(0))
+	result := true
+	if float64(so.m.size) <= other.size {
+		so.forEachKey(func(v Value) bool {
+			result = !other.callHas(v)
+			return result
+		})
+	} else {
+		other.iterateKeys(func(v Value) bool {
+			result = !so.m.has(v)
+			return result
+		})
+	}
+	return r.toBoolean(result)
+}
+
 func (r *Runtime) builtin_newSet(args []Value, newTarget *Object) *Object {
 	if newTarget == nil {
 		panic(r.needNew("Set"))
@@ -280,8 +519,13 @@ func (r *Runtime) createSetProto(val *Object) objectImpl {
 
 	o._putProp("clear", r.newNativeFunc(r.setProto_clear, "clear", 0), true, false, true)
 	o._putProp("delete", r.newNativeFunc(r.setProto_delete, "delete", 1), true, false, true)
+	o._putProp("difference", r.newNativeFunc(r.setProto_difference, "difference", 1), true, false, true)
 	o._putProp("forEach", r.newNativeFunc(r.setProto_forEach, "forEach", 1), true, false, true)
 	o._putProp("has", r.newNativeFunc(r.setProto_has, "has", 1), true, false, true)
+	o._putProp("intersection", r.newNativeFunc(r.setProto_intersection, "intersection", 1), true, false, true)
+	o._putProp("isDisjointFrom", r.newNativeFunc(r.setProto_isDisjointFrom, "isDisjointFrom", 1), true, false, true)
+	o._putProp("isSubsetOf", r.newNativeFunc(r.setProto_isSubsetOf, "isSubsetOf", 1), true, false, true)
+	o._putProp("isSupersetOf", r.newNativeFunc(r.setProto_isSupersetOf, "isSupersetOf", 1), true, false, true)
 	o.setOwnStr("size", &valueProperty{
 		getterFunc:   r.newNativeFunc(r.setProto_getSize, "get size", 0),
 		accessor:     true,
@@ -293,6 +537,8 @@ func (r *Runtime) createSetProto(val *Object) objectImpl {
 	o._putProp("values", valuesFunc, true, false, true)
 	o._putProp("keys", valuesFunc, true, false, true)
 	o._putProp("entries", r.newNativeFunc(r.setProto_entries, "entries", 0), true, false, true)
+	o._putProp("symmetricDifference", r.newNativeFunc(r.setProto_symmetricDifference, "symmetricDifference", 1), true, false, true)
+	o._putProp("union", r.newNativeFunc(r.setProto_union, "union", 1), true, false, true)
 	o._putSym(SymIterator, valueProp(valuesFunc, true, false, true))
 	o._putSym(SymToStringTag, valueProp(asciiString(classSet), false, false, true))
 
@@ -178,3 +178,106 @@ func TestSetGetAdderGetIteratorOrder(t *testing.T) {
 	`
 	testScript(SCRIPT, valueTrue, t)
 }
+
+func TestSetMethods(t *testing.T) {
+	const SCRIPT = `
+	const a = new Set([1, 2, 3]);
+	const b = new Set([3, 4]);
+	assert(compareArray([...a.union(b)], [1, 2, 3, 4]), "union");
+	assert(compareArray([...a.intersection(b)], [3]), "intersection");
+	assert(compareArray([...a.difference(b)], [1, 2]), "difference");
+	assert(compareArray([...a.symmetricDifference(b)], [1, 2, 4]), "symmetricDifference");
+	assert.sameValue(new Set([1, 2]).isSubsetOf(a), true);
+	assert.sameValue(a.isSubsetOf(b), false);
+	assert.sameValue(a.isSupersetOf(new Set([1, 3])), true);
+	assert.sameValue(a.isDisjointFrom(b), false);
+	assert.sameValue(a.isDisjointFrom(new Set([5])), true);
+
+	const setLike = {
+		size: 2,
+		has(v) { return v === 1 || v === 5; },
+		keys() { return [5, -0][Symbol.iterator](); },
+	};
+	assert(compareArray([...a.union(setLike)], [1, 2, 3, 5, 0]), "union with set-like");
+	assert(compareArray([...a.intersection(setLike)], []), "intersection with set-like via keys");
+	assert(compareArray([...a.intersection({...setLike, size: 10})], [1]), "intersection with set-like via has");
+	assert(Object.is([...new Set([0]).intersection({size: 100, has() { return false; }, keys() { return [-0].values(); }})].length, 0));
+	assert.throws(TypeError, () => a.union([1]));
+	assert.throws(TypeError, () => a.union({size: NaN, has() {}, keys() {}}));
+	assert.throws(RangeError, () => a.union({size: -1, has() {}, keys() {}}));
+	assert.throws(TypeError, () => a.union({size: 1, has: 1, keys() {}}));
+	assert.throws(TypeError, () => Set.prototype.union.call({}, a));
+
+	let closed = false;
+	const closing = {
+		size: Infinity,
+		has() { return true; },
+		keys() {
+			return {
+				next() { return {value: 42, done: false}; },
+				return() { closed = true; return {}; },
+			};
+		},
+	};
+	assert.sameValue(a.isSupersetOf({size: 0, has() {}, keys: closing.keys}), false);
+	assert(closed, "iterator closed");
+	assert.sameValue(a.isSubsetOf(closing), true);
+
+	const s = new Set([1, 2, 3]);
+	const deleting = {
+		size: 3,
+		has(v) { s.delete(2); return true; },
+		keys() {},
+	};
+	assert(compareArray([...s.difference(deleting)], []), "difference when has() modifies the set");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+type testDynSetLike struct {
+	r *Runtime
+	m map[int64]struct{}
+}
+
+func (s *testDynSetLike) Get(key string) Value {
+	switch key {
+	case "size":
+		return s.r.ToValue(len(s.m))
+	case "has":
+		return s.r.ToValue(func(v Value) bool {
+			_, ok := s.m[v.ToInteger()]
+			return ok
+		})
+	case "keys":
+		return s.r.ToValue(func() *Object {
+			return s.r.NewIterator(func(yield func(Value) bool) {
+				for k := range s.m {
+					if !yield(s.r.ToValue(k)) {
+						return
+					}
+				}
+			})
+		})
+	}
+	return nil
+}
+
+func (s *testDynSetLike) Set(string, Value) bool { return false }
+func (s *testDynSetLike) Has(key string) bool    { return s.Get(key) != nil }
+func (s *testDynSetLike) Delete(string) bool     { return false }
+func (s *testDynSetLike) Keys() []string         { return []string{"size", "has", "keys"} }
+
+func TestSetMethodsDynamicSetLike(t *testing.T) {
+	r := New()
+	r.Set("goSet", r.NewDynamicObject(&testDynSetLike{r: r, m: map[int64]struct{}{2: {}, 3: {}, 4: {}}}))
+	res, err := r.RunString(`
+	const s = new Set([1, 2, 3]);
+	[...s.union(goSet)].sort().join() + "|" + [...s.intersection(goSet)].join() + "|" + s.isDisjointFrom(goSet);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := res.String(); s != "1,2,3,4|2,3|false" {
+		t.Fatal(s)
+	}
+}
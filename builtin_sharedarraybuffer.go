@@ -0,0 +1,298 @@
+package sobek
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// sharedDataBlock is the memory of a SharedArrayBuffer. It may be used by several Runtimes running in
+// different goroutines at the same time.
+type sharedDataBlock struct {
+	// data is allocated upfront for the maximum length (rounded up to a multiple of 8 bytes), so that growing
+	// never moves it, and so that any naturally aligned 1, 2 or 4-byte element lies within an aligned 32-bit
+	// word that can be accessed atomically.
+	data       []byte
+	length     atomic.Int64
+	growable   bool
+	maxByteLen int
+
+	// mu guards waiters and serialises grow().
+	mu      sync.Mutex
+	waiters map[int][]*atomicsWaiter
+}
+
+// SharedArrayBuffer is a Go wrapper around the memory of an ECMAScript SharedArrayBuffer. Unlike ArrayBuffer, it
+// is not bound to a Runtime: the same SharedArrayBuffer can be passed to several Runtimes (which typically run in
+// different goroutines). Calling Runtime.ToValue() on it returns a new SharedArrayBuffer object backed by the same
+// memory. Calling Export() on an ECMAScript SharedArrayBuffer returns a wrapper.
+// Use NewSharedArrayBuffer() or NewGrowableSharedArrayBuffer() to create one.
+type SharedArrayBuffer struct {
+	block *sharedDataBlock
+}
+
+// maxGrowableSharedArrayBufferLength is the largest maxByteLength a growable SharedArrayBuffer can be created with
+// from JavaScript. The memory for maxByteLength is allocated upfront, so without a limit a script could make the Go
+// runtime abort with an out of memory error, which can't be recovered from.
+const maxGrowableSharedArrayBufferLength = 1 << 30
+
+func newSharedDataBlock(byteLen, maxByteLen int, growable bool) *sharedDataBlock {
+	capLen := byteLen
+	if growable {
+		capLen = maxByteLen
+	}
+	b := &sharedDataBlock{
+		data:       allocByteSlice((capLen + 7) &^ 7),
+		growable:   growable,
+		maxByteLen: maxByteLen,
+	}
+	b.length.Store(int64(byteLen))
+	return b
+}
+
+func (b *sharedDataBlock) byteLength() int {
+	return int(b.length.Load())
+}
+
+// grow implements the length update of SharedArrayBuffer.prototype.grow. The caller must check that the block
+// is growable.
+func (b *sharedDataBlock) grow(newLen int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	curLen := b.byteLength()
+	if newLen < curLen {
+		return fmt.Errorf("invalid length %d, SharedArrayBuffer cannot shrink from %d", newLen, curLen)
+	}
+	if newLen > b.maxByteLen {
+		return fmt.Errorf("invalid length %d, it exceeds maxByteLength (%d)", newLen, b.maxByteLen)
+	}
+	// The bytes beyond the current length have never been written, so there is nothing to clear.
+	b.length.Store(int64(newLen))
+	return nil
+}
+
+// NewSharedArrayBuffer creates a new zero-filled SharedArrayBuffer of the given length.
+func NewSharedArrayBuffer(byteLength int) SharedArrayBuffer {
+	if byteLength < 0 {
+		panic(fmt.Errorf("invalid length %d", byteLength))
+	}
+	return SharedArrayBuffer{
+		block: newSharedDataBlock(byteLength, byteLength, false),
+	}
+}
+
+// NewGrowableSharedArrayBuffer creates a new zero-filled SharedArrayBuffer of the given length which can
+// grow up to maxByteLength bytes. The memory for maxByteLength bytes is allocated upfront.
+// Panics if byteLength exceeds maxByteLength.
+func NewGrowableSharedArrayBuffer(byteLength, maxByteLength int) SharedArrayBuffer {
+	if byteLength < 0 || byteLength > maxByteLength {
+		panic(fmt.Errorf("invalid length %d, must be between 0 and maxByteLength (%d)", byteLength, maxByteLength))
+	}
+	return SharedArrayBuffer{
+		block: newSharedDataBlock(byteLength, maxByteLength, true),
+	}
+}
+
+func (s SharedArrayBuffer) toValue(r *Runtime) Value {
+	if s.block == nil {
+		return _null
+	}
+	return r._newSharedArrayBuffer(s.block, r.getSharedArrayBufferPrototype()).val
+}
+
+// Bytes returns the memory of the SharedArrayBuffer. Note, it may be concurrently accessed by JavaScript code
+// running in other goroutines, so any access that can race with it must use sync/atomic.
+func (s SharedArrayBuffer) Bytes() []byte {
+	return s.block.data[:s.block.byteLength()]
+}
+
+// Growable returns true if the SharedArrayBuffer was created as growable, either from JavaScript using the
+// maxByteLength option, or with NewGrowableSharedArrayBuffer().
+func (s SharedArrayBuffer) Growable() bool {
+	return s.block.growable
+}
+
+// MaxByteLength returns the maximum length the SharedArrayBuffer can grow to. For fixed-length buffers
+// it's the same as len(Bytes()).
+func (s SharedArrayBuffer) MaxByteLength() int {
+	if s.block.growable {
+		return s.block.maxByteLen
+	}
+	return s.block.byteLength()
+}
+
+// Grow increases the length of a growable SharedArrayBuffer. Unlike ArrayBuffer.Resize(), it is safe to call
+// concurrently with the Runtimes using the buffer.
+func (s SharedArrayBuffer) Grow(newByteLength int) error {
+	if !s.block.growable {
+		return errors.New("SharedArrayBuffer is not growable")
+	}
+	return s.block.grow(newByteLength)
+}
+
+func (r *Runtime) _newSharedArrayBuffer(block *sharedDataBlock, proto *Object) *arrayBufferObject {
+	b := r._newArrayBuffer(proto, nil)
+	b.shared = block
+	b.data = block.data[:block.byteLength()]
+	b.resizable = block.growable
+	b.maxByteLen = block.maxByteLen
+	return b
+}
+
+// syncLength picks up the length of a growable SharedArrayBuffer which may have been grown by another agent.
+func (o *arrayBufferObject) syncLength() {
+	if s := o.shared; s != nil && s.growable {
+		o.data = s.data[:s.byteLength()]
+	}
+}
+
+func (r *Runtime) builtin_newSharedArrayBuffer(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		panic(r.needNew("SharedArrayBuffer"))
+	}
+	var byteLen int
+	if len(args) > 0 {
+		byteLen = r.toIndex(args[0])
+	}
+	maxByteLen := -1
+	if len(args) > 1 {
+		if opts, ok := args[1].(*Object); ok {
+			if m := nilSafe(opts.self.getStr("maxByteLength", nil)); m != _undefined {
+				maxByteLen = r.toIndex(m)
+				if byteLen > maxByteLen {
+					panic(r.newErrorf(r.getRangeError(), "Invalid array buffer length %d, it exceeds maxByteLength (%d)", byteLen, maxByteLen))
+				}
+				if maxByteLen > maxGrowableSharedArrayBufferLength {
+					panic(r.newErrorf(r.getRangeError(), "Invalid array buffer max length %d, it exceeds the limit of %d bytes for a growable SharedArrayBuffer", maxByteLen, maxGrowableSharedArrayBufferLength))
+				}
+			}
+		}
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.getSharedArrayBuffer(), r.getSharedArrayBufferPrototype())
+	var block *sharedDataBlock
+	if maxByteLen >= 0 {
+		block = newSharedDataBlock(byteLen, maxByteLen, true)
+	} else {
+		block = newSharedDataBlock(byteLen, byteLen, false)
+	}
+	return r._newSharedArrayBuffer(block, proto).val
+}
+
+func (r *Runtime) toSharedArrayBuffer(v Value, method string) *arrayBufferObject {
+	o := r.toObject(v)
+	if b, ok := o.self.(*arrayBufferObject); ok && b.shared != nil {
+		b.syncLength()
+		return b
+	}
+	panic(r.NewTypeError("Method SharedArrayBuffer.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: o})))
+}
+
+func (r *Runtime) sharedArrayBufferProto_getByteLength(call FunctionCall) Value {
+	return intToValue(int64(len(r.toSharedArrayBuffer(call.This, "byteLength").data)))
+}
+
+func (r *Runtime) sharedArrayBufferProto_getGrowable(call FunctionCall) Value {
+	return r.toBoolean(r.toSharedArrayBuffer(call.This, "growable").resizable)
+}
+
+func (r *Runtime) sharedArrayBufferProto_getMaxByteLength(call FunctionCall) Value {
+	b := r.toSharedArrayBuffer(call.This, "maxByteLength")
+	if b.resizable {
+		return intToValue(int64(b.maxByteLen))
+	}
+	return intToValue(int64(len(b.data)))
+}
+
+func (r *Runtime) sharedArrayBufferProto_grow(call FunctionCall) Value {
+	b := r.toSharedArrayBuffer(call.This, "grow")
+	if !b.resizable {
+		panic(r.NewTypeError("Method SharedArrayBuffer.prototype.grow called on a fixed-length SharedArrayBuffer"))
+	}
+	newLen := r.toIndex(call.Argument(0))
+	if err := b.shared.grow(newLen); err != nil {
+		panic(r.newError(r.getRangeError(), err.Error()))
+	}
+	b.syncLength()
+	return _undefined
+}
+
+func (r *Runtime) sharedArrayBufferProto_slice(call FunctionCall) Value {
+	b := r.toSharedArrayBuffer(call.This, "slice")
+	l := int64(len(b.data))
+	start := relToIdx(call.Argument(0).ToInteger(), l)
+	var stop int64
+	if arg := call.Argument(1); arg != _undefined {
+		stop = arg.ToInteger()
+	} else {
+		stop = l
+	}
+	stop = relToIdx(stop, l)
+	newLen := max(stop-start, 0)
+	ret := r.speciesConstructor(b.val, r.getSharedArrayBuffer())([]Value{intToValue(newLen)}, nil)
+	if sb, ok := ret.self.(*arrayBufferObject); ok && sb.shared != nil {
+		if sb.shared == b.shared {
+			panic(r.NewTypeError("Species constructor returned the same SharedArrayBuffer"))
+		}
+		sb.syncLength()
+		if int64(len(sb.data)) < newLen {
+			panic(r.NewTypeError("Species constructor returned a SharedArrayBuffer that is too small: %d", len(sb.data)))
+		}
+		if start < stop {
+			copy(sb.data, b.data[start:stop])
+		}
+		return ret
+	}
+	panic(r.NewTypeError("Species constructor did not return a SharedArrayBuffer: %s", ret.String()))
+}
+
+func (r *Runtime) createSharedArrayBufferProto(val *Object) objectImpl {
+	b := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
+	b._put("byteLength", &valueProperty{
+		accessor:     true,
+		configurable: true,
+		getterFunc:   r.newNativeFunc(r.sharedArrayBufferProto_getByteLength, "get byteLength", 0),
+	})
+	b._putProp("constructor", r.getSharedArrayBuffer(), true, false, true)
+	b._putProp("grow", r.newNativeFunc(r.sharedArrayBufferProto_grow, "grow", 1), true, false, true)
+	b._put("growable", &valueProperty{
+		accessor:     true,
+		configurable: true,
+		getterFunc:   r.newNativeFunc(r.sharedArrayBufferProto_getGrowable, "get growable", 0),
+	})
+	b._put("maxByteLength", &valueProperty{
+		accessor:     true,
+		configurable: true,
+		getterFunc:   r.newNativeFunc(r.sharedArrayBufferProto_getMaxByteLength, "get maxByteLength", 0),
+	})
+	b._putProp("slice", r.newNativeFunc(r.sharedArrayBufferProto_slice, "slice", 2), true, false, true)
+	b._putSym(SymToStringTag, valueProp(asciiString("SharedArrayBuffer"), false, false, true))
+	return b
+}
+
+func (r *Runtime) createSharedArrayBuffer(val *Object) objectImpl {
+	o := r.newNativeConstructOnly(val, r.builtin_newSharedArrayBuffer, r.getSharedArrayBufferPrototype(), "SharedArrayBuffer", 1)
+	r.putSpeciesReturnThis(o)
+
+	return o
+}
+
+func (r *Runtime) getSharedArrayBufferPrototype() *Object {
+	ret := r.global.SharedArrayBufferPrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.SharedArrayBufferPrototype = ret
+		ret.self = r.createSharedArrayBufferProto(ret)
+	}
+	return ret
+}
+
+func (r *Runtime) getSharedArrayBuffer() *Object {
+	ret := r.global.SharedArrayBuffer
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.SharedArrayBuffer = ret
+		ret.self = r.createSharedArrayBuffer(ret)
+	}
+	return ret
+}
@@ -0,0 +1,103 @@
+package sobek
+
+import (
+	"testing"
+)
+
+func TestSharedArrayBuffer(t *testing.T) {
+	const SCRIPT = `
+	const sab = new SharedArrayBuffer(8);
+	assert.sameValue(sab.byteLength, 8);
+	assert.sameValue(sab.growable, false);
+	assert.sameValue(sab.maxByteLength, 8);
+	assert.sameValue(Object.prototype.toString.call(sab), "[object SharedArrayBuffer]");
+	assert.throws(TypeError, () => sab.grow(8));
+	assert.throws(TypeError, () => ArrayBuffer.prototype.slice.call(sab));
+	assert.throws(TypeError, () => Object.getOwnPropertyDescriptor(ArrayBuffer.prototype, "byteLength").get.call(sab));
+	assert.throws(TypeError, () => Object.getOwnPropertyDescriptor(SharedArrayBuffer.prototype, "byteLength").get.call(new ArrayBuffer(1)));
+
+	const u8 = new Uint8Array(sab);
+	u8.set([1, 2, 3, 4, 5, 6, 7, 8]);
+	const s = sab.slice(2, -2);
+	assert(s instanceof SharedArrayBuffer, "slice() returns a SharedArrayBuffer");
+	assert(compareArray(new Uint8Array(s), [3, 4, 5, 6]), "slice() content");
+	assert.sameValue(new DataView(sab).getUint8(7), 8);
+
+	const g = new SharedArrayBuffer(2, {maxByteLength: 8});
+	assert.sameValue(g.growable, true);
+	assert.sameValue(g.maxByteLength, 8);
+	const tracking = new Uint16Array(g);
+	const fixed = new Uint8Array(g, 0, 2);
+	assert.sameValue(tracking.length, 1);
+	g.grow(6);
+	assert.sameValue(g.byteLength, 6);
+	assert.sameValue(tracking.length, 3);
+	assert.sameValue(fixed.length, 2);
+	g.grow(6);
+	assert.throws(RangeError, () => g.grow(4));
+	assert.throws(RangeError, () => g.grow(10));
+	assert.throws(RangeError, () => new SharedArrayBuffer(4, {maxByteLength: 2}));
+	// the memory for maxByteLength is reserved upfront, a huge one must throw rather than run out of memory
+	assert.throws(RangeError, () => new SharedArrayBuffer(0, {maxByteLength: 2**45}));
+	assert.throws(RangeError, () => new SharedArrayBuffer(0, {maxByteLength: 2**53 - 1}));
+	assert.sameValue(new SharedArrayBuffer(0, {maxByteLength: 2**20}).maxByteLength, 2**20);
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestSharedArrayBufferMultipleRuntimes(t *testing.T) {
+	sab := NewGrowableSharedArrayBuffer(4, 16)
+	r1, r2 := New(), New()
+	r1.Set("sab", sab)
+	r2.Set("sab", sab)
+
+	_, err := r1.RunString(`
+	new Uint8Array(sab)[0] = 42;
+	sab.grow(8);
+	new Uint8Array(sab)[7] = 7;
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := r2.RunString(`
+	const a = new Uint8Array(sab);
+	a.length + ":" + a[0] + ":" + a[7];
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := v.String(); s != "8:42:7" {
+		t.Fatal(s)
+	}
+	if err := sab.Grow(16); err != nil {
+		t.Fatal(err)
+	}
+	if err := sab.Grow(10); err == nil {
+		t.Fatal("expected an error when shrinking")
+	}
+	v, err = r2.RunString(`a.length`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := v.ToInteger(); l != 16 {
+		t.Fatal(l)
+	}
+
+	exported, ok := r1.Get("sab").Export().(SharedArrayBuffer)
+	if !ok {
+		t.Fatalf("unexpected export type: %T", r1.Get("sab").Export())
+	}
+	if b := exported.Bytes(); len(b) != 16 || b[0] != 42 {
+		t.Fatal(b)
+	}
+	if !exported.Growable() || exported.MaxByteLength() != 16 {
+		t.Fatal("unexpected growable state")
+	}
+	var bytes []byte
+	if err := r1.ExportTo(r1.Get("sab"), &bytes); err != nil {
+		t.Fatal(err)
+	}
+	if len(bytes) != 16 {
+		t.Fatal(len(bytes))
+	}
+}
@@ -286,3 +286,18 @@ func TestStringSplit(t *testing.T) {
 `
 	testScriptWithTestLib(SCRIPT, _undefined, t)
 }
+
+func TestStringWellFormed(t *testing.T) {
+	const SCRIPT = `
+	assert.sameValue("abc".isWellFormed(), true);
+	assert.sameValue("a★b😀".isWellFormed(), true);
+	assert.sameValue("a\uD800b".isWellFormed(), false);
+	assert.sameValue("\uDC00\uD800".isWellFormed(), false);
+	assert.sameValue("a\uD800".toWellFormed(), "a�");
+	assert.sameValue("\uDC00😀\uD800x".toWellFormed(), "�😀�x");
+	assert.sameValue("abc".toWellFormed(), "abc");
+	assert.sameValue(String.prototype.isWellFormed.call(1), true);
+	assert.throws(TypeError, () => String.prototype.toWellFormed.call(null));
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
@@ -0,0 +1,1422 @@
+package sobek
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/sobek/unistring"
+)
+
+const (
+	classTemporal = "Temporal"
+
+	temporalISOCalendar = "iso8601"
+)
+
+// Option helpers
+
+// temporalGetStringOption implements GetOption for string options, the value must be one of values.
+func (r *Runtime) temporalGetStringOption(options *Object, name unistring.String, values []string, fallback string) string {
+	v := getOption(options, name)
+	if v == _undefined {
+		return fallback
+	}
+	s := v.toString().String()
+	for _, value := range values {
+		if s == value {
+			return s
+		}
+	}
+	panic(r.newErrorf(r.getRangeError(), "%s is not a valid value for %s", s, name))
+}
+
+func (r *Runtime) temporalGetOverflowOption(options *Object) string {
+	return r.temporalGetStringOption(options, "overflow", []string{"constrain", "reject"}, "constrain")
+}
+
+func (r *Runtime) temporalGetDisambiguationOption(options *Object) string {
+	return r.temporalGetStringOption(options, "disambiguation", []string{"compatible", "earlier", "later", "reject"}, "compatible")
+}
+
+func (r *Runtime) temporalGetOffsetOption(options *Object, fallback string) string {
+	return r.temporalGetStringOption(options, "offset", []string{"prefer", "use", "ignore", "reject"}, fallback)
+}
+
+func (r *Runtime) temporalGetRoundingModeOption(options *Object, fallback string) string {
+	return r.temporalGetStringOption(options, "roundingMode", temporalRoundingModes, fallback)
+}
+
+func (r *Runtime) temporalGetShowCalendarOption(options *Object) string {
+	return r.temporalGetStringOption(options, "calendarName", []string{"auto", "always", "never", "critical"}, "auto")
+}
+
+// temporalGetUnitOption implements GetTemporalUnitValuedOption.
+func (r *Runtime) temporalGetUnitOption(options *Object, name unistring.String, fallback temporalUnit) temporalUnit {
+	v := getOption(options, name)
+	if v == _undefined {
+		return fallback
+	}
+	s := v.toString().String()
+	if s == "auto" {
+		return temporalUnitAuto
+	}
+	for u := temporalYear; u <= temporalNanosecond; u++ {
+		if n := u.String(); s == n || s == n+"s" {
+			return u
+		}
+	}
+	panic(r.newErrorf(r.getRangeError(), "%s is not a valid value for %s", s, name))
+}
+
+// The unit groups of ValidateTemporalUnitValue.
+const (
+	temporalDateUnits = iota
+	temporalTimeUnits
+	temporalDateTimeUnits
+)
+
+// validateTemporalUnit implements ValidateTemporalUnitValue, extra is the additional allowed unit
+// (temporalUnitAuto or temporalDay) or temporalUnitUnset.
+func (r *Runtime) validateTemporalUnit(u temporalUnit, name unistring.String, group int, extra temporalUnit) {
+	switch {
+	case u == temporalUnitUnset || u == extra:
+		return
+	case u == temporalUnitAuto:
+	case group == temporalDateUnits && u.isDateUnit(), group == temporalTimeUnits && !u.isDateUnit(), group == temporalDateTimeUnits:
+		return
+	}
+	s := "auto"
+	if u != temporalUnitAuto {
+		s = u.String()
+	}
+	panic(r.newErrorf(r.getRangeError(), "%s is not a valid value for %s", s, name))
+}
+
+// temporalGetRoundingIncrementOption implements GetRoundingIncrementOption.
+func (r *Runtime) temporalGetRoundingIncrementOption(options *Object) int64 {
+	v := getOption(options, "roundingIncrement")
+	if v == _undefined {
+		return 1
+	}
+	i := r.toIntegerWithTruncation(v)
+	if i < 1 || i > 1e9 {
+		panic(r.newErrorf(r.getRangeError(), "roundingIncrement value is out of range"))
+	}
+	return int64(i)
+}
+
+// validateTemporalRoundingIncrement implements ValidateTemporalRoundingIncrement.
+func (r *Runtime) validateTemporalRoundingIncrement(increment, dividend int64, inclusive bool) {
+	maximum := dividend
+	if !inclusive {
+		maximum--
+	}
+	if increment > maximum || dividend%increment != 0 {
+		panic(r.newErrorf(r.getRangeError(), "roundingIncrement value is out of range"))
+	}
+}
+
+// temporalGetFractionalSecondDigitsOption implements GetTemporalFractionalSecondDigitsOption.
+func (r *Runtime) temporalGetFractionalSecondDigitsOption(options *Object) int {
+	v := getOption(options, "fractionalSecondDigits")
+	if v == _undefined {
+		return temporalPrecisionAuto
+	}
+	switch v.(type) {
+	case valueInt, valueFloat:
+	default:
+		if v.toString().String() != "auto" {
+			panic(r.newErrorf(r.getRangeError(), "fractionalSecondDigits value is out of range"))
+		}
+		return temporalPrecisionAuto
+	}
+	f := math.Floor(v.ToFloat())
+	if math.IsNaN(f) || f < 0 || f > 9 {
+		panic(r.newErrorf(r.getRangeError(), "fractionalSecondDigits value is out of range"))
+	}
+	return int(f)
+}
+
+type temporalPrecision struct {
+	precision int
+	unit      temporalUnit
+	increment int64
+}
+
+// toSecondsStringPrecision implements ToSecondsStringPrecisionRecord.
+func toSecondsStringPrecision(smallestUnit temporalUnit, digits int) temporalPrecision {
+	switch smallestUnit {
+	case temporalMinute:
+		return temporalPrecision{temporalPrecisionMinute, temporalMinute, 1}
+	case temporalSecond:
+		return temporalPrecision{0, temporalSecond, 1}
+	case temporalMillisecond:
+		return temporalPrecision{3, temporalMillisecond, 1}
+	case temporalMicrosecond:
+		return temporalPrecision{6, temporalMicrosecond, 1}
+	case temporalNanosecond:
+		return temporalPrecision{9, temporalNanosecond, 1}
+	}
+	switch {
+	case digits == temporalPrecisionAuto:
+		return temporalPrecision{temporalPrecisionAuto, temporalNanosecond, 1}
+	case digits == 0:
+		return temporalPrecision{0, temporalSecond, 1}
+	case digits <= 3:
+		return temporalPrecision{digits, temporalMillisecond, pow10Int64(3 - digits)}
+	case digits <= 6:
+		return temporalPrecision{digits, temporalMicrosecond, pow10Int64(6 - digits)}
+	}
+	return temporalPrecision{digits, temporalNanosecond, pow10Int64(9 - digits)}
+}
+
+func pow10Int64(n int) int64 {
+	res := int64(1)
+	for ; n > 0; n-- {
+		res *= 10
+	}
+	return res
+}
+
+// temporalGetToStringOptions reads the fractionalSecondDigits, roundingMode and smallestUnit options
+// used by the toString() methods.
+func (r *Runtime) temporalGetToStringOptions(options *Object) (digits int, mode string, smallestUnit temporalUnit) {
+	digits = r.temporalGetFractionalSecondDigitsOption(options)
+	mode = r.temporalGetRoundingModeOption(options, "trunc")
+	smallestUnit = r.temporalGetUnitOption(options, "smallestUnit", temporalUnitUnset)
+	return
+}
+
+// temporalToStringPrecision validates the smallestUnit option of the toString() methods and returns
+// the precision record.
+func (r *Runtime) temporalToStringPrecision(smallestUnit temporalUnit, digits int) temporalPrecision {
+	r.validateTemporalUnit(smallestUnit, "smallestUnit", temporalTimeUnits, temporalUnitUnset)
+	if smallestUnit == temporalHour {
+		panic(r.newErrorf(r.getRangeError(), "hour is not a valid value for smallestUnit"))
+	}
+	return toSecondsStringPrecision(smallestUnit, digits)
+}
+
+// temporalRoundTo returns the options object of the round() methods, a string argument is the smallest unit.
+func (r *Runtime) temporalRoundTo(v Value) *Object {
+	if v == _undefined {
+		panic(r.NewTypeError("Options parameter is required"))
+	}
+	if s, ok := v.(String); ok {
+		o := r.NewObject()
+		o.self.setOwnStr("smallestUnit", s, false)
+		return o
+	}
+	return r.getOptionsObject(v)
+}
+
+type temporalDifferenceSettings struct {
+	smallestUnit, largestUnit temporalUnit
+	roundingMode              string
+	roundingIncrement         int64
+}
+
+// temporalGetDifferenceSettings implements GetDifferenceSettings, disallowed units are the ones larger
+// than the largest unit of the group.
+func (r *Runtime) temporalGetDifferenceSettings(since bool, options *Object, group int, fallbackSmallestUnit, smallestLargestDefaultUnit temporalUnit) temporalDifferenceSettings {
+	var s temporalDifferenceSettings
+	s.largestUnit = r.temporalGetUnitOption(options, "largestUnit", temporalUnitUnset)
+	s.roundingIncrement = r.temporalGetRoundingIncrementOption(options)
+	s.roundingMode = r.temporalGetRoundingModeOption(options, "trunc")
+	s.smallestUnit = r.temporalGetUnitOption(options, "smallestUnit", temporalUnitUnset)
+	r.validateTemporalUnit(s.largestUnit, "largestUnit", group, temporalUnitAuto)
+	if s.largestUnit == temporalUnitUnset {
+		s.largestUnit = temporalUnitAuto
+	}
+	r.validateTemporalUnit(s.smallestUnit, "smallestUnit", group, temporalUnitUnset)
+	if s.smallestUnit == temporalUnitUnset {
+		s.smallestUnit = fallbackSmallestUnit
+	}
+	defaultLargestUnit := largerTemporalUnit(smallestLargestDefaultUnit, s.smallestUnit)
+	if s.largestUnit == temporalUnitAuto {
+		s.largestUnit = defaultLargestUnit
+	}
+	if largerTemporalUnit(s.largestUnit, s.smallestUnit) != s.largestUnit {
+		panic(r.newErrorf(r.getRangeError(), "smallestUnit must be smaller than largestUnit"))
+	}
+	if maximum := s.smallestUnit.maximumRoundingIncrement(); maximum != 0 {
+		r.validateTemporalRoundingIncrement(s.roundingIncrement, maximum, false)
+	}
+	if since {
+		s.roundingMode = negateRoundingMode(s.roundingMode)
+	}
+	return s
+}
+
+// Conversions
+
+// toIntegerWithTruncation implements ToIntegerWithTruncation.
+func (r *Runtime) toIntegerWithTruncation(v Value) float64 {
+	f := v.ToNumber().ToFloat()
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		panic(r.newErrorf(r.getRangeError(), "%s is not a finite number", valueFloat(f).String()))
+	}
+	return math.Trunc(f) + 0
+}
+
+// toPositiveIntegerWithTruncation implements ToPositiveIntegerWithTruncation.
+func (r *Runtime) toPositiveIntegerWithTruncation(v Value) float64 {
+	f := r.toIntegerWithTruncation(v)
+	if f <= 0 {
+		panic(r.newErrorf(r.getRangeError(), "%s is not a positive integer", valueFloat(f).String()))
+	}
+	return f
+}
+
+// toIntegerIfIntegral implements ToIntegerIfIntegral.
+func (r *Runtime) toIntegerIfIntegral(v Value) float64 {
+	f := v.ToNumber().ToFloat()
+	if math.IsNaN(f) || math.IsInf(f, 0) || f != math.Trunc(f) {
+		panic(r.newErrorf(r.getRangeError(), "%s is not an integer", valueFloat(f).String()))
+	}
+	return f + 0
+}
+
+// toTemporalString returns the string argument of the from() methods.
+func (r *Runtime) toTemporalString(v Value) string {
+	s, ok := v.(String)
+	if !ok {
+		panic(r.NewTypeError("%s is not a string", r.objectproto_toString(FunctionCall{This: v})))
+	}
+	return s.String()
+}
+
+func (r *Runtime) temporalInvalidString(s string) *Object {
+	return r.newErrorf(r.getRangeError(), "Invalid Temporal string: %s", s).(*Object)
+}
+
+// canonicalizeCalendar implements CanonicalizeCalendar, only the ISO 8601 calendar is supported.
+func (r *Runtime) canonicalizeCalendar(id string) string {
+	if strings.ToLower(id) != temporalISOCalendar {
+		panic(r.newErrorf(r.getRangeError(), "Unsupported calendar: %s", id))
+	}
+	return temporalISOCalendar
+}
+
+// toCalendarArgument validates the calendar argument of the constructors.
+func (r *Runtime) toCalendarArgument(v Value) string {
+	s, ok := v.(String)
+	if !ok {
+		panic(r.NewTypeError("Calendar must be a string"))
+	}
+	return r.canonicalizeCalendar(s.String())
+}
+
+func (r *Runtime) temporalCalendarFromParseResult(res *temporalParseResult) string {
+	if res.calendar == "" {
+		return temporalISOCalendar
+	}
+	return r.canonicalizeCalendar(res.calendar)
+}
+
+func isTemporalCalendarObject(o *Object) bool {
+	switch o.self.(type) {
+	case *temporalPlainDateObject, *temporalPlainDateTimeObject, *temporalZonedDateTimeObject:
+		return true
+	}
+	return false
+}
+
+// toTemporalCalendarIdentifier implements ToTemporalCalendarIdentifier.
+func (r *Runtime) toTemporalCalendarIdentifier(v Value) string {
+	if o, ok := v.(*Object); ok && isTemporalCalendarObject(o) {
+		return temporalISOCalendar
+	}
+	s, ok := v.(String)
+	if !ok {
+		panic(r.NewTypeError("Calendar must be a string"))
+	}
+	id := s.String()
+	if res, ok := parseTemporalString(id, temporalTimeZoneString); ok {
+		return r.temporalCalendarFromParseResult(res)
+	}
+	return r.canonicalizeCalendar(id)
+}
+
+// temporalCalendarWithISODefault implements GetTemporalCalendarIdentifierWithISODefault.
+func (r *Runtime) temporalCalendarWithISODefault(o *Object) string {
+	if isTemporalCalendarObject(o) {
+		return temporalISOCalendar
+	}
+	v := nilSafe(o.self.getStr("calendar", nil))
+	if v == _undefined {
+		return temporalISOCalendar
+	}
+	return r.toTemporalCalendarIdentifier(v)
+}
+
+// temporalTimeZoneFromIdentifier implements ParseTimeZoneIdentifier followed by the lookup of the
+// named time zone.
+func (r *Runtime) temporalTimeZoneFromIdentifier(id string) *temporalTimeZone {
+	if id != "" && (id[0] == '+' || id[0] == '-') {
+		if minutes, ok := parseUTCOffsetMinutes(id); ok {
+			return &temporalTimeZone{id: formatOffsetTimeZoneIdentifier(minutes), offsetNs: minutes * nsPerMinute}
+		}
+	} else if loc := loadTemporalLocation(id); loc != nil {
+		return &temporalTimeZone{id: loc.name, loc: loc}
+	}
+	panic(r.newErrorf(r.getRangeError(), "Invalid time zone: %s", id))
+}
+
+// toTemporalTimeZone implements ToTemporalTimeZoneIdentifier.
+func (r *Runtime) toTemporalTimeZone(v Value) *temporalTimeZone {
+	if o, ok := v.(*Object); ok {
+		if z, ok := o.self.(*temporalZonedDateTimeObject); ok {
+			return z.tz
+		}
+	}
+	s, ok := v.(String)
+	if !ok {
+		panic(r.NewTypeError("Time zone must be a string"))
+	}
+	id := s.String()
+	if isTimeZoneIdentifier(id) {
+		return r.temporalTimeZoneFromIdentifier(id)
+	}
+	if res, ok := parseTemporalString(id, temporalTimeZoneString); ok {
+		switch {
+		case res.timeZone != "":
+			return r.temporalTimeZoneFromIdentifier(res.timeZone)
+		case res.z:
+			return r.temporalTimeZoneFromIdentifier("UTC")
+		case res.offset != "":
+			if minutes, ok := parseUTCOffsetMinutes(res.offset); ok {
+				return &temporalTimeZone{id: formatOffsetTimeZoneIdentifier(minutes), offsetNs: minutes * nsPerMinute}
+			}
+		}
+	}
+	panic(r.newErrorf(r.getRangeError(), "Invalid time zone: %s", id))
+}
+
+// temporalSystemTimeZone implements SystemTimeZoneIdentifier.
+func (r *Runtime) temporalSystemTimeZone() *temporalTimeZone {
+	name, loc := r.intlDefaultTimeZone()
+	if l := loadTemporalLocation(name); l != nil {
+		return &temporalTimeZone{id: l.name, loc: l}
+	}
+	if r.timeZone != nil && name != "" {
+		// A custom location that is not in the time zone database.
+		return &temporalTimeZone{id: name, loc: &temporalLocation{name: name, loc: loc}}
+	}
+	return r.temporalTimeZoneFromIdentifier("UTC")
+}
+
+// Fields
+
+// The indexes of the fields in temporalFields, sorted by name.
+const (
+	temporalFieldDay = iota
+	temporalFieldHour
+	temporalFieldMicrosecond
+	temporalFieldMillisecond
+	temporalFieldMinute
+	temporalFieldMonth
+	temporalFieldMonthCode
+	temporalFieldNanosecond
+	temporalFieldOffset
+	temporalFieldSecond
+	temporalFieldTimeZone
+	temporalFieldYear
+	temporalFieldCount
+)
+
+var temporalFieldNames = [temporalFieldCount]unistring.String{
+	"day", "hour", "microsecond", "millisecond", "minute", "month", "monthCode", "nanosecond", "offset", "second", "timeZone", "year",
+}
+
+// The sets of fields, as bit masks of 1 << temporalField*.
+const (
+	temporalDateFields = 1<<temporalFieldYear | 1<<temporalFieldMonth | 1<<temporalFieldMonthCode | 1<<temporalFieldDay
+	temporalTimeFields = 1<<temporalFieldHour | 1<<temporalFieldMinute | 1<<temporalFieldSecond |
+		1<<temporalFieldMillisecond | 1<<temporalFieldMicrosecond | 1<<temporalFieldNanosecond
+)
+
+// temporalFields is the Calendar Fields Record.
+type temporalFields struct {
+	present   int
+	values    [temporalFieldCount]float64
+	monthCode string
+	offset    string
+	timeZone  *temporalTimeZone
+}
+
+func (f *temporalFields) has(field int) bool {
+	return f.present&(1<<field) != 0
+}
+
+func (f *temporalFields) set(field int, v float64) {
+	f.present |= 1 << field
+	f.values[field] = v
+}
+
+// prepareTemporalFields implements PrepareCalendarFields, it throws a TypeError if none of the fields
+// is present and partial is true.
+func (r *Runtime) prepareTemporalFields(o *Object, fields, required int, partial bool) *temporalFields {
+	res := &temporalFields{}
+	for i, name := range temporalFieldNames {
+		if fields&(1<<i) == 0 {
+			continue
+		}
+		v := nilSafe(o.self.getStr(name, nil))
+		if v == _undefined {
+			if required&(1<<i) != 0 {
+				panic(r.NewTypeError("%s is required", name))
+			}
+			continue
+		}
+		res.present |= 1 << i
+		switch i {
+		case temporalFieldDay, temporalFieldMonth:
+			res.values[i] = r.toPositiveIntegerWithTruncation(v)
+		case temporalFieldMonthCode:
+			res.monthCode = r.toMonthCode(v)
+		case temporalFieldOffset:
+			res.offset = r.toOffsetString(v)
+		case temporalFieldTimeZone:
+			res.timeZone = r.toTemporalTimeZone(v)
+		default:
+			res.values[i] = r.toIntegerWithTruncation(v)
+		}
+	}
+	if partial && res.present == 0 {
+		panic(r.NewTypeError("Object must contain at least one Temporal property"))
+	}
+	return res
+}
+
+// toMonthCode implements ToMonthCode.
+func (r *Runtime) toMonthCode(v Value) string {
+	if o, ok := v.(*Object); ok {
+		v = o.toPrimitiveString()
+	}
+	s, ok := v.(String)
+	if !ok {
+		panic(r.NewTypeError("monthCode must be a string"))
+	}
+	code := s.String()
+	if len(code) < 3 || len(code) > 4 || code[0] != 'M' || !isASCIIDigit(code[1]) || !isASCIIDigit(code[2]) ||
+		len(code) == 4 && code[3] != 'L' || code[1:3] == "00" && len(code) == 3 {
+		panic(r.newErrorf(r.getRangeError(), "Invalid monthCode: %s", code))
+	}
+	return code
+}
+
+// toOffsetString implements ToOffsetString.
+func (r *Runtime) toOffsetString(v Value) string {
+	if o, ok := v.(*Object); ok {
+		v = o.toPrimitiveString()
+	}
+	s, ok := v.(String)
+	if !ok {
+		panic(r.NewTypeError("offset must be a string"))
+	}
+	offset := s.String()
+	if _, ok := parseUTCOffsetNs(offset); !ok {
+		panic(r.newErrorf(r.getRangeError(), "Invalid offset: %s", offset))
+	}
+	return offset
+}
+
+func monthCodeFor(month int) string {
+	return "M" + twoDigits(month)
+}
+
+// mergeTemporalFields implements CalendarMergeFields.
+func mergeTemporalFields(fields, additional *temporalFields) *temporalFields {
+	res := *fields
+	if additional.has(temporalFieldMonth) || additional.has(temporalFieldMonthCode) {
+		res.present &^= 1<<temporalFieldMonth | 1<<temporalFieldMonthCode
+		res.monthCode = ""
+	}
+	for i := range temporalFieldNames {
+		if additional.has(i) {
+			res.present |= 1 << i
+			res.values[i] = additional.values[i]
+		}
+	}
+	if additional.has(temporalFieldMonthCode) {
+		res.monthCode = additional.monthCode
+	}
+	if additional.has(temporalFieldOffset) {
+		res.offset = additional.offset
+	}
+	if additional.has(temporalFieldTimeZone) {
+		res.timeZone = additional.timeZone
+	}
+	return &res
+}
+
+func isoDateFields(d isoDate) *temporalFields {
+	f := &temporalFields{}
+	f.set(temporalFieldYear, float64(d.year))
+	f.set(temporalFieldMonth, float64(d.month))
+	f.set(temporalFieldDay, float64(d.day))
+	f.present |= 1 << temporalFieldMonthCode
+	f.monthCode = monthCodeFor(d.month)
+	return f
+}
+
+func (f *temporalFields) setTime(t isoTime) {
+	f.set(temporalFieldHour, float64(t.hour))
+	f.set(temporalFieldMinute, float64(t.minute))
+	f.set(temporalFieldSecond, float64(t.second))
+	f.set(temporalFieldMillisecond, float64(t.millisecond))
+	f.set(temporalFieldMicrosecond, float64(t.microsecond))
+	f.set(temporalFieldNanosecond, float64(t.nanosecond))
+}
+
+// temporalDateFromFields implements CalendarDateFromFields.
+func (r *Runtime) temporalDateFromFields(f *temporalFields, overflow string) isoDate {
+	if !f.has(temporalFieldYear) {
+		panic(r.NewTypeError("year is required"))
+	}
+	if !f.has(temporalFieldDay) {
+		panic(r.NewTypeError("day is required"))
+	}
+	month := f.values[temporalFieldMonth]
+	if f.has(temporalFieldMonthCode) {
+		code := f.monthCode
+		m := float64(int(code[1]-'0')*10 + int(code[2]-'0'))
+		if len(code) != 3 || m > 12 {
+			panic(r.newErrorf(r.getRangeError(), "Invalid monthCode: %s", code))
+		}
+		if f.has(temporalFieldMonth) && month != m {
+			panic(r.newErrorf(r.getRangeError(), "month and monthCode do not agree"))
+		}
+		month = m
+	} else if !f.has(temporalFieldMonth) {
+		panic(r.NewTypeError("month or monthCode is required"))
+	}
+	return r.regulateISODate(f.values[temporalFieldYear], month, f.values[temporalFieldDay], overflow)
+}
+
+// regulateISODate implements RegulateISODate followed by the limits check.
+func (r *Runtime) regulateISODate(year, month, day float64, overflow string) isoDate {
+	if math.Abs(year) > 1e6 {
+		panic(r.newErrorf(r.getRangeError(), "Date is out of range"))
+	}
+	d, ok := regulateISODate(int64(year), int64(min(month, 1e6)), int64(min(day, 1e6)), overflow)
+	if !ok {
+		panic(r.newErrorf(r.getRangeError(), "Invalid date"))
+	}
+	if !isoDateWithinLimits(d) {
+		panic(r.newErrorf(r.getRangeError(), "Date is out of range"))
+	}
+	return d
+}
+
+func (r *Runtime) temporalTimeFromFields(f *temporalFields, overflow string) isoTime {
+	v := &f.values
+	t, ok := regulateTime(v[temporalFieldHour], v[temporalFieldMinute], v[temporalFieldSecond],
+		v[temporalFieldMillisecond], v[temporalFieldMicrosecond], v[temporalFieldNanosecond], overflow)
+	if !ok {
+		panic(r.newErrorf(r.getRangeError(), "Invalid time"))
+	}
+	return t
+}
+
+// temporalDateTimeFromFields implements InterpretTemporalDateTimeFields.
+func (r *Runtime) temporalDateTimeFromFields(f *temporalFields, overflow string) isoDateTime {
+	return isoDateTime{date: r.temporalDateFromFields(f, overflow), time: r.temporalTimeFromFields(f, overflow)}
+}
+
+// rejectTemporalLikeObject implements RejectTemporalLikeObject.
+func (r *Runtime) rejectTemporalLikeObject(o *Object) {
+	switch o.self.(type) {
+	case *temporalPlainDateObject, *temporalPlainDateTimeObject, *temporalPlainTimeObject, *temporalZonedDateTimeObject:
+		panic(r.NewTypeError("A Temporal object is not allowed"))
+	}
+	if nilSafe(o.self.getStr("calendar", nil)) != _undefined {
+		panic(r.NewTypeError("calendar is not allowed"))
+	}
+	if nilSafe(o.self.getStr("timeZone", nil)) != _undefined {
+		panic(r.NewTypeError("timeZone is not allowed"))
+	}
+}
+
+func (r *Runtime) toTemporalLikeObject(v Value) *Object {
+	o, ok := v.(*Object)
+	if !ok {
+		panic(r.NewTypeError("%s is not an object", v.String()))
+	}
+	r.rejectTemporalLikeObject(o)
+	return o
+}
+
+// Time zone operations that may throw
+
+// temporalPossibleEpochNs implements GetPossibleEpochNanoseconds.
+func (r *Runtime) temporalPossibleEpochNs(tz *temporalTimeZone, dt isoDateTime) []*big.Int {
+	r.checkISODaysRange(dt.date)
+	res := tz.possibleEpochNs(dt)
+	for _, ns := range res {
+		if !isValidEpochNs(ns) {
+			panic(r.newErrorf(r.getRangeError(), "Date-time is out of range"))
+		}
+	}
+	return res
+}
+
+// checkISODaysRange implements CheckISODaysRange.
+func (r *Runtime) checkISODaysRange(d isoDate) {
+	if days := d.epochDays(); days < -1e8 || days > 1e8 {
+		panic(r.newErrorf(r.getRangeError(), "Date-time is out of range"))
+	}
+}
+
+// temporalEpochNsFor implements GetEpochNanosecondsFor.
+func (r *Runtime) temporalEpochNsFor(tz *temporalTimeZone, dt isoDateTime, disambiguation string) *big.Int {
+	return r.disambiguateEpochNs(r.temporalPossibleEpochNs(tz, dt), tz, dt, disambiguation)
+}
+
+// disambiguateEpochNs implements DisambiguatePossibleEpochNanoseconds.
+func (r *Runtime) disambiguateEpochNs(possible []*big.Int, tz *temporalTimeZone, dt isoDateTime, disambiguation string) *big.Int {
+	if n := len(possible); n == 1 {
+		return possible[0]
+	} else if n != 0 {
+		switch disambiguation {
+		case "earlier", "compatible":
+			return possible[0]
+		case "later":
+			return possible[n-1]
+		}
+		panic(r.newErrorf(r.getRangeError(), "%s is ambiguous in time zone %s", dt.format(temporalPrecisionAuto), tz.id))
+	}
+	if disambiguation == "reject" {
+		panic(r.newErrorf(r.getRangeError(), "%s does not exist in time zone %s", dt.format(temporalPrecisionAuto), tz.id))
+	}
+	utc := dt.utcEpochNs()
+	dayBefore := new(big.Int).Sub(utc, bigNsPerDay)
+	dayAfter := new(big.Int).Add(utc, bigNsPerDay)
+	if !isValidEpochNs(dayBefore) || !isValidEpochNs(dayAfter) {
+		panic(r.newErrorf(r.getRangeError(), "Date-time is out of range"))
+	}
+	nanoseconds := tz.offsetNsFor(dayAfter) - tz.offsetNsFor(dayBefore)
+	if disambiguation == "earlier" {
+		possible = r.temporalPossibleEpochNs(tz, dt.addNanos(-nanoseconds))
+		return possible[0]
+	}
+	possible = r.temporalPossibleEpochNs(tz, dt.addNanos(nanoseconds))
+	return possible[len(possible)-1]
+}
+
+// temporalStartOfDay implements GetStartOfDay.
+func (r *Runtime) temporalStartOfDay(tz *temporalTimeZone, d isoDate) *big.Int {
+	dt := isoDateTime{date: d}
+	if possible := r.temporalPossibleEpochNs(tz, dt); len(possible) > 0 {
+		return possible[0]
+	}
+	return tz.nextTransition(new(big.Int).Sub(dt.utcEpochNs(), bigNsPerDay))
+}
+
+// The offset behaviours and match behaviours of InterpretISODateTimeOffset.
+const (
+	temporalOffsetOption = iota
+	temporalOffsetExact
+	temporalOffsetWall
+
+	temporalMatchExactly = false
+	temporalMatchMinutes = true
+)
+
+// interpretISODateTimeOffset implements InterpretISODateTimeOffset, the time is nil for start-of-day.
+func (r *Runtime) interpretISODateTimeOffset(d isoDate, t *isoTime, offsetBehaviour int, offsetNs int64, tz *temporalTimeZone,
+	disambiguation, offsetOption string, matchMinutes bool) *big.Int {
+	if t == nil {
+		return r.temporalStartOfDay(tz, d)
+	}
+	dt := isoDateTime{date: d, time: *t}
+	if offsetBehaviour == temporalOffsetWall || offsetBehaviour == temporalOffsetOption && offsetOption == "ignore" {
+		return r.temporalEpochNsFor(tz, dt, disambiguation)
+	}
+	if offsetBehaviour == temporalOffsetExact || offsetBehaviour == temporalOffsetOption && offsetOption == "use" {
+		balanced := dt.addNanos(-offsetNs)
+		r.checkISODaysRange(balanced.date)
+		epochNs := balanced.utcEpochNs()
+		if !isValidEpochNs(epochNs) {
+			panic(r.newErrorf(r.getRangeError(), "Date-time is out of range"))
+		}
+		return epochNs
+	}
+	r.checkISODaysRange(d)
+	utc := dt.utcEpochNs()
+	possible := r.temporalPossibleEpochNs(tz, dt)
+	for _, candidate := range possible {
+		candidateOffset := new(big.Int).Sub(utc, candidate).Int64()
+		if candidateOffset == offsetNs {
+			return candidate
+		}
+		if matchMinutes && roundInt64ToIncrement(candidateOffset, nsPerMinute, "halfExpand") == offsetNs {
+			return candidate
+		}
+	}
+	if offsetOption == "reject" {
+		panic(r.newErrorf(r.getRangeError(), "Offset %s is invalid for %s in %s", formatUTCOffsetNs(offsetNs), dt.format(temporalPrecisionAuto), tz.id))
+	}
+	return r.disambiguateEpochNs(possible, tz, dt, disambiguation)
+}
+
+// temporalAddInstant implements AddInstant.
+func (r *Runtime) temporalAddInstant(epochNs, d *big.Int) *big.Int {
+	res := new(big.Int).Add(epochNs, d)
+	if !isValidEpochNs(res) {
+		panic(r.newErrorf(r.getRangeError(), "Instant is out of range"))
+	}
+	return res
+}
+
+// temporalDateAdd implements CalendarDateAdd.
+func (r *Runtime) temporalDateAdd(d isoDate, dur dateDuration, overflow string) isoDate {
+	y, m := balanceISOYearMonth(int64(d.year)+dur.years, int64(d.month)+dur.months)
+	if y < -1e6 || y > 1e6 {
+		panic(r.newErrorf(r.getRangeError(), "Date is out of range"))
+	}
+	intermediate, ok := regulateISODate(y, m, int64(d.day), overflow)
+	if !ok {
+		panic(r.newErrorf(r.getRangeError(), "Invalid date"))
+	}
+	days := intermediate.epochDays() + dur.days + 7*dur.weeks
+	if days < temporalMinEpochDays || days > temporalMaxEpochDays {
+		panic(r.newErrorf(r.getRangeError(), "Date is out of range"))
+	}
+	return isoDateFromEpochDays(days)
+}
+
+// temporalDateUntil implements CalendarDateUntil.
+func temporalDateUntil(one, two isoDate, largestUnit temporalUnit) dateDuration {
+	sign := int64(compareISODate(two, one))
+	if sign == 0 {
+		return dateDuration{}
+	}
+	var res dateDuration
+	surpasses := func(year, month int64, day int) bool {
+		y, m := balanceISOYearMonth(year, month)
+		c := isoDate{year: int(y), month: int(m), day: day}
+		return int64(compareISODate(c, two))*sign > 0
+	}
+	if largestUnit == temporalYear {
+		candidate := int64(two.year - one.year)
+		if candidate != 0 && surpasses(int64(one.year)+candidate, int64(one.month), one.day) {
+			candidate -= sign
+		}
+		if candidate*sign > 0 {
+			res.years = candidate
+		}
+	}
+	if largestUnit == temporalYear || largestUnit == temporalMonth {
+		y, m := int64(one.year)+res.years, int64(one.month)
+		candidate := int64(two.year)*12 + int64(two.month) - (y*12 + m)
+		if candidate != 0 && surpasses(y, m+candidate, one.day) {
+			candidate -= sign
+		}
+		if candidate*sign > 0 {
+			res.months = candidate
+		}
+	}
+	y, m := balanceISOYearMonth(int64(one.year)+res.years, int64(one.month)+res.months)
+	constrained, _ := regulateISODate(y, m, int64(one.day), "constrain")
+	res.days = two.epochDays() - constrained.epochDays()
+	if largestUnit == temporalWeek {
+		res.weeks = res.days / 7
+		res.days %= 7
+	}
+	return res
+}
+
+// temporalDateTimeAdd adds an internal duration to a date-time the way AddDurationToDateTime does.
+func (r *Runtime) temporalDateTimeAdd(dt isoDateTime, d internalDuration, overflow string) isoDateTime {
+	days, t := addTime(dt.time, d.time)
+	dur := d.date
+	dur.days += days
+	res := isoDateTime{date: r.temporalDateAdd(dt.date, dur, overflow), time: t}
+	if !isoDateTimeWithinLimits(res) {
+		panic(r.newErrorf(r.getRangeError(), "Date-time is out of range"))
+	}
+	return res
+}
+
+// temporalAddZonedDateTime implements AddZonedDateTime.
+func (r *Runtime) temporalAddZonedDateTime(epochNs *big.Int, tz *temporalTimeZone, d internalDuration, overflow string) *big.Int {
+	if d.date.sign() == 0 {
+		return r.temporalAddInstant(epochNs, d.time)
+	}
+	dt := tz.isoDateTimeFor(epochNs)
+	intermediate := isoDateTime{date: r.temporalDateAdd(dt.date, d.date, overflow), time: dt.time}
+	if !isoDateTimeWithinLimits(intermediate) {
+		panic(r.newErrorf(r.getRangeError(), "Date-time is out of range"))
+	}
+	return r.temporalAddInstant(r.temporalEpochNsFor(tz, intermediate, "compatible"), d.time)
+}
+
+// temporalDifferenceISODateTime implements DifferenceISODateTime.
+func temporalDifferenceISODateTime(dt1, dt2 isoDateTime, largestUnit temporalUnit) internalDuration {
+	timeDuration := big.NewInt(dt2.time.nanos() - dt1.time.nanos())
+	timeSign := timeDuration.Sign()
+	dateSign := compareISODate(dt2.date, dt1.date)
+	adjustedDate := dt2.date
+	if timeSign == -dateSign {
+		adjustedDate = adjustedDate.addDays(int64(timeSign))
+		timeDuration = add24HourDays(timeDuration, int64(-timeSign))
+	}
+	dateLargestUnit := largerTemporalUnit(temporalDay, largestUnit)
+	dateDifference := temporalDateUntil(dt1.date, adjustedDate, dateLargestUnit)
+	if largestUnit != dateLargestUnit {
+		timeDuration = add24HourDays(timeDuration, dateDifference.days)
+		dateDifference.days = 0
+	}
+	return internalDuration{date: dateDifference, time: timeDuration}
+}
+
+// temporalDifferenceZonedDateTime implements DifferenceZonedDateTime.
+func (r *Runtime) temporalDifferenceZonedDateTime(ns1, ns2 *big.Int, tz *temporalTimeZone, largestUnit temporalUnit) internalDuration {
+	diff := new(big.Int).Sub(ns2, ns1)
+	if diff.Sign() == 0 {
+		return internalDuration{time: diff}
+	}
+	start := tz.isoDateTimeFor(ns1)
+	end := tz.isoDateTimeFor(ns2)
+	if compareISODate(start.date, end.date) == 0 {
+		return internalDuration{time: diff}
+	}
+	sign := diff.Sign()
+	maxDayCorrection := 1
+	if sign == 1 {
+		maxDayCorrection = 2
+	}
+	dayCorrection := 0
+	if signInt64(end.time.nanos()-start.time.nanos()) == -sign {
+		dayCorrection++
+	}
+	var intermediateDate isoDate
+	var timeDuration *big.Int
+	success := false
+	for ; dayCorrection <= maxDayCorrection && !success; dayCorrection++ {
+		intermediateDate = end.date.addDays(int64(-dayCorrection * sign))
+		intermediateNs := r.temporalEpochNsFor(tz, isoDateTime{date: intermediateDate, time: start.time}, "compatible")
+		timeDuration = new(big.Int).Sub(ns2, intermediateNs)
+		if sign != -timeDuration.Sign() {
+			success = true
+		}
+	}
+	if !success {
+		panic(r.newErrorf(r.getRangeError(), "Unable to compute the difference in time zone %s", tz.id))
+	}
+	dateDifference := temporalDateUntil(start.date, intermediateDate, largerTemporalUnit(largestUnit, temporalDay))
+	return internalDuration{date: dateDifference, time: timeDuration}
+}
+
+// temporalRoundTimeDuration implements RoundTimeDuration.
+func (r *Runtime) temporalRoundTimeDuration(d *big.Int, increment int64, unit temporalUnit, mode string) *big.Int {
+	res := roundBigToIncrement(d, big.NewInt(increment*temporalUnitLengths[unit]), mode)
+	if res.CmpAbs(temporalMaxTimeDurNs) > 0 {
+		panic(r.newErrorf(r.getRangeError(), "Duration is out of range"))
+	}
+	return res
+}
+
+// temporalDifferenceInstant implements DifferenceInstant.
+func (r *Runtime) temporalDifferenceInstant(ns1, ns2 *big.Int, increment int64, unit temporalUnit, mode string) internalDuration {
+	return internalDuration{time: r.temporalRoundTimeDuration(new(big.Int).Sub(ns2, ns1), increment, unit, mode)}
+}
+
+// temporalRelativeStart is the starting point of the relative rounding, tz is nil for plain date-times.
+type temporalRelativeStart struct {
+	dt isoDateTime
+	tz *temporalTimeZone
+}
+
+func (r *Runtime) temporalRelativeEpochNs(start *temporalRelativeStart, dt isoDateTime) *big.Int {
+	if start.tz == nil {
+		return dt.utcEpochNs()
+	}
+	return r.temporalEpochNsFor(start.tz, dt, "compatible")
+}
+
+type temporalNudgeResult struct {
+	duration               internalDuration
+	nudgedEpochNs          *big.Int
+	didExpandCalendarUnit  bool
+	totalNum, totalDenom   *big.Int
+	totalBase, totalFactor int64
+}
+
+// temporalNudgeToCalendarUnit implements NudgeToCalendarUnit. The total is returned as the fraction
+// totalBase + totalNum / totalDenom * totalFactor.
+func (r *Runtime) temporalNudgeToCalendarUnit(sign int64, d internalDuration, destEpochNs *big.Int, start *temporalRelativeStart,
+	increment int64, unit temporalUnit, mode string) temporalNudgeResult {
+	var r1, r2 int64
+	var startDuration, endDuration dateDuration
+	switch unit {
+	case temporalYear:
+		r1 = roundInt64ToIncrement(d.date.years, increment, "trunc")
+		r2 = r1 + increment*sign
+		startDuration = dateDuration{years: r1}
+		endDuration = dateDuration{years: r2}
+	case temporalMonth:
+		r1 = roundInt64ToIncrement(d.date.months, increment, "trunc")
+		r2 = r1 + increment*sign
+		startDuration = dateDuration{years: d.date.years, months: r1}
+		endDuration = dateDuration{years: d.date.years, months: r2}
+	case temporalWeek:
+		weeksStart := r.temporalDateAdd(start.dt.date, dateDuration{years: d.date.years, months: d.date.months}, "constrain")
+		weeksEnd := weeksStart.addDays(d.date.days)
+		untilResult := temporalDateUntil(weeksStart, weeksEnd, temporalWeek)
+		r1 = roundInt64ToIncrement(d.date.weeks+untilResult.weeks, increment, "trunc")
+		r2 = r1 + increment*sign
+		startDuration = dateDuration{years: d.date.years, months: d.date.months, weeks: r1}
+		endDuration = dateDuration{years: d.date.years, months: d.date.months, weeks: r2}
+	default:
+		r1 = roundInt64ToIncrement(d.date.days, increment, "trunc")
+		r2 = r1 + increment*sign
+		startDuration = dateDuration{years: d.date.years, months: d.date.months, weeks: d.date.weeks, days: r1}
+		endDuration = dateDuration{years: d.date.years, months: d.date.months, weeks: d.date.weeks, days: r2}
+	}
+	startDate := r.temporalDateAdd(start.dt.date, startDuration, "constrain")
+	endDate := r.temporalDateAdd(start.dt.date, endDuration, "constrain")
+	startEpochNs := r.temporalRelativeEpochNs(start, isoDateTime{date: startDate, time: start.dt.time})
+	endEpochNs := r.temporalRelativeEpochNs(start, isoDateTime{date: endDate, time: start.dt.time})
+	if sign == 1 && (startEpochNs.Cmp(destEpochNs) > 0 || destEpochNs.Cmp(endEpochNs) > 0) ||
+		sign == -1 && (endEpochNs.Cmp(destEpochNs) > 0 || destEpochNs.Cmp(startEpochNs) > 0) {
+		panic(r.newErrorf(r.getRangeError(), "Unable to round the duration relative to the given date"))
+	}
+	numerator := new(big.Int).Sub(destEpochNs, startEpochNs)
+	denominator := new(big.Int).Sub(endEpochNs, startEpochNs)
+	expand := numerator.Cmp(denominator) == 0
+	if numerator.Sign() != 0 && !expand {
+		twice := new(big.Int).Lsh(new(big.Int).Abs(numerator), 1)
+		expand = roundsUp(unsignedRoundingMode(mode, sign < 0), twice.CmpAbs(denominator), r1%2 != 0)
+	}
+	res := temporalNudgeResult{
+		totalNum:    numerator,
+		totalDenom:  denominator,
+		totalBase:   r1,
+		totalFactor: increment * sign,
+	}
+	if expand {
+		res.didExpandCalendarUnit = true
+		res.duration = internalDuration{date: endDuration, time: new(big.Int)}
+		res.nudgedEpochNs = endEpochNs
+	} else {
+		res.duration = internalDuration{date: startDuration, time: new(big.Int)}
+		res.nudgedEpochNs = startEpochNs
+	}
+	return res
+}
+
+// total returns the total of NudgeToCalendarUnit.
+func (n *temporalNudgeResult) total() float64 {
+	t := new(big.Rat).SetFrac(n.totalNum, n.totalDenom)
+	t.Mul(t, new(big.Rat).SetInt64(n.totalFactor))
+	t.Add(t, new(big.Rat).SetInt64(n.totalBase))
+	f, _ := t.Float64()
+	return f
+}
+
+// temporalNudgeToZonedTime implements NudgeToZonedTime.
+func (r *Runtime) temporalNudgeToZonedTime(sign int64, d internalDuration, start *temporalRelativeStart, increment int64,
+	unit temporalUnit, mode string) temporalNudgeResult {
+	startDate := r.temporalDateAdd(start.dt.date, d.date, "constrain")
+	endDate := startDate.addDays(sign)
+	startEpochNs := r.temporalEpochNsFor(start.tz, isoDateTime{date: startDate, time: start.dt.time}, "compatible")
+	endEpochNs := r.temporalEpochNsFor(start.tz, isoDateTime{date: endDate, time: start.dt.time}, "compatible")
+	daySpan := new(big.Int).Sub(endEpochNs, startEpochNs)
+	unitIncrement := big.NewInt(increment * temporalUnitLengths[unit])
+	roundedTime := roundBigToIncrement(d.time, unitIncrement, mode)
+	beyondDaySpan := new(big.Int).Sub(roundedTime, daySpan)
+	var res temporalNudgeResult
+	dayDelta := int64(0)
+	if beyondDaySpan.Sign() != int(-sign) {
+		res.didExpandCalendarUnit = true
+		dayDelta = sign
+		roundedTime = roundBigToIncrement(beyondDaySpan, unitIncrement, mode)
+		res.nudgedEpochNs = new(big.Int).Add(roundedTime, endEpochNs)
+	} else {
+		res.nudgedEpochNs = new(big.Int).Add(roundedTime, startEpochNs)
+	}
+	date := d.date
+	date.days += dayDelta
+	res.duration = internalDuration{date: date, time: roundedTime}
+	return res
+}
+
+// temporalNudgeToDayOrTime implements NudgeToDayOrTime.
+func (r *Runtime) temporalNudgeToDayOrTime(d internalDuration, destEpochNs *big.Int, largestUnit temporalUnit, increment int64,
+	smallestUnit temporalUnit, mode string) temporalNudgeResult {
+	timeDuration := add24HourDays(d.time, d.date.days)
+	roundedTime := roundBigToIncrement(timeDuration, big.NewInt(increment*temporalUnitLengths[smallestUnit]), mode)
+	diffTime := new(big.Int).Sub(roundedTime, timeDuration)
+	wholeDays := new(big.Int).Quo(timeDuration, bigNsPerDay)
+	roundedWholeDays, remainder := new(big.Int).QuoRem(roundedTime, bigNsPerDay, new(big.Int))
+	dayDelta := new(big.Int).Sub(roundedWholeDays, wholeDays)
+	var res temporalNudgeResult
+	res.didExpandCalendarUnit = dayDelta.Sign() == timeDuration.Sign()
+	res.nudgedEpochNs = new(big.Int).Add(diffTime, destEpochNs)
+	date := d.date
+	date.days = 0
+	if largerTemporalUnit(largestUnit, temporalDay) == largestUnit {
+		date.days = roundedWholeDays.Int64()
+	} else {
+		remainder = roundedTime
+	}
+	res.duration = internalDuration{date: date, time: remainder}
+	return res
+}
+
+// temporalBubbleRelativeDuration implements BubbleRelativeDuration.
+func (r *Runtime) temporalBubbleRelativeDuration(sign int64, d internalDuration, nudgedEpochNs *big.Int, start *temporalRelativeStart,
+	largestUnit, smallestUnit temporalUnit) internalDuration {
+	if smallestUnit == largestUnit {
+		return d
+	}
+	for unit := smallestUnit - 1; unit >= largestUnit; unit-- {
+		if unit == temporalWeek && largestUnit != temporalWeek {
+			continue
+		}
+		var endDuration dateDuration
+		switch unit {
+		case temporalYear:
+			endDuration = dateDuration{years: d.date.years + sign}
+		case temporalMonth:
+			endDuration = dateDuration{years: d.date.years, months: d.date.months + sign}
+		default:
+			endDuration = dateDuration{years: d.date.years, months: d.date.months, weeks: d.date.weeks + sign}
+		}
+		end := r.temporalDateAdd(start.dt.date, endDuration, "constrain")
+		endEpochNs := r.temporalRelativeEpochNs(start, isoDateTime{date: end, time: start.dt.time})
+		beyondEnd := new(big.Int).Sub(nudgedEpochNs, endEpochNs)
+		if beyondEnd.Sign() == int(-sign) {
+			break
+		}
+		d = internalDuration{date: endDuration, time: new(big.Int)}
+	}
+	return d
+}
+
+// temporalRoundRelativeDuration implements RoundRelativeDuration.
+func (r *Runtime) temporalRoundRelativeDuration(d internalDuration, destEpochNs *big.Int, start *temporalRelativeStart,
+	largestUnit temporalUnit, increment int64, smallestUnit temporalUnit, mode string) internalDuration {
+	irregularLengthUnit := smallestUnit.isCalendarUnit() || start.tz != nil && smallestUnit == temporalDay
+	sign := int64(1)
+	if d.sign() < 0 {
+		sign = -1
+	}
+	var nudge temporalNudgeResult
+	switch {
+	case irregularLengthUnit:
+		nudge = r.temporalNudgeToCalendarUnit(sign, d, destEpochNs, start, increment, smallestUnit, mode)
+	case start.tz != nil:
+		nudge = r.temporalNudgeToZonedTime(sign, d, start, increment, smallestUnit, mode)
+	default:
+		nudge = r.temporalNudgeToDayOrTime(d, destEpochNs, largestUnit, increment, smallestUnit, mode)
+	}
+	d = nudge.duration
+	if nudge.didExpandCalendarUnit && smallestUnit != temporalWeek {
+		d = r.temporalBubbleRelativeDuration(sign, d, nudge.nudgedEpochNs, start, largestUnit, largerTemporalUnit(smallestUnit, temporalDay))
+	}
+	return d
+}
+
+// temporalTotalRelativeDuration implements TotalRelativeDuration.
+func (r *Runtime) temporalTotalRelativeDuration(d internalDuration, destEpochNs *big.Int, start *temporalRelativeStart, unit temporalUnit) float64 {
+	if unit.isCalendarUnit() || start.tz != nil && unit == temporalDay {
+		sign := int64(1)
+		if d.sign() < 0 {
+			sign = -1
+		}
+		nudge := r.temporalNudgeToCalendarUnit(sign, d, destEpochNs, start, 1, unit, "trunc")
+		return nudge.total()
+	}
+	return totalTimeDuration(add24HourDays(d.time, d.date.days), unit)
+}
+
+// totalTimeDuration implements TotalTimeDuration.
+func totalTimeDuration(d *big.Int, unit temporalUnit) float64 {
+	f, _ := new(big.Rat).SetFrac(d, big.NewInt(temporalUnitLengths[unit])).Float64()
+	return f
+}
+
+// temporalDifferenceDateTimeWithRounding implements DifferencePlainDateTimeWithRounding.
+func (r *Runtime) temporalDifferenceDateTimeWithRounding(dt1, dt2 isoDateTime, largestUnit temporalUnit, increment int64,
+	smallestUnit temporalUnit, mode string) internalDuration {
+	if compareISODateTime(dt1, dt2) == 0 {
+		return internalDuration{time: new(big.Int)}
+	}
+	diff := temporalDifferenceISODateTime(dt1, dt2, largestUnit)
+	if smallestUnit == temporalNanosecond && increment == 1 {
+		return diff
+	}
+	return r.temporalRoundRelativeDuration(diff, dt2.utcEpochNs(), &temporalRelativeStart{dt: dt1}, largestUnit, increment, smallestUnit, mode)
+}
+
+// temporalDifferenceZonedDateTimeWithRounding implements DifferenceZonedDateTimeWithRounding.
+func (r *Runtime) temporalDifferenceZonedDateTimeWithRounding(ns1, ns2 *big.Int, tz *temporalTimeZone, largestUnit temporalUnit,
+	increment int64, smallestUnit temporalUnit, mode string) internalDuration {
+	if !largestUnit.isDateUnit() {
+		return r.temporalDifferenceInstant(ns1, ns2, increment, smallestUnit, mode)
+	}
+	diff := r.temporalDifferenceZonedDateTime(ns1, ns2, tz, largestUnit)
+	if smallestUnit == temporalNanosecond && increment == 1 {
+		return diff
+	}
+	start := &temporalRelativeStart{dt: tz.isoDateTimeFor(ns1), tz: tz}
+	return r.temporalRoundRelativeDuration(diff, ns2, start, largestUnit, increment, smallestUnit, mode)
+}
+
+// temporalDurationFromInternal calls durationFromInternal and throws if the result is out of range.
+func (r *Runtime) temporalDurationFromInternal(d internalDuration, largestUnit temporalUnit) temporalDuration {
+	res, ok := durationFromInternal(d, largestUnit)
+	if !ok {
+		panic(r.newErrorf(r.getRangeError(), "Duration is out of range"))
+	}
+	return res
+}
+
+// Locale formatting
+
+// temporalToLocaleString formats the time using Intl.DateTimeFormat, kind is the index in
+// intlDateToLocaleKinds. A nil time zone means the time is formatted in UTC, this is used for the
+// plain types whose fields are passed as a UTC time.
+func (r *Runtime) temporalToLocaleString(t time.Time, kind int, tz *temporalTimeZone, locales, options Value) Value {
+	k := intlDateToLocaleKinds[kind]
+	if tz != nil {
+		if opts, ok := options.(*Object); ok && nilSafe(opts.self.getStr("timeZone", nil)) != _undefined {
+			panic(r.NewTypeError("timeZone option is not allowed"))
+		}
+	}
+	dtf := r.newIntlDateTimeFormat(locales, options, k.required, k.defaults)
+	if tz == nil {
+		dtf.timeZone, dtf.loc = "UTC", time.UTC
+	} else {
+		dtf.timeZone, dtf.loc = tz.id, tz.location()
+	}
+	return newStringValue(dtf.format(t))
+}
+
+// isoDateTimeToTime returns the date-time as a UTC time.
+func isoDateTimeToTime(dt isoDateTime) time.Time {
+	return time.Date(dt.date.year, time.Month(dt.date.month), dt.date.day, dt.time.hour, dt.time.minute, dt.time.second,
+		dt.time.subSecondNanos(), time.UTC)
+}
+
+// formatCalendarAnnotation implements FormatCalendarAnnotation for the ISO 8601 calendar.
+func formatCalendarAnnotation(showCalendar string) string {
+	switch showCalendar {
+	case "always":
+		return "[u-ca=" + temporalISOCalendar + "]"
+	case "critical":
+		return "[!u-ca=" + temporalISOCalendar + "]"
+	}
+	return ""
+}
+
+// Getters
+
+func (r *Runtime) putTemporalGetter(o *baseObject, name unistring.String, f func(FunctionCall) Value) {
+	o.setOwnStr(name, &valueProperty{
+		getterFunc:   r.newNativeFunc(f, "get "+name, 0),
+		accessor:     true,
+		configurable: true,
+	}, true)
+}
+
+var temporalDateGetters = []struct {
+	name unistring.String
+	get  func(d isoDate) Value
+}{
+	{"calendarId", func(isoDate) Value { return asciiString(temporalISOCalendar) }},
+	{"era", func(isoDate) Value { return _undefined }},
+	{"eraYear", func(isoDate) Value { return _undefined }},
+	{"year", func(d isoDate) Value { return intToValue(int64(d.year)) }},
+	{"month", func(d isoDate) Value { return intToValue(int64(d.month)) }},
+	{"monthCode", func(d isoDate) Value { return asciiString(monthCodeFor(d.month)) }},
+	{"day", func(d isoDate) Value { return intToValue(int64(d.day)) }},
+	{"dayOfWeek", func(d isoDate) Value { return intToValue(int64(d.dayOfWeek())) }},
+	{"dayOfYear", func(d isoDate) Value { return intToValue(int64(d.dayOfYear())) }},
+	{"weekOfYear", func(d isoDate) Value { w, _ := d.weekOfYear(); return intToValue(int64(w)) }},
+	{"yearOfWeek", func(d isoDate) Value { _, y := d.weekOfYear(); return intToValue(int64(y)) }},
+	{"daysInWeek", func(isoDate) Value { return intToValue(7) }},
+	{"daysInMonth", func(d isoDate) Value { return intToValue(int64(isoDaysInMonth(d.year, d.month))) }},
+	{"daysInYear", func(d isoDate) Value { return intToValue(int64(isoDaysInYear(d.year))) }},
+	{"monthsInYear", func(isoDate) Value { return intToValue(12) }},
+	{"inLeapYear", func(d isoDate) Value { return valueBool(isISOLeapYear(d.year)) }},
+}
+
+var temporalTimeGetters = []struct {
+	name unistring.String
+	get  func(t isoTime) int
+}{
+	{"hour", func(t isoTime) int { return t.hour }},
+	{"minute", func(t isoTime) int { return t.minute }},
+	{"second", func(t isoTime) int { return t.second }},
+	{"millisecond", func(t isoTime) int { return t.millisecond }},
+	{"microsecond", func(t isoTime) int { return t.microsecond }},
+	{"nanosecond", func(t isoTime) int { return t.nanosecond }},
+}
+
+// putTemporalDateGetters defines the calendar getters, date returns the ISO date of the receiver.
+func (r *Runtime) putTemporalDateGetters(o *baseObject, date func(this Value, method string) isoDate) {
+	for _, g := range temporalDateGetters {
+		g := g
+		r.putTemporalGetter(o, g.name, func(call FunctionCall) Value {
+			return g.get(date(call.This, g.name.String()))
+		})
+	}
+}
+
+// putTemporalTimeGetters defines the time getters, tm returns the ISO time of the receiver.
+func (r *Runtime) putTemporalTimeGetters(o *baseObject, tm func(this Value, method string) isoTime) {
+	for _, g := range temporalTimeGetters {
+		g := g
+		r.putTemporalGetter(o, g.name, func(call FunctionCall) Value {
+			return intToValue(int64(g.get(tm(call.This, g.name.String()))))
+		})
+	}
+}
+
+func (r *Runtime) temporalValueOf(call FunctionCall) Value {
+	panic(r.NewTypeError("Do not use valueOf() on Temporal objects, use compare() or equals() to compare them"))
+}
+
+func (r *Runtime) newTemporalBaseObject(self objectImpl, b *baseObject, proto *Object) *Object {
+	o := &Object{runtime: r}
+	b.class = classObject
+	b.val = o
+	b.extensible = true
+	o.self = self
+	b.prototype = proto
+	b.init()
+	return o
+}
+
+// Temporal.Now
+
+func (r *Runtime) temporalNowEpochNs() *big.Int {
+	t := r.now()
+	ns := new(big.Int).Mul(big.NewInt(t.Unix()), bigNsPerSecond)
+	return ns.Add(ns, big.NewInt(int64(t.Nanosecond())))
+}
+
+func (r *Runtime) temporalNowTimeZone(v Value) *temporalTimeZone {
+	if v == _undefined {
+		return r.temporalSystemTimeZone()
+	}
+	return r.toTemporalTimeZone(v)
+}
+
+// temporalNowDateTime implements SystemDateTime.
+func (r *Runtime) temporalNowDateTime(v Value) isoDateTime {
+	tz := r.temporalNowTimeZone(v)
+	return tz.isoDateTimeFor(r.temporalNowEpochNs())
+}
+
+func (r *Runtime) temporalNow_instant(FunctionCall) Value {
+	return r.newTemporalInstant(r.temporalNowEpochNs(), nil)
+}
+
+func (r *Runtime) temporalNow_timeZoneId(FunctionCall) Value {
+	return newStringValue(r.temporalSystemTimeZone().id)
+}
+
+func (r *Runtime) temporalNow_zonedDateTimeISO(call FunctionCall) Value {
+	tz := r.temporalNowTimeZone(call.Argument(0))
+	return r.newTemporalZonedDateTime(r.temporalNowEpochNs(), tz, nil)
+}
+
+func (r *Runtime) temporalNow_plainDateTimeISO(call FunctionCall) Value {
+	return r.newTemporalPlainDateTime(r.temporalNowDateTime(call.Argument(0)), nil)
+}
+
+func (r *Runtime) temporalNow_plainDateISO(call FunctionCall) Value {
+	return r.newTemporalPlainDate(r.temporalNowDateTime(call.Argument(0)).date, nil)
+}
+
+func (r *Runtime) temporalNow_plainTimeISO(call FunctionCall) Value {
+	return r.newTemporalPlainTime(r.temporalNowDateTime(call.Argument(0)).time, nil)
+}
+
+func createTemporalNowTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.global.ObjectPrototype
+	}
+
+	t.putSym(SymToStringTag, func(r *Runtime) Value { return valueProp(asciiString("Temporal.Now"), false, false, true) })
+
+	t.putStr("instant", func(r *Runtime) Value { return r.methodProp(r.temporalNow_instant, "instant", 0) })
+	t.putStr("plainDateISO", func(r *Runtime) Value { return r.methodProp(r.temporalNow_plainDateISO, "plainDateISO", 0) })
+	t.putStr("plainDateTimeISO", func(r *Runtime) Value { return r.methodProp(r.temporalNow_plainDateTimeISO, "plainDateTimeISO", 0) })
+	t.putStr("plainTimeISO", func(r *Runtime) Value { return r.methodProp(r.temporalNow_plainTimeISO, "plainTimeISO", 0) })
+	t.putStr("timeZoneId", func(r *Runtime) Value { return r.methodProp(r.temporalNow_timeZoneId, "timeZoneId", 0) })
+	t.putStr("zonedDateTimeISO", func(r *Runtime) Value { return r.methodProp(r.temporalNow_zonedDateTimeISO, "zonedDateTimeISO", 0) })
+
+	return t
+}
+
+var temporalNowTemplate *objectTemplate
+var temporalNowTemplateOnce sync.Once
+
+func getTemporalNowTemplate() *objectTemplate {
+	temporalNowTemplateOnce.Do(func() {
+		temporalNowTemplate = createTemporalNowTemplate()
+	})
+	return temporalNowTemplate
+}
+
+func (r *Runtime) getTemporalNow() *Object {
+	ret := r.global.TemporalNow
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.TemporalNow = ret
+		r.newTemplatedObject(getTemporalNowTemplate(), ret)
+	}
+	return ret
+}
+
+// Temporal
+
+func createTemporalTemplate() *objectTemplate {
+	t := newObjectTemplate()
+	t.protoFactory = func(r *Runtime) *Object {
+		return r.global.ObjectPrototype
+	}
+
+	t.putSym(SymToStringTag, func(r *Runtime) Value { return valueProp(asciiString(classTemporal), false, false, true) })
+
+	t.putStr("Duration", func(r *Runtime) Value { return valueProp(r.getTemporalDuration(), true, false, true) })
+	t.putStr("Instant", func(r *Runtime) Value { return valueProp(r.getTemporalInstant(), true, false, true) })
+	t.putStr("Now", func(r *Runtime) Value { return valueProp(r.getTemporalNow(), true, false, true) })
+	t.putStr("PlainDate", func(r *Runtime) Value { return valueProp(r.getTemporalPlainDate(), true, false, true) })
+	t.putStr("PlainDateTime", func(r *Runtime) Value { return valueProp(r.getTemporalPlainDateTime(), true, false, true) })
+	t.putStr("PlainTime", func(r *Runtime) Value { return valueProp(r.getTemporalPlainTime(), true, false, true) })
+	t.putStr("ZonedDateTime", func(r *Runtime) Value { return valueProp(r.getTemporalZonedDateTime(), true, false, true) })
+
+	return t
+}
+
+var temporalTemplate *objectTemplate
+var temporalTemplateOnce sync.Once
+
+func getTemporalTemplate() *objectTemplate {
+	temporalTemplateOnce.Do(func() {
+		temporalTemplate = createTemporalTemplate()
+	})
+	return temporalTemplate
+}
+
+func (r *Runtime) getTemporal() *Object {
+	ret := r.global.Temporal
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.Temporal = ret
+		r.newTemplatedObject(getTemporalTemplate(), ret)
+	}
+	return ret
+}
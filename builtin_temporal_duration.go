@@ -0,0 +1,468 @@
+package sobek
+
+import (
+	"math/big"
+
+	"github.com/grafana/sobek/unistring"
+)
+
+type temporalDurationObject struct {
+	baseObject
+	d temporalDuration
+}
+
+// The duration properties in the order they are read by ToTemporalPartialDurationRecord.
+var temporalDurationFieldNames = [...]struct {
+	name unistring.String
+	unit temporalUnit
+}{
+	{"days", temporalDay},
+	{"hours", temporalHour},
+	{"microseconds", temporalMicrosecond},
+	{"milliseconds", temporalMillisecond},
+	{"minutes", temporalMinute},
+	{"months", temporalMonth},
+	{"nanoseconds", temporalNanosecond},
+	{"seconds", temporalSecond},
+	{"weeks", temporalWeek},
+	{"years", temporalYear},
+}
+
+// newTemporalDuration implements CreateTemporalDuration, the duration must be valid.
+func (r *Runtime) newTemporalDuration(d temporalDuration, proto *Object) *Object {
+	if proto == nil {
+		proto = r.getTemporalDurationPrototype()
+	}
+	do := &temporalDurationObject{d: d}
+	return r.newTemporalBaseObject(do, &do.baseObject, proto)
+}
+
+func (r *Runtime) newValidTemporalDuration(d temporalDuration) *Object {
+	if !d.isValid() {
+		panic(r.newErrorf(r.getRangeError(), "Invalid duration"))
+	}
+	return r.newTemporalDuration(d, nil)
+}
+
+func (r *Runtime) toTemporalDurationObject(v Value, method string) *temporalDurationObject {
+	if o, ok := v.(*Object); ok {
+		if do, ok := o.self.(*temporalDurationObject); ok {
+			return do
+		}
+	}
+	panic(r.NewTypeError("Method Temporal.Duration.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+// toTemporalPartialDuration implements ToTemporalPartialDurationRecord, the fields that are not present
+// are taken from d.
+func (r *Runtime) toTemporalPartialDuration(v Value, d temporalDuration) temporalDuration {
+	o, ok := v.(*Object)
+	if !ok {
+		panic(r.NewTypeError("%s is not an object", v.String()))
+	}
+	present := false
+	for _, f := range temporalDurationFieldNames {
+		if v := nilSafe(o.self.getStr(f.name, nil)); v != _undefined {
+			d[f.unit-temporalYear] = r.toIntegerIfIntegral(v)
+			present = true
+		}
+	}
+	if !present {
+		panic(r.NewTypeError("Object must contain at least one duration property"))
+	}
+	return d
+}
+
+// toTemporalDuration implements ToTemporalDuration.
+func (r *Runtime) toTemporalDuration(v Value) temporalDuration {
+	if o, ok := v.(*Object); ok {
+		if do, ok := o.self.(*temporalDurationObject); ok {
+			return do.d
+		}
+		d := r.toTemporalPartialDuration(o, temporalDuration{})
+		if !d.isValid() {
+			panic(r.newErrorf(r.getRangeError(), "Invalid duration"))
+		}
+		return d
+	}
+	s := r.toTemporalString(v)
+	d, ok := parseTemporalDurationString(s)
+	if !ok {
+		panic(r.newErrorf(r.getRangeError(), "Invalid duration string: %s", s))
+	}
+	return d
+}
+
+// temporalRelativeTo is the result of GetTemporalRelativeToOption, tz is not nil for a zoned relativeTo.
+type temporalRelativeTo struct {
+	present bool
+	date    isoDate
+	ns      *big.Int
+	tz      *temporalTimeZone
+}
+
+// temporalGetRelativeToOption implements GetTemporalRelativeToOption.
+func (r *Runtime) temporalGetRelativeToOption(options *Object) temporalRelativeTo {
+	v := getOption(options, "relativeTo")
+	if v == _undefined {
+		return temporalRelativeTo{}
+	}
+	var date isoDate
+	var t *isoTime
+	var tz *temporalTimeZone
+	var offsetNs int64
+	offsetBehaviour := temporalOffsetOption
+	matchMinutes := temporalMatchExactly
+	if o, ok := v.(*Object); ok {
+		switch self := o.self.(type) {
+		case *temporalZonedDateTimeObject:
+			return temporalRelativeTo{present: true, ns: self.ns, tz: self.tz}
+		case *temporalPlainDateObject:
+			return temporalRelativeTo{present: true, date: self.date}
+		case *temporalPlainDateTimeObject:
+			return temporalRelativeTo{present: true, date: self.dt.date}
+		}
+		r.temporalCalendarWithISODefault(o)
+		fields := r.prepareTemporalFields(o, temporalDateFields|temporalTimeFields|1<<temporalFieldOffset|1<<temporalFieldTimeZone, 0, false)
+		dt := r.temporalDateTimeFromFields(fields, "constrain")
+		date, t, tz = dt.date, &dt.time, fields.timeZone
+		if fields.has(temporalFieldOffset) {
+			offsetNs, _ = parseUTCOffsetNs(fields.offset)
+		} else {
+			offsetBehaviour = temporalOffsetWall
+		}
+	} else {
+		s := r.toTemporalString(v)
+		res, ok := parseTemporalString(s, temporalRelativeToString)
+		if !ok {
+			panic(r.temporalInvalidString(s))
+		}
+		if res.timeZone != "" {
+			tz = r.toTemporalTimeZone(newStringValue(res.timeZone))
+			switch {
+			case res.z:
+				offsetBehaviour = temporalOffsetExact
+			case res.offset == "":
+				offsetBehaviour = temporalOffsetWall
+			default:
+				offsetNs, _ = parseUTCOffsetNs(res.offset)
+			}
+			// an offset with seconds must match exactly
+			matchMinutes = len(res.offset) <= len("+00:00")
+		}
+		r.temporalCalendarFromParseResult(res)
+		date = res.date
+		if res.hasTime {
+			t = &res.time
+		}
+	}
+	if tz == nil {
+		if !isoDateWithinLimits(date) {
+			panic(r.newErrorf(r.getRangeError(), "Date is out of range"))
+		}
+		return temporalRelativeTo{present: true, date: date}
+	}
+	ns := r.interpretISODateTimeOffset(date, t, offsetBehaviour, offsetNs, tz, "compatible", "reject", matchMinutes)
+	return temporalRelativeTo{present: true, ns: ns, tz: tz}
+}
+
+// temporalDateDurationDays implements DateDurationDays.
+func (r *Runtime) temporalDateDurationDays(d dateDuration, relativeTo isoDate) int64 {
+	if d.years == 0 && d.months == 0 && d.weeks == 0 {
+		return d.days
+	}
+	later := r.temporalDateAdd(relativeTo, dateDuration{years: d.years, months: d.months, weeks: d.weeks}, "constrain")
+	return d.days + later.epochDays() - relativeTo.epochDays()
+}
+
+// temporalPlainRelativeTarget returns the start and the end of the duration relative to the date.
+func (r *Runtime) temporalPlainRelativeTarget(d *temporalDuration, relativeTo isoDate) (isoDateTime, isoDateTime) {
+	internal := d.internalWith24HourDays()
+	days, targetTime := addTime(isoTime{}, internal.time)
+	dateDur := internal.date
+	dateDur.days = days
+	targetDate := r.temporalDateAdd(relativeTo, dateDur, "constrain")
+	return isoDateTime{date: relativeTo}, isoDateTime{date: targetDate, time: targetTime}
+}
+
+func (r *Runtime) builtin_newTemporalDuration(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		panic(r.needNew("Temporal.Duration"))
+	}
+	var d temporalDuration
+	for i := range d {
+		if v := argAt(args, i); v != _undefined {
+			d[i] = r.toIntegerIfIntegral(v)
+		}
+	}
+	if !d.isValid() {
+		panic(r.newErrorf(r.getRangeError(), "Invalid duration"))
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.global.TemporalDuration, r.getTemporalDurationPrototype())
+	return r.newTemporalDuration(d, proto)
+}
+
+func (r *Runtime) temporalDuration_from(call FunctionCall) Value {
+	return r.newTemporalDuration(r.toTemporalDuration(call.Argument(0)), nil)
+}
+
+func (r *Runtime) temporalDuration_compare(call FunctionCall) Value {
+	one := r.toTemporalDuration(call.Argument(0))
+	two := r.toTemporalDuration(call.Argument(1))
+	relativeTo := r.temporalGetRelativeToOption(r.getOptionsObject(call.Argument(2)))
+	if one == two {
+		return intToValue(0)
+	}
+	largestUnit1, largestUnit2 := one.defaultLargestUnit(), two.defaultLargestUnit()
+	d1, d2 := one.internal(), two.internal()
+	if relativeTo.tz != nil && (largestUnit1.isDateUnit() || largestUnit2.isDateUnit()) {
+		after1 := r.temporalAddZonedDateTime(relativeTo.ns, relativeTo.tz, d1, "constrain")
+		after2 := r.temporalAddZonedDateTime(relativeTo.ns, relativeTo.tz, d2, "constrain")
+		return intToValue(int64(after1.Cmp(after2)))
+	}
+	days1, days2 := d1.date.days, d2.date.days
+	if largestUnit1.isCalendarUnit() || largestUnit2.isCalendarUnit() {
+		if !relativeTo.present {
+			panic(r.newErrorf(r.getRangeError(), "A starting point is required for comparing calendar units"))
+		}
+		days1 = r.temporalDateDurationDays(d1.date, relativeTo.date)
+		days2 = r.temporalDateDurationDays(d2.date, relativeTo.date)
+	}
+	return intToValue(int64(add24HourDays(d1.time, days1).Cmp(add24HourDays(d2.time, days2))))
+}
+
+func (r *Runtime) temporalDurationProto_with(call FunctionCall) Value {
+	do := r.toTemporalDurationObject(call.This, "with")
+	return r.newValidTemporalDuration(r.toTemporalPartialDuration(call.Argument(0), do.d))
+}
+
+func (r *Runtime) temporalDurationProto_negated(call FunctionCall) Value {
+	do := r.toTemporalDurationObject(call.This, "negated")
+	return r.newTemporalDuration(do.d.negated(), nil)
+}
+
+func (r *Runtime) temporalDurationProto_abs(call FunctionCall) Value {
+	do := r.toTemporalDurationObject(call.This, "abs")
+	if do.d.sign() < 0 {
+		return r.newTemporalDuration(do.d.negated(), nil)
+	}
+	return r.newTemporalDuration(do.d, nil)
+}
+
+// temporalAddDurations implements AddDurations.
+func (r *Runtime) temporalAddDurations(d temporalDuration, other Value, sign int) Value {
+	d2 := r.toTemporalDuration(other)
+	if sign < 0 {
+		d2 = d2.negated()
+	}
+	largestUnit := largerTemporalUnit(d.defaultLargestUnit(), d2.defaultLargestUnit())
+	if largestUnit.isCalendarUnit() {
+		panic(r.newErrorf(r.getRangeError(), "Durations with years, months or weeks cannot be added without a starting point"))
+	}
+	t := new(big.Int).Add(d.internalWith24HourDays().time, d2.internalWith24HourDays().time)
+	if t.CmpAbs(temporalMaxTimeDurNs) > 0 {
+		panic(r.newErrorf(r.getRangeError(), "Duration is out of range"))
+	}
+	return r.newTemporalDuration(r.temporalDurationFromInternal(internalDuration{time: t}, largestUnit), nil)
+}
+
+func (r *Runtime) temporalDurationProto_add(call FunctionCall) Value {
+	do := r.toTemporalDurationObject(call.This, "add")
+	return r.temporalAddDurations(do.d, call.Argument(0), 1)
+}
+
+func (r *Runtime) temporalDurationProto_subtract(call FunctionCall) Value {
+	do := r.toTemporalDurationObject(call.This, "subtract")
+	return r.temporalAddDurations(do.d, call.Argument(0), -1)
+}
+
+func (r *Runtime) temporalDurationProto_round(call FunctionCall) Value {
+	do := r.toTemporalDurationObject(call.This, "round")
+	roundTo := r.temporalRoundTo(call.Argument(0))
+	largestUnit := r.temporalGetUnitOption(roundTo, "largestUnit", temporalUnitUnset)
+	relativeTo := r.temporalGetRelativeToOption(roundTo)
+	increment := r.temporalGetRoundingIncrementOption(roundTo)
+	mode := r.temporalGetRoundingModeOption(roundTo, "halfExpand")
+	smallestUnit := r.temporalGetUnitOption(roundTo, "smallestUnit", temporalUnitUnset)
+	r.validateTemporalUnit(smallestUnit, "smallestUnit", temporalDateTimeUnits, temporalUnitUnset)
+	smallestUnitPresent := smallestUnit != temporalUnitUnset
+	if !smallestUnitPresent {
+		smallestUnit = temporalNanosecond
+	}
+	existingLargestUnit := do.d.defaultLargestUnit()
+	defaultLargestUnit := largerTemporalUnit(existingLargestUnit, smallestUnit)
+	largestUnitPresent := largestUnit != temporalUnitUnset
+	if !largestUnitPresent || largestUnit == temporalUnitAuto {
+		largestUnit = defaultLargestUnit
+	}
+	if !smallestUnitPresent && !largestUnitPresent {
+		panic(r.newErrorf(r.getRangeError(), "smallestUnit or largestUnit is required"))
+	}
+	if largerTemporalUnit(largestUnit, smallestUnit) != largestUnit {
+		panic(r.newErrorf(r.getRangeError(), "smallestUnit must be smaller than largestUnit"))
+	}
+	if maximum := smallestUnit.maximumRoundingIncrement(); maximum != 0 {
+		r.validateTemporalRoundingIncrement(increment, maximum, false)
+	}
+	if increment > 1 && largestUnit != smallestUnit && smallestUnit.isDateUnit() {
+		panic(r.newErrorf(r.getRangeError(), "roundingIncrement value is out of range"))
+	}
+	if relativeTo.tz != nil {
+		target := r.temporalAddZonedDateTime(relativeTo.ns, relativeTo.tz, do.d.internal(), "constrain")
+		internal := r.temporalDifferenceZonedDateTimeWithRounding(relativeTo.ns, target, relativeTo.tz, largestUnit, increment, smallestUnit, mode)
+		if largestUnit.isDateUnit() {
+			largestUnit = temporalHour
+		}
+		return r.newTemporalDuration(r.temporalDurationFromInternal(internal, largestUnit), nil)
+	}
+	if relativeTo.present {
+		start, target := r.temporalPlainRelativeTarget(&do.d, relativeTo.date)
+		internal := r.temporalDifferenceDateTimeWithRounding(start, target, largestUnit, increment, smallestUnit, mode)
+		return r.newTemporalDuration(r.temporalDurationFromInternal(internal, largestUnit), nil)
+	}
+	if existingLargestUnit.isCalendarUnit() || largestUnit.isCalendarUnit() {
+		panic(r.newErrorf(r.getRangeError(), "A starting point is required for rounding calendar units"))
+	}
+	internal := do.d.internalWith24HourDays()
+	if smallestUnit == temporalDay {
+		days := roundBigToIncrement(internal.time, new(big.Int).Mul(big.NewInt(increment), bigNsPerDay), mode)
+		internal = internalDuration{date: dateDuration{days: days.Quo(days, bigNsPerDay).Int64()}, time: new(big.Int)}
+	} else {
+		internal = internalDuration{time: r.temporalRoundTimeDuration(internal.time, increment, smallestUnit, mode)}
+	}
+	return r.newTemporalDuration(r.temporalDurationFromInternal(internal, largestUnit), nil)
+}
+
+func (r *Runtime) temporalDurationProto_total(call FunctionCall) Value {
+	do := r.toTemporalDurationObject(call.This, "total")
+	totalOf := call.Argument(0)
+	if totalOf == _undefined {
+		panic(r.NewTypeError("Options parameter is required"))
+	}
+	var options *Object
+	if s, ok := totalOf.(String); ok {
+		options = r.NewObject()
+		options.self.setOwnStr("unit", s, false)
+	} else {
+		options = r.getOptionsObject(totalOf)
+	}
+	relativeTo := r.temporalGetRelativeToOption(options)
+	unit := r.temporalGetUnitOption(options, "unit", temporalUnitUnset)
+	if unit == temporalUnitUnset {
+		panic(r.newErrorf(r.getRangeError(), "unit is required"))
+	}
+	r.validateTemporalUnit(unit, "unit", temporalDateTimeUnits, temporalUnitUnset)
+	var total float64
+	switch {
+	case relativeTo.tz != nil:
+		target := r.temporalAddZonedDateTime(relativeTo.ns, relativeTo.tz, do.d.internal(), "constrain")
+		if !unit.isDateUnit() {
+			total = totalTimeDuration(new(big.Int).Sub(target, relativeTo.ns), unit)
+			break
+		}
+		diff := r.temporalDifferenceZonedDateTime(relativeTo.ns, target, relativeTo.tz, unit)
+		start := &temporalRelativeStart{dt: relativeTo.tz.isoDateTimeFor(relativeTo.ns), tz: relativeTo.tz}
+		total = r.temporalTotalRelativeDuration(diff, target, start, unit)
+	case relativeTo.present:
+		start, target := r.temporalPlainRelativeTarget(&do.d, relativeTo.date)
+		if compareISODateTime(start, target) != 0 {
+			diff := temporalDifferenceISODateTime(start, target, unit)
+			total = r.temporalTotalRelativeDuration(diff, target.utcEpochNs(), &temporalRelativeStart{dt: start}, unit)
+		}
+	default:
+		if do.d.defaultLargestUnit().isCalendarUnit() || unit.isCalendarUnit() {
+			panic(r.newErrorf(r.getRangeError(), "A starting point is required for totaling calendar units"))
+		}
+		total = totalTimeDuration(do.d.internalWith24HourDays().time, unit)
+	}
+	return floatToValue(total)
+}
+
+func (r *Runtime) temporalDurationProto_toString(call FunctionCall) Value {
+	do := r.toTemporalDurationObject(call.This, "toString")
+	options := r.getOptionsObject(call.Argument(0))
+	digits, mode, smallestUnit := r.temporalGetToStringOptions(options)
+	if smallestUnit == temporalMinute {
+		panic(r.newErrorf(r.getRangeError(), "minute is not a valid value for smallestUnit"))
+	}
+	precision := r.temporalToStringPrecision(smallestUnit, digits)
+	if precision.unit == temporalNanosecond && precision.increment == 1 {
+		return asciiString(do.d.format(precision.precision))
+	}
+	largestUnit := do.d.defaultLargestUnit()
+	internal := do.d.internal()
+	internal.time = r.temporalRoundTimeDuration(internal.time, precision.increment, precision.unit, mode)
+	rounded := r.temporalDurationFromInternal(internal, largerTemporalUnit(largestUnit, temporalSecond))
+	return asciiString(rounded.format(precision.precision))
+}
+
+func (r *Runtime) temporalDurationProto_toJSON(call FunctionCall) Value {
+	do := r.toTemporalDurationObject(call.This, "toJSON")
+	return asciiString(do.d.String())
+}
+
+func (r *Runtime) temporalDurationProto_toLocaleString(call FunctionCall) Value {
+	do := r.toTemporalDurationObject(call.This, "toLocaleString")
+	return asciiString(do.d.String())
+}
+
+func (r *Runtime) createTemporalDurationProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
+
+	o._putProp("constructor", r.getTemporalDuration(), true, false, true)
+	for i, name := range [...]unistring.String{"years", "months", "weeks", "days", "hours", "minutes", "seconds", "milliseconds", "microseconds", "nanoseconds"} {
+		name, i := name, i
+		r.putTemporalGetter(o, name, func(call FunctionCall) Value {
+			return floatToValue(r.toTemporalDurationObject(call.This, name.String()).d[i])
+		})
+	}
+	r.putTemporalGetter(o, "sign", func(call FunctionCall) Value {
+		return intToValue(int64(r.toTemporalDurationObject(call.This, "sign").d.sign()))
+	})
+	r.putTemporalGetter(o, "blank", func(call FunctionCall) Value {
+		return valueBool(r.toTemporalDurationObject(call.This, "blank").d.sign() == 0)
+	})
+	o._putProp("abs", r.newNativeFunc(r.temporalDurationProto_abs, "abs", 0), true, false, true)
+	o._putProp("add", r.newNativeFunc(r.temporalDurationProto_add, "add", 1), true, false, true)
+	o._putProp("negated", r.newNativeFunc(r.temporalDurationProto_negated, "negated", 0), true, false, true)
+	o._putProp("round", r.newNativeFunc(r.temporalDurationProto_round, "round", 1), true, false, true)
+	o._putProp("subtract", r.newNativeFunc(r.temporalDurationProto_subtract, "subtract", 1), true, false, true)
+	o._putProp("toJSON", r.newNativeFunc(r.temporalDurationProto_toJSON, "toJSON", 0), true, false, true)
+	o._putProp("toLocaleString", r.newNativeFunc(r.temporalDurationProto_toLocaleString, "toLocaleString", 0), true, false, true)
+	o._putProp("toString", r.newNativeFunc(r.temporalDurationProto_toString, "toString", 0), true, false, true)
+	o._putProp("total", r.newNativeFunc(r.temporalDurationProto_total, "total", 1), true, false, true)
+	o._putProp("valueOf", r.newNativeFunc(r.temporalValueOf, "valueOf", 0), true, false, true)
+	o._putProp("with", r.newNativeFunc(r.temporalDurationProto_with, "with", 1), true, false, true)
+
+	o._putSym(SymToStringTag, valueProp(asciiString("Temporal.Duration"), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) createTemporalDuration(val *Object) objectImpl {
+	o := r.newNativeConstructOnly(val, r.builtin_newTemporalDuration, r.getTemporalDurationPrototype(), "Duration", 0)
+	o._putProp("compare", r.newNativeFunc(r.temporalDuration_compare, "compare", 2), true, false, true)
+	o._putProp("from", r.newNativeFunc(r.temporalDuration_from, "from", 1), true, false, true)
+
+	return o
+}
+
+func (r *Runtime) getTemporalDurationPrototype() *Object {
+	ret := r.global.TemporalDurationPrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.TemporalDurationPrototype = ret
+		ret.self = r.createTemporalDurationProto(ret)
+	}
+	return ret
+}
+
+func (r *Runtime) getTemporalDuration() *Object {
+	ret := r.global.TemporalDuration
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.TemporalDuration = ret
+		ret.self = r.createTemporalDuration(ret)
+	}
+	return ret
+}
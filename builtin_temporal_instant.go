@@ -0,0 +1,277 @@
+package sobek
+
+import (
+	"math/big"
+	"reflect"
+	"time"
+)
+
+type temporalInstantObject struct {
+	baseObject
+	ns *big.Int
+}
+
+func (i *temporalInstantObject) exportType() reflect.Type {
+	return typeTime
+}
+
+func (i *temporalInstantObject) export(*objectExportCtx) interface{} {
+	return temporalEpochNsToTime(i.ns)
+}
+
+func temporalEpochNsToTime(ns *big.Int) time.Time {
+	sec, nsec := new(big.Int).DivMod(ns, bigNsPerSecond, new(big.Int))
+	return time.Unix(sec.Int64(), nsec.Int64())
+}
+
+// newTemporalInstant implements CreateTemporalInstant, the epoch nanoseconds must be valid.
+func (r *Runtime) newTemporalInstant(ns *big.Int, proto *Object) *Object {
+	if proto == nil {
+		proto = r.getTemporalInstantPrototype()
+	}
+	i := &temporalInstantObject{ns: ns}
+	return r.newTemporalBaseObject(i, &i.baseObject, proto)
+}
+
+func (r *Runtime) toTemporalInstantObject(v Value, method string) *temporalInstantObject {
+	if o, ok := v.(*Object); ok {
+		if i, ok := o.self.(*temporalInstantObject); ok {
+			return i
+		}
+	}
+	panic(r.NewTypeError("Method Temporal.Instant.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+func (r *Runtime) checkEpochNs(ns *big.Int) *big.Int {
+	if !isValidEpochNs(ns) {
+		panic(r.newErrorf(r.getRangeError(), "Instant is out of range"))
+	}
+	return ns
+}
+
+// toTemporalInstant implements ToTemporalInstant and returns the epoch nanoseconds.
+func (r *Runtime) toTemporalInstant(v Value) *big.Int {
+	if o, ok := v.(*Object); ok {
+		switch self := o.self.(type) {
+		case *temporalInstantObject:
+			return self.ns
+		case *temporalZonedDateTimeObject:
+			return self.ns
+		}
+		v = o.toPrimitiveString()
+	}
+	s := r.toTemporalString(v)
+	res, ok := parseTemporalString(s, temporalInstantString)
+	if !ok {
+		panic(r.temporalInvalidString(s))
+	}
+	var offsetNs int64
+	if !res.z {
+		offsetNs, _ = parseUTCOffsetNs(res.offset)
+	}
+	balanced := isoDateTime{date: res.date, time: res.time}.addNanos(-offsetNs)
+	r.checkISODaysRange(balanced.date)
+	return r.checkEpochNs(balanced.utcEpochNs())
+}
+
+// temporalInstantToString implements TemporalInstantToString, tz may be nil.
+func temporalInstantToString(ns *big.Int, tz *temporalTimeZone, precision int) string {
+	outputTimeZone := tz
+	if outputTimeZone == nil {
+		outputTimeZone = &temporalTimeZone{id: "UTC"}
+	}
+	offsetNs := outputTimeZone.offsetNsFor(ns)
+	s := isoDateTimeFromEpochNs(ns, offsetNs).format(precision)
+	if tz == nil {
+		return s + "Z"
+	}
+	return s + formatDateTimeUTCOffsetRounded(offsetNs)
+}
+
+// temporalRoundInstant implements RoundTemporalInstant.
+func temporalRoundInstant(ns *big.Int, increment int64, unit temporalUnit, mode string) *big.Int {
+	return roundBigToIncrementAsIfPositive(ns, big.NewInt(increment*temporalUnitLengths[unit]), mode)
+}
+
+func (r *Runtime) builtin_newTemporalInstant(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		panic(r.needNew("Temporal.Instant"))
+	}
+	ns := r.checkEpochNs((*big.Int)(toBigInt(argAt(args, 0))))
+	proto := r.getPrototypeFromCtor(newTarget, r.global.TemporalInstant, r.getTemporalInstantPrototype())
+	return r.newTemporalInstant(ns, proto)
+}
+
+func (r *Runtime) temporalInstant_from(call FunctionCall) Value {
+	return r.newTemporalInstant(r.toTemporalInstant(call.Argument(0)), nil)
+}
+
+func (r *Runtime) temporalInstant_fromEpochMilliseconds(call FunctionCall) Value {
+	ms := (*big.Int)(numberToBigInt(call.Argument(0).ToNumber()))
+	return r.newTemporalInstant(r.checkEpochNs(new(big.Int).Mul(ms, big.NewInt(nsPerMillisecond))), nil)
+}
+
+func (r *Runtime) temporalInstant_fromEpochNanoseconds(call FunctionCall) Value {
+	ns := (*big.Int)(toBigInt(call.Argument(0)))
+	return r.newTemporalInstant(r.checkEpochNs(new(big.Int).Set(ns)), nil)
+}
+
+func (r *Runtime) temporalInstant_compare(call FunctionCall) Value {
+	one := r.toTemporalInstant(call.Argument(0))
+	two := r.toTemporalInstant(call.Argument(1))
+	return intToValue(int64(one.Cmp(two)))
+}
+
+// temporalAddDurationToInstant implements AddDurationToInstant.
+func (r *Runtime) temporalAddDurationToInstant(ns *big.Int, other Value, sign int) Value {
+	d := r.toTemporalDuration(other)
+	if sign < 0 {
+		d = d.negated()
+	}
+	if d.defaultLargestUnit().isDateUnit() {
+		panic(r.newErrorf(r.getRangeError(), "Durations with date units cannot be added to an Instant"))
+	}
+	return r.newTemporalInstant(r.temporalAddInstant(ns, d.internalWith24HourDays().time), nil)
+}
+
+func (r *Runtime) temporalInstantProto_add(call FunctionCall) Value {
+	i := r.toTemporalInstantObject(call.This, "add")
+	return r.temporalAddDurationToInstant(i.ns, call.Argument(0), 1)
+}
+
+func (r *Runtime) temporalInstantProto_subtract(call FunctionCall) Value {
+	i := r.toTemporalInstantObject(call.This, "subtract")
+	return r.temporalAddDurationToInstant(i.ns, call.Argument(0), -1)
+}
+
+// temporalDifferenceInstantValues implements DifferenceTemporalInstant.
+func (r *Runtime) temporalDifferenceInstantValues(since bool, ns *big.Int, other, options Value) Value {
+	otherNs := r.toTemporalInstant(other)
+	s := r.temporalGetDifferenceSettings(since, r.getOptionsObject(options), temporalTimeUnits, temporalNanosecond, temporalSecond)
+	internal := r.temporalDifferenceInstant(ns, otherNs, s.roundingIncrement, s.smallestUnit, s.roundingMode)
+	d := r.temporalDurationFromInternal(internal, s.largestUnit)
+	if since {
+		d = d.negated()
+	}
+	return r.newTemporalDuration(d, nil)
+}
+
+func (r *Runtime) temporalInstantProto_until(call FunctionCall) Value {
+	i := r.toTemporalInstantObject(call.This, "until")
+	return r.temporalDifferenceInstantValues(false, i.ns, call.Argument(0), call.Argument(1))
+}
+
+func (r *Runtime) temporalInstantProto_since(call FunctionCall) Value {
+	i := r.toTemporalInstantObject(call.This, "since")
+	return r.temporalDifferenceInstantValues(true, i.ns, call.Argument(0), call.Argument(1))
+}
+
+func (r *Runtime) temporalInstantProto_round(call FunctionCall) Value {
+	i := r.toTemporalInstantObject(call.This, "round")
+	roundTo := r.temporalRoundTo(call.Argument(0))
+	increment := r.temporalGetRoundingIncrementOption(roundTo)
+	mode := r.temporalGetRoundingModeOption(roundTo, "halfExpand")
+	smallestUnit := r.temporalGetUnitOption(roundTo, "smallestUnit", temporalUnitUnset)
+	if smallestUnit == temporalUnitUnset {
+		panic(r.newErrorf(r.getRangeError(), "smallestUnit is required"))
+	}
+	r.validateTemporalUnit(smallestUnit, "smallestUnit", temporalTimeUnits, temporalUnitUnset)
+	r.validateTemporalRoundingIncrement(increment, nsPerDay/temporalUnitLengths[smallestUnit], true)
+	return r.newTemporalInstant(temporalRoundInstant(i.ns, increment, smallestUnit, mode), nil)
+}
+
+func (r *Runtime) temporalInstantProto_equals(call FunctionCall) Value {
+	i := r.toTemporalInstantObject(call.This, "equals")
+	other := r.toTemporalInstant(call.Argument(0))
+	return valueBool(i.ns.Cmp(other) == 0)
+}
+
+func (r *Runtime) temporalInstantProto_toString(call FunctionCall) Value {
+	i := r.toTemporalInstantObject(call.This, "toString")
+	options := r.getOptionsObject(call.Argument(0))
+	digits, mode, smallestUnit := r.temporalGetToStringOptions(options)
+	var tz *temporalTimeZone
+	tzValue := getOption(options, "timeZone")
+	precision := r.temporalToStringPrecision(smallestUnit, digits)
+	if tzValue != _undefined {
+		tz = r.toTemporalTimeZone(tzValue)
+	}
+	ns := temporalRoundInstant(i.ns, precision.increment, precision.unit, mode)
+	return asciiString(temporalInstantToString(ns, tz, precision.precision))
+}
+
+func (r *Runtime) temporalInstantProto_toJSON(call FunctionCall) Value {
+	i := r.toTemporalInstantObject(call.This, "toJSON")
+	return asciiString(temporalInstantToString(i.ns, nil, temporalPrecisionAuto))
+}
+
+func (r *Runtime) temporalInstantProto_toLocaleString(call FunctionCall) Value {
+	i := r.toTemporalInstantObject(call.This, "toLocaleString")
+	return r.dateToLocaleString(temporalEpochNsToTime(i.ns), 0, call.Argument(0), call.Argument(1))
+}
+
+func (r *Runtime) temporalInstantProto_toZonedDateTimeISO(call FunctionCall) Value {
+	i := r.toTemporalInstantObject(call.This, "toZonedDateTimeISO")
+	tz := r.toTemporalTimeZone(call.Argument(0))
+	return r.newTemporalZonedDateTime(i.ns, tz, nil)
+}
+
+func (r *Runtime) createTemporalInstantProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
+
+	o._putProp("constructor", r.getTemporalInstant(), true, false, true)
+	r.putTemporalGetter(o, "epochMilliseconds", func(call FunctionCall) Value {
+		i := r.toTemporalInstantObject(call.This, "epochMilliseconds")
+		ms, _ := new(big.Int).DivMod(i.ns, big.NewInt(nsPerMillisecond), new(big.Int))
+		return intToValue(ms.Int64())
+	})
+	r.putTemporalGetter(o, "epochNanoseconds", func(call FunctionCall) Value {
+		i := r.toTemporalInstantObject(call.This, "epochNanoseconds")
+		return (*valueBigInt)(new(big.Int).Set(i.ns))
+	})
+	o._putProp("add", r.newNativeFunc(r.temporalInstantProto_add, "add", 1), true, false, true)
+	o._putProp("equals", r.newNativeFunc(r.temporalInstantProto_equals, "equals", 1), true, false, true)
+	o._putProp("round", r.newNativeFunc(r.temporalInstantProto_round, "round", 1), true, false, true)
+	o._putProp("since", r.newNativeFunc(r.temporalInstantProto_since, "since", 1), true, false, true)
+	o._putProp("subtract", r.newNativeFunc(r.temporalInstantProto_subtract, "subtract", 1), true, false, true)
+	o._putProp("toJSON", r.newNativeFunc(r.temporalInstantProto_toJSON, "toJSON", 0), true, false, true)
+	o._putProp("toLocaleString", r.newNativeFunc(r.temporalInstantProto_toLocaleString, "toLocaleString", 0), true, false, true)
+	o._putProp("toString", r.newNativeFunc(r.temporalInstantProto_toString, "toString", 0), true, false, true)
+	o._putProp("toZonedDateTimeISO", r.newNativeFunc(r.temporalInstantProto_toZonedDateTimeISO, "toZonedDateTimeISO", 1), true, false, true)
+	o._putProp("until", r.newNativeFunc(r.temporalInstantProto_until, "until", 1), true, false, true)
+	o._putProp("valueOf", r.newNativeFunc(r.temporalValueOf, "valueOf", 0), true, false, true)
+
+	o._putSym(SymToStringTag, valueProp(asciiString("Temporal.Instant"), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) createTemporalInstant(val *Object) objectImpl {
+	o := r.newNativeConstructOnly(val, r.builtin_newTemporalInstant, r.getTemporalInstantPrototype(), "Instant", 1)
+	o._putProp("compare", r.newNativeFunc(r.temporalInstant_compare, "compare", 2), true, false, true)
+	o._putProp("from", r.newNativeFunc(r.temporalInstant_from, "from", 1), true, false, true)
+	o._putProp("fromEpochMilliseconds", r.newNativeFunc(r.temporalInstant_fromEpochMilliseconds, "fromEpochMilliseconds", 1), true, false, true)
+	o._putProp("fromEpochNanoseconds", r.newNativeFunc(r.temporalInstant_fromEpochNanoseconds, "fromEpochNanoseconds", 1), true, false, true)
+
+	return o
+}
+
+func (r *Runtime) getTemporalInstantPrototype() *Object {
+	ret := r.global.TemporalInstantPrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.TemporalInstantPrototype = ret
+		ret.self = r.createTemporalInstantProto(ret)
+	}
+	return ret
+}
+
+func (r *Runtime) getTemporalInstant() *Object {
+	ret := r.global.TemporalInstant
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.TemporalInstant = ret
+		ret.self = r.createTemporalInstant(ret)
+	}
+	return ret
+}
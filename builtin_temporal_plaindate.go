@@ -0,0 +1,264 @@
+package sobek
+
+import "math/big"
+
+type temporalPlainDateObject struct {
+	baseObject
+	date isoDate
+}
+
+// newTemporalPlainDate implements CreateTemporalDate, the date must be within the limits.
+func (r *Runtime) newTemporalPlainDate(d isoDate, proto *Object) *Object {
+	if proto == nil {
+		proto = r.getTemporalPlainDatePrototype()
+	}
+	pd := &temporalPlainDateObject{date: d}
+	return r.newTemporalBaseObject(pd, &pd.baseObject, proto)
+}
+
+func (r *Runtime) toTemporalPlainDateObject(v Value, method string) *temporalPlainDateObject {
+	if o, ok := v.(*Object); ok {
+		if pd, ok := o.self.(*temporalPlainDateObject); ok {
+			return pd
+		}
+	}
+	panic(r.NewTypeError("Method Temporal.PlainDate.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+// toTemporalDate implements ToTemporalDate.
+func (r *Runtime) toTemporalDate(v, options Value) isoDate {
+	if o, ok := v.(*Object); ok {
+		var d isoDate
+		switch self := o.self.(type) {
+		case *temporalPlainDateObject:
+			d = self.date
+		case *temporalPlainDateTimeObject:
+			d = self.dt.date
+		case *temporalZonedDateTimeObject:
+			d = self.tz.isoDateTimeFor(self.ns).date
+		default:
+			r.temporalCalendarWithISODefault(o)
+			fields := r.prepareTemporalFields(o, temporalDateFields, 0, false)
+			overflow := r.temporalGetOverflowOption(r.getOptionsObject(options))
+			return r.temporalDateFromFields(fields, overflow)
+		}
+		r.temporalGetOverflowOption(r.getOptionsObject(options))
+		return d
+	}
+	s := r.toTemporalString(v)
+	res, ok := parseTemporalString(s, temporalDateTimeString)
+	if !ok {
+		panic(r.temporalInvalidString(s))
+	}
+	r.temporalCalendarFromParseResult(res)
+	r.temporalGetOverflowOption(r.getOptionsObject(options))
+	if !isoDateWithinLimits(res.date) {
+		panic(r.newErrorf(r.getRangeError(), "Date is out of range"))
+	}
+	return res.date
+}
+
+func (r *Runtime) builtin_newTemporalPlainDate(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		panic(r.needNew("Temporal.PlainDate"))
+	}
+	y := r.toIntegerWithTruncation(argAt(args, 0))
+	m := r.toIntegerWithTruncation(argAt(args, 1))
+	d := r.toIntegerWithTruncation(argAt(args, 2))
+	if c := argAt(args, 3); c != _undefined {
+		r.toCalendarArgument(c)
+	}
+	if !isValidISODateFloat(y, m, d) {
+		panic(r.newErrorf(r.getRangeError(), "Invalid date"))
+	}
+	date := isoDate{year: int(y), month: int(m), day: int(d)}
+	if !isoDateWithinLimits(date) {
+		panic(r.newErrorf(r.getRangeError(), "Date is out of range"))
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.global.TemporalPlainDate, r.getTemporalPlainDatePrototype())
+	return r.newTemporalPlainDate(date, proto)
+}
+
+func (r *Runtime) temporalPlainDate_from(call FunctionCall) Value {
+	return r.newTemporalPlainDate(r.toTemporalDate(call.Argument(0), call.Argument(1)), nil)
+}
+
+func (r *Runtime) temporalPlainDate_compare(call FunctionCall) Value {
+	one := r.toTemporalDate(call.Argument(0), _undefined)
+	two := r.toTemporalDate(call.Argument(1), _undefined)
+	return intToValue(int64(compareISODate(one, two)))
+}
+
+func (r *Runtime) temporalPlainDateProto_with(call FunctionCall) Value {
+	pd := r.toTemporalPlainDateObject(call.This, "with")
+	like := r.toTemporalLikeObject(call.Argument(0))
+	fields := isoDateFields(pd.date)
+	partial := r.prepareTemporalFields(like, temporalDateFields, 0, true)
+	fields = mergeTemporalFields(fields, partial)
+	overflow := r.temporalGetOverflowOption(r.getOptionsObject(call.Argument(1)))
+	return r.newTemporalPlainDate(r.temporalDateFromFields(fields, overflow), nil)
+}
+
+func (r *Runtime) temporalPlainDateProto_withCalendar(call FunctionCall) Value {
+	pd := r.toTemporalPlainDateObject(call.This, "withCalendar")
+	r.toTemporalCalendarIdentifier(call.Argument(0))
+	return r.newTemporalPlainDate(pd.date, nil)
+}
+
+// temporalAddDurationToDate implements AddDurationToDate.
+func (r *Runtime) temporalAddDurationToDate(d isoDate, other, options Value, sign int) Value {
+	dur := r.toTemporalDuration(other)
+	if sign < 0 {
+		dur = dur.negated()
+	}
+	dateDur := dur.dateDurationWithoutTime()
+	overflow := r.temporalGetOverflowOption(r.getOptionsObject(options))
+	return r.newTemporalPlainDate(r.temporalDateAdd(d, dateDur, overflow), nil)
+}
+
+func (r *Runtime) temporalPlainDateProto_add(call FunctionCall) Value {
+	pd := r.toTemporalPlainDateObject(call.This, "add")
+	return r.temporalAddDurationToDate(pd.date, call.Argument(0), call.Argument(1), 1)
+}
+
+func (r *Runtime) temporalPlainDateProto_subtract(call FunctionCall) Value {
+	pd := r.toTemporalPlainDateObject(call.This, "subtract")
+	return r.temporalAddDurationToDate(pd.date, call.Argument(0), call.Argument(1), -1)
+}
+
+// temporalDifferencePlainDate implements DifferenceTemporalPlainDate.
+func (r *Runtime) temporalDifferencePlainDate(since bool, d isoDate, other, options Value) Value {
+	otherDate := r.toTemporalDate(other, _undefined)
+	s := r.temporalGetDifferenceSettings(since, r.getOptionsObject(options), temporalDateUnits, temporalDay, temporalDay)
+	if compareISODate(d, otherDate) == 0 {
+		return r.newTemporalDuration(temporalDuration{}, nil)
+	}
+	internal := internalDuration{date: temporalDateUntil(d, otherDate, s.largestUnit), time: new(big.Int)}
+	if s.smallestUnit != temporalDay || s.roundingIncrement != 1 {
+		dest := isoDateTime{date: otherDate}
+		internal = r.temporalRoundRelativeDuration(internal, dest.utcEpochNs(), &temporalRelativeStart{dt: isoDateTime{date: d}},
+			s.largestUnit, s.roundingIncrement, s.smallestUnit, s.roundingMode)
+	}
+	res := r.temporalDurationFromInternal(internal, temporalDay)
+	if since {
+		res = res.negated()
+	}
+	return r.newTemporalDuration(res, nil)
+}
+
+func (r *Runtime) temporalPlainDateProto_until(call FunctionCall) Value {
+	pd := r.toTemporalPlainDateObject(call.This, "until")
+	return r.temporalDifferencePlainDate(false, pd.date, call.Argument(0), call.Argument(1))
+}
+
+func (r *Runtime) temporalPlainDateProto_since(call FunctionCall) Value {
+	pd := r.toTemporalPlainDateObject(call.This, "since")
+	return r.temporalDifferencePlainDate(true, pd.date, call.Argument(0), call.Argument(1))
+}
+
+func (r *Runtime) temporalPlainDateProto_equals(call FunctionCall) Value {
+	pd := r.toTemporalPlainDateObject(call.This, "equals")
+	other := r.toTemporalDate(call.Argument(0), _undefined)
+	return valueBool(compareISODate(pd.date, other) == 0)
+}
+
+func (r *Runtime) temporalPlainDateProto_toPlainDateTime(call FunctionCall) Value {
+	pd := r.toTemporalPlainDateObject(call.This, "toPlainDateTime")
+	t := r.toTimeRecordOrMidnight(call.Argument(0))
+	return r.newValidTemporalPlainDateTime(isoDateTime{date: pd.date, time: t})
+}
+
+func (r *Runtime) temporalPlainDateProto_toZonedDateTime(call FunctionCall) Value {
+	pd := r.toTemporalPlainDateObject(call.This, "toZonedDateTime")
+	item := call.Argument(0)
+	var tz *temporalTimeZone
+	temporalTime := _undefined
+	if o, ok := item.(*Object); ok {
+		if tzLike := nilSafe(o.self.getStr("timeZone", nil)); tzLike == _undefined {
+			tz = r.toTemporalTimeZone(o)
+		} else {
+			tz = r.toTemporalTimeZone(tzLike)
+			temporalTime = nilSafe(o.self.getStr("plainTime", nil))
+		}
+	} else {
+		tz = r.toTemporalTimeZone(item)
+	}
+	if temporalTime == _undefined {
+		return r.newTemporalZonedDateTime(r.temporalStartOfDay(tz, pd.date), tz, nil)
+	}
+	dt := isoDateTime{date: pd.date, time: r.toTemporalTime(temporalTime, _undefined)}
+	if !isoDateTimeWithinLimits(dt) {
+		panic(r.newErrorf(r.getRangeError(), "Date-time is out of range"))
+	}
+	return r.newTemporalZonedDateTime(r.temporalEpochNsFor(tz, dt, "compatible"), tz, nil)
+}
+
+func (r *Runtime) temporalPlainDateProto_toString(call FunctionCall) Value {
+	pd := r.toTemporalPlainDateObject(call.This, "toString")
+	showCalendar := r.temporalGetShowCalendarOption(r.getOptionsObject(call.Argument(0)))
+	return asciiString(pd.date.String() + formatCalendarAnnotation(showCalendar))
+}
+
+func (r *Runtime) temporalPlainDateProto_toJSON(call FunctionCall) Value {
+	pd := r.toTemporalPlainDateObject(call.This, "toJSON")
+	return asciiString(pd.date.String())
+}
+
+func (r *Runtime) temporalPlainDateProto_toLocaleString(call FunctionCall) Value {
+	pd := r.toTemporalPlainDateObject(call.This, "toLocaleString")
+	return r.temporalToLocaleString(isoDateTimeToTime(isoDateTime{date: pd.date}), 1, nil, call.Argument(0), call.Argument(1))
+}
+
+func (r *Runtime) createTemporalPlainDateProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
+
+	o._putProp("constructor", r.getTemporalPlainDate(), true, false, true)
+	r.putTemporalDateGetters(o, func(this Value, method string) isoDate {
+		return r.toTemporalPlainDateObject(this, method).date
+	})
+	o._putProp("add", r.newNativeFunc(r.temporalPlainDateProto_add, "add", 1), true, false, true)
+	o._putProp("equals", r.newNativeFunc(r.temporalPlainDateProto_equals, "equals", 1), true, false, true)
+	o._putProp("since", r.newNativeFunc(r.temporalPlainDateProto_since, "since", 1), true, false, true)
+	o._putProp("subtract", r.newNativeFunc(r.temporalPlainDateProto_subtract, "subtract", 1), true, false, true)
+	o._putProp("toJSON", r.newNativeFunc(r.temporalPlainDateProto_toJSON, "toJSON", 0), true, false, true)
+	o._putProp("toLocaleString", r.newNativeFunc(r.temporalPlainDateProto_toLocaleString, "toLocaleString", 0), true, false, true)
+	o._putProp("toPlainDateTime", r.newNativeFunc(r.temporalPlainDateProto_toPlainDateTime, "toPlainDateTime", 0), true, false, true)
+	o._putProp("toString", r.newNativeFunc(r.temporalPlainDateProto_toString, "toString", 0), true, false, true)
+	o._putProp("toZonedDateTime", r.newNativeFunc(r.temporalPlainDateProto_toZonedDateTime, "toZonedDateTime", 1), true, false, true)
+	o._putProp("until", r.newNativeFunc(r.temporalPlainDateProto_until, "until", 1), true, false, true)
+	o._putProp("valueOf", r.newNativeFunc(r.temporalValueOf, "valueOf", 0), true, false, true)
+	o._putProp("with", r.newNativeFunc(r.temporalPlainDateProto_with, "with", 1), true, false, true)
+	o._putProp("withCalendar", r.newNativeFunc(r.temporalPlainDateProto_withCalendar, "withCalendar", 1), true, false, true)
+
+	o._putSym(SymToStringTag, valueProp(asciiString("Temporal.PlainDate"), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) createTemporalPlainDate(val *Object) objectImpl {
+	o := r.newNativeConstructOnly(val, r.builtin_newTemporalPlainDate, r.getTemporalPlainDatePrototype(), "PlainDate", 3)
+	o._putProp("compare", r.newNativeFunc(r.temporalPlainDate_compare, "compare", 2), true, false, true)
+	o._putProp("from", r.newNativeFunc(r.temporalPlainDate_from, "from", 1), true, false, true)
+
+	return o
+}
+
+func (r *Runtime) getTemporalPlainDatePrototype() *Object {
+	ret := r.global.TemporalPlainDatePrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.TemporalPlainDatePrototype = ret
+		ret.self = r.createTemporalPlainDateProto(ret)
+	}
+	return ret
+}
+
+func (r *Runtime) getTemporalPlainDate() *Object {
+	ret := r.global.TemporalPlainDate
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.TemporalPlainDate = ret
+		ret.self = r.createTemporalPlainDate(ret)
+	}
+	return ret
+}
@@ -0,0 +1,306 @@
+package sobek
+
+type temporalPlainDateTimeObject struct {
+	baseObject
+	dt isoDateTime
+}
+
+// newTemporalPlainDateTime implements CreateTemporalDateTime, the date-time must be within the limits.
+func (r *Runtime) newTemporalPlainDateTime(dt isoDateTime, proto *Object) *Object {
+	if proto == nil {
+		proto = r.getTemporalPlainDateTimePrototype()
+	}
+	pdt := &temporalPlainDateTimeObject{dt: dt}
+	return r.newTemporalBaseObject(pdt, &pdt.baseObject, proto)
+}
+
+func (r *Runtime) newValidTemporalPlainDateTime(dt isoDateTime) *Object {
+	if !isoDateTimeWithinLimits(dt) {
+		panic(r.newErrorf(r.getRangeError(), "Date-time is out of range"))
+	}
+	return r.newTemporalPlainDateTime(dt, nil)
+}
+
+func (r *Runtime) toTemporalPlainDateTimeObject(v Value, method string) *temporalPlainDateTimeObject {
+	if o, ok := v.(*Object); ok {
+		if pdt, ok := o.self.(*temporalPlainDateTimeObject); ok {
+			return pdt
+		}
+	}
+	panic(r.NewTypeError("Method Temporal.PlainDateTime.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+// toTemporalDateTime implements ToTemporalDateTime.
+func (r *Runtime) toTemporalDateTime(v, options Value) isoDateTime {
+	if o, ok := v.(*Object); ok {
+		var dt isoDateTime
+		switch self := o.self.(type) {
+		case *temporalPlainDateTimeObject:
+			dt = self.dt
+		case *temporalZonedDateTimeObject:
+			dt = self.tz.isoDateTimeFor(self.ns)
+		case *temporalPlainDateObject:
+			dt = isoDateTime{date: self.date}
+		default:
+			r.temporalCalendarWithISODefault(o)
+			fields := r.prepareTemporalFields(o, temporalDateFields|temporalTimeFields, 0, false)
+			overflow := r.temporalGetOverflowOption(r.getOptionsObject(options))
+			dt = r.temporalDateTimeFromFields(fields, overflow)
+			if !isoDateTimeWithinLimits(dt) {
+				panic(r.newErrorf(r.getRangeError(), "Date-time is out of range"))
+			}
+			return dt
+		}
+		r.temporalGetOverflowOption(r.getOptionsObject(options))
+		return dt
+	}
+	s := r.toTemporalString(v)
+	res, ok := parseTemporalString(s, temporalDateTimeString)
+	if !ok {
+		panic(r.temporalInvalidString(s))
+	}
+	r.temporalCalendarFromParseResult(res)
+	r.temporalGetOverflowOption(r.getOptionsObject(options))
+	dt := isoDateTime{date: res.date, time: res.time}
+	if !isoDateTimeWithinLimits(dt) {
+		panic(r.newErrorf(r.getRangeError(), "Date-time is out of range"))
+	}
+	return dt
+}
+
+func (r *Runtime) builtin_newTemporalPlainDateTime(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		panic(r.needNew("Temporal.PlainDateTime"))
+	}
+	y := r.toIntegerWithTruncation(argAt(args, 0))
+	m := r.toIntegerWithTruncation(argAt(args, 1))
+	d := r.toIntegerWithTruncation(argAt(args, 2))
+	var fields [6]float64
+	for i := range fields {
+		if v := argAt(args, i+3); v != _undefined {
+			fields[i] = r.toIntegerWithTruncation(v)
+		}
+	}
+	if c := argAt(args, 9); c != _undefined {
+		r.toCalendarArgument(c)
+	}
+	if !isValidISODateFloat(y, m, d) {
+		panic(r.newErrorf(r.getRangeError(), "Invalid date"))
+	}
+	t, ok := regulateTime(fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], "reject")
+	if !ok {
+		panic(r.newErrorf(r.getRangeError(), "Invalid time"))
+	}
+	dt := isoDateTime{date: isoDate{year: int(y), month: int(m), day: int(d)}, time: t}
+	if !isoDateTimeWithinLimits(dt) {
+		panic(r.newErrorf(r.getRangeError(), "Date-time is out of range"))
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.global.TemporalPlainDateTime, r.getTemporalPlainDateTimePrototype())
+	return r.newTemporalPlainDateTime(dt, proto)
+}
+
+func (r *Runtime) temporalPlainDateTime_from(call FunctionCall) Value {
+	return r.newTemporalPlainDateTime(r.toTemporalDateTime(call.Argument(0), call.Argument(1)), nil)
+}
+
+func (r *Runtime) temporalPlainDateTime_compare(call FunctionCall) Value {
+	one := r.toTemporalDateTime(call.Argument(0), _undefined)
+	two := r.toTemporalDateTime(call.Argument(1), _undefined)
+	return intToValue(int64(compareISODateTime(one, two)))
+}
+
+func (r *Runtime) temporalPlainDateTimeProto_with(call FunctionCall) Value {
+	pdt := r.toTemporalPlainDateTimeObject(call.This, "with")
+	like := r.toTemporalLikeObject(call.Argument(0))
+	fields := isoDateFields(pdt.dt.date)
+	fields.setTime(pdt.dt.time)
+	fields = mergeTemporalFields(fields, r.prepareTemporalFields(like, temporalDateFields|temporalTimeFields, 0, true))
+	overflow := r.temporalGetOverflowOption(r.getOptionsObject(call.Argument(1)))
+	return r.newValidTemporalPlainDateTime(r.temporalDateTimeFromFields(fields, overflow))
+}
+
+func (r *Runtime) temporalPlainDateTimeProto_withPlainTime(call FunctionCall) Value {
+	pdt := r.toTemporalPlainDateTimeObject(call.This, "withPlainTime")
+	t := r.toTimeRecordOrMidnight(call.Argument(0))
+	return r.newValidTemporalPlainDateTime(isoDateTime{date: pdt.dt.date, time: t})
+}
+
+func (r *Runtime) temporalPlainDateTimeProto_withCalendar(call FunctionCall) Value {
+	pdt := r.toTemporalPlainDateTimeObject(call.This, "withCalendar")
+	r.toTemporalCalendarIdentifier(call.Argument(0))
+	return r.newTemporalPlainDateTime(pdt.dt, nil)
+}
+
+// temporalAddDurationToDateTime implements AddDurationToDateTime.
+func (r *Runtime) temporalAddDurationToDateTime(dt isoDateTime, other, options Value, sign int) Value {
+	d := r.toTemporalDuration(other)
+	if sign < 0 {
+		d = d.negated()
+	}
+	overflow := r.temporalGetOverflowOption(r.getOptionsObject(options))
+	return r.newTemporalPlainDateTime(r.temporalDateTimeAdd(dt, d.internalWith24HourDays(), overflow), nil)
+}
+
+func (r *Runtime) temporalPlainDateTimeProto_add(call FunctionCall) Value {
+	pdt := r.toTemporalPlainDateTimeObject(call.This, "add")
+	return r.temporalAddDurationToDateTime(pdt.dt, call.Argument(0), call.Argument(1), 1)
+}
+
+func (r *Runtime) temporalPlainDateTimeProto_subtract(call FunctionCall) Value {
+	pdt := r.toTemporalPlainDateTimeObject(call.This, "subtract")
+	return r.temporalAddDurationToDateTime(pdt.dt, call.Argument(0), call.Argument(1), -1)
+}
+
+// temporalDifferencePlainDateTime implements DifferenceTemporalPlainDateTime.
+func (r *Runtime) temporalDifferencePlainDateTime(since bool, dt isoDateTime, other, options Value) Value {
+	otherDt := r.toTemporalDateTime(other, _undefined)
+	s := r.temporalGetDifferenceSettings(since, r.getOptionsObject(options), temporalDateTimeUnits, temporalNanosecond, temporalDay)
+	internal := r.temporalDifferenceDateTimeWithRounding(dt, otherDt, s.largestUnit, s.roundingIncrement, s.smallestUnit, s.roundingMode)
+	res := r.temporalDurationFromInternal(internal, s.largestUnit)
+	if since {
+		res = res.negated()
+	}
+	return r.newTemporalDuration(res, nil)
+}
+
+func (r *Runtime) temporalPlainDateTimeProto_until(call FunctionCall) Value {
+	pdt := r.toTemporalPlainDateTimeObject(call.This, "until")
+	return r.temporalDifferencePlainDateTime(false, pdt.dt, call.Argument(0), call.Argument(1))
+}
+
+func (r *Runtime) temporalPlainDateTimeProto_since(call FunctionCall) Value {
+	pdt := r.toTemporalPlainDateTimeObject(call.This, "since")
+	return r.temporalDifferencePlainDateTime(true, pdt.dt, call.Argument(0), call.Argument(1))
+}
+
+// temporalGetRoundOptions reads the options of the round() methods of PlainDateTime and ZonedDateTime.
+func (r *Runtime) temporalGetRoundOptions(roundTo *Object) (increment int64, mode string, smallestUnit temporalUnit) {
+	increment = r.temporalGetRoundingIncrementOption(roundTo)
+	mode = r.temporalGetRoundingModeOption(roundTo, "halfExpand")
+	smallestUnit = r.temporalGetUnitOption(roundTo, "smallestUnit", temporalUnitUnset)
+	if smallestUnit == temporalUnitUnset {
+		panic(r.newErrorf(r.getRangeError(), "smallestUnit is required"))
+	}
+	r.validateTemporalUnit(smallestUnit, "smallestUnit", temporalTimeUnits, temporalDay)
+	if smallestUnit == temporalDay {
+		r.validateTemporalRoundingIncrement(increment, 1, true)
+	} else {
+		r.validateTemporalRoundingIncrement(increment, smallestUnit.maximumRoundingIncrement(), false)
+	}
+	return
+}
+
+func (r *Runtime) temporalPlainDateTimeProto_round(call FunctionCall) Value {
+	pdt := r.toTemporalPlainDateTimeObject(call.This, "round")
+	increment, mode, smallestUnit := r.temporalGetRoundOptions(r.temporalRoundTo(call.Argument(0)))
+	if smallestUnit == temporalNanosecond && increment == 1 {
+		return r.newTemporalPlainDateTime(pdt.dt, nil)
+	}
+	return r.newValidTemporalPlainDateTime(roundISODateTime(pdt.dt, increment, smallestUnit, mode))
+}
+
+func (r *Runtime) temporalPlainDateTimeProto_equals(call FunctionCall) Value {
+	pdt := r.toTemporalPlainDateTimeObject(call.This, "equals")
+	other := r.toTemporalDateTime(call.Argument(0), _undefined)
+	return valueBool(compareISODateTime(pdt.dt, other) == 0)
+}
+
+func (r *Runtime) temporalPlainDateTimeProto_toString(call FunctionCall) Value {
+	pdt := r.toTemporalPlainDateTimeObject(call.This, "toString")
+	options := r.getOptionsObject(call.Argument(0))
+	showCalendar := r.temporalGetShowCalendarOption(options)
+	digits, mode, smallestUnit := r.temporalGetToStringOptions(options)
+	precision := r.temporalToStringPrecision(smallestUnit, digits)
+	dt := roundISODateTime(pdt.dt, precision.increment, precision.unit, mode)
+	if !isoDateTimeWithinLimits(dt) {
+		panic(r.newErrorf(r.getRangeError(), "Date-time is out of range"))
+	}
+	return asciiString(dt.format(precision.precision) + formatCalendarAnnotation(showCalendar))
+}
+
+func (r *Runtime) temporalPlainDateTimeProto_toJSON(call FunctionCall) Value {
+	pdt := r.toTemporalPlainDateTimeObject(call.This, "toJSON")
+	return asciiString(pdt.dt.format(temporalPrecisionAuto))
+}
+
+func (r *Runtime) temporalPlainDateTimeProto_toLocaleString(call FunctionCall) Value {
+	pdt := r.toTemporalPlainDateTimeObject(call.This, "toLocaleString")
+	return r.temporalToLocaleString(isoDateTimeToTime(pdt.dt), 0, nil, call.Argument(0), call.Argument(1))
+}
+
+func (r *Runtime) temporalPlainDateTimeProto_toZonedDateTime(call FunctionCall) Value {
+	pdt := r.toTemporalPlainDateTimeObject(call.This, "toZonedDateTime")
+	tz := r.toTemporalTimeZone(call.Argument(0))
+	disambiguation := r.temporalGetDisambiguationOption(r.getOptionsObject(call.Argument(1)))
+	return r.newTemporalZonedDateTime(r.temporalEpochNsFor(tz, pdt.dt, disambiguation), tz, nil)
+}
+
+func (r *Runtime) temporalPlainDateTimeProto_toPlainDate(call FunctionCall) Value {
+	pdt := r.toTemporalPlainDateTimeObject(call.This, "toPlainDate")
+	return r.newTemporalPlainDate(pdt.dt.date, nil)
+}
+
+func (r *Runtime) temporalPlainDateTimeProto_toPlainTime(call FunctionCall) Value {
+	pdt := r.toTemporalPlainDateTimeObject(call.This, "toPlainTime")
+	return r.newTemporalPlainTime(pdt.dt.time, nil)
+}
+
+func (r *Runtime) createTemporalPlainDateTimeProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
+
+	o._putProp("constructor", r.getTemporalPlainDateTime(), true, false, true)
+	r.putTemporalDateGetters(o, func(this Value, method string) isoDate {
+		return r.toTemporalPlainDateTimeObject(this, method).dt.date
+	})
+	r.putTemporalTimeGetters(o, func(this Value, method string) isoTime {
+		return r.toTemporalPlainDateTimeObject(this, method).dt.time
+	})
+	o._putProp("add", r.newNativeFunc(r.temporalPlainDateTimeProto_add, "add", 1), true, false, true)
+	o._putProp("equals", r.newNativeFunc(r.temporalPlainDateTimeProto_equals, "equals", 1), true, false, true)
+	o._putProp("round", r.newNativeFunc(r.temporalPlainDateTimeProto_round, "round", 1), true, false, true)
+	o._putProp("since", r.newNativeFunc(r.temporalPlainDateTimeProto_since, "since", 1), true, false, true)
+	o._putProp("subtract", r.newNativeFunc(r.temporalPlainDateTimeProto_subtract, "subtract", 1), true, false, true)
+	o._putProp("toJSON", r.newNativeFunc(r.temporalPlainDateTimeProto_toJSON, "toJSON", 0), true, false, true)
+	o._putProp("toLocaleString", r.newNativeFunc(r.temporalPlainDateTimeProto_toLocaleString, "toLocaleString", 0), true, false, true)
+	o._putProp("toPlainDate", r.newNativeFunc(r.temporalPlainDateTimeProto_toPlainDate, "toPlainDate", 0), true, false, true)
+	o._putProp("toPlainTime", r.newNativeFunc(r.temporalPlainDateTimeProto_toPlainTime, "toPlainTime", 0), true, false, true)
+	o._putProp("toString", r.newNativeFunc(r.temporalPlainDateTimeProto_toString, "toString", 0), true, false, true)
+	o._putProp("toZonedDateTime", r.newNativeFunc(r.temporalPlainDateTimeProto_toZonedDateTime, "toZonedDateTime", 1), true, false, true)
+	o._putProp("until", r.newNativeFunc(r.temporalPlainDateTimeProto_until, "until", 1), true, false, true)
+	o._putProp("valueOf", r.newNativeFunc(r.temporalValueOf, "valueOf", 0), true, false, true)
+	o._putProp("with", r.newNativeFunc(r.temporalPlainDateTimeProto_with, "with", 1), true, false, true)
+	o._putProp("withCalendar", r.newNativeFunc(r.temporalPlainDateTimeProto_withCalendar, "withCalendar", 1), true, false, true)
+	o._putProp("withPlainTime", r.newNativeFunc(r.temporalPlainDateTimeProto_withPlainTime, "withPlainTime", 0), true, false, true)
+
+	o._putSym(SymToStringTag, valueProp(asciiString("Temporal.PlainDateTime"), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) createTemporalPlainDateTime(val *Object) objectImpl {
+	o := r.newNativeConstructOnly(val, r.builtin_newTemporalPlainDateTime, r.getTemporalPlainDateTimePrototype(), "PlainDateTime", 3)
+	o._putProp("compare", r.newNativeFunc(r.temporalPlainDateTime_compare, "compare", 2), true, false, true)
+	o._putProp("from", r.newNativeFunc(r.temporalPlainDateTime_from, "from", 1), true, false, true)
+
+	return o
+}
+
+func (r *Runtime) getTemporalPlainDateTimePrototype() *Object {
+	ret := r.global.TemporalPlainDateTimePrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.TemporalPlainDateTimePrototype = ret
+		ret.self = r.createTemporalPlainDateTimeProto(ret)
+	}
+	return ret
+}
+
+func (r *Runtime) getTemporalPlainDateTime() *Object {
+	ret := r.global.TemporalPlainDateTime
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.TemporalPlainDateTime = ret
+		ret.self = r.createTemporalPlainDateTime(ret)
+	}
+	return ret
+}
@@ -0,0 +1,235 @@
+package sobek
+
+import "math/big"
+
+type temporalPlainTimeObject struct {
+	baseObject
+	time isoTime
+}
+
+// newTemporalPlainTime implements CreateTemporalTime.
+func (r *Runtime) newTemporalPlainTime(t isoTime, proto *Object) *Object {
+	if proto == nil {
+		proto = r.getTemporalPlainTimePrototype()
+	}
+	pt := &temporalPlainTimeObject{time: t}
+	return r.newTemporalBaseObject(pt, &pt.baseObject, proto)
+}
+
+func (r *Runtime) toTemporalPlainTimeObject(v Value, method string) *temporalPlainTimeObject {
+	if o, ok := v.(*Object); ok {
+		if pt, ok := o.self.(*temporalPlainTimeObject); ok {
+			return pt
+		}
+	}
+	panic(r.NewTypeError("Method Temporal.PlainTime.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+// toTemporalTime implements ToTemporalTime.
+func (r *Runtime) toTemporalTime(v, options Value) isoTime {
+	if o, ok := v.(*Object); ok {
+		var t isoTime
+		switch self := o.self.(type) {
+		case *temporalPlainTimeObject:
+			t = self.time
+		case *temporalPlainDateTimeObject:
+			t = self.dt.time
+		case *temporalZonedDateTimeObject:
+			t = self.tz.isoDateTimeFor(self.ns).time
+		default:
+			fields := r.prepareTemporalFields(o, temporalTimeFields, 0, true)
+			overflow := r.temporalGetOverflowOption(r.getOptionsObject(options))
+			return r.temporalTimeFromFields(fields, overflow)
+		}
+		r.temporalGetOverflowOption(r.getOptionsObject(options))
+		return t
+	}
+	s := r.toTemporalString(v)
+	res, ok := parseTemporalString(s, temporalTimeString)
+	if !ok {
+		panic(r.temporalInvalidString(s))
+	}
+	r.temporalGetOverflowOption(r.getOptionsObject(options))
+	return res.time
+}
+
+// toTimeRecordOrMidnight implements ToTimeRecordOrMidnight.
+func (r *Runtime) toTimeRecordOrMidnight(v Value) isoTime {
+	if v == _undefined {
+		return isoTime{}
+	}
+	return r.toTemporalTime(v, _undefined)
+}
+
+func (r *Runtime) builtin_newTemporalPlainTime(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		panic(r.needNew("Temporal.PlainTime"))
+	}
+	var fields [6]float64
+	for i := range fields {
+		if v := argAt(args, i); v != _undefined {
+			fields[i] = r.toIntegerWithTruncation(v)
+		}
+	}
+	t, ok := regulateTime(fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], "reject")
+	if !ok {
+		panic(r.newErrorf(r.getRangeError(), "Invalid time"))
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.global.TemporalPlainTime, r.getTemporalPlainTimePrototype())
+	return r.newTemporalPlainTime(t, proto)
+}
+
+func (r *Runtime) temporalPlainTime_from(call FunctionCall) Value {
+	return r.newTemporalPlainTime(r.toTemporalTime(call.Argument(0), call.Argument(1)), nil)
+}
+
+func (r *Runtime) temporalPlainTime_compare(call FunctionCall) Value {
+	one := r.toTemporalTime(call.Argument(0), _undefined)
+	two := r.toTemporalTime(call.Argument(1), _undefined)
+	return intToValue(int64(compareISOTime(one, two)))
+}
+
+func (r *Runtime) temporalPlainTimeProto_with(call FunctionCall) Value {
+	pt := r.toTemporalPlainTimeObject(call.This, "with")
+	like := r.toTemporalLikeObject(call.Argument(0))
+	fields := &temporalFields{}
+	fields.setTime(pt.time)
+	fields = mergeTemporalFields(fields, r.prepareTemporalFields(like, temporalTimeFields, 0, true))
+	overflow := r.temporalGetOverflowOption(r.getOptionsObject(call.Argument(1)))
+	return r.newTemporalPlainTime(r.temporalTimeFromFields(fields, overflow), nil)
+}
+
+// temporalAddDurationToTime implements AddDurationToTime.
+func (r *Runtime) temporalAddDurationToTime(t isoTime, other Value, sign int) Value {
+	d := r.toTemporalDuration(other)
+	if sign < 0 {
+		d = d.negated()
+	}
+	_, res := addTime(t, d.timeDuration())
+	return r.newTemporalPlainTime(res, nil)
+}
+
+func (r *Runtime) temporalPlainTimeProto_add(call FunctionCall) Value {
+	pt := r.toTemporalPlainTimeObject(call.This, "add")
+	return r.temporalAddDurationToTime(pt.time, call.Argument(0), 1)
+}
+
+func (r *Runtime) temporalPlainTimeProto_subtract(call FunctionCall) Value {
+	pt := r.toTemporalPlainTimeObject(call.This, "subtract")
+	return r.temporalAddDurationToTime(pt.time, call.Argument(0), -1)
+}
+
+// temporalDifferencePlainTime implements DifferenceTemporalPlainTime.
+func (r *Runtime) temporalDifferencePlainTime(since bool, t isoTime, other, options Value) Value {
+	otherTime := r.toTemporalTime(other, _undefined)
+	s := r.temporalGetDifferenceSettings(since, r.getOptionsObject(options), temporalTimeUnits, temporalNanosecond, temporalHour)
+	diff := big.NewInt(otherTime.nanos() - t.nanos())
+	diff = r.temporalRoundTimeDuration(diff, s.roundingIncrement, s.smallestUnit, s.roundingMode)
+	res := r.temporalDurationFromInternal(internalDuration{time: diff}, s.largestUnit)
+	if since {
+		res = res.negated()
+	}
+	return r.newTemporalDuration(res, nil)
+}
+
+func (r *Runtime) temporalPlainTimeProto_until(call FunctionCall) Value {
+	pt := r.toTemporalPlainTimeObject(call.This, "until")
+	return r.temporalDifferencePlainTime(false, pt.time, call.Argument(0), call.Argument(1))
+}
+
+func (r *Runtime) temporalPlainTimeProto_since(call FunctionCall) Value {
+	pt := r.toTemporalPlainTimeObject(call.This, "since")
+	return r.temporalDifferencePlainTime(true, pt.time, call.Argument(0), call.Argument(1))
+}
+
+func (r *Runtime) temporalPlainTimeProto_round(call FunctionCall) Value {
+	pt := r.toTemporalPlainTimeObject(call.This, "round")
+	roundTo := r.temporalRoundTo(call.Argument(0))
+	increment := r.temporalGetRoundingIncrementOption(roundTo)
+	mode := r.temporalGetRoundingModeOption(roundTo, "halfExpand")
+	smallestUnit := r.temporalGetUnitOption(roundTo, "smallestUnit", temporalUnitUnset)
+	if smallestUnit == temporalUnitUnset {
+		panic(r.newErrorf(r.getRangeError(), "smallestUnit is required"))
+	}
+	r.validateTemporalUnit(smallestUnit, "smallestUnit", temporalTimeUnits, temporalUnitUnset)
+	r.validateTemporalRoundingIncrement(increment, smallestUnit.maximumRoundingIncrement(), false)
+	_, t := roundISOTime(pt.time, increment, smallestUnit, mode)
+	return r.newTemporalPlainTime(t, nil)
+}
+
+func (r *Runtime) temporalPlainTimeProto_equals(call FunctionCall) Value {
+	pt := r.toTemporalPlainTimeObject(call.This, "equals")
+	other := r.toTemporalTime(call.Argument(0), _undefined)
+	return valueBool(compareISOTime(pt.time, other) == 0)
+}
+
+func (r *Runtime) temporalPlainTimeProto_toString(call FunctionCall) Value {
+	pt := r.toTemporalPlainTimeObject(call.This, "toString")
+	digits, mode, smallestUnit := r.temporalGetToStringOptions(r.getOptionsObject(call.Argument(0)))
+	precision := r.temporalToStringPrecision(smallestUnit, digits)
+	_, t := roundISOTime(pt.time, precision.increment, precision.unit, mode)
+	return asciiString(t.format(precision.precision))
+}
+
+func (r *Runtime) temporalPlainTimeProto_toJSON(call FunctionCall) Value {
+	pt := r.toTemporalPlainTimeObject(call.This, "toJSON")
+	return asciiString(pt.time.format(temporalPrecisionAuto))
+}
+
+func (r *Runtime) temporalPlainTimeProto_toLocaleString(call FunctionCall) Value {
+	pt := r.toTemporalPlainTimeObject(call.This, "toLocaleString")
+	dt := isoDateTime{date: isoDate{year: 1970, month: 1, day: 1}, time: pt.time}
+	return r.temporalToLocaleString(isoDateTimeToTime(dt), 2, nil, call.Argument(0), call.Argument(1))
+}
+
+func (r *Runtime) createTemporalPlainTimeProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
+
+	o._putProp("constructor", r.getTemporalPlainTime(), true, false, true)
+	r.putTemporalTimeGetters(o, func(this Value, method string) isoTime {
+		return r.toTemporalPlainTimeObject(this, method).time
+	})
+	o._putProp("add", r.newNativeFunc(r.temporalPlainTimeProto_add, "add", 1), true, false, true)
+	o._putProp("equals", r.newNativeFunc(r.temporalPlainTimeProto_equals, "equals", 1), true, false, true)
+	o._putProp("round", r.newNativeFunc(r.temporalPlainTimeProto_round, "round", 1), true, false, true)
+	o._putProp("since", r.newNativeFunc(r.temporalPlainTimeProto_since, "since", 1), true, false, true)
+	o._putProp("subtract", r.newNativeFunc(r.temporalPlainTimeProto_subtract, "subtract", 1), true, false, true)
+	o._putProp("toJSON", r.newNativeFunc(r.temporalPlainTimeProto_toJSON, "toJSON", 0), true, false, true)
+	o._putProp("toLocaleString", r.newNativeFunc(r.temporalPlainTimeProto_toLocaleString, "toLocaleString", 0), true, false, true)
+	o._putProp("toString", r.newNativeFunc(r.temporalPlainTimeProto_toString, "toString", 0), true, false, true)
+	o._putProp("until", r.newNativeFunc(r.temporalPlainTimeProto_until, "until", 1), true, false, true)
+	o._putProp("valueOf", r.newNativeFunc(r.temporalValueOf, "valueOf", 0), true, false, true)
+	o._putProp("with", r.newNativeFunc(r.temporalPlainTimeProto_with, "with", 1), true, false, true)
+
+	o._putSym(SymToStringTag, valueProp(asciiString("Temporal.PlainTime"), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) createTemporalPlainTime(val *Object) objectImpl {
+	o := r.newNativeConstructOnly(val, r.builtin_newTemporalPlainTime, r.getTemporalPlainTimePrototype(), "PlainTime", 0)
+	o._putProp("compare", r.newNativeFunc(r.temporalPlainTime_compare, "compare", 2), true, false, true)
+	o._putProp("from", r.newNativeFunc(r.temporalPlainTime_from, "from", 1), true, false, true)
+
+	return o
+}
+
+func (r *Runtime) getTemporalPlainTimePrototype() *Object {
+	ret := r.global.TemporalPlainTimePrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.TemporalPlainTimePrototype = ret
+		ret.self = r.createTemporalPlainTimeProto(ret)
+	}
+	return ret
+}
+
+func (r *Runtime) getTemporalPlainTime() *Object {
+	ret := r.global.TemporalPlainTime
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.TemporalPlainTime = ret
+		ret.self = r.createTemporalPlainTime(ret)
+	}
+	return ret
+}
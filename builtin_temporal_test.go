@@ -0,0 +1,184 @@
+package sobek
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTemporalPlainDate(t *testing.T) {
+	const SCRIPT = `
+	const d = new Temporal.PlainDate(2024, 2, 29);
+	assert.sameValue(d.toString(), "2024-02-29");
+	assert.sameValue(d.calendarId, "iso8601");
+	assert.sameValue(d.monthCode, "M02");
+	assert.sameValue(d.dayOfWeek, 4);
+	assert.sameValue(d.dayOfYear, 60);
+	assert.sameValue(d.weekOfYear, 9);
+	assert.sameValue(d.daysInMonth, 29);
+	assert.sameValue(d.inLeapYear, true);
+	assert.sameValue(d.add({years: 1}).toString(), "2025-02-28");
+	assert.throws(RangeError, () => d.add({years: 1}, {overflow: "reject"}));
+	assert.sameValue(d.with({day: 1}).toString(), "2024-02-01");
+	assert.sameValue(d.toString({calendarName: "always"}), "2024-02-29[u-ca=iso8601]");
+
+	const a = Temporal.PlainDate.from("2024-01-31");
+	assert.sameValue(a.until("2025-03-01", {largestUnit: "years"}).toString(), "P1Y1M1D");
+	assert.sameValue(a.until("2024-03-15", {largestUnit: "months", smallestUnit: "months", roundingMode: "halfExpand"}).toString(), "P1M");
+	assert.sameValue(a.since("2024-01-01").toString(), "P30D");
+	assert.sameValue(Temporal.PlainDate.compare("2024-06-01", {year: 2024, month: 6, day: 2}), -1);
+	assert.sameValue(Temporal.PlainDate.from({year: 2024, month: 13, day: 1}).toString(), "2024-12-01");
+	assert.throws(RangeError, () => Temporal.PlainDate.from({year: 2024, month: 13, day: 1}, {overflow: "reject"}));
+	assert.throws(TypeError, () => Temporal.PlainDate.from({year: 2024, day: 1}));
+	assert.throws(RangeError, () => Temporal.PlainDate.from("2020-01-01T00:00Z"));
+	assert.throws(RangeError, () => Temporal.PlainDate.from("+275760-09-14"));
+	assert.throws(RangeError, () => d.withCalendar("gregory"));
+	assert.throws(TypeError, () => d.valueOf());
+	assert.throws(TypeError, () => Temporal.PlainDate(2024, 1, 1));
+	assert.sameValue(JSON.stringify({d}), '{"d":"2024-02-29"}');
+	assert.sameValue(Object.prototype.toString.call(d), "[object Temporal.PlainDate]");
+	assert.sameValue(d.toPlainDateTime("10:00").toString(), "2024-02-29T10:00:00");
+	assert.sameValue(d.toZonedDateTime({timeZone: "UTC", plainTime: "10:00"}).toString(), "2024-02-29T10:00:00+00:00[UTC]");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestTemporalPlainTime(t *testing.T) {
+	const SCRIPT = `
+	const t = Temporal.PlainTime.from("12:34:56.789");
+	assert.sameValue(t.hour, 12);
+	assert.sameValue(t.millisecond, 789);
+	assert.sameValue(t.round({smallestUnit: "second"}).toString(), "12:34:57");
+	assert.sameValue(t.toString({fractionalSecondDigits: 1}), "12:34:56.7");
+	assert.sameValue(t.toString({smallestUnit: "minute"}), "12:34");
+	assert.sameValue(Temporal.PlainTime.from("23:59:59.999").add({milliseconds: 2}).toString(), "00:00:00.001");
+	assert.sameValue(t.until("14:00", {largestUnit: "minutes"}).toString(), "PT85M3.211S");
+	assert.sameValue(Temporal.PlainTime.from("T12").toString(), "12:00:00");
+	assert.throws(RangeError, () => Temporal.PlainTime.from("1214"));
+	assert.throws(RangeError, () => new Temporal.PlainTime(24));
+	assert.throws(RangeError, () => t.round({smallestUnit: "hour", roundingIncrement: 5}));
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestTemporalPlainDateTime(t *testing.T) {
+	const SCRIPT = `
+	const dt = Temporal.PlainDateTime.from("2020-01-01T10:00");
+	assert.sameValue(dt.until("2021-03-04T05:06:07.5", {largestUnit: "years", smallestUnit: "minutes", roundingMode: "halfExpand"}).toString(), "P1Y2M2DT19H6M");
+	assert.sameValue(dt.with({monthCode: "M05"}).toString(), "2020-05-01T10:00:00");
+	assert.sameValue(dt.round({smallestUnit: "day"}).toString(), "2020-01-01T00:00:00");
+	assert.sameValue(dt.add({hours: 14}).toString(), "2020-01-02T00:00:00");
+	assert.sameValue(dt.withPlainTime().toString(), "2020-01-01T00:00:00");
+	assert.sameValue(dt.toPlainDate().toString(), "2020-01-01");
+	assert.sameValue(Temporal.PlainDateTime.from({year: 2020, month: 1, day: 1, hour: 25}).hour, 23);
+	assert.throws(RangeError, () => Temporal.PlainDateTime.from("-271821-04-19T00:00"));
+	assert.sameValue(Temporal.PlainDateTime.from("-271821-04-19T00:00:00.000000001").toString(), "-271821-04-19T00:00:00.000000001");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestTemporalZonedDateTime(t *testing.T) {
+	const SCRIPT = `
+	const ny = "America/New_York";
+	assert.sameValue(Temporal.ZonedDateTime.from("2024-03-10T02:30[" + ny + "]").toString(), "2024-03-10T03:30:00-04:00[America/New_York]");
+	assert.sameValue(Temporal.ZonedDateTime.from("2024-03-10T02:30[" + ny + "]", {disambiguation: "earlier"}).toString(), "2024-03-10T01:30:00-05:00[America/New_York]");
+	assert.throws(RangeError, () => Temporal.ZonedDateTime.from("2024-03-10T02:30[" + ny + "]", {disambiguation: "reject"}));
+	assert.sameValue(Temporal.ZonedDateTime.from("2024-11-03T01:30-05:00[" + ny + "]").offset, "-05:00");
+	assert.sameValue(Temporal.ZonedDateTime.from("2024-11-03T12:00[" + ny + "]").hoursInDay, 25);
+	assert.sameValue(Temporal.ZonedDateTime.from("2024-03-10T12:00[" + ny + "]").hoursInDay, 23);
+	assert.sameValue(new Temporal.ZonedDateTime(0n, "america/new_york").toString(), "1969-12-31T19:00:00-05:00[America/New_York]");
+
+	const z = Temporal.ZonedDateTime.from("2024-06-01T12:00[Europe/London]");
+	assert.sameValue(z.epochMilliseconds, 1717239600000);
+	assert.sameValue(z.getTimeZoneTransition("next").toString(), "2024-10-27T01:00:00+00:00[Europe/London]");
+	assert.sameValue(z.getTimeZoneTransition("previous").toString(), "2024-03-31T02:00:00+01:00[Europe/London]");
+	assert.sameValue(z.until("2024-12-01T12:00[Europe/London]", {largestUnit: "days"}).toString(), "P183D");
+	assert.sameValue(z.until("2024-12-01T12:00[Europe/London]").toString(), "PT4393H");
+	assert.sameValue(z.round({smallestUnit: "day"}).toString(), "2024-06-02T00:00:00+01:00[Europe/London]");
+	assert.sameValue(z.with({month: 1}).toString(), "2024-01-01T12:00:00+00:00[Europe/London]");
+	assert.sameValue(z.withTimeZone("Asia/Kolkata").toString(), "2024-06-01T16:30:00+05:30[Asia/Kolkata]");
+	assert.sameValue(z.toString({smallestUnit: "minute", timeZoneName: "never", offset: "never"}), "2024-06-01T12:00");
+	assert.sameValue(z.startOfDay().toString(), "2024-06-01T00:00:00+01:00[Europe/London]");
+	assert.throws(RangeError, () => z.until("2024-06-01T12:00[Europe/Paris]", {largestUnit: "days"}));
+	assert.throws(RangeError, () => Temporal.ZonedDateTime.from("2024-06-01T12:00+02:00[Europe/London]"));
+	assert.sameValue(Temporal.ZonedDateTime.from("2024-06-01T12:00+02:00[Europe/London]", {offset: "use"}).hour, 11);
+	assert.throws(TypeError, () => Temporal.ZonedDateTime.from({year: 2024, month: 6, day: 1}));
+	assert.sameValue(Temporal.ZonedDateTime.from("2020-01-01T00:00+05:30[+05:30]").timeZoneId, "+05:30");
+	assert.sameValue(Temporal.ZonedDateTime.from("2020-01-01T00:00Z[utc]").timeZoneId, "UTC");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestTemporalInstant(t *testing.T) {
+	const SCRIPT = `
+	const i = Temporal.Instant.from("2020-01-01T00:00:00.123456789+01:00");
+	assert.sameValue(i.epochNanoseconds, 1577833200123456789n);
+	assert.sameValue(i.toString(), "2019-12-31T23:00:00.123456789Z");
+	assert.sameValue(i.toString({smallestUnit: "second", timeZone: "Europe/Berlin"}), "2020-01-01T00:00:00+01:00");
+	assert.sameValue(i.round({smallestUnit: "hour", roundingIncrement: 4}).toString(), "2020-01-01T00:00:00Z");
+	assert.throws(RangeError, () => i.round({smallestUnit: "hour", roundingIncrement: 5}));
+	assert.sameValue(Temporal.Instant.fromEpochNanoseconds(-1n).epochMilliseconds, -1);
+	assert.throws(RangeError, () => Temporal.Instant.fromEpochMilliseconds(1.5));
+	assert.throws(RangeError, () => Temporal.Instant.from("+275760-09-13T00:00:00.000000001Z"));
+	assert.sameValue(Temporal.Instant.from("2020-01-01T00:00Z").until("2020-01-02T00:00Z").toString(), "PT86400S");
+	assert.throws(RangeError, () => i.add({days: 1}));
+	assert.sameValue(new Date(0).toTemporalInstant().toString(), "1970-01-01T00:00:00Z");
+	assert.sameValue(i.toZonedDateTimeISO("+01:00").hour, 0);
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestTemporalDuration(t *testing.T) {
+	const SCRIPT = `
+	const d = Temporal.Duration.from("P1Y2M3DT4H5M6.789S");
+	assert.sameValue(d.toString(), "P1Y2M3DT4H5M6.789S");
+	assert.sameValue(d.milliseconds, 789);
+	assert.sameValue(d.sign, 1);
+	assert.sameValue(d.negated().toString(), "-P1Y2M3DT4H5M6.789S");
+	assert.sameValue(Temporal.Duration.from("PT1.5H").toString(), "PT1H30M");
+	assert.sameValue(Temporal.Duration.from({hours: 25}).round({largestUnit: "days"}).toString(), "P1DT1H");
+	assert.sameValue(Temporal.Duration.from({hours: 130}).total({unit: "days"}), 5.416666666666667);
+	assert.sameValue(Temporal.Duration.from({months: 1}).total({unit: "days", relativeTo: "2024-02-01"}), 29);
+	assert.sameValue(Temporal.Duration.from({days: 45}).round({largestUnit: "months", relativeTo: "2024-01-15"}).toString(), "P1M14D");
+	assert.sameValue(Temporal.Duration.from({hours: 24}).round({largestUnit: "days", relativeTo: "2024-03-10T00:00[America/New_York]"}).toString(), "P1DT1H");
+	assert.sameValue(Temporal.Duration.compare({days: 31}, {months: 1}, {relativeTo: "2024-02-01"}), 1);
+	assert.throws(RangeError, () => Temporal.Duration.compare({days: 31}, {months: 1}));
+	assert.sameValue(Temporal.Duration.from({days: 1, hours: 12}).add({hours: 13}).toString(), "P2DT1H");
+	assert.throws(RangeError, () => Temporal.Duration.from({years: 1}).add({days: 1}));
+	assert.sameValue(Temporal.Duration.from({seconds: 1, milliseconds: 500}).toString({fractionalSecondDigits: 0, roundingMode: "halfExpand"}), "PT2S");
+	assert.throws(RangeError, () => new Temporal.Duration(1, -1));
+	assert.throws(TypeError, () => Temporal.Duration.from({}));
+	assert.sameValue(new Temporal.Duration().blank, true);
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestTemporalNow(t *testing.T) {
+	const SCRIPT = `
+	assert.sameValue(Temporal.Now.instant().epochMilliseconds, 1700000000000);
+	assert.sameValue(Temporal.Now.plainDateTimeISO("UTC").toString(), "2023-11-14T22:13:20");
+	assert.sameValue(Temporal.Now.zonedDateTimeISO("Asia/Tokyo").toString(), "2023-11-15T07:13:20+09:00[Asia/Tokyo]");
+	assert.sameValue(Temporal.Now.plainDateISO("-10:00").toString(), "2023-11-14");
+	assert.sameValue(typeof Temporal.Now.timeZoneId(), "string");
+	assert.sameValue(Object.prototype.toString.call(Temporal.Now), "[object Temporal.Now]");
+	`
+	vm := New()
+	vm.SetTimeSource(func() time.Time {
+		return time.UnixMilli(1700000000000)
+	})
+	vm.testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestTemporalExport(t *testing.T) {
+	vm := New()
+	v, err := vm.RunString(`Temporal.ZonedDateTime.from("2024-06-01T12:00[Europe/London]")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm, ok := v.Export().(time.Time)
+	if !ok {
+		t.Fatalf("unexpected export: %T", v.Export())
+	}
+	if tm.Unix() != 1717239600 || tm.Location().String() != "Europe/London" {
+		t.Fatal(tm)
+	}
+}
@@ -0,0 +1,444 @@
+package sobek
+
+import (
+	"math/big"
+	"reflect"
+)
+
+type temporalZonedDateTimeObject struct {
+	baseObject
+	ns *big.Int
+	tz *temporalTimeZone
+}
+
+func (z *temporalZonedDateTimeObject) exportType() reflect.Type {
+	return typeTime
+}
+
+func (z *temporalZonedDateTimeObject) export(*objectExportCtx) interface{} {
+	return temporalEpochNsToTime(z.ns).In(z.tz.location())
+}
+
+func (z *temporalZonedDateTimeObject) isoDateTime() isoDateTime {
+	return z.tz.isoDateTimeFor(z.ns)
+}
+
+// newTemporalZonedDateTime implements CreateTemporalZonedDateTime, the epoch nanoseconds must be valid.
+func (r *Runtime) newTemporalZonedDateTime(ns *big.Int, tz *temporalTimeZone, proto *Object) *Object {
+	if proto == nil {
+		proto = r.getTemporalZonedDateTimePrototype()
+	}
+	z := &temporalZonedDateTimeObject{ns: ns, tz: tz}
+	return r.newTemporalBaseObject(z, &z.baseObject, proto)
+}
+
+func (r *Runtime) toTemporalZonedDateTimeObject(v Value, method string) *temporalZonedDateTimeObject {
+	if o, ok := v.(*Object); ok {
+		if z, ok := o.self.(*temporalZonedDateTimeObject); ok {
+			return z
+		}
+	}
+	panic(r.NewTypeError("Method Temporal.ZonedDateTime.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+// toTemporalZonedDateTime implements ToTemporalZonedDateTime.
+func (r *Runtime) toTemporalZonedDateTime(v, options Value) (*big.Int, *temporalTimeZone) {
+	var date isoDate
+	var t *isoTime
+	var tz *temporalTimeZone
+	var offsetNs int64
+	var disambiguation, offsetOption string
+	offsetBehaviour := temporalOffsetOption
+	matchMinutes := temporalMatchExactly
+	if o, ok := v.(*Object); ok {
+		if z, ok := o.self.(*temporalZonedDateTimeObject); ok {
+			opts := r.getOptionsObject(options)
+			r.temporalGetDisambiguationOption(opts)
+			r.temporalGetOffsetOption(opts, "reject")
+			r.temporalGetOverflowOption(opts)
+			return z.ns, z.tz
+		}
+		r.temporalCalendarWithISODefault(o)
+		fields := r.prepareTemporalFields(o, temporalDateFields|temporalTimeFields|1<<temporalFieldOffset|1<<temporalFieldTimeZone,
+			1<<temporalFieldTimeZone, false)
+		opts := r.getOptionsObject(options)
+		disambiguation = r.temporalGetDisambiguationOption(opts)
+		offsetOption = r.temporalGetOffsetOption(opts, "reject")
+		overflow := r.temporalGetOverflowOption(opts)
+		dt := r.temporalDateTimeFromFields(fields, overflow)
+		date, t, tz = dt.date, &dt.time, fields.timeZone
+		if fields.has(temporalFieldOffset) {
+			offsetNs, _ = parseUTCOffsetNs(fields.offset)
+		} else {
+			offsetBehaviour = temporalOffsetWall
+		}
+	} else {
+		s := r.toTemporalString(v)
+		res, ok := parseTemporalString(s, temporalZonedString)
+		if !ok {
+			panic(r.temporalInvalidString(s))
+		}
+		tz = r.toTemporalTimeZone(newStringValue(res.timeZone))
+		switch {
+		case res.z:
+			offsetBehaviour = temporalOffsetExact
+		case res.offset == "":
+			offsetBehaviour = temporalOffsetWall
+		default:
+			offsetNs, _ = parseUTCOffsetNs(res.offset)
+		}
+		r.temporalCalendarFromParseResult(res)
+		// an offset with seconds must match exactly
+		matchMinutes = len(res.offset) <= len("+00:00")
+		opts := r.getOptionsObject(options)
+		disambiguation = r.temporalGetDisambiguationOption(opts)
+		offsetOption = r.temporalGetOffsetOption(opts, "reject")
+		r.temporalGetOverflowOption(opts)
+		date = res.date
+		if res.hasTime {
+			t = &res.time
+		}
+	}
+	ns := r.interpretISODateTimeOffset(date, t, offsetBehaviour, offsetNs, tz, disambiguation, offsetOption, matchMinutes)
+	return ns, tz
+}
+
+// temporalZonedDateTimeToString implements TemporalZonedDateTimeToString.
+func temporalZonedDateTimeToString(ns *big.Int, tz *temporalTimeZone, precision int, showCalendar, showTimeZone, showOffset string) string {
+	offsetNs := tz.offsetNsFor(ns)
+	s := isoDateTimeFromEpochNs(ns, offsetNs).format(precision)
+	if showOffset != "never" {
+		s += formatDateTimeUTCOffsetRounded(offsetNs)
+	}
+	switch showTimeZone {
+	case "never":
+	case "critical":
+		s += "[!" + tz.id + "]"
+	default:
+		s += "[" + tz.id + "]"
+	}
+	return s + formatCalendarAnnotation(showCalendar)
+}
+
+func (r *Runtime) builtin_newTemporalZonedDateTime(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		panic(r.needNew("Temporal.ZonedDateTime"))
+	}
+	ns := r.checkEpochNs((*big.Int)(toBigInt(argAt(args, 0))))
+	id, ok := argAt(args, 1).(String)
+	if !ok {
+		panic(r.NewTypeError("Time zone must be a string"))
+	}
+	tz := r.temporalTimeZoneFromIdentifier(id.String())
+	if c := argAt(args, 2); c != _undefined {
+		r.toCalendarArgument(c)
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.global.TemporalZonedDateTime, r.getTemporalZonedDateTimePrototype())
+	return r.newTemporalZonedDateTime(ns, tz, proto)
+}
+
+func (r *Runtime) temporalZonedDateTime_from(call FunctionCall) Value {
+	ns, tz := r.toTemporalZonedDateTime(call.Argument(0), call.Argument(1))
+	return r.newTemporalZonedDateTime(ns, tz, nil)
+}
+
+func (r *Runtime) temporalZonedDateTime_compare(call FunctionCall) Value {
+	one, _ := r.toTemporalZonedDateTime(call.Argument(0), _undefined)
+	two, _ := r.toTemporalZonedDateTime(call.Argument(1), _undefined)
+	return intToValue(int64(one.Cmp(two)))
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_with(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "with")
+	like := r.toTemporalLikeObject(call.Argument(0))
+	offsetNs := z.tz.offsetNsFor(z.ns)
+	dt := isoDateTimeFromEpochNs(z.ns, offsetNs)
+	fields := isoDateFields(dt.date)
+	fields.setTime(dt.time)
+	fields.present |= 1 << temporalFieldOffset
+	fields.offset = formatUTCOffsetNs(offsetNs)
+	partial := r.prepareTemporalFields(like, temporalDateFields|temporalTimeFields|1<<temporalFieldOffset, 0, true)
+	fields = mergeTemporalFields(fields, partial)
+	opts := r.getOptionsObject(call.Argument(1))
+	disambiguation := r.temporalGetDisambiguationOption(opts)
+	offsetOption := r.temporalGetOffsetOption(opts, "prefer")
+	overflow := r.temporalGetOverflowOption(opts)
+	dt = r.temporalDateTimeFromFields(fields, overflow)
+	newOffsetNs, _ := parseUTCOffsetNs(fields.offset)
+	ns := r.interpretISODateTimeOffset(dt.date, &dt.time, temporalOffsetOption, newOffsetNs, z.tz, disambiguation, offsetOption, temporalMatchExactly)
+	return r.newTemporalZonedDateTime(ns, z.tz, nil)
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_withPlainTime(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "withPlainTime")
+	date := z.isoDateTime().date
+	if v := call.Argument(0); v != _undefined {
+		dt := isoDateTime{date: date, time: r.toTemporalTime(v, _undefined)}
+		return r.newTemporalZonedDateTime(r.temporalEpochNsFor(z.tz, dt, "compatible"), z.tz, nil)
+	}
+	return r.newTemporalZonedDateTime(r.temporalStartOfDay(z.tz, date), z.tz, nil)
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_withTimeZone(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "withTimeZone")
+	tz := r.toTemporalTimeZone(call.Argument(0))
+	return r.newTemporalZonedDateTime(z.ns, tz, nil)
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_withCalendar(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "withCalendar")
+	r.toTemporalCalendarIdentifier(call.Argument(0))
+	return r.newTemporalZonedDateTime(z.ns, z.tz, nil)
+}
+
+// temporalAddDurationToZonedDateTime implements AddDurationToZonedDateTime.
+func (r *Runtime) temporalAddDurationToZonedDateTime(z *temporalZonedDateTimeObject, other, options Value, sign int) Value {
+	d := r.toTemporalDuration(other)
+	if sign < 0 {
+		d = d.negated()
+	}
+	overflow := r.temporalGetOverflowOption(r.getOptionsObject(options))
+	return r.newTemporalZonedDateTime(r.temporalAddZonedDateTime(z.ns, z.tz, d.internal(), overflow), z.tz, nil)
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_add(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "add")
+	return r.temporalAddDurationToZonedDateTime(z, call.Argument(0), call.Argument(1), 1)
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_subtract(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "subtract")
+	return r.temporalAddDurationToZonedDateTime(z, call.Argument(0), call.Argument(1), -1)
+}
+
+// temporalDifferenceZonedDateTimeValues implements DifferenceTemporalZonedDateTime.
+func (r *Runtime) temporalDifferenceZonedDateTimeValues(since bool, z *temporalZonedDateTimeObject, other, options Value) Value {
+	otherNs, otherTz := r.toTemporalZonedDateTime(other, _undefined)
+	s := r.temporalGetDifferenceSettings(since, r.getOptionsObject(options), temporalDateTimeUnits, temporalNanosecond, temporalHour)
+	var res temporalDuration
+	if !s.largestUnit.isDateUnit() {
+		internal := r.temporalDifferenceInstant(z.ns, otherNs, s.roundingIncrement, s.smallestUnit, s.roundingMode)
+		res = r.temporalDurationFromInternal(internal, s.largestUnit)
+	} else {
+		if !z.tz.equals(otherTz) {
+			panic(r.newErrorf(r.getRangeError(), "Time zones %s and %s are not the same", z.tz.id, otherTz.id))
+		}
+		if z.ns.Cmp(otherNs) == 0 {
+			return r.newTemporalDuration(temporalDuration{}, nil)
+		}
+		internal := r.temporalDifferenceZonedDateTimeWithRounding(z.ns, otherNs, z.tz, s.largestUnit, s.roundingIncrement, s.smallestUnit, s.roundingMode)
+		res = r.temporalDurationFromInternal(internal, temporalHour)
+	}
+	if since {
+		res = res.negated()
+	}
+	return r.newTemporalDuration(res, nil)
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_until(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "until")
+	return r.temporalDifferenceZonedDateTimeValues(false, z, call.Argument(0), call.Argument(1))
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_since(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "since")
+	return r.temporalDifferenceZonedDateTimeValues(true, z, call.Argument(0), call.Argument(1))
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_round(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "round")
+	increment, mode, smallestUnit := r.temporalGetRoundOptions(r.temporalRoundTo(call.Argument(0)))
+	if smallestUnit == temporalNanosecond && increment == 1 {
+		return r.newTemporalZonedDateTime(z.ns, z.tz, nil)
+	}
+	dt := z.isoDateTime()
+	var ns *big.Int
+	if smallestUnit == temporalDay {
+		start := r.temporalStartOfDay(z.tz, dt.date)
+		end := r.temporalStartOfDay(z.tz, dt.date.addDays(1))
+		dayLength := new(big.Int).Sub(end, start)
+		progress := new(big.Int).Sub(z.ns, start)
+		ns = roundBigToIncrementAsIfPositive(progress, dayLength, mode)
+		ns.Add(ns, start)
+	} else {
+		rounded := roundISODateTime(dt, increment, smallestUnit, mode)
+		offsetNs := z.tz.offsetNsFor(z.ns)
+		ns = r.interpretISODateTimeOffset(rounded.date, &rounded.time, temporalOffsetOption, offsetNs, z.tz, "compatible", "prefer", temporalMatchExactly)
+	}
+	return r.newTemporalZonedDateTime(ns, z.tz, nil)
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_equals(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "equals")
+	otherNs, otherTz := r.toTemporalZonedDateTime(call.Argument(0), _undefined)
+	return valueBool(z.ns.Cmp(otherNs) == 0 && z.tz.equals(otherTz))
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_toString(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "toString")
+	options := r.getOptionsObject(call.Argument(0))
+	showCalendar := r.temporalGetShowCalendarOption(options)
+	digits := r.temporalGetFractionalSecondDigitsOption(options)
+	showOffset := r.temporalGetStringOption(options, "offset", []string{"auto", "never"}, "auto")
+	mode := r.temporalGetRoundingModeOption(options, "trunc")
+	smallestUnit := r.temporalGetUnitOption(options, "smallestUnit", temporalUnitUnset)
+	showTimeZone := r.temporalGetStringOption(options, "timeZoneName", []string{"auto", "never", "critical"}, "auto")
+	precision := r.temporalToStringPrecision(smallestUnit, digits)
+	ns := r.checkEpochNs(temporalRoundInstant(z.ns, precision.increment, precision.unit, mode))
+	return newStringValue(temporalZonedDateTimeToString(ns, z.tz, precision.precision, showCalendar, showTimeZone, showOffset))
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_toJSON(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "toJSON")
+	return newStringValue(temporalZonedDateTimeToString(z.ns, z.tz, temporalPrecisionAuto, "auto", "auto", "auto"))
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_toLocaleString(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "toLocaleString")
+	return r.temporalToLocaleString(temporalEpochNsToTime(z.ns), 0, z.tz, call.Argument(0), call.Argument(1))
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_startOfDay(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "startOfDay")
+	return r.newTemporalZonedDateTime(r.temporalStartOfDay(z.tz, z.isoDateTime().date), z.tz, nil)
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_getTimeZoneTransition(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "getTimeZoneTransition")
+	directionParam := call.Argument(0)
+	if directionParam == _undefined {
+		panic(r.NewTypeError("Options parameter is required"))
+	}
+	var options *Object
+	if s, ok := directionParam.(String); ok {
+		options = r.NewObject()
+		options.self.setOwnStr("direction", s, false)
+	} else {
+		options = r.getOptionsObject(directionParam)
+	}
+	direction := r.temporalGetStringOption(options, "direction", []string{"next", "previous"}, "")
+	if direction == "" {
+		panic(r.newErrorf(r.getRangeError(), "direction is required"))
+	}
+	var ns *big.Int
+	if direction == "next" {
+		ns = z.tz.nextTransition(z.ns)
+	} else {
+		ns = z.tz.previousTransition(z.ns)
+	}
+	if ns == nil {
+		return _null
+	}
+	return r.newTemporalZonedDateTime(ns, z.tz, nil)
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_toInstant(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "toInstant")
+	return r.newTemporalInstant(z.ns, nil)
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_toPlainDate(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "toPlainDate")
+	return r.newTemporalPlainDate(z.isoDateTime().date, nil)
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_toPlainTime(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "toPlainTime")
+	return r.newTemporalPlainTime(z.isoDateTime().time, nil)
+}
+
+func (r *Runtime) temporalZonedDateTimeProto_toPlainDateTime(call FunctionCall) Value {
+	z := r.toTemporalZonedDateTimeObject(call.This, "toPlainDateTime")
+	return r.newTemporalPlainDateTime(z.isoDateTime(), nil)
+}
+
+func (r *Runtime) createTemporalZonedDateTimeProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
+
+	o._putProp("constructor", r.getTemporalZonedDateTime(), true, false, true)
+	r.putTemporalGetter(o, "timeZoneId", func(call FunctionCall) Value {
+		return newStringValue(r.toTemporalZonedDateTimeObject(call.This, "timeZoneId").tz.id)
+	})
+	r.putTemporalDateGetters(o, func(this Value, method string) isoDate {
+		return r.toTemporalZonedDateTimeObject(this, method).isoDateTime().date
+	})
+	r.putTemporalTimeGetters(o, func(this Value, method string) isoTime {
+		return r.toTemporalZonedDateTimeObject(this, method).isoDateTime().time
+	})
+	r.putTemporalGetter(o, "epochMilliseconds", func(call FunctionCall) Value {
+		z := r.toTemporalZonedDateTimeObject(call.This, "epochMilliseconds")
+		ms, _ := new(big.Int).DivMod(z.ns, big.NewInt(nsPerMillisecond), new(big.Int))
+		return intToValue(ms.Int64())
+	})
+	r.putTemporalGetter(o, "epochNanoseconds", func(call FunctionCall) Value {
+		z := r.toTemporalZonedDateTimeObject(call.This, "epochNanoseconds")
+		return (*valueBigInt)(new(big.Int).Set(z.ns))
+	})
+	r.putTemporalGetter(o, "hoursInDay", func(call FunctionCall) Value {
+		z := r.toTemporalZonedDateTimeObject(call.This, "hoursInDay")
+		today := z.isoDateTime().date
+		start := r.temporalStartOfDay(z.tz, today)
+		end := r.temporalStartOfDay(z.tz, today.addDays(1))
+		return floatToValue(totalTimeDuration(end.Sub(end, start), temporalHour))
+	})
+	r.putTemporalGetter(o, "offsetNanoseconds", func(call FunctionCall) Value {
+		z := r.toTemporalZonedDateTimeObject(call.This, "offsetNanoseconds")
+		return intToValue(z.tz.offsetNsFor(z.ns))
+	})
+	r.putTemporalGetter(o, "offset", func(call FunctionCall) Value {
+		z := r.toTemporalZonedDateTimeObject(call.This, "offset")
+		return asciiString(formatUTCOffsetNs(z.tz.offsetNsFor(z.ns)))
+	})
+	o._putProp("add", r.newNativeFunc(r.temporalZonedDateTimeProto_add, "add", 1), true, false, true)
+	o._putProp("equals", r.newNativeFunc(r.temporalZonedDateTimeProto_equals, "equals", 1), true, false, true)
+	o._putProp("getTimeZoneTransition", r.newNativeFunc(r.temporalZonedDateTimeProto_getTimeZoneTransition, "getTimeZoneTransition", 1), true, false, true)
+	o._putProp("round", r.newNativeFunc(r.temporalZonedDateTimeProto_round, "round", 1), true, false, true)
+	o._putProp("since", r.newNativeFunc(r.temporalZonedDateTimeProto_since, "since", 1), true, false, true)
+	o._putProp("startOfDay", r.newNativeFunc(r.temporalZonedDateTimeProto_startOfDay, "startOfDay", 0), true, false, true)
+	o._putProp("subtract", r.newNativeFunc(r.temporalZonedDateTimeProto_subtract, "subtract", 1), true, false, true)
+	o._putProp("toInstant", r.newNativeFunc(r.temporalZonedDateTimeProto_toInstant, "toInstant", 0), true, false, true)
+	o._putProp("toJSON", r.newNativeFunc(r.temporalZonedDateTimeProto_toJSON, "toJSON", 0), true, false, true)
+	o._putProp("toLocaleString", r.newNativeFunc(r.temporalZonedDateTimeProto_toLocaleString, "toLocaleString", 0), true, false, true)
+	o._putProp("toPlainDate", r.newNativeFunc(r.temporalZonedDateTimeProto_toPlainDate, "toPlainDate", 0), true, false, true)
+	o._putProp("toPlainDateTime", r.newNativeFunc(r.temporalZonedDateTimeProto_toPlainDateTime, "toPlainDateTime", 0), true, false, true)
+	o._putProp("toPlainTime", r.newNativeFunc(r.temporalZonedDateTimeProto_toPlainTime, "toPlainTime", 0), true, false, true)
+	o._putProp("toString", r.newNativeFunc(r.temporalZonedDateTimeProto_toString, "toString", 0), true, false, true)
+	o._putProp("until", r.newNativeFunc(r.temporalZonedDateTimeProto_until, "until", 1), true, false, true)
+	o._putProp("valueOf", r.newNativeFunc(r.temporalValueOf, "valueOf", 0), true, false, true)
+	o._putProp("with", r.newNativeFunc(r.temporalZonedDateTimeProto_with, "with", 1), true, false, true)
+	o._putProp("withCalendar", r.newNativeFunc(r.temporalZonedDateTimeProto_withCalendar, "withCalendar", 1), true, false, true)
+	o._putProp("withPlainTime", r.newNativeFunc(r.temporalZonedDateTimeProto_withPlainTime, "withPlainTime", 0), true, false, true)
+	o._putProp("withTimeZone", r.newNativeFunc(r.temporalZonedDateTimeProto_withTimeZone, "withTimeZone", 1), true, false, true)
+
+	o._putSym(SymToStringTag, valueProp(asciiString("Temporal.ZonedDateTime"), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) createTemporalZonedDateTime(val *Object) objectImpl {
+	o := r.newNativeConstructOnly(val, r.builtin_newTemporalZonedDateTime, r.getTemporalZonedDateTimePrototype(), "ZonedDateTime", 2)
+	o._putProp("compare", r.newNativeFunc(r.temporalZonedDateTime_compare, "compare", 2), true, false, true)
+	o._putProp("from", r.newNativeFunc(r.temporalZonedDateTime_from, "from", 1), true, false, true)
+
+	return o
+}
+
+func (r *Runtime) getTemporalZonedDateTimePrototype() *Object {
+	ret := r.global.TemporalZonedDateTimePrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.TemporalZonedDateTimePrototype = ret
+		ret.self = r.createTemporalZonedDateTimeProto(ret)
+	}
+	return ret
+}
+
+func (r *Runtime) getTemporalZonedDateTime() *Object {
+	ret := r.global.TemporalZonedDateTime
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.TemporalZonedDateTime = ret
+		ret.self = r.createTemporalZonedDateTime(ret)
+	}
+	return ret
+}
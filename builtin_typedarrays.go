@@ -1,6 +1,7 @@
 package sobek
 
 import (
+	"encoding/base64"
 	"fmt"
 	"math"
 	"sort"
@@ -11,64 +12,77 @@ import (
 	"github.com/grafana/sobek/unistring"
 )
 
+// typedArraySortCtx sorts the elements of a typed array in place using their natural order.
 type typedArraySortCtx struct {
-	ta           *typedArrayObject
-	compare      func(FunctionCall) Value
-	needValidate bool
-	detached     bool
+	ta     *typedArrayObject
+	length int
 }
 
 func (ctx *typedArraySortCtx) Len() int {
-	return ctx.ta.length
+	return ctx.length
 }
 
-func (ctx *typedArraySortCtx) checkDetached() {
-	if !ctx.detached && ctx.needValidate {
-		ctx.detached = !ctx.ta.viewedArrayBuf.ensureNotDetached(false)
-		ctx.needValidate = false
-	}
+func (ctx *typedArraySortCtx) Less(i, j int) bool {
+	offset := ctx.ta.offset
+	return ctx.ta.typedArray.less(offset+i, offset+j)
 }
 
-func (ctx *typedArraySortCtx) Less(i, j int) bool {
-	ctx.checkDetached()
-	if ctx.detached {
-		return false
-	}
+func (ctx *typedArraySortCtx) Swap(i, j int) {
 	offset := ctx.ta.offset
-	if ctx.compare != nil {
-		x := ctx.ta.typedArray.get(offset + i)
-		y := ctx.ta.typedArray.get(offset + j)
-		res := ctx.compare(FunctionCall{
-			This:      _undefined,
-			Arguments: []Value{x, y},
-		}).ToNumber()
-		ctx.needValidate = true
-		if i, ok := res.(valueInt); ok {
-			return i < 0
-		}
-		f := res.ToFloat()
-		if f < 0 {
-			return true
-		}
-		if f > 0 {
-			return false
-		}
-		if math.Signbit(f) {
-			return true
-		}
+	ctx.ta.typedArray.swap(offset+i, offset+j)
+}
+
+// typedArrayValuesSortCtx sorts the values of a typed array using a comparator. The values are copied out of
+// the array as in SortIndexedProperties, because the comparator may shrink or detach the buffer.
+type typedArrayValuesSortCtx struct {
+	values  []Value
+	compare func(FunctionCall) Value
+}
+
+func (ctx *typedArrayValuesSortCtx) Len() int {
+	return len(ctx.values)
+}
+
+func (ctx *typedArrayValuesSortCtx) Less(i, j int) bool {
+	res := ctx.compare(FunctionCall{
+		This:      _undefined,
+		Arguments: []Value{ctx.values[i], ctx.values[j]},
+	}).ToNumber()
+	if i, ok := res.(valueInt); ok {
+		return i < 0
+	}
+	f := res.ToFloat()
+	if f < 0 {
+		return true
+	}
+	if f > 0 {
 		return false
 	}
+	if math.Signbit(f) {
+		return true
+	}
+	return false
+}
 
-	return ctx.ta.typedArray.less(offset+i, offset+j)
+func (ctx *typedArrayValuesSortCtx) Swap(i, j int) {
+	ctx.values[i], ctx.values[j] = ctx.values[j], ctx.values[i]
 }
 
-func (ctx *typedArraySortCtx) Swap(i, j int) {
-	ctx.checkDetached()
-	if ctx.detached {
+// sortTypedArray sorts the first length elements of ta. If there is a comparator the sorted values are
+// written back only to the indices which are still valid once the sort is complete.
+func sortTypedArray(ta *typedArrayObject, length int, compare func(FunctionCall) Value) {
+	if compare == nil {
+		sort.Stable(&typedArraySortCtx{ta: ta, length: length})
 		return
 	}
-	offset := ctx.ta.offset
-	ctx.ta.typedArray.swap(offset+i, offset+j)
+	values := make([]Value, length)
+	for i := range values {
+		values[i] = ta.typedArray.get(ta.offset + i)
+	}
+	sort.Stable(&typedArrayValuesSortCtx{values: values, compare: compare})
+	for i, v := range values {
+		ta._putIdx(i, v)
+	}
 }
 
 func allocByteSlice(size int) (b []byte) {
@@ -88,16 +102,110 @@ func (r *Runtime) builtin_newArrayBuffer(args []Value, newTarget *Object) *Objec
 	if newTarget == nil {
 		panic(r.needNew("ArrayBuffer"))
 	}
-	b := r._newArrayBuffer(r.getPrototypeFromCtor(newTarget, r.getArrayBuffer(), r.getArrayBufferPrototype()), nil)
+	var byteLen int
 	if len(args) > 0 {
-		b.data = allocByteSlice(r.toIndex(args[0]))
+		byteLen = r.toIndex(args[0])
+	}
+	maxByteLen := -1
+	if len(args) > 1 {
+		if opts, ok := args[1].(*Object); ok {
+			if m := nilSafe(opts.self.getStr("maxByteLength", nil)); m != _undefined {
+				maxByteLen = r.toIndex(m)
+				if byteLen > maxByteLen {
+					panic(r.newErrorf(r.getRangeError(), "Invalid array buffer length %d, it exceeds maxByteLength (%d)", byteLen, maxByteLen))
+				}
+			}
+		}
+	}
+	b := r._newArrayBuffer(r.getPrototypeFromCtor(newTarget, r.getArrayBuffer(), r.getArrayBufferPrototype()), nil)
+	b.data = allocByteSlice(byteLen)
+	if maxByteLen >= 0 {
+		b.resizable = true
+		b.maxByteLen = maxByteLen
 	}
 	return b.val
 }
 
+func (r *Runtime) toArrayBuffer(v Value, method string) *arrayBufferObject {
+	o := r.toObject(v)
+	if b, ok := o.self.(*arrayBufferObject); ok && b.shared == nil {
+		return b
+	}
+	panic(r.NewTypeError("Method ArrayBuffer.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: o})))
+}
+
+func (r *Runtime) arrayBufferProto_getMaxByteLength(call FunctionCall) Value {
+	b := r.toArrayBuffer(call.This, "maxByteLength")
+	if b.detached {
+		return intToValue(0)
+	}
+	if b.resizable {
+		return intToValue(int64(b.maxByteLen))
+	}
+	return intToValue(int64(len(b.data)))
+}
+
+func (r *Runtime) arrayBufferProto_getResizable(call FunctionCall) Value {
+	return r.toBoolean(r.toArrayBuffer(call.This, "resizable").resizable)
+}
+
+func (r *Runtime) arrayBufferProto_getDetached(call FunctionCall) Value {
+	return r.toBoolean(r.toArrayBuffer(call.This, "detached").detached)
+}
+
+func (r *Runtime) arrayBufferProto_resize(call FunctionCall) Value {
+	b := r.toArrayBuffer(call.This, "resize")
+	if !b.resizable {
+		panic(r.NewTypeError("Method ArrayBuffer.prototype.resize called on a fixed-length ArrayBuffer"))
+	}
+	newLen := r.toIndex(call.Argument(0))
+	b.ensureNotDetached(true)
+	if newLen > b.maxByteLen {
+		panic(r.newErrorf(r.getRangeError(), "Invalid array buffer length %d, it exceeds maxByteLength (%d)", newLen, b.maxByteLen))
+	}
+	b.resize(newLen)
+	return _undefined
+}
+
+// arrayBufferCopyAndDetach implements ArrayBufferCopyAndDetach. If the new length does not exceed the current
+// one, the underlying data is moved to the new buffer without copying.
+func (r *Runtime) arrayBufferCopyAndDetach(b *arrayBufferObject, newLength Value, preserveResizability bool) Value {
+	var newLen int
+	if newLength == _undefined {
+		newLen = len(b.data)
+	} else {
+		newLen = r.toIndex(newLength)
+	}
+	b.ensureNotDetached(true)
+	ret := r._newArrayBuffer(r.getArrayBufferPrototype(), nil)
+	if preserveResizability && b.resizable {
+		if newLen > b.maxByteLen {
+			panic(r.newErrorf(r.getRangeError(), "Invalid array buffer length %d, it exceeds maxByteLength (%d)", newLen, b.maxByteLen))
+		}
+		ret.resizable = true
+		ret.maxByteLen = b.maxByteLen
+	}
+	if newLen <= len(b.data) {
+		ret.data = b.data[:newLen]
+	} else {
+		ret.data = allocByteSlice(newLen)
+		copy(ret.data, b.data)
+	}
+	b.detach()
+	return ret.val
+}
+
+func (r *Runtime) arrayBufferProto_transfer(call FunctionCall) Value {
+	return r.arrayBufferCopyAndDetach(r.toArrayBuffer(call.This, "transfer"), call.Argument(0), true)
+}
+
+func (r *Runtime) arrayBufferProto_transferToFixedLength(call FunctionCall) Value {
+	return r.arrayBufferCopyAndDetach(r.toArrayBuffer(call.This, "transferToFixedLength"), call.Argument(0), false)
+}
+
 func (r *Runtime) arrayBufferProto_getByteLength(call FunctionCall) Value {
 	o := r.toObject(call.This)
-	if b, ok := o.self.(*arrayBufferObject); ok {
+	if b, ok := o.self.(*arrayBufferObject); ok && b.shared == nil {
 		if b.ensureNotDetached(false) {
 			return intToValue(int64(len(b.data)))
 		}
@@ -108,7 +216,8 @@ func (r *Runtime) arrayBufferProto_getByteLength(call FunctionCall) Value {
 
 func (r *Runtime) arrayBufferProto_slice(call FunctionCall) Value {
 	o := r.toObject(call.This)
-	if b, ok := o.self.(*arrayBufferObject); ok {
+	if b, ok := o.self.(*arrayBufferObject); ok && b.shared == nil {
+		b.ensureNotDetached(true)
 		l := int64(len(b.data))
 		start := relToIdx(call.Argument(0).ToInteger(), l)
 		var stop int64
@@ -120,17 +229,17 @@ func (r *Runtime) arrayBufferProto_slice(call FunctionCall) Value {
 		stop = relToIdx(stop, l)
 		newLen := max(stop-start, 0)
 		ret := r.speciesConstructor(o, r.getArrayBuffer())([]Value{intToValue(newLen)}, nil)
-		if ab, ok := ret.self.(*arrayBufferObject); ok {
-			if newLen > 0 {
-				b.ensureNotDetached(true)
-				if ret == o {
-					panic(r.NewTypeError("Species constructor returned the same ArrayBuffer"))
-				}
-				if int64(len(ab.data)) < newLen {
-					panic(r.NewTypeError("Species constructor returned an ArrayBuffer that is too small: %d", len(ab.data)))
-				}
-				ab.ensureNotDetached(true)
-				copy(ab.data, b.data[start:stop])
+		if ab, ok := ret.self.(*arrayBufferObject); ok && ab.shared == nil {
+			ab.ensureNotDetached(true)
+			if ret == o {
+				panic(r.NewTypeError("Species constructor returned the same ArrayBuffer"))
+			}
+			if int64(len(ab.data)) < newLen {
+				panic(r.NewTypeError("Species constructor returned an ArrayBuffer that is too small: %d", len(ab.data)))
+			}
+			b.ensureNotDetached(true)
+			if curLen := int64(len(b.data)); start < curLen {
+				copy(ab.data, b.data[start:min(stop, curLen)])
 			}
 			return ret
 		}
@@ -169,28 +278,33 @@ func (r *Runtime) newDataView(args []Value, newTarget *Object) *Object {
 		panic(r.NewTypeError("First argument to DataView constructor must be an ArrayBuffer"))
 	}
 	var byteOffset, byteLen int
+	var offsetArg Value = _undefined
 	if len(args) > 1 {
-		offsetArg := nilSafe(args[1])
-		byteOffset = r.toIndex(offsetArg)
-		buffer.ensureNotDetached(true)
-		if byteOffset > len(buffer.data) {
-			panic(r.newErrorf(r.getRangeError(), "Start offset %s is outside the bounds of the buffer", offsetArg.String()))
-		}
+		offsetArg = nilSafe(args[1])
 	}
-	if len(args) > 2 && args[2] != nil && args[2] != _undefined {
+	byteOffset = r.toIndex(offsetArg)
+	buffer.ensureNotDetached(true)
+	buffer.syncLength()
+	if byteOffset > len(buffer.data) {
+		panic(r.newErrorf(r.getRangeError(), "Start offset %s is outside the bounds of the buffer", offsetArg.String()))
+	}
+	hasLength := len(args) > 2 && args[2] != nil && args[2] != _undefined
+	lengthTracking := !hasLength && buffer.resizable
+	if hasLength {
 		byteLen = r.toIndex(args[2])
 		if byteOffset+byteLen > len(buffer.data) {
 			panic(r.newErrorf(r.getRangeError(), "Invalid DataView length %d", byteLen))
 		}
-	} else {
+	} else if !lengthTracking {
 		byteLen = len(buffer.data) - byteOffset
 	}
 	proto := r.getPrototypeFromCtor(newTarget, r.getDataView(), r.getDataViewPrototype())
 	buffer.ensureNotDetached(true)
+	buffer.syncLength()
 	if byteOffset > len(buffer.data) {
 		panic(r.newErrorf(r.getRangeError(), "Start offset %d is outside the bounds of the buffer", byteOffset))
 	}
-	if byteOffset+byteLen > len(buffer.data) {
+	if hasLength && byteOffset+byteLen > len(buffer.data) {
 		panic(r.newErrorf(r.getRangeError(), "Invalid DataView length %d", byteLen))
 	}
 	o := &Object{runtime: r}
@@ -204,6 +318,7 @@ func (r *Runtime) newDataView(args []Value, newTarget *Object) *Object {
 		viewedArrayBuf: buffer,
 		byteOffset:     byteOffset,
 		byteLen:        byteLen,
+		lengthTracking: lengthTracking,
 	}
 	o.self = b
 	b.init()
@@ -219,20 +334,26 @@ func (r *Runtime) dataViewProto_getBuffer(call FunctionCall) Value {
 
 func (r *Runtime) dataViewProto_getByteLen(call FunctionCall) Value {
 	if dv, ok := r.toObject(call.This).self.(*dataViewObject); ok {
-		dv.viewedArrayBuf.ensureNotDetached(true)
-		return intToValue(int64(dv.byteLen))
+		return intToValue(int64(dv.validate()))
 	}
 	panic(r.NewTypeError("Method get DataView.prototype.byteLength called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: call.This})))
 }
 
 func (r *Runtime) dataViewProto_getByteOffset(call FunctionCall) Value {
 	if dv, ok := r.toObject(call.This).self.(*dataViewObject); ok {
-		dv.viewedArrayBuf.ensureNotDetached(true)
+		dv.validate()
 		return intToValue(int64(dv.byteOffset))
 	}
 	panic(r.NewTypeError("Method get DataView.prototype.byteOffset called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: call.This})))
 }
 
+func (r *Runtime) dataViewProto_getFloat16(call FunctionCall) Value {
+	if dv, ok := r.toObject(call.This).self.(*dataViewObject); ok {
+		return floatToValue(dv.viewedArrayBuf.getFloat16(dv.getIdxAndByteOrder(r.toIndex(call.Argument(0)), call.Argument(1), 2)))
+	}
+	panic(r.NewTypeError("Method DataView.prototype.getFloat16 called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: call.This})))
+}
+
 func (r *Runtime) dataViewProto_getFloat32(call FunctionCall) Value {
 	if dv, ok := r.toObject(call.This).self.(*dataViewObject); ok {
 		return floatToValue(float64(dv.viewedArrayBuf.getFloat32(dv.getIdxAndByteOrder(r.toIndex(call.Argument(0)), call.Argument(1), 4))))
@@ -305,6 +426,17 @@ func (r *Runtime) dataViewProto_getBigUint64(call FunctionCall) Value {
 	panic(r.NewTypeError("Method DataView.prototype.getBigUint64 called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: call.This})))
 }
 
+func (r *Runtime) dataViewProto_setFloat16(call FunctionCall) Value {
+	if dv, ok := r.toObject(call.This).self.(*dataViewObject); ok {
+		idxVal := r.toIndex(call.Argument(0))
+		val := toFloat16(call.Argument(1))
+		idx, bo := dv.getIdxAndByteOrder(idxVal, call.Argument(2), 2)
+		dv.viewedArrayBuf.setFloat16(idx, val, bo)
+		return _undefined
+	}
+	panic(r.NewTypeError("Method DataView.prototype.setFloat16 called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: call.This})))
+}
+
 func (r *Runtime) dataViewProto_setFloat32(call FunctionCall) Value {
 	if dv, ok := r.toObject(call.This).self.(*dataViewObject); ok {
 		idxVal := r.toIndex(call.Argument(0))
@@ -424,27 +556,21 @@ func (r *Runtime) typedArrayProto_getBuffer(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_getByteLen(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		if ta.viewedArrayBuf.data == nil {
-			return _positiveZero
-		}
-		return intToValue(int64(ta.length) * int64(ta.elemSize))
+		return intToValue(int64(ta.getLength()) * int64(ta.elemSize))
 	}
 	panic(r.NewTypeError("Method get TypedArray.prototype.byteLength called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: call.This})))
 }
 
 func (r *Runtime) typedArrayProto_getLength(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		if ta.viewedArrayBuf.data == nil {
-			return _positiveZero
-		}
-		return intToValue(int64(ta.length))
+		return intToValue(int64(ta.getLength()))
 	}
 	panic(r.NewTypeError("Method get TypedArray.prototype.length called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: call.This})))
 }
 
 func (r *Runtime) typedArrayProto_getByteOffset(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		if ta.viewedArrayBuf.data == nil {
+		if ta.isOutOfBounds() {
 			return _positiveZero
 		}
 		return intToValue(int64(ta.offset) * int64(ta.elemSize))
@@ -454,8 +580,7 @@ func (r *Runtime) typedArrayProto_getByteOffset(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_copyWithin(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
-		l := int64(ta.length)
+		l := int64(ta.validate())
 		var relEnd int64
 		to := toIntStrict(relToIdx(call.Argument(0).ToInteger(), l))
 		from := toIntStrict(relToIdx(call.Argument(1).ToInteger(), l))
@@ -465,12 +590,17 @@ func (r *Runtime) typedArrayProto_copyWithin(call FunctionCall) Value {
 			relEnd = l
 		}
 		final := toIntStrict(relToIdx(relEnd, l))
-		data := ta.viewedArrayBuf.data
-		offset := ta.offset
-		elemSize := ta.elemSize
-		if final > from {
-			ta.viewedArrayBuf.ensureNotDetached(true)
-			copy(data[(offset+to)*elemSize:], data[(offset+from)*elemSize:(offset+final)*elemSize])
+		count := min(final-from, int(l)-to)
+		if count > 0 {
+			// the buffer may have been shrunk by the conversions above
+			curLen := ta.validate()
+			count = min(count, curLen-from, curLen-to)
+			if count > 0 {
+				data := ta.viewedArrayBuf.data
+				offset := ta.offset
+				elemSize := ta.elemSize
+				copy(data[(offset+to)*elemSize:], data[(offset+from)*elemSize:(offset+from+count)*elemSize])
+			}
 		}
 		return call.This
 	}
@@ -479,7 +609,7 @@ func (r *Runtime) typedArrayProto_copyWithin(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_entries(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
+		ta.validate()
 		return r.createArrayIterator(ta.val, iterationKindKeyValue)
 	}
 	panic(r.NewTypeError("Method TypedArray.prototype.entries called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: call.This})))
@@ -487,13 +617,13 @@ func (r *Runtime) typedArrayProto_entries(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_every(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
+		length := ta.validate()
 		callbackFn := r.toCallable(call.Argument(0))
 		fc := FunctionCall{
 			This:      call.Argument(1),
 			Arguments: []Value{nil, nil, call.This},
 		}
-		for k := 0; k < ta.length; k++ {
+		for k := 0; k < length; k++ {
 			if ta.isValidIntegerIndex(k) {
 				fc.Arguments[0] = ta.typedArray.get(ta.offset + k)
 			} else {
@@ -512,8 +642,7 @@ func (r *Runtime) typedArrayProto_every(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_fill(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
-		l := int64(ta.length)
+		l := int64(ta.validate())
 		k := toIntStrict(relToIdx(call.Argument(1).ToInteger(), l))
 		var relEnd int64
 		if endArg := call.Argument(2); endArg != _undefined {
@@ -523,7 +652,7 @@ func (r *Runtime) typedArrayProto_fill(call FunctionCall) Value {
 		}
 		final := toIntStrict(relToIdx(relEnd, l))
 		value := ta.typedArray.toRaw(call.Argument(0))
-		ta.viewedArrayBuf.ensureNotDetached(true)
+		final = min(final, ta.validate())
 		for ; k < final; k++ {
 			ta.typedArray.setRaw(ta.offset+k, value)
 		}
@@ -535,16 +664,16 @@ func (r *Runtime) typedArrayProto_fill(call FunctionCall) Value {
 func (r *Runtime) typedArrayProto_filter(call FunctionCall) Value {
 	o := r.toObject(call.This)
 	if ta, ok := o.self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
+		length := ta.validate()
 		callbackFn := r.toCallable(call.Argument(0))
 		fc := FunctionCall{
 			This:      call.Argument(1),
 			Arguments: []Value{nil, nil, call.This},
 		}
-		buf := make([]byte, 0, ta.length*ta.elemSize)
+		buf := make([]byte, 0, length*ta.elemSize)
 		captured := 0
 		rawVal := make([]byte, ta.elemSize)
-		for k := 0; k < ta.length; k++ {
+		for k := 0; k < length; k++ {
 			if ta.isValidIntegerIndex(k) {
 				fc.Arguments[0] = ta.typedArray.get(ta.offset + k)
 				i := (ta.offset + k) * ta.elemSize
@@ -581,13 +710,13 @@ func (r *Runtime) typedArrayProto_filter(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_find(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
+		length := ta.validate()
 		predicate := r.toCallable(call.Argument(0))
 		fc := FunctionCall{
 			This:      call.Argument(1),
 			Arguments: []Value{nil, nil, call.This},
 		}
-		for k := 0; k < ta.length; k++ {
+		for k := 0; k < length; k++ {
 			var val Value
 			if ta.isValidIntegerIndex(k) {
 				val = ta.typedArray.get(ta.offset + k)
@@ -605,13 +734,13 @@ func (r *Runtime) typedArrayProto_find(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_findIndex(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
+		length := ta.validate()
 		predicate := r.toCallable(call.Argument(0))
 		fc := FunctionCall{
 			This:      call.Argument(1),
 			Arguments: []Value{nil, nil, call.This},
 		}
-		for k := 0; k < ta.length; k++ {
+		for k := 0; k < length; k++ {
 			if ta.isValidIntegerIndex(k) {
 				fc.Arguments[0] = ta.typedArray.get(ta.offset + k)
 			} else {
@@ -629,13 +758,13 @@ func (r *Runtime) typedArrayProto_findIndex(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_findLast(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
+		length := ta.validate()
 		predicate := r.toCallable(call.Argument(0))
 		fc := FunctionCall{
 			This:      call.Argument(1),
 			Arguments: []Value{nil, nil, call.This},
 		}
-		for k := ta.length - 1; k >= 0; k-- {
+		for k := length - 1; k >= 0; k-- {
 			var val Value
 			if ta.isValidIntegerIndex(k) {
 				val = ta.typedArray.get(ta.offset + k)
@@ -653,13 +782,13 @@ func (r *Runtime) typedArrayProto_findLast(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_findLastIndex(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
+		length := ta.validate()
 		predicate := r.toCallable(call.Argument(0))
 		fc := FunctionCall{
 			This:      call.Argument(1),
 			Arguments: []Value{nil, nil, call.This},
 		}
-		for k := ta.length - 1; k >= 0; k-- {
+		for k := length - 1; k >= 0; k-- {
 			if ta.isValidIntegerIndex(k) {
 				fc.Arguments[0] = ta.typedArray.get(ta.offset + k)
 			} else {
@@ -677,13 +806,13 @@ func (r *Runtime) typedArrayProto_findLastIndex(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_forEach(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
+		length := ta.validate()
 		callbackFn := r.toCallable(call.Argument(0))
 		fc := FunctionCall{
 			This:      call.Argument(1),
 			Arguments: []Value{nil, nil, call.This},
 		}
-		for k := 0; k < ta.length; k++ {
+		for k := 0; k < length; k++ {
 			var val Value
 			if ta.isValidIntegerIndex(k) {
 				val = ta.typedArray.get(ta.offset + k)
@@ -699,8 +828,7 @@ func (r *Runtime) typedArrayProto_forEach(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_includes(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
-		length := int64(ta.length)
+		length := int64(ta.validate())
 		if length == 0 {
 			return valueFalse
 		}
@@ -719,15 +847,15 @@ func (r *Runtime) typedArrayProto_includes(call FunctionCall) Value {
 			searchElement = _positiveZero
 		}
 		startIdx := toIntStrict(n)
-		if !ta.viewedArrayBuf.ensureNotDetached(false) {
-			if searchElement == _undefined && startIdx < ta.length {
-				return valueTrue
-			}
-			return valueFalse
+		// the buffer may have been shrunk or detached by the conversion above, in which case the elements
+		// past the end are treated as undefined
+		curLen := min(ta.getLength(), int(length))
+		if searchElement == _undefined && curLen < int(length) {
+			return valueTrue
 		}
 		if ta.typedArray.typeMatch(searchElement) {
 			se := ta.typedArray.toRaw(searchElement)
-			for k := startIdx; k < ta.length; k++ {
+			for k := startIdx; k < curLen; k++ {
 				if ta.typedArray.getRaw(ta.offset+k) == se {
 					return valueTrue
 				}
@@ -740,16 +868,15 @@ func (r *Runtime) typedArrayProto_includes(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_at(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
+		length := int64(ta.validate())
 		idx := call.Argument(0).ToInteger()
-		length := int64(ta.length)
 		if idx < 0 {
 			idx = length + idx
 		}
 		if idx >= length || idx < 0 {
 			return _undefined
 		}
-		if ta.viewedArrayBuf.ensureNotDetached(false) {
+		if ta.isValidIntegerIndex(int(idx)) {
 			return ta.typedArray.get(ta.offset + int(idx))
 		}
 		return _undefined
@@ -759,8 +886,7 @@ func (r *Runtime) typedArrayProto_at(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_indexOf(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
-		length := int64(ta.length)
+		length := int64(ta.validate())
 		if length == 0 {
 			return intToValue(-1)
 		}
@@ -774,14 +900,14 @@ func (r *Runtime) typedArrayProto_indexOf(call FunctionCall) Value {
 			n = max(length+n, 0)
 		}
 
-		if ta.viewedArrayBuf.ensureNotDetached(false) {
+		if curLen := min(ta.getLength(), int(length)); curLen > 0 {
 			searchElement := call.Argument(0)
 			if searchElement == _negativeZero {
 				searchElement = _positiveZero
 			}
 			if !IsNaN(searchElement) && ta.typedArray.typeMatch(searchElement) {
 				se := ta.typedArray.toRaw(searchElement)
-				for k := toIntStrict(n); k < ta.length; k++ {
+				for k := toIntStrict(n); k < curLen; k++ {
 					if ta.typedArray.getRaw(ta.offset+k) == se {
 						return intToValue(int64(k))
 					}
@@ -795,7 +921,7 @@ func (r *Runtime) typedArrayProto_indexOf(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_join(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
+		l := ta.validate()
 		s := call.Argument(0)
 		var sep String
 		if s != _undefined {
@@ -803,7 +929,6 @@ func (r *Runtime) typedArrayProto_join(call FunctionCall) Value {
 		} else {
 			sep = asciiString(",")
 		}
-		l := ta.length
 		if l == 0 {
 			return stringEmpty
 		}
@@ -835,7 +960,7 @@ func (r *Runtime) typedArrayProto_join(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_keys(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
+		ta.validate()
 		return r.createArrayIterator(ta.val, iterationKindKey)
 	}
 	panic(r.NewTypeError("Method TypedArray.prototype.keys called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: call.This})))
@@ -843,8 +968,7 @@ func (r *Runtime) typedArrayProto_keys(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_lastIndexOf(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
-		length := int64(ta.length)
+		length := int64(ta.validate())
 		if length == 0 {
 			return intToValue(-1)
 		}
@@ -865,14 +989,14 @@ func (r *Runtime) typedArrayProto_lastIndexOf(call FunctionCall) Value {
 			}
 		}
 
-		if ta.viewedArrayBuf.ensureNotDetached(false) {
+		if curLen := int64(ta.getLength()); curLen > 0 {
 			searchElement := call.Argument(0)
 			if searchElement == _negativeZero {
 				searchElement = _positiveZero
 			}
 			if !IsNaN(searchElement) && ta.typedArray.typeMatch(searchElement) {
 				se := ta.typedArray.toRaw(searchElement)
-				for k := toIntStrict(fromIndex); k >= 0; k-- {
+				for k := toIntStrict(min(fromIndex, curLen-1)); k >= 0; k-- {
 					if ta.typedArray.getRaw(ta.offset+k) == se {
 						return intToValue(int64(k))
 					}
@@ -887,21 +1011,21 @@ func (r *Runtime) typedArrayProto_lastIndexOf(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_map(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
+		length := ta.validate()
 		callbackFn := r.toCallable(call.Argument(0))
 		fc := FunctionCall{
 			This:      call.Argument(1),
 			Arguments: []Value{nil, nil, call.This},
 		}
-		dst := r.typedArraySpeciesCreate(ta, []Value{intToValue(int64(ta.length))})
-		for i := 0; i < ta.length; i++ {
+		dst := r.typedArraySpeciesCreate(ta, []Value{intToValue(int64(length))})
+		for i := 0; i < length; i++ {
 			if ta.isValidIntegerIndex(i) {
 				fc.Arguments[0] = ta.typedArray.get(ta.offset + i)
 			} else {
 				fc.Arguments[0] = _undefined
 			}
 			fc.Arguments[1] = intToValue(int64(i))
-			dst.typedArray.set(dst.offset+i, callbackFn(fc))
+			dst._putIdx(i, callbackFn(fc))
 		}
 		return dst.val
 	}
@@ -910,7 +1034,7 @@ func (r *Runtime) typedArrayProto_map(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_reduce(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
+		length := ta.validate()
 		callbackFn := r.toCallable(call.Argument(0))
 		fc := FunctionCall{
 			This:      _undefined,
@@ -920,7 +1044,7 @@ func (r *Runtime) typedArrayProto_reduce(call FunctionCall) Value {
 		if len(call.Arguments) >= 2 {
 			fc.Arguments[0] = call.Argument(1)
 		} else {
-			if ta.length > 0 {
+			if length > 0 {
 				fc.Arguments[0] = ta.typedArray.get(ta.offset + 0)
 				k = 1
 			}
@@ -928,7 +1052,7 @@ func (r *Runtime) typedArrayProto_reduce(call FunctionCall) Value {
 		if fc.Arguments[0] == nil {
 			panic(r.NewTypeError("Reduce of empty array with no initial value"))
 		}
-		for ; k < ta.length; k++ {
+		for ; k < length; k++ {
 			if ta.isValidIntegerIndex(k) {
 				fc.Arguments[1] = ta.typedArray.get(ta.offset + k)
 			} else {
@@ -945,13 +1069,12 @@ func (r *Runtime) typedArrayProto_reduce(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_reduceRight(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
+		k := ta.validate() - 1
 		callbackFn := r.toCallable(call.Argument(0))
 		fc := FunctionCall{
 			This:      _undefined,
 			Arguments: []Value{nil, nil, nil, call.This},
 		}
-		k := ta.length - 1
 		if len(call.Arguments) >= 2 {
 			fc.Arguments[0] = call.Argument(1)
 		} else {
@@ -980,8 +1103,7 @@ func (r *Runtime) typedArrayProto_reduceRight(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_reverse(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
-		l := ta.length
+		l := ta.validate()
 		middle := l / 2
 		for lower := 0; lower != middle; lower++ {
 			upper := l - lower - 1
@@ -1000,11 +1122,9 @@ func (r *Runtime) typedArrayProto_set(call FunctionCall) Value {
 		if targetOffset < 0 {
 			panic(r.newError(r.getRangeError(), "offset should be >= 0"))
 		}
-		ta.viewedArrayBuf.ensureNotDetached(true)
-		targetLen := ta.length
+		targetLen := ta.validate()
 		if src, ok := srcObj.self.(*typedArrayObject); ok {
-			src.viewedArrayBuf.ensureNotDetached(true)
-			srcLen := src.length
+			srcLen := src.validate()
 			if x := srcLen + targetOffset; x < 0 || x > targetLen {
 				panic(r.newError(r.getRangeError(), "Source is too large"))
 			}
@@ -1053,16 +1173,13 @@ func (r *Runtime) typedArrayProto_set(call FunctionCall) Value {
 				}
 			}
 		} else {
-			targetLen := ta.length
 			srcLen := toIntStrict(toLength(srcObj.self.getStr("length", nil)))
 			if x := srcLen + targetOffset; x < 0 || x > targetLen {
 				panic(r.newError(r.getRangeError(), "Source is too large"))
 			}
 			for i := 0; i < srcLen; i++ {
 				val := nilSafe(srcObj.self.getIdx(valueInt(i), nil))
-				if ta.isValidIntegerIndex(targetOffset + i) {
-					ta.typedArray.set(ta.offset+targetOffset+i, val)
-				}
+				ta._putIdx(targetOffset+i, val)
 			}
 		}
 		return _undefined
@@ -1072,8 +1189,7 @@ func (r *Runtime) typedArrayProto_set(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_slice(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
-		length := int64(ta.length)
+		length := int64(ta.validate())
 		start := toIntStrict(relToIdx(call.Argument(0).ToInteger(), length))
 		var e int64
 		if endArg := call.Argument(1); endArg != _undefined {
@@ -1088,17 +1204,20 @@ func (r *Runtime) typedArrayProto_slice(call FunctionCall) Value {
 			count = 0
 		}
 		dst := r.typedArraySpeciesCreate(ta, []Value{intToValue(int64(count))})
+		if count > 0 {
+			// the species constructor may have shrunk the buffer
+			end = min(end, ta.validate())
+			count = max(end-start, 0)
+		}
 		if dst.defaultCtor == ta.defaultCtor {
 			if count > 0 {
-				ta.viewedArrayBuf.ensureNotDetached(true)
 				offset := ta.offset
 				elemSize := ta.elemSize
-				copy(dst.viewedArrayBuf.data[dst.offset:], ta.viewedArrayBuf.data[(offset+start)*elemSize:(offset+start+count)*elemSize])
+				copy(dst.viewedArrayBuf.data[dst.offset*elemSize:], ta.viewedArrayBuf.data[(offset+start)*elemSize:(offset+start+count)*elemSize])
 			}
 		} else {
 			for i := 0; i < count; i++ {
-				ta.viewedArrayBuf.ensureNotDetached(true)
-				dst.typedArray.set(dst.offset+i, ta.typedArray.get(ta.offset+start+i))
+				dst._putIdx(i, ta.typedArray.get(ta.offset+start+i))
 			}
 		}
 		return dst.val
@@ -1108,13 +1227,13 @@ func (r *Runtime) typedArrayProto_slice(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_some(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
+		length := ta.validate()
 		callbackFn := r.toCallable(call.Argument(0))
 		fc := FunctionCall{
 			This:      call.Argument(1),
 			Arguments: []Value{nil, nil, call.This},
 		}
-		for k := 0; k < ta.length; k++ {
+		for k := 0; k < length; k++ {
 			if ta.isValidIntegerIndex(k) {
 				fc.Arguments[0] = ta.typedArray.get(ta.offset + k)
 			} else {
@@ -1132,19 +1251,13 @@ func (r *Runtime) typedArrayProto_some(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_sort(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
 		var compareFn func(FunctionCall) Value
 
 		if arg := call.Argument(0); arg != _undefined {
 			compareFn = r.toCallable(arg)
 		}
-
-		ctx := typedArraySortCtx{
-			ta:      ta,
-			compare: compareFn,
-		}
-
-		sort.Stable(&ctx)
+		length := ta.validate()
+		sortTypedArray(ta, length, compareFn)
 		return call.This
 	}
 	panic(r.NewTypeError("Method TypedArray.prototype.sort called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: call.This})))
@@ -1152,10 +1265,15 @@ func (r *Runtime) typedArrayProto_sort(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_subarray(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		l := int64(ta.length)
+		l := int64(ta.getLength())
 		beginIdx := relToIdx(call.Argument(0).ToInteger(), l)
+		beginByteOffset := intToValue((int64(ta.offset) + beginIdx) * int64(ta.elemSize))
+		endArg := call.Argument(1)
+		if ta.lengthTracking && endArg == _undefined {
+			return r.typedArraySpeciesCreate(ta, []Value{ta.viewedArrayBuf.val, beginByteOffset}).val
+		}
 		var relEnd int64
-		if endArg := call.Argument(1); endArg != _undefined {
+		if endArg != _undefined {
 			relEnd = endArg.ToInteger()
 		} else {
 			relEnd = l
@@ -1163,7 +1281,7 @@ func (r *Runtime) typedArrayProto_subarray(call FunctionCall) Value {
 		endIdx := relToIdx(relEnd, l)
 		newLen := max(endIdx-beginIdx, 0)
 		return r.typedArraySpeciesCreate(ta, []Value{ta.viewedArrayBuf.val,
-			intToValue((int64(ta.offset) + beginIdx) * int64(ta.elemSize)),
+			beginByteOffset,
 			intToValue(newLen),
 		}).val
 	}
@@ -1172,15 +1290,16 @@ func (r *Runtime) typedArrayProto_subarray(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_toLocaleString(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		length := ta.length
+		length := ta.validate()
+		args := []Value{call.Argument(0), call.Argument(1)}
 		var buf StringBuilder
 		for i := 0; i < length; i++ {
-			ta.viewedArrayBuf.ensureNotDetached(true)
 			if i > 0 {
 				buf.WriteRune(',')
 			}
-			item := ta.typedArray.get(ta.offset + i)
-			r.writeItemLocaleString(item, &buf)
+			if item := ta._getIdx(i); item != nil {
+				r.writeItemLocaleString(item, args, &buf)
+			}
 		}
 		return buf.String()
 	}
@@ -1189,7 +1308,7 @@ func (r *Runtime) typedArrayProto_toLocaleString(call FunctionCall) Value {
 
 func (r *Runtime) typedArrayProto_values(call FunctionCall) Value {
 	if ta, ok := r.toObject(call.This).self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
+		ta.validate()
 		return r.createArrayIterator(ta.val, iterationKindValue)
 	}
 	panic(r.NewTypeError("Method TypedArray.prototype.values called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: call.This})))
@@ -1211,8 +1330,7 @@ func (r *Runtime) typedArrayProto_with(call FunctionCall) Value {
 	if !ok {
 		panic(r.NewTypeError("%s is not a valid TypedArray", r.objectproto_toString(FunctionCall{This: call.This})))
 	}
-	ta.viewedArrayBuf.ensureNotDetached(true)
-	length := ta.length
+	length := ta.validate()
 	relativeIndex := call.Argument(0).ToInteger()
 	var actualIndex int
 
@@ -1221,9 +1339,6 @@ func (r *Runtime) typedArrayProto_with(call FunctionCall) Value {
 	} else {
 		actualIndex = toIntStrict(int64(length) + relativeIndex)
 	}
-	if !ta.isValidIntegerIndex(actualIndex) {
-		panic(r.newError(r.getRangeError(), "Invalid typed array index"))
-	}
 
 	var numericValue Value
 	switch ta.typedArray.(type) {
@@ -1232,6 +1347,9 @@ func (r *Runtime) typedArrayProto_with(call FunctionCall) Value {
 	default:
 		numericValue = call.Argument(1).ToNumber()
 	}
+	if !ta.isValidIntegerIndex(actualIndex) {
+		panic(r.newError(r.getRangeError(), "Invalid typed array index"))
+	}
 
 	a := r.typedArrayCreate(ta.defaultCtor, intToValue(int64(length)))
 	for k := 0; k < length; k++ {
@@ -1239,7 +1357,7 @@ func (r *Runtime) typedArrayProto_with(call FunctionCall) Value {
 		if k == actualIndex {
 			fromValue = numericValue
 		} else {
-			fromValue = ta.typedArray.get(ta.offset + k)
+			fromValue = nilSafe(ta._getIdx(k))
 		}
 		a.typedArray.set(k, fromValue)
 	}
@@ -1252,8 +1370,7 @@ func (r *Runtime) typedArrayProto_toReversed(call FunctionCall) Value {
 	if !ok {
 		panic(r.NewTypeError("%s is not a valid TypedArray", r.objectproto_toString(FunctionCall{This: call.This})))
 	}
-	ta.viewedArrayBuf.ensureNotDetached(true)
-	length := ta.length
+	length := ta.validate()
 
 	a := r.typedArrayCreate(ta.defaultCtor, intToValue(int64(length)))
 
@@ -1272,8 +1389,6 @@ func (r *Runtime) typedArrayProto_toSorted(call FunctionCall) Value {
 	if !ok {
 		panic(r.NewTypeError("%s is not a valid TypedArray", r.objectproto_toString(FunctionCall{This: call.This})))
 	}
-	ta.viewedArrayBuf.ensureNotDetached(true)
-
 	var compareFn func(FunctionCall) Value
 	arg := call.Argument(0)
 	if arg != _undefined {
@@ -1285,17 +1400,12 @@ func (r *Runtime) typedArrayProto_toSorted(call FunctionCall) Value {
 		}
 	}
 
-	length := ta.length
+	length := ta.validate()
 
 	a := r.typedArrayCreate(ta.defaultCtor, intToValue(int64(length)))
-	copy(a.viewedArrayBuf.data, ta.viewedArrayBuf.data)
-
-	ctx := typedArraySortCtx{
-		ta:      a,
-		compare: compareFn,
-	}
+	copy(a.viewedArrayBuf.data, ta.viewedArrayBuf.data[ta.offset*ta.elemSize:(ta.offset+length)*ta.elemSize])
 
-	sort.Stable(&ctx)
+	sortTypedArray(a, length, compareFn)
 
 	return a.val
 }
@@ -1377,10 +1487,10 @@ func (r *Runtime) typedArraySpeciesCreate(ta *typedArrayObject, args []Value) *t
 func (r *Runtime) typedArrayCreate(ctor *Object, args ...Value) *typedArrayObject {
 	o := r.toConstructor(ctor)(args, ctor)
 	if ta, ok := o.self.(*typedArrayObject); ok {
-		ta.viewedArrayBuf.ensureNotDetached(true)
+		length := ta.validate()
 		if len(args) == 1 {
 			if l, ok := args[0].(valueInt); ok {
-				if ta.length < int(l) {
+				if length < int(l) {
 					panic(r.NewTypeError("Derived TypedArray constructor created an array which was too small"))
 				}
 			}
@@ -1452,11 +1562,20 @@ func (r *Runtime) _newTypedArrayFromArrayBuffer(ab *arrayBufferObject, args []Va
 	if len(args) > 2 && args[2] != nil && args[2] != _undefined {
 		length = r.toIndex(args[2])
 		ab.ensureNotDetached(true)
+		ab.syncLength()
 		if byteOffset+length*ta.elemSize > len(ab.data) {
 			panic(r.newErrorf(r.getRangeError(), "Invalid typed array length: %d", length))
 		}
+	} else if ab.resizable {
+		ab.ensureNotDetached(true)
+		ab.syncLength()
+		if byteOffset > len(ab.data) {
+			panic(r.newErrorf(r.getRangeError(), "Start offset %d is outside the bounds of the buffer", byteOffset))
+		}
+		ta.lengthTracking = true
 	} else {
 		ab.ensureNotDetached(true)
+		ab.syncLength()
 		if len(ab.data)%ta.elemSize != 0 {
 			panic(r.newErrorf(r.getRangeError(), "Byte length of %s should be a multiple of %d", newTarget.self.getStr("name", nil), ta.elemSize))
 		}
@@ -1481,14 +1600,12 @@ func checkTypedArrayMixBigInt(src, dst *Object) {
 
 func (r *Runtime) _newTypedArrayFromTypedArray(src *typedArrayObject, newTarget *Object, taCtor typedArrayObjectCtor, proto *Object) *Object {
 	dst := r.allocateTypedArray(newTarget, 0, taCtor, proto)
-	src.viewedArrayBuf.ensureNotDetached(true)
-	l := src.length
+	l := src.validate()
 
 	dst.viewedArrayBuf.data = allocByteSlice(toIntStrict(int64(l) * int64(dst.elemSize)))
-	src.viewedArrayBuf.ensureNotDetached(true)
 	if src.defaultCtor == dst.defaultCtor {
 		copy(dst.viewedArrayBuf.data, src.viewedArrayBuf.data[src.offset*src.elemSize:])
-		dst.length = src.length
+		dst.length = l
 		return dst.val
 	} else {
 		checkTypedArrayMixBigInt(src.defaultCtor, newTarget)
@@ -1553,6 +1670,10 @@ func (r *Runtime) newInt32Array(args []Value, newTarget, proto *Object) *Object
 	return r._newTypedArray(args, newTarget, r.newInt32ArrayObject, proto)
 }
 
+func (r *Runtime) newFloat16Array(args []Value, newTarget, proto *Object) *Object {
+	return r._newTypedArray(args, newTarget, r.newFloat16ArrayObject, proto)
+}
+
 func (r *Runtime) newFloat32Array(args []Value, newTarget, proto *Object) *Object {
 	return r._newTypedArray(args, newTarget, r.newFloat32ArrayObject, proto)
 }
@@ -1569,6 +1690,299 @@ func (r *Runtime) newBigUint64Array(args []Value, newTarget, proto *Object) *Obj
 	return r._newTypedArray(args, newTarget, r.newBigUint64ArrayObject, proto)
 }
 
+type base64LastChunkHandling int
+
+const (
+	base64LastChunkLoose base64LastChunkHandling = iota
+	base64LastChunkStrict
+	base64LastChunkStopBeforePartial
+)
+
+func (r *Runtime) toUint8Array(v Value, method string) *typedArrayObject {
+	if o, ok := v.(*Object); ok {
+		if ta, ok := o.self.(*typedArrayObject); ok {
+			if _, ok := ta.typedArray.(*uint8Array); ok {
+				return ta
+			}
+		}
+	}
+	panic(r.NewTypeError("Method Uint8Array.prototype.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+func (r *Runtime) toBase64Input(v Value, method string) String {
+	if s, ok := v.(String); ok {
+		return s
+	}
+	panic(r.NewTypeError("%s: argument must be a string", method))
+}
+
+// getBase64Alphabet returns true if the alphabet option is "base64url".
+func (r *Runtime) getBase64Alphabet(opts *Object) bool {
+	switch alphabet := getOption(opts, "alphabet"); {
+	case alphabet == _undefined || alphabet.StrictEquals(asciiString("base64")):
+		return false
+	case alphabet.StrictEquals(asciiString("base64url")):
+		return true
+	}
+	panic(r.NewTypeError("alphabet must be either \"base64\" or \"base64url\""))
+}
+
+func (r *Runtime) getBase64LastChunkHandling(opts *Object) base64LastChunkHandling {
+	switch v := getOption(opts, "lastChunkHandling"); {
+	case v == _undefined || v.StrictEquals(asciiString("loose")):
+		return base64LastChunkLoose
+	case v.StrictEquals(asciiString("strict")):
+		return base64LastChunkStrict
+	case v.StrictEquals(asciiString("stop-before-partial")):
+		return base64LastChunkStopBeforePartial
+	}
+	panic(r.NewTypeError("lastChunkHandling must be one of \"loose\", \"strict\" or \"stop-before-partial\""))
+}
+
+func isASCIIWhitespace(c uint16) bool {
+	switch c {
+	case '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func base64CharValue(c uint16, url bool) (byte, bool) {
+	switch {
+	case c >= 'A' && c <= 'Z':
+		return byte(c - 'A'), true
+	case c >= 'a' && c <= 'z':
+		return byte(c-'a') + 26, true
+	case c >= '0' && c <= '9':
+		return byte(c-'0') + 52, true
+	case c == '+' && !url, c == '-' && url:
+		return 62, true
+	case c == '/' && !url, c == '_' && url:
+		return 63, true
+	}
+	return 0, false
+}
+
+// decodeBase64Chunk implements DecodeBase64Chunk and DecodeFinalBase64Chunk for the first n values of chunk.
+// Returns false if throwOnExtraBits is set and the unused bits are not zero.
+func decodeBase64Chunk(dst []byte, chunk *[4]byte, n int, throwOnExtraBits bool) ([]byte, bool) {
+	switch n {
+	case 2:
+		if throwOnExtraBits && chunk[1]&0x0f != 0 {
+			return dst, false
+		}
+		return append(dst, chunk[0]<<2|chunk[1]>>4), true
+	case 3:
+		if throwOnExtraBits && chunk[2]&0x03 != 0 {
+			return dst, false
+		}
+		return append(dst, chunk[0]<<2|chunk[1]>>4, chunk[1]<<4|chunk[2]>>2), true
+	}
+	return append(dst, chunk[0]<<2|chunk[1]>>4, chunk[1]<<4|chunk[2]>>2, chunk[2]<<6|chunk[3]), true
+}
+
+func skipASCIIWhitespace(s String, idx int) int {
+	for l := s.Length(); idx < l && isASCIIWhitespace(s.CharAt(idx)); idx++ {
+	}
+	return idx
+}
+
+// fromBase64 implements FromBase64. It returns the number of characters read, the decoded bytes and false if there
+// was an error, in which case the bytes contain what had been decoded before the error.
+func fromBase64(s String, url bool, lastChunkHandling base64LastChunkHandling, maxLength int) (read int, bytes []byte, ok bool) {
+	if maxLength == 0 {
+		return 0, nil, true
+	}
+	length := s.Length()
+	bytes = make([]byte, 0, min(length/4*3+2, maxLength))
+	var chunk [4]byte
+	chunkLength := 0
+	idx := 0
+	for {
+		idx = skipASCIIWhitespace(s, idx)
+		if idx == length {
+			if chunkLength > 0 {
+				switch lastChunkHandling {
+				case base64LastChunkStopBeforePartial:
+					return read, bytes, true
+				case base64LastChunkLoose:
+					if chunkLength == 1 {
+						return read, bytes, false
+					}
+					bytes, _ = decodeBase64Chunk(bytes, &chunk, chunkLength, false)
+				default:
+					return read, bytes, false
+				}
+			}
+			return length, bytes, true
+		}
+		c := s.CharAt(idx)
+		idx++
+		if c == '=' {
+			if chunkLength < 2 {
+				return read, bytes, false
+			}
+			idx = skipASCIIWhitespace(s, idx)
+			if chunkLength == 2 {
+				if idx == length {
+					if lastChunkHandling == base64LastChunkStopBeforePartial {
+						return read, bytes, true
+					}
+					return read, bytes, false
+				}
+				if s.CharAt(idx) == '=' {
+					idx = skipASCIIWhitespace(s, idx+1)
+				}
+			}
+			if idx < length {
+				return read, bytes, false
+			}
+			bytes, ok = decodeBase64Chunk(bytes, &chunk, chunkLength, lastChunkHandling == base64LastChunkStrict)
+			if !ok {
+				return read, bytes, false
+			}
+			return length, bytes, true
+		}
+		v, valid := base64CharValue(c, url)
+		if !valid {
+			return read, bytes, false
+		}
+		remaining := maxLength - len(bytes)
+		if remaining == 1 && chunkLength == 2 || remaining == 2 && chunkLength == 3 {
+			return read, bytes, true
+		}
+		chunk[chunkLength] = v
+		chunkLength++
+		if chunkLength == 4 {
+			bytes, _ = decodeBase64Chunk(bytes, &chunk, 4, false)
+			chunkLength = 0
+			read = idx
+			if len(bytes) == maxLength {
+				return read, bytes, true
+			}
+		}
+	}
+}
+
+func hexCharValue(c uint16) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return byte(c - '0'), true
+	case c >= 'a' && c <= 'f':
+		return byte(c-'a') + 10, true
+	case c >= 'A' && c <= 'F':
+		return byte(c-'A') + 10, true
+	}
+	return 0, false
+}
+
+// fromHex implements FromHex, see fromBase64 for the meaning of the return values.
+func fromHex(s String, maxLength int) (read int, bytes []byte, ok bool) {
+	length := s.Length()
+	if length%2 != 0 {
+		return 0, nil, false
+	}
+	bytes = make([]byte, 0, min(length/2, maxLength))
+	for read < length && len(bytes) < maxLength {
+		hi, ok1 := hexCharValue(s.CharAt(read))
+		lo, ok2 := hexCharValue(s.CharAt(read + 1))
+		if !ok1 || !ok2 {
+			return read, bytes, false
+		}
+		read += 2
+		bytes = append(bytes, hi<<4|lo)
+	}
+	return read, bytes, true
+}
+
+func (r *Runtime) newUint8ArrayFromBytes(data []byte) Value {
+	buf := r._newArrayBuffer(r.getArrayBufferPrototype(), nil)
+	buf.data = data
+	// Uint8Array.prototype is non-writable and non-configurable
+	proto := r.getUint8Array().self.getStr("prototype", nil).(*Object)
+	return r.newUint8ArrayObject(buf, 0, len(data), proto).val
+}
+
+func (r *Runtime) uint8Array_fromBase64(call FunctionCall) Value {
+	s := r.toBase64Input(call.Argument(0), "Uint8Array.fromBase64")
+	opts := r.getOptionsObject(call.Argument(1))
+	url := r.getBase64Alphabet(opts)
+	lastChunkHandling := r.getBase64LastChunkHandling(opts)
+	_, bytes, ok := fromBase64(s, url, lastChunkHandling, math.MaxInt)
+	if !ok {
+		panic(r.newError(r.getSyntaxError(), "Invalid base64 string"))
+	}
+	return r.newUint8ArrayFromBytes(bytes)
+}
+
+func (r *Runtime) uint8Array_fromHex(call FunctionCall) Value {
+	_, bytes, ok := fromHex(r.toBase64Input(call.Argument(0), "Uint8Array.fromHex"), math.MaxInt)
+	if !ok {
+		panic(r.newError(r.getSyntaxError(), "Invalid hex string"))
+	}
+	return r.newUint8ArrayFromBytes(bytes)
+}
+
+// setUint8ArrayBytes copies the decoded bytes into ta and returns the {read, written} result object.
+// The bytes are written even if there was a decoding error, in which case a SyntaxError is thrown afterwards.
+func (r *Runtime) setUint8ArrayBytes(ta *typedArrayObject, read int, bytes []byte, ok bool, msg string) Value {
+	copy(ta.viewedArrayBuf.data[ta.offset:], bytes)
+	if !ok {
+		panic(r.newError(r.getSyntaxError(), msg))
+	}
+	o := r.NewObject()
+	o.self.setOwnStr("read", intToValue(int64(read)), false)
+	o.self.setOwnStr("written", intToValue(int64(len(bytes))), false)
+	return o
+}
+
+func (r *Runtime) uint8ArrayProto_setFromBase64(call FunctionCall) Value {
+	ta := r.toUint8Array(call.This, "setFromBase64")
+	s := r.toBase64Input(call.Argument(0), "Uint8Array.prototype.setFromBase64")
+	opts := r.getOptionsObject(call.Argument(1))
+	url := r.getBase64Alphabet(opts)
+	lastChunkHandling := r.getBase64LastChunkHandling(opts)
+	read, bytes, ok := fromBase64(s, url, lastChunkHandling, ta.validate())
+	return r.setUint8ArrayBytes(ta, read, bytes, ok, "Invalid base64 string")
+}
+
+func (r *Runtime) uint8ArrayProto_setFromHex(call FunctionCall) Value {
+	ta := r.toUint8Array(call.This, "setFromHex")
+	s := r.toBase64Input(call.Argument(0), "Uint8Array.prototype.setFromHex")
+	read, bytes, ok := fromHex(s, ta.validate())
+	return r.setUint8ArrayBytes(ta, read, bytes, ok, "Invalid hex string")
+}
+
+func (r *Runtime) uint8ArrayProto_toBase64(call FunctionCall) Value {
+	ta := r.toUint8Array(call.This, "toBase64")
+	opts := r.getOptionsObject(call.Argument(0))
+	url := r.getBase64Alphabet(opts)
+	omitPadding := getOption(opts, "omitPadding").ToBoolean()
+	var enc *base64.Encoding
+	switch {
+	case url && omitPadding:
+		enc = base64.RawURLEncoding
+	case url:
+		enc = base64.URLEncoding
+	case omitPadding:
+		enc = base64.RawStdEncoding
+	default:
+		enc = base64.StdEncoding
+	}
+	l := ta.validate()
+	return asciiString(enc.EncodeToString(ta.viewedArrayBuf.data[ta.offset : ta.offset+l]))
+}
+
+func (r *Runtime) uint8ArrayProto_toHex(call FunctionCall) Value {
+	ta := r.toUint8Array(call.This, "toHex")
+	l := ta.validate()
+	buf := make([]byte, 0, l*2)
+	for _, b := range ta.viewedArrayBuf.data[ta.offset : ta.offset+l] {
+		buf = append(buf, hex[b>>4], hex[b&0xf])
+	}
+	return asciiString(buf)
+}
+
 func (r *Runtime) createArrayBufferProto(val *Object) objectImpl {
 	b := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
 	byteLengthProp := &valueProperty{
@@ -1578,7 +1992,25 @@ func (r *Runtime) createArrayBufferProto(val *Object) objectImpl {
 	}
 	b._put("byteLength", byteLengthProp)
 	b._putProp("constructor", r.getArrayBuffer(), true, false, true)
+	b._put("detached", &valueProperty{
+		accessor:     true,
+		configurable: true,
+		getterFunc:   r.newNativeFunc(r.arrayBufferProto_getDetached, "get detached", 0),
+	})
+	b._put("maxByteLength", &valueProperty{
+		accessor:     true,
+		configurable: true,
+		getterFunc:   r.newNativeFunc(r.arrayBufferProto_getMaxByteLength, "get maxByteLength", 0),
+	})
+	b._putProp("resize", r.newNativeFunc(r.arrayBufferProto_resize, "resize", 1), true, false, true)
+	b._put("resizable", &valueProperty{
+		accessor:     true,
+		configurable: true,
+		getterFunc:   r.newNativeFunc(r.arrayBufferProto_getResizable, "get resizable", 0),
+	})
 	b._putProp("slice", r.newNativeFunc(r.arrayBufferProto_slice, "slice", 2), true, false, true)
+	b._putProp("transfer", r.newNativeFunc(r.arrayBufferProto_transfer, "transfer", 0), true, false, true)
+	b._putProp("transferToFixedLength", r.newNativeFunc(r.arrayBufferProto_transferToFixedLength, "transferToFixedLength", 0), true, false, true)
 	b._putSym(SymToStringTag, valueProp(asciiString("ArrayBuffer"), false, false, true))
 	return b
 }
@@ -1615,7 +2047,7 @@ func (r *Runtime) getTypedArray() *Object {
 	return ret
 }
 
-func (r *Runtime) createTypedArrayCtor(val *Object, ctor func(args []Value, newTarget, proto *Object) *Object, name unistring.String, bytesPerElement int) {
+func (r *Runtime) createTypedArrayCtor(val *Object, ctor func(args []Value, newTarget, proto *Object) *Object, name unistring.String, bytesPerElement int) (*nativeFuncObject, *baseObject) {
 	p := r.newBaseObject(r.getTypedArrayPrototype(), classObject)
 	o := r.newNativeConstructOnly(val, func(args []Value, newTarget *Object) *Object {
 		return ctor(args, newTarget, p.val)
@@ -1627,10 +2059,12 @@ func (r *Runtime) createTypedArrayCtor(val *Object, ctor func(args []Value, newT
 	bpe := intToValue(int64(bytesPerElement))
 	o._putProp("BYTES_PER_ELEMENT", bpe, false, false, false)
 	p._putProp("BYTES_PER_ELEMENT", bpe, false, false, false)
+	return o, p
 }
 
 func addTypedArrays(t *objectTemplate) {
 	t.putStr("ArrayBuffer", func(r *Runtime) Value { return valueProp(r.getArrayBuffer(), true, false, true) })
+	t.putStr("SharedArrayBuffer", func(r *Runtime) Value { return valueProp(r.getSharedArrayBuffer(), true, false, true) })
 	t.putStr("DataView", func(r *Runtime) Value { return valueProp(r.getDataView(), true, false, true) })
 	t.putStr("Uint8Array", func(r *Runtime) Value { return valueProp(r.getUint8Array(), true, false, true) })
 	t.putStr("Uint8ClampedArray", func(r *Runtime) Value { return valueProp(r.getUint8ClampedArray(), true, false, true) })
@@ -1639,6 +2073,7 @@ func addTypedArrays(t *objectTemplate) {
 	t.putStr("Int16Array", func(r *Runtime) Value { return valueProp(r.getInt16Array(), true, false, true) })
 	t.putStr("Uint32Array", func(r *Runtime) Value { return valueProp(r.getUint32Array(), true, false, true) })
 	t.putStr("Int32Array", func(r *Runtime) Value { return valueProp(r.getInt32Array(), true, false, true) })
+	t.putStr("Float16Array", func(r *Runtime) Value { return valueProp(r.getFloat16Array(), true, false, true) })
 	t.putStr("Float32Array", func(r *Runtime) Value { return valueProp(r.getFloat32Array(), true, false, true) })
 	t.putStr("Float64Array", func(r *Runtime) Value { return valueProp(r.getFloat64Array(), true, false, true) })
 	t.putStr("BigInt64Array", func(r *Runtime) Value { return valueProp(r.getBigInt64Array(), true, false, true) })
@@ -1761,7 +2196,13 @@ func (r *Runtime) getUint8Array() *Object {
 	if ret == nil {
 		ret = &Object{runtime: r}
 		r.global.Uint8Array = ret
-		r.createTypedArrayCtor(ret, r.newUint8Array, "Uint8Array", 1)
+		o, p := r.createTypedArrayCtor(ret, r.newUint8Array, "Uint8Array", 1)
+		o._putProp("fromBase64", r.newNativeFunc(r.uint8Array_fromBase64, "fromBase64", 1), true, false, true)
+		o._putProp("fromHex", r.newNativeFunc(r.uint8Array_fromHex, "fromHex", 1), true, false, true)
+		p._putProp("setFromBase64", r.newNativeFunc(r.uint8ArrayProto_setFromBase64, "setFromBase64", 1), true, false, true)
+		p._putProp("setFromHex", r.newNativeFunc(r.uint8ArrayProto_setFromHex, "setFromHex", 1), true, false, true)
+		p._putProp("toBase64", r.newNativeFunc(r.uint8ArrayProto_toBase64, "toBase64", 0), true, false, true)
+		p._putProp("toHex", r.newNativeFunc(r.uint8ArrayProto_toHex, "toHex", 0), true, false, true)
 	}
 	return ret
 }
@@ -1826,6 +2267,16 @@ func (r *Runtime) getInt32Array() *Object {
 	return ret
 }
 
+func (r *Runtime) getFloat16Array() *Object {
+	ret := r.global.Float16Array
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.Float16Array = ret
+		r.createTypedArrayCtor(ret, r.newFloat16Array, "Float16Array", 2)
+	}
+	return ret
+}
+
 func (r *Runtime) getFloat32Array() *Object {
 	ret := r.global.Float32Array
 	if ret == nil {
@@ -1896,6 +2347,7 @@ func createDataViewProtoTemplate() *objectTemplate {
 
 	t.putStr("constructor", func(r *Runtime) Value { return valueProp(r.getDataView(), true, false, true) })
 
+	t.putStr("getFloat16", func(r *Runtime) Value { return r.methodProp(r.dataViewProto_getFloat16, "getFloat16", 1) })
 	t.putStr("getFloat32", func(r *Runtime) Value { return r.methodProp(r.dataViewProto_getFloat32, "getFloat32", 1) })
 	t.putStr("getFloat64", func(r *Runtime) Value { return r.methodProp(r.dataViewProto_getFloat64, "getFloat64", 1) })
 	t.putStr("getInt8", func(r *Runtime) Value { return r.methodProp(r.dataViewProto_getInt8, "getInt8", 1) })
@@ -1906,6 +2358,7 @@ func createDataViewProtoTemplate() *objectTemplate {
 	t.putStr("getUint32", func(r *Runtime) Value { return r.methodProp(r.dataViewProto_getUint32, "getUint32", 1) })
 	t.putStr("getBigInt64", func(r *Runtime) Value { return r.methodProp(r.dataViewProto_getBigInt64, "getBigInt64", 1) })
 	t.putStr("getBigUint64", func(r *Runtime) Value { return r.methodProp(r.dataViewProto_getBigUint64, "getBigUint64", 1) })
+	t.putStr("setFloat16", func(r *Runtime) Value { return r.methodProp(r.dataViewProto_setFloat16, "setFloat16", 2) })
 	t.putStr("setFloat32", func(r *Runtime) Value { return r.methodProp(r.dataViewProto_setFloat32, "setFloat32", 2) })
 	t.putStr("setFloat64", func(r *Runtime) Value { return r.methodProp(r.dataViewProto_setFloat64, "setFloat64", 2) })
 	t.putStr("setInt8", func(r *Runtime) Value { return r.methodProp(r.dataViewProto_setInt8, "setInt8", 2) })
@@ -488,3 +488,207 @@ func TestTypedArrayMapEvilSpecies(t *testing.T) {
 	`
 	testScript(SCRIPT, intToValue(1), t)
 }
+
+func TestResizableArrayBuffer(t *testing.T) {
+	const SCRIPT = `
+	const buf = new ArrayBuffer(4, {maxByteLength: 16});
+	assert.sameValue(buf.resizable, true);
+	assert.sameValue(buf.maxByteLength, 16);
+	assert.sameValue(new ArrayBuffer(4).resizable, false);
+	assert.sameValue(new ArrayBuffer(4).maxByteLength, 4);
+	assert.throws(RangeError, () => new ArrayBuffer(5, {maxByteLength: 4}));
+
+	const tracking = new Uint8Array(buf);
+	const fixed = new Uint8Array(buf, 0, 4);
+	const dv = new DataView(buf, 2);
+	tracking[3] = 42;
+	buf.resize(8);
+	assert.sameValue(buf.byteLength, 8);
+	assert.sameValue(tracking.length, 8);
+	assert.sameValue(fixed.length, 4);
+	assert.sameValue(dv.byteLength, 6);
+	assert.sameValue(tracking[3], 42);
+	assert.sameValue(tracking[7], 0);
+
+	buf.resize(2);
+	assert.sameValue(tracking.length, 2);
+	assert.sameValue(fixed.length, 0, "out of bounds");
+	assert.sameValue(fixed.byteOffset, 0);
+	assert.sameValue(fixed[0], undefined);
+	assert.throws(TypeError, () => fixed.fill(0));
+	assert.sameValue(dv.byteLength, 0);
+	assert.throws(RangeError, () => dv.getUint8(0));
+	buf.resize(1);
+	assert.throws(TypeError, () => dv.getUint8(0));
+	assert.throws(TypeError, () => dv.byteLength);
+
+	buf.resize(4);
+	assert.sameValue(fixed.length, 4, "back in bounds");
+	assert.sameValue(tracking[3], 0, "the grown part is zeroed");
+	assert.throws(RangeError, () => buf.resize(17));
+	assert.throws(TypeError, () => new ArrayBuffer(1).resize(1));
+
+	const sub = tracking.subarray(1);
+	buf.resize(6);
+	assert.sameValue(sub.length, 5, "subarray of a length-tracking array is length-tracking");
+
+	let shrunk = false;
+	const res = tracking.map((v, i) => {
+		if (!shrunk) {
+			shrunk = true;
+			buf.resize(2);
+		}
+		return i;
+	});
+	assert.sameValue(res.length, 6);
+	assert.sameValue(tracking.length, 2);
+	assert(compareArray([...res], [0, 1, 2, 3, 4, 5]));
+	assert(compareArray([...tracking], [0, 0]));
+	assert.sameValue(tracking.includes(undefined), false);
+
+	const sortBuf = new ArrayBuffer(4, {maxByteLength: 4});
+	const sorting = new Uint8Array(sortBuf);
+	sorting.set([4, 3, 2, 1]);
+	shrunk = false;
+	sorting.sort((a, b) => {
+		if (!shrunk) {
+			shrunk = true;
+			sortBuf.resize(2);
+		}
+		return a - b;
+	});
+	assert(compareArray([...sorting], [1, 2]), "sort writes back the indices which are still in bounds");
+
+	sortBuf.resize(4);
+	sorting.set([4, 3, 2, 1]);
+	const sorted = sorting.toSorted((a, b) => {
+		sortBuf.resize(0);
+		return a - b;
+	});
+	assert(compareArray([...sorted], [1, 2, 3, 4]), "toSorted");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestArrayBufferTransfer(t *testing.T) {
+	const SCRIPT = `
+	const buf = new ArrayBuffer(4, {maxByteLength: 8});
+	new Uint8Array(buf).set([1, 2, 3, 4]);
+	const ta = new Uint8Array(buf);
+	const moved = buf.transfer(6);
+	assert.sameValue(buf.detached, true);
+	assert.sameValue(buf.byteLength, 0);
+	assert.sameValue(ta.length, 0);
+	assert.throws(TypeError, () => buf.transfer());
+	assert.sameValue(moved.resizable, true);
+	assert.sameValue(moved.maxByteLength, 8);
+	assert(compareArray([...new Uint8Array(moved)], [1, 2, 3, 4, 0, 0]));
+
+	const fixed = moved.transferToFixedLength(2);
+	assert.sameValue(fixed.resizable, false);
+	assert(compareArray([...new Uint8Array(fixed)], [1, 2]));
+	assert.throws(RangeError, () => fixed.transfer(2).transfer(-1));
+	assert.throws(RangeError, () => new ArrayBuffer(1, {maxByteLength: 2}).transfer(3));
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestResizableArrayBufferGo(t *testing.T) {
+	vm := New()
+	data := make([]byte, 2, 8)
+	ab := vm.NewResizableArrayBuffer(data, 8)
+	if !ab.Resizable() || ab.MaxByteLength() != 8 {
+		t.Fatal(ab.Resizable(), ab.MaxByteLength())
+	}
+	vm.Set("buf", ab)
+	ta, err := vm.RunString(`const ta = new Uint8Array(buf); ta[1] = 7; ta`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ab.Resize(4); err != nil {
+		t.Fatal(err)
+	}
+	if &ab.Bytes()[0] != &data[0] {
+		t.Fatal("the buffer was reallocated")
+	}
+	if l := ta.ToObject(vm).Get("length").ToInteger(); l != 4 {
+		t.Fatal(l)
+	}
+	if err := ab.Resize(9); err == nil {
+		t.Fatal("expected error")
+	}
+	if err := vm.NewArrayBuffer(nil).Resize(0); err == nil {
+		t.Fatal("expected error")
+	}
+	ab.Detach()
+	if err := ab.Resize(1); err == nil {
+		t.Fatal("expected error")
+	}
+	res, err := vm.RunString(`ta.length`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ToInteger() != 0 {
+		t.Fatal(res)
+	}
+}
+
+func TestUint8ArrayBase64(t *testing.T) {
+	const SCRIPT = `
+	const bytes = new Uint8Array([72, 101, 108, 108, 111, 251, 255]);
+	assert.sameValue(bytes.toBase64(), "SGVsbG/7/w==");
+	assert.sameValue(bytes.toBase64({alphabet: "base64url"}), "SGVsbG_7_w==");
+	assert.sameValue(bytes.toBase64({omitPadding: true}), "SGVsbG/7/w");
+	assert.sameValue(new Uint8Array(bytes.buffer, 1, 2).toBase64(), "ZWw=");
+	assert.throws(TypeError, () => bytes.toBase64({alphabet: "other"}));
+	assert.throws(TypeError, () => Uint8Array.prototype.toBase64.call(new Uint8ClampedArray(1)));
+
+	assert(compareArray(Uint8Array.fromBase64("SGVsbG/7/w=="), bytes), "padded");
+	assert(compareArray(Uint8Array.fromBase64(" SGVs\nbG/7 /w "), bytes), "whitespace and no padding");
+	assert(compareArray(Uint8Array.fromBase64("SGVsbG_7_w", {alphabet: "base64url"}), bytes), "base64url");
+	assert.throws(SyntaxError, () => Uint8Array.fromBase64("SGVsbG/7/w", {alphabet: "base64url"}));
+	assert.throws(SyntaxError, () => Uint8Array.fromBase64("SGVsbG/7/w", {lastChunkHandling: "strict"}));
+	assert.throws(SyntaxError, () => Uint8Array.fromBase64("SGVsbG/7/x==", {lastChunkHandling: "strict"}));
+	assert(compareArray(Uint8Array.fromBase64("SGVsbG/7/x=="), bytes), "loose ignores extra bits");
+	assert(compareArray(Uint8Array.fromBase64("SGVsbG/7/w", {lastChunkHandling: "stop-before-partial"}), [72, 101, 108, 108, 111, 251]), "stop-before-partial");
+	assert.throws(SyntaxError, () => Uint8Array.fromBase64("SGVsbG/7/w=x"));
+	assert.throws(SyntaxError, () => Uint8Array.fromBase64("S"));
+	assert.throws(TypeError, () => Uint8Array.fromBase64(1));
+	assert.throws(TypeError, () => Uint8Array.fromBase64("", 1));
+
+	const target = new Uint8Array(4);
+	let res = target.setFromBase64("SGVsbG8=");
+	assert.sameValue(res.read, 4);
+	assert.sameValue(res.written, 3);
+	assert(compareArray(target, [72, 101, 108, 0]), "only complete chunks that fit are decoded");
+	assert.throws(SyntaxError, () => target.setFromBase64("AAAA!"));
+	assert(compareArray(target, [0, 0, 0, 0]), "bytes decoded before the error are written");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestUint8ArrayHex(t *testing.T) {
+	const SCRIPT = `
+	const bytes = new Uint8Array([0, 15, 16, 171, 255]);
+	assert.sameValue(bytes.toHex(), "000f10abff");
+	assert(compareArray(Uint8Array.fromHex("000F10abFF"), bytes), "fromHex");
+	assert.throws(SyntaxError, () => Uint8Array.fromHex("abc"));
+	assert.throws(SyntaxError, () => Uint8Array.fromHex("zz"));
+
+	const target = new Uint8Array(3);
+	let res = target.setFromHex("0102030405");
+	assert.sameValue(res.read, 6);
+	assert.sameValue(res.written, 3);
+	assert(compareArray(target, [1, 2, 3]), "setFromHex");
+	assert.throws(SyntaxError, () => target.setFromHex("09xx"));
+	assert(compareArray(target, [9, 2, 3]), "bytes decoded before the error are written");
+
+	const rab = new ArrayBuffer(2, {maxByteLength: 4});
+	const tracking = new Uint8Array(rab);
+	rab.resize(0);
+	assert.sameValue(tracking.toHex(), "");
+	rab.transfer();
+	assert.throws(TypeError, () => tracking.toHex());
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
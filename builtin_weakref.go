@@ -0,0 +1,140 @@
+package sobek
+
+import (
+	"weak"
+)
+
+// weakValue holds a value that satisfies CanBeHeldWeakly (i.e. an Object or a non-registered Symbol)
+// without preventing it from being garbage collected.
+type weakValue struct {
+	obj weak.Pointer[Object]
+	sym weak.Pointer[Symbol]
+}
+
+func newWeakValue(v Value) weakValue {
+	switch v := v.(type) {
+	case *Object:
+		return weakValue{obj: weak.Make(v)}
+	case *Symbol:
+		return weakValue{sym: weak.Make(v)}
+	}
+	return weakValue{}
+}
+
+// get returns the value or nil if it has been collected (or was never set).
+func (w weakValue) get() Value {
+	if o := w.obj.Value(); o != nil {
+		return o
+	}
+	if s := w.sym.Value(); s != nil {
+		return s
+	}
+	return nil
+}
+
+type weakRefObject struct {
+	baseObject
+	target weakValue
+}
+
+func (r *Runtime) isRegisteredSymbol(sym *Symbol) bool {
+	for _, s := range r.symbolRegistry {
+		if s == sym {
+			return true
+		}
+	}
+	return false
+}
+
+// canBeHeldWeakly implements CanBeHeldWeakly
+func (r *Runtime) canBeHeldWeakly(v Value) bool {
+	switch v := v.(type) {
+	case *Object:
+		return true
+	case *Symbol:
+		return !r.isRegisteredSymbol(v)
+	}
+	return false
+}
+
+// addToKeptObjects implements AddToKeptObjects. The kept objects are released when control
+// is passed outside the Runtime.
+func (r *Runtime) addToKeptObjects(v Value) {
+	r.keptObjects = append(r.keptObjects, v)
+}
+
+func (r *Runtime) weakRefProto_deref(call FunctionCall) Value {
+	thisObj := r.toObject(call.This)
+	wro, ok := thisObj.self.(*weakRefObject)
+	if !ok {
+		panic(r.NewTypeError("Method WeakRef.prototype.deref called on incompatible receiver %s", r.objectproto_toString(FunctionCall{This: thisObj})))
+	}
+	if target := wro.target.get(); target != nil {
+		r.addToKeptObjects(target)
+		return target
+	}
+	return _undefined
+}
+
+func (r *Runtime) builtin_newWeakRef(args []Value, newTarget *Object) *Object {
+	if newTarget == nil {
+		panic(r.needNew("WeakRef"))
+	}
+	var target Value = _undefined
+	if len(args) > 0 {
+		target = args[0]
+	}
+	if !r.canBeHeldWeakly(target) {
+		panic(r.NewTypeError("WeakRef: invalid target"))
+	}
+	proto := r.getPrototypeFromCtor(newTarget, r.global.WeakRef, r.getWeakRefPrototype())
+	o := &Object{runtime: r}
+
+	wro := &weakRefObject{}
+	wro.class = classObject
+	wro.val = o
+	wro.extensible = true
+	o.self = wro
+	wro.prototype = proto
+	wro.init()
+	wro.target = newWeakValue(target)
+	r.addToKeptObjects(target)
+	return o
+}
+
+func (r *Runtime) createWeakRefProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
+
+	o._putProp("constructor", r.getWeakRef(), true, false, true)
+	o._putProp("deref", r.newNativeFunc(r.weakRefProto_deref, "deref", 0), true, false, true)
+
+	o._putSym(SymToStringTag, valueProp(asciiString(classWeakRef), false, false, true))
+
+	return o
+}
+
+func (r *Runtime) createWeakRef(val *Object) objectImpl {
+	o := r.newNativeConstructOnly(val, r.builtin_newWeakRef, r.getWeakRefPrototype(), "WeakRef", 1)
+
+	return o
+}
+
+func (r *Runtime) getWeakRefPrototype() *Object {
+	ret := r.global.WeakRefPrototype
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.WeakRefPrototype = ret
+		ret.self = r.createWeakRefProto(ret)
+	}
+	return ret
+}
+
+func (r *Runtime) getWeakRef() *Object {
+	ret := r.global.WeakRef
+	if ret == nil {
+		ret = &Object{runtime: r}
+		r.global.WeakRef = ret
+		ret.self = r.createWeakRef(ret)
+	}
+	return ret
+}
@@ -0,0 +1,45 @@
+package sobek
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestWeakRef(t *testing.T) {
+	const SCRIPT = `
+	const target = {};
+	const ref = new WeakRef(target);
+	assert.sameValue(ref.deref(), target);
+	assert.sameValue(new WeakRef(Symbol("s")).deref().description, "s");
+	assert.throws(TypeError, () => new WeakRef(1));
+	assert.throws(TypeError, () => new WeakRef(Symbol.for("registered")));
+	assert.throws(TypeError, () => WeakRef({}));
+	assert.sameValue(Object.prototype.toString.call(ref), "[object WeakRef]");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestWeakRefCollected(t *testing.T) {
+	r := New()
+	_, err := r.RunString(`
+	var ref = new WeakRef({});
+	// the target is kept alive until the end of the current job
+	if (ref.deref() === undefined) {
+		throw new Error("target collected too early");
+	}
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		runtime.GC()
+		res, err := r.RunString(`ref.deref() === undefined`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.ToBoolean() {
+			return
+		}
+	}
+	t.Fatal("the target has not been collected")
+}
@@ -332,6 +332,8 @@ type scope struct {
 	argsInStash bool
 	// need 'arguments' object (functions only)
 	argsNeeded bool
+	// is an async generator (functions only)
+	asyncGenerator bool
 }
 
 type block struct {
@@ -343,6 +345,7 @@ type block struct {
 	outer      *block
 	breaking   *block // set when the 'finally' block is an empty break statement sequence
 	needResult bool
+	async      bool // set for 'for await' loops (blockLoopEnum only)
 }
 
 func (c *compiler) leaveScopeBlock(enter *enterBlock) {
@@ -1056,11 +1059,19 @@ func (c *compiler) compileModule(module *SourceTextModuleRecord) {
 }
 
 func (c *compiler) compileImportEntry(in importEntry) {
-	importedModule, err := c.hostResolveImportedModule(c.module, in.moduleRequest)
+	importedModule, err := c.hostResolveImportedModule(c.module, in.moduleRequest, in.attributes)
 	if err != nil {
 		panic(fmt.Errorf("previously resolved module returned error: %w", err))
 	}
-	if in.importName != "*" {
+	switch in.importName {
+	case "*":
+		// namespace imports can't fail
+	case "*source*":
+		if _, ok := importedModule.(ModuleSourceRecord); !ok {
+			c.compileSyntaxError(fmt.Sprintf("The requested module %q does not provide a source representation", in.moduleRequest), in.offset)
+			return
+		}
+	default:
 		resolution, ambiguous := importedModule.ResolveExport(in.importName)
 		if resolution == nil || ambiguous {
 			c.compileImportError(ambiguous, in.moduleRequest, in.importName, in.offset)
@@ -1099,7 +1110,7 @@ func (c *compiler) compileLocalExportEntry(entry exportEntry) {
 }
 
 func (c *compiler) compileIndirectExportEntry(entry exportEntry) {
-	otherModule, err := c.hostResolveImportedModule(c.module, entry.moduleRequest)
+	otherModule, err := c.hostResolveImportedModule(c.module, entry.moduleRequest, entry.attributes)
 	if err != nil {
 		panic(fmt.Errorf("previously resolved module returned error %w", err))
 	}
@@ -1209,6 +1220,9 @@ func (c *compiler) compile(in *ast.Program, strict, inGlobal bool, evalVm *vm) {
 	if !inGlobal || ownVarScope {
 		c.compileFunctions(funcs)
 	}
+	if decl, _ := findUsingDeclaration(in.Body); decl != nil {
+		c.throwSyntaxError(int(decl.Idx)-1, "Using declaration is not allowed at the top level of a script")
+	}
 	c.compileStatements(in.Body, true)
 	if enter != nil {
 		c.leaveScopeBlock(enter)
@@ -1446,7 +1460,7 @@ func (c *compiler) createLexicalBinding(target ast.Expression, isConst bool) {
 
 func (c *compiler) createLexicalBindings(lex *ast.LexicalDeclaration) {
 	for _, d := range lex.List {
-		c.createLexicalBinding(d.Target, lex.Token == token.CONST)
+		c.createLexicalBinding(d.Target, lex.Token != token.LET)
 	}
 }
 
@@ -1497,7 +1511,7 @@ func (c *compiler) compileLexicalDeclarations(list []ast.Statement, scopeDeclare
 func (c *compiler) compileLexicalDeclarationsFuncBody(list []ast.Statement, calleeBinding *binding) {
 	for _, st := range list {
 		if lex, ok := st.(*ast.LexicalDeclaration); ok {
-			isConst := lex.Token == token.CONST
+			isConst := lex.Token != token.LET
 			for _, d := range lex.List {
 				c.createBindings(d.Target, func(name unistring.String, offset int) {
 					c.createLexicalIdBindingFuncBody(name, isConst, offset, calleeBinding)
@@ -207,6 +207,7 @@ type compiledOptional struct {
 }
 type compiledDynamicImport struct {
 	baseCompiledExpr
+	phase ast.ImportPhase
 }
 
 func (c *compiler) compileExpression(v ast.Expression) compiledExpr {
@@ -1341,6 +1342,7 @@ func (e *compiledFunctionLiteral) compile() (prg *Program, name unistring.String
 	e.c.newScope()
 	s := e.c.scope
 	s.funcType = e.typ
+	s.asyncGenerator = e.isAsync && e.isGenerator
 
 	if e.name != nil {
 		name = e.name.Name
@@ -1723,7 +1725,11 @@ func (e *compiledFunctionLiteral) emitGetter(putOnStack bool) {
 		}
 	case funcMethod, funcClsInit:
 		if e.isAsync {
-			e.c.emit(&newAsyncMethod{newMethod: newMethod{newFunc: newFunc{prg: p, length: length, name: name, source: e.source, strict: strict}, homeObjOffset: e.homeObjOffset}})
+			if e.isGenerator {
+				e.c.emit(&newAsyncGeneratorMethod{newMethod: newMethod{newFunc: newFunc{prg: p, length: length, name: name, source: e.source, strict: strict}, homeObjOffset: e.homeObjOffset}})
+			} else {
+				e.c.emit(&newAsyncMethod{newMethod: newMethod{newFunc: newFunc{prg: p, length: length, name: name, source: e.source, strict: strict}, homeObjOffset: e.homeObjOffset}})
+			}
 		} else {
 			if e.isGenerator {
 				e.c.emit(&newGeneratorMethod{newMethod: newMethod{newFunc: newFunc{prg: p, length: length, name: name, source: e.source, strict: strict}, homeObjOffset: e.homeObjOffset}})
@@ -1733,7 +1739,11 @@ func (e *compiledFunctionLiteral) emitGetter(putOnStack bool) {
 		}
 	case funcRegular:
 		if e.isAsync {
-			e.c.emit(&newAsyncFunc{newFunc: newFunc{prg: p, length: length, name: name, source: e.source, strict: strict}})
+			if e.isGenerator {
+				e.c.emit(&newAsyncGeneratorFunc{newFunc: newFunc{prg: p, length: length, name: name, source: e.source, strict: strict}})
+			} else {
+				e.c.emit(&newAsyncFunc{newFunc: newFunc{prg: p, length: length, name: name, source: e.source, strict: strict}})
+			}
 		} else {
 			if e.isGenerator {
 				e.c.emit(&newGeneratorFunc{newFunc: newFunc{prg: p, length: length, name: name, source: e.source, strict: strict}})
@@ -1755,9 +1765,6 @@ func (c *compiler) compileFunctionLiteral(v *ast.FunctionLiteral, isExpr bool) *
 		c.checkIdentifierName(v.Name.Name, int(v.Name.Idx)-1)
 		c.checkIdentifierLName(v.Name.Name, int(v.Name.Idx)-1)
 	}
-	if v.Async && v.Generator {
-		c.throwSyntaxError(int(v.Function)-1, "Async generators are not supported yet")
-	}
 	r := &compiledFunctionLiteral{
 		name:            v.Name,
 		parameterList:   v.ParameterList,
@@ -3161,7 +3168,9 @@ func (c *compiler) compileCallee(v ast.Expression) compiledExpr {
 		panic("unreachable")
 	}
 	if imp, ok := v.(*ast.DynamicImportExpression); ok {
-		r := &compiledDynamicImport{}
+		r := &compiledDynamicImport{
+			phase: imp.Phase,
+		}
 		r.init(c, imp.Idx)
 		return r
 	}
@@ -3598,7 +3607,7 @@ func (e *compiledOptional) emitGetter(putOnStack bool) {
 
 func (e *compiledDynamicImport) emitGetter(putOnStack bool) {
 	if putOnStack {
-		e.c.emit(dynamicImport)
+		e.c.emit(loadDynamicImport(e.phase)) // the values of ast.ImportPhase mirror ImportPhase
 	}
 }
 
@@ -3616,6 +3625,12 @@ func (e *compiledYieldExpression) emitGetter(putOnStack bool) {
 	} else {
 		e.c.emit(loadUndef)
 	}
+	if !e.delegate {
+		if s := e.c.scope.nearestFunction(); s != nil && s.asyncGenerator {
+			// In async generators the operand of 'yield' is awaited before it's passed on.
+			e.c.emit(await)
+		}
+	}
 	if putOnStack {
 		if e.delegate {
 			e.c.emit(yieldDelegateRes)
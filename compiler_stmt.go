@@ -253,6 +253,16 @@ func (c *compiler) compileForHeadLexDecl(decl *ast.LexicalDeclaration, needResul
 }
 
 func (c *compiler) compileLabeledForStatement(v *ast.ForStatement, needResult bool, label unistring.String) {
+	if init, ok := v.Initializer.(*ast.ForLoopInitializerLexicalDecl); ok && init.LexicalDeclaration.Token == token.USING {
+		c.compileDisposableScope(init.LexicalDeclaration.Await, func() {
+			c.compileForLoop(v, needResult, label)
+		})
+		return
+	}
+	c.compileForLoop(v, needResult, label)
+}
+
+func (c *compiler) compileForLoop(v *ast.ForStatement, needResult bool, label unistring.String) {
 	loopBlock := &block{
 		typ:        blockLoop,
 		outer:      c.block,
@@ -389,6 +399,9 @@ func (c *compiler) compileForInto(into ast.ForInto, needResult bool) (enter *ent
 		case *ast.Identifier:
 			b := c.createLexicalIdBinding(target.Name, into.IsConst, int(into.Idx)-1)
 			c.emit(enumGet)
+			if into.IsUsing {
+				c.emit(addDisposableResource(into.IsAwait))
+			}
 			b.emitInitP()
 		case ast.Pattern:
 			c.createLexicalBinding(target, into.IsConst)
@@ -407,13 +420,15 @@ func (c *compiler) compileForInto(into ast.ForInto, needResult bool) (enter *ent
 	return
 }
 
-func (c *compiler) compileLabeledForInOfStatement(into ast.ForInto, source ast.Expression, body ast.Statement, iter, needResult bool, label unistring.String) {
+func (c *compiler) compileLabeledForInOfStatement(into ast.ForInto, source ast.Expression, body ast.Statement, iter, async, needResult bool, label unistring.String) {
 	c.block = &block{
 		typ:        blockLoopEnum,
 		outer:      c.block,
 		label:      label,
 		needResult: needResult,
+		async:      async,
 	}
+	loopBlock := c.block
 	enterPos := -1
 	if forDecl, ok := into.(*ast.ForDeclaration); ok {
 		c.block = &block{
@@ -452,7 +467,9 @@ func (c *compiler) compileLabeledForInOfStatement(into ast.ForInto, source ast.E
 		}
 		c.popScope()
 	}
-	if iter {
+	if async {
+		c.emit(iterateAsyncP)
+	} else if iter {
 		c.emit(iterateP)
 	} else {
 		c.emit(enumerate)
@@ -462,29 +479,45 @@ func (c *compiler) compileLabeledForInOfStatement(into ast.ForInto, source ast.E
 	}
 	start := len(c.p.code)
 	c.block.cont = start
+	if async {
+		c.emit(iterNextAsync, await)
+	}
+	next := len(c.p.code)
 	c.emit(nil)
-	enterIterBlock := c.compileForInto(into, needResult)
-	if needResult {
-		c.emit(clearResult)
+	compileIteration := func() {
+		enterIterBlock := c.compileForInto(into, needResult)
+		if needResult {
+			c.emit(clearResult)
+		}
+		c.compileStatement(body, needResult)
+		if enterIterBlock != nil {
+			c.leaveScopeBlock(enterIterBlock)
+			c.popScope()
+		}
 	}
-	c.compileStatement(body, needResult)
-	if enterIterBlock != nil {
-		c.leaveScopeBlock(enterIterBlock)
-		c.popScope()
+	if forDecl, ok := into.(*ast.ForDeclaration); ok && forDecl.IsUsing {
+		c.compileDisposableScope(forDecl.IsAwait, compileIteration)
+	} else {
+		compileIteration()
 	}
 	c.emit(jump(start - len(c.p.code)))
-	if iter {
-		c.p.code[start] = iterNext(len(c.p.code) - start)
+	if async {
+		c.p.code[next] = iterAsyncResult(len(c.p.code) - next)
+		c.emit(enumPop, jump(4))
 	} else {
-		c.p.code[start] = enumNext(len(c.p.code) - start)
+		if iter {
+			c.p.code[next] = iterNext(len(c.p.code) - next)
+		} else {
+			c.p.code[next] = enumNext(len(c.p.code) - next)
+		}
+		c.emit(enumPop, jump(2))
 	}
-	c.emit(enumPop, jump(2))
 	c.leaveBlock()
-	c.emit(enumPopClose)
+	c.emitIterClose(loopBlock)
 }
 
 func (c *compiler) compileLabeledForInStatement(v *ast.ForInStatement, needResult bool, label unistring.String) {
-	c.compileLabeledForInOfStatement(v.Into, v.Source, v.Body, false, needResult, label)
+	c.compileLabeledForInOfStatement(v.Into, v.Source, v.Body, false, false, needResult, label)
 }
 
 func (c *compiler) compileForOfStatement(v *ast.ForOfStatement, needResult bool) {
@@ -492,7 +525,7 @@ func (c *compiler) compileForOfStatement(v *ast.ForOfStatement, needResult bool)
 }
 
 func (c *compiler) compileLabeledForOfStatement(v *ast.ForOfStatement, needResult bool, label unistring.String) {
-	c.compileLabeledForInOfStatement(v.Into, v.Source, v.Body, true, needResult, label)
+	c.compileLabeledForInOfStatement(v.Into, v.Source, v.Body, true, v.Await, needResult, label)
 }
 
 func (c *compiler) compileWhileStatement(v *ast.WhileStatement, needResult bool) {
@@ -643,12 +676,22 @@ L:
 		case blockWith:
 			c.emit(leaveWith)
 		case blockLoopEnum:
-			c.emit(enumPopClose)
+			c.emitIterClose(b)
 		}
 	}
 	return block
 }
 
+// emitIterClose emits the code that closes the iterator of a for-in/of loop when it's exited prematurely.
+// For 'for await' loops the result of the iterator's return() method is awaited.
+func (c *compiler) emitIterClose(b *block) {
+	if b.async {
+		c.emit(iterAsyncClose(3), await, iterAsyncCloseResult)
+	} else {
+		c.emit(enumPopClose)
+	}
+}
+
 func (c *compiler) compileBreak(label *ast.Identifier, idx file.Idx) {
 	block := c.emitBlockExitCode(label, idx, true)
 	block.breaks = append(block.breaks, len(c.p.code))
@@ -736,6 +779,9 @@ func (c *compiler) compileReturnStatement(v *ast.ReturnStatement) {
 	}
 	if v.Argument != nil {
 		c.emitExpr(c.compileExpression(v.Argument), true)
+		if s := c.scope.nearestFunction(); s != nil && s.asyncGenerator {
+			c.emit(await)
+		}
 	} else {
 		c.emit(loadUndef)
 	}
@@ -744,7 +790,7 @@ func (c *compiler) compileReturnStatement(v *ast.ReturnStatement) {
 		case blockTry:
 			c.emit(saveResult, leaveTry{}, loadResult)
 		case blockLoopEnum:
-			c.emit(enumPopClose)
+			c.emitIterClose(b)
 		}
 	}
 	if s := c.scope.nearestFunction(); s != nil && s.funcType == funcDerivedCtor {
@@ -819,7 +865,7 @@ func (c *compiler) compileExportDeclaration(expr *ast.ExportDeclaration) {
 		})
 	case expr.ExportFromClause != nil:
 		from := expr.ExportFromClause
-		module, err := c.hostResolveImportedModule(c.module, expr.FromClause.ModuleSpecifier.String())
+		module, err := c.hostResolveImportedModule(c.module, expr.FromClause.ModuleSpecifier.String(), importAttributesFromAst(expr.Attributes))
 		if err != nil {
 			c.throwSyntaxError(int(expr.Idx0()), err.Error())
 		}
@@ -853,11 +899,42 @@ func (c *compiler) compileImportDeclaration(expr *ast.ImportDeclaration) {
 	if expr.FromClause == nil {
 		return // import "specifier";
 	}
-	module, err := c.hostResolveImportedModule(c.module, expr.FromClause.ModuleSpecifier.String())
+	module, err := c.hostResolveImportedModule(c.module, expr.FromClause.ModuleSpecifier.String(), importAttributesFromAst(expr.Attributes))
 	if err != nil {
 		c.throwSyntaxError(int(expr.Idx0()), err.Error())
 	}
 	if expr.ImportClause != nil {
+		switch expr.Phase {
+		case ast.ImportPhaseSource:
+			if _, ok := module.(ModuleSourceRecord); !ok {
+				return // already handled
+			}
+			idx := expr.Idx
+			c.emitLexicalAssign(
+				expr.ImportClause.ImportedDefaultBinding.Name,
+				int(idx),
+				c.compileEmitterExpr(func() {
+					c.emit(importSource{
+						module: module,
+					})
+				}, idx),
+			)
+			return
+		case ast.ImportPhaseDefer:
+			idx := expr.Idx
+			resolve := c.hostResolveImportedModule
+			c.emitLexicalAssign(
+				expr.ImportClause.NameSpaceImport.ImportedBinding,
+				int(idx),
+				c.compileEmitterExpr(func() {
+					c.emit(importDeferredNamespace{
+						module:  module,
+						resolve: resolve,
+					})
+				}, idx),
+			)
+			return
+		}
 		if namespace := expr.ImportClause.NameSpaceImport; namespace != nil {
 			idx := expr.Idx
 			c.emitLexicalAssign(
@@ -1023,11 +1100,66 @@ func (c *compiler) compileVariableStatement(v *ast.VariableStatement) {
 }
 
 func (c *compiler) compileLexicalDeclaration(v *ast.LexicalDeclaration) {
+	if v.Token == token.USING {
+		for _, e := range v.List {
+			c.compileUsingBinding(e, v.Await)
+		}
+		return
+	}
 	for _, e := range v.List {
 		c.compileLexicalBinding(e)
 	}
 }
 
+func (c *compiler) compileUsingBinding(expr *ast.Binding, async bool) {
+	target := expr.Target.(*ast.Identifier)
+	offset := int(target.Idx) - 1
+	b := c.scope.boundNames[target.Name]
+	c.assert(b != nil, offset, "Using declaration for an unbound name")
+	c.emitNamedOrConst(c.compileExpression(expr.Initializer), target.Name)
+	c.p.addSrcMap(offset)
+	c.emit(addDisposableResource(async))
+	b.emitInitP()
+}
+
+// findUsingDeclaration returns the first 'using' declaration in the list and whether the list contains
+// any 'await using' declarations.
+func findUsingDeclaration(list []ast.Statement) (first *ast.LexicalDeclaration, async bool) {
+	for _, st := range list {
+		if lex, ok := st.(*ast.LexicalDeclaration); ok && lex.Token == token.USING {
+			if first == nil {
+				first = lex
+			}
+			if lex.Await {
+				return first, true
+			}
+		}
+	}
+	return
+}
+
+// compileDisposableScope wraps the code emitted by body into a try-finally block which disposes the
+// resources added by 'using' declarations when the scope is exited (i.e. DisposeResources()).
+func (c *compiler) compileDisposableScope(async bool, body func()) {
+	c.block = &block{
+		typ:   blockTry,
+		outer: c.block,
+	}
+	lbl := len(c.p.code)
+	c.emit(nil)
+	body()
+	c.emit(enterFinally{})
+	finallyOffset := len(c.p.code) - lbl // finallyOffset should not include enterFinally
+	if async {
+		c.emit(disposeResourcesAsync(3), await, pop)
+	} else {
+		c.emit(disposeResources)
+	}
+	c.emit(leaveFinally{})
+	c.p.code[lbl] = try{finallyOffset: int32(finallyOffset)}
+	c.leaveBlock()
+}
+
 func (c *compiler) isEmptyResult(st ast.Statement) bool {
 	switch st := st.(type) {
 	case *ast.EmptyStatement, *ast.VariableStatement, *ast.LexicalDeclaration, *ast.FunctionDeclaration,
@@ -1095,6 +1227,16 @@ func (c *compiler) compileStatementsNeedResult(list []ast.Statement, lastProduci
 }
 
 func (c *compiler) compileStatements(list []ast.Statement, needResult bool) {
+	if decl, async := findUsingDeclaration(list); decl != nil {
+		c.compileDisposableScope(async, func() {
+			c.compileStatementList(list, needResult)
+		})
+		return
+	}
+	c.compileStatementList(list, needResult)
+}
+
+func (c *compiler) compileStatementList(list []ast.Statement, needResult bool) {
 	lastProducingIdx, blk := c.scanStatements(list)
 	if blk != nil {
 		needResult = blk.needResult
@@ -1232,6 +1374,33 @@ func (c *compiler) compileSwitchStatement(v *ast.SwitchStatement, needResult boo
 
 	c.compileFunctions(funcs)
 
+	var usingDecl *ast.LexicalDeclaration
+	var usingAsync bool
+	for _, s := range v.Body {
+		if decl, async := findUsingDeclaration(s.Consequent); decl != nil {
+			if usingDecl == nil {
+				usingDecl = decl
+			}
+			usingAsync = usingAsync || async
+		}
+	}
+	if usingDecl != nil {
+		c.compileDisposableScope(usingAsync, func() {
+			c.compileCaseBlock(v, db, needResult)
+		})
+	} else {
+		c.compileCaseBlock(v, db, needResult)
+	}
+
+	if enter != nil {
+		c.leaveScopeBlock(enter)
+		enter.stackSize--
+		c.popScope()
+	}
+	c.leaveBlock()
+}
+
+func (c *compiler) compileCaseBlock(v *ast.SwitchStatement, db *binding, needResult bool) {
 	if needResult {
 		c.emit(clearResult)
 	}
@@ -1275,18 +1444,12 @@ func (c *compiler) compileSwitchStatement(v *ast.SwitchStatement, needResult boo
 		if s.Test != nil || i != 0 {
 			c.p.code[jumps[i]] = jump(len(c.p.code) - jumps[i])
 		}
-		c.compileStatements(s.Consequent, needResult)
+		c.compileStatementList(s.Consequent, needResult)
 	}
 
 	if jumpNoMatch != -1 {
 		c.p.code[jumpNoMatch] = jump(len(c.p.code) - jumpNoMatch)
 	}
-	if enter != nil {
-		c.leaveScopeBlock(enter)
-		enter.stackSize--
-		c.popScope()
-	}
-	c.leaveBlock()
 }
 
 func (c *compiler) compileClassDeclaration(v *ast.ClassDeclaration) {
@@ -5916,6 +5916,249 @@ func TestGeneratorMethods(t *testing.T) {
 	testScriptWithTestLib(SCRIPT, _undefined, t)
 }
 
+func TestAsyncGeneratorFunc(t *testing.T) {
+	const SCRIPT = `
+	const trace = [];
+	async function* g() {
+		try {
+			yield 1;
+			yield await Promise.resolve(2);
+			trace.push(yield 3);
+			yield* [4, 5];
+			return yield* (async function*() { yield 6; return 7; })();
+		} finally {
+			trace.push("finally");
+		}
+	}
+	const iter = g();
+	assert.sameValue(Object.prototype.toString.call(iter), "[object AsyncGenerator]");
+	assert.sameValue(Object.getPrototypeOf(g), Object.getPrototypeOf(async function*() {}));
+
+	const results = [];
+	for (let i = 0; i < 8; i++) {
+		const res = await iter.next(i);
+		results.push(res.value, res.done);
+	}
+	assert(compareArray(results, [1, false, 2, false, 3, false, 4, false, 5, false, 6, false, 7, true, undefined, true]), results);
+	assert(compareArray(trace, [3, "finally"]), trace);
+
+	const iter1 = g();
+	const p1 = iter1.next(), p2 = iter1.return(42), p3 = iter1.next();
+	assert.sameValue((await p1).value, 1);
+	assert.sameValue((await p2).value, 42);
+	assert.sameValue((await p3).done, true);
+
+	const iter2 = g();
+	await iter2.next();
+	try {
+		await iter2.throw(new Error("boom"));
+		throw new Error("should not reach");
+	} catch (e) {
+		assert.sameValue(e.message, "boom");
+	}
+
+	const AsyncGeneratorFunction = Object.getPrototypeOf(g).constructor;
+	assert.sameValue(Object.getPrototypeOf(AsyncGeneratorFunction), Function);
+	const f = new AsyncGeneratorFunction("a", "yield a; yield a * 2;");
+	const values = [];
+	for await (const v of f(5)) {
+		values.push(v);
+	}
+	assert(compareArray(values, [5, 10]), values);
+	`
+	testAsyncFuncWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestForAwaitOf(t *testing.T) {
+	const SCRIPT = `
+	const trace = [];
+	function iterable(name) {
+		return {
+			[Symbol.asyncIterator]() {
+				let i = 0;
+				return {
+					next() { return Promise.resolve({value: i++, done: i > 5}); },
+					return() { trace.push("return " + name); return {done: true}; }
+				};
+			}
+		};
+	}
+	async function f() {
+		outer: for await (const a of iterable("outer")) {
+			for await (const b of iterable("inner")) {
+				if (b === 1) continue outer;
+				if (a === 2) return a;
+				trace.push(a + ":" + b);
+			}
+		}
+	}
+	assert.sameValue(await f(), 2);
+	assert(compareArray(trace, ["0:0", "return inner", "1:0", "return inner", "return inner", "return outer"]), trace);
+
+	const values = [];
+	for await (const v of [Promise.resolve(1), 2]) {
+		values.push(v);
+	}
+	assert(compareArray(values, [1, 2]), values);
+
+	try {
+		for await (const v of {[Symbol.asyncIterator]() { return {next() { return 1; }}; }}) {}
+		throw new Error("should not reach");
+	} catch (e) {
+		assert(e instanceof TypeError, e);
+	}
+
+	// a rejected next() result ends the loop without calling return()
+	let returnCalls = 0;
+	const rejecting = {
+		[Symbol.asyncIterator]() {
+			return {
+				next() { return Promise.reject("rejected"); },
+				return() { returnCalls++; return {}; },
+			};
+		},
+	};
+	try {
+		for await (const v of rejecting) {}
+		throw new Error("should not reach");
+	} catch (e) {
+		assert.sameValue(e, "rejected");
+	}
+	assert.sameValue(returnCalls, 0);
+
+	// a rejected value of a sync iterator closes it once
+	let syncReturnCalls = 0;
+	const syncIterable = {
+		[Symbol.iterator]() {
+			return {
+				next() { return {value: Promise.reject("sync rejected"), done: false}; },
+				return() { syncReturnCalls++; return {}; },
+			};
+		},
+	};
+	try {
+		for await (const v of syncIterable) {}
+		throw new Error("should not reach");
+	} catch (e) {
+		assert.sameValue(e, "sync rejected");
+	}
+	assert.sameValue(syncReturnCalls, 1);
+	`
+	testAsyncFuncWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestForAwaitOfSyntaxErrors(t *testing.T) {
+	for _, src := range []string{
+		"function f() { for await (const x of y); }",
+		"async function f() { for await (x in y); }",
+		"async function f() { for await (;;); }",
+	} {
+		_, err := Compile("", src, false)
+		if err == nil {
+			t.Fatalf("expected a syntax error for %q", src)
+		}
+	}
+}
+
+func TestUsingDeclaration(t *testing.T) {
+	const SCRIPT = `
+	const trace = [];
+	function res(name) {
+		return {[Symbol.dispose]() { trace.push(name); }};
+	}
+	{
+		using a = res("a"), b = res("b");
+		using c = null;
+		trace.push("body");
+	}
+	function f() {
+		using r = res("r");
+		return "ret";
+	}
+	trace.push(f());
+	for (using x of [res("x1"), res("x2")]) {
+		if (trace.length > 100) break;
+	}
+	switch (1) {
+	case 1:
+		using s = res("s");
+	case 2:
+		trace.push("case");
+	}
+	function* g() {
+		using y = res("y");
+		yield 1;
+		yield 2;
+	}
+	const it = g();
+	it.next();
+	it.return();
+	assert(compareArray(trace, ["body", "b", "a", "r", "ret", "x1", "x2", "case", "s", "y"]), trace);
+
+	try {
+		using d1 = {[Symbol.dispose]() { throw new Error("d1"); }};
+		using d2 = {[Symbol.dispose]() { throw new Error("d2"); }};
+		throw new Error("body");
+	} catch (e) {
+		assert(e instanceof SuppressedError, "SuppressedError");
+		assert.sameValue(e.error.message, "d1");
+		assert.sameValue(e.suppressed.error.message, "d2");
+		assert.sameValue(e.suppressed.suppressed.message, "body");
+	}
+
+	assert.throws(TypeError, () => { using x = {}; });
+	assert.throws(TypeError, () => { using x = 1; });
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestAwaitUsingDeclaration(t *testing.T) {
+	const SCRIPT = `
+	const trace = [];
+	function ares(name) {
+		return {async [Symbol.asyncDispose]() { await null; trace.push(name); }};
+	}
+	function res(name) {
+		return {[Symbol.dispose]() { trace.push(name); }};
+	}
+	{
+		await using a = ares("a"), b = res("b");
+		using c = res("c");
+		trace.push("body");
+	}
+	for await (await using x of [ares("x")]) {
+		trace.push("iter");
+	}
+	assert(compareArray(trace, ["body", "c", "b", "a", "iter", "x"]), trace);
+
+	try {
+		await using r = {[Symbol.asyncDispose]() { return Promise.reject(new Error("r")); }};
+		throw new Error("body");
+	} catch (e) {
+		assert(e instanceof SuppressedError, "SuppressedError");
+		assert.sameValue(e.error.message, "r");
+		assert.sameValue(e.suppressed.message, "body");
+	}
+	`
+	testAsyncFuncWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestUsingDeclarationSyntaxErrors(t *testing.T) {
+	for _, src := range []string{
+		"using x = null;",
+		"{ using x; }",
+		"{ using {x} = y; }",
+		"for (using x in y);",
+		"if (true) using x = null;",
+		"function f() { await using x = null; }",
+	} {
+		_, err := Compile("", src, false)
+		if err == nil {
+			t.Fatalf("expected a syntax error for %q", src)
+		}
+	}
+}
+
 func TestFunctionBodyClassDecl(t *testing.T) {
 	const SCRIPT = `
 	function as(requiredArgument = {}) {
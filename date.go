@@ -7,14 +7,11 @@ import (
 )
 
 const (
-	dateTimeLayout       = "Mon Jan 02 2006 15:04:05 GMT-0700 (MST)"
-	utcDateTimeLayout    = "Mon, 02 Jan 2006 15:04:05 GMT"
-	isoDateTimeLayout    = "2006-01-02T15:04:05.000Z"
-	dateLayout           = "Mon Jan 02 2006"
-	timeLayout           = "15:04:05 GMT-0700 (MST)"
-	datetimeLayout_en_GB = "01/02/2006, 15:04:05"
-	dateLayout_en_GB     = "01/02/2006"
-	timeLayout_en_GB     = "15:04:05"
+	dateTimeLayout    = "Mon Jan 02 2006 15:04:05 GMT-0700 (MST)"
+	utcDateTimeLayout = "Mon, 02 Jan 2006 15:04:05 GMT"
+	isoDateTimeLayout = "2006-01-02T15:04:05.000Z"
+	dateLayout        = "Mon Jan 02 2006"
+	timeLayout        = "15:04:05 GMT-0700 (MST)"
 
 	maxTime   = 8.64e15
 	timeUnset = math.MinInt64
@@ -25,7 +22,7 @@ type dateObject struct {
 	msec int64
 }
 
-func dateParse(date string) (t time.Time, ok bool) {
+func dateParse(date string, local *time.Location) (t time.Time, ok bool) {
 	d, ok := parseDateISOString(date)
 	if !ok {
 		d, ok = parseDateOtherString(date)
@@ -45,7 +42,7 @@ func dateParse(date string) (t time.Time, ok bool) {
 	}
 	var loc *time.Location
 	if d.isLocal {
-		loc = time.Local
+		loc = local
 	} else {
 		loc = time.FixedZone("", d.timeZoneOffset*60)
 	}
@@ -72,8 +69,8 @@ func (r *Runtime) newDateObject(t time.Time, isSet bool, proto *Object) *Object
 	return v
 }
 
-func dateFormat(t time.Time) string {
-	return t.Local().Format(dateTimeLayout)
+func dateFormat(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(dateTimeLayout)
 }
 
 func timeFromMsec(msec int64) time.Time {
@@ -116,7 +113,7 @@ func (d *dateObject) unset() {
 }
 
 func (d *dateObject) time() time.Time {
-	return timeFromMsec(d.msec)
+	return timeFromMsec(d.msec).In(d.val.runtime.location())
 }
 
 func (d *dateObject) timeUTC() time.Time {
@@ -130,6 +130,47 @@ func TestTimezoneOffset(t *testing.T) {
 	testScript(SCRIPT, intToValue(-60), t)
 }
 
+func TestDateSetTimeZone(t *testing.T) {
+	const SCRIPT = `
+	var d = new Date(Date.UTC(2024, 6, 1, 12, 30));
+	assert.sameValue(d.getTimezoneOffset(), -600, "getTimezoneOffset()");
+	assert.sameValue(d.getHours(), 22, "getHours()");
+	assert.sameValue(d.toString(), "Mon Jul 01 2024 22:30:00 GMT+1000 (AEST)", "toString()");
+	assert.sameValue(new Date("2024-07-01T00:00").getTime(), Date.UTC(2024, 5, 30, 14), "parse without offset");
+	assert.sameValue(new Date(2024, 0, 1).getTime(), Date.UTC(2023, 11, 31, 13), "new Date(y, m, d)");
+	d.setHours(1);
+	assert.sameValue(d.getTime(), Date.UTC(2024, 5, 30, 15, 30), "setHours()");
+	assert.sameValue(new Intl.DateTimeFormat().resolvedOptions().timeZone, "Australia/Sydney", "Intl default time zone");
+	assert.sameValue(Temporal.Now.timeZoneId(), "Australia/Sydney", "Temporal.Now.timeZoneId()");
+	`
+
+	loc, err := time.LoadLocation("Australia/Sydney")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := New()
+	vm.SetTimeZone(loc)
+	vm.testScriptWithTestLib(SCRIPT, _undefined, t)
+
+	res, err := vm.RunString(`new Date(0)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := res.Export().(time.Time); d.Location() != loc {
+		t.Fatalf("Invalid timezone: %v", d.Location())
+	}
+
+	other := New()
+	other.SetTimeZone(time.UTC)
+	v, err := other.RunString(`new Date(Date.UTC(2024, 6, 1, 12, 30)).getHours()`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := v.ToInteger(); h != 12 {
+		t.Fatalf("Unexpected hours in UTC: %d", h)
+	}
+}
+
 func TestDateValueOf(t *testing.T) {
 	const SCRIPT = `
 	var d9 = new Date(1.23e15);
@@ -0,0 +1,75 @@
+package sobek
+
+import (
+	"math"
+	"reflect"
+)
+
+// Float16 is an IEEE 754 binary16 (half-precision) floating point number, stored as raw bits. It is the
+// element type of Float16Array. Runtime.ToValue() converts it into a Number and Runtime.ExportTo() can
+// convert a Number into a Float16 (rounding to the nearest representable value) or a Float16Array into
+// a []Float16 (the returned slice shares memory with the array).
+type Float16 uint16
+
+var (
+	typeFloat16      = reflect.TypeOf(Float16(0))
+	typeFloat16Slice = reflect.TypeOf(([]Float16)(nil))
+)
+
+// NewFloat16 converts f into a Float16 using the round-to-nearest, ties-to-even rule.
+func NewFloat16(f float64) Float16 {
+	return Float16(float64ToFloat16Bits(f))
+}
+
+// Float64 returns the value of f as a float64. The conversion is exact.
+func (f Float16) Float64() float64 {
+	return float16BitsToFloat64(uint16(f))
+}
+
+func float64ToFloat16Bits(f float64) uint16 {
+	sign := uint16(math.Float64bits(f)>>48) & 0x8000
+	if math.IsNaN(f) {
+		return 0x7e00
+	}
+	a := math.Abs(f)
+	if a >= 65520 { // halfway between the largest finite value (65504) and 2^16, rounds to Infinity
+		return sign | 0x7c00
+	}
+	if a < 0x1p-14 {
+		// Subnormal (or zero). Scaling by a power of 2 is exact, so this is the only rounding.
+		return sign | uint16(math.RoundToEven(a*0x1p24))
+	}
+	frac, exp := math.Frexp(a) // a = frac * 2^exp, frac is in [0.5, 1)
+	mant := uint16(math.RoundToEven((frac*2 - 1) * 1024))
+	if mant == 1024 {
+		mant = 0
+		exp++
+	}
+	// exp-1 is the unbiased exponent, the bias is 15. The overflow case is handled above.
+	return sign | uint16(exp+14)<<10 | mant
+}
+
+func float16BitsToFloat64(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := h & 0x3ff
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(float64(mant), -24)
+	case 0x1f:
+		if mant != 0 {
+			return math.NaN()
+		}
+		f = math.Inf(1)
+	default:
+		f = math.Ldexp(float64(mant|0x400), exp-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
+
+func toFloat16(v Value) uint16 {
+	return float64ToFloat16Bits(v.ToFloat())
+}
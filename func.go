@@ -85,6 +85,10 @@ type asyncFuncObject struct {
 	baseJsFuncObject
 }
 
+type asyncGeneratorFuncObject struct {
+	baseJsFuncObject
+}
+
 type classFuncObject struct {
 	baseJsFuncObject
 	initFields   *Program
@@ -109,6 +113,10 @@ type asyncMethodFuncObject struct {
 	methodFuncObject
 }
 
+type asyncGeneratorMethodFuncObject struct {
+	methodFuncObject
+}
+
 type arrowFuncObject struct {
 	baseJsFuncObject
 	funcObj   *Object
@@ -145,6 +153,7 @@ const (
 	genStateSuspendedYield
 	genStateSuspendedYieldRes
 	genStateCompleted
+	genStateAwaitingReturn
 )
 
 type generatorObject struct {
@@ -154,6 +163,31 @@ type generatorObject struct {
 	state     generatorState
 }
 
+type completionType uint8
+
+const (
+	completionNormal completionType = iota
+	completionReturn
+	completionThrow
+)
+
+type asyncGeneratorRequest struct {
+	typ        completionType
+	value      Value
+	promiseCap *promiseCapability
+}
+
+type asyncGeneratorObject struct {
+	baseObject
+	gen       generator
+	queue     []asyncGeneratorRequest
+	delegated *iteratorRecord
+	state     generatorState
+
+	// set if the generator expects the resumption value on the stack
+	resumeRes bool
+}
+
 func (f *nativeFuncObject) source() String {
 	return newStringValue(fmt.Sprintf("function %s() { [native code] }", nilSafe(f.getStr("name", nil)).toString()))
 }
@@ -1147,3 +1181,346 @@ func (f *generatorMethodFuncObject) assertCallable() (func(FunctionCall) Value,
 func (f *generatorMethodFuncObject) export(*objectExportCtx) interface{} {
 	return f.Call
 }
+
+func (g *asyncGeneratorObject) init(vmCall func(*vm, int), nArgs int) {
+	g.baseObject.init()
+	vm := g.val.runtime.vm
+	g.gen.vm = vm
+
+	g.gen.enter()
+	vmCall(vm, nArgs)
+
+	_, _, ex := g.gen.step()
+
+	vm.popTryFrame()
+	if ex != nil {
+		panic(ex)
+	}
+
+	g.state = genStateSuspendedStart
+	vm.popCtx()
+}
+
+func (g *asyncGeneratorObject) enqueue(typ completionType, v Value, pcap *promiseCapability) {
+	switch typ {
+	case completionNormal:
+		if g.state == genStateCompleted {
+			pcap.resolve(g.val.runtime.createIterResultObject(_undefined, true))
+			return
+		}
+	case completionThrow:
+		if g.state == genStateSuspendedStart {
+			g.state = genStateCompleted
+		}
+		if g.state == genStateCompleted {
+			pcap.reject(v)
+			return
+		}
+	}
+	g.queue = append(g.queue, asyncGeneratorRequest{typ: typ, value: v, promiseCap: pcap})
+	switch g.state {
+	case genStateSuspendedStart, genStateCompleted:
+		if typ == completionReturn {
+			g.state = genStateAwaitingReturn
+			g.awaitReturn()
+			return
+		}
+		g.resumeNext()
+	case genStateSuspendedYield:
+		g.resumeNext()
+	}
+}
+
+// await suspends until v is settled, similarly to what the 'await' operator does inside the generator body.
+func (g *asyncGeneratorObject) resumeNext() {
+	req := g.queue[0]
+	g.state = genStateExecuting
+	if g.delegated != nil {
+		g.delegateStep(req.typ, req.value)
+		return
+	}
+	switch req.typ {
+	case completionNormal:
+		g.resumeBody(req.value)
+	case completionThrow:
+		g.throwInto(req.value)
+	case completionReturn:
+		g.val.runtime.await(req.value, g.doReturn, g.throwInto)
+	}
+}
+
+func (g *asyncGeneratorObject) resumeBody(v Value) {
+	if !g.resumeRes {
+		v = nil
+	}
+	g.step(g.gen.next(v))
+}
+
+func (g *asyncGeneratorObject) throwInto(v Value) {
+	g.step(g.gen.nextThrow(v))
+}
+
+func (g *asyncGeneratorObject) doReturn(v Value) {
+	g.gen.returning = v
+	g.gen.enterNext()
+	vm := g.gen.vm
+	if !g.gen.enterNextFinallyFrame() {
+		g.gen.returning = nil
+		vm.popTryFrame()
+		ex := vm.restoreStacks(g.gen.iterStackLen, g.gen.refStackLen)
+		vm.callStack = vm.callStack[:len(vm.callStack)-1]
+		vm.sp = vm.sb - 1
+		vm.popCtx()
+		g.step(v, resultNormal, ex)
+		return
+	}
+	res, resType, ex := g.gen.step()
+	vm.popTryFrame()
+	vm.popCtx()
+	g.step(res, resType, ex)
+}
+
+func (g *asyncGeneratorObject) step(res Value, resType resultType, ex *Exception) {
+	if ex != nil {
+		g.delegated = nil
+		g.state = genStateCompleted
+		g.completeStep(completionThrow, ex.val, true)
+		g.drainQueue()
+		return
+	}
+	switch resType {
+	case resultAwait:
+		g.val.runtime.await(res, func(v Value) {
+			g.step(g.gen.next(v))
+		}, g.throwInto)
+	case resultYield, resultYieldRes:
+		g.resumeRes = resType == resultYieldRes
+		g.yield(res)
+	case resultYieldDelegate, resultYieldDelegateRes:
+		g.resumeRes = resType == resultYieldDelegateRes
+		r := g.val.runtime
+		ex := r.vm.try(func() {
+			g.delegated = r.getAsyncIterator(res)
+		})
+		if ex != nil {
+			g.delegated = nil
+			g.throwInto(ex.val)
+			return
+		}
+		g.delegateStep(completionNormal, _undefined)
+	case resultNormal:
+		g.state = genStateCompleted
+		g.completeStep(completionNormal, res, true)
+		g.drainQueue()
+	default:
+		panic(g.val.runtime.NewTypeError("Runtime bug: unexpected result type: %v", resType))
+	}
+}
+
+func (g *asyncGeneratorObject) yield(v Value) {
+	g.completeStep(completionNormal, v, false)
+	if len(g.queue) > 0 {
+		g.resumeNext()
+	} else {
+		g.state = genStateSuspendedYield
+	}
+}
+
+func (g *asyncGeneratorObject) completeStep(typ completionType, v Value, done bool) {
+	req := g.queue[0]
+	g.queue[0] = asyncGeneratorRequest{}
+	g.queue = g.queue[1:]
+	if typ == completionThrow {
+		req.promiseCap.reject(v)
+	} else {
+		req.promiseCap.resolve(g.val.runtime.createIterResultObject(v, done))
+	}
+}
+
+func (g *asyncGeneratorObject) drainQueue() {
+	for len(g.queue) > 0 {
+		req := g.queue[0]
+		switch req.typ {
+		case completionReturn:
+			g.state = genStateAwaitingReturn
+			g.awaitReturn()
+			return
+		case completionThrow:
+			g.completeStep(completionThrow, req.value, true)
+		default:
+			g.completeStep(completionNormal, _undefined, true)
+		}
+	}
+}
+
+func (g *asyncGeneratorObject) awaitReturn() {
+	complete := func(typ completionType) func(Value) {
+		return func(v Value) {
+			g.state = genStateCompleted
+			g.completeStep(typ, v, true)
+			g.drainQueue()
+		}
+	}
+	g.val.runtime.await(g.queue[0].value, complete(completionNormal), complete(completionThrow))
+}
+
+func (g *asyncGeneratorObject) delegateThrow(v Value) {
+	g.delegated = nil
+	g.throwInto(v)
+}
+
+func (g *asyncGeneratorObject) delegateStep(typ completionType, received Value) {
+	r := g.val.runtime
+	iter := g.delegated
+	var method func(FunctionCall) Value
+	ex := r.vm.try(func() {
+		switch typ {
+		case completionNormal:
+			method = iter.next
+			if method == nil {
+				panic(r.NewTypeError("iterator.next is missing or not a function"))
+			}
+		case completionThrow:
+			method = toMethod(iter.iterator.self.getStr("throw", nil))
+		case completionReturn:
+			method = toMethod(iter.iterator.self.getStr("return", nil))
+		}
+	})
+	if ex != nil {
+		g.delegateThrow(ex.val)
+		return
+	}
+	if method == nil {
+		g.delegated = nil
+		if typ == completionReturn {
+			r.await(received, g.doReturn, g.throwInto)
+			return
+		}
+		g.closeDelegated(iter, func() {
+			g.throwInto(r.NewTypeError("The iterator does not provide a 'throw' method"))
+		})
+		return
+	}
+	var innerResult Value
+	ex = r.vm.try(func() {
+		innerResult = method(FunctionCall{This: iter.iterator, Arguments: []Value{received}})
+	})
+	if ex != nil {
+		g.delegateThrow(ex.val)
+		return
+	}
+	r.await(innerResult, func(res Value) {
+		obj, ok := res.(*Object)
+		if !ok {
+			g.delegateThrow(r.NewTypeError("Iterator result %s is not an object", res))
+			return
+		}
+		var done bool
+		var value Value
+		ex := r.vm.try(func() {
+			done = iteratorComplete(obj)
+			value = iteratorValue(obj)
+		})
+		if ex != nil {
+			g.delegateThrow(ex.val)
+			return
+		}
+		if !done {
+			g.yield(value)
+			return
+		}
+		g.delegated = nil
+		if typ == completionReturn {
+			g.doReturn(value)
+		} else {
+			g.resumeBody(value)
+		}
+	}, g.delegateThrow)
+}
+
+// closeDelegated calls the 'return' method of the delegated iterator (if any) and awaits its result before
+// calling then.
+func (g *asyncGeneratorObject) closeDelegated(iter *iteratorRecord, then func()) {
+	r := g.val.runtime
+	var res Value
+	ex := r.vm.try(func() {
+		if method := toMethod(iter.iterator.self.getStr("return", nil)); method != nil {
+			res = method(FunctionCall{This: iter.iterator})
+		}
+	})
+	if ex != nil {
+		g.throwInto(ex.val)
+		return
+	}
+	if res == nil {
+		then()
+		return
+	}
+	r.await(res, func(v Value) {
+		if _, ok := v.(*Object); !ok {
+			g.throwInto(r.NewTypeError("Iterator result %s is not an object", v))
+			return
+		}
+		then()
+	}, g.throwInto)
+}
+
+func (f *baseJsFuncObject) asyncGeneratorCall(vmCall func(*vm, int), nArgs int) Value {
+	o := &Object{runtime: f.val.runtime}
+
+	genObj := &asyncGeneratorObject{
+		baseObject: baseObject{
+			class:      classObject,
+			val:        o,
+			extensible: true,
+		},
+	}
+	o.self = genObj
+	genObj.init(vmCall, nArgs)
+	genObj.prototype = o.runtime.getPrototypeFromCtor(f.val, nil, o.runtime.getAsyncGeneratorPrototype())
+	return o
+}
+
+func (f *baseJsFuncObject) asyncGeneratorVmCall(vmCall func(*vm, int), nArgs int) {
+	vm := f.val.runtime.vm
+	vm.push(f.asyncGeneratorCall(vmCall, nArgs))
+	vm.pc++
+}
+
+func (f *asyncGeneratorFuncObject) vmCall(_ *vm, nArgs int) {
+	f.asyncGeneratorVmCall(f.baseJsFuncObject.vmCall, nArgs)
+}
+
+func (f *asyncGeneratorFuncObject) Call(call FunctionCall) Value {
+	f.prepareForVmCall(call)
+	return f.asyncGeneratorCall(f.baseJsFuncObject.vmCall, len(call.Arguments))
+}
+
+func (f *asyncGeneratorFuncObject) assertCallable() (func(FunctionCall) Value, bool) {
+	return f.Call, true
+}
+
+func (f *asyncGeneratorFuncObject) export(*objectExportCtx) interface{} {
+	return f.Call
+}
+
+func (f *asyncGeneratorFuncObject) assertConstructor() func(args []Value, newTarget *Object) *Object {
+	return nil
+}
+
+func (f *asyncGeneratorMethodFuncObject) vmCall(_ *vm, nArgs int) {
+	f.asyncGeneratorVmCall(f.methodFuncObject.vmCall, nArgs)
+}
+
+func (f *asyncGeneratorMethodFuncObject) Call(call FunctionCall) Value {
+	f.prepareForVmCall(call)
+	return f.asyncGeneratorCall(f.methodFuncObject.vmCall, len(call.Arguments))
+}
+
+func (f *asyncGeneratorMethodFuncObject) assertCallable() (func(FunctionCall) Value, bool) {
+	return f.Call, true
+}
+
+func (f *asyncGeneratorMethodFuncObject) export(*objectExportCtx) interface{} {
+	return f.Call
+}
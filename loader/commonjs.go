@@ -0,0 +1,385 @@
+package loader
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"github.com/grafana/sobek/ast"
+	"github.com/grafana/sobek/parser"
+	"github.com/grafana/sobek/token"
+)
+
+// commonJSModule is the module record of a CommonJS module. Its default export is module.exports and its named
+// exports are the properties of module.exports which are found by commonJSAnalysis.
+type commonJSModule struct {
+	*sobek.SyntheticModuleRecord
+	names []string
+}
+
+// Require loads the module with the given specifier, relatively to the root of the file system, and returns the
+// value require(specifier) would return in a CommonJS module:
+//
+//   - module.exports for CommonJS modules;
+//   - the parsed value for JSON files;
+//   - the namespace object for ES modules, which must not use top-level await.
+//
+// JavaScript errors, including the errors thrown while evaluating the module, are returned as *sobek.Exception.
+func (l *Loader) Require(rt *sobek.Runtime, specifier string) (sobek.Value, error) {
+	require, _ := sobek.AssertFunction(rt.ToValue(l.requireFunction(rt, "/")))
+	return require(sobek.Undefined(), rt.ToValue(specifier))
+}
+
+// loadJavaScript parses the source of a .js, .mjs or .cjs file. Files are ES modules or CommonJS modules depending
+// on their extension or on the "type" field of the nearest package.json. If neither says it, files are ES modules,
+// unless the Loader was created with WithCommonJSDetection, in which case only the files using import, export or
+// top-level await are ES modules.
+func (l *Loader) loadJavaScript(p, src string) (sobek.ModuleRecord, error) {
+	format := l.packageType(p)
+	switch path.Ext(p) {
+	case ".mjs":
+		format = "module"
+	case ".cjs":
+		format = "commonjs"
+	}
+	switch {
+	case format == "commonjs":
+		return l.loadCommonJS(p, src)
+	case format == "module" || !l.detectCommonJS:
+		return sobek.ParseModule(p, src, l.Resolve, l.parserOptions...)
+	}
+
+	opts := append(l.parserOptions[:len(l.parserOptions):len(l.parserOptions)], parser.IsModule)
+	body, err := sobek.Parse(p, src, opts...)
+	if err == nil && (len(body.ImportEntries) > 0 || len(body.ExportEntries) > 0 || body.HasTLA) {
+		return sobek.ModuleFromAST(body, l.Resolve)
+	}
+	m, cjsErr := l.loadCommonJS(p, src)
+	if cjsErr == nil {
+		return m, nil
+	}
+	if err == nil {
+		// for example a module which only uses import.meta
+		return sobek.ModuleFromAST(body, l.Resolve)
+	}
+	return nil, err
+}
+
+// packageType returns the "type" field of the package.json nearest to p.
+func (l *Loader) packageType(p string) string {
+	for dir := path.Dir(p); ; dir = path.Dir(dir) {
+		if pkg, err := l.readPackageJSON(dir); pkg != nil || err != nil {
+			if pkg == nil {
+				return ""
+			}
+			return pkg.Type
+		}
+		if dir == "/" {
+			return ""
+		}
+	}
+}
+
+// probeCommonJS finds the file p refers to in the way require does: p itself, then p with the .js, .cjs or .json
+// extension, then the entry point of the package in the directory p, then its index.js or index.json.
+// It returns p if there is no such file.
+func (l *Loader) probeCommonJS(p string) string {
+	if l.isFile(p) {
+		return p
+	}
+	for _, ext := range []string{".js", ".cjs", ".json"} {
+		if l.isFile(p + ext) {
+			return p + ext
+		}
+	}
+	if info, err := fs.Stat(l.fsys, fsPath(p)); err != nil || !info.IsDir() {
+		return p
+	}
+	if entry, err := l.packageEntry(p, true); err == nil && entry != p && entry != path.Join(p, "index.js") {
+		if e := l.probeCommonJS(entry); l.isFile(e) {
+			return e
+		}
+	}
+	for _, index := range []string{"index.js", "index.json"} {
+		if e := path.Join(p, index); l.isFile(e) {
+			return e
+		}
+	}
+	return p
+}
+
+func (l *Loader) isFile(p string) bool {
+	info, err := fs.Stat(l.fsys, fsPath(p))
+	return err == nil && !info.IsDir()
+}
+
+// loadCommonJS wraps the source in a function providing exports, require, module, __filename and __dirname.
+// The function is run once per Runtime, when the module is first required or imported.
+// It must be called with l.mu held.
+func (l *Loader) loadCommonJS(p, src string) (sobek.ModuleRecord, error) {
+	if strings.HasPrefix(src, "#!") {
+		src = "//" + src[2:]
+	}
+	body, err := sobek.Parse(p, "(function (exports, require, module, __filename, __dirname) {"+src+"\n})", l.parserOptions...)
+	if err != nil {
+		return nil, err
+	}
+	var analysis commonJSAnalysis
+	analysis.statements(body.Body[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral).Body.List)
+	for _, specifier := range analysis.reexports {
+		// Only CommonJS modules can be re-exported, as the exported names of an ES module are resolved using l.Resolve.
+		if m, err := l.resolve(specifier, p, "", true); err == nil {
+			if m, ok := m.(*commonJSModule); ok {
+				analysis.names = append(analysis.names, m.names...)
+			}
+		}
+	}
+	prg, err := sobek.CompileAST(body, false)
+	if err != nil {
+		return nil, err
+	}
+
+	exportNames := []string{"default"}
+	seen := map[string]bool{"default": true}
+	var names []string
+	for _, name := range analysis.names {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	exportNames = append(exportNames, names...)
+	return &commonJSModule{
+		SyntheticModuleRecord: sobek.NewSyntheticModule(exportNames, func(mi *sobek.SyntheticModuleInstance) error {
+			return l.runCommonJS(mi, prg, p, names)
+		}),
+		names: names,
+	}, nil
+}
+
+func (l *Loader) runCommonJS(mi *sobek.SyntheticModuleInstance, prg *sobek.Program, p string, names []string) error {
+	rt := mi.Runtime()
+	exports := rt.NewObject()
+	module := rt.NewObject()
+	_ = module.Set("id", p)
+	_ = module.Set("filename", p)
+	_ = module.Set("loaded", false)
+	_ = module.Set("exports", exports)
+	_ = mi.SetExport("default", exports)
+
+	wrapper, err := rt.RunProgram(prg)
+	if err != nil {
+		return err
+	}
+	fn, _ := sobek.AssertFunction(wrapper)
+	_, err = fn(exports, exports, rt.ToValue(l.requireFunction(rt, p)), module, rt.ToValue(p), rt.ToValue(path.Dir(p)))
+	if err != nil {
+		return err
+	}
+
+	result := module.Get("exports")
+	_ = mi.SetExport("default", result)
+	if o, ok := result.(*sobek.Object); ok {
+		for _, name := range names {
+			if v := o.Get(name); v != nil {
+				_ = mi.SetExport(name, v)
+			}
+		}
+	}
+	_ = module.Set("loaded", true)
+	return nil
+}
+
+// requireFunction returns the require function of the module at referrer.
+func (l *Loader) requireFunction(rt *sobek.Runtime, referrer string) func(sobek.FunctionCall) sobek.Value {
+	return func(call sobek.FunctionCall) sobek.Value {
+		specifier := call.Argument(0).String()
+		l.mu.Lock()
+		m, err := l.resolve(specifier, referrer, "", true)
+		l.mu.Unlock()
+		if err == nil {
+			err = l.link(m)
+		}
+		if err != nil {
+			panic(jsError(rt, err))
+		}
+
+		promise := m.Evaluate(rt)
+		switch promise.State() {
+		case sobek.PromiseStateRejected:
+			if ex, ok := promise.Result().Export().(*sobek.Exception); ok {
+				panic(ex)
+			}
+			panic(promise.Result())
+		case sobek.PromiseStatePending:
+			if l.usesTLA(m, make(map[sobek.ModuleRecord]bool)) {
+				panic(rt.NewTypeError(fmt.Sprintf("require() of %q is not supported as it uses top-level await", specifier)))
+			}
+			panic(rt.NewTypeError(fmt.Sprintf("require() of %q is not supported as it is still being evaluated, "+
+				"which happens when it is part of an import cycle", specifier)))
+		}
+		switch m.(type) {
+		case *commonJSModule, *sobek.JSONModuleRecord:
+			return promise.Result().Export().(sobek.ModuleInstance).GetBindingValue("default")
+		}
+		return rt.NamespaceObjectFor(m)
+	}
+}
+
+// usesTLA returns whether m or one of the modules it imports uses top-level await.
+func (l *Loader) usesTLA(m sobek.ModuleRecord, seen map[sobek.ModuleRecord]bool) bool {
+	if seen[m] {
+		return false
+	}
+	seen[m] = true
+	c, ok := m.(sobek.CyclicModuleRecord)
+	if !ok {
+		return false
+	}
+	if t, ok := m.(interface{ HasTLA() bool }); ok && t.HasTLA() {
+		return true
+	}
+	for _, required := range c.RequestedModules() {
+		if required.Phase == sobek.SourcePhase {
+			continue
+		}
+		if r, err := l.Resolve(m, required.Specifier, required.Attributes); err == nil && l.usesTLA(r, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// commonJSAnalysis finds the names exported by a CommonJS module by looking for the patterns below, like Node.js does:
+//
+//	exports.a = ...
+//	module.exports.a = ...
+//	Object.defineProperty(exports, "a", ...)
+//	module.exports = { a, b: ... }
+//	module.exports = require("./other.js")
+//	__exportStar(require("./other.js"), exports)
+//
+// The specifiers of the re-exported modules are collected in reexports.
+type commonJSAnalysis struct {
+	names     []string
+	reexports []string
+}
+
+func (a *commonJSAnalysis) statements(list []ast.Statement) {
+	for _, stmt := range list {
+		a.statement(stmt)
+	}
+}
+
+func (a *commonJSAnalysis) statement(stmt ast.Statement) {
+	switch stmt := stmt.(type) {
+	case *ast.ExpressionStatement:
+		a.expression(stmt.Expression)
+	case *ast.BlockStatement:
+		a.statements(stmt.List)
+	case *ast.IfStatement:
+		a.statement(stmt.Consequent)
+		if stmt.Alternate != nil {
+			a.statement(stmt.Alternate)
+		}
+	}
+}
+
+func (a *commonJSAnalysis) expression(expr ast.Expression) {
+	switch expr := expr.(type) {
+	case *ast.SequenceExpression:
+		for _, e := range expr.Sequence {
+			a.expression(e)
+		}
+	case *ast.AssignExpression:
+		if expr.Operator == token.ASSIGN {
+			if name, ok := exportsMember(expr.Left); ok {
+				a.names = append(a.names, name)
+			} else if isModuleExports(expr.Left) {
+				a.moduleExports(expr.Right)
+			}
+		}
+		a.expression(expr.Right)
+	case *ast.CallExpression:
+		args := expr.ArgumentList
+		switch callee := expr.Callee.(type) {
+		case *ast.DotExpression:
+			if len(args) >= 2 && isIdentifier(callee.Left, "Object") && callee.Identifier.Name == "defineProperty" && isExports(args[0]) {
+				if name, ok := args[1].(*ast.StringLiteral); ok {
+					a.names = append(a.names, name.Value.String())
+				}
+			}
+		case *ast.Identifier:
+			if len(args) >= 1 && (callee.Name == "__exportStar" || callee.Name == "__export") {
+				if specifier, ok := requireCall(args[0]); ok {
+					a.reexports = append(a.reexports, specifier)
+				}
+			}
+		}
+	}
+}
+
+// moduleExports handles the value assigned to module.exports.
+func (a *commonJSAnalysis) moduleExports(expr ast.Expression) {
+	switch expr := expr.(type) {
+	case *ast.ObjectLiteral:
+		for _, prop := range expr.Value {
+			switch prop := prop.(type) {
+			case *ast.PropertyShort:
+				a.names = append(a.names, prop.Name.Name.String())
+			case *ast.PropertyKeyed:
+				if key, ok := prop.Key.(*ast.StringLiteral); ok && !prop.Computed && prop.Kind == ast.PropertyKindValue {
+					a.names = append(a.names, key.Value.String())
+				}
+			}
+		}
+	default:
+		if specifier, ok := requireCall(expr); ok {
+			a.reexports = append(a.reexports, specifier)
+		}
+	}
+}
+
+// exportsMember returns the name of the property if expr is exports.name, exports["name"] or the same on module.exports.
+func exportsMember(expr ast.Expression) (string, bool) {
+	switch expr := expr.(type) {
+	case *ast.DotExpression:
+		if isExports(expr.Left) {
+			return expr.Identifier.Name.String(), true
+		}
+	case *ast.BracketExpression:
+		if name, ok := expr.Member.(*ast.StringLiteral); ok && isExports(expr.Left) {
+			return name.Value.String(), true
+		}
+	}
+	return "", false
+}
+
+func isExports(expr ast.Expression) bool {
+	return isIdentifier(expr, "exports") || isModuleExports(expr)
+}
+
+func isModuleExports(expr ast.Expression) bool {
+	dot, ok := expr.(*ast.DotExpression)
+	return ok && isIdentifier(dot.Left, "module") && dot.Identifier.Name == "exports"
+}
+
+func isIdentifier(expr ast.Expression, name string) bool {
+	id, ok := expr.(*ast.Identifier)
+	return ok && id.Name.String() == name
+}
+
+// requireCall returns the specifier if expr is require("specifier").
+func requireCall(expr ast.Expression) (string, bool) {
+	call, ok := expr.(*ast.CallExpression)
+	if !ok || len(call.ArgumentList) != 1 || !isIdentifier(call.Callee, "require") {
+		return "", false
+	}
+	specifier, ok := call.ArgumentList[0].(*ast.StringLiteral)
+	if !ok {
+		return "", false
+	}
+	return specifier.Value.String(), true
+}
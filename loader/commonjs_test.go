@@ -0,0 +1,178 @@
+package loader
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/grafana/sobek"
+)
+
+func commonJSTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"main.mjs": {Data: []byte(`
+			import lib, { add, name, answer, viaDefine, reexported } from "./lib.js";
+			import * as ns from "./lib.js";
+			import { a } from "./cycle/a.js";
+			globalThis.result = [lib.name, add(1, 2), name, answer, viaDefine, reexported, ns.default === lib, a].join();
+		`)},
+		"lib.js": {Data: []byte(`#!/usr/bin/env node
+			globalThis.evaluations = (globalThis.evaluations || 0) + 1;
+			exports.add = function (x, y) { return x + y; };
+			exports.name = exports.unset = void 0;
+			exports.name = "lib";
+			module.exports.answer = 42;
+			Object.defineProperty(exports, "viaDefine", { enumerable: true, get() { return "defined"; } });
+			__exportStar(require("./reexported"), exports);
+			function __exportStar(m, e) { for (const k in m) e[k] = m[k]; }
+		`)},
+		"reexported.js": {Data: []byte(`module.exports = { reexported: "reexported", other: 1 };`)},
+		"cycle/a.js": {Data: []byte(`
+			exports.early = "early";
+			const b = require("./b");
+			exports.a = "a:" + b.seen;
+		`)},
+		"cycle/b.js": {Data: []byte(`
+			const a = require("./a.js");
+			exports.seen = a.early + "," + a.a;
+		`)},
+
+		"require.js": {Data: []byte(`
+			const data = require("./data.json");
+			const esm = require("./esm.mjs");
+			const pkg = require("pkg");
+			const dir = require("./dir");
+			module.exports = [data.name, esm.default, esm.named, pkg, dir, __filename, __dirname, module.loaded].join();
+		`)},
+		"data.json":    {Data: []byte(`{"name": "data"}`)},
+		"esm.mjs":      {Data: []byte(`export default "esm"; export const named = "named";`)},
+		"dir/index.js": {Data: []byte(`module.exports = "dir";`)},
+
+		"node_modules/pkg/package.json": {Data: []byte(`{"exports": {"import": "./esm.js", "require": "./cjs"}}`)},
+		"node_modules/pkg/cjs.js":       {Data: []byte(`module.exports = "pkg";`)},
+		"node_modules/pkg/esm.js":       {Data: []byte(`export default "pkg esm";`)},
+
+		"typed/package.json": {Data: []byte(`{"type": "commonjs"}`)},
+		"typed/esm.js":       {Data: []byte(`export default 1;`)},
+		"throws.js":          {Data: []byte(`globalThis.thrown = (globalThis.thrown || 0) + 1; throw new RangeError("boom");`)},
+		"missing.js":         {Data: []byte(`require("./nope");`)},
+		"tla.mjs":            {Data: []byte(`await 1;`)},
+		"requireTLA.js":      {Data: []byte(`require("./tla.mjs");`)},
+		"tlaDep.mjs":         {Data: []byte(`import "./tla.mjs";`)},
+		"requireTLADep.js":   {Data: []byte(`require("./tlaDep.mjs");`)},
+		"esmCycle.mjs":       {Data: []byte(`import "./cjsCycle.cjs";`)},
+		"cjsCycle.cjs":       {Data: []byte(`require("./esmCycle.mjs");`)},
+	}
+}
+
+func TestCommonJS(t *testing.T) {
+	t.Parallel()
+	l := New(commonJSTestFS(), WithCommonJSDetection())
+
+	for i := 0; i < 2; i++ {
+		vm := sobek.New()
+		promise, err := l.Evaluate(vm, "/main.mjs")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if promise.State() != sobek.PromiseStateFulfilled {
+			t.Fatalf("got %v", promise.Result())
+		}
+		if res := vm.Get("result").String(); res != "lib,3,lib,42,defined,reexported,true,a:early,undefined" {
+			t.Fatalf("unexpected result %q", res)
+		}
+
+		v, err := l.Require(vm, "./lib")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name := v.ToObject(vm).Get("name").String(); name != "lib" {
+			t.Fatalf("unexpected name %q", name)
+		}
+		if n := vm.Get("evaluations").ToInteger(); n != 1 {
+			t.Fatalf("module evaluated %d times", n)
+		}
+
+		v, err = l.Require(vm, "/require.js")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res := v.String(); res != "data,esm,named,pkg,dir,/require.js,/,false" {
+			t.Fatalf("unexpected result %q", res)
+		}
+	}
+}
+
+func TestCommonJSErrors(t *testing.T) {
+	t.Parallel()
+	l := New(commonJSTestFS(), WithCommonJSDetection())
+
+	for specifier, expected := range map[string]string{
+		"/typed/esm.js":     "SyntaxError",
+		"/throws.js":        "RangeError: boom",
+		"/missing.js":       "TypeError",
+		"/requireTLA.js":    "uses top-level await",
+		"/requireTLADep.js": "uses top-level await",
+		"/esmCycle.mjs":     "is still being evaluated",
+		"/nope/nothing.js":  "nothing.js",
+	} {
+		vm := sobek.New()
+		_, err := l.Require(vm, specifier)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("%s: expected error %q, got %v", specifier, expected, err)
+		}
+		if _, ok := err.(*sobek.Exception); !ok {
+			t.Fatalf("%s: expected a *sobek.Exception, got %T", specifier, err)
+		}
+	}
+
+	vm := sobek.New()
+	for i := 0; i < 2; i++ {
+		if _, err := l.Require(vm, "/throws.js"); err == nil || !strings.Contains(err.Error(), "RangeError: boom") {
+			t.Fatalf("expected error, got %v", err)
+		}
+	}
+	if n := vm.Get("thrown").ToInteger(); n != 1 {
+		t.Fatalf("module evaluated %d times", n)
+	}
+}
+
+func TestCommonJSDetection(t *testing.T) {
+	t.Parallel()
+	fsys := fstest.MapFS{
+		"main.js": {Data: []byte(`import "./side.js";`)},
+		"side.js": {Data: []byte(`globalThis.thisType = typeof this; leaked = 1;`)},
+		"cjs.cjs": {Data: []byte(`leaked = 1; module.exports = typeof this;`)},
+	}
+
+	vm := sobek.New()
+	promise, err := New(fsys).Evaluate(vm, "/main.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if promise.State() != sobek.PromiseStateRejected || !strings.Contains(promise.Result().String(), "ReferenceError: leaked is not defined") {
+		t.Fatalf("expected side.js to be a strict mode ES module, got %v", promise.Result())
+	}
+	if res := vm.Get("thisType").String(); res != "undefined" {
+		t.Fatalf("unexpected this %q", res)
+	}
+	v, err := New(fsys).Require(vm, "./cjs.cjs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.String() != "object" || vm.Get("leaked") == nil {
+		t.Fatalf("expected cjs.cjs to be a CommonJS module, got %v", v)
+	}
+
+	vm = sobek.New()
+	promise, err = New(fsys, WithCommonJSDetection()).Evaluate(vm, "/main.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if promise.State() != sobek.PromiseStateFulfilled {
+		t.Fatalf("got %v", promise.Result())
+	}
+	if res := vm.Get("thisType").String(); res != "object" || vm.Get("leaked") == nil {
+		t.Fatalf("expected side.js to be a CommonJS module, this is %q", res)
+	}
+}
@@ -0,0 +1,163 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ImportMap is an import map as described in https://html.spec.whatwg.org/multipage/webappapis.html#import-maps.
+// Addresses and scope prefixes are resolved relatively to the root of the file system. A null (or empty) address
+// blocks the specifier from being resolved.
+type ImportMap struct {
+	Imports map[string]string            `json:"imports,omitempty"`
+	Scopes  map[string]map[string]string `json:"scopes,omitempty"`
+}
+
+// ParseImportMap parses the JSON representation of an import map.
+func ParseImportMap(data []byte) (*ImportMap, error) {
+	var m ImportMap
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid import map: %w", err)
+	}
+	return &m, nil
+}
+
+type specifierMapEntry struct {
+	key     string
+	address string // empty if blocked
+}
+
+// specifierMap is sorted by key in descending order so that longer prefixes are matched first.
+type specifierMap []specifierMapEntry
+
+type scopeEntry struct {
+	prefix  string
+	imports specifierMap
+}
+
+type normalizedImportMap struct {
+	imports specifierMap
+	scopes  []scopeEntry
+}
+
+func normalizeImportMap(m *ImportMap) *normalizedImportMap {
+	nm := &normalizedImportMap{
+		imports: normalizeSpecifierMap(m.Imports),
+	}
+	for prefix, imports := range m.Scopes {
+		scopePrefix := resolveURLLike(prefix, "/")
+		if scopePrefix == "" {
+			scopePrefix = joinPath("/", prefix)
+		}
+		nm.scopes = append(nm.scopes, scopeEntry{
+			prefix:  scopePrefix,
+			imports: normalizeSpecifierMap(imports),
+		})
+	}
+	sort.Slice(nm.scopes, func(i, j int) bool {
+		return nm.scopes[i].prefix > nm.scopes[j].prefix
+	})
+	return nm
+}
+
+func normalizeSpecifierMap(m map[string]string) specifierMap {
+	result := make(specifierMap, 0, len(m))
+	for key, value := range m {
+		if key == "" {
+			continue
+		}
+		if url := resolveURLLike(key, "/"); url != "" {
+			key = url
+		}
+		address := resolveURLLike(value, "/")
+		if strings.HasSuffix(key, "/") && !strings.HasSuffix(address, "/") {
+			address = ""
+		}
+		result = append(result, specifierMapEntry{key: key, address: address})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].key > result[j].key
+	})
+	return result
+}
+
+// resolve resolves the normalized specifier using the import map. It returns false if there is no matching entry.
+func (m *normalizedImportMap) resolve(specifier, referrer string) (string, bool, error) {
+	for _, scope := range m.scopes {
+		if scope.prefix == referrer || strings.HasSuffix(scope.prefix, "/") && strings.HasPrefix(referrer, scope.prefix) {
+			if resolved, ok, err := scope.imports.resolve(specifier); ok || err != nil {
+				return resolved, ok, err
+			}
+		}
+	}
+	return m.imports.resolve(specifier)
+}
+
+func (m specifierMap) resolve(specifier string) (string, bool, error) {
+	for _, entry := range m {
+		if entry.key == specifier {
+			if entry.address == "" {
+				return "", false, fmt.Errorf("resolution of %q was blocked by the import map", specifier)
+			}
+			return entry.address, true, nil
+		}
+		if strings.HasSuffix(entry.key, "/") && strings.HasPrefix(specifier, entry.key) {
+			if entry.address == "" {
+				return "", false, fmt.Errorf("resolution of %q was blocked by the import map", specifier)
+			}
+			afterPrefix := specifier[len(entry.key):]
+			var resolved string
+			if hasScheme(entry.address) {
+				resolved = entry.address + afterPrefix
+			} else {
+				resolved = joinPath(entry.address, afterPrefix)
+			}
+			if !strings.HasPrefix(resolved, entry.address) {
+				return "", false, fmt.Errorf("resolution of %q was blocked as it backtracks above its prefix %q", specifier, entry.key)
+			}
+			return resolved, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// resolveURLLike resolves specifiers which are absolute paths, paths relative to referrer (starting with ./ or ../)
+// or URLs with a scheme. It returns an empty string for bare specifiers.
+func resolveURLLike(specifier, referrer string) string {
+	switch {
+	case strings.HasPrefix(specifier, "/"):
+		return joinPath("/", specifier)
+	case strings.HasPrefix(specifier, "./"), strings.HasPrefix(specifier, "../"):
+		return joinPath(path.Dir(referrer), specifier)
+	case hasScheme(specifier):
+		return specifier
+	}
+	return ""
+}
+
+// joinPath is like path.Join, but it keeps a trailing slash, which is significant in import maps.
+func joinPath(elem ...string) string {
+	p := path.Join(elem...)
+	if strings.HasSuffix(elem[len(elem)-1], "/") && p != "/" {
+		p += "/"
+	}
+	return p
+}
+
+// hasScheme reports whether the specifier starts with a URL scheme such as "https:" or "node:".
+func hasScheme(specifier string) bool {
+	for i, c := range specifier {
+		switch {
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		case i > 0 && (c >= '0' && c <= '9' || c == '+' || c == '-' || c == '.'):
+		case c == ':':
+			return i > 0
+		default:
+			return false
+		}
+	}
+	return false
+}
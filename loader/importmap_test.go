@@ -0,0 +1,63 @@
+package loader
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImportMap(t *testing.T) {
+	t.Parallel()
+	m, err := ParseImportMap([]byte(`{
+		"imports": {
+			"a": "/lib/a.js",
+			"pkg/": "./vendor/pkg/",
+			"pkg/special.js": "/special.js",
+			"/old/": "/new/",
+			"blocked": null,
+			"bad/": "/no-slash",
+			"https://cdn.example.com/x.js": "/local/x.js"
+		},
+		"scopes": {
+			"/scoped/": {"a": "/scoped/a.js"},
+			"/scoped/deeper/": {"a": "/deeper/a.js"}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := New(nil, WithImportMap(m))
+
+	for _, test := range []struct {
+		specifier, referrer, expected string
+	}{
+		{"a", "/main.js", "/lib/a.js"},
+		{"a", "/scoped/main.js", "/scoped/a.js"},
+		{"a", "/scoped/deeper/main.js", "/deeper/a.js"},
+		{"pkg/x/y.js", "/main.js", "/vendor/pkg/x/y.js"},
+		{"pkg/special.js", "/main.js", "/special.js"},
+		{"./old/x.js", "/main.js", "/new/x.js"},
+		{"../old/x.js", "/dir/main.js", "/new/x.js"},
+		{"./x.js", "/dir/main.js", "/dir/x.js"},
+		{"https://cdn.example.com/x.js", "/main.js", "/local/x.js"},
+		{"host:fs", "/main.js", "host:fs"},
+	} {
+		resolved, err := l.resolveSpecifier(test.specifier, test.referrer, false)
+		if err != nil {
+			t.Fatalf("%s from %s: %v", test.specifier, test.referrer, err)
+		}
+		if resolved != test.expected {
+			t.Fatalf("%s from %s: expected %q, got %q", test.specifier, test.referrer, test.expected, resolved)
+		}
+	}
+
+	for specifier, expected := range map[string]string{
+		"blocked":        "blocked by the import map",
+		"bad/x.js":       "blocked by the import map",
+		"pkg/../../x.js": "backtracks above its prefix",
+	} {
+		_, err := l.resolveSpecifier(specifier, "/main.js", false)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("%s: expected error %q, got %v", specifier, expected, err)
+		}
+	}
+}
@@ -0,0 +1,461 @@
+/*
+Package loader provides a reference implementation of module loading on top of sobek.ParseModule.
+
+Modules are loaded from an fs.FS. Specifiers are resolved the way browsers and Node.js do it:
+
+  - absolute ("/lib/a.js") and relative ("./a.js", "../a.js") specifiers are resolved relatively to the
+    importing module, the root of the file system corresponds to "/";
+  - specifiers are then mapped through the import map, if any;
+  - bare specifiers ("lodash", "@scope/pkg/sub.js") are looked up in the node_modules directories of the
+    importing module and of all its parents. If the package.json of the package has an "exports" field, the
+    package and its subpaths resolve to the files listed there, including "*" patterns, and other subpaths
+    can't be imported;
+  - modules registered with Loader.Register (for example sobek.SyntheticModuleRecord) are found by their
+    exact specifier, such as "host:fs".
+
+Usage:
+
+	l := loader.New(os.DirFS("scripts"))
+	vm := sobek.New()
+	promise, err := l.Evaluate(vm, "/main.js")
+
+CommonJS modules are supported as well: .cjs files and files in a package whose package.json has "type": "commonjs"
+are wrapped in a function scope providing require, module and exports. Other files are ES modules, unless the
+WithCommonJSDetection option is used, which makes the files that don't use import, export or top-level await
+CommonJS modules too. ES modules can import them, their default export is module.exports and their named exports
+are found by static analysis of the source. Loader.Require loads a module the way require does.
+
+Module records are cached by the Loader, so a Loader can be shared by multiple Runtimes: every module is
+parsed and linked once and each Runtime keeps its own instances of the modules. Errors are not cached, a module
+which failed to load is loaded again the next time it is imported.
+*/
+package loader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/grafana/sobek"
+	"github.com/grafana/sobek/parser"
+)
+
+// Option represents one of the options of a Loader.
+type Option func(*Loader)
+
+// WithImportMap is an option to map specifiers using an import map.
+func WithImportMap(m *ImportMap) Option {
+	return func(l *Loader) {
+		l.importMap = normalizeImportMap(m)
+	}
+}
+
+// WithParserOptions is an option to set the parser options used for all modules. By default source maps are
+// loaded from the file system of the Loader.
+func WithParserOptions(opts ...parser.Option) Option {
+	return func(l *Loader) {
+		l.parserOptions = append(l.parserOptions, opts...)
+	}
+}
+
+// WithCommonJSDetection is an option to load the files which neither have a .mjs or .cjs extension nor a "type"
+// in their package.json as CommonJS modules, unless they use import, export or top-level await. Without it these
+// files are always ES modules, so a script with only side effects keeps running in strict mode.
+func WithCommonJSDetection() Option {
+	return func(l *Loader) {
+		l.detectCommonJS = true
+	}
+}
+
+// Loader resolves, loads and caches modules. It is safe for concurrent use.
+type Loader struct {
+	fsys           fs.FS
+	importMap      *normalizedImportMap
+	parserOptions  []parser.Option
+	detectCommonJS bool
+
+	mu      sync.Mutex
+	modules map[string]sobek.ModuleRecord
+	cache   map[cacheKey]sobek.ModuleRecord
+	loading map[cacheKey]struct{}
+	paths   map[sobek.ModuleRecord]string
+
+	linkMu sync.Mutex
+	linked map[sobek.ModuleRecord]struct{}
+}
+
+type cacheKey struct {
+	path       string
+	moduleType string
+}
+
+// New creates a Loader which loads modules from fsys.
+func New(fsys fs.FS, opts ...Option) *Loader {
+	l := &Loader{
+		fsys:    fsys,
+		modules: make(map[string]sobek.ModuleRecord),
+		cache:   make(map[cacheKey]sobek.ModuleRecord),
+		loading: make(map[cacheKey]struct{}),
+		paths:   make(map[sobek.ModuleRecord]string),
+		linked:  make(map[sobek.ModuleRecord]struct{}),
+	}
+	l.parserOptions = []parser.Option{parser.WithSourceMapLoader(func(p string) ([]byte, error) {
+		return fs.ReadFile(l.fsys, fsPath(p))
+	})}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Register makes the module available under the given specifier, which is matched exactly (after applying
+// the import map). Import attributes are not checked for registered modules.
+func (l *Loader) Register(specifier string, m sobek.ModuleRecord) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.modules[specifier] = m
+}
+
+// Resolve is a sobek.HostResolveImportedModuleFunc which resolves the specifier relatively to the referencing
+// module and returns the (cached) module record for it. Modules imported with `with { type: "json" }` are
+// loaded as JSON modules.
+func (l *Loader) Resolve(referencingScriptOrModule interface{}, specifier string, attributes []sobek.ImportAttribute) (sobek.ModuleRecord, error) {
+	var moduleType string
+	for _, attr := range attributes {
+		if attr.Key == "type" {
+			moduleType = attr.Value
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	referrer := "/"
+	if m, ok := referencingScriptOrModule.(sobek.ModuleRecord); ok {
+		if p, ok := l.paths[m]; ok {
+			referrer = p
+		}
+	}
+	return l.resolve(specifier, referrer, moduleType, false)
+}
+
+// resolve returns the module record for the specifier, loading it if needed. The CommonJS resolution algorithm is
+// used if require is set. It must be called with l.mu held.
+func (l *Loader) resolve(specifier, referrer, moduleType string, require bool) (sobek.ModuleRecord, error) {
+	resolved, err := l.resolveSpecifier(specifier, referrer, require)
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := l.modules[resolved]; ok {
+		return m, nil
+	}
+	if require && !hasScheme(resolved) {
+		resolved = l.probeCommonJS(resolved)
+		if moduleType == "" && path.Ext(resolved) == ".json" {
+			moduleType = "json"
+		}
+	}
+	key := cacheKey{path: resolved, moduleType: moduleType}
+	if m, ok := l.cache[key]; ok {
+		return m, nil
+	}
+	// CommonJS re-exports are resolved while loading, this breaks the cycles between them.
+	if _, ok := l.loading[key]; ok {
+		return nil, fmt.Errorf("cyclic re-export of %q", resolved)
+	}
+	l.loading[key] = struct{}{}
+	m, err := l.load(resolved, moduleType)
+	delete(l.loading, key)
+	if err != nil {
+		// errors are not cached, so that loading the module again after a transient error can succeed
+		return nil, err
+	}
+	l.cache[key] = m
+	l.paths[m] = resolved
+	return m, nil
+}
+
+// Load resolves the specifier relatively to the root of the file system and returns the linked module record.
+func (l *Loader) Load(specifier string) (sobek.ModuleRecord, error) {
+	m, err := l.Resolve(nil, specifier, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err = l.link(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Evaluate loads the module with the given specifier, enables dynamic import for rt and evaluates the module in rt.
+// The returned promise is settled once the module and its dependencies have been evaluated, which for modules
+// using top-level await or dynamic import requires the jobs of rt to be run, for example by an event loop.
+func (l *Loader) Evaluate(rt *sobek.Runtime, specifier string) (*sobek.Promise, error) {
+	m, err := l.Load(specifier)
+	if err != nil {
+		return nil, err
+	}
+	l.EnableDynamicImport(rt)
+	return m.Evaluate(rt), nil
+}
+
+// EnableDynamicImport makes import(), import.source() and import.defer() in rt load modules using the Loader.
+func (l *Loader) EnableDynamicImport(rt *sobek.Runtime) {
+	rt.SetImportModuleDynamically(func(referencingScriptOrModule interface{}, specifier sobek.Value, attributes []sobek.ImportAttribute, phase sobek.ImportPhase, promiseCapability interface{}) {
+		m, err := l.Resolve(referencingScriptOrModule, specifier.String(), attributes)
+		if err == nil && phase != sobek.SourcePhase {
+			err = l.link(m)
+		}
+		if err != nil {
+			rt.FinishLoadingImportModule(referencingScriptOrModule, specifier, promiseCapability, nil, jsError(rt, err))
+			return
+		}
+		rt.FinishLoadingImportModule(referencingScriptOrModule, specifier, promiseCapability, m, nil)
+	})
+}
+
+// link links the module unless it has already been linked successfully. Failures are not cached, as they come from
+// dependencies which could not be loaded and which are loaded again on the next attempt.
+func (l *Loader) link(m sobek.ModuleRecord) error {
+	l.linkMu.Lock()
+	defer l.linkMu.Unlock()
+	if _, ok := l.linked[m]; ok {
+		return nil
+	}
+	if err := m.Link(); err != nil {
+		return err
+	}
+	l.linked[m] = struct{}{}
+	return nil
+}
+
+func (l *Loader) resolveSpecifier(specifier, referrer string, require bool) (string, error) {
+	asURL := resolveURLLike(specifier, referrer)
+	normalized := asURL
+	if normalized == "" {
+		normalized = specifier
+	}
+	if l.importMap != nil {
+		if resolved, ok, err := l.importMap.resolve(normalized, referrer); ok || err != nil {
+			return resolved, err
+		}
+	}
+	if asURL != "" {
+		return asURL, nil
+	}
+	if _, ok := l.modules[specifier]; ok {
+		return specifier, nil
+	}
+	return l.resolveBare(specifier, referrer, require)
+}
+
+// resolveBare looks the package of a bare specifier up in the node_modules directories.
+func (l *Loader) resolveBare(specifier, referrer string, require bool) (string, error) {
+	name, subpath := splitPackageSpecifier(specifier)
+	if name == "" {
+		return "", fmt.Errorf("invalid module specifier %q", specifier)
+	}
+	for dir := path.Dir(referrer); ; dir = path.Dir(dir) {
+		pkgDir := path.Join(dir, "node_modules", name)
+		if info, err := fs.Stat(l.fsys, fsPath(pkgDir)); err == nil && info.IsDir() {
+			if subpath != "" {
+				return l.packageSubpath(pkgDir, subpath, require)
+			}
+			return l.packageEntry(pkgDir, require)
+		}
+		if dir == "/" {
+			break
+		}
+	}
+	return "", fmt.Errorf("cannot find module %q imported from %q", specifier, referrer)
+}
+
+type packageJSON struct {
+	Type    string          `json:"type"`
+	Exports json.RawMessage `json:"exports"`
+	Module  string          `json:"module"`
+	Main    string          `json:"main"`
+}
+
+// packageEntry returns the entry point of the package, taken from the "exports", "module" or "main" field
+// of its package.json. The "module" field is ignored by require. It defaults to index.js.
+func (l *Loader) packageEntry(pkgDir string, require bool) (string, error) {
+	entry := "index.js"
+	pkg, err := l.readPackageJSON(pkgDir)
+	switch {
+	case err != nil:
+		return "", err
+	case pkg != nil:
+		if e := exportsEntry(pkg.Exports, ".", exportsConditions(require)); e != "" {
+			entry = e
+		} else if pkg.Module != "" && !require {
+			entry = pkg.Module
+		} else if pkg.Main != "" {
+			entry = pkg.Main
+		}
+	}
+	return path.Join(pkgDir, entry), nil
+}
+
+// readPackageJSON reads the package.json in dir. It returns nil if there is none.
+func (l *Loader) readPackageJSON(dir string) (*packageJSON, error) {
+	data, err := fs.ReadFile(l.fsys, fsPath(path.Join(dir, "package.json")))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var pkg packageJSON
+	if err = json.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("invalid package.json in %q: %w", dir, err)
+	}
+	return &pkg, nil
+}
+
+// packageSubpath returns the file of a subpath of the package. If its package.json has an "exports" field, only
+// the subpaths listed there can be imported, otherwise the subpath is the path of the file in the package.
+func (l *Loader) packageSubpath(pkgDir, subpath string, require bool) (string, error) {
+	pkg, err := l.readPackageJSON(pkgDir)
+	if err != nil {
+		return "", err
+	}
+	if pkg == nil || len(pkg.Exports) == 0 {
+		return path.Join(pkgDir, subpath), nil
+	}
+	e := exportsEntry(pkg.Exports, "./"+subpath, exportsConditions(require))
+	if e == "" {
+		return "", fmt.Errorf("package subpath %q is not exported by %q", "./"+subpath, pkgDir)
+	}
+	p := path.Join(pkgDir, e)
+	if !strings.HasPrefix(e, "./") || !strings.HasPrefix(p, pkgDir+"/") {
+		return "", fmt.Errorf("invalid target %q for the package subpath %q of %q", e, "./"+subpath, pkgDir)
+	}
+	return p, nil
+}
+
+// exportsConditions returns the conditions matched in the "exports" field of a package.json.
+func exportsConditions(require bool) []string {
+	if require {
+		return []string{"require", "default"}
+	}
+	return []string{"import", "default"}
+}
+
+// exportsEntry returns the entry of subpath, "." or "./name", in the "exports" field of a package.json for the first
+// matching condition. Subpaths also match the keys with a "*", such as "./features/*", which is then replaced in the
+// entry by the matched part of the subpath. It returns "" if subpath is not exported.
+func exportsEntry(exports json.RawMessage, subpath string, conditions []string) string {
+	if len(exports) == 0 {
+		return ""
+	}
+	var entries map[string]json.RawMessage
+	if json.Unmarshal(exports, &entries) != nil || !hasSubpathKeys(entries) {
+		// a string or an object of conditions is the "." entry
+		if subpath != "." {
+			return ""
+		}
+		return conditionalEntry(exports, conditions)
+	}
+	if entry, ok := entries[subpath]; ok && !strings.Contains(subpath, "*") {
+		return conditionalEntry(entry, conditions)
+	}
+	var bestKey, match string
+	for key := range entries {
+		prefix, suffix, ok := strings.Cut(key, "*")
+		if !ok || strings.Contains(suffix, "*") || len(subpath) < len(key) ||
+			!strings.HasPrefix(subpath, prefix) || !strings.HasSuffix(subpath, suffix) {
+			continue
+		}
+		// the longest prefix wins, as Node.js does it
+		if bestPrefix, _, _ := strings.Cut(bestKey, "*"); bestKey == "" || len(prefix) > len(bestPrefix) ||
+			len(prefix) == len(bestPrefix) && len(key) > len(bestKey) {
+			bestKey, match = key, subpath[len(prefix):len(subpath)-len(suffix)]
+		}
+	}
+	if bestKey == "" {
+		return ""
+	}
+	return strings.ReplaceAll(conditionalEntry(entries[bestKey], conditions), "*", match)
+}
+
+// hasSubpathKeys reports whether the keys of an "exports" object are subpaths rather than conditions.
+func hasSubpathKeys(entries map[string]json.RawMessage) bool {
+	for key := range entries {
+		return strings.HasPrefix(key, ".")
+	}
+	return false
+}
+
+// conditionalEntry returns the entry for the first matching condition of an "exports" value, which is either an
+// entry or an object of conditions, possibly nested. It returns "" for null, which excludes a subpath.
+func conditionalEntry(value json.RawMessage, conditions []string) string {
+	var entry string
+	if json.Unmarshal(value, &entry) == nil {
+		return entry
+	}
+	var entries map[string]json.RawMessage
+	if json.Unmarshal(value, &entries) != nil {
+		return ""
+	}
+	for _, condition := range conditions {
+		if e := conditionalEntry(entries[condition], conditions); e != "" {
+			return e
+		}
+	}
+	return ""
+}
+
+func (l *Loader) load(p, moduleType string) (sobek.ModuleRecord, error) {
+	if hasScheme(p) {
+		return nil, fmt.Errorf("unknown module %q", p)
+	}
+	if moduleType != "" && moduleType != "json" {
+		return nil, fmt.Errorf("unsupported module type %q for %q", moduleType, p)
+	}
+	src, err := fs.ReadFile(l.fsys, fsPath(p))
+	if err != nil {
+		return nil, err
+	}
+	if moduleType == "json" {
+		return sobek.NewJSONModule(string(src))
+	}
+	return l.loadJavaScript(p, string(src))
+}
+
+// splitPackageSpecifier splits a bare specifier into the package name, which includes the scope if any,
+// and the path inside the package.
+func splitPackageSpecifier(specifier string) (name, subpath string) {
+	parts := strings.SplitN(specifier, "/", 3)
+	n := 1
+	if strings.HasPrefix(specifier, "@") {
+		n = 2
+	}
+	if len(parts) < n || parts[0] == "" || parts[n-1] == "" {
+		return "", ""
+	}
+	return strings.Join(parts[:n], "/"), strings.Join(parts[n:], "/")
+}
+
+// fsPath converts an absolute module path to a path in the fs.FS.
+func fsPath(p string) string {
+	p = strings.TrimPrefix(path.Clean(p), "/")
+	if p == "" {
+		return "."
+	}
+	return p
+}
+
+// jsError converts a loading error into a JavaScript error to reject the promise returned by import() with.
+func jsError(rt *sobek.Runtime, err error) interface{} {
+	var syntaxError *sobek.CompilerSyntaxError
+	if errors.As(err, &syntaxError) {
+		if o, err := rt.New(rt.Get("SyntaxError"), rt.ToValue(syntaxError.Error())); err == nil {
+			return o
+		}
+	}
+	return rt.NewTypeError(err.Error())
+}
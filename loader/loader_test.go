@@ -0,0 +1,196 @@
+package loader
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/grafana/sobek"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"main.js": {Data: []byte(`
+			import { a, fromB } from "./lib/a.js";
+			import _ from "lodash";
+			import { sub } from "@scope/pkg/sub.js";
+			import mapped from "mapped";
+			import depSub from "dep/sub";
+			import depFeature from "dep/features/x";
+			import { readFile } from "host:fs";
+			import config from "./config.json" with { type: "json" };
+
+			globalThis.result = [a, fromB(), _.name, sub, mapped, depSub, depFeature, readFile("x"), config.name].join();
+			import("./lib/b.js").then(ns => { globalThis.dynamic = ns.fromA(); });
+			import("./missing.js").catch(e => { globalThis.dynamicError = e instanceof TypeError; });
+		`)},
+		"config.json":   {Data: []byte(`{"name": "config"}`)},
+		"lib/a.js":      {Data: []byte(`import { b } from "./b.js"; export const a = "a"; export function fromB() { return b; }`)},
+		"lib/b.js":      {Data: []byte(`import { a } from "../lib/a.js"; export const b = "b"; export function fromA() { return a; }`)},
+		"lib/mapped.js": {Data: []byte(`export default "mapped";`)},
+
+		"node_modules/lodash/package.json":     {Data: []byte(`{"main": "lodash.js"}`)},
+		"node_modules/lodash/lodash.js":        {Data: []byte(`export default { name: "lodash" };`)},
+		"node_modules/@scope/pkg/package.json": {Data: []byte(`{}`)},
+		"node_modules/@scope/pkg/sub.js":       {Data: []byte(`import dep from "dep"; export const sub = "sub+" + dep;`)},
+		"node_modules/dep/package.json": {Data: []byte(`{"exports": {
+			".": {"import": "./esm.js", "require": "./cjs.js"},
+			"./sub": "./lib/sub.js",
+			"./features/*": {"import": "./lib/features/*.js"},
+			"./features/internal/*": null
+		}}`)},
+		"node_modules/dep/esm.js":                     {Data: []byte(`export default "dep";`)},
+		"node_modules/dep/lib/sub.js":                 {Data: []byte(`export default "dep/sub";`)},
+		"node_modules/dep/lib/features/x.js":          {Data: []byte(`export default "dep/features/x";`)},
+		"node_modules/dep/lib/features/internal/y.js": {Data: []byte(`export default "internal";`)},
+	}
+}
+
+func TestLoader(t *testing.T) {
+	t.Parallel()
+	l := New(testFS(), WithImportMap(&ImportMap{
+		Imports: map[string]string{"mapped": "./lib/mapped.js"},
+	}))
+	l.Register("host:fs", sobek.NewSyntheticModule([]string{"readFile"}, func(mi *sobek.SyntheticModuleInstance) error {
+		return mi.SetExport("readFile", func(name string) string { return "file " + name })
+	}))
+
+	for i := 0; i < 2; i++ {
+		vm := sobek.New()
+		promise, err := l.Evaluate(vm, "/main.js")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if promise.State() != sobek.PromiseStateFulfilled {
+			t.Fatalf("got %v", promise.Result())
+		}
+		if res := vm.Get("result").String(); res != "a,b,lodash,sub+dep,mapped,dep/sub,dep/features/x,file x,config" {
+			t.Fatalf("unexpected result %q", res)
+		}
+		if res := vm.Get("dynamic"); res == nil || res.String() != "a" {
+			t.Fatalf("unexpected dynamic import result %v", res)
+		}
+		if res := vm.Get("dynamicError"); res == nil || !res.ToBoolean() {
+			t.Fatalf("unexpected dynamic import error %v", res)
+		}
+	}
+
+	m1, err := l.Load("./lib/a.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m2, err := l.Load("/lib/../lib/a.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m1 != m2 {
+		t.Fatal("module records are not cached")
+	}
+}
+
+func TestLoaderErrors(t *testing.T) {
+	t.Parallel()
+	l := New(fstest.MapFS{
+		"syntax.js":   {Data: []byte(`export let;`)},
+		"link.js":     {Data: []byte(`import { x } from "./empty.js";`)},
+		"empty.js":    {Data: []byte(``)},
+		"bare.js":     {Data: []byte(`import "unknown";`)},
+		"type.js":     {Data: []byte(`import "./empty.js" with { type: "css" };`)},
+		"blocked.js":  {Data: []byte(`import "blocked";`)},
+		"scheme.js":   {Data: []byte(`import "https://example.com/x.js";`)},
+		"data.json":   {Data: []byte(`{`)},
+		"json.js":     {Data: []byte(`import data from "./data.json" with { type: "json" };`)},
+		"notfound.js": {Data: []byte(`import "./nope.js";`)},
+		"exports.js":  {Data: []byte(`import "pkg/esm.js";`)},
+		"excluded.js": {Data: []byte(`import "pkg/features/internal/y";`)},
+		"node_modules/pkg/package.json": {Data: []byte(`{"exports": {
+			".": "./esm.js",
+			"./features/*": "./features/*.js",
+			"./features/internal/*": null
+		}}`)},
+		"node_modules/pkg/esm.js":                 {Data: []byte(``)},
+		"node_modules/pkg/features/internal/y.js": {Data: []byte(``)},
+	}, WithImportMap(&ImportMap{Imports: map[string]string{"blocked": ""}}))
+
+	for specifier, expected := range map[string]string{
+		"/syntax.js":   "SyntaxError",
+		"/link.js":     `does not provide an export named "x"`,
+		"/bare.js":     `cannot find module "unknown"`,
+		"/type.js":     `unsupported module type "css"`,
+		"/blocked.js":  `resolution of "blocked" was blocked by the import map`,
+		"/scheme.js":   `unknown module "https://example.com/x.js"`,
+		"/json.js":     `invalid JSON module source`,
+		"/notfound.js": `nope.js`,
+		"/exports.js":  `package subpath "./esm.js" is not exported`,
+		"/excluded.js": `package subpath "./features/internal/y" is not exported`,
+	} {
+		vm := sobek.New()
+		var msg string
+		promise, err := l.Evaluate(vm, specifier)
+		if err != nil {
+			msg = err.Error()
+		} else if promise.State() == sobek.PromiseStateRejected {
+			msg = promise.Result().String()
+			if ex, ok := promise.Result().Export().(*sobek.Exception); ok {
+				msg = ex.Error()
+			}
+		}
+		if !strings.Contains(msg, expected) {
+			t.Fatalf("%s: expected error %q, got %q", specifier, expected, msg)
+		}
+	}
+}
+
+// flakyFS fails to open the files in failures until they are removed.
+type flakyFS struct {
+	files    fstest.MapFS
+	mu       sync.Mutex
+	failures map[string]bool
+}
+
+func (f *flakyFS) Open(name string) (fs.File, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failures[name] {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: errors.New("transient error")}
+	}
+	return f.files.Open(name)
+}
+
+func TestLoaderTransientErrors(t *testing.T) {
+	t.Parallel()
+	fsys := &flakyFS{
+		files: fstest.MapFS{
+			"main.js": {Data: []byte(`import { value } from "./dep.js"; globalThis.result = value;`)},
+			"dep.js":  {Data: []byte(`export const value = "dep";`)},
+		},
+		failures: map[string]bool{"main.js": true, "dep.js": true},
+	}
+	l := New(fsys)
+
+	for _, name := range []string{"main.js", "dep.js", ""} {
+		vm := sobek.New()
+		promise, err := l.Evaluate(vm, "/main.js")
+		if name != "" {
+			if err == nil && promise.State() != sobek.PromiseStateRejected {
+				t.Fatalf("expected an error with %s failing", name)
+			}
+			fsys.mu.Lock()
+			delete(fsys.failures, name)
+			fsys.mu.Unlock()
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if promise.State() != sobek.PromiseStateFulfilled {
+			t.Fatalf("got %v", promise.Result())
+		}
+		if res := vm.Get("result").String(); res != "dep" {
+			t.Fatalf("unexpected result %q", res)
+		}
+	}
+}
@@ -5,7 +5,35 @@ import (
 	"sort"
 )
 
-type HostResolveImportedModuleFunc func(referencingScriptOrModule interface{}, specifier string) (ModuleRecord, error)
+type HostResolveImportedModuleFunc func(referencingScriptOrModule interface{}, specifier string, attributes []ImportAttribute) (ModuleRecord, error)
+
+// ImportAttribute is a single key/value pair from the `with { ... }` clause of an import or export declaration
+// or from the options of a dynamic import().
+type ImportAttribute struct {
+	Key   string
+	Value string
+}
+
+// ImportPhase is the phase in which a module is requested.
+type ImportPhase uint8
+
+const (
+	// EvaluationPhase modules are linked and evaluated before the module requesting them.
+	EvaluationPhase ImportPhase = iota
+	// SourcePhase modules (`import source x from "y"`) are only resolved. They are neither linked nor evaluated.
+	SourcePhase
+	// DeferPhase modules (`import defer * as ns from "y"`) are linked, but only evaluated
+	// the first time a property of their namespace is accessed.
+	DeferPhase
+)
+
+// ModuleRequest is a module specifier together with the import attributes and the phase it was requested with.
+// Attributes are sorted by key.
+type ModuleRequest struct {
+	Specifier  string
+	Attributes []ImportAttribute
+	Phase      ImportPhase
+}
 
 // TODO most things here probably should be unexported and names should be revised before merged in master
 // Record should probably be dropped from everywhere
@@ -23,6 +51,13 @@ type ModuleRecord interface {
 	Evaluate(*Runtime) *Promise
 }
 
+// ModuleSourceRecord is implemented by module records which have a source representation
+// that can be imported with `import source x from "y"`.
+type ModuleSourceRecord interface {
+	ModuleRecord
+	GetModuleSource(rt *Runtime) Value
+}
+
 type CyclicModuleRecordStatus uint8
 
 const (
@@ -36,7 +71,7 @@ const (
 
 type CyclicModuleRecord interface {
 	ModuleRecord
-	RequestedModules() []string
+	RequestedModules() []ModuleRequest
 	InitializeEnvironment() error
 	Instantiate(rt *Runtime) (CyclicModuleInstance, error)
 }
@@ -93,10 +128,13 @@ func (c *compiler) innerModuleLinking(state *linkState, m ModuleRecord, stack *[
 	var err error
 	var requiredModule ModuleRecord
 	for _, required := range module.RequestedModules() {
-		requiredModule, err = c.hostResolveImportedModule(module, required)
+		requiredModule, err = c.hostResolveImportedModule(module, required.Specifier, required.Attributes)
 		if err != nil {
 			return 0, err
 		}
+		if required.Phase == SourcePhase {
+			continue
+		}
 		index, err = c.innerModuleLinking(state, requiredModule, stack, index)
 		if err != nil {
 			return 0, err
@@ -243,12 +281,29 @@ func (r *Runtime) innerModuleEvaluation(
 
 	*stack = append(*stack, c)
 	var requiredModule ModuleRecord
+	var evaluationList []ModuleRecord
 	for _, required := range cr.RequestedModules() {
-		requiredModule, err = resolve(m, required)
+		if required.Phase == SourcePhase {
+			continue
+		}
+		requiredModule, err = resolve(m, required.Specifier, required.Attributes)
 		if err != nil {
 			state.evaluationError[c] = err
 			return index, err
 		}
+		if required.Phase == DeferPhase {
+			// the deferred module itself is evaluated on first access, but the parts of its graph which can not
+			// be evaluated synchronously at that point are evaluated now
+			err = r.gatherAsynchronousTransitiveDependencies(state, requiredModule, resolve, map[ModuleRecord]struct{}{}, &evaluationList)
+			if err != nil {
+				state.evaluationError[c] = err
+				return index, err
+			}
+			continue
+		}
+		evaluationList = append(evaluationList, requiredModule)
+	}
+	for _, requiredModule = range evaluationList {
 		index, err = r.innerModuleEvaluation(state, requiredModule, stack, index, resolve)
 		if err != nil {
 			return index, err
@@ -301,6 +356,86 @@ func (r *Runtime) innerModuleEvaluation(
 	return index, nil
 }
 
+// gatherAsynchronousTransitiveDependencies appends to result the modules in the graph of m, a module imported with
+// `import defer`, which use top-level await or are still being evaluated asynchronously. It doesn't look past them,
+// as their dependencies are evaluated together with them.
+func (r *Runtime) gatherAsynchronousTransitiveDependencies(
+	state *evaluationState, m ModuleRecord, resolve HostResolveImportedModuleFunc,
+	seen map[ModuleRecord]struct{}, result *[]ModuleRecord,
+) error {
+	if _, ok := seen[m]; ok {
+		return nil
+	}
+	seen[m] = struct{}{}
+	cr, ok := m.(CyclicModuleRecord)
+	if !ok {
+		return nil
+	}
+	if c, ok := r.modules[m].(CyclicModuleInstance); ok {
+		if status := state.status[c]; status == Evaluating || status == Evaluating_Async || status == Evaluated {
+			if state.asyncEvaluation[c] != 0 && state.evaluationError[c] == nil {
+				appendModuleRecord(result, m)
+			}
+			return nil
+		}
+	}
+	if t, ok := m.(interface{ HasTLA() bool }); ok && t.HasTLA() {
+		appendModuleRecord(result, m)
+		return nil
+	}
+	for _, required := range cr.RequestedModules() {
+		if required.Phase == SourcePhase {
+			continue
+		}
+		requiredModule, err := resolve(m, required.Specifier, required.Attributes)
+		if err != nil {
+			return err
+		}
+		if err = r.gatherAsynchronousTransitiveDependencies(state, requiredModule, resolve, seen, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendModuleRecord(list *[]ModuleRecord, m ModuleRecord) {
+	for _, l := range *list {
+		if l == m {
+			return
+		}
+	}
+	*list = append(*list, m)
+}
+
+// evaluateDeferredModule synchronously evaluates a module imported with `import defer`.
+// It throws the evaluation error, if any, or a TypeError if the module could not be evaluated synchronously
+// because it or one of its dependencies uses top-level await.
+func (r *Runtime) evaluateDeferredModule(m ModuleRecord, resolve HostResolveImportedModuleFunc) {
+	var p *Promise
+	if c, ok := m.(CyclicModuleRecord); ok && resolve != nil {
+		p = r.CyclicModuleRecordEvaluate(c, resolve)
+	} else if ok {
+		p = c.Evaluate(r)
+	} else {
+		if _, ok := r.modules[m]; ok {
+			return
+		}
+		p = m.Evaluate(r)
+		if p.state == PromiseStateFulfilled {
+			r.modules[m] = p.Result().Export().(ModuleInstance)
+		}
+	}
+	switch p.state {
+	case PromiseStateRejected:
+		if ex, ok := p.Result().Export().(*Exception); ok {
+			panic(ex)
+		}
+		panic(p.Result())
+	case PromiseStatePending:
+		panic(r.NewTypeError("Deferred module can not be evaluated synchronously as it uses top-level await"))
+	}
+}
+
 func (r *Runtime) executeAsyncModule(state *evaluationState, c CyclicModuleInstance) {
 	// implement https://262.ecma-international.org/13.0/#sec-execute-async-module
 	p, res, rej := r.NewPromise()
@@ -394,6 +529,14 @@ func (r *Runtime) asyncModuleExecutionRejected(state *evaluationState, c CyclicM
 	}
 }
 
+// setModuleInstance records the instance of a module which is evaluated outside of CyclicModuleRecordEvaluate.
+func (r *Runtime) setModuleInstance(m ModuleRecord, mi ModuleInstance) {
+	if r.modules == nil {
+		r.modules = make(map[ModuleRecord]ModuleInstance)
+	}
+	r.modules[m] = mi
+}
+
 // TODO fix this whole thing
 func (r *Runtime) findModuleRecord(i ModuleInstance) ModuleRecord {
 	for m, mi := range r.modules {
@@ -458,12 +601,58 @@ func (r *Runtime) SetFinalImportMeta(fn func(*Object, ModuleRecord)) {
 }
 
 // TODO fix signature
-type ImportModuleDynamicallyCallback func(referencingScriptOrModule interface{}, specifier Value, promiseCapability interface{})
+// The phase is SourcePhase for import.source() and DeferPhase for import.defer(). For SourcePhase the host doesn't need
+// to link the module before calling FinishLoadingImportModule.
+type ImportModuleDynamicallyCallback func(referencingScriptOrModule interface{}, specifier Value, attributes []ImportAttribute, phase ImportPhase, promiseCapability interface{})
+
+// dynamicImportPayload is the payload of a dynamic import which the host passes back to FinishLoadingImportModule.
+type dynamicImportPayload struct {
+	capability *promiseCapability
+	phase      ImportPhase
+}
 
 func (r *Runtime) SetImportModuleDynamically(callback ImportModuleDynamicallyCallback) {
 	r.importModuleDynamically = callback
 }
 
+// importAttributesFromOptions extracts the import attributes from the second argument of import().
+// It panics with a TypeError if the options are malformed or contain an unsupported attribute.
+func (r *Runtime) importAttributesFromOptions(options Value) []ImportAttribute {
+	// https://tc39.es/ecma262/#sec-evaluate-import-call
+	if options == _undefined {
+		return nil
+	}
+	optionsObj, ok := options.(*Object)
+	if !ok {
+		panic(r.NewTypeError("The second argument to import() must be an object"))
+	}
+	with := optionsObj.self.getStr("with", nil)
+	if with == nil || with == _undefined {
+		return nil
+	}
+	withObj, ok := with.(*Object)
+	if !ok {
+		panic(r.NewTypeError("The 'with' option to import() must be an object"))
+	}
+	var attributes []ImportAttribute
+	for item, next := iterateEnumerableStringProperties(withObj)(); next != nil; item, next = next() {
+		value, ok := item.value.(String)
+		if !ok {
+			panic(r.NewTypeError("Import attribute value must be a string"))
+		}
+		attributes = append(attributes, ImportAttribute{Key: item.name.String(), Value: value.String()})
+	}
+	for _, attr := range attributes {
+		if attr.Key != "type" {
+			panic(r.NewTypeError("Unsupported import attribute '%s'", attr.Key))
+		}
+	}
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].Key < attributes[j].Key
+	})
+	return attributes
+}
+
 // TODO figure out whether Result should be an Option thing :shrug:
 func (r *Runtime) FinishLoadingImportModule(referrer interface{}, specifier Value, payload interface{}, result ModuleRecord, err interface{}) {
 	// https://262.ecma-international.org/14.0/#sec-FinishLoadingImportedModule
@@ -477,10 +666,11 @@ func (r *Runtime) FinishLoadingImportModule(referrer interface{}, specifier Valu
 	//     a. a. Perform ContinueModuleLoading(payload, result).
 	// 3. 3. Else,
 	//     a. a. Perform ContinueDynamicImport(payload, result).
-	r.continueDynamicImport(payload.(*promiseCapability), result, err) // TODO better type inferance
+	p := payload.(*dynamicImportPayload) // TODO better type inferance
+	r.continueDynamicImport(p.capability, p.phase, specifier, result, err)
 }
 
-func (r *Runtime) continueDynamicImport(promiseCapability *promiseCapability, result ModuleRecord, err interface{}) {
+func (r *Runtime) continueDynamicImport(promiseCapability *promiseCapability, phase ImportPhase, specifier Value, result ModuleRecord, err interface{}) {
 	// https://262.ecma-international.org/14.0/#sec-ContinueDynamicImport
 	if err != nil {
 		promiseCapability.reject(r.ToValue(err))
@@ -488,6 +678,11 @@ func (r *Runtime) continueDynamicImport(promiseCapability *promiseCapability, re
 	}
 	// 2. 2. Let module be moduleCompletion.[[Value]].
 	module := result
+	if phase == SourcePhase {
+		// https://tc39.es/proposal-source-phase-imports/#sec-ContinueDynamicImport
+		r.continueDynamicSourceImport(promiseCapability, specifier, module)
+		return
+	}
 	// 3. 3. Let loadPromise be module.LoadRequestedModules().
 	loadPromise := r.promiseResolve(r.getPromise(), _undefined) // TODO fix
 
@@ -514,6 +709,10 @@ func (r *Runtime) continueDynamicImport(promiseCapability *promiseCapability, re
 			promiseCapability.reject(r.ToValue(err))
 			return nil
 		}
+		if phase == DeferPhase {
+			r.evaluateDynamicDeferredImport(promiseCapability, module, rejectionClosure)
+			return nil
+		}
 		evaluationPromise := module.Evaluate(r)
 		onFullfill := r.ToValue(func(call FunctionCall) Value {
 			namespace := r.NamespaceObjectFor(module)
@@ -526,3 +725,65 @@ func (r *Runtime) continueDynamicImport(promiseCapability *promiseCapability, re
 
 	r.performPromiseThen(loadPromise.Export().(*Promise), linkAndEvaluateClosure, rejectionClosure, nil)
 }
+
+func (r *Runtime) continueDynamicSourceImport(promiseCapability *promiseCapability, specifier Value, module ModuleRecord) {
+	s, ok := module.(ModuleSourceRecord)
+	if !ok {
+		promiseCapability.reject(r.newErrorf(r.getSyntaxError(), "The requested module %q does not provide a source representation", specifier.String()))
+		return
+	}
+	var source Value
+	if err := r.try(func() {
+		source = s.GetModuleSource(r)
+	}); err != nil {
+		if ex, ok := err.(*Exception); ok {
+			promiseCapability.reject(ex.val)
+		} else {
+			promiseCapability.reject(r.ToValue(err))
+		}
+		return
+	}
+	promiseCapability.resolve(source)
+}
+
+// evaluateDynamicDeferredImport resolves the promise of import.defer() with the deferred namespace of module once the
+// parts of its graph which can not be evaluated synchronously, because they use top-level await, are evaluated.
+func (r *Runtime) evaluateDynamicDeferredImport(promiseCapability *promiseCapability, module ModuleRecord, rejectionClosure Value) {
+	// https://tc39.es/proposal-defer-import-eval/#sec-ContinueDynamicImport
+	resolve := moduleResolver(module)
+	var evaluationList []ModuleRecord
+	if resolve != nil {
+		if r.evaluationState == nil {
+			r.evaluationState = newEvaluationState()
+		}
+		err := r.gatherAsynchronousTransitiveDependencies(r.evaluationState, module, resolve, map[ModuleRecord]struct{}{}, &evaluationList)
+		if err != nil {
+			promiseCapability.reject(r.ToValue(err))
+			return
+		}
+	}
+	namespace := r.deferredNamespaceObjectFor(module, resolve)
+	if len(evaluationList) == 0 {
+		promiseCapability.resolve(namespace)
+		return
+	}
+	remaining := len(evaluationList)
+	onFullfill := r.ToValue(func(call FunctionCall) Value {
+		remaining--
+		if remaining == 0 {
+			promiseCapability.resolve(namespace)
+		}
+		return nil
+	})
+	for _, m := range evaluationList {
+		r.performPromiseThen(m.Evaluate(r), onFullfill, rejectionClosure, nil)
+	}
+}
+
+// moduleResolver returns the function which resolves the modules requested by m, or nil if it isn't known.
+func moduleResolver(m ModuleRecord) HostResolveImportedModuleFunc {
+	if s, ok := m.(*SourceTextModuleRecord); ok {
+		return s.hostResolveImportedModule
+	}
+	return nil
+}
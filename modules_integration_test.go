@@ -29,7 +29,7 @@ func newSimpleComboResolver() *simpleComboResolver {
 	return &simpleComboResolver{cache: make(map[string]cacheElement), reverseCache: make(map[sobek.ModuleRecord]string)}
 }
 
-func (s *simpleComboResolver) resolve(referencingScriptOrModule interface{}, specifier string) (sobek.ModuleRecord, error) {
+func (s *simpleComboResolver) resolve(referencingScriptOrModule interface{}, specifier string, _ []sobek.ImportAttribute) (sobek.ModuleRecord, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	k, ok := s.cache[specifier]
@@ -116,7 +116,7 @@ func TestNotSourceModulesBigTest(t *testing.T) {
         `),
 	}
 	resolver.fs = mapfs
-	m, err := resolver.resolve(nil, "main.js")
+	m, err := resolver.resolve(nil, "main.js", nil)
 	if err != nil {
 		t.Fatalf("got error %s", err)
 	}
@@ -195,7 +195,7 @@ func TestNotSourceModulesBigTestDynamicImport(t *testing.T) {
         })`),
 	}
 	resolver.fs = mapfs
-	m, err := resolver.resolve(nil, "main.js")
+	m, err := resolver.resolve(nil, "main.js", nil)
 	if err != nil {
 		t.Fatalf("got error %s", err)
 	}
@@ -209,10 +209,10 @@ func TestNotSourceModulesBigTestDynamicImport(t *testing.T) {
 	vm.SetPromiseRejectionTracker(func(p *sobek.Promise, operation sobek.PromiseRejectionOperation) {
 		t.Fatal(p.Result())
 	})
-	vm.SetImportModuleDynamically(func(referencingScriptOrModule interface{}, specifierValue sobek.Value, promiseCapability interface{}) {
+	vm.SetImportModuleDynamically(func(referencingScriptOrModule interface{}, specifierValue sobek.Value, attributes []sobek.ImportAttribute, _ sobek.ImportPhase, promiseCapability interface{}) {
 		specifier := specifierValue.String()
 		go func() {
-			m, err := resolver.resolve(referencingScriptOrModule, specifier)
+			m, err := resolver.resolve(referencingScriptOrModule, specifier, attributes)
 
 			eventLoopQueue <- func() {
 				defer vm.RunString("") // FIXME haxx // the specification kind of doesn't have a solutioo for htis it seems
@@ -302,8 +302,12 @@ func (s *cyclicModuleImpl) Instantiate(_ *sobek.Runtime) (sobek.CyclicModuleInst
 	return &cyclicModuleInstanceImpl{module: s}, nil
 }
 
-func (s *cyclicModuleImpl) RequestedModules() []string {
-	return s.requestedModules
+func (s *cyclicModuleImpl) RequestedModules() []sobek.ModuleRequest {
+	requests := make([]sobek.ModuleRequest, len(s.requestedModules))
+	for i, specifier := range s.requestedModules {
+		requests[i] = sobek.ModuleRequest{Specifier: specifier}
+	}
+	return requests
 }
 
 func (s *cyclicModuleImpl) Link() error {
@@ -321,7 +325,7 @@ func (s *cyclicModuleImpl) ResolveExport(exportName string, resolveset ...sobek.
 		return nil, false
 	}
 
-	m, err := s.resolve(s, b.module)
+	m, err := s.resolve(s, b.module, nil)
 	if err != nil {
 		panic(err)
 	}
@@ -387,7 +391,7 @@ func TestSourceMetaImport(t *testing.T) {
         `),
 	}
 	resolver.fs = mapfs
-	m, err := resolver.resolve(nil, "main.js")
+	m, err := resolver.resolve(nil, "main.js", nil)
 	if err != nil {
 		t.Fatalf("got error %s", err)
 	}
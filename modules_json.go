@@ -0,0 +1,71 @@
+package sobek
+
+import "encoding/json"
+
+// JSONModuleRecord is a ModuleRecord for JSON modules, as imported with `import data from "./data.json" with { type: "json" }`.
+// The module has a single "default" export which holds the result of JSON.parse of the source.
+// Each Runtime gets its own copy of the value, which is created the first time the module is evaluated.
+type JSONModuleRecord struct {
+	source String
+}
+
+var _ ModuleRecord = &JSONModuleRecord{}
+
+// NewJSONModule returns a JSONModuleRecord for the given source. It returns an error if the source is not valid JSON.
+func NewJSONModule(source string) (*JSONModuleRecord, error) {
+	if !json.Valid([]byte(source)) {
+		return nil, &CompilerSyntaxError{CompilerError: CompilerError{
+			Message: "invalid JSON module source",
+		}}
+	}
+	return &JSONModuleRecord{source: newStringValue(source)}, nil
+}
+
+func (m *JSONModuleRecord) GetExportedNames(callback func([]string), _ ...ModuleRecord) bool {
+	callback([]string{"default"})
+	return true
+}
+
+func (m *JSONModuleRecord) ResolveExport(exportName string, _ ...ResolveSetElement) (*ResolvedBinding, bool) {
+	if exportName == "default" {
+		return &ResolvedBinding{
+			Module:      m,
+			BindingName: "default",
+		}, false
+	}
+	return nil, false
+}
+
+func (m *JSONModuleRecord) Link() error {
+	return nil
+}
+
+func (m *JSONModuleRecord) Evaluate(rt *Runtime) *Promise {
+	p, resolve, reject := rt.NewPromise()
+	if mi, ok := rt.modules[m]; ok {
+		_ = resolve(mi)
+		return p
+	}
+	var value Value
+	if ex := rt.try(func() {
+		value = rt.builtinJSON_parse(FunctionCall{Arguments: []Value{m.source}})
+	}); ex != nil {
+		_ = reject(ex)
+		return p
+	}
+	mi := &jsonModuleInstance{value: value}
+	rt.setModuleInstance(m, mi)
+	_ = resolve(mi)
+	return p
+}
+
+type jsonModuleInstance struct {
+	value Value
+}
+
+func (mi *jsonModuleInstance) GetBindingValue(name string) Value {
+	if name == "default" {
+		return mi.value
+	}
+	return nil
+}
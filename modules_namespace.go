@@ -11,6 +11,9 @@ type namespaceObject struct {
 	m            ModuleRecord
 	exports      map[unistring.String]struct{}
 	exportsNames []unistring.String
+
+	// evaluate is set for the namespace of a module imported with `import defer` until it is evaluated.
+	evaluate func()
 }
 
 func (r *Runtime) NamespaceObjectFor(m ModuleRecord) *Object {
@@ -20,18 +23,39 @@ func (r *Runtime) NamespaceObjectFor(m ModuleRecord) *Object {
 	if o, ok := r.moduleNamespaces[m]; ok {
 		return o.val
 	}
-	o := r.createNamespaceObject(m)
+	o := r.createNamespaceObject(m, false)
 	r.moduleNamespaces[m] = o
 	return o.val
 }
 
-func (r *Runtime) createNamespaceObject(m ModuleRecord) *namespaceObject {
+// deferredNamespaceObjectFor returns the namespace object of a module imported with `import defer`.
+// The module is evaluated the first time one of the namespace's string keyed properties is accessed.
+func (r *Runtime) deferredNamespaceObjectFor(m ModuleRecord, resolve HostResolveImportedModuleFunc) *Object {
+	if r.deferredModuleNamespaces == nil {
+		r.deferredModuleNamespaces = make(map[ModuleRecord]*namespaceObject)
+	}
+	if o, ok := r.deferredModuleNamespaces[m]; ok {
+		return o.val
+	}
+	o := r.createNamespaceObject(m, true)
+	o.evaluate = func() {
+		r.evaluateDeferredModule(m, resolve)
+	}
+	r.deferredModuleNamespaces[m] = o
+	return o.val
+}
+
+func (r *Runtime) createNamespaceObject(m ModuleRecord, deferred bool) *namespaceObject {
 	o := &Object{runtime: r}
 	no := &namespaceObject{m: m}
 	no.val = o
 	no.extensible = true
+	tag := "Module"
+	if deferred {
+		tag = "Deferred Module"
+	}
 	no.defineOwnPropertySym(SymToStringTag, PropertyDescriptor{
-		Value: newStringValue("Module"),
+		Value: newStringValue(tag),
 	}, true)
 	no.extensible = false
 	o.self = no
@@ -39,6 +63,10 @@ func (r *Runtime) createNamespaceObject(m ModuleRecord) *namespaceObject {
 	no.exports = make(map[unistring.String]struct{})
 	m.GetExportedNames(func(names []string) {
 		for _, exportName := range names {
+			if deferred && exportName == "then" {
+				// so that awaiting a deferred namespace doesn't evaluate the module
+				continue
+			}
 			v, ambiguous := no.m.ResolveExport(exportName)
 			if ambiguous || v == nil {
 				continue
@@ -50,7 +78,16 @@ func (r *Runtime) createNamespaceObject(m ModuleRecord) *namespaceObject {
 	return no
 }
 
+// ensureEvaluated evaluates the module of a deferred namespace. Accessing "then" doesn't trigger evaluation.
+func (no *namespaceObject) ensureEvaluated(name unistring.String) {
+	if no.evaluate != nil && name != "then" {
+		no.evaluate()
+		no.evaluate = nil
+	}
+}
+
 func (no *namespaceObject) stringKeys(all bool, accum []Value) []Value {
+	no.ensureEvaluated("")
 	for name := range no.exports {
 		if !all { //  TODO this seems off
 			_ = no.getOwnPropStr(name)
@@ -92,6 +129,7 @@ func (i *namespacePropIter) next() (propIterItem, iterNextFunc) {
 }
 
 func (no *namespaceObject) getOwnPropStr(name unistring.String) Value {
+	no.ensureEvaluated(name)
 	if _, ok := no.exports[name]; !ok {
 		return nil
 	}
@@ -124,6 +162,7 @@ func (no *namespaceObject) getOwnPropStr(name unistring.String) Value {
 }
 
 func (no *namespaceObject) hasOwnPropertyStr(name unistring.String) bool {
+	no.ensureEvaluated(name)
 	_, ok := no.exports[name]
 	return ok
 }
@@ -145,6 +184,7 @@ func (no *namespaceObject) setOwnStr(name unistring.String, val Value, throw boo
 }
 
 func (no *namespaceObject) deleteStr(name unistring.String, throw bool) bool {
+	no.ensureEvaluated(name)
 	if _, exists := no.exports[name]; exists {
 		no.val.runtime.typeErrorResult(throw, "Cannot add property %s, object is not extensible", name)
 		return false
@@ -86,7 +86,7 @@ type SourceTextModuleRecord struct {
 	// context
 	// importmeta
 	hasTLA                bool
-	requestedModules      []string
+	requestedModules      []ModuleRequest
 	importEntries         []importEntry
 	localExportEntries    []exportEntry
 	indirectExportEntries []exportEntry
@@ -99,6 +99,7 @@ type SourceTextModuleRecord struct {
 
 type importEntry struct {
 	moduleRequest string
+	attributes    []ImportAttribute
 	importName    string
 	localName     string
 	offset        int
@@ -107,6 +108,7 @@ type importEntry struct {
 type exportEntry struct {
 	exportName    string
 	moduleRequest string
+	attributes    []ImportAttribute
 	importName    string
 	localName     string
 	offset        int
@@ -124,6 +126,7 @@ func importEntriesFromAst(declarations []*ast.ImportDeclaration) ([]importEntry,
 			continue // no entry in this case
 		}
 		moduleRequest := importDeclarion.FromClause.ModuleSpecifier.String()
+		attributes := importAttributesFromAst(importDeclarion.Attributes)
 		if named := importClause.NamedImports; named != nil {
 			for _, el := range named.ImportsList {
 				localName := el.Alias.String()
@@ -136,6 +139,7 @@ func importEntriesFromAst(declarations []*ast.ImportDeclaration) ([]importEntry,
 				names[localName] = struct{}{}
 				result = append(result, importEntry{
 					moduleRequest: moduleRequest,
+					attributes:    attributes,
 					importName:    el.IdentifierName.String(),
 					localName:     localName,
 					offset:        int(importDeclarion.Idx0()),
@@ -148,9 +152,14 @@ func importEntriesFromAst(declarations []*ast.ImportDeclaration) ([]importEntry,
 				return nil, fmt.Errorf("duplicate bounded name %s", localName)
 			}
 			names[localName] = struct{}{}
+			importName := "default"
+			if importDeclarion.Phase == ast.ImportPhaseSource {
+				importName = "*source*"
+			}
 			result = append(result, importEntry{
 				moduleRequest: moduleRequest,
-				importName:    "default",
+				attributes:    attributes,
+				importName:    importName,
 				localName:     localName,
 				offset:        int(importDeclarion.Idx0()),
 			})
@@ -163,6 +172,7 @@ func importEntriesFromAst(declarations []*ast.ImportDeclaration) ([]importEntry,
 			names[localName] = struct{}{}
 			result = append(result, importEntry{
 				moduleRequest: moduleRequest,
+				attributes:    attributes,
 				importName:    "*",
 				localName:     namespace.ImportedBinding.String(),
 				offset:        int(importDeclarion.Idx0()),
@@ -243,6 +253,7 @@ func exportEntriesFromAst(declarations []*ast.ExportDeclaration) []exportEntry {
 						exportName:    exportFromClause.Alias.String(),
 						importName:    "*",
 						moduleRequest: from.ModuleSpecifier.String(),
+						attributes:    importAttributesFromAst(exportDeclaration.Attributes),
 						offset:        int(exportDeclaration.Idx0()),
 					})
 				} else {
@@ -309,6 +320,7 @@ func exportEntriesFromAst(declarations []*ast.ExportDeclaration) []exportEntry {
 						importName:    spec.IdentifierName.String(),
 						exportName:    alias,
 						moduleRequest: fromClause.ModuleSpecifier.String(),
+						attributes:    importAttributesFromAst(exportDeclaration.Attributes),
 						offset:        int(exportDeclaration.Idx0()),
 					})
 				}
@@ -362,19 +374,40 @@ func exportEntriesFromAst(declarations []*ast.ExportDeclaration) []exportEntry {
 	return result
 }
 
-func requestedModulesFromAst(statements []ast.Statement) []string {
-	var result []string
+func importAttributesFromAst(attributes []*ast.ImportAttribute) []ImportAttribute {
+	if len(attributes) == 0 {
+		return nil
+	}
+	result := make([]ImportAttribute, len(attributes))
+	for i, attr := range attributes {
+		result[i] = ImportAttribute{Key: attr.Key.String(), Value: attr.Value.String()}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+func requestedModulesFromAst(statements []ast.Statement) []ModuleRequest {
+	var result []ModuleRequest
 	for _, st := range statements {
 		switch imp := st.(type) {
 		case *ast.ImportDeclaration:
+			specifier := imp.ModuleSpecifier
 			if imp.FromClause != nil {
-				result = append(result, imp.FromClause.ModuleSpecifier.String())
-			} else {
-				result = append(result, imp.ModuleSpecifier.String())
+				specifier = imp.FromClause.ModuleSpecifier
 			}
+			result = append(result, ModuleRequest{
+				Specifier:  specifier.String(),
+				Attributes: importAttributesFromAst(imp.Attributes),
+				Phase:      ImportPhase(imp.Phase), // the values of ast.ImportPhase mirror ImportPhase
+			})
 		case *ast.ExportDeclaration:
 			if imp.FromClause != nil {
-				result = append(result, imp.FromClause.ModuleSpecifier.String())
+				result = append(result, ModuleRequest{
+					Specifier:  imp.FromClause.ModuleSpecifier.String(),
+					Attributes: importAttributesFromAst(imp.Attributes),
+				})
 			}
 		}
 	}
@@ -427,11 +460,12 @@ func ModuleFromAST(body *ast.Program, resolveModule HostResolveImportedModuleFun
 				localExportEntries = append(localExportEntries, ee)
 				continue
 			}
-			if ie.importName == "*" {
+			if ie.importName == "*" || ie.importName == "*source*" {
 				localExportEntries = append(localExportEntries, ee)
 			} else {
 				indirectExportEntries = append(indirectExportEntries, exportEntry{
 					moduleRequest: ie.moduleRequest,
+					attributes:    ie.attributes,
 					importName:    ie.importName,
 					exportName:    ee.exportName,
 				})
@@ -513,7 +547,7 @@ func (module *SourceTextModuleRecord) GetExportedNames(callback func([]string),
 	}
 
 	for i, e := range module.starExportEntries {
-		requestedModule, err := module.hostResolveImportedModule(module, e.moduleRequest)
+		requestedModule, err := module.hostResolveImportedModule(module, e.moduleRequest, e.attributes)
 		if err != nil {
 			panic(err)
 		}
@@ -545,7 +579,7 @@ func (module *SourceTextModuleRecord) handleAsyncGeteExportNames(
 	exportedNames []string, remaining []exportEntry, callback func([]string), exportStarSet ...ModuleRecord,
 ) {
 	for _, e := range remaining {
-		requestedModule, err := module.hostResolveImportedModule(module, e.moduleRequest)
+		requestedModule, err := module.hostResolveImportedModule(module, e.moduleRequest, e.attributes)
 		if err != nil {
 			panic(err)
 		}
@@ -625,7 +659,7 @@ func (module *SourceTextModuleRecord) ResolveExport(exportName string, resolvese
 
 	for _, e := range module.indirectExportEntries {
 		if exportName == e.exportName {
-			importedModule, err := module.hostResolveImportedModule(module, e.moduleRequest)
+			importedModule, err := module.hostResolveImportedModule(module, e.moduleRequest, e.attributes)
 			if err != nil {
 				panic(err) // TODO return err
 			}
@@ -648,7 +682,7 @@ func (module *SourceTextModuleRecord) ResolveExport(exportName string, resolvese
 	var starResolution *ResolvedBinding
 
 	for _, e := range module.starExportEntries {
-		importedModule, err := module.hostResolveImportedModule(module, e.moduleRequest)
+		importedModule, err := module.hostResolveImportedModule(module, e.moduleRequest, e.attributes)
 		if err != nil {
 			panic(err) // TODO return err
 		}
@@ -696,6 +730,11 @@ func (module *SourceTextModuleRecord) Link() error {
 	return c.CyclicModuleRecordConcreteLink(module)
 }
 
-func (module *SourceTextModuleRecord) RequestedModules() []string {
+// HasTLA returns whether the module uses top-level await.
+func (module *SourceTextModuleRecord) HasTLA() bool {
+	return module.hasTLA
+}
+
+func (module *SourceTextModuleRecord) RequestedModules() []ModuleRequest {
 	return module.requestedModules
 }
@@ -0,0 +1,121 @@
+package sobek
+
+import "fmt"
+
+// SyntheticModuleRecord is a ModuleRecord whose exports are provided by the host, for example to expose a Go package
+// to JavaScript as `import { readFile } from "host:fs"`.
+// The export names are fixed when the record is created, the values are set by the evaluation steps, which are run
+// once per Runtime when the module is evaluated.
+type SyntheticModuleRecord struct {
+	exportNames     []string
+	evaluationSteps func(*SyntheticModuleInstance) error
+}
+
+var _ ModuleRecord = &SyntheticModuleRecord{}
+
+// NewSyntheticModule creates a SyntheticModuleRecord with the given export names. evaluationSteps is called once per
+// Runtime the module is evaluated in and is expected to set the exports using SyntheticModuleInstance.SetExport.
+// Exports which are not set are undefined and duplicate export names are only exported once. If evaluationSteps
+// returns an error (or throws) the evaluation of the module and every module importing it fails, and evaluating
+// the module again in the same Runtime fails with the same error without running evaluationSteps again.
+// The instance is available to the Runtime while evaluationSteps is running, so that a module which is evaluated
+// again from within its own evaluation steps (for example through a cycle of host loaded modules) gets the partially
+// initialized instance.
+func NewSyntheticModule(exportNames []string, evaluationSteps func(*SyntheticModuleInstance) error) *SyntheticModuleRecord {
+	names := make([]string, 0, len(exportNames))
+	seen := make(map[string]struct{}, len(exportNames))
+	for _, name := range exportNames {
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+	return &SyntheticModuleRecord{
+		exportNames:     names,
+		evaluationSteps: evaluationSteps,
+	}
+}
+
+func (m *SyntheticModuleRecord) GetExportedNames(callback func([]string), _ ...ModuleRecord) bool {
+	callback(m.exportNames)
+	return true
+}
+
+func (m *SyntheticModuleRecord) ResolveExport(exportName string, _ ...ResolveSetElement) (*ResolvedBinding, bool) {
+	for _, name := range m.exportNames {
+		if name == exportName {
+			return &ResolvedBinding{
+				Module:      m,
+				BindingName: exportName,
+			}, false
+		}
+	}
+	return nil, false
+}
+
+func (m *SyntheticModuleRecord) Link() error {
+	return nil
+}
+
+func (m *SyntheticModuleRecord) Evaluate(rt *Runtime) *Promise {
+	p, resolve, reject := rt.NewPromise()
+	if mi, ok := rt.modules[m]; ok {
+		if err := mi.(*SyntheticModuleInstance).evaluationError; err != nil {
+			_ = reject(err)
+		} else {
+			_ = resolve(mi)
+		}
+		return p
+	}
+	mi := &SyntheticModuleInstance{
+		rt:     rt,
+		module: m,
+		values: make(map[string]Value, len(m.exportNames)),
+	}
+	for _, name := range m.exportNames {
+		mi.values[name] = _undefined
+	}
+	rt.setModuleInstance(m, mi)
+	var err error
+	if ex := rt.try(func() {
+		err = m.evaluationSteps(mi)
+	}); ex != nil {
+		err = ex
+	}
+	if err != nil {
+		mi.evaluationError = err
+		_ = reject(err)
+		return p
+	}
+	_ = resolve(mi)
+	return p
+}
+
+// SyntheticModuleInstance is the instance of a SyntheticModuleRecord in a particular Runtime.
+type SyntheticModuleInstance struct {
+	rt     *Runtime
+	module *SyntheticModuleRecord
+	values map[string]Value
+
+	evaluationError error
+}
+
+// Runtime returns the Runtime the module is evaluated in.
+func (mi *SyntheticModuleInstance) Runtime() *Runtime {
+	return mi.rt
+}
+
+// SetExport sets the value of the named export. The value is converted using Runtime.ToValue.
+// Bindings are live, so this can also be called after the evaluation to update the value seen by importing modules.
+// It returns an error if the module has no such export.
+func (mi *SyntheticModuleInstance) SetExport(name string, value interface{}) error {
+	if _, ok := mi.values[name]; !ok {
+		return fmt.Errorf("synthetic module has no export named %q", name)
+	}
+	mi.values[name] = mi.rt.ToValue(value)
+	return nil
+}
+
+func (mi *SyntheticModuleInstance) GetBindingValue(name string) Value {
+	return mi.values[name]
+}
@@ -1,7 +1,9 @@
 package sobek
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -305,6 +307,113 @@ func TestSimpleModule(t *testing.T) {
 			"1-fixture.js": `export var x`,
 			"2-fixture.js": `export var x`,
 		},
+		"json module": {
+			"a.js": `
+				import config from "config.json" with { type: "json" };
+				import { config as again } from "dep.js";
+				if (config === again && config.nested.value === 3) {
+					globalThis.s = config.a + config.nested.value - 1;
+				}
+			`,
+			"dep.js":      `import config from "config.json" with { type: "json" }; export { config };`,
+			"config.json": `{"a": 3, "nested": {"value": 3}}`,
+		},
+		"json module dynamic import": {
+			"a.js": `
+				import("config.json", { with: { type: "json" } }).then(ns => {
+					globalThis.s = ns.default.a;
+				});
+			`,
+			"config.json": `{"a": 5}`,
+		},
+		"import defer": {
+			"a.js": `
+				import defer * as ns from "dep.js";
+				globalThis.log = ["a"];
+				if (Object.prototype.toString.call(ns) === "[object Deferred Module]" && ns.then === undefined) {
+					log.push(ns.value);
+				}
+				if (log.join() === "a,dep,5" && ns.value === 5) {
+					globalThis.s = 5;
+				}
+			`,
+			"dep.js": `
+				globalThis.log.push("dep");
+				export const value = 5;
+				export function then() {}
+			`,
+		},
+		"import defer async dependency": {
+			"a.js": `
+				import defer * as ns from "dep.js";
+				log.push("a");
+				if (ns.value === 5 && log.join() === "tla start,tla,a,dep") {
+					globalThis.s = 5;
+				}
+			`,
+			"dep.js": `
+				import { v } from "tla.js";
+				log.push("dep");
+				export const value = v + 4;
+			`,
+			"tla.js": `
+				globalThis.log = ["tla start"];
+				await 1;
+				log.push("tla");
+				export const v = 1;
+			`,
+		},
+		"dynamic import defer": {
+			"a.js": `
+				globalThis.log = ["a"];
+				import.defer("dep.js").then(ns => {
+					log.push(Object.prototype.toString.call(ns));
+					log.push(ns.value);
+					if (log.join() === "a,[object Deferred Module],dep,5") {
+						globalThis.s = 5;
+					}
+				});
+			`,
+			"dep.js": `
+				globalThis.log.push("dep");
+				export const value = 5;
+			`,
+		},
+		"dynamic import defer async dependency": {
+			"a.js": `
+				globalThis.log = [];
+				import.defer("dep.js").then(ns => {
+					log.push("a");
+					if (ns.value === 5 && log.join() === "tla,a,dep") {
+						globalThis.s = 5;
+					}
+				});
+			`,
+			"dep.js": `
+				import { v } from "tla.js";
+				log.push("dep");
+				export const value = v + 4;
+			`,
+			"tla.js": `
+				await 1;
+				log.push("tla");
+				export const v = 1;
+			`,
+		},
+		"dynamic import invalid attributes": {
+			"a.js": `
+				Promise.all([
+					import("config.json", 1).catch(e => e instanceof TypeError),
+					import("config.json", { with: { type: 1 } }).catch(e => e instanceof TypeError),
+					import("config.json", { with: { foo: "bar" } }).catch(e => e instanceof TypeError),
+				]).then(res => {
+					if (res.every(r => r === true)) {
+						globalThis.s = 5;
+					}
+				});
+			`,
+			"config.json": `{}`,
+		},
 	}
 	for name, cases := range testCases {
 		cases := cases
@@ -338,10 +447,14 @@ func runModules(t testing.TB, files map[string]string) func(*Runtime) *Promise {
 	}
 	mu := sync.Mutex{}
 	cache := make(map[string]cacheElement)
-	var hostResolveImportedModule func(referencingScriptOrModule interface{}, specifier string) (ModuleRecord, error)
-	hostResolveImportedModule = func(_ interface{}, specifier string) (ModuleRecord, error) {
+	var hostResolveImportedModule func(referencingScriptOrModule interface{}, specifier string, attributes []ImportAttribute) (ModuleRecord, error)
+	hostResolveImportedModule = func(_ interface{}, specifier string, attributes []ImportAttribute) (ModuleRecord, error) {
 		mu.Lock()
 		defer mu.Unlock()
+		isJSON := strings.HasSuffix(specifier, ".json")
+		if isJSON != (len(attributes) == 1 && attributes[0].Value == "json") {
+			return nil, fmt.Errorf("wrong import attributes %v for %q", attributes, specifier)
+		}
 		k, ok := cache[specifier]
 		if ok {
 			return k.m, k.err
@@ -351,7 +464,13 @@ func runModules(t testing.TB, files map[string]string) func(*Runtime) *Promise {
 		if !ok {
 			return nil, fmt.Errorf("can't find %q from files", specifier)
 		}
-		p, err := ParseModule(specifier, src, hostResolveImportedModule)
+		var p ModuleRecord
+		var err error
+		if isJSON {
+			p, err = NewJSONModule(src)
+		} else {
+			p, err = ParseModule(specifier, src, hostResolveImportedModule)
+		}
 		if err != nil {
 			cache[specifier] = cacheElement{err: err}
 			return nil, err
@@ -373,7 +492,7 @@ func runModules(t testing.TB, files map[string]string) func(*Runtime) *Promise {
 		return err
 	}
 
-	m, err := hostResolveImportedModule(nil, "a.js")
+	m, err := hostResolveImportedModule(nil, "a.js", nil)
 	if err != nil {
 		t.Fatalf("got error %s", err)
 	}
@@ -386,12 +505,12 @@ func runModules(t testing.TB, files map[string]string) func(*Runtime) *Promise {
 
 	return func(vm *Runtime) *Promise {
 		eventLoopQueue := make(chan func(), 2) // the most basic and likely buggy event loop
-		vm.SetImportModuleDynamically(func(referencingScriptOrModule interface{}, specifierValue Value, pcap interface{}) {
+		vm.SetImportModuleDynamically(func(referencingScriptOrModule interface{}, specifierValue Value, attributes []ImportAttribute, _ ImportPhase, pcap interface{}) {
 			specifier := specifierValue.String()
 
 			eventLoopQueue <- func() {
 				ex := vm.runWrapped(func() {
-					m, err := hostResolveImportedModule(referencingScriptOrModule, specifier)
+					m, err := hostResolveImportedModule(referencingScriptOrModule, specifier, attributes)
 					vm.FinishLoadingImportModule(referencingScriptOrModule, specifierValue, pcap, m, err)
 				})
 				if ex != nil {
@@ -415,6 +534,275 @@ func runModules(t testing.TB, files map[string]string) func(*Runtime) *Promise {
 	}
 }
 
+type testSourceModule struct {
+	evaluated bool
+}
+
+func (m *testSourceModule) GetExportedNames(callback func([]string), _ ...ModuleRecord) bool {
+	callback(nil)
+	return true
+}
+
+func (m *testSourceModule) ResolveExport(string, ...ResolveSetElement) (*ResolvedBinding, bool) {
+	return nil, false
+}
+
+func (m *testSourceModule) Link() error {
+	return nil
+}
+
+func (m *testSourceModule) Evaluate(rt *Runtime) *Promise {
+	m.evaluated = true
+	p, res, _ := rt.NewPromise()
+	_ = res(nil)
+	return p
+}
+
+func (m *testSourceModule) GetModuleSource(rt *Runtime) Value {
+	return rt.ToValue("the source")
+}
+
+func TestJSONModuleEvaluate(t *testing.T) {
+	t.Parallel()
+	m, err := NewJSONModule(`{"a": [1, 2]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := New()
+	promise := m.Evaluate(vm)
+	if promise.state != PromiseStateFulfilled {
+		t.Fatalf("got %+v", promise.Result().Export())
+	}
+	value := vm.NamespaceObjectFor(m).Get("default")
+	if v := value.ToObject(vm).Get("a").ToObject(vm).Get("1"); v.ToInteger() != 2 {
+		t.Fatalf("unexpected value %v", v)
+	}
+	promise = m.Evaluate(vm)
+	if promise.state != PromiseStateFulfilled || vm.NamespaceObjectFor(m).Get("default") != value {
+		t.Fatalf("expected the same instance, got %+v", promise.Result().Export())
+	}
+	if _, err = NewJSONModule(`{"a": `); err == nil {
+		t.Fatal("expected an error for invalid JSON")
+	}
+}
+
+func TestSourcePhaseImport(t *testing.T) {
+	t.Parallel()
+	files := map[string]string{
+		"a.js": `
+			import source s from "lib.wasm";
+			import source s2 from "lib.wasm";
+			import source from "c.js";
+			export { s };
+			globalThis.s = s;
+			globalThis.def = source;
+		`,
+		"b.js": `import source x from "c.js";`,
+		"c.js": `export default 5;`,
+		"d.js": `
+			import.source("lib.wasm").then(s => {
+				globalThis.ds = s;
+				return import.source("c.js");
+			}).catch(e => {
+				globalThis.de = e;
+			});
+		`,
+	}
+	source := &testSourceModule{}
+	cache := make(map[string]ModuleRecord)
+	var resolve HostResolveImportedModuleFunc
+	resolve = func(_ interface{}, specifier string, _ []ImportAttribute) (ModuleRecord, error) {
+		if specifier == "lib.wasm" {
+			return source, nil
+		}
+		if m, ok := cache[specifier]; ok {
+			return m, nil
+		}
+		m, err := ParseModule(specifier, files[specifier], resolve)
+		if err != nil {
+			return nil, err
+		}
+		cache[specifier] = m
+		return m, nil
+	}
+
+	m, err := resolve(nil, "a.js", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = m.Link(); err != nil {
+		t.Fatal(err)
+	}
+	vm := New()
+	promise := m.Evaluate(vm)
+	if promise.state != PromiseStateFulfilled {
+		t.Fatalf("got %+v", promise.Result().Export())
+	}
+	if v := vm.Get("s"); v == nil || v.String() != "the source" {
+		t.Fatalf("unexpected source %v", v)
+	}
+	if v := vm.Get("def"); v == nil || v.ToInteger() != 5 {
+		t.Fatalf("unexpected default import %v", v)
+	}
+	if ns := vm.NamespaceObjectFor(m); ns.Get("s").String() != "the source" {
+		t.Fatalf("unexpected exported source %v", ns.Get("s"))
+	}
+	if source.evaluated {
+		t.Fatal("source phase import was evaluated")
+	}
+
+	m, err = resolve(nil, "b.js", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = m.Link(); err != nil {
+		t.Fatal(err)
+	}
+	promise = m.Evaluate(vm)
+	if promise.state != PromiseStateRejected {
+		t.Fatalf("expected promise to be rejected %q", promise.state)
+	}
+	exc := promise.Result().Export().(*Exception)
+	if !strings.Contains(exc.String(), `SyntaxError: The requested module "c.js" does not provide a source representation`) {
+		t.Fatalf("unexpected error %q", exc.String())
+	}
+
+	vm.SetImportModuleDynamically(func(referencingScriptOrModule interface{}, specifier Value, attributes []ImportAttribute, phase ImportPhase, pcap interface{}) {
+		if phase != SourcePhase {
+			t.Errorf("unexpected phase %d", phase)
+		}
+		m, err := resolve(referencingScriptOrModule, specifier.String(), attributes)
+		vm.FinishLoadingImportModule(referencingScriptOrModule, specifier, pcap, m, err)
+	})
+	m, err = resolve(nil, "d.js", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = m.Link(); err != nil {
+		t.Fatal(err)
+	}
+	if promise = m.Evaluate(vm); promise.state != PromiseStateFulfilled {
+		t.Fatalf("got %+v", promise.Result().Export())
+	}
+	if v := vm.Get("ds"); v == nil || v.String() != "the source" {
+		t.Fatalf("unexpected dynamically imported source %v", v)
+	}
+	if v := vm.Get("de"); v == nil || v.String() != `SyntaxError: The requested module "c.js" does not provide a source representation` {
+		t.Fatalf("unexpected error %v", v)
+	}
+	if source.evaluated {
+		t.Fatal("source phase import was evaluated")
+	}
+}
+
+func TestSyntheticModule(t *testing.T) {
+	t.Parallel()
+	var instances []*SyntheticModuleInstance
+	fs := NewSyntheticModule([]string{"readFile", "counter", "unset"}, func(mi *SyntheticModuleInstance) error {
+		instances = append(instances, mi)
+		if err := mi.SetExport("readFile", func(name string) string {
+			return "contents of " + name
+		}); err != nil {
+			return err
+		}
+		if err := mi.SetExport("missing", 1); err == nil {
+			return errors.New("expected an error for a missing export")
+		}
+		return mi.SetExport("counter", 1)
+	})
+	brokenEvaluations := 0
+	broken := NewSyntheticModule([]string{"x"}, func(mi *SyntheticModuleInstance) error {
+		brokenEvaluations++
+		panic(mi.Runtime().NewTypeError("broken module"))
+	})
+	files := map[string]string{
+		"a.js": `
+			import { readFile, counter, unset } from "host:fs";
+			import * as ns from "host:fs";
+			export function getCounter() { return counter; }
+			if (readFile("a.txt") !== "contents of a.txt" || unset !== undefined || ns.counter !== 1) {
+				throw new Error("unexpected exports");
+			}
+		`,
+		"b.js": `import { x } from "host:broken";`,
+	}
+	var resolve HostResolveImportedModuleFunc
+	resolve = func(_ interface{}, specifier string, _ []ImportAttribute) (ModuleRecord, error) {
+		switch specifier {
+		case "host:fs":
+			return fs, nil
+		case "host:broken":
+			return broken, nil
+		}
+		return ParseModule(specifier, files[specifier], resolve)
+	}
+
+	m, err := resolve(nil, "a.js", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = m.Link(); err != nil {
+		t.Fatal(err)
+	}
+	vm := New()
+	promise := m.Evaluate(vm)
+	if promise.state != PromiseStateFulfilled {
+		t.Fatalf("got %+v", promise.Result().Export())
+	}
+	if len(instances) != 1 || instances[0].Runtime() != vm {
+		t.Fatalf("unexpected instances %v", instances)
+	}
+	_ = instances[0].SetExport("counter", 2)
+	getCounter, _ := AssertFunction(vm.NamespaceObjectFor(m).Get("getCounter"))
+	if v, err := getCounter(nil); err != nil || v.ToInteger() != 2 {
+		t.Fatalf("expected a live binding, got %v, %v", v, err)
+	}
+	if v := vm.NamespaceObjectFor(fs).Get("counter"); v.ToInteger() != 2 {
+		t.Fatalf("unexpected namespace value %v", v)
+	}
+
+	for i := 0; i < 2; i++ {
+		// a new importer gets the cached evaluation error
+		m, err = resolve(nil, "b.js", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = m.Link(); err != nil {
+			t.Fatal(err)
+		}
+		promise = m.Evaluate(vm)
+		if promise.state != PromiseStateRejected {
+			t.Fatalf("expected promise to be rejected %q", promise.state)
+		}
+		if exc := promise.Result().Export().(*Exception); exc.Value().String() != "TypeError: broken module" {
+			t.Fatalf("unexpected error %q", exc)
+		}
+	}
+	if brokenEvaluations != 1 {
+		t.Fatalf("broken module evaluated %d times", brokenEvaluations)
+	}
+}
+
+func TestSyntheticModuleDuplicateExports(t *testing.T) {
+	t.Parallel()
+	m := NewSyntheticModule([]string{"a", "b", "a"}, func(mi *SyntheticModuleInstance) error {
+		return mi.SetExport("a", 1)
+	})
+	var names []string
+	m.GetExportedNames(func(n []string) { names = n })
+	if len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Fatalf("unexpected export names %v", names)
+	}
+	vm := New()
+	if promise := m.Evaluate(vm); promise.state != PromiseStateFulfilled {
+		t.Fatalf("got %+v", promise.Result().Export())
+	}
+	keys := vm.NamespaceObjectFor(m).Keys()
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Fatalf("unexpected namespace keys %v", keys)
+	}
+}
+
 func TestAmbiguousImport(t *testing.T) {
 	t.Parallel()
 	fn := runModules(t, map[string]string{
@@ -10,33 +10,41 @@ import (
 )
 
 const (
-	classObject        = "Object"
-	classArray         = "Array"
-	classWeakSet       = "WeakSet"
-	classWeakMap       = "WeakMap"
-	classMap           = "Map"
-	classMath          = "Math"
-	classSet           = "Set"
-	classFunction      = "Function"
-	classAsyncFunction = "AsyncFunction"
-	classNumber        = "Number"
-	classString        = "String"
-	classBoolean       = "Boolean"
-	classError         = "Error"
-	classRegExp        = "RegExp"
-	classDate          = "Date"
-	classJSON          = "JSON"
-	classGlobal        = "global"
-	classPromise       = "Promise"
-
+	classObject               = "Object"
+	classArray                = "Array"
+	classWeakSet              = "WeakSet"
+	classWeakMap              = "WeakMap"
+	classWeakRef              = "WeakRef"
+	classFinalizationRegistry = "FinalizationRegistry"
+	classDisposableStack      = "DisposableStack"
+	classAsyncDisposableStack = "AsyncDisposableStack"
+	classMap                  = "Map"
+	classMath                 = "Math"
+	classSet                  = "Set"
+	classFunction             = "Function"
+	classAsyncFunction        = "AsyncFunction"
+	classNumber               = "Number"
+	classString               = "String"
+	classBoolean              = "Boolean"
+	classError                = "Error"
+	classRegExp               = "RegExp"
+	classDate                 = "Date"
+	classJSON                 = "JSON"
+	classGlobal               = "global"
+	classPromise              = "Promise"
+
+	classIterator             = "Iterator"
+	classIteratorHelper       = "Iterator Helper"
 	classArrayIterator        = "Array Iterator"
 	classMapIterator          = "Map Iterator"
 	classSetIterator          = "Set Iterator"
 	classStringIterator       = "String Iterator"
 	classRegExpStringIterator = "RegExp String Iterator"
 
-	classGenerator         = "Generator"
-	classGeneratorFunction = "GeneratorFunction"
+	classGenerator              = "Generator"
+	classGeneratorFunction      = "GeneratorFunction"
+	classAsyncGenerator         = "AsyncGenerator"
+	classAsyncGeneratorFunction = "AsyncGeneratorFunction"
 )
 
 var (
@@ -16,6 +16,12 @@ type JsonEncodable interface {
 	JsonEncodable() interface{}
 }
 
+// Disposable allows a Go value to be used in 'using' declarations and with DisposableStack. The Dispose method
+// is exposed as [Symbol.dispose](). If it returns an error, it is thrown as a GoError.
+type Disposable interface {
+	Dispose() error
+}
+
 // FieldNameMapper provides custom mapping between Go and JavaScript property names.
 type FieldNameMapper interface {
 	// FieldName returns a JavaScript name for the given struct field in the given type.
@@ -190,6 +196,16 @@ func (o *objectGoReflect) init() {
 	if j, ok := o.origValue.Interface().(JsonEncodable); ok {
 		o.toJson = j.JsonEncodable
 	}
+
+	if d, ok := o.origValue.Interface().(Disposable); ok {
+		r := o.val.runtime
+		o._putSym(SymDispose, valueProp(r.newNativeFunc(func(FunctionCall) Value {
+			if err := d.Dispose(); err != nil {
+				panic(r.NewGoError(err))
+			}
+			return _undefined
+		}, "[Symbol.dispose]", 0), true, false, true))
+	}
 }
 
 func (o *objectGoReflect) getStr(name unistring.String, receiver Value) Value {
@@ -474,6 +474,11 @@ func (self *_parser) parseObjectProperty() ast.Property {
 				self.errorUnexpectedToken(self.token)
 			}
 		case (literal == "get" || literal == "set" || tkn == token.ASYNC) && self.token != token.COLON:
+			generator := false
+			if tkn == token.ASYNC && self.token == token.MULTIPLY {
+				generator = true
+				self.next()
+			}
 			_, _, keyValue, tkn1 := self.parseObjectPropertyKey()
 			if keyValue == nil {
 				return nil
@@ -493,7 +498,7 @@ func (self *_parser) parseObjectProperty() ast.Property {
 			return &ast.PropertyKeyed{
 				Key:      keyValue,
 				Kind:     kind,
-				Value:    self.parseMethodDefinition(keyStartIdx, kind, false, async),
+				Value:    self.parseMethodDefinition(keyStartIdx, kind, generator, async),
 				Computed: tkn1 == token.ILLEGAL,
 			}
 		}
@@ -790,19 +795,30 @@ L:
 
 func (self *_parser) parseImportExpression() ast.Expression {
 	idx := self.idx
-	if self.peek() == token.LEFT_PARENTHESIS {
+	phase := self.peekDynamicImportPhase()
+	if phase != ast.ImportPhaseEvaluation || self.peek() == token.LEFT_PARENTHESIS {
 		self.expect(token.IMPORT)
-		cexp := self.parseCallExpression(&ast.DynamicImportExpression{})
-		if len(cexp.ArgumentList) != 1 {
-			self.error(self.idx, "dynamic import requires exactly one argument")
+		if phase != ast.ImportPhaseEvaluation {
+			self.expect(token.PERIOD)
+			self.next()
+		}
+		cexp := self.parseCallExpression(&ast.DynamicImportExpression{Idx: idx, Phase: phase})
+		if len(cexp.ArgumentList) != 1 && len(cexp.ArgumentList) != 2 {
+			self.error(self.idx, "dynamic import requires one or two arguments")
 			return &ast.BadExpression{From: idx, To: self.idx}
 		}
-
-		if _, ok := cexp.ArgumentList[0].(*ast.SpreadElement); ok {
-			self.error(self.idx, "dynamic import can't use spread list")
+		if phase != ast.ImportPhaseEvaluation && len(cexp.ArgumentList) != 1 {
+			self.error(self.idx, "dynamic import with a phase requires one argument")
 			return &ast.BadExpression{From: idx, To: self.idx}
 		}
 
+		for _, arg := range cexp.ArgumentList {
+			if _, ok := arg.(*ast.SpreadElement); ok {
+				self.error(self.idx, "dynamic import can't use spread list")
+				return &ast.BadExpression{From: idx, To: self.idx}
+			}
+		}
+
 		return cexp
 	}
 	if self.opts.module {
@@ -813,6 +829,27 @@ func (self *_parser) parseImportExpression() ast.Expression {
 	return &ast.BadExpression{From: idx, To: self.idx}
 }
 
+// peekDynamicImportPhase returns the phase of an `import.source(...)` or `import.defer(...)` call which starts at the
+// current token without consuming anything. For anything else, including import.meta, it returns ImportPhaseEvaluation.
+func (self *_parser) peekDynamicImportPhase() ast.ImportPhase {
+	if self.peek() != token.PERIOD {
+		return ast.ImportPhaseEvaluation
+	}
+	state := self.mark(nil)
+	defer self.restore(state)
+	self.next()
+	self.next()
+	if self.token == token.IDENTIFIER && self.peek() == token.LEFT_PARENTHESIS {
+		switch self.literal {
+		case "source":
+			return ast.ImportPhaseSource
+		case "defer":
+			return ast.ImportPhaseDefer
+		}
+	}
+	return ast.ImportPhaseEvaluation
+}
+
 func (self *_parser) parseLeftHandSideExpressionAllowCall() ast.Expression {
 	allowIn := self.scope.allowIn
 	self.scope.allowIn = true
@@ -942,17 +979,7 @@ func (self *_parser) parseUnaryExpression() ast.Expression {
 			if self.scope.inFuncParams {
 				self.error(idx, "Illegal await-expression in formal parameters of async function")
 			}
-			scope := self.scope
-			for scope != nil {
-				if scope.inFunction {
-					break
-				}
-				if scope.outer == nil {
-					scope.hasTLA = true
-					break
-				}
-				scope = scope.outer
-			}
+			self.markTopLevelAwait()
 			return &ast.AwaitExpression{
 				Await:    idx,
 				Argument: self.parseUnaryExpression(),
@@ -1338,3 +1338,114 @@ func TestParseTemplateLiteralWithTail(t *testing.T) {
 		t.Fatal(prg.Body[0])
 	}
 }
+
+func TestParseImportAttributes(t *testing.T) {
+	prg, err := ParseFile(nil, "", `
+import a from "a.json" with { type: "json" };
+import "b.json" with { "type": "json", };
+export * from "c.json" with { type: "json" }
+export { d } from "d.json" assert { type: "json" }
+import e from "e.js";
+`, 0, IsModule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{1, 1, 1, 1, 0}
+	for i, st := range prg.Body {
+		var attributes []*ast.ImportAttribute
+		switch st := st.(type) {
+		case *ast.ImportDeclaration:
+			attributes = st.Attributes
+		case *ast.ExportDeclaration:
+			attributes = st.Attributes
+		default:
+			t.Fatalf("%d: unexpected statement %T", i, st)
+		}
+		if len(attributes) != expected[i] {
+			t.Fatalf("%d: expected %d attributes, got %d", i, expected[i], len(attributes))
+		}
+		for _, attr := range attributes {
+			if attr.Key != "type" || attr.Value != "json" {
+				t.Fatalf("%d: unexpected attribute %s: %s", i, attr.Key, attr.Value)
+			}
+		}
+	}
+
+	for src, expectedErr := range map[string]string{
+		`import a from "a.json" with { type: "json", type: "json" };`: `Duplicate import attribute 'type'`,
+		`import a from "a.json" with { foo: "bar" };`:                 `Unsupported import attribute 'foo'`,
+		`import a from "a.json" with { type: json };`:                 `Import attribute value must be a string`,
+		`import a from "a.json" with { type: "json" } b;`:             `Unexpected identifier`,
+		`import("a.json", {}, {});`:                                   `dynamic import requires one or two arguments`,
+	} {
+		_, err := ParseFile(nil, "", src, 0, IsModule)
+		if err == nil || !strings.Contains(err.Error(), expectedErr) {
+			t.Fatalf("%s: expected error %q, got %v", src, expectedErr, err)
+		}
+	}
+	if _, err := ParseFile(nil, "", `import("a.json", { with: { type: "json" } },);`, 0, IsModule); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseImportPhase(t *testing.T) {
+	for src, expected := range map[string]ast.ImportPhase{
+		`import source x from "x";`:      ast.ImportPhaseSource,
+		`import source from from "x";`:   ast.ImportPhaseSource,
+		`import source from "x";`:        ast.ImportPhaseEvaluation,
+		`import source, { a } from "x";`: ast.ImportPhaseEvaluation,
+		`import defer * as ns from "x";`: ast.ImportPhaseDefer,
+		`import defer from "x";`:         ast.ImportPhaseEvaluation,
+		`import * as defer from "x";`:    ast.ImportPhaseEvaluation,
+	} {
+		prg, err := ParseFile(nil, "", src, 0, IsModule)
+		if err != nil {
+			t.Fatalf("%s: %v", src, err)
+		}
+		if phase := prg.Body[0].(*ast.ImportDeclaration).Phase; phase != expected {
+			t.Fatalf("%s: expected phase %d, got %d", src, expected, phase)
+		}
+	}
+	for _, src := range []string{
+		`import defer x from "x";`,
+		`import source { x } from "x";`,
+		`import source x, { y } from "x";`,
+	} {
+		if _, err := ParseFile(nil, "", src, 0, IsModule); err == nil {
+			t.Fatalf("%s: expected an error", src)
+		}
+	}
+}
+
+func TestParseDynamicImportPhase(t *testing.T) {
+	for src, expected := range map[string]ast.ImportPhase{
+		`import("x");`:        ast.ImportPhaseEvaluation,
+		`import.source("x");`: ast.ImportPhaseSource,
+		`import.defer("x");`:  ast.ImportPhaseDefer,
+	} {
+		for _, opts := range [][]Option{nil, {IsModule}} {
+			prg, err := ParseFile(nil, "", src, 0, opts...)
+			if err != nil {
+				t.Fatalf("%s: %v", src, err)
+			}
+			call := prg.Body[0].(*ast.ExpressionStatement).Expression.(*ast.CallExpression)
+			if phase := call.Callee.(*ast.DynamicImportExpression).Phase; phase != expected {
+				t.Fatalf("%s: expected phase %d, got %d", src, expected, phase)
+			}
+		}
+	}
+	if _, err := ParseFile(nil, "", `import.meta.url;`, 0, IsModule); err != nil {
+		t.Fatal(err)
+	}
+	for _, src := range []string{
+		`import.source;`,
+		`import.defer.x("x");`,
+		`import.source("x", {});`,
+		`(import.defer)("x");`,
+		`new import.defer("x");`,
+	} {
+		if _, err := ParseFile(nil, "", src, 0, IsModule); err == nil {
+			t.Fatalf("%s: expected an error", src)
+		}
+	}
+}
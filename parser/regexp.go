@@ -2,8 +2,12 @@ package parser
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf16"
 	"unicode/utf8"
 )
 
@@ -72,12 +76,83 @@ func TransformRegExp(pattern string, dotAll, unicode bool) (transformed string,
 	}
 	err = parser.parse()
 	if err != nil {
+		if _, incompat := err.(RegexpErrorIncompatible); incompat {
+			// the rest of the pattern has not been parsed, but it still must not contain
+			// the groups that are valid in regexp2 and invalid in JavaScript
+			if err1 := checkRegExpGroups(pattern); err1 != nil {
+				return "", err1
+			}
+		}
 		return "", err
 	}
 
 	return parser.ResultString(), nil
 }
 
+// checkRegExpGroups checks the syntax of the (?...) groups in the pattern.
+func checkRegExpGroups(pattern string) error {
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '(':
+			if inClass || !strings.HasPrefix(pattern[i+1:], "?") || len(pattern) < i+3 {
+				continue
+			}
+			switch pattern[i+2] {
+			case ':', '=', '!', '<':
+				continue
+			}
+			if _, _, _, ok := parseRegExpModifiers(pattern[i+2:]); !ok {
+				return RegexpSyntaxError{regexpParseError{offset: i, err: "Invalid group"}}
+			}
+		}
+	}
+	return nil
+}
+
+// parseRegExpModifiers parses the modifiers of a (?ims-ims:...) group, s must start right after '?'.
+// The length includes the terminating ':'.
+func parseRegExpModifiers(s string) (add, remove string, length int, ok bool) {
+	var seen [3]bool
+	removing := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case 'i', 'm', 's':
+			idx := strings.IndexByte("ims", c)
+			if seen[idx] {
+				return
+			}
+			seen[idx] = true
+		case '-':
+			if removing {
+				return
+			}
+			removing = true
+			add = s[:i]
+			continue
+		case ':':
+			if removing {
+				remove = s[len(add)+1 : i]
+			} else {
+				add = s[:i]
+			}
+			ok = add != "" || remove != ""
+			length = i + 1
+			return
+		default:
+			return
+		}
+	}
+	return
+}
+
 func (self *_RegExp_parser) ResultString() string {
 	if self.passOffset != -1 {
 		return self.str[:self.passOffset]
@@ -184,8 +259,30 @@ func (self *_RegExp_parser) scanGroup() {
 				self.pass()         // <
 				self.scanGroupName()
 			case ch != ':':
-				self.error(true, "Invalid group")
-				return
+				add, remove, length, ok := parseRegExpModifiers(str[1:])
+				if !ok {
+					self.error(true, "Invalid group")
+					return
+				}
+				// Go needs the flags to be non-empty on both sides of '-'
+				self.writeByte('?')
+				self.writeString(add)
+				if remove != "" {
+					self.writeByte('-')
+					self.writeString(remove)
+				}
+				self.writeByte(':')
+				for i := 0; i <= length; i++ {
+					self.read()
+				}
+				// the dot is translated depending on the dotAll mode
+				if strings.IndexByte(add, 's') != -1 {
+					defer func(dotAll bool) { self.dotAll = dotAll }(self.dotAll)
+					self.dotAll = true
+				} else if strings.IndexByte(remove, 's') != -1 {
+					defer func(dotAll bool) { self.dotAll = dotAll }(self.dotAll)
+					self.dotAll = false
+				}
 			}
 		}
 	}
@@ -391,6 +488,14 @@ func (self *_RegExp_parser) scanEscape(inClass bool) {
 		}
 		self.read()
 		return
+	case 'p', 'P':
+		if self.unicode {
+			// Only the properties which are not expanded by ExpandUnicodeClasses() remain at this point
+			self.error(false, "Unicode property escape")
+			return
+		}
+		self.pass()
+		return
 	case 'k':
 		// The rules are too complicated to implement here, so we pass it on to regexp2
 		self.error(false, "named group back-reference")
@@ -498,3 +603,898 @@ func (self *_RegExp_parser) error(fatal bool, msg string, msgValues ...interface
 	self.offset = self.length
 	self.chr = -1
 }
+
+// classSet is the value of a character class with the v flag: a set of code points and a set of strings.
+type classSet struct {
+	chars   codePointSet
+	strings map[string]struct{} // contains strings which are not exactly one code point long
+	// native contains the property escapes which are passed to regexp2 as is, because the unicode package
+	// lacks the data.
+	native            string
+	mayContainStrings bool
+}
+
+func newClassSetChar(c rune) *classSet {
+	return &classSet{
+		chars: codePointSet{{c, c}},
+	}
+}
+
+func (s *classSet) addString(str string) {
+	if utf8.RuneCountInString(str) == 1 {
+		c, _ := utf8.DecodeRuneInString(str)
+		s.chars = s.chars.union(codePointSet{{c, c}})
+		return
+	}
+	if s.strings == nil {
+		s.strings = make(map[string]struct{})
+	}
+	s.strings[str] = struct{}{}
+	s.mayContainStrings = true
+}
+
+func (s *classSet) union(other *classSet) {
+	s.chars = s.chars.union(other.chars)
+	for str := range other.strings {
+		s.addString(str)
+	}
+	s.native += other.native
+	s.mayContainStrings = s.mayContainStrings || other.mayContainStrings
+}
+
+func (s *classSet) intersect(other *classSet) {
+	s.chars = s.chars.intersect(other.chars)
+	for str := range s.strings {
+		if _, exists := other.strings[str]; !exists {
+			delete(s.strings, str)
+		}
+	}
+	s.mayContainStrings = s.mayContainStrings && other.mayContainStrings
+}
+
+func (s *classSet) subtract(other *classSet) {
+	s.chars = s.chars.subtract(other.chars)
+	for str := range other.strings {
+		delete(s.strings, str)
+	}
+}
+
+// caseFold closes the code points of the set under simple case folding and folds its strings, which is what
+// MaybeSimpleCaseFolding amounts to when the set is matched ignoring case.
+func (s *classSet) caseFold() {
+	s.chars = s.chars.caseClosure()
+	if len(s.strings) > 0 {
+		folded := make(map[string]struct{}, len(s.strings))
+		for str := range s.strings {
+			folded[strings.Map(simpleCaseFold, str)] = struct{}{}
+		}
+		s.strings = folded
+	}
+}
+
+func writeRegExpChar(sb *strings.Builder, c rune) {
+	if c < utf8.RuneSelf && (c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+		sb.WriteRune(c)
+		return
+	}
+	sb.WriteString(`\u{`)
+	sb.WriteString(strconv.FormatInt(int64(c), 16))
+	sb.WriteByte('}')
+}
+
+func (s *classSet) writeClass(sb *strings.Builder, negated bool) {
+	sb.WriteByte('[')
+	if negated {
+		sb.WriteByte('^')
+	}
+	for _, r := range s.chars {
+		writeRegExpChar(sb, r.lo)
+		if r.hi > r.lo {
+			if r.hi > r.lo+1 {
+				sb.WriteByte('-')
+			}
+			writeRegExpChar(sb, r.hi)
+		}
+	}
+	sb.WriteString(s.native)
+	sb.WriteByte(']')
+}
+
+// String returns the u flag syntax for the set. The strings are tried first, longest to shortest, followed by
+// the code points, followed by the empty string.
+func (s *classSet) String(negated bool) string {
+	var sb strings.Builder
+	if len(s.strings) == 0 {
+		s.writeClass(&sb, negated)
+		return sb.String()
+	}
+	list := make([]string, 0, len(s.strings))
+	hasEmpty := false
+	for str := range s.strings {
+		if str == "" {
+			hasEmpty = true
+		} else {
+			list = append(list, str)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		li, lj := utf8.RuneCountInString(list[i]), utf8.RuneCountInString(list[j])
+		if li != lj {
+			return li > lj
+		}
+		return list[i] < list[j]
+	})
+	sb.WriteString("(?:")
+	for i, str := range list {
+		if i > 0 {
+			sb.WriteByte('|')
+		}
+		for _, c := range str {
+			writeRegExpChar(&sb, c)
+		}
+	}
+	if len(s.chars) > 0 || s.native != "" {
+		if len(list) > 0 {
+			sb.WriteByte('|')
+		}
+		s.writeClass(&sb, false)
+	}
+	if hasEmpty {
+		sb.WriteByte('|')
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
+
+type _RegExp_unicodeExpander struct {
+	str         string
+	pos         int // The offset of the next character
+	copied      int // The offset up to which str has been copied into the result
+	unicodeSets bool
+	ignoreCase  bool
+
+	result strings.Builder
+	err    error
+}
+
+// ExpandUnicodeClasses rewrites a pattern that has the u or the v flag, so that it can be handled by
+// TransformRegExp() and by regexp2 in the Unicode mode. The Unicode property escapes (\p{...} and \P{...})
+// are replaced with explicit character classes built from the tables in the unicode package and, if
+// unicodeSets is true, the character classes using the v flag syntax (nested classes, the -- and &&
+// operators and \q{...}) are replaced with their u flag equivalents.
+//
+// If ignoreCase is true, the sets of the v flag classes are closed under simple case folding before the set
+// operations and the complements are applied, as the sets are case folded in the v flag mode.
+//
+// The Emoji properties are left for regexp2 to handle, so they cannot be used in set operations. A few properties,
+// for which there is no data available (Script_Extensions, Bidi_Mirrored, Changes_When_NFKC_Casefolded and the
+// properties of strings other than Emoji_Keycap_Sequence), are not supported.
+//
+// If the pattern is invalid, an error of type RegexpSyntaxError is returned.
+func ExpandUnicodeClasses(pattern string, unicodeSets, ignoreCase bool) (string, error) {
+	e := _RegExp_unicodeExpander{
+		str:         pattern,
+		unicodeSets: unicodeSets,
+		ignoreCase:  ignoreCase,
+	}
+	e.expand()
+	if e.err != nil {
+		return "", e.err
+	}
+	if e.copied == 0 {
+		return pattern, nil
+	}
+	e.result.WriteString(pattern[e.copied:])
+	return e.result.String(), nil
+}
+
+func (self *_RegExp_unicodeExpander) peek() rune {
+	if self.pos >= len(self.str) {
+		return -1
+	}
+	chr, _ := utf8.DecodeRuneInString(self.str[self.pos:])
+	return chr
+}
+
+func (self *_RegExp_unicodeExpander) next() rune {
+	if self.pos >= len(self.str) {
+		return -1
+	}
+	chr, width := utf8.DecodeRuneInString(self.str[self.pos:])
+	self.pos += width
+	return chr
+}
+
+func (self *_RegExp_unicodeExpander) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(self.str[self.pos:], prefix)
+}
+
+// replace replaces the part of the pattern between start and the current position with s.
+func (self *_RegExp_unicodeExpander) replace(start int, s string) {
+	if self.copied == 0 {
+		self.result.Grow(len(self.str) + len(s))
+	}
+	self.result.WriteString(self.str[self.copied:start])
+	self.result.WriteString(s)
+	self.copied = self.pos
+}
+
+func (self *_RegExp_unicodeExpander) error(msg string, msgValues ...interface{}) {
+	if self.err != nil {
+		return
+	}
+	self.err = RegexpSyntaxError{regexpParseError{
+		offset: self.pos,
+		err:    fmt.Sprintf(msg, msgValues...),
+	}}
+	self.pos = len(self.str)
+}
+
+func (self *_RegExp_unicodeExpander) expand() {
+	for self.pos < len(self.str) {
+		start := self.pos
+		switch self.next() {
+		case '\\':
+			if chr := self.peek(); chr == 'p' || chr == 'P' {
+				set := self.parsePropertyEscape()
+				if self.err != nil {
+					return
+				}
+				if set.native == "" {
+					self.replace(start, set.String(false))
+				}
+			} else {
+				self.next()
+			}
+		case '[':
+			if self.unicodeSets {
+				negated := false
+				if self.peek() == '^' {
+					self.next()
+					negated = true
+				}
+				set := self.parseClassSetContents()
+				if self.err != nil {
+					return
+				}
+				if negated && set.mayContainStrings {
+					self.error("Negated character class may contain strings")
+					return
+				}
+				self.replace(start, set.String(negated))
+			} else {
+				self.expandClass()
+			}
+		}
+	}
+}
+
+// expandClass expands the property escapes within a character class without the v flag. Ranges cannot have
+// class escapes as their boundaries in the Unicode mode.
+func (self *_RegExp_unicodeExpander) expandClass() {
+	const (
+		atomNone = iota
+		atomChar
+		atomDash // a dash following a character, i.e. a range
+		atomClass
+	)
+	if self.peek() == '^' {
+		self.next()
+	}
+	last := atomNone
+	for {
+		start := self.pos
+		switch self.next() {
+		case -1:
+			self.error("Unterminated character class")
+			return
+		case ']':
+			return
+		case '-':
+			switch last {
+			case atomChar:
+				last = atomDash
+			case atomClass:
+				if self.peek() != ']' {
+					self.error("Invalid character class")
+					return
+				}
+				last = atomChar
+			default:
+				last = atomChar
+			}
+		case '\\':
+			switch self.next() {
+			case -1:
+				self.error("\\ at end of pattern")
+				return
+			case 'p', 'P':
+				if last == atomDash {
+					self.error("Invalid character class")
+					return
+				}
+				self.pos = start + 1
+				set := self.parsePropertyEscape()
+				if self.err != nil {
+					return
+				}
+				if len(set.chars) > 0 || set.native != "" {
+					var sb strings.Builder
+					set.writeClass(&sb, false)
+					self.replace(start, sb.String()[1:sb.Len()-1])
+				} else {
+					self.replace(start, "")
+				}
+				last = atomClass
+			case 'd', 'D', 's', 'S', 'w', 'W':
+				if last == atomDash {
+					self.error("Invalid character class")
+					return
+				}
+				last = atomClass
+			default:
+				if last == atomDash {
+					last = atomNone
+				} else {
+					last = atomChar
+				}
+			}
+		default:
+			if last == atomDash {
+				last = atomNone
+			} else {
+				last = atomChar
+			}
+		}
+	}
+}
+
+func isRegExpPropertyName(s string, allowDigits bool) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		chr := s[i]
+		if !(chr >= 'a' && chr <= 'z' || chr >= 'A' && chr <= 'Z' || chr == '_' || allowDigits && chr >= '0' && chr <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// \p{...} or \P{...}
+func (self *_RegExp_unicodeExpander) parsePropertyEscape() *classSet {
+	escape := self.next()
+	if self.next() != '{' {
+		self.error("Invalid property name")
+		return nil
+	}
+	end := strings.IndexByte(self.str[self.pos:], '}')
+	if end == -1 {
+		self.error("Invalid property name")
+		return nil
+	}
+	body := self.str[self.pos : self.pos+end]
+	self.pos += end + 1
+	set := self.lookupProperty(body)
+	if set == nil {
+		return nil
+	}
+	self.caseFold(set)
+	if escape == 'P' {
+		if set.mayContainStrings {
+			self.error("Invalid property name")
+			return nil
+		}
+		if set.native != "" {
+			return &classSet{native: `\P{` + body + `}`}
+		}
+		return &classSet{chars: set.chars.complement()}
+	}
+	return set
+}
+
+// caseFold folds the set if the pattern has both the i and the v flags. It must be done before the complements
+// and the set operations are applied.
+func (self *_RegExp_unicodeExpander) caseFold(set *classSet) *classSet {
+	if self.ignoreCase && self.unicodeSets && set.native == "" {
+		set.caseFold()
+	}
+	return set
+}
+
+func (self *_RegExp_unicodeExpander) lookupProperty(body string) *classSet {
+	name, value, hasValue := strings.Cut(body, "=")
+	if !isRegExpPropertyName(name, false) || hasValue && !isRegExpPropertyName(value, true) {
+		self.error("Invalid property name")
+		return nil
+	}
+	if hasValue {
+		var chars codePointSet
+		var found bool
+		switch {
+		case slices.Contains(unicodeGeneralCategoryNames, name):
+			chars, found = lookupUnicodeGeneralCategory(value)
+		case slices.Contains(unicodeScriptNames, name):
+			chars, found = lookupUnicodeScript(value)
+		case slices.Contains(unicodeScriptExtensionsNames, name):
+			if _, found = lookupUnicodeScript(value); found {
+				self.error("Unicode property \\p{%s} is not supported", body)
+				return nil
+			}
+		}
+		if !found {
+			self.error("Invalid property name")
+			return nil
+		}
+		return &classSet{chars: chars}
+	}
+	if chars, found := lookupUnicodeGeneralCategory(name); found {
+		return &classSet{chars: chars}
+	}
+	if chars, found := lookupUnicodeBinaryProperty(name); found {
+		return &classSet{chars: chars}
+	}
+	if alias, ok := unicodeBinaryPropertyAliases[name]; ok {
+		name = alias
+	}
+	if _, ok := unicodeRegexp2Properties[name]; ok {
+		return &classSet{native: `\p{` + name + `}`}
+	}
+	if self.unicodeSets && name == "Emoji_Keycap_Sequence" {
+		set := &classSet{}
+		for _, chr := range "#*0123456789" {
+			set.addString(string(chr) + "\ufe0f\u20e3")
+		}
+		return set
+	}
+	if _, ok := unicodeUnsupportedProperties[name]; ok {
+		self.error("Unicode property \\p{%s} is not supported", body)
+		return nil
+	}
+	self.error("Invalid property name")
+	return nil
+}
+
+// parseClassSetContents parses the contents of a character class with the v flag up to and including the
+// closing bracket.
+func (self *_RegExp_unicodeExpander) parseClassSetContents() *classSet {
+	if self.peek() == ']' {
+		self.next()
+		return &classSet{}
+	}
+	set, chr, isChar := self.parseClassSetOperand()
+	if set == nil {
+		return nil
+	}
+
+	if op := self.hasPrefix("&&"); op || self.hasPrefix("--") {
+		for self.hasPrefix("&&") && op || self.hasPrefix("--") && !op {
+			self.pos += 2
+			if op && self.peek() == '&' {
+				self.error("Invalid set operation in character class")
+				return nil
+			}
+			operand, _, _ := self.parseClassSetOperand()
+			if operand == nil {
+				return nil
+			}
+			if set.native != "" || operand.native != "" {
+				self.error("Unicode property escapes for Emoji cannot be used in set operations")
+				return nil
+			}
+			if op {
+				set.intersect(operand)
+			} else {
+				set.subtract(operand)
+			}
+		}
+		switch self.next() {
+		case ']':
+			return set
+		case -1:
+			self.error("Unterminated character class")
+		default:
+			self.error("Invalid set operation in character class")
+		}
+		return nil
+	}
+
+	res := &classSet{}
+	for {
+		if isChar && self.peek() == '-' && !self.hasPrefix("--") {
+			self.next()
+			_, hi, isChar := self.parseClassSetOperand()
+			if self.err != nil {
+				return nil
+			}
+			if !isChar {
+				self.error("Invalid character class")
+				return nil
+			}
+			if hi < chr {
+				self.error("Range out of order in character class")
+				return nil
+			}
+			res.union(self.caseFold(&classSet{chars: codePointSet{{chr, hi}}}))
+		} else {
+			res.union(set)
+		}
+		switch {
+		case self.peek() == ']':
+			self.next()
+			return res
+		case self.peek() == -1:
+			self.error("Unterminated character class")
+			return nil
+		case self.hasPrefix("&&"), self.hasPrefix("--"):
+			self.error("Invalid set operation in character class")
+			return nil
+		}
+		set, chr, isChar = self.parseClassSetOperand()
+		if set == nil {
+			return nil
+		}
+	}
+}
+
+// parseClassSetOperand parses a nested class, a class escape, a \q{...} or a single character. In the latter
+// case the character is also returned as chr.
+func (self *_RegExp_unicodeExpander) parseClassSetOperand() (set *classSet, chr rune, isChar bool) {
+	switch self.peek() {
+	case -1:
+		self.error("Unterminated character class")
+		return
+	case '[':
+		self.next()
+		negated := false
+		if self.peek() == '^' {
+			self.next()
+			negated = true
+		}
+		set = self.parseClassSetContents()
+		if set != nil && negated {
+			if set.mayContainStrings {
+				self.error("Negated character class may contain strings")
+				return nil, 0, false
+			}
+			if set.native != "" {
+				self.error("Unicode property escapes for Emoji cannot be used in set operations")
+				return nil, 0, false
+			}
+			set = &classSet{chars: set.chars.complement()}
+		}
+		return
+	case '\\':
+		self.next()
+		switch self.peek() {
+		case 'd', 'D', 's', 'S', 'w', 'W':
+			// the complement of \W, \D and \S is taken after the folding, like for \P
+			escape := self.next()
+			set = self.caseFold(&classSet{chars: regExpClassEscape(unicode.ToLower(escape))})
+			if escape != unicode.ToLower(escape) {
+				set.chars = set.chars.complement()
+			}
+			return
+		case 'p', 'P':
+			set = self.parsePropertyEscape()
+			return
+		case 'q':
+			self.next()
+			if self.next() != '{' {
+				self.error("Invalid escape")
+				return
+			}
+			set = self.parseClassStringDisjunction()
+			if set != nil {
+				self.caseFold(set)
+			}
+			return
+		}
+		chr = self.parseClassSetEscape()
+	default:
+		chr = self.parseClassSetCharacter()
+	}
+	if self.err != nil {
+		return
+	}
+	return self.caseFold(newClassSetChar(chr)), chr, true
+}
+
+func regExpClassEscape(escape rune) codePointSet {
+	var set codePointSet
+	switch escape {
+	case 'd', 'D':
+		set = codePointSet{{'0', '9'}}
+	case 's', 'S':
+		for _, chr := range WhitespaceChars {
+			set = append(set, codePointRange{chr, chr})
+		}
+		set = set.normalize()
+	case 'w', 'W':
+		set = newCodePointSet(codePointRange{'0', '9'}, codePointRange{'A', 'Z'}, codePointRange{'_', '_'}, codePointRange{'a', 'z'})
+	}
+	if escape >= 'A' && escape <= 'Z' {
+		set = set.complement()
+	}
+	return set
+}
+
+// \q{...}
+func (self *_RegExp_unicodeExpander) parseClassStringDisjunction() *classSet {
+	set := &classSet{}
+	var sb strings.Builder
+	for {
+		switch self.peek() {
+		case -1:
+			self.error("Unterminated character class")
+		case '}':
+			self.next()
+			set.addString(sb.String())
+			return set
+		case '|':
+			self.next()
+			set.addString(sb.String())
+			sb.Reset()
+		case '\\':
+			self.next()
+			sb.WriteRune(self.parseClassSetEscape())
+		default:
+			sb.WriteRune(self.parseClassSetCharacter())
+		}
+		if self.err != nil {
+			return nil
+		}
+	}
+}
+
+func (self *_RegExp_unicodeExpander) parseClassSetCharacter() rune {
+	chr := self.next()
+	if strings.ContainsRune("()[]{}/-\\|", chr) {
+		self.error("Invalid character '%c' in character class", chr)
+		return 0
+	}
+	if strings.ContainsRune("&!#$%*+,.:;<=>?@^`~", chr) && self.peek() == chr {
+		self.error("Invalid set operation in character class")
+		return 0
+	}
+	return chr
+}
+
+// parseClassSetEscape parses the character escape following a backslash.
+func (self *_RegExp_unicodeExpander) parseClassSetEscape() rune {
+	chr := self.next()
+	switch chr {
+	case -1:
+		self.error("\\ at end of pattern")
+	case 'f':
+		return '\f'
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'v':
+		return '\v'
+	case 'b':
+		return '\b'
+	case 'c':
+		if chr := self.peek(); chr >= 'a' && chr <= 'z' || chr >= 'A' && chr <= 'Z' {
+			self.next()
+			return chr % 32
+		}
+		self.error("Invalid escape")
+	case '0':
+		if !isDecimalDigit(self.peek()) {
+			return 0
+		}
+		self.error("Invalid decimal escape")
+	case 'x':
+		return self.parseHexDigits(2)
+	case 'u':
+		if self.peek() == '{' {
+			self.next()
+			var value rune
+			digits := 0
+			for self.peek() != '}' {
+				digit := digitValue(self.next())
+				if digit >= 16 {
+					self.error("Invalid Unicode escape")
+					return 0
+				}
+				value = value*16 + rune(digit)
+				if value > unicode.MaxRune {
+					self.error("Invalid Unicode escape")
+					return 0
+				}
+				digits++
+			}
+			self.next()
+			if digits == 0 {
+				self.error("Invalid Unicode escape")
+			}
+			return value
+		}
+		value := self.parseHexDigits(4)
+		if utf16.IsSurrogate(value) && value < 0xdc00 && self.hasPrefix(`\u`) {
+			pos := self.pos
+			self.pos += 2
+			if second := self.parseHexDigits(4); second >= 0xdc00 && second <= 0xdfff {
+				return utf16.DecodeRune(value, second)
+			}
+			if self.err != nil {
+				return 0
+			}
+			self.pos = pos
+		}
+		return value
+	default:
+		if strings.ContainsRune("^$\\.*+?()[]{}|/", chr) || strings.ContainsRune("&-!#%,:;<=>@`~", chr) {
+			return chr
+		}
+		self.error("Invalid escape")
+	}
+	return 0
+}
+
+func (self *_RegExp_unicodeExpander) parseHexDigits(n int) rune {
+	var value rune
+	for i := 0; i < n; i++ {
+		digit := digitValue(self.next())
+		if digit >= 16 {
+			self.error("Invalid escape")
+			return 0
+		}
+		value = value*16 + rune(digit)
+	}
+	return value
+}
+
+type regexpGroupFrame struct {
+	current, all map[string]struct{} // the names in the current alternative and in all the alternatives
+}
+
+func newRegexpGroupFrame() *regexpGroupFrame {
+	return &regexpGroupFrame{
+		current: make(map[string]struct{}),
+		all:     make(map[string]struct{}),
+	}
+}
+
+type regexpReplacement struct {
+	start, end int
+	value      string
+}
+
+// RenameDuplicateGroups checks the named capture groups of a pattern. The same name can only be used by
+// several groups if they are in different alternatives, otherwise an error of type RegexpSyntaxError is
+// returned.
+//
+// If there are such duplicates, the groups are renamed, so that the names are unique (as regexp2 requires),
+// and the backreferences to the duplicate names are replaced with a conditional matching the group which
+// has participated in the match. The ECMAScript names of all the capture groups are returned in groupNames
+// (the first element corresponds to the whole match and is always empty).
+//
+// If there are no duplicates, the pattern is returned as is and groupNames is nil.
+func RenameDuplicateGroups(pattern string) (renamed string, groupNames []string, err error) {
+	if !strings.Contains(pattern, "(?<") {
+		return pattern, nil, nil
+	}
+	type namedGroup struct {
+		name       string
+		start, end int // the offsets of the name
+	}
+	var (
+		groups     []namedGroup
+		backrefs   []namedGroup
+		used       = make(map[string]struct{})
+		stack      = []*regexpGroupFrame{newRegexpGroupFrame()}
+		duplicates = false
+	)
+	groupNames = []string{""}
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+			if strings.HasPrefix(pattern[i:], "k<") {
+				if end := strings.IndexByte(pattern[i:], '>'); end > 0 {
+					backrefs = append(backrefs, namedGroup{name: pattern[i+2 : i+end], start: i - 1, end: i + end + 1})
+					i += end
+				}
+			}
+		case '[':
+			for i++; i < len(pattern) && pattern[i] != ']'; i++ {
+				if pattern[i] == '\\' {
+					i++
+				}
+			}
+		case '(':
+			frame := stack[len(stack)-1]
+			if strings.HasPrefix(pattern[i:], "(?<") && !strings.HasPrefix(pattern[i:], "(?<=") && !strings.HasPrefix(pattern[i:], "(?<!") {
+				end := strings.IndexByte(pattern[i:], '>')
+				if end == -1 {
+					return "", nil, RegexpSyntaxError{regexpParseError{offset: i, err: "Invalid capture group name"}}
+				}
+				name := pattern[i+3 : i+end]
+				for _, f := range stack {
+					if _, exists := f.current[name]; exists {
+						return "", nil, RegexpSyntaxError{regexpParseError{offset: i, err: fmt.Sprintf("Duplicate capture group name '%s'", name)}}
+					}
+				}
+				if _, exists := used[name]; exists {
+					duplicates = true
+				}
+				used[name] = struct{}{}
+				frame.current[name] = struct{}{}
+				frame.all[name] = struct{}{}
+				groups = append(groups, namedGroup{name: name, start: i + 3, end: i + end})
+				groupNames = append(groupNames, name)
+				i += end
+			} else if !strings.HasPrefix(pattern[i:], "(?") {
+				groupNames = append(groupNames, "")
+			}
+			stack = append(stack, newRegexpGroupFrame())
+		case '|':
+			stack[len(stack)-1].current = make(map[string]struct{})
+		case ')':
+			if len(stack) > 1 {
+				frame := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				parent := stack[len(stack)-1]
+				for name := range frame.all {
+					parent.current[name] = struct{}{}
+					parent.all[name] = struct{}{}
+				}
+			}
+		}
+	}
+	if !duplicates {
+		return pattern, nil, nil
+	}
+
+	internalNames := make(map[string][]string)
+	var replacements []regexpReplacement
+	for _, g := range groups {
+		internal := g.name
+		if names := internalNames[g.name]; len(names) > 0 {
+			for n := len(names) + 1; ; n++ {
+				internal = g.name + "__" + strconv.Itoa(n)
+				if _, exists := used[internal]; !exists {
+					break
+				}
+			}
+			used[internal] = struct{}{}
+			replacements = append(replacements, regexpReplacement{start: g.start, end: g.end, value: internal})
+		}
+		internalNames[g.name] = append(internalNames[g.name], internal)
+	}
+	for _, ref := range backrefs {
+		names := internalNames[ref.name]
+		if len(names) < 2 {
+			continue
+		}
+		// (?(a)\k<a>|(?(a__2)\k<a__2>))
+		var sb strings.Builder
+		for i, name := range names {
+			if i > 0 {
+				sb.WriteByte('|')
+			}
+			sb.WriteString(`(?(` + name + `)\k<` + name + `>`)
+		}
+		sb.WriteString(strings.Repeat(")", len(names)))
+		replacements = append(replacements, regexpReplacement{start: ref.start, end: ref.end, value: "(?:" + sb.String() + ")"})
+	}
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].start < replacements[j].start
+	})
+	var sb strings.Builder
+	pos := 0
+	for _, r := range replacements {
+		sb.WriteString(pattern[pos:r.start])
+		sb.WriteString(r.value)
+		pos = r.end
+	}
+	sb.WriteString(pattern[pos:])
+	return sb.String(), groupNames, nil
+}
@@ -28,6 +28,10 @@ func TestRegExp(t *testing.T) {
 			test("(?U)", "Invalid group")
 			test("(?)|(?i)", "Invalid group")
 			test("(?P<w>)(?P<w>)(?P<D>)", "Invalid group")
+			test("(?ii:a)", "Invalid group")
+			test("(?i-i:a)", "Invalid group")
+			test("(?-:a)", "Invalid group")
+			test("(?=a)(?x:a)", "Invalid group")
 		}
 
 		{
@@ -143,6 +147,12 @@ func TestRegExp(t *testing.T) {
 
 			test(`\S+`, "[^"+WhitespaceChars+"]+")
 
+			test(`(?i-:a)`, `(?i:a)`)
+
+			test(`(?m-is:.)`, `(?m-is:`+Re2Dot+`)`)
+
+			test(`(?s:.(?-s:.))`, `(?s:.(?-s:`+Re2Dot+`))`)
+
 		}
 	})
 }
@@ -166,6 +176,68 @@ func TestTransformRegExp(t *testing.T) {
 	})
 }
 
+func TestExpandUnicodeClasses(t *testing.T) {
+	tt(t, func() {
+		test := func(input string, unicodeSets bool, expect string) {
+			pattern, err := ExpandUnicodeClasses(input, unicodeSets, false)
+			is(err, nil)
+			is(pattern, expect)
+		}
+
+		test(`a[b\]]\(`, false, `a[b\]]\(`)
+		test(`\p{AHex}+`, false, `[0-9A-Fa-f]+`)
+		test(`[_\p{AHex}]`, false, `[_0-9A-Fa-f]`)
+		test(`\P{Any}`, false, `[]`)
+		test(`\p{Emoji}`, false, `\p{Emoji}`)
+		test(`[\p{AHex}--[a-z]]`, true, `[0-9A-F]`)
+		test(`[^\d&&[5-7]]`, true, `[^5-7]`)
+		test(`[\q{ab|c|}x]`, true, `(?:ab|[cx]|)`)
+		test(`[\[\u{41}]`, true, `[A\u{5b}]`)
+
+		testIgnoreCase := func(input string, expect string) {
+			pattern, err := ExpandUnicodeClasses(input, true, true)
+			is(err, nil)
+			is(pattern, expect)
+		}
+		testIgnoreCase(`[k--\q{K}]`, `[]`)
+		testIgnoreCase(`[^k]`, `[^Kk\u{212a}]`)
+		testIgnoreCase(`\P{ASCII}`, `[\u{80}-\u{17e}\u{180}-\u{2129}\u{212b}-\u{10ffff}]`)
+
+		testErr := func(input string, unicodeSets bool, expect string) {
+			_, err := ExpandUnicodeClasses(input, unicodeSets, false)
+			_, isSyntaxErr := err.(RegexpSyntaxError)
+			is(isSyntaxErr, true)
+			is(err, expect)
+		}
+
+		testErr(`\p{Foo}`, false, "Invalid property name")
+		testErr(`[\d-a]`, false, "Invalid character class")
+		testErr(`[a|b]`, true, "Invalid character '|' in character class")
+		testErr(`[a!!b]`, true, "Invalid set operation in character class")
+		testErr(`[^\q{ab}]`, true, "Negated character class may contain strings")
+		testErr(`[\p{AHex}`, true, "Unterminated character class")
+		testErr(`\p{scx=Latn}`, false, "Unicode property \\p{scx=Latn} is not supported")
+	})
+}
+
+func TestRenameDuplicateGroups(t *testing.T) {
+	tt(t, func() {
+		renamed, names, err := RenameDuplicateGroups(`(?<a>x)|(?<a>y)\k<a>`)
+		is(err, nil)
+		is(renamed, `(?<a>x)|(?<a__2>y)(?:(?(a)\k<a>|(?(a__2)\k<a__2>)))`)
+		is(len(names), 3)
+		is(names[2], "a")
+
+		renamed, names, err = RenameDuplicateGroups(`(?<a>x)(?<b>y)`)
+		is(err, nil)
+		is(renamed, `(?<a>x)(?<b>y)`)
+		is(len(names), 0)
+
+		_, _, err = RenameDuplicateGroups(`(?<a>x)|(?<b>y)(?<b>z)`)
+		is(err, "Duplicate capture group name 'b'")
+	})
+}
+
 func BenchmarkTransformRegExp(b *testing.B) {
 	f := func(reStr string, b *testing.B) {
 		b.ResetTimer()
@@ -0,0 +1,437 @@
+package parser
+
+import (
+	"sort"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/unicode/rangetable"
+)
+
+// codePointRange is an inclusive range of code points.
+type codePointRange struct {
+	lo, hi rune
+}
+
+// codePointSet is a sorted list of disjoint and non-adjacent code point ranges.
+type codePointSet []codePointRange
+
+var anyCodePoint = codePointSet{{0, unicode.MaxRune}}
+
+func newCodePointSet(ranges ...codePointRange) codePointSet {
+	s := make(codePointSet, len(ranges))
+	copy(s, ranges)
+	return s.normalize()
+}
+
+func codePointSetFromTable(t *unicode.RangeTable) codePointSet {
+	var s codePointSet
+	add := func(lo, hi, stride rune) {
+		if stride == 1 {
+			s = append(s, codePointRange{lo, hi})
+			return
+		}
+		for c := lo; c <= hi; c += stride {
+			s = append(s, codePointRange{c, c})
+		}
+	}
+	for _, r := range t.R16 {
+		add(rune(r.Lo), rune(r.Hi), rune(r.Stride))
+	}
+	for _, r := range t.R32 {
+		add(rune(r.Lo), rune(r.Hi), rune(r.Stride))
+	}
+	return s.normalize()
+}
+
+// normalize sorts the ranges and merges the overlapping and adjacent ones.
+func (s codePointSet) normalize() codePointSet {
+	if len(s) < 2 {
+		return s
+	}
+	sort.Slice(s, func(i, j int) bool {
+		return s[i].lo < s[j].lo
+	})
+	res := s[:1]
+	for _, r := range s[1:] {
+		last := &res[len(res)-1]
+		if r.lo <= last.hi+1 {
+			if r.hi > last.hi {
+				last.hi = r.hi
+			}
+		} else {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+func (s codePointSet) contains(c rune) bool {
+	i := sort.Search(len(s), func(i int) bool {
+		return s[i].hi >= c
+	})
+	return i < len(s) && s[i].lo <= c
+}
+
+func (s codePointSet) union(other codePointSet) codePointSet {
+	res := make(codePointSet, 0, len(s)+len(other))
+	res = append(res, s...)
+	res = append(res, other...)
+	return res.normalize()
+}
+
+func (s codePointSet) intersect(other codePointSet) codePointSet {
+	var res codePointSet
+	i, j := 0, 0
+	for i < len(s) && j < len(other) {
+		lo, hi := max(s[i].lo, other[j].lo), min(s[i].hi, other[j].hi)
+		if lo <= hi {
+			res = append(res, codePointRange{lo, hi})
+		}
+		if s[i].hi < other[j].hi {
+			i++
+		} else {
+			j++
+		}
+	}
+	return res
+}
+
+func (s codePointSet) complement() codePointSet {
+	var res codePointSet
+	next := rune(0)
+	for _, r := range s {
+		if r.lo > next {
+			res = append(res, codePointRange{next, r.lo - 1})
+		}
+		next = r.hi + 1
+	}
+	if next <= unicode.MaxRune {
+		res = append(res, codePointRange{next, unicode.MaxRune})
+	}
+	return res
+}
+
+func (s codePointSet) subtract(other codePointSet) codePointSet {
+	return s.intersect(other.complement())
+}
+
+var (
+	unicodeGeneralCategoryNames = []string{"General_Category", "gc"}
+	unicodeScriptNames          = []string{"Script", "sc"}
+	// Go does not ship the Script_Extensions data, so Script_Extensions=X is not supported.
+	unicodeScriptExtensionsNames = []string{"Script_Extensions", "scx"}
+)
+
+// unicodeScriptAliases maps the short (ISO 15924) names of the scripts to the names used by unicode.Scripts.
+var unicodeScriptAliases = map[string]string{
+	"Adlm": "Adlam", "Aghb": "Caucasian_Albanian", "Arab": "Arabic", "Armi": "Imperial_Aramaic",
+	"Armn": "Armenian", "Avst": "Avestan", "Bali": "Balinese", "Bamu": "Bamum", "Bass": "Bassa_Vah",
+	"Batk": "Batak", "Beng": "Bengali", "Berf": "Beria_Erfe", "Bhks": "Bhaiksuki", "Bopo": "Bopomofo",
+	"Brah": "Brahmi", "Brai": "Braille", "Bugi": "Buginese", "Buhd": "Buhid", "Cakm": "Chakma",
+	"Cans": "Canadian_Aboriginal", "Cari": "Carian", "Cher": "Cherokee", "Chrs": "Chorasmian",
+	"Copt": "Coptic", "Qaac": "Coptic", "Cpmn": "Cypro_Minoan", "Cprt": "Cypriot", "Cyrl": "Cyrillic",
+	"Deva": "Devanagari", "Diak": "Dives_Akuru", "Dogr": "Dogra", "Dsrt": "Deseret", "Dupl": "Duployan",
+	"Egyp": "Egyptian_Hieroglyphs", "Elba": "Elbasan", "Elym": "Elymaic", "Ethi": "Ethiopic", "Gara": "Garay",
+	"Geor": "Georgian", "Glag": "Glagolitic", "Gong": "Gunjala_Gondi", "Gonm": "Masaram_Gondi",
+	"Goth": "Gothic", "Gran": "Grantha", "Grek": "Greek", "Gujr": "Gujarati", "Gukh": "Gurung_Khema",
+	"Guru": "Gurmukhi", "Hang": "Hangul", "Hani": "Han", "Hano": "Hanunoo", "Hatr": "Hatran",
+	"Hebr": "Hebrew", "Hira": "Hiragana", "Hluw": "Anatolian_Hieroglyphs", "Hmng": "Pahawh_Hmong",
+	"Hmnp": "Nyiakeng_Puachue_Hmong", "Hung": "Old_Hungarian", "Ital": "Old_Italic", "Java": "Javanese",
+	"Kali": "Kayah_Li", "Kana": "Katakana", "Khar": "Kharoshthi", "Khmr": "Khmer", "Khoj": "Khojki",
+	"Kits": "Khitan_Small_Script", "Knda": "Kannada", "Krai": "Kirat_Rai", "Kthi": "Kaithi",
+	"Lana": "Tai_Tham", "Laoo": "Lao", "Latn": "Latin", "Lepc": "Lepcha", "Limb": "Limbu",
+	"Lina": "Linear_A", "Linb": "Linear_B", "Lyci": "Lycian", "Lydi": "Lydian", "Mahj": "Mahajani",
+	"Maka": "Makasar", "Mand": "Mandaic", "Mani": "Manichaean", "Marc": "Marchen", "Medf": "Medefaidrin",
+	"Mend": "Mende_Kikakui", "Merc": "Meroitic_Cursive", "Mero": "Meroitic_Hieroglyphs", "Mlym": "Malayalam",
+	"Mong": "Mongolian", "Mroo": "Mro", "Mtei": "Meetei_Mayek", "Mult": "Multani", "Mymr": "Myanmar",
+	"Nagm": "Nag_Mundari", "Nand": "Nandinagari", "Narb": "Old_North_Arabian", "Nbat": "Nabataean",
+	"Nkoo": "Nko", "Nshu": "Nushu", "Ogam": "Ogham", "Olck": "Ol_Chiki", "Onao": "Ol_Onal",
+	"Orkh": "Old_Turkic", "Orya": "Oriya", "Osge": "Osage", "Osma": "Osmanya", "Ougr": "Old_Uyghur",
+	"Palm": "Palmyrene", "Pauc": "Pau_Cin_Hau", "Perm": "Old_Permic", "Phag": "Phags_Pa",
+	"Phli": "Inscriptional_Pahlavi", "Phlp": "Psalter_Pahlavi", "Phnx": "Phoenician", "Plrd": "Miao",
+	"Prti": "Inscriptional_Parthian", "Rjng": "Rejang", "Rohg": "Hanifi_Rohingya", "Runr": "Runic",
+	"Samr": "Samaritan", "Sarb": "Old_South_Arabian", "Saur": "Saurashtra", "Sgnw": "SignWriting",
+	"Shaw": "Shavian", "Shrd": "Sharada", "Sidd": "Siddham", "Sidt": "Sidetic", "Sind": "Khudawadi",
+	"Sinh": "Sinhala", "Sogd": "Sogdian", "Sogo": "Old_Sogdian", "Sora": "Sora_Sompeng", "Soyo": "Soyombo",
+	"Sund": "Sundanese", "Sunu": "Sunuwar", "Sylo": "Syloti_Nagri", "Syrc": "Syriac", "Tagb": "Tagbanwa",
+	"Takr": "Takri", "Tale": "Tai_Le", "Talu": "New_Tai_Lue", "Taml": "Tamil", "Tang": "Tangut",
+	"Tavt": "Tai_Viet", "Tayo": "Tai_Yo", "Telu": "Telugu", "Tfng": "Tifinagh", "Tglg": "Tagalog",
+	"Thaa": "Thaana", "Tibt": "Tibetan", "Tirh": "Tirhuta", "Tnsa": "Tangsa", "Todr": "Todhri",
+	"Tols": "Tolong_Siki", "Tutg": "Tulu_Tigalari", "Ugar": "Ugaritic", "Vaii": "Vai", "Vith": "Vithkuqi",
+	"Wara": "Warang_Citi", "Wcho": "Wancho", "Xpeo": "Old_Persian", "Xsux": "Cuneiform", "Yezi": "Yezidi",
+	"Yiii": "Yi", "Zanb": "Zanabazar_Square", "Zinh": "Inherited", "Qaai": "Inherited", "Zyyy": "Common",
+	"Zzzz": "Unknown",
+}
+
+// unicodeBinaryPropertyAliases maps the short names of the binary properties to the long ones.
+var unicodeBinaryPropertyAliases = map[string]string{
+	"AHex": "ASCII_Hex_Digit", "Alpha": "Alphabetic", "Bidi_C": "Bidi_Control", "Bidi_M": "Bidi_Mirrored",
+	"CI": "Case_Ignorable", "CWCF": "Changes_When_Casefolded", "CWCM": "Changes_When_Casemapped",
+	"CWKCF": "Changes_When_NFKC_Casefolded", "CWL": "Changes_When_Lowercased",
+	"CWT": "Changes_When_Titlecased", "CWU": "Changes_When_Uppercased", "DI": "Default_Ignorable_Code_Point",
+	"Dep": "Deprecated", "Dia": "Diacritic", "EBase": "Emoji_Modifier_Base", "EComp": "Emoji_Component",
+	"EMod": "Emoji_Modifier", "EPres": "Emoji_Presentation", "ExtPict": "Extended_Pictographic",
+	"Ext": "Extender", "Gr_Base": "Grapheme_Base", "Gr_Ext": "Grapheme_Extend", "Hex": "Hex_Digit",
+	"IDSB": "IDS_Binary_Operator", "IDST": "IDS_Trinary_Operator", "IDC": "ID_Continue", "IDS": "ID_Start",
+	"Ideo": "Ideographic", "Join_C": "Join_Control", "LOE": "Logical_Order_Exception", "Lower": "Lowercase",
+	"NChar": "Noncharacter_Code_Point", "Pat_Syn": "Pattern_Syntax", "Pat_WS": "Pattern_White_Space",
+	"QMark": "Quotation_Mark", "RI": "Regional_Indicator", "STerm": "Sentence_Terminal", "SD": "Soft_Dotted",
+	"Term": "Terminal_Punctuation", "UIdeo": "Unified_Ideograph", "Upper": "Uppercase",
+	"VS": "Variation_Selector", "space": "White_Space", "XIDC": "XID_Continue", "XIDS": "XID_Start",
+}
+
+// unicodeBinaryProperties lists the binary properties that can be computed from the tables in the unicode package.
+var unicodeBinaryProperties = map[string]func() codePointSet{
+	"Any":   func() codePointSet { return anyCodePoint },
+	"ASCII": func() codePointSet { return codePointSet{{0, unicode.MaxASCII}} },
+	"Assigned": func() codePointSet {
+		return codePointSetFromTable(unicode.Categories["Cn"]).complement()
+	},
+	"Alphabetic": func() codePointSet {
+		return codePointSetFromTable(rangetable.Merge(unicode.Lu, unicode.Ll, unicode.Lt, unicode.Lm, unicode.Lo, unicode.Nl, unicode.Other_Alphabetic))
+	},
+	"Lowercase": func() codePointSet {
+		return codePointSetFromTable(rangetable.Merge(unicode.Ll, unicode.Other_Lowercase))
+	},
+	"Uppercase": func() codePointSet {
+		return codePointSetFromTable(rangetable.Merge(unicode.Lu, unicode.Other_Uppercase))
+	},
+	"Cased": func() codePointSet {
+		return codePointSetFromTable(rangetable.Merge(unicode.Ll, unicode.Other_Lowercase, unicode.Lu, unicode.Other_Uppercase, unicode.Lt))
+	},
+	"Case_Ignorable": func() codePointSet {
+		// Word_Break=MidLetter, MidNumLet and Single_Quote are not available in the unicode package.
+		wordBreak := rangetable.New(0x27, 0x2e, 0x3a, 0xb7, 0x387, 0x55f, 0x5f4, 0x2018, 0x2019, 0x2024, 0x2027,
+			0xfe13, 0xfe52, 0xfe55, 0xff07, 0xff0e, 0xff1a)
+		return codePointSetFromTable(rangetable.Merge(unicode.Mn, unicode.Me, unicode.Cf, unicode.Lm, unicode.Sk, wordBreak))
+	},
+	"Math": func() codePointSet {
+		return codePointSetFromTable(rangetable.Merge(unicode.Sm, unicode.Other_Math))
+	},
+	"Default_Ignorable_Code_Point": func() codePointSet {
+		return codePointSetFromTable(rangetable.Merge(unicode.Other_Default_Ignorable_Code_Point, unicode.Cf, unicode.Variation_Selector)).
+			subtract(codePointSetFromTable(rangetable.Merge(unicode.White_Space, unicode.Prepended_Concatenation_Mark))).
+			subtract(newCodePointSet(codePointRange{0xfff9, 0xfffb}, codePointRange{0x13430, 0x1343f}))
+	},
+	"Grapheme_Extend": func() codePointSet {
+		return codePointSetFromTable(rangetable.Merge(unicode.Me, unicode.Mn, unicode.Other_Grapheme_Extend))
+	},
+	"Grapheme_Base": func() codePointSet {
+		return codePointSetFromTable(rangetable.Merge(unicode.Cc, unicode.Cf, unicode.Cs, unicode.Co, unicode.Categories["Cn"],
+			unicode.Zl, unicode.Zp, unicode.Me, unicode.Mn, unicode.Other_Grapheme_Extend)).complement()
+	},
+	"ID_Start": func() codePointSet {
+		return codePointSetFromTable(unicodeRangeIdStartPos).subtract(codePointSetFromTable(unicodeRangeIdNeg))
+	},
+	"ID_Continue": func() codePointSet {
+		return codePointSetFromTable(unicodeRangeIdContPos).subtract(codePointSetFromTable(unicodeRangeIdNeg))
+	},
+	"XID_Start": func() codePointSet {
+		// The code points that are not stable under NFKC normalisation (see DerivedCoreProperties.txt)
+		return codePointSetFromTable(unicodeRangeIdStartPos).subtract(codePointSetFromTable(unicodeRangeIdNeg)).
+			subtract(codePointSetFromTable(rangetable.New(0x37a, 0xe33, 0xeb3, 0x309b, 0x309c, 0xfc5e, 0xfc5f, 0xfc60,
+				0xfc61, 0xfc62, 0xfc63, 0xfdfa, 0xfdfb, 0xfe70, 0xfe72, 0xfe74, 0xfe76, 0xfe78, 0xfe7a, 0xfe7c, 0xfe7e,
+				0xff9e, 0xff9f)))
+	},
+	"XID_Continue": func() codePointSet {
+		return codePointSetFromTable(unicodeRangeIdContPos).subtract(codePointSetFromTable(unicodeRangeIdNeg)).
+			subtract(codePointSetFromTable(rangetable.New(0x37a, 0x309b, 0x309c, 0xfc5e, 0xfc5f, 0xfc60, 0xfc61, 0xfc62,
+				0xfc63, 0xfdfa, 0xfdfb, 0xfe70, 0xfe72, 0xfe74, 0xfe76, 0xfe78, 0xfe7a, 0xfe7c, 0xfe7e)))
+	},
+	// The Changes_When_* properties are derived from the full case mappings, see DerivedCoreProperties.txt.
+	"Changes_When_Lowercased": func() codePointSet {
+		return changesWhen(caseMappingChanges(cases.Lower(language.Und), unicode.ToLower))
+	},
+	"Changes_When_Uppercased": func() codePointSet {
+		return changesWhen(caseMappingChanges(cases.Upper(language.Und), unicode.ToUpper))
+	},
+	"Changes_When_Titlecased": func() codePointSet {
+		return changesWhen(caseMappingChanges(cases.Title(language.Und), unicode.ToTitle))
+	},
+	"Changes_When_Casefolded": func() codePointSet {
+		// The full case folding only differs from the simple one for the code points which have a full lower or
+		// upper case mapping longer than one code point.
+		upper, lower := cases.Upper(language.Und), cases.Lower(language.Und)
+		return changesWhen(func(_ rune, decomposed string) bool {
+			for _, c := range decomposed {
+				if simpleCaseFold(c) != c || utf8.RuneCountInString(upper.String(string(c))) > 1 ||
+					utf8.RuneCountInString(lower.String(string(c))) > 1 {
+					return true
+				}
+			}
+			return false
+		})
+	},
+	"Changes_When_Casemapped": func() codePointSet {
+		return changesWhen(caseMappingChanges(cases.Lower(language.Und), unicode.ToLower)).
+			union(changesWhen(caseMappingChanges(cases.Upper(language.Und), unicode.ToUpper))).
+			union(changesWhen(caseMappingChanges(cases.Title(language.Und), unicode.ToTitle)))
+	},
+}
+
+// unicodeRegexp2Properties lists the binary properties for which the unicode package has no data, but regexp2 does.
+// Such properties are passed through, so they cannot be used in the set operations.
+var unicodeRegexp2Properties = map[string]struct{}{
+	"Emoji":                 {},
+	"Emoji_Component":       {},
+	"Emoji_Modifier":        {},
+	"Emoji_Modifier_Base":   {},
+	"Emoji_Presentation":    {},
+	"Extended_Pictographic": {},
+}
+
+// unicodeUnsupportedProperties lists the valid properties which are not supported because of the lack of data.
+var unicodeUnsupportedProperties = map[string]struct{}{
+	"Bidi_Mirrored":                {},
+	"Changes_When_NFKC_Casefolded": {},
+	"Basic_Emoji":                  {},
+	"RGI_Emoji_Modifier_Sequence":  {},
+	"RGI_Emoji_Flag_Sequence":      {},
+	"RGI_Emoji_Tag_Sequence":       {},
+	"RGI_Emoji_ZWJ_Sequence":       {},
+	"RGI_Emoji":                    {},
+}
+
+// simpleCaseFold returns the simple case folding of c, i.e. the lower case, except for Cherokee which folds
+// to upper case and for the dotted and dotless i which do not fold.
+func simpleCaseFold(c rune) rune {
+	if c == 0x130 || c == 0x131 {
+		return c
+	}
+	u := unicode.ToUpper(c)
+	if u >= 0x13a0 && u <= 0x13f5 {
+		return u
+	}
+	return unicode.ToLower(u)
+}
+
+// changesWhen returns the code points c for which changes(c, toNFD(c)) is true.
+func changesWhen(changes func(c rune, decomposed string) bool) codePointSet {
+	var s codePointSet
+	for _, r := range caseMappingCandidates() {
+		for c := r.lo; c <= r.hi; c++ {
+			if changes(c, norm.NFD.String(string(c))) {
+				s = append(s, codePointRange{c, c})
+			}
+		}
+	}
+	return s.normalize()
+}
+
+// caseMappingChanges reports whether the full case mapping done by caser changes the decomposed code point. The simple
+// case mapping is checked as well, as it may come from a newer version of Unicode than the full one.
+func caseMappingChanges(caser cases.Caser, simple func(rune) rune) func(rune, string) bool {
+	return func(c rune, decomposed string) bool {
+		return simple(c) != c || caser.String(decomposed) != decomposed
+	}
+}
+
+// caseMappingCandidates returns the code points which may be changed by a case mapping: the cased ones and the
+// ones which have a simple case mapping.
+func caseMappingCandidates() codePointSet {
+	return cachedCodePointSet("caseMappingCandidates", func() codePointSet {
+		s := codePointSetFromTable(rangetable.Merge(unicode.Ll, unicode.Other_Lowercase, unicode.Lu, unicode.Other_Uppercase, unicode.Lt))
+		for _, r := range unicode.CaseRanges {
+			s = append(s, codePointRange{rune(r.Lo), rune(r.Hi)})
+		}
+		return s.normalize()
+	})
+}
+
+// caseClosure returns s with the code points which have the same simple case folding as one of its code points
+// added.
+func (s codePointSet) caseClosure() codePointSet {
+	res := append(codePointSet(nil), s...)
+	for _, r := range s.intersect(caseMappingCandidates()) {
+		for c := r.lo; c <= r.hi; c++ {
+			for f := unicode.SimpleFold(c); f != c; f = unicode.SimpleFold(f) {
+				res = append(res, codePointRange{f, f})
+			}
+		}
+	}
+	return res.normalize()
+}
+
+var unicodePropertyCache sync.Map // string -> codePointSet
+
+func cachedCodePointSet(key string, fn func() codePointSet) codePointSet {
+	if s, ok := unicodePropertyCache.Load(key); ok {
+		return s.(codePointSet)
+	}
+	s, _ := unicodePropertyCache.LoadOrStore(key, fn())
+	return s.(codePointSet)
+}
+
+func lookupUnicodeGeneralCategory(value string) (codePointSet, bool) {
+	if alias, ok := unicode.CategoryAliases[value]; ok {
+		value = alias
+	}
+	if t := unicode.Categories[value]; t != nil {
+		return cachedCodePointSet("gc="+value, func() codePointSet {
+			return codePointSetFromTable(t)
+		}), true
+	}
+	return nil, false
+}
+
+func lookupUnicodeScript(value string) (codePointSet, bool) {
+	if alias, ok := unicodeScriptAliases[value]; ok {
+		value = alias
+	}
+	if value == "Unknown" {
+		return cachedCodePointSet("sc=Unknown", func() codePointSet {
+			var s codePointSet
+			for _, t := range unicode.Scripts {
+				s = append(s, codePointSetFromTable(t)...)
+			}
+			return s.normalize().complement()
+		}), true
+	}
+	if t := unicode.Scripts[value]; t != nil {
+		return cachedCodePointSet("sc="+value, func() codePointSet {
+			return codePointSetFromTable(t)
+		}), true
+	}
+	return nil, false
+}
+
+func lookupUnicodeBinaryProperty(name string) (codePointSet, bool) {
+	if alias, ok := unicodeBinaryPropertyAliases[name]; ok {
+		name = alias
+	}
+	if fn := unicodeBinaryProperties[name]; fn != nil {
+		return cachedCodePointSet(name, fn), true
+	}
+	if t := unicode.Properties[name]; t != nil {
+		if _, isOther := otherUnicodeProperties[name]; !isOther {
+			return cachedCodePointSet(name, func() codePointSet {
+				return codePointSetFromTable(t)
+			}), true
+		}
+	}
+	return nil, false
+}
+
+// otherUnicodeProperties lists the contributory properties from unicode.Properties which are not valid in
+// ECMAScript.
+var otherUnicodeProperties = map[string]struct{}{
+	"Hyphen":                             {},
+	"ID_Compat_Math_Continue":            {},
+	"ID_Compat_Math_Start":               {},
+	"IDS_Unary_Operator":                 {},
+	"Modifier_Combining_Mark":            {},
+	"Other_Alphabetic":                   {},
+	"Other_Default_Ignorable_Code_Point": {},
+	"Other_Grapheme_Extend":              {},
+	"Other_ID_Continue":                  {},
+	"Other_ID_Start":                     {},
+	"Other_Lowercase":                    {},
+	"Other_Math":                         {},
+	"Other_Uppercase":                    {},
+	"Prepended_Concatenation_Mark":       {},
+}
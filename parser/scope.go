@@ -52,3 +52,16 @@ func (self *_scope) hasLabel(name unistring.String) bool {
 	}
 	return false
 }
+
+// markTopLevelAwait sets hasTLA on the top-level scope if the current position is not inside a function.
+func (self *_parser) markTopLevelAwait() {
+	for scope := self.scope; scope != nil; scope = scope.outer {
+		if scope.inFunction {
+			break
+		}
+		if scope.outer == nil {
+			scope.hasTLA = true
+			break
+		}
+	}
+}
@@ -75,6 +75,14 @@ func (self *_parser) parseStatement() ast.Statement {
 		self.insertSemicolon = true
 	case token.CONST:
 		return self.parseLexicalDeclaration(self.token)
+	case token.IDENTIFIER:
+		if self.isUsingDeclaration(false, false) {
+			return self.parseUsingDeclaration(false)
+		}
+	case token.AWAIT:
+		if self.isUsingDeclaration(true, false) {
+			return self.parseUsingDeclaration(true)
+		}
 	case token.ASYNC:
 		if f := self.parseMaybeAsyncFunction(true); f != nil {
 			return &ast.FunctionDeclaration{
@@ -115,7 +123,7 @@ func (self *_parser) parseStatement() ast.Statement {
 			return exp
 		}
 	case token.IMPORT:
-		if self.peek() != token.LEFT_PARENTHESIS {
+		if self.peek() != token.LEFT_PARENTHESIS && self.peekDynamicImportPhase() == ast.ImportPhaseEvaluation {
 			if !self.opts.module {
 				self.error(self.idx, "import not supported in script")
 				self.next()
@@ -649,7 +657,7 @@ func (self *_parser) parseForIn(idx file.Idx, into ast.ForInto) *ast.ForInStatem
 	}
 }
 
-func (self *_parser) parseForOf(idx file.Idx, into ast.ForInto) *ast.ForOfStatement {
+func (self *_parser) parseForOf(idx file.Idx, into ast.ForInto, await bool) *ast.ForOfStatement {
 	// Already have consumed "<into> of"
 
 	source := self.parseAssignmentExpression()
@@ -660,6 +668,7 @@ func (self *_parser) parseForOf(idx file.Idx, into ast.ForInto) *ast.ForOfStatem
 		Into:   into,
 		Source: source,
 		Body:   self.parseIterationStatement(),
+		Await:  await,
 	}
 }
 
@@ -689,6 +698,15 @@ func (self *_parser) parseFor(idx file.Idx, initializer ast.ForLoopInitializer)
 
 func (self *_parser) parseForOrForInStatement() ast.Statement {
 	idx := self.expect(token.FOR)
+	await := false
+	if self.token == token.AWAIT && self.scope.allowAwait {
+		if !self.scope.inAsync {
+			self.errorUnexpectedToken(token.AWAIT)
+		}
+		self.markTopLevelAwait()
+		await = true
+		self.next()
+	}
 	self.expect(token.LEFT_PARENTHESIS)
 
 	var initializer ast.ForLoopInitializer
@@ -708,8 +726,19 @@ func (self *_parser) parseForOrForInStatement() ast.Statement {
 				tok = token.IDENTIFIER
 			}
 		}
-		if tok == token.VAR || tok == token.LET || tok == token.CONST {
+		awaitUsing := false
+		if self.isUsingDeclaration(false, true) {
+			tok = token.USING
+		} else if self.isUsingDeclaration(true, true) {
+			tok = token.USING
+			awaitUsing = true
+		}
+		if tok == token.VAR || tok == token.LET || tok == token.CONST || tok == token.USING {
 			idx := self.idx
+			if awaitUsing {
+				self.markTopLevelAwait()
+				self.next()
+			}
 			self.next()
 			var list []*ast.Binding
 			if tok == token.VAR {
@@ -717,6 +746,9 @@ func (self *_parser) parseForOrForInStatement() ast.Statement {
 			} else {
 				list = self.parseVariableDeclarationList()
 			}
+			if tok == token.USING {
+				self.checkUsingBindings(list, false)
+			}
 			if len(list) == 1 {
 				if self.token == token.IN {
 					self.next() // in
@@ -730,6 +762,9 @@ func (self *_parser) parseForOrForInStatement() ast.Statement {
 				if list[0].Initializer != nil {
 					self.error(list[0].Initializer.Idx0(), "for-in loop variable declaration may not have an initializer")
 				}
+				if tok == token.USING && forIn {
+					self.error(idx, "using declarations are not allowed in for-in loops")
+				}
 				if tok == token.VAR {
 					into = &ast.ForIntoVar{
 						Binding: list[0],
@@ -737,12 +772,17 @@ func (self *_parser) parseForOrForInStatement() ast.Statement {
 				} else {
 					into = &ast.ForDeclaration{
 						Idx:     idx,
-						IsConst: tok == token.CONST,
+						IsConst: tok == token.CONST || tok == token.USING,
+						IsUsing: tok == token.USING,
+						IsAwait: awaitUsing,
 						Target:  list[0].Target,
 					}
 				}
 			} else {
 				self.ensurePatternInit(list)
+				if tok == token.USING {
+					self.checkUsingBindings(list, true)
+				}
 				if tok == token.VAR {
 					initializer = &ast.ForLoopInitializerVarDeclList{
 						List: list,
@@ -752,6 +792,7 @@ func (self *_parser) parseForOrForInStatement() ast.Statement {
 						LexicalDeclaration: ast.LexicalDeclaration{
 							Idx:   idx,
 							Token: tok,
+							Await: awaitUsing,
 							List:  list,
 						},
 					}
@@ -791,12 +832,15 @@ func (self *_parser) parseForOrForInStatement() ast.Statement {
 		self.scope.allowIn = allowIn
 	}
 
+	if forOf {
+		return self.parseForOf(idx, into, await)
+	}
+	if await {
+		self.error(idx, "for await can only be used with for-of loops")
+	}
 	if forIn {
 		return self.parseForIn(idx, into)
 	}
-	if forOf {
-		return self.parseForOf(idx, into)
-	}
 
 	self.expect(token.SEMICOLON)
 	return self.parseFor(idx, initializer)
@@ -843,6 +887,74 @@ func (self *_parser) parseLexicalDeclaration(tok token.Token) *ast.LexicalDeclar
 	}
 }
 
+// isUsingDeclaration returns true if the current token starts a 'using' declaration (or 'await using' if await
+// is set), i.e. 'using' is followed by a binding identifier on the same line. In a for loop head 'using of'
+// is treated as the start of a for-of loop.
+func (self *_parser) isUsingDeclaration(await, forHead bool) bool {
+	state := self.mark(nil)
+	defer self.restore(state)
+	if await {
+		if self.token != token.AWAIT || !self.scope.allowAwait {
+			return false
+		}
+		self.next()
+		if self.hasLineTerminatorBefore(state.idx, state.literal) {
+			return false
+		}
+	}
+	if self.token != token.IDENTIFIER || self.literal != "using" {
+		return false
+	}
+	idx, literal := self.idx, self.literal
+	self.next()
+	if forHead && self.token == token.IDENTIFIER && self.literal == "of" {
+		return false
+	}
+	return self.isBindingId(self.token) && !self.hasLineTerminatorBefore(idx, literal)
+}
+
+// hasLineTerminatorBefore returns true if there is a LineTerminator between the end of the previous token
+// (identified by its index and literal) and the current token.
+func (self *_parser) hasLineTerminatorBefore(prevIdx file.Idx, prevLiteral string) bool {
+	return strings.ContainsAny(self.slice(prevIdx+file.Idx(len(prevLiteral)), self.idx), "\n\r\u2028\u2029")
+}
+
+func (self *_parser) parseUsingDeclaration(await bool) *ast.LexicalDeclaration {
+	idx := self.idx
+	if await {
+		self.markTopLevelAwait()
+		self.next()
+	}
+	self.next() // using
+	if !self.scope.allowLet {
+		self.error(idx, "Lexical declaration cannot appear in a single-statement context")
+	}
+
+	list := self.parseVariableDeclarationList()
+	self.checkUsingBindings(list, true)
+	self.semicolon()
+
+	return &ast.LexicalDeclaration{
+		Idx:   idx,
+		Token: token.USING,
+		Await: await,
+		List:  list,
+	}
+}
+
+func (self *_parser) checkUsingBindings(list []*ast.Binding, needInit bool) {
+	for _, item := range list {
+		if _, ok := item.Target.(*ast.Identifier); !ok {
+			self.error(item.Idx0(), "Binding patterns are not allowed in using declarations")
+			break
+		}
+		if needInit && item.Initializer == nil {
+			self.error(item.Idx1(), "Missing initializer in using declaration")
+			break
+		}
+	}
+}
+
 func (self *_parser) parseDoWhileStatement() ast.Statement {
 	inIteration := self.scope.inIteration
 	self.scope.inIteration = true
@@ -1092,20 +1204,27 @@ func (self *_parser) parseExportDeclaration() *ast.ExportDeclaration {
 	case token.MULTIPLY:
 		exportFromClause := self.parseExportFromClause()
 		fromClause := self.parseFromClause()
+		attributes := self.parseWithClause()
 		self.semicolon()
 		return &ast.ExportDeclaration{
 			Idx:              idx,
 			ExportFromClause: exportFromClause,
 			FromClause:       fromClause,
+			Attributes:       attributes,
 		}
 	case token.LEFT_BRACE:
 		namedExports := self.parseNamedExports()
 		fromClause := self.parseFromClause()
+		var attributes []*ast.ImportAttribute
+		if fromClause != nil {
+			attributes = self.parseWithClause()
+		}
 		self.semicolon()
 		return &ast.ExportDeclaration{
 			Idx:          idx,
 			NamedExports: namedExports,
 			FromClause:   fromClause,
+			Attributes:   attributes,
 		}
 	case token.VAR:
 		return &ast.ExportDeclaration{
@@ -1216,17 +1335,115 @@ func (self *_parser) parseImportDeclaration() *ast.ImportDeclaration {
 
 	if self.token == token.STRING {
 		moduleSpecifier := self.parseModuleSpecifier()
+		attributes := self.parseWithClause()
 		self.semicolon()
-		return &ast.ImportDeclaration{Idx: idx, ModuleSpecifier: moduleSpecifier}
+		return &ast.ImportDeclaration{Idx: idx, ModuleSpecifier: moduleSpecifier, Attributes: attributes}
 	}
 
+	var importClause *ast.ImportClause
+	phase := self.parseImportPhase()
+	switch phase {
+	case ast.ImportPhaseSource:
+		importClause = &ast.ImportClause{ImportedDefaultBinding: self.parseImportedDefaultBinding()}
+	case ast.ImportPhaseDefer:
+		importClause = &ast.ImportClause{NameSpaceImport: self.parseNameSpaceImport()}
+	default:
+		importClause = self.parseImportClause()
+	}
+	fromClause := self.parseFromClause()
+	if fromClause == nil && phase != ast.ImportPhaseEvaluation {
+		self.error(self.idx, "Expected 'from' keyword, found '%s' instead", self.literal)
+	}
+	attributes := self.parseWithClause()
+	self.semicolon()
 	return &ast.ImportDeclaration{
-		ImportClause: self.parseImportClause(),
-		FromClause:   self.parseFromClause(),
+		ImportClause: importClause,
+		FromClause:   fromClause,
+		Attributes:   attributes,
+		Phase:        phase,
 		Idx:          idx,
 	}
 }
 
+// parseImportPhase consumes the `source` or `defer` modifier of an import
+// declaration. Both are contextual, so `import source from "x"` and
+// `import defer, { a } from "x"` are ordinary default imports.
+func (self *_parser) parseImportPhase() ast.ImportPhase {
+	if self.token != token.IDENTIFIER {
+		return ast.ImportPhaseEvaluation
+	}
+	switch self.literal {
+	case "defer":
+		if self.peek() == token.MULTIPLY {
+			self.next()
+			return ast.ImportPhaseDefer
+		}
+	case "source":
+		state := self.mark(nil)
+		self.next()
+		phase := ast.ImportPhaseEvaluation
+		if self.token == token.IDENTIFIER {
+			if self.literal != "from" {
+				phase = ast.ImportPhaseSource
+			} else {
+				// import source from from "x"
+				self.next()
+				if self.token == token.IDENTIFIER && self.literal == "from" {
+					phase = ast.ImportPhaseSource
+				}
+			}
+		}
+		self.restore(state)
+		if phase == ast.ImportPhaseSource {
+			self.next()
+		}
+		return phase
+	}
+	return ast.ImportPhaseEvaluation
+}
+
+// parseWithClause parses the optional import attributes following a module
+// specifier: `with { type: "json" }`. The legacy `assert` keyword is accepted
+// as well, as long as it is on the same line.
+func (self *_parser) parseWithClause() []*ast.ImportAttribute {
+	if self.token != token.WITH && (self.token != token.IDENTIFIER || self.literal != "assert" || self.implicitSemicolon) {
+		return nil
+	}
+	self.next()
+	self.expect(token.LEFT_BRACE)
+	attributes := []*ast.ImportAttribute{}
+	for self.token != token.RIGHT_BRACE && self.token != token.EOF {
+		idx, key := self.idx, self.parsedLiteral
+		if self.token != token.STRING && !token.IsId(self.token) {
+			self.errorUnexpectedToken(self.token)
+			self.next()
+			break
+		}
+		self.next()
+		self.expect(token.COLON)
+		if self.token != token.STRING {
+			self.error(self.idx, "Import attribute value must be a string")
+		}
+		value := self.parsedLiteral
+		self.next()
+		for _, attr := range attributes {
+			if attr.Key == key {
+				self.error(idx, "Duplicate import attribute '%s'", key)
+			}
+		}
+		if key != "type" {
+			self.error(idx, "Unsupported import attribute '%s'", key)
+		}
+		attributes = append(attributes, &ast.ImportAttribute{Idx: idx, Key: key, Value: value})
+		if self.token != token.COMMA {
+			break
+		}
+		self.next()
+	}
+	self.expect(token.RIGHT_BRACE)
+	return attributes
+}
+
 // Find the next statement after an error (recover)
 func (self *_parser) nextStatement() {
 	for {
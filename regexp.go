@@ -1,11 +1,14 @@
 package sobek
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"regexp"
 	"sort"
 	"strings"
+	"sync/atomic"
+	"time"
 	"unicode/utf16"
 
 	"github.com/dlclark/regexp2/v2"
@@ -13,6 +16,8 @@ import (
 	"github.com/grafana/sobek/unistring"
 )
 
+var errRegexpInterrupted = errors.New("regexp matching interrupted")
+
 type regexp2MatchCache struct {
 	target String
 	runes  []rune
@@ -23,8 +28,19 @@ type regexp2MatchCache struct {
 type regexp2Wrapper struct {
 	rx    *regexp2.Regexp
 	cache *regexp2MatchCache
+	// groupNames is set if the pattern had duplicate group names which had to be renamed
+	groupNames []string
+	// interrupted and timeout are set by setLimits()
+	interrupted *uint32
+	timeout     time.Duration
 }
 
+// regexp2InterruptCheckPeriod is how long a regexp2 match runs before it's stopped to check the interrupt flag.
+// regexp2 cannot be stopped from the outside, so the match is then restarted with twice the period. A match which is
+// never interrupted therefore takes at most about twice as long and an interrupted one stops within about the time
+// it has already run.
+const regexp2InterruptCheckPeriod = 100 * time.Millisecond
+
 type regexpWrapper regexp.Regexp
 
 type positionMapItem struct {
@@ -62,8 +78,12 @@ func (rd *arrayRuneReader) ReadRune() (r rune, size int, err error) {
 // Not goroutine-safe. Use regexpPattern.clone()
 type regexpPattern struct {
 	src string
+	// the ECMAScript names of the capture groups if src has renamed duplicate groups, see parser.RenameDuplicateGroups()
+	groupNames []string
 
-	global, ignoreCase, multiline, dotAll, sticky, unicode bool
+	global, ignoreCase, multiline, dotAll, sticky, hasIndices bool
+	// unicode is also set when unicodeSets is, because src only uses the u flag syntax at this point
+	unicode, unicodeSets bool
 
 	regexpWrapper  *regexpWrapper
 	regexp2Wrapper *regexp2Wrapper
@@ -118,6 +138,7 @@ func (p *regexpPattern) createRegexp2() {
 		// At this point the regexp should have been successfully converted to re2, if it fails now, it's a bug.
 		panic(err)
 	}
+	rx.groupNames = p.groupNames
 	p.regexp2Wrapper = rx
 }
 
@@ -143,23 +164,30 @@ func buildUTF8PosMap(s unicodeString) (positionMap, string) {
 	return pm, sb.String()
 }
 
-func (p *regexpPattern) findSubmatchIndex(s String, start int) regexpResult {
-	if p.regexpWrapper == nil {
-		return p.regexp2Wrapper.findSubmatchIndex(s, start, p.unicode, p.global || p.sticky)
-	}
-	if start != 0 {
+// regexp2 returns the regexp2Wrapper with the Runtime's match limits applied.
+func (p *regexpPattern) regexp2(r *Runtime) *regexp2Wrapper {
+	p.createRegexp2()
+	p.regexp2Wrapper.setLimits(r.regexpMatchTimeout, &r.vm.interrupted)
+	return p.regexp2Wrapper
+}
+
+func (p *regexpPattern) findSubmatchIndex(r *Runtime, s String, start int) regexpResult {
+	if p.regexpWrapper == nil || start != 0 {
 		// Unfortunately Go's regexp library does not allow starting from an arbitrary position.
 		// If we just drop the first _start_ characters of the string the assertions (^, $, \b and \B) will not
 		// work correctly.
-		p.createRegexp2()
-		return p.regexp2Wrapper.findSubmatchIndex(s, start, p.unicode, p.global || p.sticky)
+		result, err := p.regexp2(r).findSubmatchIndex(s, start, p.unicode, p.global || p.sticky)
+		if err != nil {
+			panic(r.newRegexpMatchError(err))
+		}
+		return result
 	}
 	return p.regexpWrapper.findSubmatchIndex(s, p.unicode)
 }
 
-func (p *regexpPattern) findAllSubmatchIndex(s String, start int, limit int, sticky bool) []regexpResult {
+func (p *regexpPattern) findAllSubmatchIndex(r *Runtime, s String, start int, limit int, sticky bool) []regexpResult {
 	if p.regexpWrapper == nil {
-		return p.regexp2Wrapper.findAllSubmatchIndex(s, start, limit, sticky, p.unicode)
+		return p.findAllSubmatchIndexRegexp2(r, s, start, limit, sticky)
 	}
 	if start == 0 {
 		a, u := devirtualizeString(s)
@@ -190,20 +218,41 @@ func (p *regexpPattern) findAllSubmatchIndex(s String, start int, limit int, sti
 		}
 	}
 
-	p.createRegexp2()
-	return p.regexp2Wrapper.findAllSubmatchIndex(s, start, limit, sticky, p.unicode)
+	return p.findAllSubmatchIndexRegexp2(r, s, start, limit, sticky)
+}
+
+func (p *regexpPattern) findAllSubmatchIndexRegexp2(r *Runtime, s String, start int, limit int, sticky bool) []regexpResult {
+	results, err := p.regexp2(r).findAllSubmatchIndex(s, start, limit, sticky, p.unicode)
+	if err != nil {
+		panic(r.newRegexpMatchError(err))
+	}
+	return results
+}
+
+func (r *Runtime) newRegexpMatchError(err error) Value {
+	if errors.Is(err, errRegexpInterrupted) {
+		panic(r.vm.newInterruptedError())
+	}
+	if errors.Is(err, regexp2.ErrBacktrackingStackLimit) {
+		return r.newError(r.getRangeError(), "Maximum RegExp backtracking stack size exceeded")
+	}
+	// regexp2 does not have a distinct type for the timeout error, and it is the only one left
+	return r.newError(r.getRangeError(), "RegExp match timeout exceeded")
 }
 
 // clone creates a copy of the regexpPattern which can be used concurrently.
 func (p *regexpPattern) clone() *regexpPattern {
 	ret := &regexpPattern{
-		src:        p.src,
-		global:     p.global,
-		ignoreCase: p.ignoreCase,
-		multiline:  p.multiline,
-		dotAll:     p.dotAll,
-		sticky:     p.sticky,
-		unicode:    p.unicode,
+		src:         p.src,
+		groupNames:  p.groupNames,
+		global:      p.global,
+		ignoreCase:  p.ignoreCase,
+		multiline:   p.multiline,
+		dotAll:      p.dotAll,
+		sticky:      p.sticky,
+		unicode:     p.unicode,
+		unicodeSets: p.unicodeSets,
+		hasIndices:  p.hasIndices,
 	}
 	if p.regexpWrapper != nil {
 		ret.regexpWrapper = p.regexpWrapper.clone()
@@ -222,7 +271,78 @@ type regexpObject struct {
 	standard bool
 }
 
-func (r *regexp2Wrapper) findSubmatchIndex(s String, start int, fullUnicode, doCache bool) regexpResult {
+// setLimits applies the match timeout (non-positive means no timeout) and the interrupt flag of a Runtime.
+// The first run of a match is limited on a copy of the compiled regexp as it may be shared with other Runtimes.
+func (r *regexp2Wrapper) setLimits(timeout time.Duration, interrupted *uint32) {
+	if timeout < 0 {
+		timeout = 0
+	}
+	first := regexp2InterruptCheckPeriod
+	if timeout > 0 && timeout < first {
+		first = timeout
+	}
+	if r.rx.MatchTimeout != first {
+		rx := *r.rx
+		rx.MatchTimeout = first
+		r.rx = &rx
+	}
+	r.interrupted = interrupted
+	r.timeout = timeout
+}
+
+// find runs a match using fn, restarting it with a doubled timeout each time the current one is reached
+// (see regexp2InterruptCheckPeriod) until it completes, the Runtime is interrupted or the match timeout is exceeded.
+func (r *regexp2Wrapper) find(fn func(rx *regexp2.Regexp) (*regexp2.Match, error)) (*regexp2.Match, error) {
+	rx := r.rx
+	var deadline time.Time
+	if r.timeout > rx.MatchTimeout {
+		deadline = time.Now().Add(r.timeout)
+	}
+	for {
+		match, err := fn(rx)
+		if err == nil || errors.Is(err, regexp2.ErrBacktrackingStackLimit) {
+			return match, err
+		}
+		if err := r.checkInterrupted(); err != nil {
+			return nil, err
+		}
+		next := 2 * rx.MatchTimeout
+		if next < rx.MatchTimeout {
+			next = regexp2.DefaultMatchTimeout
+		}
+		if r.timeout > 0 {
+			remaining := time.Until(deadline)
+			if deadline.IsZero() || remaining <= 0 {
+				return nil, err
+			}
+			if remaining < next {
+				next = remaining
+			}
+		}
+		restarted := *rx
+		restarted.MatchTimeout = next
+		rx = &restarted
+	}
+}
+
+func (r *regexp2Wrapper) checkInterrupted() error {
+	if r.interrupted != nil && atomic.LoadUint32(r.interrupted) != 0 {
+		return errRegexpInterrupted
+	}
+	return nil
+}
+
+func (r *regexp2Wrapper) groupName(idx int, group *regexp2.Group) string {
+	if r.groupNames != nil {
+		return r.groupNames[idx]
+	}
+	return group.Name
+}
+
+func (r *regexp2Wrapper) findSubmatchIndex(s String, start int, fullUnicode, doCache bool) (regexpResult, error) {
+	if err := r.checkInterrupted(); err != nil {
+		return regexpResult{}, err
+	}
 	if fullUnicode {
 		return r.findSubmatchIndexUnicode(s, start, doCache)
 	}
@@ -230,7 +350,6 @@ func (r *regexp2Wrapper) findSubmatchIndex(s String, start int, fullUnicode, doC
 }
 
 func (r *regexp2Wrapper) findUTF16Cached(s String, start int, doCache bool) (match *regexp2.Match, runes []rune, err error) {
-	wrapped := r.rx
 	cache := r.cache
 	if cache != nil && cache.posMap == nil && cache.target.SameAs(s) {
 		runes = cache.runes
@@ -238,7 +357,9 @@ func (r *regexp2Wrapper) findUTF16Cached(s String, start int, doCache bool) (mat
 		runes = s.utf16Runes()
 		cache = nil
 	}
-	match, err = wrapped.FindRunesMatchStartingAt(runes, start)
+	match, err = r.find(func(rx *regexp2.Regexp) (*regexp2.Match, error) {
+		return rx.FindRunesMatchStartingAt(runes, start)
+	})
 	if doCache && match != nil && err == nil {
 		if cache == nil {
 			if r.cache == nil {
@@ -255,14 +376,10 @@ func (r *regexp2Wrapper) findUTF16Cached(s String, start int, doCache bool) (mat
 	return
 }
 
-func (r *regexp2Wrapper) findSubmatchIndexUTF16(s String, start int, doCache bool) regexpResult {
+func (r *regexp2Wrapper) findSubmatchIndexUTF16(s String, start int, doCache bool) (regexpResult, error) {
 	match, _, err := r.findUTF16Cached(s, start, doCache)
-	if err != nil {
-		return regexpResult{}
-	}
-
-	if match == nil {
-		return regexpResult{}
+	if match == nil || err != nil {
+		return regexpResult{}, err
 	}
 	groups := match.Groups()
 
@@ -270,14 +387,14 @@ func (r *regexp2Wrapper) findSubmatchIndexUTF16(s String, start int, doCache boo
 		indexes: make([]int, 0, len(groups)<<1),
 		groups:  make([]string, 0, len(groups)),
 	}
-	for _, group := range groups {
+	for i, group := range groups {
 		if len(group.Captures) > 0 {
-			result.appendIndexesAndGroup(group.RuneIndex, group.RuneIndex+group.RuneLength, group.Name)
+			result.appendIndexesAndGroup(group.RuneIndex, group.RuneIndex+group.RuneLength, r.groupName(i, &group))
 		} else {
-			result.appendIndexesAndGroup(-1, 0, group.Name)
+			result.appendIndexesAndGroup(-1, 0, r.groupName(i, &group))
 		}
 	}
-	return result
+	return result, nil
 }
 
 func (r *regexp2Wrapper) findUnicodeCached(s String, start int, doCache bool) (match *regexp2.Match, posMap []int, err error) {
@@ -287,7 +404,6 @@ func (r *regexp2Wrapper) findUnicodeCached(s String, start int, doCache bool) (m
 		splitPair   bool
 		savedRune   rune
 	)
-	wrapped := r.rx
 	cache := r.cache
 	if cache != nil && cache.posMap != nil && cache.target.SameAs(s) {
 		runes, posMap = cache.runes, cache.posMap
@@ -301,7 +417,9 @@ func (r *regexp2Wrapper) findUnicodeCached(s String, start int, doCache bool) (m
 		_, second := utf16.EncodeRune(runes[mappedStart])
 		savedRune, runes[mappedStart] = runes[mappedStart], second
 	}
-	match, err = wrapped.FindRunesMatchStartingAt(runes, mappedStart)
+	match, err = r.find(func(rx *regexp2.Regexp) (*regexp2.Match, error) {
+		return rx.FindRunesMatchStartingAt(runes, mappedStart)
+	})
 	if doCache && match != nil && err == nil {
 		if splitPair {
 			runes[mappedStart] = savedRune
@@ -323,10 +441,10 @@ func (r *regexp2Wrapper) findUnicodeCached(s String, start int, doCache bool) (m
 	return
 }
 
-func (r *regexp2Wrapper) findSubmatchIndexUnicode(s String, start int, doCache bool) regexpResult {
+func (r *regexp2Wrapper) findSubmatchIndexUnicode(s String, start int, doCache bool) (regexpResult, error) {
 	match, posMap, err := r.findUnicodeCached(s, start, doCache)
 	if match == nil || err != nil {
-		return regexpResult{}
+		return regexpResult{}, err
 	}
 
 	groups := match.Groups()
@@ -335,21 +453,20 @@ func (r *regexp2Wrapper) findSubmatchIndexUnicode(s String, start int, doCache b
 		indexes: make([]int, 0, len(groups)<<1),
 		groups:  make([]string, 0, len(groups)),
 	}
-	for _, group := range groups {
+	for i, group := range groups {
 		if len(group.Captures) > 0 {
-			result.appendIndexesAndGroup(posMap[group.RuneIndex], posMap[group.RuneIndex+group.RuneLength], group.Name)
+			result.appendIndexesAndGroup(posMap[group.RuneIndex], posMap[group.RuneIndex+group.RuneLength], r.groupName(i, &group))
 		} else {
-			result.appendIndexesAndGroup(-1, 0, group.Name)
+			result.appendIndexesAndGroup(-1, 0, r.groupName(i, &group))
 		}
 	}
-	return result
+	return result, nil
 }
 
-func (r *regexp2Wrapper) findAllSubmatchIndexUTF16(s String, start, limit int, sticky bool) []regexpResult {
-	wrapped := r.rx
+func (r *regexp2Wrapper) findAllSubmatchIndexUTF16(s String, start, limit int, sticky bool) ([]regexpResult, error) {
 	match, runes, err := r.findUTF16Cached(s, start, false)
 	if match == nil || err != nil {
-		return nil
+		return nil, err
 	}
 	if limit < 0 {
 		limit = len(runes) + 1
@@ -363,13 +480,13 @@ func (r *regexp2Wrapper) findAllSubmatchIndexUTF16(s String, start, limit int, s
 			groups:  make([]string, 0, len(groups)),
 		}
 
-		for _, group := range groups {
+		for i, group := range groups {
 			if len(group.Captures) > 0 {
 				startPos := group.RuneIndex
 				endPos := group.RuneIndex + group.RuneLength
-				result.appendIndexesAndGroup(startPos, endPos, group.Name)
+				result.appendIndexesAndGroup(startPos, endPos, r.groupName(i, &group))
 			} else {
-				result.appendIndexesAndGroup(-1, 0, group.Name)
+				result.appendIndexesAndGroup(-1, 0, r.groupName(i, &group))
 			}
 		}
 
@@ -385,12 +502,18 @@ func (r *regexp2Wrapper) findAllSubmatchIndexUTF16(s String, start, limit int, s
 		if limit <= 0 {
 			break
 		}
-		match, err = wrapped.FindNextMatch(match)
+		if err = r.checkInterrupted(); err != nil {
+			return nil, err
+		}
+		prev := match
+		match, err = r.find(func(rx *regexp2.Regexp) (*regexp2.Match, error) {
+			return rx.FindNextMatch(prev)
+		})
 		if err != nil {
-			return nil
+			return nil, err
 		}
 	}
-	return results
+	return results, nil
 }
 
 func buildPosMap(rd io.RuneReader, l, start int) (posMap []int, runes []rune, mappedStart int, splitPair bool) {
@@ -431,15 +554,14 @@ func posMapReverseLookup(posMap []int, pos int) (int, bool) {
 	return mapped, false
 }
 
-func (r *regexp2Wrapper) findAllSubmatchIndexUnicode(s unicodeString, start, limit int, sticky bool) []regexpResult {
-	wrapped := r.rx
+func (r *regexp2Wrapper) findAllSubmatchIndexUnicode(s unicodeString, start, limit int, sticky bool) ([]regexpResult, error) {
 	if limit < 0 {
 		limit = len(s) + 1
 	}
 	results := make([]regexpResult, 0, limit)
 	match, posMap, err := r.findUnicodeCached(s, start, false)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 	for match != nil {
 		groups := match.Groups()
@@ -449,13 +571,13 @@ func (r *regexp2Wrapper) findAllSubmatchIndexUnicode(s unicodeString, start, lim
 			groups:  make([]string, 0, len(groups)),
 		}
 
-		for _, group := range groups {
+		for i, group := range groups {
 			if len(group.Captures) > 0 {
 				start := posMap[group.RuneIndex]
 				end := posMap[group.RuneIndex+group.RuneLength]
-				result.appendIndexesAndGroup(start, end, group.Name)
+				result.appendIndexesAndGroup(start, end, r.groupName(i, &group))
 			} else {
-				result.appendIndexesAndGroup(-1, 0, group.Name)
+				result.appendIndexesAndGroup(-1, 0, r.groupName(i, &group))
 			}
 		}
 
@@ -467,15 +589,24 @@ func (r *regexp2Wrapper) findAllSubmatchIndexUnicode(s unicodeString, start, lim
 		}
 
 		results = append(results, result)
-		match, err = wrapped.FindNextMatch(match)
+		if err = r.checkInterrupted(); err != nil {
+			return nil, err
+		}
+		prev := match
+		match, err = r.find(func(rx *regexp2.Regexp) (*regexp2.Match, error) {
+			return rx.FindNextMatch(prev)
+		})
 		if err != nil {
-			return nil
+			return nil, err
 		}
 	}
-	return results
+	return results, nil
 }
 
-func (r *regexp2Wrapper) findAllSubmatchIndex(s String, start, limit int, sticky, fullUnicode bool) []regexpResult {
+func (r *regexp2Wrapper) findAllSubmatchIndex(s String, start, limit int, sticky, fullUnicode bool) ([]regexpResult, error) {
+	if err := r.checkInterrupted(); err != nil {
+		return nil, err
+	}
 	a, u := devirtualizeString(s)
 	if u != nil {
 		if fullUnicode {
@@ -488,7 +619,8 @@ func (r *regexp2Wrapper) findAllSubmatchIndex(s String, start, limit int, sticky
 
 func (r *regexp2Wrapper) clone() *regexp2Wrapper {
 	return &regexp2Wrapper{
-		rx: r.rx,
+		rx:         r.rx,
+		groupNames: r.groupNames,
 	}
 }
 
@@ -526,7 +658,7 @@ func (r *regexpWrapper) findSubmatchIndexUnicode(s unicodeString, fullUnicode bo
 	wrapped := (*regexp.Regexp)(r)
 	if fullUnicode {
 		posMap, runes, _, _ := buildPosMap(&lenientUtf16Decoder{utf16Reader: s.utf16Reader()}, s.Length(), 0)
-		result := regexpResult{indexes: wrapped.FindReaderSubmatchIndex(&arrayRuneReader{runes: runes})}
+		result := regexpResult{indexes: wrapped.FindReaderSubmatchIndex(&arrayRuneReader{runes: runes}), groups: wrapped.SubexpNames()}
 		for i, item := range result.indexes {
 			if item >= 0 {
 				result.indexes[i] = posMap[item]
@@ -551,7 +683,11 @@ func (r *Runtime) createRegexpGroupsObj(valueArray []Value, groupNames []string)
 			} else if groups == nil {
 				groups = r.newBaseObject(nil, classObject)
 			}
-			groups.setOwnStr(unistring.NewFromString(name), valueArray[index], false)
+			n := unistring.NewFromString(name)
+			// with duplicate names only one of the groups can participate
+			if valueArray[index] != _undefined || !groups.hasOwnPropertyStr(n) {
+				groups.setOwnStr(n, valueArray[index], false)
+			}
 		}
 	}
 	if groups != nil {
@@ -567,7 +703,11 @@ func createRegexpGroupsMap(result regexpResult) (groups map[unistring.String]int
 			if group := result.groups[i]; group != "" {
 				idx := i * 2
 				if idx < len(result.indexes)-1 {
-					groups[unistring.NewFromString(group)] = idx
+					name := unistring.NewFromString(group)
+					// with duplicate names only one of the groups can participate
+					if _, exists := groups[name]; !exists || result.indexes[idx] >= 0 {
+						groups[name] = idx
+					}
 				}
 			}
 		}
@@ -609,9 +749,50 @@ func (r *regexpObject) execResultToArray(target String, result regexpResult) Val
 		Enumerable:   FLAG_TRUE,
 	}, false)
 
+	if r.pattern.hasIndices {
+		match.self.defineOwnPropertyStr("indices", PropertyDescriptor{
+			Value:        r.createRegexpIndicesArray(valueArray, result),
+			Writable:     FLAG_TRUE,
+			Configurable: FLAG_TRUE,
+			Enumerable:   FLAG_TRUE,
+		}, false)
+	}
+
 	return match
 }
 
+// createRegexpIndicesArray creates the value of the "indices" property of a match result (see the 'd' flag).
+// Only the captures that are present in valueArray are included, the offsets are in UTF-16 units.
+func (r *regexpObject) createRegexpIndicesArray(valueArray []Value, result regexpResult) *Object {
+	rt := r.val.runtime
+	indexValues := make([]Value, len(valueArray))
+	for index := range valueArray {
+		if valueArray[index] == _undefined {
+			indexValues[index] = _undefined
+			continue
+		}
+		offset := index << 1
+		indexValues[index] = rt.newArrayValues([]Value{
+			intToValue(int64(result.indexes[offset])),
+			intToValue(int64(result.indexes[offset+1])),
+		})
+	}
+	indices := rt.newArrayValues(indexValues)
+	var groupsVal Value
+	if groups := rt.createRegexpGroupsObj(indexValues, result.groups); groups != nil {
+		groupsVal = groups
+	} else {
+		groupsVal = _undefined
+	}
+	indices.self.defineOwnPropertyStr("groups", PropertyDescriptor{
+		Value:        groupsVal,
+		Writable:     FLAG_TRUE,
+		Configurable: FLAG_TRUE,
+		Enumerable:   FLAG_TRUE,
+	}, false)
+	return indices
+}
+
 func (r *regexpObject) getLastIndex() int64 {
 	lastIndex := toLength(r.getStr("lastIndex", nil))
 	if !r.pattern.global && !r.pattern.sticky {
@@ -623,7 +804,7 @@ func (r *regexpObject) getLastIndex() int64 {
 func (r *regexpObject) execRegexp(target String) (match bool, result regexpResult) {
 	index := r.getLastIndex()
 	if index >= 0 && index <= int64(target.Length()) {
-		result = r.pattern.findSubmatchIndex(target, int(index))
+		result = r.pattern.findSubmatchIndex(r.val.runtime, target, int(index))
 	}
 	match = len(result.indexes) > 0 && (!r.pattern.sticky || int64(result.indexes[0]) == index)
 
@@ -1,7 +1,9 @@
 package sobek
 
 import (
+	"errors"
 	"testing"
+	"time"
 )
 
 func TestRegexp1(t *testing.T) {
@@ -806,6 +808,274 @@ func TestRegExpRepeatCount(t *testing.T) {
 	testScript(SCRIPT, valueTrue, t)
 }
 
+func TestRegexpUnicodePropertyEscapes(t *testing.T) {
+	const SCRIPT = `
+	assert.sameValue(/\p{L}+/u.exec("abc1")[0], "abc");
+	assert.sameValue(/\p{Letter}+/u.exec("Ωμέγα!")[0], "Ωμέγα");
+	assert.sameValue(/\P{L}+/u.exec("ab12c")[0], "12");
+	assert.sameValue(/\p{General_Category=Lu}/u.test("a"), false);
+	assert.sameValue(/\p{gc=Uppercase_Letter}/u.test("A"), true);
+	assert.sameValue(/\p{Lu}/ui.test("a"), true, "case insensitive");
+	assert.sameValue(/\p{Script=Greek}/u.test("α"), true);
+	assert.sameValue(/\p{sc=Grek}/u.test("a"), false);
+	assert.sameValue(/^\p{CWU}+$/u.test("a\u00df\u01f0"), true, "full case mapping");
+	assert.sameValue(/\p{CWCF}/u.test("\u00df"), true);
+	assert.sameValue(/\p{CWCF}/u.test("\u0131"), false);
+	assert.sameValue(/\p{CWL}/u.test("a"), false);
+	assert.sameValue(/^\p{ASCII_Hex_Digit}+$/u.test("09afAF"), true);
+	assert.sameValue(/\p{Alpha}/u.test("1"), false);
+	assert.sameValue(/\p{Any}/u.test("\udbff\udfff"), true);
+	assert.sameValue(/^\p{ID_Start}\p{ID_Continue}*$/u.test("x\u0301y1"), true);
+	assert.sameValue(/[\p{Lu}\d]+/u.exec("aAB12c")[0], "AB12");
+	assert.sameValue(/[^\p{L}]+/u.exec("ab12c")[0], "12");
+	assert.sameValue(/\p{Emoji}/u.test("😀"), true, "Emoji");
+	assert.sameValue(/(?=.)\p{Lu}/u.test("A"), true, "regexp2");
+	assert.sameValue(/(?=.)\p{Emoji_Presentation}/u.test("a"), false, "Emoji with regexp2");
+	assert.sameValue(/\p{L}/.test("p{L}"), true, "not unicode mode");
+
+	assert.throws(SyntaxError, () => new RegExp("\\p{Unknown_Property}", "u"));
+	assert.throws(SyntaxError, () => new RegExp("\\p{gc=Greek}", "u"));
+	assert.throws(SyntaxError, () => new RegExp("\\p{ASCII=Y}", "u"));
+	assert.throws(SyntaxError, () => new RegExp("\\p{lu}", "u"));
+	assert.throws(SyntaxError, () => new RegExp("\\pL", "u"));
+	assert.throws(SyntaxError, () => new RegExp("[a-\\p{L}]", "u"));
+	assert.throws(SyntaxError, () => new RegExp("[\\p{L}-z]", "u"));
+	assert.throws(SyntaxError, () => new RegExp("\\p{RGI_Emoji}", "u"));
+	assert.throws(SyntaxError, () => new RegExp("\\p{scx=Hira}", "u"), "Script_Extensions");
+	assert.throws(SyntaxError, () => new RegExp("\\p{Bidi_M}", "u"));
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestRegexpUnicodeSets(t *testing.T) {
+	const SCRIPT = `
+	const re = /[\p{L}--[a-z]]+/v;
+	assert.sameValue(re.unicodeSets, true);
+	assert.sameValue(re.unicode, false);
+	assert.sameValue(re.flags, "v");
+	assert.sameValue(String(re), "/[\\p{L}--[a-z]]+/v");
+	assert.sameValue(re.exec("abcDEF")[0], "DEF");
+	assert.sameValue(/[\p{L}&&\p{ASCII}]+/v.exec("éabc")[0], "abc");
+	assert.sameValue(/[[a-z]--[aeiou]]+/v.exec("aebcd")[0], "bcd");
+	assert.sameValue(/[\w--\d]+/v.exec("12ab_3")[0], "ab_");
+	assert.sameValue(/[^\d]/v.exec("1a")[0], "a");
+	assert.sameValue(/[\q{abc|d}x]+/v.exec("zabcdx")[0], "abcdx");
+	assert.sameValue(/^[\q{abc|ab}]$/v.test("ab"), true);
+	assert.sameValue(/^[\q{abc|ab|}]/v.exec("abcd")[0], "abc", "longest string first");
+	assert.sameValue(/[\q{}]/v.exec("x")[0], "");
+	assert.sameValue(/^[\p{Emoji_Keycap_Sequence}]$/v.test("1\ufe0f\u20e3"), true);
+	assert.sameValue(/\p{Emoji}[\p{Emoji}x]/v.test("😀x"), true);
+	assert.sameValue(/[\(\-\&]+/v.exec("a(-&")[0], "(-&");
+	assert.sameValue("😀😀".split(/(?:)/v).length, 2);
+
+	// the sets are case folded before the complements and the set operations
+	assert.sameValue(/\P{Lu}/vi.test("A"), false);
+	assert.sameValue(/\P{Lu}/vi.test("a"), false);
+	assert.sameValue(/\P{Lu}/ui.test("A"), true);
+	assert.sameValue(/[^\p{Lu}]/vi.test("a"), false);
+	assert.sameValue(/[[^\p{Lu}]]/vi.test("A"), false);
+	assert.sameValue(/[\p{L}--\p{Lu}]/vi.test("a"), false);
+	assert.sameValue(/[\p{L}--\p{Lu}]/v.test("a"), true);
+	assert.sameValue(/[\W]/vi.test("\u017f"), false);
+	assert.sameValue(/[\q{AB}--\q{ab}]/vi.test("ab"), false);
+	assert.sameValue(/[\q{AB}]/vi.test("ab"), true);
+
+	assert.throws(SyntaxError, () => new RegExp("a", "uv"));
+	assert.throws(SyntaxError, () => new RegExp("[(]", "v"));
+	assert.throws(SyntaxError, () => new RegExp("[a&&&b]", "v"));
+	assert.throws(SyntaxError, () => new RegExp("[a--b&&c]", "v"));
+	assert.throws(SyntaxError, () => new RegExp("[a-z&&b]", "v"));
+	assert.throws(SyntaxError, () => new RegExp("[z-a]", "v"));
+	assert.throws(SyntaxError, () => new RegExp("[^\\q{ab}]", "v"));
+	assert.throws(SyntaxError, () => new RegExp("[a[^\\p{Emoji_Keycap_Sequence}]]", "v"));
+	assert.throws(SyntaxError, () => new RegExp("[\\p{Emoji}--a]", "v"));
+	assert.throws(SyntaxError, () => new RegExp("[a", "v"));
+	// properties of strings without data in Go are not supported, see README
+	assert.throws(SyntaxError, () => new RegExp("\\p{RGI_Emoji}", "v"));
+	assert.throws(SyntaxError, () => new RegExp("[\\p{Basic_Emoji}]", "v"));
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestRegexpMatchIndices(t *testing.T) {
+	const SCRIPT = `
+	let re = /a(?<Z>z)?(b)/d;
+	assert.sameValue(re.hasIndices, true);
+	assert.sameValue(re.flags, "d");
+	assert.sameValue(/x/dgimsuy.flags, "dgimsuy");
+	assert.sameValue(String(/x/dg), "/x/dg");
+	assert.sameValue(Object.getOwnPropertyDescriptor(RegExp.prototype, "hasIndices").get.call(RegExp.prototype), undefined);
+	assert.throws(SyntaxError, () => new RegExp("a", "dd"));
+
+	let m = re.exec("xxab");
+	assert(compareArray(m.indices[0], [2, 4]), "indices[0]");
+	assert.sameValue(m.indices[1], undefined);
+	assert(compareArray(m.indices[2], [3, 4]), "indices[2]");
+	assert.sameValue(Object.getPrototypeOf(m.indices.groups), null);
+	assert.sameValue(m.indices.groups.Z, undefined);
+	assert(Object.prototype.hasOwnProperty.call(m.indices.groups, "Z"), "groups has Z");
+	assert.sameValue(/a/.exec("a").indices, undefined);
+	assert.sameValue(/a/d.exec("a").indices.groups, undefined);
+
+	// UTF-16 offsets in both engines
+	m = /(?<x>b+)/du.exec("\u{1F600}abb");
+	assert(compareArray(m.indices.groups.x, [3, 5]), "unicode");
+	m = /(?<=a)(b)/d.exec("\u{1F600}abb");
+	assert(compareArray(m.indices[1], [3, 4]), "regexp2");
+	m = /(?<=a)(b)/du.exec("\u{1F600}abb");
+	assert(compareArray(m.indices[1], [3, 4]), "regexp2 unicode");
+
+	let all = [..."a1b2".matchAll(/[a-z](\d)/dg)].map(m => m.indices[1].join("-"));
+	assert(compareArray(all, ["1-2", "3-4"]), "matchAll");
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestRegexpModifiers(t *testing.T) {
+	const SCRIPT = `
+	assert(/a(?i:b)c/.test("aBc"), "i");
+	assert(!/a(?i:b)c/.test("aBC"), "i is scoped");
+	assert(/(?-i:a)b/i.test("aB"), "-i");
+	assert(!/(?-i:a)b/i.test("AB"), "-i is scoped");
+	assert(/(?s:.)./.test("\nx"), "s");
+	assert(!/(?s:.)./.test("\n\n"), "s is scoped");
+	assert(!/(?-s:.)/s.test("\n"), "-s");
+	assert(/(?m:^b)/.test("a\nb"), "m");
+	assert(!/(?-m:^b)/m.test("a\nb"), "-m");
+
+	// regexp2
+	assert(/(?<=a)(?i:b)/.test("aB"), "regexp2 i");
+	assert(/(?=\n)(?s:.)/.test("\n"), "regexp2 s");
+	assert(/(a)(?i:\1)/.test("aA"), "backreference");
+
+	for (const src of ["(?ii:a)", "(?i-i:a)", "(?-:a)", "(?x:a)", "(?i)", "(?=a)(?I:a)"]) {
+		assert.throws(SyntaxError, () => new RegExp(src), src);
+	}
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestRegexpEscape(t *testing.T) {
+	const SCRIPT = `
+	assert.sameValue(RegExp.escape("foo.bar"), "\\x66oo\\.bar");
+	assert.sameValue(RegExp.escape("(a+b)*c/"), "\\(a\\+b\\)\\*c\\/");
+	assert.sameValue(RegExp.escape("1-2, x\t"), "\\x31\\x2d2\\x2c\\x20x\\t");
+	assert.sameValue(RegExp.escape("_\u2028\ufeff"), "_\\u2028\\ufeff");
+	assert.sameValue(RegExp.escape("\ud800\u{1F600}"), "\\ud800\u{1F600}");
+	assert.sameValue(RegExp.escape(""), "");
+	assert.throws(TypeError, () => RegExp.escape(1));
+
+	const s = "a.b*c$^";
+	assert.sameValue(new RegExp(RegExp.escape(s)).exec("xa.b*c$^")[0], s);
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestRegexpDuplicateNamedGroups(t *testing.T) {
+	const SCRIPT = `
+	const re = /(?<y>\d{4})-\d\d|\d\d-(?<y>\d{4})/;
+	assert.sameValue(re.exec("2024-01").groups.y, "2024");
+	assert.sameValue(re.exec("01-2025").groups.y, "2025");
+	assert.sameValue("01-2025".replace(re, "$<y>"), "2025");
+	assert(compareArray(Object.keys(re.exec("01-2025").groups), ["y"]), "keys");
+
+	// regexp2 with backreferences
+	const re2 = /(?:(?<a>x)|(?<a>y))\k<a>/;
+	assert.sameValue(re2.exec("xx").groups.a, "x");
+	assert.sameValue(re2.exec("yy").groups.a, "y");
+	assert.sameValue(re2.exec("xy"), null);
+	assert.sameValue(re2.source, "(?:(?<a>x)|(?<a>y))\\k<a>");
+	assert.sameValue("yy".replace(re2, "[$<a>]"), "[y]");
+
+	let m = /(?<a>x)|(?<a>y)/dg.exec("y");
+	assert(compareArray(m.indices.groups.a, [0, 1]), "indices");
+
+	assert.throws(SyntaxError, () => new RegExp("(?<a>x)(?<a>y)"));
+	assert.throws(SyntaxError, () => new RegExp("(?<a>x)|((?<a>y)(?<a>z))"));
+	assert.throws(SyntaxError, () => new RegExp("(?:(?<a>x)|y)(?<a>z)"));
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestRegexpMatchTimeout(t *testing.T) {
+	prg := MustCompile("test.js", `
+	let res;
+	try {
+		res = /^(a+)+(?=b)\1$/.test("a".repeat(40) + "c");
+	} catch (e) {
+		res = e instanceof RangeError ? e.message : e;
+	}
+	/(?<=a)b/g[Symbol.replace]("abab", "c") + ":" + res;
+	`, false)
+
+	vm := New()
+	vm.SetRegExpMatchTimeout(50 * time.Millisecond)
+	v, err := vm.RunProgram(prg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := v.String(); s != "acac:RegExp match timeout exceeded" {
+		t.Fatal(s)
+	}
+}
+
+func TestRegexpMatchInterrupted(t *testing.T) {
+	vm := New()
+	p, err := compileRegexp(`(?=a)a`, "g")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := p.findAllSubmatchIndex(vm, asciiString("aaa"), 0, -1, false); len(res) != 3 {
+		t.Fatal(res)
+	}
+	vm.Set("match", func() {
+		vm.Interrupt("halt")
+		p.findAllSubmatchIndex(vm, asciiString("aaa"), 0, -1, false)
+		t.Error("the match is not interrupted")
+	})
+	_, err = vm.RunString(`match()`)
+	var ie *InterruptedError
+	if !errors.As(err, &ie) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ie.Value() != "halt" {
+		t.Fatal(ie.Value())
+	}
+}
+
+func TestRegexpRunningMatchInterrupted(t *testing.T) {
+	vm := New()
+	// takes a few hundred milliseconds, so it is restarted at least once to check the interrupt flag
+	v, err := vm.RunString(`/^(?=a)(?:(a+)+x|a*c)$/.test("a".repeat(21) + "c")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.ToBoolean() {
+		t.Fatal("a restarted match must still match")
+	}
+
+	time.AfterFunc(200*time.Millisecond, func() {
+		vm.Interrupt("halt")
+	})
+	done := make(chan error, 1)
+	go func() {
+		_, err := vm.RunString(`/(?=a)(a+)+$/.exec("a".repeat(40) + "b")`)
+		done <- err
+	}()
+	select {
+	case err = <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the running match is not interrupted")
+	}
+	var ie *InterruptedError
+	if !errors.As(err, &ie) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ie.Value() != "halt" {
+		t.Fatal(ie.Value())
+	}
+}
+
 func BenchmarkRegexpSplitWithBackRef(b *testing.B) {
 	const SCRIPT = `
 	"aaaaaaaaaaaaaaaaaaaaaaaaa++bbbbbbbbbbbbbbbbbbbbbb+-ccccccccccccccccccccccc".split(/([+-])\1/)
@@ -15,7 +15,7 @@ import (
 	"strconv"
 	"time"
 
-	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
 
 	js_ast "github.com/grafana/sobek/ast"
 	"github.com/grafana/sobek/file"
@@ -62,12 +62,30 @@ type global struct {
 	Proxy    *Object
 	Reflect  *Object
 	Promise  *Object
+	Iterator *Object
 	Math     *Object
 	JSON     *Object
+	Atomics  *Object
+	Intl     *Object
+
+	IntlCollator       *Object
+	IntlDateTimeFormat *Object
+	IntlNumberFormat   *Object
+	IntlPluralRules    *Object
+
+	Temporal              *Object
+	TemporalNow           *Object
+	TemporalDuration      *Object
+	TemporalInstant       *Object
+	TemporalPlainDate     *Object
+	TemporalPlainDateTime *Object
+	TemporalPlainTime     *Object
+	TemporalZonedDateTime *Object
 
 	AsyncFunction *Object
 
 	ArrayBuffer       *Object
+	SharedArrayBuffer *Object
 	DataView          *Object
 	TypedArray        *Object
 	Uint8Array        *Object
@@ -77,24 +95,30 @@ type global struct {
 	Int16Array        *Object
 	Uint32Array       *Object
 	Int32Array        *Object
+	Float16Array      *Object
 	Float32Array      *Object
 	Float64Array      *Object
 	BigInt64Array     *Object
 	BigUint64Array    *Object
 
-	WeakSet *Object
-	WeakMap *Object
-	Map     *Object
-	Set     *Object
-
-	Error          *Object
-	AggregateError *Object
-	TypeError      *Object
-	ReferenceError *Object
-	SyntaxError    *Object
-	RangeError     *Object
-	EvalError      *Object
-	URIError       *Object
+	WeakSet              *Object
+	WeakMap              *Object
+	WeakRef              *Object
+	FinalizationRegistry *Object
+	DisposableStack      *Object
+	AsyncDisposableStack *Object
+	Map                  *Object
+	Set                  *Object
+
+	Error           *Object
+	AggregateError  *Object
+	SuppressedError *Object
+	TypeError       *Object
+	ReferenceError  *Object
+	SyntaxError     *Object
+	RangeError      *Object
+	EvalError       *Object
+	URIError        *Object
 
 	GoError *Object
 
@@ -109,14 +133,31 @@ type global struct {
 	DatePrototype     *Object
 	SymbolPrototype   *Object
 
-	ArrayBufferPrototype *Object
-	DataViewPrototype    *Object
-	TypedArrayPrototype  *Object
-	WeakSetPrototype     *Object
-	WeakMapPrototype     *Object
-	MapPrototype         *Object
-	SetPrototype         *Object
-	PromisePrototype     *Object
+	ArrayBufferPrototype          *Object
+	SharedArrayBufferPrototype    *Object
+	DataViewPrototype             *Object
+	TypedArrayPrototype           *Object
+	WeakSetPrototype              *Object
+	WeakMapPrototype              *Object
+	WeakRefPrototype              *Object
+	FinalizationRegistryPrototype *Object
+	DisposableStackPrototype      *Object
+	AsyncDisposableStackPrototype *Object
+	MapPrototype                  *Object
+	SetPrototype                  *Object
+	PromisePrototype              *Object
+
+	IntlCollatorPrototype       *Object
+	IntlDateTimeFormatPrototype *Object
+	IntlNumberFormatPrototype   *Object
+	IntlPluralRulesPrototype    *Object
+
+	TemporalDurationPrototype      *Object
+	TemporalInstantPrototype       *Object
+	TemporalPlainDatePrototype     *Object
+	TemporalPlainDateTimePrototype *Object
+	TemporalPlainTimePrototype     *Object
+	TemporalZonedDateTimePrototype *Object
 
 	GeneratorFunctionPrototype *Object
 	GeneratorFunction          *Object
@@ -124,12 +165,20 @@ type global struct {
 
 	AsyncFunctionPrototype *Object
 
-	IteratorPrototype             *Object
-	ArrayIteratorPrototype        *Object
-	MapIteratorPrototype          *Object
-	SetIteratorPrototype          *Object
-	StringIteratorPrototype       *Object
-	RegExpStringIteratorPrototype *Object
+	AsyncGeneratorFunctionPrototype *Object
+	AsyncGeneratorFunction          *Object
+	AsyncGeneratorPrototype         *Object
+
+	IteratorPrototype              *Object
+	IteratorHelperPrototype        *Object
+	WrapForValidIteratorPrototype  *Object
+	AsyncIteratorPrototype         *Object
+	AsyncFromSyncIteratorPrototype *Object
+	ArrayIteratorPrototype         *Object
+	MapIteratorPrototype           *Object
+	SetIteratorPrototype           *Object
+	StringIteratorPrototype        *Object
+	RegExpStringIteratorPrototype  *Object
 
 	ErrorPrototype *Object
 
@@ -183,9 +232,12 @@ type Runtime struct {
 	stringSingleton *stringObject
 	rand            RandSource
 	now             Now
-	_collator       *collate.Collator
+	timeZone        *time.Location
+	intl            intlState
 	parserOptions   []parser.Option
 
+	regexpMatchTimeout time.Duration
+
 	symbolRegistry map[unistring.String]*Symbol
 
 	fieldsInfoCache  map[reflect.Type]*reflectFieldsInfo
@@ -197,9 +249,10 @@ type Runtime struct {
 	hash  *maphash.Hash
 	idSeq uint64
 
-	modules          map[ModuleRecord]ModuleInstance
-	moduleNamespaces map[ModuleRecord]*namespaceObject
-	importMetas      map[ModuleRecord]*Object
+	modules                  map[ModuleRecord]ModuleInstance
+	moduleNamespaces         map[ModuleRecord]*namespaceObject
+	deferredModuleNamespaces map[ModuleRecord]*namespaceObject
+	importMetas              map[ModuleRecord]*Object
 
 	getImportMetaProperties func(ModuleRecord) []MetaProperty
 	finalizeImportMeta      func(*Object, ModuleRecord)
@@ -208,6 +261,10 @@ type Runtime struct {
 
 	jobQueue []func()
 
+	keptObjects       []Value
+	finalizationQueue *finalizationQueue
+	waitAsyncQueue    *waitAsyncQueue
+
 	promiseRejectionTracker PromiseRejectionTracker
 	asyncContextTracker     AsyncContextTracker
 
@@ -435,23 +492,208 @@ func (e *Exception) Stack() []StackFrame {
 	return e.stack
 }
 
-func (r *Runtime) createIterProto(val *Object) objectImpl {
+func (r *Runtime) asyncIterProto_asyncDispose(call FunctionCall) Value {
+	pcap := r.newPromiseCapability(r.getPromise())
+	pcap.try(func() {
+		ret := toMethod(r.getV(call.This, asciiString("return")))
+		if ret == nil {
+			pcap.resolve(_undefined)
+			return
+		}
+		result := ret(FunctionCall{This: call.This, Arguments: []Value{_undefined}})
+		resultWrapper := r.promiseResolve(r.getPromise(), result)
+		onFulfilled := r.newNativeFunc(func(FunctionCall) Value {
+			return _undefined
+		}, "", 1)
+		r.performPromiseThen(resultWrapper.self.(*Promise), onFulfilled, _undefined, pcap)
+	})
+	return pcap.promise
+}
+
+func (r *Runtime) createAsyncIterProto(val *Object) objectImpl {
 	o := newBaseObjectObj(val, r.global.ObjectPrototype, classObject)
 
-	o._putSym(SymIterator, valueProp(r.newNativeFunc(r.returnThis, "[Symbol.iterator]", 0), true, false, true))
+	o._putSym(SymAsyncIterator, valueProp(r.newNativeFunc(r.returnThis, "[Symbol.asyncIterator]", 0), true, false, true))
+	o._putSym(SymAsyncDispose, valueProp(r.newNativeFunc(r.asyncIterProto_asyncDispose, "[Symbol.asyncDispose]", 0), true, false, true))
+	return o
+}
+
+func (r *Runtime) getAsyncIteratorPrototype() *Object {
+	var o *Object
+	if o = r.global.AsyncIteratorPrototype; o == nil {
+		o = &Object{runtime: r}
+		r.global.AsyncIteratorPrototype = o
+		o.self = r.createAsyncIterProto(o)
+	}
+	return o
+}
+
+type asyncFromSyncIterObject struct {
+	baseObject
+	syncIter *iteratorRecord
+}
+
+func (r *Runtime) toAsyncFromSyncIterObject(v Value, method string) *asyncFromSyncIterObject {
+	if o, ok := v.(*Object); ok {
+		if iter, ok := o.self.(*asyncFromSyncIterObject); ok {
+			return iter
+		}
+	}
+	panic(r.NewTypeError("Method %%AsyncFromSyncIteratorPrototype%%.%s called on incompatible receiver %s", method, r.objectproto_toString(FunctionCall{This: v})))
+}
+
+// closeSyncIter implements IteratorClose for the sync iterator of an async-from-sync iterator. Unlike
+// iteratorRecord.returnIter it leaves the record intact, as the methods of the wrapper can still be called.
+func closeSyncIter(syncIter *iteratorRecord) {
+	rec := *syncIter
+	rec.returnIter()
+}
+
+// asyncFromSyncIterContinuation implements AsyncFromSyncIteratorContinuation
+func (r *Runtime) asyncFromSyncIterContinuation(result *Object, pcap *promiseCapability, syncIter *iteratorRecord, closeOnRejection bool) Value {
+	var done bool
+	var valueWrapper *Object
+	ex := r.vm.try(func() {
+		done = iteratorComplete(result)
+		value := iteratorValue(result)
+		if !done && closeOnRejection {
+			ex := r.vm.try(func() {
+				valueWrapper = r.promiseResolve(r.getPromise(), value)
+			})
+			if ex != nil {
+				_ = r.vm.try(func() { closeSyncIter(syncIter) })
+				panic(ex)
+			}
+		} else {
+			valueWrapper = r.promiseResolve(r.getPromise(), value)
+		}
+	})
+	if ex != nil {
+		pcap.reject(ex.val)
+		return pcap.promise
+	}
+	onFulfilled := r.newNativeFunc(func(call FunctionCall) Value {
+		return r.createIterResultObject(call.Argument(0), done)
+	}, "", 1)
+	var onRejected Value = _undefined
+	if !done && closeOnRejection {
+		onRejected = r.newNativeFunc(func(call FunctionCall) Value {
+			_ = r.vm.try(func() { closeSyncIter(syncIter) })
+			panic(call.Argument(0))
+		}, "", 1)
+	}
+	r.performPromiseThen(valueWrapper.self.(*Promise), onFulfilled, onRejected, pcap)
+	return pcap.promise
+}
+
+func (r *Runtime) asyncFromSyncIterProto_next(call FunctionCall) Value {
+	o := r.toAsyncFromSyncIterObject(call.This, "next")
+	pcap := r.newPromiseCapability(r.getPromise())
+	syncIter := o.syncIter
+	var result *Object
+	ex := r.vm.try(func() {
+		if syncIter.next == nil {
+			panic(r.NewTypeError("iterator.next is missing or not a function"))
+		}
+		result = r.toObject(syncIter.next(FunctionCall{This: syncIter.iterator, Arguments: call.Arguments}))
+	})
+	if ex != nil {
+		pcap.reject(ex.val)
+		return pcap.promise
+	}
+	return r.asyncFromSyncIterContinuation(result, pcap, syncIter, true)
+}
+
+func (r *Runtime) asyncFromSyncIterProto_return(call FunctionCall) Value {
+	o := r.toAsyncFromSyncIterObject(call.This, "return")
+	pcap := r.newPromiseCapability(r.getPromise())
+	syncIter := o.syncIter
+	var result *Object
+	ex := r.vm.try(func() {
+		method := toMethod(syncIter.iterator.self.getStr("return", nil))
+		if method == nil {
+			return
+		}
+		res, ok := method(FunctionCall{This: syncIter.iterator, Arguments: call.Arguments}).(*Object)
+		if !ok {
+			panic(r.NewTypeError("iterator result is not an object"))
+		}
+		result = res
+	})
+	if ex != nil {
+		pcap.reject(ex.val)
+		return pcap.promise
+	}
+	if result == nil {
+		pcap.resolve(r.createIterResultObject(call.Argument(0), true))
+		return pcap.promise
+	}
+	return r.asyncFromSyncIterContinuation(result, pcap, syncIter, false)
+}
+
+func (r *Runtime) asyncFromSyncIterProto_throw(call FunctionCall) Value {
+	o := r.toAsyncFromSyncIterObject(call.This, "throw")
+	pcap := r.newPromiseCapability(r.getPromise())
+	syncIter := o.syncIter
+	var result *Object
+	ex := r.vm.try(func() {
+		method := toMethod(syncIter.iterator.self.getStr("throw", nil))
+		if method == nil {
+			closeSyncIter(syncIter)
+			panic(r.NewTypeError("The iterator does not provide a 'throw' method"))
+		}
+		res, ok := method(FunctionCall{This: syncIter.iterator, Arguments: call.Arguments}).(*Object)
+		if !ok {
+			panic(r.NewTypeError("iterator result is not an object"))
+		}
+		result = res
+	})
+	if ex != nil {
+		pcap.reject(ex.val)
+		return pcap.promise
+	}
+	return r.asyncFromSyncIterContinuation(result, pcap, syncIter, true)
+}
+
+func (r *Runtime) createAsyncFromSyncIterProto(val *Object) objectImpl {
+	o := newBaseObjectObj(val, r.getAsyncIteratorPrototype(), classObject)
+
+	o._putProp("next", r.newNativeFunc(r.asyncFromSyncIterProto_next, "next", 1), true, false, true)
+	o._putProp("return", r.newNativeFunc(r.asyncFromSyncIterProto_return, "return", 1), true, false, true)
+	o._putProp("throw", r.newNativeFunc(r.asyncFromSyncIterProto_throw, "throw", 1), true, false, true)
 	return o
 }
 
-func (r *Runtime) getIteratorPrototype() *Object {
+func (r *Runtime) getAsyncFromSyncIteratorPrototype() *Object {
 	var o *Object
-	if o = r.global.IteratorPrototype; o == nil {
+	if o = r.global.AsyncFromSyncIteratorPrototype; o == nil {
 		o = &Object{runtime: r}
-		r.global.IteratorPrototype = o
-		o.self = r.createIterProto(o)
+		r.global.AsyncFromSyncIteratorPrototype = o
+		o.self = r.createAsyncFromSyncIterProto(o)
 	}
 	return o
 }
 
+// createAsyncFromSyncIterator implements CreateAsyncFromSyncIterator
+func (r *Runtime) createAsyncFromSyncIterator(syncIter *iteratorRecord) *iteratorRecord {
+	o := &Object{runtime: r}
+	iter := &asyncFromSyncIterObject{
+		baseObject: baseObject{
+			class:      classObject,
+			val:        o,
+			extensible: true,
+			prototype:  r.getAsyncFromSyncIteratorPrototype(),
+		},
+		syncIter: syncIter,
+	}
+	o.self = iter
+	iter.init()
+	return &iteratorRecord{
+		iterator: o,
+		next:     r.asyncFromSyncIterProto_next,
+	}
+}
+
 func (r *Runtime) init() {
 	r.rand = rand.Float64
 	r.now = time.Now
@@ -591,6 +833,17 @@ func (r *Runtime) newGeneratorFunc(name unistring.String, length int, strict boo
 	return
 }
 
+func (r *Runtime) newAsyncGeneratorFunc(name unistring.String, length int, strict bool) (f *asyncGeneratorFuncObject) {
+	f = &asyncGeneratorFuncObject{}
+	r.initBaseJsFunction(&f.baseJsFuncObject, strict)
+	f.class = classFunction
+	f.prototype = r.getAsyncGeneratorFunctionPrototype()
+	f.val.self = f
+	f.init(name, intToValue(int64(length)))
+	f._putProp("prototype", r.newBaseObject(r.getAsyncGeneratorPrototype(), classObject).val, true, false, false)
+	return
+}
+
 func (r *Runtime) newClassFunc(name unistring.String, length int, proto *Object, derived bool) (f *classFuncObject) {
 	v := &Object{runtime: r}
 
@@ -634,6 +887,16 @@ func (r *Runtime) newGeneratorMethod(name unistring.String, length int, strict b
 	return
 }
 
+func (r *Runtime) newAsyncGeneratorMethod(name unistring.String, length int, strict bool) (f *asyncGeneratorMethodFuncObject) {
+	f = &asyncGeneratorMethodFuncObject{}
+	r.initBaseJsFunction(&f.baseJsFuncObject, strict)
+	f.prototype = r.getAsyncGeneratorFunctionPrototype()
+	f.val.self = f
+	f.init(name, intToValue(int64(length)))
+	f._putProp("prototype", r.newBaseObject(r.getAsyncGeneratorPrototype(), classObject).val, true, false, false)
+	return
+}
+
 func (r *Runtime) newAsyncMethod(name unistring.String, length int, strict bool) (f *asyncMethodFuncObject) {
 	f = &asyncMethodFuncObject{}
 	r.initBaseJsFunction(&f.baseJsFuncObject, strict)
@@ -1294,6 +1557,25 @@ func (r *Runtime) toIndex(v Value) int {
 	panic(r.newErrorf(r.getRangeError(), "Invalid index %s", v.String()))
 }
 
+// getOptionsObject implements GetOptionsObject. It returns nil if v is undefined.
+func (r *Runtime) getOptionsObject(v Value) *Object {
+	if o, ok := v.(*Object); ok {
+		return o
+	}
+	if v != _undefined {
+		panic(r.NewTypeError("Options must be an object"))
+	}
+	return nil
+}
+
+// getOption returns the named option from an object returned by getOptionsObject().
+func getOption(opts *Object, name unistring.String) Value {
+	if opts == nil {
+		return _undefined
+	}
+	return nilSafe(opts.self.getStr(name, nil))
+}
+
 func (r *Runtime) toBoolean(b bool) Value {
 	if b {
 		return valueTrue
@@ -1519,7 +1801,10 @@ func (r *Runtime) CaptureCallStack(depth int, stack []StackFrame) []StackFrame {
 // Interrupt a running JavaScript. The corresponding Go call will return an *InterruptedError containing v.
 // If the interrupt propagates until the stack is empty the currently queued promise resolve/reject jobs will be cleared
 // without being executed. This is the same time they would be executed otherwise.
-// Note, it only works while in JavaScript code, it does not interrupt native Go functions (which includes all built-ins).
+// Note, it only works while in JavaScript code, it does not interrupt native Go functions (which includes all built-ins),
+// with the exception of Atomics.wait() which stops waiting.
+// RegExp matches are stopped too, however a long running match of the backtracking engine only checks the interrupt
+// periodically, with a delay of up to about the time it has already run.
 // If the runtime is currently not running, it will be immediately interrupted on the next Run*() call.
 // To avoid that use ClearInterrupt()
 func (r *Runtime) Interrupt(v interface{}) {
@@ -1878,6 +2163,8 @@ func (r *Runtime) toValue(i interface{}, origValue reflect.Value) Value {
 		return floatToValue(float64(i))
 	case float64:
 		return floatToValue(i)
+	case Float16:
+		return floatToValue(i.Float64())
 	case *big.Int:
 		v := new(big.Int)
 		if i != nil {
@@ -2128,6 +2415,11 @@ func (r *Runtime) toReflectValue(v Value, dst reflect.Value, ctx *objectExportCt
 		}
 	}
 
+	if typ == typeFloat16 {
+		dst.Set(reflect.ValueOf(NewFloat16(v.ToFloat())))
+		return nil
+	}
+
 	if typ == typeTime {
 		if obj, ok := v.(*Object); ok {
 			if d, ok := obj.self.(*dateObject); ok {
@@ -2136,7 +2428,7 @@ func (r *Runtime) toReflectValue(v Value, dst reflect.Value, ctx *objectExportCt
 			}
 		}
 		if et.Kind() == reflect.String {
-			tme, ok := dateParse(v.String())
+			tme, ok := dateParse(v.String(), r.location())
 			if !ok {
 				return fmt.Errorf("could not convert string %v to %v", v, typ)
 			}
@@ -2447,6 +2739,21 @@ func (r *Runtime) SetTimeSource(now Now) {
 	r.now = now
 }
 
+// SetTimeZone sets the local time zone for this Runtime. It is used by the Date local time methods, when parsing
+// date strings without an offset, and as the default time zone of Intl.DateTimeFormat and Temporal.Now.
+// If not called or called with nil, time.Local is used.
+func (r *Runtime) SetTimeZone(loc *time.Location) {
+	r.timeZone = loc
+}
+
+// location returns the local time zone of this Runtime.
+func (r *Runtime) location() *time.Location {
+	if r.timeZone != nil {
+		return r.timeZone
+	}
+	return time.Local
+}
+
 // SetParserOptions sets parser options to be used by RunString, RunScript and eval() within the code.
 func (r *Runtime) SetParserOptions(opts ...parser.Option) {
 	r.parserOptions = opts
@@ -2461,6 +2768,43 @@ func (r *Runtime) SetMaxCallStackSize(size int) {
 	r.vm.maxCallStackSize = size
 }
 
+// SetRegExpMatchTimeout sets the maximum duration of a single RegExp match. It only applies to the patterns that
+// cannot be handled by the standard Go regexp package (e.g. the ones with backreferences or lookarounds) because
+// those are run by a backtracking engine and may take exponential time on some inputs. When exceeded, a RangeError
+// is thrown. Zero (the default) means no timeout.
+// This method (as the rest of the Set* methods) is not safe for concurrent use and may only be called
+// from the vm goroutine or when the vm is not running.
+func (r *Runtime) SetRegExpMatchTimeout(timeout time.Duration) {
+	r.regexpMatchTimeout = timeout
+}
+
+// SetDefaultLocale sets the locale used by Intl and the toLocaleString() family of methods when no locale is
+// requested or none of the requested locales is available. The extensions of the tag are ignored.
+// If not called (or called with language.Und), "en-US" is used.
+// This method (as the rest of the Set* methods) is not safe for concurrent use and may only be called
+// from the vm goroutine or when the vm is not running.
+func (r *Runtime) SetDefaultLocale(tag language.Tag) {
+	r.intl.defaultLocale = tag
+	r.intl.resetCache()
+}
+
+// SetAvailableLocales restricts the set of locales that Intl is allowed to use, the requested locales that are
+// not in the set (after removing the extensions and the trailing subtags, e.g. "de-CH" matches "de") fall back to
+// the default locale. Calling it without arguments makes all the locales with data available (the default).
+// This method (as the rest of the Set* methods) is not safe for concurrent use and may only be called
+// from the vm goroutine or when the vm is not running.
+func (r *Runtime) SetAvailableLocales(tags ...language.Tag) {
+	if len(tags) == 0 {
+		r.intl.availableLocales = nil
+	} else {
+		r.intl.availableLocales = make(map[string]struct{}, len(tags))
+		for _, tag := range tags {
+			r.intl.availableLocales[intlStripExtensions(tag.String())] = struct{}{}
+		}
+	}
+	r.intl.resetCache()
+}
+
 // New is an equivalent of the 'new' operator allowing to call it directly from Go.
 func (r *Runtime) New(construct Value, args ...Value) (o *Object, err error) {
 	err = r.try(func() {
@@ -2735,6 +3079,20 @@ func (r *Runtime) getIterator(obj Value, method func(FunctionCall) Value) *itera
 	}
 }
 
+// getAsyncIterator implements GetIterator(obj, async). If obj does not have a Symbol.asyncIterator method,
+// its sync iterator is wrapped with CreateAsyncFromSyncIterator.
+func (r *Runtime) getAsyncIterator(obj Value) *iteratorRecord {
+	method := toMethod(r.getV(obj, SymAsyncIterator))
+	if method == nil {
+		syncMethod := toMethod(r.getV(obj, SymIterator))
+		if syncMethod == nil {
+			panic(r.NewTypeError("object is not async iterable"))
+		}
+		return r.createAsyncFromSyncIterator(r.getIterator(obj, syncMethod))
+	}
+	return r.getIterator(obj, method)
+}
+
 func iteratorComplete(iterResult *Object) bool {
 	return nilSafe(iterResult.self.getStr("done", nil)).ToBoolean()
 }
@@ -2852,12 +3210,14 @@ func (r *Runtime) leave() {
 		}
 	}
 	r.jobQueue = nil
+	r.keptObjects = nil
 	r.vm.stack = nil
 }
 
 // called when the top level function returns (i.e. control is passed outside the Runtime) but it was due to an interrupt
 func (r *Runtime) leaveAbrupt() {
 	r.jobQueue = nil
+	r.keptObjects = nil
 	r.ClearInterrupt()
 }
 
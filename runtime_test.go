@@ -2768,6 +2768,52 @@ func TestStringToBytesConversion(t *testing.T) {
 	}
 }
 
+func TestPromiseTry(t *testing.T) {
+	const SCRIPT = `
+	assert.sameValue(await Promise.try((a, b) => a + b, 1, 2), 3);
+	assert.sameValue(await Promise.try(() => Promise.resolve(4)), 4);
+	let called = false;
+	const p = Promise.try(function() {
+		"use strict";
+		called = true;
+		assert.sameValue(this, undefined);
+		throw new Error("boom");
+	});
+	assert(called, "callback is called synchronously");
+	try {
+		await p;
+		throw new Error("should not reach");
+	} catch (e) {
+		assert.sameValue(e.message, "boom");
+	}
+	try {
+		await Promise.try(1);
+		throw new Error("should not reach");
+	} catch (e) {
+		assert(e instanceof TypeError, e);
+	}
+	assert.throws(TypeError, () => Promise.try.call({}, () => {}));
+	`
+	testAsyncFuncWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestPromiseWithResolvers(t *testing.T) {
+	const SCRIPT = `
+	const {promise, resolve, reject} = Promise.withResolvers();
+	assert(promise instanceof Promise);
+	resolve(42);
+	reject(new Error("ignored"));
+	assert.sameValue(await promise, 42);
+
+	class MyPromise extends Promise {}
+	const r = MyPromise.withResolvers();
+	assert(r.promise instanceof MyPromise);
+	assert.sameValue(Object.keys(r).join(), "promise,resolve,reject");
+	assert.throws(TypeError, () => Promise.withResolvers.call(() => {}));
+	`
+	testAsyncFuncWithTestLib(SCRIPT, _undefined, t)
+}
+
 func TestPromiseAll(t *testing.T) {
 	const SCRIPT = `
 var p1 = new Promise(function() {});
@@ -31,15 +31,16 @@ var (
 	stringBound_      String = asciiString("bound ")
 	stringEmpty       String = asciiString("")
 
-	stringError          String = asciiString("Error")
-	stringAggregateError String = asciiString("AggregateError")
-	stringTypeError      String = asciiString("TypeError")
-	stringReferenceError String = asciiString("ReferenceError")
-	stringSyntaxError    String = asciiString("SyntaxError")
-	stringRangeError     String = asciiString("RangeError")
-	stringEvalError      String = asciiString("EvalError")
-	stringURIError       String = asciiString("URIError")
-	stringGoError        String = asciiString("GoError")
+	stringError           String = asciiString("Error")
+	stringAggregateError  String = asciiString("AggregateError")
+	stringSuppressedError String = asciiString("SuppressedError")
+	stringTypeError       String = asciiString("TypeError")
+	stringReferenceError  String = asciiString("ReferenceError")
+	stringSyntaxError     String = asciiString("SyntaxError")
+	stringRangeError      String = asciiString("RangeError")
+	stringEvalError       String = asciiString("EvalError")
+	stringURIError        String = asciiString("URIError")
+	stringGoError         String = asciiString("GoError")
 
 	stringObjectNull      String = asciiString("[object Null]")
 	stringObjectUndefined String = asciiString("[object Undefined]")
@@ -70,6 +71,11 @@ type String interface {
 	toLower() String
 	toUpper() String
 	toTrimmedUTF8() string
+	toWellFormed() String
+
+	// IsWellFormed returns false if the string contains lone surrogates. Such strings cannot be converted
+	// to UTF-8 (e.g. by String() or Export()) without replacing them with U+FFFD.
+	IsWellFormed() bool
 }
 
 type stringIterObject struct {
@@ -388,6 +388,14 @@ func (s asciiString) toTrimmedUTF8() string {
 	return strings.TrimSpace(string(s))
 }
 
+func (s asciiString) IsWellFormed() bool {
+	return true
+}
+
+func (s asciiString) toWellFormed() String {
+	return s
+}
+
 func (s asciiString) string() unistring.String {
 	return unistring.String(s)
 }
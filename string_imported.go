@@ -305,3 +305,12 @@ func (i *importedString) toUpper() String {
 func (i *importedString) toTrimmedUTF8() string {
 	return strings.Trim(i.s, parser.WhitespaceChars)
 }
+
+// IsWellFormed always returns true because Go strings are decoded with invalid UTF-8 sequences replaced by U+FFFD.
+func (i *importedString) IsWellFormed() bool {
+	return true
+}
+
+func (i *importedString) toWellFormed() String {
+	return i
+}
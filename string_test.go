@@ -166,6 +166,21 @@ func TestStringFromUTF16(t *testing.T) {
 	}
 }
 
+func TestStringIsWellFormed(t *testing.T) {
+	if s := StringFromUTF16([]uint16{'a', 0xD83D, 0xDE00}); !s.IsWellFormed() {
+		t.Fatal(s)
+	}
+	if s := StringFromUTF16([]uint16{'a', 0xD800}); s.IsWellFormed() {
+		t.Fatal(s)
+	}
+	if s := newStringValue("ascii"); !s.IsWellFormed() {
+		t.Fatal(s)
+	}
+	if s := (&importedString{s: "imported_юникод"}); !s.IsWellFormed() {
+		t.Fatal(s)
+	}
+}
+
 func TestStringBuilder(t *testing.T) {
 	t.Run("writeUTF8String-switch", func(t *testing.T) {
 		var sb StringBuilder
@@ -393,6 +393,41 @@ func (s unicodeString) toTrimmedUTF8() string {
 	return strings.Trim(s.String(), parser.WhitespaceChars)
 }
 
+// loneSurrogate returns the index of the first lone surrogate at or after pos, or -1 if there are none.
+func (s unicodeString) loneSurrogate(pos int) int {
+	for i := pos; i < len(s); i++ {
+		c := s[i]
+		if isUTF16FirstSurrogate(c) {
+			if i+1 < len(s) && isUTF16SecondSurrogate(s[i+1]) {
+				i++
+				continue
+			}
+			return i
+		}
+		if isUTF16SecondSurrogate(c) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s unicodeString) IsWellFormed() bool {
+	return s.loneSurrogate(1) == -1
+}
+
+func (s unicodeString) toWellFormed() String {
+	i := s.loneSurrogate(1)
+	if i == -1 {
+		return s
+	}
+	b := make(unicodeString, len(s))
+	copy(b, s)
+	for ; i != -1; i = b.loneSurrogate(i + 1) {
+		b[i] = 0xFFFD
+	}
+	return b
+}
+
 func (s unicodeString) ToNumber() Value {
 	return asciiString(s.toTrimmedUTF8()).ToNumber()
 }
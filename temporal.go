@@ -0,0 +1,1153 @@
+package sobek
+
+import (
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// This file contains the calendar and time arithmetic used by the Temporal built-ins. Only the ISO 8601
+// calendar is supported. Epoch nanoseconds and time durations exceed the int64 range and are kept
+// as *big.Int, the arguments of the functions below are never modified.
+
+const (
+	nsPerMicrosecond = 1000
+	nsPerMillisecond = 1000 * nsPerMicrosecond
+	nsPerSecond      = 1000 * nsPerMillisecond
+	nsPerMinute      = 60 * nsPerSecond
+	nsPerHour        = 60 * nsPerMinute
+	nsPerDay         = 24 * nsPerHour
+
+	// the epoch days of -271821-04-19 and +275760-09-13
+	temporalMinEpochDays = -100000001
+	temporalMaxEpochDays = 100000000
+)
+
+var (
+	bigNsPerDay           = big.NewInt(nsPerDay)
+	bigNsPerSecond        = big.NewInt(nsPerSecond)
+	temporalMaxEpochNs    = new(big.Int).Mul(big.NewInt(1e8), bigNsPerDay)
+	temporalMinEpochNs    = new(big.Int).Neg(temporalMaxEpochNs)
+	temporalMaxTimeDurNs  = new(big.Int).Sub(new(big.Int).Mul(big.NewInt(1<<53), bigNsPerSecond), big.NewInt(1))
+	temporalMaxDateFields = float64(1 << 32)
+)
+
+type temporalUnit int
+
+const (
+	temporalUnitUnset temporalUnit = iota - 2
+	temporalUnitAuto
+	temporalYear
+	temporalMonth
+	temporalWeek
+	temporalDay
+	temporalHour
+	temporalMinute
+	temporalSecond
+	temporalMillisecond
+	temporalMicrosecond
+	temporalNanosecond
+)
+
+var temporalUnitNames = [...]string{
+	temporalYear:        "year",
+	temporalMonth:       "month",
+	temporalWeek:        "week",
+	temporalDay:         "day",
+	temporalHour:        "hour",
+	temporalMinute:      "minute",
+	temporalSecond:      "second",
+	temporalMillisecond: "millisecond",
+	temporalMicrosecond: "microsecond",
+	temporalNanosecond:  "nanosecond",
+}
+
+var temporalUnitLengths = [...]int64{
+	temporalDay:         nsPerDay,
+	temporalHour:        nsPerHour,
+	temporalMinute:      nsPerMinute,
+	temporalSecond:      nsPerSecond,
+	temporalMillisecond: nsPerMillisecond,
+	temporalMicrosecond: nsPerMicrosecond,
+	temporalNanosecond:  1,
+}
+
+func (u temporalUnit) String() string {
+	return temporalUnitNames[u]
+}
+
+func (u temporalUnit) isCalendarUnit() bool {
+	return u >= temporalYear && u <= temporalWeek
+}
+
+func (u temporalUnit) isDateUnit() bool {
+	return u >= temporalYear && u <= temporalDay
+}
+
+// largerTemporalUnit implements LargerOfTwoTemporalUnits.
+func largerTemporalUnit(u1, u2 temporalUnit) temporalUnit {
+	if u1 < u2 {
+		return u1
+	}
+	return u2
+}
+
+// maximumRoundingIncrement implements MaximumTemporalDurationRoundingIncrement, 0 means there is no maximum.
+func (u temporalUnit) maximumRoundingIncrement() int64 {
+	switch u {
+	case temporalHour:
+		return 24
+	case temporalMinute, temporalSecond:
+		return 60
+	case temporalMillisecond, temporalMicrosecond, temporalNanosecond:
+		return 1000
+	}
+	return 0
+}
+
+func floorDivInt64(x, y int64) int64 {
+	q := x / y
+	if (x%y != 0) && ((x < 0) != (y < 0)) {
+		q--
+	}
+	return q
+}
+
+func floorModInt64(x, y int64) int64 {
+	return x - floorDivInt64(x, y)*y
+}
+
+func signInt64(x int64) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
+
+// Rounding
+
+const (
+	unsignedRoundZero = iota
+	unsignedRoundInfinity
+	unsignedRoundHalfZero
+	unsignedRoundHalfInfinity
+	unsignedRoundHalfEven
+)
+
+var temporalRoundingModes = []string{"ceil", "floor", "expand", "trunc", "halfCeil", "halfFloor", "halfExpand", "halfTrunc", "halfEven"}
+
+// unsignedRoundingMode implements GetUnsignedRoundingMode.
+func unsignedRoundingMode(mode string, negative bool) int {
+	switch mode {
+	case "ceil":
+		if negative {
+			return unsignedRoundZero
+		}
+		return unsignedRoundInfinity
+	case "floor":
+		if negative {
+			return unsignedRoundInfinity
+		}
+		return unsignedRoundZero
+	case "expand":
+		return unsignedRoundInfinity
+	case "trunc":
+		return unsignedRoundZero
+	case "halfCeil":
+		if negative {
+			return unsignedRoundHalfZero
+		}
+		return unsignedRoundHalfInfinity
+	case "halfFloor":
+		if negative {
+			return unsignedRoundHalfInfinity
+		}
+		return unsignedRoundHalfZero
+	case "halfExpand":
+		return unsignedRoundHalfInfinity
+	case "halfTrunc":
+		return unsignedRoundHalfZero
+	}
+	return unsignedRoundHalfEven
+}
+
+// negateRoundingMode implements NegateRoundingMode.
+func negateRoundingMode(mode string) string {
+	switch mode {
+	case "ceil":
+		return "floor"
+	case "floor":
+		return "ceil"
+	case "halfCeil":
+		return "halfFloor"
+	case "halfFloor":
+		return "halfCeil"
+	}
+	return mode
+}
+
+// roundsUp implements the decision part of ApplyUnsignedRoundingMode for a value that lies strictly
+// between r1 and r2. cmpHalf is the result of comparing the distance from r1 with the distance to r2,
+// oddR1 reports whether r1 is odd.
+func roundsUp(mode int, cmpHalf int, oddR1 bool) bool {
+	switch mode {
+	case unsignedRoundZero:
+		return false
+	case unsignedRoundInfinity:
+		return true
+	}
+	if cmpHalf != 0 {
+		return cmpHalf > 0
+	}
+	switch mode {
+	case unsignedRoundHalfZero:
+		return false
+	case unsignedRoundHalfInfinity:
+		return true
+	}
+	return oddR1
+}
+
+// roundBigToIncrement implements RoundNumberToIncrement for integers.
+func roundBigToIncrement(x, increment *big.Int, mode string) *big.Int {
+	q, rem := new(big.Int).QuoRem(x, increment, new(big.Int))
+	if rem.Sign() == 0 {
+		return new(big.Int).Set(x)
+	}
+	negative := x.Sign() < 0
+	rem.Abs(rem).Lsh(rem, 1)
+	if roundsUp(unsignedRoundingMode(mode, negative), rem.Cmp(increment), q.Bit(0) == 1) {
+		if negative {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q.Mul(q, increment)
+}
+
+// roundBigToIncrementAsIfPositive implements RoundNumberToIncrementAsIfPositive for integers.
+func roundBigToIncrementAsIfPositive(x, increment *big.Int, mode string) *big.Int {
+	q, m := new(big.Int).DivMod(x, increment, new(big.Int))
+	if m.Sign() == 0 {
+		return new(big.Int).Set(x)
+	}
+	m.Lsh(m, 1)
+	if roundsUp(unsignedRoundingMode(mode, false), m.Cmp(increment), q.Bit(0) == 1) {
+		q.Add(q, big.NewInt(1))
+	}
+	return q.Mul(q, increment)
+}
+
+// roundInt64ToIncrement implements RoundNumberToIncrement for values that fit int64.
+func roundInt64ToIncrement(x, increment int64, mode string) int64 {
+	q, rem := x/increment, x%increment
+	if rem == 0 {
+		return x
+	}
+	negative := x < 0
+	if rem < 0 {
+		rem = -rem
+	}
+	cmpHalf := 0
+	if d := 2*rem - increment; d > 0 {
+		cmpHalf = 1
+	} else if d < 0 {
+		cmpHalf = -1
+	}
+	if roundsUp(unsignedRoundingMode(mode, negative), cmpHalf, q%2 != 0) {
+		if negative {
+			q--
+		} else {
+			q++
+		}
+	}
+	return q * increment
+}
+
+// ISO dates
+
+type isoDate struct {
+	year, month, day int
+}
+
+func isISOLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+func isoDaysInYear(year int) int {
+	if isISOLeapYear(year) {
+		return 366
+	}
+	return 365
+}
+
+func isoDaysInMonth(year, month int) int {
+	switch month {
+	case 2:
+		if isISOLeapYear(year) {
+			return 29
+		}
+		return 28
+	case 4, 6, 9, 11:
+		return 30
+	}
+	return 31
+}
+
+func isValidISODate(year, month, day int64) bool {
+	return month >= 1 && month <= 12 && day >= 1 && year > math.MinInt32 && year < math.MaxInt32 &&
+		day <= int64(isoDaysInMonth(int(year), int(month)))
+}
+
+// isValidISODateFloat implements IsValidISODate for the results of ToIntegerWithTruncation.
+func isValidISODateFloat(year, month, day float64) bool {
+	return math.Abs(year) < 1e9 && isValidISODate(int64(year), int64(min(max(month, 0), 13)), int64(min(max(day, 0), 32)))
+}
+
+// epochDays implements ISODateToEpochDays for a date that is not necessarily valid.
+func isoDateToEpochDays(year, month, day int64) int64 {
+	year += floorDivInt64(month-1, 12)
+	month = floorModInt64(month-1, 12) + 1
+	if month <= 2 {
+		year--
+	}
+	era := floorDivInt64(year, 400)
+	yoe := year - era*400
+	doy := (153*((month+9)%12)+2)/5 + day - 1
+	doe := yoe*365 + yoe/4 - yoe/100 + doy
+	return era*146097 + doe - 719468
+}
+
+func (d isoDate) epochDays() int64 {
+	return isoDateToEpochDays(int64(d.year), int64(d.month), int64(d.day))
+}
+
+func isoDateFromEpochDays(days int64) isoDate {
+	days += 719468
+	era := floorDivInt64(days, 146097)
+	doe := days - era*146097
+	yoe := (doe - doe/1460 + doe/36524 - doe/146096) / 365
+	year := yoe + era*400
+	doy := doe - (365*yoe + yoe/4 - yoe/100)
+	mp := (5*doy + 2) / 153
+	day := doy - (153*mp+2)/5 + 1
+	month := mp + 3
+	if month > 12 {
+		month -= 12
+	}
+	if month <= 2 {
+		year++
+	}
+	return isoDate{year: int(year), month: int(month), day: int(day)}
+}
+
+// balanceISODate implements BalanceISODate.
+func balanceISODate(year, month, day int64) isoDate {
+	return isoDateFromEpochDays(isoDateToEpochDays(year, month, day))
+}
+
+func (d isoDate) addDays(days int64) isoDate {
+	return isoDateFromEpochDays(d.epochDays() + days)
+}
+
+// balanceISOYearMonth implements BalanceISOYearMonth.
+func balanceISOYearMonth(year, month int64) (int64, int64) {
+	return year + floorDivInt64(month-1, 12), floorModInt64(month-1, 12) + 1
+}
+
+func compareISODate(d1, d2 isoDate) int {
+	switch {
+	case d1.year != d2.year:
+		return signInt64(int64(d1.year - d2.year))
+	case d1.month != d2.month:
+		return signInt64(int64(d1.month - d2.month))
+	}
+	return signInt64(int64(d1.day - d2.day))
+}
+
+// dayOfWeek returns the ISO day of week, 1 is Monday.
+func (d isoDate) dayOfWeek() int {
+	return int(floorModInt64(d.epochDays()+3, 7)) + 1
+}
+
+func (d isoDate) dayOfYear() int {
+	return int(d.epochDays()-isoDateToEpochDays(int64(d.year), 1, 1)) + 1
+}
+
+func isoWeeksInYear(year int) int {
+	jan1 := isoDate{year: year, month: 1, day: 1}.dayOfWeek()
+	if jan1 == 4 || jan1 == 3 && isISOLeapYear(year) {
+		return 53
+	}
+	return 52
+}
+
+// weekOfYear returns the ISO week number and the year the week belongs to.
+func (d isoDate) weekOfYear() (week, year int) {
+	week = (d.dayOfYear() - d.dayOfWeek() + 10) / 7
+	year = d.year
+	if week < 1 {
+		year--
+		week = isoWeeksInYear(year)
+	} else if week > isoWeeksInYear(year) {
+		year++
+		week = 1
+	}
+	return
+}
+
+func isoDateWithinLimits(d isoDate) bool {
+	days := d.epochDays()
+	return days >= temporalMinEpochDays && days <= temporalMaxEpochDays
+}
+
+// regulateISODate implements RegulateISODate, ok is false if the date is invalid and overflow is "reject".
+func regulateISODate(year, month, day int64, overflow string) (isoDate, bool) {
+	if overflow == "constrain" {
+		month = min(max(month, 1), 12)
+		if year > math.MinInt32 && year < math.MaxInt32 {
+			day = min(max(day, 1), int64(isoDaysInMonth(int(year), int(month))))
+		}
+	}
+	if !isValidISODate(year, month, day) {
+		return isoDate{}, false
+	}
+	return isoDate{year: int(year), month: int(month), day: int(day)}, true
+}
+
+// ISO times
+
+type isoTime struct {
+	hour, minute, second, millisecond, microsecond, nanosecond int
+}
+
+func (t isoTime) nanos() int64 {
+	return int64(t.hour)*nsPerHour + int64(t.minute)*nsPerMinute + int64(t.second)*nsPerSecond +
+		int64(t.millisecond)*nsPerMillisecond + int64(t.microsecond)*nsPerMicrosecond + int64(t.nanosecond)
+}
+
+func isoTimeFromNanos(ns int64) isoTime {
+	return isoTime{
+		hour:        int(ns / nsPerHour),
+		minute:      int(ns / nsPerMinute % 60),
+		second:      int(ns / nsPerSecond % 60),
+		millisecond: int(ns / nsPerMillisecond % 1000),
+		microsecond: int(ns / nsPerMicrosecond % 1000),
+		nanosecond:  int(ns % 1000),
+	}
+}
+
+func compareISOTime(t1, t2 isoTime) int {
+	return signInt64(t1.nanos() - t2.nanos())
+}
+
+func isValidTime(h, m, s, ms, us, ns float64) bool {
+	return h >= 0 && h <= 23 && m >= 0 && m <= 59 && s >= 0 && s <= 59 &&
+		ms >= 0 && ms <= 999 && us >= 0 && us <= 999 && ns >= 0 && ns <= 999
+}
+
+// regulateTime implements RegulateTime, ok is false if the time is invalid and overflow is "reject".
+func regulateTime(h, m, s, ms, us, ns float64, overflow string) (isoTime, bool) {
+	if overflow == "constrain" {
+		h = min(max(h, 0), 23)
+		m = min(max(m, 0), 59)
+		s = min(max(s, 0), 59)
+		ms = min(max(ms, 0), 999)
+		us = min(max(us, 0), 999)
+		ns = min(max(ns, 0), 999)
+	} else if !isValidTime(h, m, s, ms, us, ns) {
+		return isoTime{}, false
+	}
+	return isoTime{hour: int(h), minute: int(m), second: int(s), millisecond: int(ms), microsecond: int(us), nanosecond: int(ns)}, true
+}
+
+// addTime implements AddTime, it returns the number of days the result overflows into and the time.
+func addTime(t isoTime, d *big.Int) (int64, isoTime) {
+	ns := new(big.Int).Add(big.NewInt(t.nanos()), d)
+	days, rem := ns.DivMod(ns, bigNsPerDay, new(big.Int))
+	return days.Int64(), isoTimeFromNanos(rem.Int64())
+}
+
+// roundISOTime implements RoundTime, it returns the number of days the result overflows into and the time.
+func roundISOTime(t isoTime, increment int64, unit temporalUnit, mode string) (int64, isoTime) {
+	ns := roundInt64ToIncrement(t.nanos(), increment*temporalUnitLengths[unit], mode)
+	return ns / nsPerDay, isoTimeFromNanos(ns % nsPerDay)
+}
+
+// ISO date-times
+
+type isoDateTime struct {
+	date isoDate
+	time isoTime
+}
+
+func compareISODateTime(dt1, dt2 isoDateTime) int {
+	if c := compareISODate(dt1.date, dt2.date); c != 0 {
+		return c
+	}
+	return compareISOTime(dt1.time, dt2.time)
+}
+
+// utcEpochNs implements GetUTCEpochNanoseconds.
+func (dt isoDateTime) utcEpochNs() *big.Int {
+	ns := big.NewInt(dt.date.epochDays())
+	ns.Mul(ns, bigNsPerDay)
+	return ns.Add(ns, big.NewInt(dt.time.nanos()))
+}
+
+func isoDateTimeWithinLimits(dt isoDateTime) bool {
+	days := dt.date.epochDays()
+	if days == temporalMinEpochDays {
+		return dt.time.nanos() > 0
+	}
+	return days > temporalMinEpochDays && days <= temporalMaxEpochDays
+}
+
+// isoDateTimeFromEpochNs returns the date-time at the given offset from UTC.
+func isoDateTimeFromEpochNs(epochNs *big.Int, offsetNs int64) isoDateTime {
+	ns := new(big.Int).Add(epochNs, big.NewInt(offsetNs))
+	days, rem := ns.DivMod(ns, bigNsPerDay, new(big.Int))
+	return isoDateTime{date: isoDateFromEpochDays(days.Int64()), time: isoTimeFromNanos(rem.Int64())}
+}
+
+// addNanos returns the date-time shifted by the given number of nanoseconds.
+func (dt isoDateTime) addNanos(ns int64) isoDateTime {
+	days, t := addTime(dt.time, big.NewInt(ns))
+	return isoDateTime{date: dt.date.addDays(days), time: t}
+}
+
+// roundISODateTime implements RoundISODateTime.
+func roundISODateTime(dt isoDateTime, increment int64, unit temporalUnit, mode string) isoDateTime {
+	days, t := roundISOTime(dt.time, increment, unit, mode)
+	return isoDateTime{date: dt.date.addDays(days), time: t}
+}
+
+func isValidEpochNs(ns *big.Int) bool {
+	return ns.CmpAbs(temporalMaxEpochNs) <= 0
+}
+
+// Durations
+
+// temporalDuration holds the fields of a Temporal.Duration indexed by temporalUnit - temporalYear.
+type temporalDuration [10]float64
+
+func (d *temporalDuration) field(u temporalUnit) float64 {
+	return d[u-temporalYear]
+}
+
+func (d *temporalDuration) sign() int {
+	for _, v := range d {
+		if v < 0 {
+			return -1
+		}
+		if v > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+func (d *temporalDuration) negated() temporalDuration {
+	var res temporalDuration
+	for i, v := range d {
+		if v != 0 {
+			res[i] = -v
+		}
+	}
+	return res
+}
+
+// defaultLargestUnit implements DefaultTemporalLargestUnit.
+func (d *temporalDuration) defaultLargestUnit() temporalUnit {
+	for i, v := range d {
+		if v != 0 {
+			return temporalYear + temporalUnit(i)
+		}
+	}
+	return temporalNanosecond
+}
+
+func bigFromFloat(f float64) *big.Int {
+	i, _ := big.NewFloat(f).Int(nil)
+	return i
+}
+
+func floatFromBig(i *big.Int) float64 {
+	f, _ := new(big.Float).SetInt(i).Float64()
+	return f
+}
+
+// timeDuration implements TimeDurationFromComponents for the time fields of the duration.
+func (d *temporalDuration) timeDuration() *big.Int {
+	res := new(big.Int)
+	for u := temporalHour; u <= temporalNanosecond; u++ {
+		if v := d.field(u); v != 0 {
+			res.Add(res, new(big.Int).Mul(bigFromFloat(v), big.NewInt(temporalUnitLengths[u])))
+		}
+	}
+	return res
+}
+
+// isValid implements IsValidDuration.
+func (d *temporalDuration) isValid() bool {
+	sign := d.sign()
+	for _, v := range d {
+		if math.IsNaN(v) || math.IsInf(v, 0) || v < 0 && sign > 0 || v > 0 && sign < 0 {
+			return false
+		}
+	}
+	for u := temporalYear; u <= temporalWeek; u++ {
+		if math.Abs(d.field(u)) >= temporalMaxDateFields {
+			return false
+		}
+	}
+	total := d.timeDuration()
+	total.Add(total, new(big.Int).Mul(bigFromFloat(d.field(temporalDay)), bigNsPerDay))
+	return total.CmpAbs(temporalMaxTimeDurNs) <= 0
+}
+
+type dateDuration struct {
+	years, months, weeks, days int64
+}
+
+func (d dateDuration) sign() int {
+	for _, v := range [...]int64{d.years, d.months, d.weeks, d.days} {
+		if v != 0 {
+			return signInt64(v)
+		}
+	}
+	return 0
+}
+
+// internalDuration is the Internal Duration Record.
+type internalDuration struct {
+	date dateDuration
+	time *big.Int
+}
+
+func (d internalDuration) sign() int {
+	if s := d.date.sign(); s != 0 {
+		return s
+	}
+	return d.time.Sign()
+}
+
+// internal implements ToInternalDurationRecord.
+func (d *temporalDuration) internal() internalDuration {
+	return internalDuration{
+		date: dateDuration{
+			years:  int64(d.field(temporalYear)),
+			months: int64(d.field(temporalMonth)),
+			weeks:  int64(d.field(temporalWeek)),
+			days:   int64(d.field(temporalDay)),
+		},
+		time: d.timeDuration(),
+	}
+}
+
+// internalWith24HourDays implements ToInternalDurationRecordWith24HourDays.
+func (d *temporalDuration) internalWith24HourDays() internalDuration {
+	res := d.internal()
+	res.time = add24HourDays(res.time, res.date.days)
+	res.date.days = 0
+	return res
+}
+
+// dateDurationWithoutTime implements ToDateDurationRecordWithoutTime.
+func (d *temporalDuration) dateDurationWithoutTime() dateDuration {
+	res := d.internalWith24HourDays()
+	res.date.days = new(big.Int).Quo(res.time, bigNsPerDay).Int64()
+	return res.date
+}
+
+func add24HourDays(d *big.Int, days int64) *big.Int {
+	res := new(big.Int).Mul(big.NewInt(days), bigNsPerDay)
+	return res.Add(res, d)
+}
+
+// durationFromInternal implements TemporalDurationFromInternal, ok is false if the result is not a valid duration.
+func durationFromInternal(d internalDuration, largestUnit temporalUnit) (temporalDuration, bool) {
+	var res temporalDuration
+	sign := d.time.Sign()
+	ns := new(big.Int).Abs(d.time)
+	var fields [7]*big.Int // days .. nanoseconds
+	for i := range fields {
+		fields[i] = new(big.Int)
+	}
+	fields[6].Set(ns)
+	if largestUnit.isDateUnit() {
+		largestUnit = temporalDay
+	}
+	divisors := [...]int64{24, 60, 60, 1000, 1000, 1000}
+	for u := temporalNanosecond; u > largestUnit; u-- {
+		i := u - temporalDay
+		fields[i-1], fields[i] = new(big.Int).QuoRem(fields[i], big.NewInt(divisors[i-1]), new(big.Int))
+	}
+	res[temporalYear-temporalYear] = float64(d.date.years)
+	res[temporalMonth-temporalYear] = float64(d.date.months)
+	res[temporalWeek-temporalYear] = float64(d.date.weeks)
+	for i, f := range fields {
+		if sign < 0 {
+			f.Neg(f)
+		}
+		if i == 0 {
+			f.Add(f, big.NewInt(d.date.days))
+		}
+		res[temporalDay-temporalYear+temporalUnit(i)] = floatFromBig(f)
+	}
+	for i, v := range res {
+		if v == 0 {
+			res[i] = 0 // no negative zeros
+		}
+	}
+	return res, res.isValid()
+}
+
+// Time zones
+
+// temporalTimeZone is a time zone identified either by an IANA name or by a fixed UTC offset.
+type temporalTimeZone struct {
+	id       string
+	offsetNs int64
+	loc      *temporalLocation
+}
+
+func (tz *temporalTimeZone) isOffset() bool {
+	return tz.loc == nil
+}
+
+// equals implements TimeZoneEquals.
+func (tz *temporalTimeZone) equals(other *temporalTimeZone) bool {
+	if tz.id == other.id {
+		return true
+	}
+	if tz.isOffset() || other.isOffset() {
+		return false
+	}
+	return tz.loc.primary() == other.loc.primary()
+}
+
+// location returns the time zone as a time.Location.
+func (tz *temporalTimeZone) location() *time.Location {
+	if tz.isOffset() {
+		return time.FixedZone(tz.id, int(tz.offsetNs/nsPerSecond))
+	}
+	return tz.loc.loc
+}
+
+// offsetNsFor implements GetOffsetNanosecondsFor.
+func (tz *temporalTimeZone) offsetNsFor(epochNs *big.Int) int64 {
+	if tz.isOffset() {
+		return tz.offsetNs
+	}
+	return int64(tz.loc.offsetAt(epochSeconds(epochNs))) * nsPerSecond
+}
+
+// isoDateTimeFor implements GetISODateTimeFor.
+func (tz *temporalTimeZone) isoDateTimeFor(epochNs *big.Int) isoDateTime {
+	return isoDateTimeFromEpochNs(epochNs, tz.offsetNsFor(epochNs))
+}
+
+// epochSeconds returns the floor of the epoch seconds.
+func epochSeconds(epochNs *big.Int) int64 {
+	q, _ := new(big.Int).DivMod(epochNs, bigNsPerSecond, new(big.Int))
+	return q.Int64()
+}
+
+// possibleEpochNs implements GetPossibleEpochNanoseconds without the range checks. The date-time
+// must be within 1e8+1 days of the epoch.
+func (tz *temporalTimeZone) possibleEpochNs(dt isoDateTime) []*big.Int {
+	utc := dt.utcEpochNs()
+	if tz.isOffset() {
+		return []*big.Int{utc.Sub(utc, big.NewInt(tz.offsetNs))}
+	}
+	sec := epochSeconds(utc)
+	var res []*big.Int
+	var seen [3]int
+	for i, delta := range [...]int64{-86400, 0, 86400} {
+		offset := tz.loc.offsetAt(sec + delta)
+		if i > 0 && (offset == seen[0] || i == 2 && offset == seen[1]) {
+			continue
+		}
+		seen[i] = offset
+		candidate := new(big.Int).Sub(utc, new(big.Int).Mul(big.NewInt(int64(offset)), bigNsPerSecond))
+		if tz.loc.offsetAt(epochSeconds(candidate)) == offset {
+			res = append(res, candidate)
+		}
+	}
+	if len(res) == 2 && res[0].Cmp(res[1]) > 0 {
+		res[0], res[1] = res[1], res[0]
+	}
+	return res
+}
+
+// nextTransition implements GetNamedTimeZoneNextTransition, it returns nil if there is none.
+func (tz *temporalTimeZone) nextTransition(epochNs *big.Int) *big.Int {
+	if tz.isOffset() {
+		return nil
+	}
+	sec, ok := tz.loc.nextTransition(epochSeconds(epochNs))
+	if !ok {
+		return nil
+	}
+	res := new(big.Int).Mul(big.NewInt(sec), bigNsPerSecond)
+	if !isValidEpochNs(res) {
+		return nil
+	}
+	return res
+}
+
+// previousTransition implements GetNamedTimeZonePreviousTransition, it returns nil if there is none.
+func (tz *temporalTimeZone) previousTransition(epochNs *big.Int) *big.Int {
+	if tz.isOffset() {
+		return nil
+	}
+	// the last whole second before epochNs
+	sec := epochSeconds(new(big.Int).Sub(epochNs, big.NewInt(1)))
+	if sec < -temporalMaxEpochDays*86400 {
+		return nil
+	}
+	sec, ok := tz.loc.previousTransition(sec)
+	if !ok {
+		return nil
+	}
+	res := new(big.Int).Mul(big.NewInt(sec), bigNsPerSecond)
+	if !isValidEpochNs(res) {
+		return nil
+	}
+	return res
+}
+
+// Formatting
+
+// padISOYear implements PadISOYear.
+func padISOYear(y int) string {
+	if y >= 0 && y <= 9999 {
+		return fmt4(y)
+	}
+	sign := "+"
+	if y < 0 {
+		sign = "-"
+		y = -y
+	}
+	s := strconv.Itoa(y)
+	return sign + strings.Repeat("0", max(6-len(s), 0)) + s
+}
+
+func fmt4(n int) string {
+	s := strconv.Itoa(n)
+	return strings.Repeat("0", max(4-len(s), 0)) + s
+}
+
+// Precision values besides the number of fractional second digits.
+const (
+	temporalPrecisionAuto   = -1
+	temporalPrecisionMinute = -2
+)
+
+// formatFractionalSeconds implements FormatFractionalSeconds.
+func formatFractionalSeconds(subSecondNs int, precision int) string {
+	if precision == temporalPrecisionAuto {
+		if subSecondNs == 0 {
+			return ""
+		}
+		return "." + strings.TrimRight(padNanos(subSecondNs), "0")
+	}
+	if precision == 0 {
+		return ""
+	}
+	return "." + padNanos(subSecondNs)[:precision]
+}
+
+func padNanos(ns int) string {
+	s := strconv.Itoa(ns)
+	return strings.Repeat("0", 9-len(s)) + s
+}
+
+// formatTimeString implements FormatTimeString.
+func formatTimeString(h, m, s, subSecondNs int, precision int) string {
+	res := twoDigits(h) + ":" + twoDigits(m)
+	if precision == temporalPrecisionMinute {
+		return res
+	}
+	return res + ":" + twoDigits(s) + formatFractionalSeconds(subSecondNs, precision)
+}
+
+func (t isoTime) subSecondNanos() int {
+	return t.millisecond*nsPerMillisecond + t.microsecond*nsPerMicrosecond + t.nanosecond
+}
+
+func (t isoTime) format(precision int) string {
+	return formatTimeString(t.hour, t.minute, t.second, t.subSecondNanos(), precision)
+}
+
+func (d isoDate) String() string {
+	return padISOYear(d.year) + "-" + twoDigits(d.month) + "-" + twoDigits(d.day)
+}
+
+func (dt isoDateTime) format(precision int) string {
+	return dt.date.String() + "T" + dt.time.format(precision)
+}
+
+// formatUTCOffsetNs implements FormatUTCOffsetNanoseconds.
+func formatUTCOffsetNs(offsetNs int64) string {
+	sign := "+"
+	if offsetNs < 0 {
+		sign = "-"
+		offsetNs = -offsetNs
+	}
+	t := isoTimeFromNanos(offsetNs)
+	if t.second == 0 && t.subSecondNanos() == 0 {
+		return sign + formatTimeString(t.hour, t.minute, 0, 0, temporalPrecisionMinute)
+	}
+	return sign + t.format(temporalPrecisionAuto)
+}
+
+// formatOffsetTimeZoneIdentifier implements FormatOffsetTimeZoneIdentifier.
+func formatOffsetTimeZoneIdentifier(offsetMinutes int64) string {
+	sign := "+"
+	if offsetMinutes < 0 {
+		sign = "-"
+		offsetMinutes = -offsetMinutes
+	}
+	return sign + twoDigits(int(offsetMinutes/60)) + ":" + twoDigits(int(offsetMinutes%60))
+}
+
+// formatDateTimeUTCOffsetRounded implements FormatDateTimeUTCOffsetRounded.
+func formatDateTimeUTCOffsetRounded(offsetNs int64) string {
+	return formatOffsetTimeZoneIdentifier(roundInt64ToIncrement(offsetNs, nsPerMinute, "halfExpand") / nsPerMinute)
+}
+
+func formatDurationField(v float64) string {
+	return bigFromFloat(math.Abs(v)).String()
+}
+
+// String implements TemporalDurationToString with the "auto" precision.
+func (d *temporalDuration) String() string {
+	return d.format(temporalPrecisionAuto)
+}
+
+// format implements TemporalDurationToString.
+func (d *temporalDuration) format(precision int) string {
+	var b strings.Builder
+	if d.sign() < 0 {
+		b.WriteByte('-')
+	}
+	b.WriteByte('P')
+	for i, designator := range "YMWD" {
+		if v := d[i]; v != 0 {
+			b.WriteString(formatDurationField(v))
+			b.WriteRune(designator)
+		}
+	}
+	var timePart strings.Builder
+	if v := d.field(temporalHour); v != 0 {
+		timePart.WriteString(formatDurationField(v))
+		timePart.WriteByte('H')
+	}
+	if v := d.field(temporalMinute); v != 0 {
+		timePart.WriteString(formatDurationField(v))
+		timePart.WriteByte('M')
+	}
+	zeroMinutesAndHigher := true
+	for u := temporalYear; u <= temporalMinute; u++ {
+		if d.field(u) != 0 {
+			zeroMinutesAndHigher = false
+			break
+		}
+	}
+	var seconds temporalDuration
+	copy(seconds[temporalSecond-temporalYear:], d[temporalSecond-temporalYear:])
+	secondsDuration := seconds.timeDuration()
+	if secondsDuration.Sign() != 0 || zeroMinutesAndHigher || precision != temporalPrecisionAuto {
+		secs, sub := new(big.Int).QuoRem(secondsDuration.Abs(secondsDuration), bigNsPerSecond, new(big.Int))
+		timePart.WriteString(secs.String())
+		timePart.WriteString(formatFractionalSeconds(int(sub.Int64()), precision))
+		timePart.WriteByte('S')
+	}
+	if timePart.Len() > 0 {
+		b.WriteByte('T')
+		b.WriteString(timePart.String())
+	}
+	return b.String()
+}
+
+// temporalLocation is an IANA time zone.
+type temporalLocation struct {
+	name string
+	loc  *time.Location
+}
+
+func (l *temporalLocation) offsetAt(sec int64) int {
+	_, offset := time.Unix(sec, 0).In(l.loc).Zone()
+	return offset
+}
+
+// primary returns the name used to compare time zones, the aliases of UTC are all considered the same.
+func (l *temporalLocation) primary() string {
+	if id, _, ok := intlTimeZone(l.name); ok && id == "UTC" {
+		return id
+	}
+	return l.name
+}
+
+// nextTransition returns the first offset transition after sec.
+func (l *temporalLocation) nextTransition(sec int64) (int64, bool) {
+	t := time.Unix(sec, 0).In(l.loc)
+	_, offset := t.Zone()
+	// the zone may change without changing the offset (e.g. only the abbreviation changes)
+	for i := 0; i < 1000; i++ {
+		_, end := t.ZoneBounds()
+		if end.IsZero() {
+			break
+		}
+		if _, o := end.Zone(); o != offset {
+			return end.Unix(), true
+		}
+		t = end
+	}
+	return 0, false
+}
+
+// previousTransition returns the last offset transition at or before sec.
+func (l *temporalLocation) previousTransition(sec int64) (int64, bool) {
+	t := time.Unix(sec, 0).In(l.loc)
+	for i := 0; i < 1000; i++ {
+		start, _ := t.ZoneBounds()
+		if start.IsZero() {
+			break
+		}
+		s := start.Unix()
+		before := time.Unix(s-1, 0).In(l.loc)
+		_, offsetBefore := before.Zone()
+		if _, offset := start.Zone(); offset != offsetBefore {
+			return s, true
+		}
+		t = before
+	}
+	return 0, false
+}
+
+var temporalLocations sync.Map
+
+// the directories where the time package looks for the zoneinfo files
+var temporalZoneinfoDirs = []string{
+	"/usr/share/zoneinfo/",
+	"/usr/share/lib/zoneinfo/",
+	"/usr/lib/locale/TZ/",
+	"/etc/zoneinfo/",
+}
+
+// loadTemporalLocation implements GetAvailableNamedTimeZoneIdentifier. The names are matched
+// case-insensitively, the result has the case used by the IANA database. Links are not resolved to
+// their primary identifiers.
+func loadTemporalLocation(name string) *temporalLocation {
+	if !isTimeZoneIANAName(name) {
+		return nil
+	}
+	key := strings.ToLower(name)
+	if l, ok := temporalLocations.Load(key); ok {
+		return l.(*temporalLocation)
+	}
+	if key == "local" || strings.HasPrefix(key, "posix/") || strings.HasPrefix(key, "right/") {
+		return nil
+	}
+	candidates := []string{name, strings.ToUpper(name)}
+	if found := findZoneinfoName(name); found != "" {
+		candidates = append(candidates, found)
+	}
+	candidates = append(candidates, titleCaseTimeZoneName(name))
+	for _, candidate := range candidates {
+		if loc, err := time.LoadLocation(candidate); err == nil {
+			l := &temporalLocation{name: candidate, loc: loc}
+			temporalLocations.Store(key, l)
+			return l
+		}
+	}
+	return nil
+}
+
+func isTimeZoneIANAName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, component := range strings.Split(name, "/") {
+		if component == "" || component == "." || component == ".." {
+			return false
+		}
+		for i := 0; i < len(component); i++ {
+			c := component[i]
+			switch {
+			case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '.' || c == '_':
+			case (c >= '0' && c <= '9' || c == '-' || c == '+') && i > 0:
+			default:
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// findZoneinfoName searches the zoneinfo directories for a file which name matches case-insensitively.
+func findZoneinfoName(name string) string {
+	dirs := temporalZoneinfoDirs
+	if zoneinfo := os.Getenv("ZONEINFO"); zoneinfo != "" {
+		dirs = append([]string{zoneinfo}, dirs...)
+	}
+	components := strings.Split(name, "/")
+	for _, dir := range dirs {
+		res := make([]string, 0, len(components))
+		path := dir
+		for _, component := range components {
+			entries, err := os.ReadDir(path)
+			if err != nil {
+				break
+			}
+			found := false
+			for _, e := range entries {
+				if strings.EqualFold(e.Name(), component) {
+					res = append(res, e.Name())
+					path = filepath.Join(path, e.Name())
+					found = true
+					break
+				}
+			}
+			if !found {
+				break
+			}
+		}
+		if len(res) == len(components) {
+			return strings.Join(res, "/")
+		}
+	}
+	return ""
+}
+
+// titleCaseTimeZoneName guesses the case of the name when the zoneinfo files are not available.
+func titleCaseTimeZoneName(name string) string {
+	b := []byte(strings.ToLower(name))
+	start := 0
+	for i := 0; i <= len(b); i++ {
+		if i < len(b) && b[i] != '/' && b[i] != '_' && b[i] != '-' {
+			continue
+		}
+		switch word := string(b[start:i]); {
+		case word == "of" || word == "au" || word == "es":
+		case len(word) <= 3 && start > 0 && b[start-1] == '/' || strings.ContainsAny(word, "0123456789"):
+			copy(b[start:i], strings.ToUpper(word))
+		case word != "" && word[0] >= 'a' && word[0] <= 'z':
+			b[start] -= 'a' - 'A'
+		}
+		start = i + 1
+	}
+	return string(b)
+}
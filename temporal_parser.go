@@ -0,0 +1,533 @@
+package sobek
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// temporalParseResult is the result of parsing an RFC 9557 date-time string.
+type temporalParseResult struct {
+	date    isoDate
+	hasDate bool
+	time    isoTime
+	hasTime bool
+
+	z bool
+	// offset is the UTC offset as written, empty if there is none
+	offset string
+	// timeZone is the time zone annotation, empty if there is none
+	timeZone string
+	// calendar is the first calendar annotation, empty if there is none
+	calendar string
+}
+
+// The kinds of strings accepted by parseTemporalString.
+const (
+	temporalDateTimeString   = iota // TemporalDateTimeString[~Zoned]
+	temporalZonedString             // TemporalDateTimeString[+Zoned]
+	temporalRelativeToString        // TemporalDateTimeString[+Zoned] or TemporalDateTimeString[~Zoned]
+	temporalInstantString
+	temporalTimeString
+	temporalTimeZoneString // any of the above, used to extract the time zone
+)
+
+type temporalParser struct {
+	s   string
+	pos int
+}
+
+func (p *temporalParser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *temporalParser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *temporalParser) skip(c byte) bool {
+	if p.peek() == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *temporalParser) skipAny(chars string) bool {
+	if !p.eof() && strings.IndexByte(chars, p.s[p.pos]) >= 0 {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func isASCIIDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// digits parses exactly n decimal digits.
+func (p *temporalParser) digits(n int) (int, bool) {
+	if p.pos+n > len(p.s) {
+		return 0, false
+	}
+	v := 0
+	for i := 0; i < n; i++ {
+		c := p.s[p.pos+i]
+		if !isASCIIDigit(c) {
+			return 0, false
+		}
+		v = v*10 + int(c-'0')
+	}
+	p.pos += n
+	return v, true
+}
+
+// twoDigitsInRange parses two digits within [lo, hi].
+func (p *temporalParser) twoDigitsInRange(lo, hi int) (int, bool) {
+	save := p.pos
+	v, ok := p.digits(2)
+	if !ok || v < lo || v > hi {
+		p.pos = save
+		return 0, false
+	}
+	return v, true
+}
+
+// fraction parses the decimal separator followed by 1 to 9 digits, the result is in nanoseconds.
+func (p *temporalParser) fraction() (int, bool) {
+	if p.peek() != '.' && p.peek() != ',' {
+		return 0, false
+	}
+	start := p.pos + 1
+	end := start
+	for end < len(p.s) && isASCIIDigit(p.s[end]) {
+		end++
+	}
+	if end == start || end-start > 9 {
+		return 0, false
+	}
+	v, _ := strconv.Atoi(p.s[start:end] + strings.Repeat("0", 9-(end-start)))
+	p.pos = end
+	return v, true
+}
+
+// date parses DateSpec.
+func (p *temporalParser) date() (isoDate, bool) {
+	var d isoDate
+	var ok bool
+	if c := p.peek(); c == '+' || c == '-' {
+		p.pos++
+		if d.year, ok = p.digits(6); !ok || c == '-' && d.year == 0 {
+			return d, false
+		}
+		if c == '-' {
+			d.year = -d.year
+		}
+	} else if d.year, ok = p.digits(4); !ok {
+		return d, false
+	}
+	extended := p.skip('-')
+	if d.month, ok = p.twoDigitsInRange(1, 12); !ok {
+		return d, false
+	}
+	if extended && !p.skip('-') {
+		return d, false
+	}
+	if d.day, ok = p.twoDigitsInRange(1, 31); !ok {
+		return d, false
+	}
+	return d, true
+}
+
+// timeSpec parses TimeSpec.
+func (p *temporalParser) timeSpec() (isoTime, bool) {
+	var t isoTime
+	var ok bool
+	if t.hour, ok = p.twoDigitsInRange(0, 23); !ok {
+		return t, false
+	}
+	extended := p.skip(':')
+	if !extended && (p.eof() || !isASCIIDigit(p.peek())) {
+		return t, true
+	}
+	if t.minute, ok = p.twoDigitsInRange(0, 59); !ok {
+		return t, false
+	}
+	if extended {
+		if !p.skip(':') {
+			return t, true
+		}
+	} else if p.eof() || !isASCIIDigit(p.peek()) {
+		return t, true
+	}
+	if t.second, ok = p.twoDigitsInRange(0, 60); !ok {
+		return t, false
+	}
+	if t.second == 60 {
+		t.second = 59
+	}
+	if frac, ok := p.fraction(); ok {
+		t.millisecond = frac / nsPerMillisecond
+		t.microsecond = frac / nsPerMicrosecond % 1000
+		t.nanosecond = frac % 1000
+	}
+	return t, true
+}
+
+// utcOffset parses UTCOffset[+SubMinutePrecision] and returns it as written.
+func (p *temporalParser) utcOffset() (string, bool) {
+	start := p.pos
+	if !p.skipAny("+-") {
+		return "", false
+	}
+	if _, ok := p.twoDigitsInRange(0, 23); !ok {
+		return "", false
+	}
+	extended := p.skip(':')
+	if !extended && (p.eof() || !isASCIIDigit(p.peek())) {
+		return p.s[start:p.pos], true
+	}
+	if _, ok := p.twoDigitsInRange(0, 59); !ok {
+		return "", false
+	}
+	if extended {
+		if !p.skip(':') {
+			return p.s[start:p.pos], true
+		}
+	} else if p.eof() || !isASCIIDigit(p.peek()) {
+		return p.s[start:p.pos], true
+	}
+	if _, ok := p.twoDigitsInRange(0, 59); !ok {
+		return "", false
+	}
+	p.fraction()
+	return p.s[start:p.pos], true
+}
+
+// dateTimeUTCOffset parses DateTimeUTCOffset[+Z].
+func (p *temporalParser) dateTimeUTCOffset(res *temporalParseResult) bool {
+	if p.skipAny("Zz") {
+		res.z = true
+		return true
+	}
+	if c := p.peek(); c == '+' || c == '-' {
+		offset, ok := p.utcOffset()
+		if !ok {
+			return false
+		}
+		res.offset = offset
+	}
+	return true
+}
+
+func isAnnotationKey(s string) bool {
+	if s == "" || !(s[0] >= 'a' && s[0] <= 'z' || s[0] == '_') {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if !(c >= 'a' && c <= 'z' || isASCIIDigit(c) || c == '_' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAnnotationValue(s string) bool {
+	for _, component := range strings.Split(s, "-") {
+		if len(component) < 1 {
+			return false
+		}
+		for i := 0; i < len(component); i++ {
+			c := component[i]
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || isASCIIDigit(c)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// annotations parses the optional TimeZoneAnnotation followed by Annotations.
+func (p *temporalParser) annotations(res *temporalParseResult) bool {
+	first := true
+	calendarCritical, calendars := false, 0
+	for p.peek() == '[' {
+		end := strings.IndexByte(p.s[p.pos:], ']')
+		if end < 0 {
+			return false
+		}
+		content := p.s[p.pos+1 : p.pos+end]
+		p.pos += end + 1
+		critical := strings.HasPrefix(content, "!")
+		if critical {
+			content = content[1:]
+		}
+		eq := strings.IndexByte(content, '=')
+		if eq < 0 {
+			if !first || !isTimeZoneIdentifier(content) {
+				return false
+			}
+			res.timeZone = content
+			first = false
+			continue
+		}
+		first = false
+		key, value := content[:eq], content[eq+1:]
+		if !isAnnotationKey(key) || !isAnnotationValue(value) {
+			return false
+		}
+		if key == "u-ca" {
+			if calendars == 0 {
+				res.calendar = value
+			}
+			calendars++
+			calendarCritical = calendarCritical || critical
+		} else if critical {
+			return false
+		}
+	}
+	return !(calendars > 1 && calendarCritical)
+}
+
+// isTimeZoneIdentifier checks the TimeZoneIdentifier syntax.
+func isTimeZoneIdentifier(s string) bool {
+	if c := s[0:min(len(s), 1)]; c == "+" || c == "-" {
+		_, ok := parseUTCOffsetMinutes(s)
+		return ok
+	}
+	return isTimeZoneIANAName(s)
+}
+
+// dateTime parses the optional time and the UTC offset that follow a date.
+func (p *temporalParser) dateTime(res *temporalParseResult) bool {
+	if !p.skipAny("Tt ") {
+		return true
+	}
+	t, ok := p.timeSpec()
+	if !ok {
+		return false
+	}
+	res.time, res.hasTime = t, true
+	return p.dateTimeUTCOffset(res)
+}
+
+// parseTemporalString implements ParseISODateTime, kind is one of the temporal*String constants.
+func parseTemporalString(s string, kind int) (*temporalParseResult, bool) {
+	res := &temporalParseResult{}
+	p := &temporalParser{s: s}
+	ok := false
+	if d, dateOk := p.date(); dateOk {
+		res.date, res.hasDate = d, true
+		ok = p.dateTime(res) && p.annotations(res) && p.eof()
+	}
+	if !ok && (kind == temporalTimeString || kind == temporalTimeZoneString) {
+		res = &temporalParseResult{}
+		ok = parseTimeOnlyString(s, res)
+	}
+	if !ok {
+		return nil, false
+	}
+	if res.hasDate && !isValidISODate(int64(res.date.year), int64(res.date.month), int64(res.date.day)) {
+		return nil, false
+	}
+	switch kind {
+	case temporalDateTimeString:
+		ok = !res.z
+	case temporalZonedString:
+		ok = res.timeZone != ""
+	case temporalRelativeToString:
+		ok = !res.z || res.timeZone != ""
+	case temporalInstantString:
+		ok = res.hasTime && (res.z || res.offset != "")
+	case temporalTimeString:
+		ok = res.hasTime && !res.z
+	}
+	if !ok {
+		return nil, false
+	}
+	return res, true
+}
+
+// parseTimeOnlyString parses the AnnotatedTime production.
+func parseTimeOnlyString(s string, res *temporalParseResult) bool {
+	p := &temporalParser{s: s}
+	designator := p.skipAny("Tt")
+	t, ok := p.timeSpec()
+	if !ok {
+		return false
+	}
+	res.time, res.hasTime = t, true
+	if c := p.peek(); c == '+' || c == '-' {
+		if res.offset, ok = p.utcOffset(); !ok {
+			return false
+		}
+	}
+	end := p.pos
+	if !p.annotations(res) || !p.eof() {
+		return false
+	}
+	return designator || !isAmbiguousTimeString(s[:end])
+}
+
+// isAmbiguousTimeString reports whether a time string without the designator is also a valid
+// DateSpecYearMonth or DateSpecMonthDay.
+func isAmbiguousTimeString(s string) bool {
+	p := &temporalParser{s: s}
+	// DateSpecYearMonth
+	if c := p.peek(); c == '+' || c == '-' {
+		p.pos++
+		if y, ok := p.digits(6); !ok || c == '-' && y == 0 {
+			p.pos = len(s) + 1
+		}
+	} else if _, ok := p.digits(4); !ok {
+		p.pos = len(s) + 1
+	}
+	if p.pos <= len(s) {
+		p.skip('-')
+		if _, ok := p.twoDigitsInRange(1, 12); ok && p.eof() {
+			return true
+		}
+	}
+	// DateSpecMonthDay
+	p = &temporalParser{s: s}
+	if strings.HasPrefix(s, "--") {
+		p.pos = 2
+	}
+	if _, ok := p.twoDigitsInRange(1, 12); !ok {
+		return false
+	}
+	p.skip('-')
+	_, ok := p.twoDigitsInRange(1, 31)
+	return ok && p.eof()
+}
+
+// parseUTCOffsetNs implements ParseDateTimeUTCOffset.
+func parseUTCOffsetNs(s string) (int64, bool) {
+	p := &temporalParser{s: s}
+	if _, ok := p.utcOffset(); !ok || !p.eof() {
+		return 0, false
+	}
+	sign := int64(1)
+	if s[0] == '-' {
+		sign = -1
+	}
+	p = &temporalParser{s: s, pos: 1}
+	h, _ := p.digits(2)
+	p.skip(':')
+	m, _ := p.digits(2)
+	p.skip(':')
+	sec, _ := p.digits(2)
+	frac, _ := p.fraction()
+	return sign * (int64(h)*nsPerHour + int64(m)*nsPerMinute + int64(sec)*nsPerSecond + int64(frac)), true
+}
+
+// parseUTCOffsetMinutes parses UTCOffset[~SubMinutePrecision].
+func parseUTCOffsetMinutes(s string) (int64, bool) {
+	ns, ok := parseUTCOffsetNs(s)
+	if !ok || ns%nsPerMinute != 0 {
+		return 0, false
+	}
+	return ns / nsPerMinute, true
+}
+
+// parseTemporalDurationString implements ParseTemporalDurationString without creating the duration,
+// the result needs to be validated.
+func parseTemporalDurationString(s string) (temporalDuration, bool) {
+	var d temporalDuration
+	p := &temporalParser{s: s}
+	sign := 1.0
+	if p.skip('-') {
+		sign = -1
+	} else {
+		p.skip('+')
+	}
+	if !p.skipAny("Pp") {
+		return d, false
+	}
+	var fracUnit temporalUnit = temporalUnitUnset
+	fracNs := 0
+	any := false
+	inTime := false
+	next := temporalYear
+	for !p.eof() {
+		if p.skipAny("Tt") {
+			if inTime {
+				return d, false
+			}
+			inTime = true
+			if p.eof() {
+				return d, false
+			}
+			next = temporalHour
+			continue
+		}
+		if fracUnit != temporalUnitUnset {
+			// the fraction must be on the last component
+			return d, false
+		}
+		start := p.pos
+		for !p.eof() && isASCIIDigit(p.peek()) {
+			p.pos++
+		}
+		if p.pos == start {
+			return d, false
+		}
+		v, _ := strconv.ParseFloat(s[start:p.pos], 64)
+		frac, hasFrac := p.fraction()
+		var unit temporalUnit
+		switch c := p.peek() | 0x20; {
+		case !inTime && c == 'y':
+			unit = temporalYear
+		case !inTime && c == 'm':
+			unit = temporalMonth
+		case !inTime && c == 'w':
+			unit = temporalWeek
+		case !inTime && c == 'd':
+			unit = temporalDay
+		case inTime && c == 'h':
+			unit = temporalHour
+		case inTime && c == 'm':
+			unit = temporalMinute
+		case inTime && c == 's':
+			unit = temporalSecond
+		default:
+			return d, false
+		}
+		p.pos++
+		if unit < next || hasFrac && !inTime {
+			return d, false
+		}
+		next = unit + 1
+		d[unit-temporalYear] = v
+		if hasFrac {
+			fracUnit, fracNs = unit, frac
+		}
+		any = true
+	}
+	if !any || inTime && next == temporalHour {
+		return d, false
+	}
+	if fracUnit != temporalUnitUnset {
+		// the fraction of the unit in nanoseconds is spread over the smaller units
+		rest := int64(fracNs) * (temporalUnitLengths[fracUnit] / nsPerSecond)
+		for u := fracUnit + 1; u <= temporalNanosecond; u++ {
+			length := temporalUnitLengths[u]
+			d[u-temporalYear] = float64(rest / length)
+			rest %= length
+		}
+	}
+	for i := range d {
+		d[i] *= sign
+		if d[i] == 0 {
+			d[i] = 0
+		}
+		if math.IsInf(d[i], 0) {
+			return d, false
+		}
+	}
+	return d, true
+}
@@ -138,6 +138,8 @@ const (
 	ASYNC
 	AWAIT
 	YIELD
+
+	USING // not produced by the lexer, used in ast.LexicalDeclaration
 )
 
 var token2string = [...]string{
@@ -226,6 +228,7 @@ var token2string = [...]string{
 	ASYNC:                       "async",
 	AWAIT:                       "await",
 	YIELD:                       "yield",
+	USING:                       "using",
 	CONST:                       "const",
 	WHILE:                       "while",
 	BREAK:                       "break",
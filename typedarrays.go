@@ -1,6 +1,8 @@
 package sobek
 
 import (
+	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"reflect"
@@ -20,7 +22,8 @@ const (
 var (
 	nativeEndian byteOrder
 
-	arrayBufferType = reflect.TypeOf(ArrayBuffer{})
+	arrayBufferType       = reflect.TypeOf(ArrayBuffer{})
+	sharedArrayBufferType = reflect.TypeOf(SharedArrayBuffer{})
 )
 
 type typedArrayObjectCtor func(buf *arrayBufferObject, offset, length int, proto *Object) *typedArrayObject
@@ -28,7 +31,13 @@ type typedArrayObjectCtor func(buf *arrayBufferObject, offset, length int, proto
 type arrayBufferObject struct {
 	baseObject
 	detached bool
-	data     []byte
+	// resizable is set for resizable ArrayBuffers and growable SharedArrayBuffers.
+	resizable bool
+	// maxByteLen is the maximum byte length of a resizable buffer.
+	maxByteLen int
+	data       []byte
+	// shared is set for SharedArrayBuffers, data is a sub-slice of shared.data.
+	shared *sharedDataBlock
 }
 
 // ArrayBuffer is a Go wrapper around ECMAScript ArrayBuffer. Calling Runtime.ToValue() on it
@@ -42,6 +51,9 @@ type dataViewObject struct {
 	baseObject
 	viewedArrayBuf      *arrayBufferObject
 	byteLen, byteOffset int
+	// lengthTracking is set when the view was created over a resizable buffer without an explicit length.
+	// In this case byteLen is not used and the length follows the length of the buffer.
+	lengthTracking bool
 }
 
 type typedArray interface {
@@ -64,6 +76,7 @@ type uint16Array []byte
 type int16Array []byte
 type uint32Array []byte
 type int32Array []byte
+type float16Array []byte
 type float32Array []byte
 type float64Array []byte
 type bigInt64Array []byte
@@ -76,6 +89,9 @@ type typedArrayObject struct {
 	length, offset int
 	elemSize       int
 	typedArray     typedArray
+	// lengthTracking is set when the array was created over a resizable buffer without an explicit length.
+	// In this case length is not used, use getLength() instead.
+	lengthTracking bool
 }
 
 func (a ArrayBuffer) toValue(r *Runtime) Value {
@@ -113,6 +129,41 @@ func (a ArrayBuffer) Detached() bool {
 	return a.buf.detached
 }
 
+// Resizable returns true if the ArrayBuffer was created as resizable, either from JavaScript using the
+// maxByteLength option, or with Runtime.NewResizableArrayBuffer().
+func (a ArrayBuffer) Resizable() bool {
+	return a.buf.resizable
+}
+
+// MaxByteLength returns the maximum length the ArrayBuffer can be resized to. For fixed-length buffers
+// it's the same as len(Bytes()).
+func (a ArrayBuffer) MaxByteLength() int {
+	if a.buf.resizable {
+		return a.buf.maxByteLen
+	}
+	return len(a.buf.data)
+}
+
+// Resize changes the length of a resizable ArrayBuffer. If the new length does not exceed the capacity of the
+// underlying []byte, it is re-sliced in place, otherwise a new slice is allocated and the data is copied.
+// Any newly exposed bytes are zeroed. Typed arrays and DataViews that track the length of the buffer will
+// reflect the change.
+// Note, this method may only be called from the goroutine that 'owns' the Runtime, it may not
+// be called concurrently.
+func (a ArrayBuffer) Resize(newByteLength int) error {
+	if a.buf.detached {
+		return errors.New("ArrayBuffer is detached")
+	}
+	if !a.buf.resizable {
+		return errors.New("ArrayBuffer is not resizable")
+	}
+	if newByteLength < 0 || newByteLength > a.buf.maxByteLen {
+		return fmt.Errorf("invalid length %d, must be between 0 and %d", newByteLength, a.buf.maxByteLen)
+	}
+	a.buf.resize(newByteLength)
+	return nil
+}
+
 // NewArrayBuffer creates a new instance of ArrayBuffer backed by the provided byte slice.
 //
 // Warning: be careful when using unaligned slices (sub-slices that do not start at word boundaries). If later a
@@ -127,6 +178,25 @@ func (r *Runtime) NewArrayBuffer(data []byte) ArrayBuffer {
 	}
 }
 
+// NewResizableArrayBuffer creates a new instance of a resizable ArrayBuffer backed by the provided byte slice.
+// The buffer can be resized up to maxByteLength bytes, either from JavaScript using resize(), or by calling
+// ArrayBuffer.Resize(). If the capacity of the slice is sufficient, growing does not require copying.
+// Panics if len(data) exceeds maxByteLength.
+//
+// The same alignment considerations as for NewArrayBuffer apply.
+func (r *Runtime) NewResizableArrayBuffer(data []byte, maxByteLength int) ArrayBuffer {
+	if len(data) > maxByteLength {
+		panic(fmt.Errorf("length of the data (%d) exceeds maxByteLength (%d)", len(data), maxByteLength))
+	}
+	buf := r._newArrayBuffer(r.getArrayBufferPrototype(), nil)
+	buf.data = data
+	buf.resizable = true
+	buf.maxByteLen = maxByteLength
+	return ArrayBuffer{
+		buf: buf,
+	}
+}
+
 func (a *uint8Array) toRaw(v Value) uint64 {
 	return uint64(toUint8(v))
 }
@@ -480,6 +550,61 @@ func (a *int32Array) exportType() reflect.Type {
 	return typeInt32Array
 }
 
+func (a *float16Array) ptr(idx int) *uint16 {
+	p := unsafe.SliceData(*a)
+	return (*uint16)(unsafe.Add(unsafe.Pointer(p), idx*2))
+}
+
+func (a *float16Array) get(idx int) Value {
+	return floatToValue(float16BitsToFloat64(*(a.ptr(idx))))
+}
+
+func (a *float16Array) getRaw(idx int) uint64 {
+	return uint64(*(a.ptr(idx)))
+}
+
+func (a *float16Array) set(idx int, value Value) {
+	*(a.ptr(idx)) = toFloat16(value)
+}
+
+func (a *float16Array) toRaw(v Value) uint64 {
+	return uint64(toFloat16(v))
+}
+
+func (a *float16Array) setRaw(idx int, v uint64) {
+	*(a.ptr(idx)) = uint16(v)
+}
+
+func (a *float16Array) less(i, j int) bool {
+	return typedFloatLess(float16BitsToFloat64(*(a.ptr(i))), float16BitsToFloat64(*(a.ptr(j))))
+}
+
+func (a *float16Array) swap(i, j int) {
+	pi, pj := a.ptr(i), a.ptr(j)
+	*pi, *pj = *pj, *pi
+}
+
+func (a *float16Array) typeMatch(v Value) bool {
+	switch v := v.(type) {
+	case valueInt:
+		return float16BitsToFloat64(float64ToFloat16Bits(float64(v))) == float64(v)
+	case valueFloat:
+		f := float64(v)
+		return math.IsNaN(f) || float16BitsToFloat64(float64ToFloat16Bits(f)) == f
+	}
+	return false
+}
+
+func (a *float16Array) export(offset int, length int) interface{} {
+	return unsafe.Slice(a.ptr(offset), length)
+}
+
+var typeFloat16Array = reflect.TypeOf(([]uint16)(nil))
+
+func (a *float16Array) exportType() reflect.Type {
+	return typeFloat16Array
+}
+
 func (a *float32Array) ptr(idx int) *float32 {
 	p := unsafe.SliceData(*a)
 	return (*float32)(unsafe.Add(unsafe.Pointer(p), idx*4))
@@ -529,9 +654,12 @@ func (a *float32Array) swap(i, j int) {
 }
 
 func (a *float32Array) typeMatch(v Value) bool {
-	switch v.(type) {
-	case valueInt, valueFloat:
-		return true
+	switch v := v.(type) {
+	case valueInt:
+		return float64(float32(v)) == float64(v)
+	case valueFloat:
+		f := float64(v)
+		return math.IsNaN(f) || float64(float32(f)) == f
 	}
 	return false
 }
@@ -599,7 +727,8 @@ func (a *float64Array) exportType() reflect.Type {
 }
 
 func (a *bigInt64Array) toRaw(value Value) uint64 {
-	return toBigInt64(value).Uint64()
+	// big.Int.Uint64() is not defined for negative values, converting through int64 keeps the two's complement bits
+	return uint64(toBigInt64(value).Int64())
 }
 
 func (a *bigInt64Array) ptr(idx int) *int64 {
@@ -719,10 +848,7 @@ func (a *bigUint64Array) exportType() reflect.Type {
 }
 
 func (a *typedArrayObject) _getIdx(idx int) Value {
-	if 0 <= idx && idx < a.length {
-		if !a.viewedArrayBuf.ensureNotDetached(false) {
-			return nil
-		}
+	if a.isValidIntegerIndex(idx) {
 		return a.typedArray.get(idx + a.offset)
 	}
 	return nil
@@ -777,12 +903,42 @@ func (a *typedArrayObject) getIdx(idx valueInt, receiver Value) Value {
 }
 
 func (a *typedArrayObject) isValidIntegerIndex(idx int) bool {
-	if a.viewedArrayBuf.ensureNotDetached(false) {
-		if idx >= 0 && idx < a.length {
-			return true
-		}
+	return idx >= 0 && idx < a.getLength()
+}
+
+// isOutOfBounds implements IsTypedArrayOutOfBounds. Arrays backed by a detached buffer are out of bounds.
+func (a *typedArrayObject) isOutOfBounds() bool {
+	if a.viewedArrayBuf.detached {
+		return true
 	}
-	return false
+	a.viewedArrayBuf.syncLength()
+	bufLen := len(a.viewedArrayBuf.data)
+	start := a.offset * a.elemSize
+	if start > bufLen {
+		return true
+	}
+	return !a.lengthTracking && start+a.length*a.elemSize > bufLen
+}
+
+// getLength implements TypedArrayLength. Unlike the spec it returns 0 for out of bounds arrays.
+func (a *typedArrayObject) getLength() int {
+	if a.isOutOfBounds() {
+		return 0
+	}
+	if a.lengthTracking {
+		return len(a.viewedArrayBuf.data)/a.elemSize - a.offset
+	}
+	return a.length
+}
+
+// validate implements ValidateTypedArray. It throws a TypeError if the array is detached or out of bounds,
+// otherwise returns its current length.
+func (a *typedArrayObject) validate() int {
+	if a.isOutOfBounds() {
+		a.viewedArrayBuf.ensureNotDetached(true)
+		panic(a.val.runtime.NewTypeError("TypedArray is out of bounds"))
+	}
+	return a.getLength()
 }
 
 func (a *typedArrayObject) _putIdx(idx int, v Value) {
@@ -907,7 +1063,7 @@ func (a *typedArrayObject) deleteStr(name unistring.String, throw bool) bool {
 }
 
 func (a *typedArrayObject) deleteIdx(idx valueInt, throw bool) bool {
-	if a.viewedArrayBuf.ensureNotDetached(false) && idx >= 0 && int64(idx) < int64(a.length) {
+	if idx >= 0 && int64(idx) < int64(a.getLength()) {
 		a.val.runtime.typeErrorResult(throw, "Cannot delete property '%d' of %s", idx, a.val.String())
 		return false
 	}
@@ -916,10 +1072,11 @@ func (a *typedArrayObject) deleteIdx(idx valueInt, throw bool) bool {
 }
 
 func (a *typedArrayObject) stringKeys(all bool, accum []Value) []Value {
+	l := a.getLength()
 	if accum == nil {
-		accum = make([]Value, 0, a.length)
+		accum = make([]Value, 0, l)
 	}
-	for i := 0; i < a.length; i++ {
+	for i := 0; i < l; i++ {
 		accum = append(accum, asciiString(strconv.Itoa(i)))
 	}
 	return a.baseObject.stringKeys(all, accum)
@@ -931,7 +1088,7 @@ type typedArrayPropIter struct {
 }
 
 func (i *typedArrayPropIter) next() (propIterItem, iterNextFunc) {
-	if i.idx < i.a.length {
+	if i.idx < i.a.getLength() {
 		name := strconv.Itoa(i.idx)
 		prop := i.a._getIdx(i.idx)
 		i.idx++
@@ -949,14 +1106,31 @@ func (a *typedArrayObject) iterateStringKeys() iterNextFunc {
 
 func (a *typedArrayObject) exportToArrayOrSlice(dst reflect.Value, typ reflect.Type, ctx *objectExportCtx) error {
 	if typ == typeBytes {
-		dst.Set(reflect.ValueOf(a.viewedArrayBuf.data[a.offset*a.elemSize : (a.offset+a.length)*a.elemSize]))
+		if a.isOutOfBounds() {
+			dst.Set(reflect.ValueOf([]byte{}))
+		} else {
+			dst.Set(reflect.ValueOf(a.viewedArrayBuf.data[a.offset*a.elemSize : (a.offset+a.getLength())*a.elemSize]))
+		}
 		return nil
 	}
+	if typ == typeFloat16Slice {
+		if arr, ok := a.typedArray.(*float16Array); ok {
+			if a.isOutOfBounds() {
+				dst.Set(reflect.ValueOf([]Float16{}))
+			} else {
+				dst.Set(reflect.ValueOf(unsafe.Slice((*Float16)(arr.ptr(a.offset)), a.getLength())))
+			}
+			return nil
+		}
+	}
 	return a.baseObject.exportToArrayOrSlice(dst, typ, ctx)
 }
 
 func (a *typedArrayObject) export(_ *objectExportCtx) interface{} {
-	return a.typedArray.export(a.offset, a.length)
+	if a.isOutOfBounds() {
+		return a.typedArray.export(0, 0)
+	}
+	return a.typedArray.export(a.offset, a.getLength())
 }
 
 func (a *typedArrayObject) exportType() reflect.Type {
@@ -965,7 +1139,11 @@ func (a *typedArrayObject) exportType() reflect.Type {
 
 func (o *dataViewObject) exportToArrayOrSlice(dst reflect.Value, typ reflect.Type, ctx *objectExportCtx) error {
 	if typ == typeBytes {
-		dst.Set(reflect.ValueOf(o.viewedArrayBuf.data[o.byteOffset : o.byteOffset+o.byteLen]))
+		if o.isOutOfBounds() {
+			dst.Set(reflect.ValueOf([]byte{}))
+		} else {
+			dst.Set(reflect.ValueOf(o.viewedArrayBuf.data[o.byteOffset : o.byteOffset+o.validate()]))
+		}
 		return nil
 	}
 	return o.baseObject.exportToArrayOrSlice(dst, typ, ctx)
@@ -1023,6 +1201,10 @@ func (r *Runtime) newInt32ArrayObject(buf *arrayBufferObject, offset, length int
 	return r._newTypedArrayObject(buf, offset, length, 4, r.global.Int32Array, (*int32Array)(&buf.data), proto)
 }
 
+func (r *Runtime) newFloat16ArrayObject(buf *arrayBufferObject, offset, length int, proto *Object) *typedArrayObject {
+	return r._newTypedArrayObject(buf, offset, length, 2, r.global.Float16Array, (*float16Array)(&buf.data), proto)
+}
+
 func (r *Runtime) newFloat32ArrayObject(buf *arrayBufferObject, offset, length int, proto *Object) *typedArrayObject {
 	return r._newTypedArrayObject(buf, offset, length, 4, r.global.Float32Array, (*float32Array)(&buf.data), proto)
 }
@@ -1039,9 +1221,34 @@ func (r *Runtime) newBigUint64ArrayObject(buf *arrayBufferObject, offset, length
 	return r._newTypedArrayObject(buf, offset, length, 8, r.global.BigUint64Array, (*bigUint64Array)(&buf.data), proto)
 }
 
+// isOutOfBounds implements IsViewOutOfBounds. Views of a detached buffer are out of bounds.
+func (o *dataViewObject) isOutOfBounds() bool {
+	if o.viewedArrayBuf.detached {
+		return true
+	}
+	o.viewedArrayBuf.syncLength()
+	bufLen := len(o.viewedArrayBuf.data)
+	if o.byteOffset > bufLen {
+		return true
+	}
+	return !o.lengthTracking && o.byteOffset+o.byteLen > bufLen
+}
+
+// validate throws a TypeError if the view is out of bounds, otherwise returns its current byte length
+// (i.e. GetViewByteLength).
+func (o *dataViewObject) validate() int {
+	if o.isOutOfBounds() {
+		o.viewedArrayBuf.ensureNotDetached(true)
+		panic(o.val.runtime.NewTypeError("DataView is out of bounds"))
+	}
+	if o.lengthTracking {
+		return len(o.viewedArrayBuf.data) - o.byteOffset
+	}
+	return o.byteLen
+}
+
 func (o *dataViewObject) getIdxAndByteOrder(getIdx int, littleEndianVal Value, size int) (int, byteOrder) {
-	o.viewedArrayBuf.ensureNotDetached(true)
-	if getIdx+size > o.byteLen {
+	if getIdx+size > o.validate() {
 		panic(o.val.runtime.newErrorf(o.val.runtime.getRangeError(), "Index %d is out of bounds", getIdx))
 	}
 	getIdx += o.byteOffset
@@ -1066,6 +1273,14 @@ func (o *arrayBufferObject) ensureNotDetached(throw bool) bool {
 	return true
 }
 
+func (o *arrayBufferObject) getFloat16(idx int, byteOrder byteOrder) float64 {
+	return float16BitsToFloat64(o.getUint16(idx, byteOrder))
+}
+
+func (o *arrayBufferObject) setFloat16(idx int, val uint16, byteOrder byteOrder) {
+	o.setUint16(idx, val, byteOrder)
+}
+
 func (o *arrayBufferObject) getFloat32(idx int, byteOrder byteOrder) float32 {
 	return math.Float32frombits(o.getUint32(idx, byteOrder))
 }
@@ -1232,11 +1447,35 @@ func (o *arrayBufferObject) detach() {
 	o.detached = true
 }
 
+// resize changes the length of the buffer. The caller must check that the buffer is resizable and not
+// detached, and that newLen does not exceed maxByteLen.
+func (o *arrayBufferObject) resize(newLen int) {
+	if newLen <= cap(o.data) {
+		oldLen := len(o.data)
+		o.data = o.data[:newLen]
+		if newLen > oldLen {
+			clear(o.data[oldLen:])
+		}
+	} else {
+		data := allocByteSlice(newLen)
+		copy(data, o.data)
+		o.data = data
+	}
+}
+
 func (o *arrayBufferObject) exportType() reflect.Type {
+	if o.shared != nil {
+		return sharedArrayBufferType
+	}
 	return arrayBufferType
 }
 
 func (o *arrayBufferObject) export(*objectExportCtx) interface{} {
+	if o.shared != nil {
+		return SharedArrayBuffer{
+			block: o.shared,
+		}
+	}
 	return ArrayBuffer{
 		buf: o,
 	}
@@ -1244,6 +1483,7 @@ func (o *arrayBufferObject) export(*objectExportCtx) interface{} {
 
 func (o *arrayBufferObject) exportToArrayOrSlice(dst reflect.Value, typ reflect.Type, ctx *objectExportCtx) error {
 	if typ == typeBytes {
+		o.syncLength()
 		dst.Set(reflect.ValueOf(o.data))
 		return nil
 	}
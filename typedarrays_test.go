@@ -2,6 +2,7 @@ package sobek
 
 import (
 	"bytes"
+	"math"
 	"testing"
 )
 
@@ -485,6 +486,28 @@ func TestTypedArrayExport(t *testing.T) {
 		}
 	})
 
+	t.Run("float16", func(t *testing.T) {
+		v, err := vm.RunString("new Float16Array([1, -1.5])")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a, ok := v.Export().([]uint16); ok {
+			if len(a) != 2 || a[0] != 0x3c00 || a[1] != 0xbe00 {
+				t.Fatal(a)
+			}
+		} else {
+			t.Fatal("Wrong export type")
+		}
+		var f []Float16
+		err = vm.ExportTo(v, &f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(f) != 2 || f[0].Float64() != 1 || f[1].Float64() != -1.5 {
+			t.Fatal(f)
+		}
+	})
+
 	t.Run("float32", func(t *testing.T) {
 		v, err := vm.RunString("new Float32Array([1, -1.23456])")
 		if err != nil {
@@ -542,3 +565,113 @@ func TestTypedArrayExport(t *testing.T) {
 	})
 
 }
+
+func TestFloat16Conversion(t *testing.T) {
+	tests := []struct {
+		f    float64
+		bits uint16
+	}{
+		{0, 0},
+		{math.Copysign(0, -1), 0x8000},
+		{1, 0x3c00},
+		{-2, 0xc000},
+		{65504, 0x7bff},
+		{65519.99, 0x7bff},
+		{65520, 0x7c00},
+		{math.Inf(-1), 0xfc00},
+		{0x1p-24, 0x0001},
+		{0x1p-25, 0},      // tie, rounds to even
+		{0x1.8p-24, 0x02}, // tie, rounds to even
+		{0x1p-14, 0x0400},
+		{1 + 0x1p-11, 0x3c00},           // tie, rounds to even
+		{1 + 0x1p-11 + 0x1p-40, 0x3c01}, // above the tie, must not be double-rounded
+		{1 + 3*0x1p-11, 0x3c02},         // tie, rounds to even
+	}
+	for _, tc := range tests {
+		if b := float64ToFloat16Bits(tc.f); b != tc.bits {
+			t.Errorf("%v: expected %#04x, got %#04x", tc.f, tc.bits, b)
+		}
+	}
+	if b := float64ToFloat16Bits(math.NaN()); float16BitsToFloat64(b) == float16BitsToFloat64(b) {
+		t.Errorf("NaN: got %#04x", b)
+	}
+	for b := 0; b < 0x10000; b++ {
+		f := float16BitsToFloat64(uint16(b))
+		if math.IsNaN(f) {
+			continue
+		}
+		if b1 := float64ToFloat16Bits(f); b1 != uint16(b) {
+			t.Fatalf("%#04x: round trip failed: %v, %#04x", b, f, b1)
+		}
+	}
+}
+
+func TestFloat16Array(t *testing.T) {
+	const SCRIPT = `
+	const a = new Float16Array([1.337, 65520, -0, 1e-8]);
+	assert.sameValue(Float16Array.BYTES_PER_ELEMENT, 2);
+	assert.sameValue(a[0], 1.3369140625);
+	assert.sameValue(a[1], Infinity);
+	assert.sameValue(1 / a[2], -Infinity);
+	assert.sameValue(a[3], 0);
+	assert.sameValue(Math.f16round(1.337), 1.3369140625);
+	assert.sameValue(Math.f16round(5.960464477539063e-8), 5.960464477539063e-8);
+	assert.sameValue(Math.f16round(2.980232238769531e-8), 0);
+
+	const dv = new DataView(new ArrayBuffer(4));
+	dv.setFloat16(0, 1.5);
+	assert.sameValue(dv.getUint16(0), 0x3e00);
+	dv.setFloat16(2, -2, true);
+	assert.sameValue(dv.getUint16(2, true), 0xc000);
+	assert.sameValue(dv.getFloat16(2, true), -2);
+	assert.throws(RangeError, () => dv.getFloat16(3));
+	const sorted = new Float16Array([3, NaN, 1, 0, -0]).sort();
+	assert.sameValue(sorted[0], -0);
+	assert.sameValue(sorted[3], 3);
+	assert.sameValue(sorted[4], NaN);
+
+	const b = new Float16Array([1, 1.3369140625, 65504, NaN, Infinity]);
+	assert.sameValue(b.includes(1.0001), false);
+	assert.sameValue(b.indexOf(1.0001), -1);
+	assert.sameValue(b.lastIndexOf(1.0001), -1);
+	assert.sameValue(b.includes(1.337), false);
+	assert.sameValue(b.indexOf(1.3369140625), 1);
+	assert.sameValue(b.indexOf(65504), 2);
+	assert.sameValue(b.includes(65505), false);
+	assert.sameValue(b.includes(NaN), true);
+	assert.sameValue(b.indexOf(NaN), -1);
+	assert.sameValue(b.lastIndexOf(Infinity), 4);
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestFloat32ArraySearch(t *testing.T) {
+	// the search value must not be rounded to float32 before it's compared with the elements
+	const SCRIPT = `
+	const a = new Float32Array([1, 1.100000023841858, NaN]);
+	assert.sameValue(a.includes(1.00000001), false);
+	assert.sameValue(a.indexOf(1.00000001), -1);
+	assert.sameValue(a.lastIndexOf(1.00000001), -1);
+	assert.sameValue(a.includes(1.1), false);
+	assert.sameValue(a.indexOf(1.100000023841858), 1);
+	assert.sameValue(a.indexOf(1), 0);
+	assert.sameValue(a.includes(NaN), true);
+	assert.sameValue(a.indexOf(NaN), -1);
+	`
+	testScriptWithTestLib(SCRIPT, _undefined, t)
+}
+
+func TestFloat16ToValueExportTo(t *testing.T) {
+	vm := New()
+	if v := vm.ToValue(NewFloat16(0.1)); v.ToFloat() != 0.0999755859375 {
+		t.Fatal(v)
+	}
+	var f Float16
+	err := vm.ExportTo(vm.ToValue(0.1), &f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f != 0x2e66 {
+		t.Fatalf("%#04x", uint16(f))
+	}
+}
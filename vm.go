@@ -57,6 +57,9 @@ type tryFrame struct {
 	privEnv *privateEnv
 
 	catchPos, finallyPos, finallyRet int32
+
+	// resources added by 'using' declarations in the scope guarded by this frame
+	disposables *disposeCapability
 }
 
 type execCtx struct {
@@ -114,6 +117,9 @@ type iterStackItem struct {
 	val  Value
 	f    iterNextFunc
 	iter *iteratorRecord
+	// set while the result of next() of an async iterator is awaited, the iterator must not be closed
+	// if the result is rejected
+	nextPending bool
 }
 
 type ref interface {
@@ -381,6 +387,8 @@ type vm struct {
 	interrupted   uint32
 	interruptVal  interface{}
 	interruptLock sync.Mutex
+	// interruptWake is signalled by Interrupt() while the vm is blocked in Atomics.wait().
+	interruptWake chan struct{}
 
 	curAsyncRunner *asyncRunner
 
@@ -643,16 +651,20 @@ func (vm *vm) run() {
 	}
 
 	if interrupted {
-		vm.interruptLock.Lock()
-		v := &InterruptedError{
-			iface: vm.interruptVal,
-		}
-		v.stack = vm.captureStack(nil, 0)
-		vm.interruptLock.Unlock()
-		panic(v)
+		panic(vm.newInterruptedError())
 	}
 }
 
+func (vm *vm) newInterruptedError() *InterruptedError {
+	vm.interruptLock.Lock()
+	v := &InterruptedError{
+		iface: vm.interruptVal,
+	}
+	v.stack = vm.captureStack(nil, 0)
+	vm.interruptLock.Unlock()
+	return v
+}
+
 func (vm *vm) runWithProfiler() bool {
 	pt := vm.profTracker
 	if pt == nil {
@@ -693,7 +705,23 @@ func (vm *vm) Interrupt(v interface{}) {
 	vm.interruptLock.Lock()
 	vm.interruptVal = v
 	atomic.StoreUint32(&vm.interrupted, 1)
+	if vm.interruptWake != nil {
+		select {
+		case vm.interruptWake <- struct{}{}:
+		default:
+		}
+	}
+	vm.interruptLock.Unlock()
+}
+
+// setInterruptWake sets the channel to be signalled by Interrupt() while the vm is blocked outside of
+// JavaScript code. Returns true if the vm has already been interrupted.
+func (vm *vm) setInterruptWake(ch chan struct{}) bool {
+	vm.interruptLock.Lock()
+	vm.interruptWake = ch
+	interrupted := atomic.LoadUint32(&vm.interrupted) != 0
 	vm.interruptLock.Unlock()
+	return interrupted
 }
 
 func (vm *vm) ClearInterrupt() {
@@ -785,7 +813,7 @@ func (vm *vm) restoreStacks(iterLen, refLen uint32) (ex *Exception) {
 	// Restore other stacks
 	iterTail := vm.iterStack[iterLen:]
 	for i := len(iterTail) - 1; i >= 0; i-- {
-		if iter := iterTail[i].iter; iter != nil {
+		if iter := iterTail[i].iter; iter != nil && !iterTail[i].nextPending {
 			ex1 := vm.try(func() {
 				iter.returnIter()
 			})
@@ -1227,6 +1255,25 @@ func (i importNamespace) exec(vm *vm) {
 	vm.pc++
 }
 
+type importDeferredNamespace struct {
+	module  ModuleRecord
+	resolve HostResolveImportedModuleFunc
+}
+
+func (i importDeferredNamespace) exec(vm *vm) {
+	vm.push(vm.r.deferredNamespaceObjectFor(i.module, i.resolve))
+	vm.pc++
+}
+
+type importSource struct {
+	module ModuleRecord
+}
+
+func (i importSource) exec(vm *vm) {
+	vm.push(i.module.(ModuleSourceRecord).GetModuleSource(vm.r))
+	vm.pc++
+}
+
 type export struct {
 	idx      uint32
 	callback func(*vm, func() Value)
@@ -4130,6 +4177,20 @@ func (n *newGeneratorFunc) exec(vm *vm) {
 	vm.pc++
 }
 
+type newAsyncGeneratorFunc struct {
+	newFunc
+}
+
+func (n *newAsyncGeneratorFunc) exec(vm *vm) {
+	obj := vm.r.newAsyncGeneratorFunc(n.name, n.length, n.strict)
+	obj.prg = n.prg
+	obj.stash = vm.stash
+	obj.privEnv = vm.privEnv
+	obj.src = n.source
+	vm.push(obj.val)
+	vm.pc++
+}
+
 type newMethod struct {
 	newFunc
 	homeObjOffset uint32
@@ -4169,6 +4230,15 @@ func (n *newGeneratorMethod) exec(vm *vm) {
 	n._exec(vm, &obj.methodFuncObject)
 }
 
+type newAsyncGeneratorMethod struct {
+	newMethod
+}
+
+func (n *newAsyncGeneratorMethod) exec(vm *vm) {
+	obj := vm.r.newAsyncGeneratorMethod(n.name, n.length, n.strict)
+	n._exec(vm, &obj.methodFuncObject)
+}
+
 type newArrowFunc struct {
 	newFunc
 }
@@ -4199,6 +4269,8 @@ func getHomeObject(v Value) *Object {
 			return fn.homeObject
 		case *asyncMethodFuncObject:
 			return fn.homeObject
+		case *asyncGeneratorMethodFuncObject:
+			return fn.homeObject
 		case *classFuncObject:
 			return o.runtime.toObject(fn.getStr("prototype", nil))
 		case *arrowFuncObject:
@@ -4949,6 +5021,57 @@ func (leaveFinally) exec(vm *vm) {
 	}
 }
 
+// addDisposableResource adds the value on top of the stack to the resources of the innermost try frame
+// (which is created by the compiler for scopes containing 'using' declarations). The value is left on the
+// stack. It is set to true for 'await using'.
+type addDisposableResource bool
+
+func (a addDisposableResource) exec(vm *vm) {
+	tf := &vm.tryStack[len(vm.tryStack)-1]
+	if tf.disposables == nil {
+		tf.disposables = &disposeCapability{}
+	}
+	vm.r.addDisposableResource(tf.disposables, vm.stack[vm.sp-1], bool(a), nil)
+	vm.pc++
+}
+
+type _disposeResources struct{}
+
+var disposeResources _disposeResources
+
+func (_disposeResources) exec(vm *vm) {
+	tf := &vm.tryStack[len(vm.tryStack)-1]
+	if dc := tf.disposables; dc != nil {
+		if ex := vm.r.disposeResources(dc, tf.exception); ex != tf.exception {
+			vm.throw(ex)
+			return
+		}
+	}
+	vm.pc++
+}
+
+// disposeResourcesAsync pushes a promise for the disposal of the innermost try frame's resources, to be
+// awaited by the following instructions. If there are no async-dispose resources, the disposal is performed
+// synchronously and the specified number of instructions is skipped.
+type disposeResourcesAsync int32
+
+func (d disposeResourcesAsync) exec(vm *vm) {
+	tf := &vm.tryStack[len(vm.tryStack)-1]
+	dc := tf.disposables
+	if dc == nil || !dc.needsAwait() {
+		if dc != nil {
+			if ex := vm.r.disposeResources(dc, tf.exception); ex != tf.exception {
+				vm.throw(ex)
+				return
+			}
+		}
+		vm.pc += int(d)
+		return
+	}
+	vm.push(vm.r.disposeResourcesAsync(dc, tf.exception))
+	vm.pc++
+}
+
 type _throw struct{}
 
 var throw _throw
@@ -5058,20 +5181,20 @@ func (_loadImportMeta) exec(vm *vm) {
 	vm.pc++
 }
 
-type _loadDynamicImport struct{}
+type loadDynamicImport ImportPhase
 
-var dynamicImport _loadDynamicImport
-
-func (_loadDynamicImport) exec(vm *vm) {
+func (l loadDynamicImport) exec(vm *vm) {
 	// https://262.ecma-international.org/13.0/#sec-import-call-runtime-semantics-evaluation
 	// NOTE(@mstoykov): it will be nice if we do not use vm.r.ToValue but write it directly
-	vm.push(vm.r.ToValue(func(specifier Value) Value {
+	vm.push(vm.r.ToValue(func(call FunctionCall) Value {
 		t := vm.r.GetActiveScriptOrModule()
 
 		pcap := vm.r.newPromiseCapability(vm.r.getPromise())
 		var specifierStr String
+		var attributes []ImportAttribute
 		err := vm.r.try(func() {
-			specifierStr = specifier.toString()
+			specifierStr = call.Argument(0).toString()
+			attributes = vm.r.importAttributesFromOptions(call.Argument(1))
 		})
 		if err != nil {
 			if ex, ok := err.(*Exception); ok {
@@ -5099,7 +5222,11 @@ func (_loadDynamicImport) exec(vm *vm) {
 				return nil
 			})
 			vm.r.performPromiseThen(pcapInput.promise.Export().(*Promise), onFullfill, rejectionClosure, nil)
-			vm.r.importModuleDynamically(t, specifierStr, pcapInput)
+			payload := &dynamicImportPayload{
+				capability: pcapInput,
+				phase:      ImportPhase(l),
+			}
+			vm.r.importModuleDynamically(t, specifierStr, attributes, ImportPhase(l), payload)
 		}
 		return pcap.promise
 	}))
@@ -5333,6 +5460,115 @@ func (jmp iterNext) exec(vm *vm) {
 	}
 }
 
+type _iterateAsyncP struct{}
+
+var iterateAsyncP _iterateAsyncP
+
+func (_iterateAsyncP) exec(vm *vm) {
+	iter := vm.r.getAsyncIterator(vm.stack[vm.sp-1])
+	vm.iterStack = append(vm.iterStack, iterStackItem{iter: iter})
+	vm.sp--
+	vm.pc++
+}
+
+type _iterNextAsync struct{}
+
+// iterNextAsync calls next() on the async iterator and pushes the result so that it can be awaited.
+var iterNextAsync _iterNextAsync
+
+func (_iterNextAsync) exec(vm *vm) {
+	l := len(vm.iterStack) - 1
+	iter := vm.iterStack[l].iter
+	var res Value
+	ex := vm.try(func() {
+		if iter.next == nil {
+			panic(vm.r.NewTypeError("iterator.next is missing or not a function"))
+		}
+		res = iter.next(FunctionCall{This: iter.iterator})
+	})
+	if ex != nil {
+		vm.iterStack[l] = iterStackItem{}
+		vm.iterStack = vm.iterStack[:l]
+		vm.throw(ex.val)
+		return
+	}
+	vm.iterStack[l].nextPending = true
+	vm.push(res)
+	vm.pc++
+}
+
+// iterAsyncResult processes the awaited result of next(). If the iteration is complete it jumps,
+// otherwise the value is stored for enumGet.
+type iterAsyncResult int32
+
+func (jmp iterAsyncResult) exec(vm *vm) {
+	l := len(vm.iterStack) - 1
+	vm.iterStack[l].nextPending = false
+	res := vm.pop()
+	var value Value
+	var done bool
+	ex := vm.try(func() {
+		obj, ok := res.(*Object)
+		if !ok {
+			panic(vm.r.NewTypeError("Iterator result %s is not an object", res))
+		}
+		done = iteratorComplete(obj)
+		if !done {
+			value = iteratorValue(obj)
+		}
+	})
+	if ex != nil {
+		vm.iterStack[l] = iterStackItem{}
+		vm.iterStack = vm.iterStack[:l]
+		vm.throw(ex.val)
+		return
+	}
+	if done {
+		vm.iterStack[l].iter.close()
+		vm.pc += int(jmp)
+	} else {
+		vm.iterStack[l].val = value
+		vm.pc++
+	}
+}
+
+// iterAsyncClose pops the async iterator and calls its return() method, pushing the result so that it can
+// be awaited. If there is no return() method it jumps over the await.
+type iterAsyncClose int32
+
+func (jmp iterAsyncClose) exec(vm *vm) {
+	l := len(vm.iterStack) - 1
+	iter := vm.iterStack[l].iter
+	vm.iterStack[l] = iterStackItem{}
+	vm.iterStack = vm.iterStack[:l]
+	if iter == nil || iter.iterator == nil {
+		vm.pc += int(jmp)
+		return
+	}
+	retMethod := toMethod(iter.iterator.self.getStr("return", nil))
+	if retMethod == nil {
+		iter.close()
+		vm.pc += int(jmp)
+		return
+	}
+	res := retMethod(FunctionCall{This: iter.iterator})
+	iter.close()
+	vm.push(res)
+	vm.pc++
+}
+
+type _iterAsyncCloseResult struct{}
+
+// iterAsyncCloseResult checks and pops the awaited result of the async iterator's return() method.
+var iterAsyncCloseResult _iterAsyncCloseResult
+
+func (_iterAsyncCloseResult) exec(vm *vm) {
+	if _, ok := vm.pop().(*Object); !ok {
+		panic(vm.r.NewTypeError("Iterator result is not an object"))
+	}
+	vm.pc++
+}
+
 type iterGetNextOrUndef struct{}
 
 func (iterGetNextOrUndef) exec(vm *vm) {